@@ -3,12 +3,20 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/netip"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	grpcserver "github.com/elct9620/ccmon/handler/grpc"
+	"github.com/elct9620/ccmon/handler/grpc/query"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -19,18 +27,85 @@ type Config struct {
 	Server   Server   `mapstructure:"server"`
 	Monitor  Monitor  `mapstructure:"monitor"`
 	Claude   Claude   `mapstructure:"claude"`
+	Startup  Startup  `mapstructure:"startup"`
+
+	configFile string // path of the loaded config file, or the default location when none was found
+}
+
+// Startup configuration
+type Startup struct {
+	Strict bool `mapstructure:"strict"` // refuse to start when the timezone or plan would silently degrade
 }
 
 // Database configuration
 type Database struct {
-	Path string `mapstructure:"path"`
+	Path        string `mapstructure:"path"`
+	WritePolicy string `mapstructure:"write_policy"` // enum: safe, fast
 }
 
 // Server configuration
 type Server struct {
-	Address   string      `mapstructure:"address"`
-	Retention string      `mapstructure:"retention"`
-	Cache     ServerCache `mapstructure:"cache"`
+	Address         string      `mapstructure:"address"`
+	Retention       string      `mapstructure:"retention"`
+	MaxRecords      int         `mapstructure:"max_records"`      // oldest requests are evicted beyond this count, 0 = unlimited
+	ReadOnly        bool        `mapstructure:"read_only"`        // query-only mode: no OTLP receiver, database opened read-only
+	AuthToken       string      `mapstructure:"auth_token"`       // required in "authorization" metadata when set
+	AllowedNetworks []string    `mapstructure:"allowed_networks"` // CIDRs of peers allowed to connect, empty allows every peer
+	IgnoreModels    []string    `mapstructure:"ignore_models"`    // model name globs excluded from stats and request lists
+	RateLimit       RateLimit   `mapstructure:"rate_limit"`
+	Cache           ServerCache `mapstructure:"cache"`
+	StatsD          StatsD      `mapstructure:"statsd"`
+	AutoExport      AutoExport  `mapstructure:"auto_export"`
+	Metrics         Metrics     `mapstructure:"metrics"`   // usage from OTLP metric data points
+	Listeners       []Listener  `mapstructure:"listeners"` // extra OTLP receivers, each with its own token and sender label
+
+	FutureTimestamp    string `mapstructure:"future_timestamp"`     // enum: clamp, reject
+	ClockSkewTolerance string `mapstructure:"clock_skew_tolerance"` // how far ahead of now a record may be stamped
+	DedupWindow        string `mapstructure:"dedup_window"`         // same session, model and usage this close are stored once
+
+	MaxQueryPeriod string `mapstructure:"max_query_period"` // longest explicit range a request list query may cover
+	StreamInterval string `mapstructure:"stream_interval"`  // how often streamed stats are pushed without new data
+	MaxBulkAppend  int    `mapstructure:"max_bulk_append"`  // most records a BulkAppend call may carry, 0 = query.DefaultMaxBulkAppend
+}
+
+// Listener configuration for an extra OTLP receiver address
+type Listener struct {
+	Address   string `mapstructure:"address"`    // host:port, OTLP only, the query service stays on server.address
+	AuthToken string `mapstructure:"auth_token"` // required in "authorization" metadata
+	Label     string `mapstructure:"label"`      // label stamped on received requests, defaults to user
+	Value     string `mapstructure:"value"`      // label value, e.g. the sender's name
+}
+
+// RateLimit configuration for query calls, per client host
+type RateLimit struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"` // 0 disables rate limiting
+	Burst             int     `mapstructure:"burst"`
+}
+
+// StatsD configuration for pushing usage gauges to a StatsD or DogStatsD server
+type StatsD struct {
+	Address  string            `mapstructure:"address"`  // host:port, empty disables the emitter
+	Interval string            `mapstructure:"interval"` // how often the gauges are sent
+	Prefix   string            `mapstructure:"prefix"`   // prepended to every metric name
+	Tags     []string          `mapstructure:"tags"`     // DogStatsD tags such as "env:prod"
+	Names    map[string]string `mapstructure:"names"`    // metric name overrides by gauge key, e.g. daily_cost
+}
+
+// AutoExport configuration for archiving each completed day or month to a directory
+type AutoExport struct {
+	Directory string `mapstructure:"directory"` // empty disables the auto export
+	Interval  string `mapstructure:"interval"`  // enum: day, month
+	Format    string `mapstructure:"format"`    // enum: csv, jsonl
+}
+
+// Metrics configuration for ingesting usage from OTLP metric data points
+type Metrics struct {
+	Enabled          bool   `mapstructure:"enabled"`           // off by default, Claude Code also sends the usage as logs
+	TokenMetric      string `mapstructure:"token_metric"`      // sum of tokens split by type_attribute
+	CostMetric       string `mapstructure:"cost_metric"`       // sum of cost in USD
+	TypeAttribute    string `mapstructure:"type_attribute"`    // token type: input, output, cacheRead, cacheCreation
+	ModelAttribute   string `mapstructure:"model_attribute"`   // model name
+	SessionAttribute string `mapstructure:"session_attribute"` // session ID
 }
 
 // ServerCache configuration
@@ -40,21 +115,136 @@ type ServerCache struct {
 
 // CacheStats configuration
 type CacheStats struct {
-	Enabled bool   `mapstructure:"enabled"`
-	TTL     string `mapstructure:"ttl"`
+	Enabled    bool   `mapstructure:"enabled"`
+	TTL        string `mapstructure:"ttl"`
+	MaxEntries int    `mapstructure:"max_entries"` // LRU bound, 0 means unlimited
 }
 
 // Monitor configuration
 type Monitor struct {
-	Server          string `mapstructure:"server"`
-	Timezone        string `mapstructure:"timezone"`
-	RefreshInterval string `mapstructure:"refresh_interval"`
+	Server                 string   `mapstructure:"server"`
+	AuthToken              string   `mapstructure:"auth_token"`  // sent as "authorization" metadata to the server
+	AuthScheme             string   `mapstructure:"auth_scheme"` // enum: raw, bearer, basic (how auth_token is sent)
+	Timezone               string   `mapstructure:"timezone"`
+	RefreshInterval        string   `mapstructure:"refresh_interval"`
+	MinRefreshInterval     string   `mapstructure:"min_refresh_interval"`     // refresh_interval and runtime changes are raised to this, never below 1s
+	IncludeUnknownSessions bool     `mapstructure:"include_unknown_sessions"` // count empty session IDs as one "unknown" session
+	MonthlyIncludeToday    bool     `mapstructure:"monthly_include_today"`    // count today in the monthly period, false covers completed days only
+	DurationFormat         string   `mapstructure:"duration_format"`          // enum: default, compact, clock
+	MinCost                float64  `mapstructure:"min_cost"`                 // hide cheaper requests in lists and exports; 0 shows all
+	StatsColumns           []string `mapstructure:"stats_columns"`            // stats table column order, any of: reqs, limited, cache, total, cost, burn_rate
+	StatsAlign             string   `mapstructure:"stats_align"`              // enum: left, right (numeric stats columns)
+	HideZeroRows           bool     `mapstructure:"hide_zero_rows"`           // hide stats table tier rows without requests in the period
+	NegligibleCost         float64  `mapstructure:"negligible_cost"`          // count cheaper requests in a separate negligible bucket; 0 disables it
+	GroupNegligible        bool     `mapstructure:"group_negligible"`         // move negligible requests into one group listed last in --group-by
+	DailyOrder             string   `mapstructure:"daily_order"`              // enum: newest, oldest (day order of the daily tab)
+	CostColumn             string   `mapstructure:"cost_column"`              // enum: request, cumulative (cost column of the requests table)
+	CostShare              bool     `mapstructure:"cost_share"`               // add a column with each request's share of the period cost
+	SparklineInterval      string   `mapstructure:"sparkline_interval"`       // header cost trend bucket size (e.g. 1h, 30m)
+	SparklineBuckets       int      `mapstructure:"sparkline_buckets"`        // header cost trend bucket count, 0 hides it
+	FormatError            string   `mapstructure:"format_error"`             // printed by --format when a query fails, may be empty
+	FormatErrorExitCode    int      `mapstructure:"format_error_exit_code"`   // --format exit code on failure, 0 keeps status bars quiet
+	Locale                 string   `mapstructure:"locale"`                   // decimal and grouping separators of costs, e.g. en-US, de-DE
+	NumberGrouping         bool     `mapstructure:"number_grouping"`          // group thousands of costs with the locale separator
+	TokenDecimals          int      `mapstructure:"token_decimals"`           // decimals of abbreviated token counts: 0, 1 or -1 for the defaults
+	PercentDecimals        int      `mapstructure:"percent_decimals"`         // decimals of plan usage percentages: 0 or 1
+	ExportCostDecimals     int      `mapstructure:"export_cost_decimals"`     // decimals --export rounds costs to: 0 to 15
+	Schedule               string   `mapstructure:"schedule"`                 // recurring window of @schedule_cost and --summary (e.g. "mon-fri 09:00-17:00"), empty disables it
+	ShowPreviousBlock      bool     `mapstructure:"show_previous_block"`      // show the previous block's final usage under the block progress
+	ListWindow             string   `mapstructure:"list_window"`              // only list requests this recent (e.g. 2h), empty lists the whole period
+	RollingDays            int      `mapstructure:"rolling_days"`             // days of the "r" rolling window filter up to now, 0 disables it
+	ProjectLabel           string   `mapstructure:"project_label"`            // label naming the project of a request (e.g. project), empty disables the "p" filter
+	StaleAfter             string   `mapstructure:"stale_after"`              // warn in the footer when the latest request is older (e.g. 1h), 0 disables it
+	AutoBlock              bool     `mapstructure:"auto_block"`               // without --block, anchor the block at the hour of today's first request
+	BlockGracePeriod       string   `mapstructure:"block_grace_period"`       // keep an ended block's progress, dimmed, this long before rolling over (e.g. 5m)
+	StreamStats            bool     `mapstructure:"stream_stats"`             // subscribe to the period stats pushed by the server instead of querying them on every refresh
+	DefaultCommand         string   `mapstructure:"default_command"`          // enum: tui, summary, format (what bare ccmon runs)
+	DefaultFormat          string   `mapstructure:"default_format"`           // format string printed when default_command is format
+	BudgetSignalFile       string   `mapstructure:"budget_signal_file"`       // written while monthly plan usage is >= 100%, empty disables it
+
+	Notifications Notifications     `mapstructure:"notifications"` // desktop alerts when daily spend crosses a threshold
+	BudgetBanner  BudgetBanner      `mapstructure:"budget_banner"` // warning banner when plan usage crosses a threshold
+	StatsCache    MonitorStatsCache `mapstructure:"stats_cache"`   // where the monitor and --format cache server stats
+}
+
+// MonitorStatsCache configuration of the client-side stats cache
+type MonitorStatsCache struct {
+	Backend string `mapstructure:"backend"` // enum: memory (per process), file (shared by ccmon processes on this host)
+	TTL     string `mapstructure:"ttl"`     // how long file entries are reused by other processes
+	Path    string `mapstructure:"path"`    // directory of the file backend
+}
+
+// Notifications configuration for desktop alerts on daily spend
+type Notifications struct {
+	Enabled        bool      `mapstructure:"enabled"`
+	DailyCost      []float64 `mapstructure:"daily_cost"`       // USD thresholds of @daily_cost
+	DailyPlanUsage []int     `mapstructure:"daily_plan_usage"` // percent thresholds of @daily_plan_usage
+	QuietHours     []string  `mapstructure:"quiet_hours"`      // "HH:MM-HH:MM" windows in monitor.timezone when alerts wait
+}
+
+// BudgetBanner configuration for the monitor's plan usage warning banner
+type BudgetBanner struct {
+	Thresholds  []int  `mapstructure:"thresholds"`   // percent thresholds of @daily_plan_usage and @monthly_plan_usage, empty (default) disables the banner
+	AutoDismiss string `mapstructure:"auto_dismiss"` // hide the banner after it was shown this long, 0 keeps it until dismissed
 }
 
 // Claude configuration
 type Claude struct {
-	Plan      string `mapstructure:"plan"`       // enum: unset, pro, max, max20
-	MaxTokens int    `mapstructure:"max_tokens"` // override default token limits
+	Plan             string              `mapstructure:"plan"`               // enum: unset, pro, max, max20
+	PlanPrice        float64             `mapstructure:"plan_price"`         // monthly price overriding plans.json, 0 keeps the plan's price
+	MaxTokens        TokenCount          `mapstructure:"max_tokens"`         // override default token limits, e.g. 44000 or "44k"
+	BaseTokens       TokenCount          `mapstructure:"base_tokens"`        // block limit of base tier tokens, 0 shows base usage without a bar
+	SoftLimit        int                 `mapstructure:"soft_limit"`         // percentage of the token limit marked on the block progress bar, 0 disables
+	BlockTokenMetric string              `mapstructure:"block_token_metric"` // enum: limited, total (premium tokens counted against the block limit)
+	Rates            []ModelRate         `mapstructure:"rates"`              // per-model token rates, first match wins
+	ModelTiers       []ModelTierOverride `mapstructure:"model_tiers"`        // base/premium/opus overrides, replaced by model_tiers.toml once edited in the monitor
+	ModelNames       []ModelName         `mapstructure:"model_names"`        // short display names, first match wins
+}
+
+// TokenCount is a token limit written as an integer or a string with a k or M suffix, e.g. "44k" or "1.2M"
+type TokenCount int
+
+// configDecodeHook keeps viper's default hooks and accepts human-friendly token counts
+func configDecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		tokenCountHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
+
+// tokenCountHook decodes string token counts with entity.ParseTokenCount
+func tokenCountHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(TokenCount(0)) || from.Kind() != reflect.String {
+		return data, nil
+	}
+
+	count, err := entity.ParseTokenCount(data.(string))
+	if err != nil {
+		return nil, err
+	}
+	return TokenCount(count), nil
+}
+
+// ModelTierOverride configuration assigning a model to a usage tier
+type ModelTierOverride struct {
+	Model string `mapstructure:"model"` // exact model name, case-insensitive
+	Tier  string `mapstructure:"tier"`  // enum: base, premium, opus
+}
+
+// ModelName configuration of a short name shown instead of the raw model name
+type ModelName struct {
+	Model string `mapstructure:"model"` // case-insensitive glob, e.g. "claude-3-5-sonnet-*"
+	Name  string `mapstructure:"name"`  // shown in the request list, --group-by model and exports
+}
+
+// ModelRate configuration in USD per million tokens
+type ModelRate struct {
+	Model         string  `mapstructure:"model"` // case-insensitive glob, e.g. "claude-*sonnet*"
+	Input         float64 `mapstructure:"input"`
+	Output        float64 `mapstructure:"output"` // only used to reprice with --rates
+	CacheRead     float64 `mapstructure:"cache_read"`
+	CacheCreation float64 `mapstructure:"cache_creation"` // only used to reprice with --rates
 }
 
 // LoadConfig loads configuration from files and command-line flags
@@ -63,15 +253,93 @@ func LoadConfig() (*Config, error) {
 
 	// Set default values
 	v.SetDefault("database.path", "~/.ccmon/ccmon.db")
+	v.SetDefault("database.write_policy", "safe")
+	v.SetDefault("startup.strict", false)
 	v.SetDefault("server.address", "127.0.0.1:4317")
 	v.SetDefault("server.retention", "never")
+	v.SetDefault("server.max_records", 0)
+	v.SetDefault("server.read_only", false)
+	v.SetDefault("server.auth_token", "")
+	v.SetDefault("server.allowed_networks", []string{})
+	v.SetDefault("server.ignore_models", []string{})
+	v.SetDefault("server.rate_limit.requests_per_second", 0.0)
+	v.SetDefault("server.rate_limit.burst", 20)
+	v.SetDefault("server.metrics.enabled", false)
+	v.SetDefault("server.metrics.token_metric", "claude_code.token.usage")
+	v.SetDefault("server.metrics.cost_metric", "claude_code.cost.usage")
+	v.SetDefault("server.metrics.type_attribute", "type")
+	v.SetDefault("server.metrics.model_attribute", "model")
+	v.SetDefault("server.metrics.session_attribute", "session.id")
+	v.SetDefault("server.statsd.address", "")
+	v.SetDefault("server.statsd.interval", "10s")
+	v.SetDefault("server.statsd.prefix", "ccmon.")
+	v.SetDefault("server.statsd.tags", []string{})
+	v.SetDefault("server.auto_export.directory", "")
+	v.SetDefault("server.auto_export.interval", "day")
+	v.SetDefault("server.auto_export.format", "csv")
+	v.SetDefault("server.future_timestamp", "clamp")
+	v.SetDefault("server.clock_skew_tolerance", "5m")
+	v.SetDefault("server.dedup_window", "1s")
+	v.SetDefault("server.max_query_period", "365d")
+	v.SetDefault("server.stream_interval", "10s")
+	v.SetDefault("server.max_bulk_append", query.DefaultMaxBulkAppend)
 	v.SetDefault("server.cache.stats.enabled", true)
 	v.SetDefault("server.cache.stats.ttl", "1m")
+	v.SetDefault("server.cache.stats.max_entries", 1000)
 	v.SetDefault("monitor.server", "127.0.0.1:4317")
+	v.SetDefault("monitor.auth_token", "")
+	v.SetDefault("monitor.auth_scheme", "raw")
 	v.SetDefault("monitor.timezone", "UTC")
 	v.SetDefault("monitor.refresh_interval", "5s")
+	v.SetDefault("monitor.min_refresh_interval", "1s")
+	v.SetDefault("monitor.include_unknown_sessions", true)
+	v.SetDefault("monitor.monthly_include_today", true)
+	v.SetDefault("monitor.duration_format", "default")
+	v.SetDefault("monitor.min_cost", 0.0)
+	v.SetDefault("monitor.stats_columns", []string{"reqs", "limited", "cache", "total", "cost", "burn_rate"})
+	v.SetDefault("monitor.stats_align", "left")
+	v.SetDefault("monitor.hide_zero_rows", false)
+	v.SetDefault("monitor.negligible_cost", 0.0)
+	v.SetDefault("monitor.group_negligible", false)
+	v.SetDefault("monitor.daily_order", "newest")
+	v.SetDefault("monitor.cost_column", "request")
+	v.SetDefault("monitor.cost_share", false)
+	v.SetDefault("monitor.sparkline_interval", "1h")
+	v.SetDefault("monitor.sparkline_buckets", 12)
+	v.SetDefault("monitor.format_error", "❌ ERROR")
+	v.SetDefault("monitor.format_error_exit_code", 1)
+	v.SetDefault("monitor.locale", "en-US")
+	v.SetDefault("monitor.number_grouping", false)
+	v.SetDefault("monitor.token_decimals", -1)
+	v.SetDefault("monitor.percent_decimals", 0)
+	v.SetDefault("monitor.export_cost_decimals", 6)
+	v.SetDefault("monitor.schedule", "")
+	v.SetDefault("monitor.show_previous_block", false)
+	v.SetDefault("monitor.auto_block", false)
+	v.SetDefault("monitor.block_grace_period", "0s")
+	v.SetDefault("monitor.stream_stats", false)
+	v.SetDefault("monitor.default_command", "tui")
+	v.SetDefault("monitor.default_format", "")
+	v.SetDefault("monitor.list_window", "")
+	v.SetDefault("monitor.rolling_days", 0)
+	v.SetDefault("monitor.project_label", "")
+	v.SetDefault("monitor.stale_after", "1h")
+	v.SetDefault("monitor.budget_signal_file", "")
+	v.SetDefault("monitor.stats_cache.backend", "memory")
+	v.SetDefault("monitor.stats_cache.ttl", "10s")
+	v.SetDefault("monitor.stats_cache.path", "~/.ccmon/cache/stats")
+	v.SetDefault("monitor.notifications.enabled", false)
+	v.SetDefault("monitor.notifications.daily_cost", []float64{})
+	v.SetDefault("monitor.notifications.daily_plan_usage", []int{80, 100})
+	v.SetDefault("monitor.notifications.quiet_hours", []string{})
+	v.SetDefault("monitor.budget_banner.thresholds", []int{})
+	v.SetDefault("monitor.budget_banner.auto_dismiss", "0s")
 	v.SetDefault("claude.plan", "unset")
-	v.SetDefault("claude.max_tokens", 0) // 0 means use plan defaults
+	v.SetDefault("claude.plan_price", 0.0)
+	v.SetDefault("claude.max_tokens", 0)  // 0 means use plan defaults
+	v.SetDefault("claude.soft_limit", 0)  // 0 disables the soft limit marker
+	v.SetDefault("claude.base_tokens", 0) // 0 shows base usage without a bar
+	v.SetDefault("claude.block_token_metric", "limited")
 
 	// Define command-line flags using pflag (if not already defined)
 	if pflag.Lookup("database-path") == nil {
@@ -83,17 +351,41 @@ func LoadConfig() (*Config, error) {
 	if pflag.Lookup("server-retention") == nil {
 		pflag.String("server-retention", "", "Data retention period (e.g., '7d', '30d', 'never')")
 	}
+	if pflag.Lookup("server-read-only") == nil {
+		pflag.Bool("server-read-only", false, "Run server in query-only mode with a read-only database (OTLP receiver disabled)")
+	}
+	if pflag.Lookup("server-auth-token") == nil {
+		pflag.String("server-auth-token", "", "Token clients must send in the authorization metadata (required for DeleteByPeriod)")
+	}
 	if pflag.Lookup("monitor-server") == nil {
 		pflag.String("monitor-server", "", "gRPC server address for query service")
 	}
+	if pflag.Lookup("monitor-auth-token") == nil {
+		pflag.String("monitor-auth-token", "", "Auth token sent to the query service")
+	}
+	if pflag.Lookup("min-cost") == nil {
+		pflag.Float64("min-cost", 0, "Hide requests cheaper than this cost (USD) in the request list and exports")
+	}
+	if pflag.Lookup("schedule") == nil {
+		pflag.String("schedule", "", "Recurring window of @schedule_cost and --summary in monitor.timezone, e.g. 'mon-fri 09:00-17:00'")
+	}
 	if pflag.Lookup("monitor-timezone") == nil {
 		pflag.String("monitor-timezone", "", "Timezone for time filtering and display")
 	}
+	if pflag.Lookup("monitor-include-unknown-sessions") == nil {
+		pflag.Bool("monitor-include-unknown-sessions", true, "Count requests without a session ID as a single unknown session")
+	}
+	if pflag.Lookup("monitor-monthly-include-today") == nil {
+		pflag.Bool("monitor-monthly-include-today", true, "Include today in the monthly period of @monthly_cost and friends; false covers completed days only")
+	}
+	if pflag.Lookup("strict-startup") == nil {
+		pflag.Bool("strict-startup", false, "Refuse to start when monitor.timezone or claude.plan would silently fall back")
+	}
 	if pflag.Lookup("claude-plan") == nil {
 		pflag.String("claude-plan", "", "Claude subscription plan (unset, pro, max, max20)")
 	}
 	if pflag.Lookup("claude-max-tokens") == nil {
-		pflag.Int("claude-max-tokens", 0, "Custom token limit override (0 means use plan defaults)")
+		pflag.String("claude-max-tokens", "0", "Custom token limit override, e.g. 44000, 44k or 1.2M (0 means use plan defaults)")
 	}
 	if pflag.Lookup("server-cache-stats-enabled") == nil {
 		pflag.Bool("server-cache-stats-enabled", true, "Enable stats cache")
@@ -101,6 +393,9 @@ func LoadConfig() (*Config, error) {
 	if pflag.Lookup("server-cache-stats-ttl") == nil {
 		pflag.String("server-cache-stats-ttl", "1m", "Stats cache TTL")
 	}
+	if pflag.Lookup("server-cache-stats-max-entries") == nil {
+		pflag.Int("server-cache-stats-max-entries", 1000, "Maximum cached stats periods before LRU eviction (0 means unlimited)")
+	}
 
 	// Parse flags if not already parsed
 	if !pflag.Parsed() {
@@ -117,12 +412,36 @@ func LoadConfig() (*Config, error) {
 	if err := v.BindPFlag("server.retention", pflag.Lookup("server-retention")); err != nil {
 		log.Printf("Warning: failed to bind server-retention flag: %v", err)
 	}
+	if err := v.BindPFlag("server.read_only", pflag.Lookup("server-read-only")); err != nil {
+		log.Printf("Warning: failed to bind server-read-only flag: %v", err)
+	}
+	if err := v.BindPFlag("server.auth_token", pflag.Lookup("server-auth-token")); err != nil {
+		log.Printf("Warning: failed to bind server-auth-token flag: %v", err)
+	}
 	if err := v.BindPFlag("monitor.server", pflag.Lookup("monitor-server")); err != nil {
 		log.Printf("Warning: failed to bind monitor-server flag: %v", err)
 	}
+	if err := v.BindPFlag("monitor.auth_token", pflag.Lookup("monitor-auth-token")); err != nil {
+		log.Printf("Warning: failed to bind monitor-auth-token flag: %v", err)
+	}
+	if err := v.BindPFlag("monitor.min_cost", pflag.Lookup("min-cost")); err != nil {
+		log.Printf("Warning: failed to bind min-cost flag: %v", err)
+	}
+	if err := v.BindPFlag("monitor.schedule", pflag.Lookup("schedule")); err != nil {
+		log.Printf("Warning: failed to bind schedule flag: %v", err)
+	}
 	if err := v.BindPFlag("monitor.timezone", pflag.Lookup("monitor-timezone")); err != nil {
 		log.Printf("Warning: failed to bind monitor-timezone flag: %v", err)
 	}
+	if err := v.BindPFlag("monitor.include_unknown_sessions", pflag.Lookup("monitor-include-unknown-sessions")); err != nil {
+		log.Printf("Warning: failed to bind monitor-include-unknown-sessions flag: %v", err)
+	}
+	if err := v.BindPFlag("monitor.monthly_include_today", pflag.Lookup("monitor-monthly-include-today")); err != nil {
+		log.Printf("Warning: failed to bind monitor-monthly-include-today flag: %v", err)
+	}
+	if err := v.BindPFlag("startup.strict", pflag.Lookup("strict-startup")); err != nil {
+		log.Printf("Warning: failed to bind strict-startup flag: %v", err)
+	}
 	if err := v.BindPFlag("claude.plan", pflag.Lookup("claude-plan")); err != nil {
 		log.Printf("Warning: failed to bind claude-plan flag: %v", err)
 	}
@@ -135,6 +454,13 @@ func LoadConfig() (*Config, error) {
 	if err := v.BindPFlag("server.cache.stats.ttl", pflag.Lookup("server-cache-stats-ttl")); err != nil {
 		log.Printf("Warning: failed to bind server-cache-stats-ttl flag: %v", err)
 	}
+	if err := v.BindPFlag("server.cache.stats.max_entries", pflag.Lookup("server-cache-stats-max-entries")); err != nil {
+		log.Printf("Warning: failed to bind server-cache-stats-max-entries flag: %v", err)
+	}
+
+	// Environment variables override the config file, flags override both
+	bindEnv(v)
+	bindStrictStartupEnv(v)
 
 	// Set config name (without extension)
 	v.SetConfigName("config")
@@ -155,21 +481,98 @@ func LoadConfig() (*Config, error) {
 
 	// Unmarshal config
 	var config Config
-	if err := v.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config, viper.DecodeHook(configDecodeHook())); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
-	// Expand home directory in database path
+	config.configFile = v.ConfigFileUsed()
+	if config.configFile == "" {
+		config.configFile = expandPath("~/.ccmon/config.toml")
+	}
+
+	// Expand home directory in file paths
 	config.Database.Path = expandPath(config.Database.Path)
+	config.Monitor.BudgetSignalFile = expandPath(config.Monitor.BudgetSignalFile)
+	config.Monitor.StatsCache.Path = expandPath(config.Monitor.StatsCache.Path)
 
-	// Validate configuration
+	// Validate configuration; the loaded values are still returned so diagnostics
+	// such as --timezones can explain what is wrong
 	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+		return &config, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	return &config, nil
 }
 
+// envBindings maps config keys to the environment variables overriding them, e.g. in containers
+var envBindings = map[string]string{
+	"claude.plan":       "CCMON_PLAN",
+	"claude.plan_price": "CCMON_PLAN_PRICE",
+	"monitor.timezone":  "CCMON_TIMEZONE",
+}
+
+// bindEnv binds the environment overrides, they are validated with the rest of the configuration
+func bindEnv(v *viper.Viper) {
+	for key, env := range envBindings {
+		if err := v.BindEnv(key, env); err != nil {
+			log.Printf("Warning: failed to bind %s environment variable: %v", env, err)
+		}
+	}
+}
+
+// bindStrictStartupEnv lets CCMON_STRICT_STARTUP enable strict startup for a single run
+func bindStrictStartupEnv(v *viper.Viper) {
+	if err := v.BindEnv("startup.strict", "CCMON_STRICT_STARTUP"); err != nil {
+		log.Printf("Warning: failed to bind CCMON_STRICT_STARTUP environment variable: %v", err)
+	}
+}
+
+// ConfigFile returns the path of the loaded config file, or the default location when none was found
+func (c *Config) ConfigFile() string {
+	return c.configFile
+}
+
+// ModelTiersFile returns the path the model tier overrides edited in the monitor are saved to,
+// next to the config file so the config itself is never rewritten
+func (c *Config) ModelTiersFile() string {
+	return filepath.Join(filepath.Dir(c.configFile), "model_tiers.toml")
+}
+
+// LoadRatesFile reads the [[rates]] table of a TOML file used to reprice requests with --rates
+func LoadRatesFile(path string) ([]ModelRate, error) {
+	v := viper.New()
+	v.SetConfigFile(expandPath(path))
+	v.SetConfigType("toml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read rates file: %w", err)
+	}
+
+	var rates []ModelRate
+	if err := v.UnmarshalKey("rates", &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file: %w", err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("rates file %s has no [[rates]] entries", path)
+	}
+	if err := validateModelRates("rates", rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// validateModelRates checks the model rates listed under key
+func validateModelRates(key string, rates []ModelRate) error {
+	for i, rate := range rates {
+		if strings.TrimSpace(rate.Model) == "" {
+			return fmt.Errorf("%s[%d].model must not be empty", key, i)
+		}
+		if rate.Input < 0 || rate.Output < 0 || rate.CacheRead < 0 || rate.CacheCreation < 0 {
+			return fmt.Errorf("%s[%d] rates must be >= 0 (model: %s)", key, i, rate.Model)
+		}
+	}
+	return nil
+}
+
 // expandPath expands ~ to home directory
 func expandPath(path string) string {
 	if strings.HasPrefix(path, "~/") {
@@ -195,7 +598,24 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid claude plan: %s (must be one of: unset, pro, max, max20)", c.Claude.Plan)
 	}
 
-	// Validate timezone
+	// Validate plan price override
+	if c.Claude.PlanPrice < 0 {
+		return fmt.Errorf("claude.plan_price must be >= 0, got: %g", c.Claude.PlanPrice)
+	}
+	if c.Claude.PlanPrice > 0 && (c.Claude.Plan == "" || c.Claude.Plan == "unset") {
+		return fmt.Errorf("claude.plan_price requires claude.plan to be one of: pro, max, max20")
+	}
+
+	// Validate timezone; strict startup also rejects the fallbacks, an empty timezone is read as UTC
+	// and "Local" follows the host, which is often UTC in containers
+	if c.Startup.Strict {
+		switch c.Monitor.Timezone {
+		case "":
+			return fmt.Errorf("monitor.timezone is empty and would fall back to UTC; set an IANA name such as \"America/New_York\"")
+		case "Local":
+			return fmt.Errorf("monitor.timezone \"Local\" depends on the host; set an IANA name such as \"America/New_York\"")
+		}
+	}
 	if c.Monitor.Timezone != "" {
 		_, err := time.LoadLocation(c.Monitor.Timezone)
 		if err != nil {
@@ -203,16 +623,366 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate how the auth token is sent
+	if _, err := entity.ParseAuthScheme(c.Monitor.AuthScheme); err != nil {
+		return fmt.Errorf("invalid monitor.auth_scheme: %s (must be one of: raw, bearer, basic)", c.Monitor.AuthScheme)
+	}
+
+	// Validate duration format
+	if _, err := entity.ParseDurationStyle(c.Monitor.DurationFormat); err != nil {
+		return fmt.Errorf("invalid monitor.duration_format: %s (must be one of: default, compact, clock)", c.Monitor.DurationFormat)
+	}
+
+	// Validate stats table layout
+	validStatsColumns := map[string]bool{
+		"reqs":      true,
+		"limited":   true,
+		"cache":     true,
+		"total":     true,
+		"cost":      true,
+		"burn_rate": true,
+	}
+	seenStatsColumns := make(map[string]bool)
+	for _, column := range c.Monitor.StatsColumns {
+		if !validStatsColumns[column] {
+			return fmt.Errorf("invalid monitor.stats_columns entry: %s (must be one of: reqs, limited, cache, total, cost, burn_rate)", column)
+		}
+		if seenStatsColumns[column] {
+			return fmt.Errorf("duplicate monitor.stats_columns entry: %s", column)
+		}
+		seenStatsColumns[column] = true
+	}
+
+	if c.Monitor.StatsAlign != "" && c.Monitor.StatsAlign != "left" && c.Monitor.StatsAlign != "right" {
+		return fmt.Errorf("invalid monitor.stats_align: %s (must be one of: left, right)", c.Monitor.StatsAlign)
+	}
+
+	if c.Monitor.DailyOrder != "" && c.Monitor.DailyOrder != "newest" && c.Monitor.DailyOrder != "oldest" {
+		return fmt.Errorf("invalid monitor.daily_order: %s (must be one of: newest, oldest)", c.Monitor.DailyOrder)
+	}
+
+	if c.Monitor.CostColumn != "" && c.Monitor.CostColumn != "request" && c.Monitor.CostColumn != "cumulative" {
+		return fmt.Errorf("invalid monitor.cost_column: %s (must be one of: request, cumulative)", c.Monitor.CostColumn)
+	}
+
+	// Validate the command bare ccmon runs
+	switch c.Monitor.DefaultCommand {
+	case "", "tui", "summary":
+	case "format":
+		if c.Monitor.DefaultFormat == "" {
+			return fmt.Errorf("monitor.default_format is required when monitor.default_command is format")
+		}
+	default:
+		return fmt.Errorf("invalid monitor.default_command: %s (must be one of: tui, summary, format)", c.Monitor.DefaultCommand)
+	}
+
+	// Validate header sparkline
+	if c.Monitor.SparklineInterval != "" {
+		interval, err := time.ParseDuration(c.Monitor.SparklineInterval)
+		if err != nil {
+			return fmt.Errorf("invalid monitor.sparkline_interval: %s (%w)", c.Monitor.SparklineInterval, err)
+		}
+		if interval < time.Minute {
+			return fmt.Errorf("monitor.sparkline_interval must be at least 1m, got: %s", c.Monitor.SparklineInterval)
+		}
+	}
+	if c.Monitor.SparklineBuckets < 0 || c.Monitor.SparklineBuckets > 60 {
+		return fmt.Errorf("monitor.sparkline_buckets must be between 0 and 60, got: %d", c.Monitor.SparklineBuckets)
+	}
+
+	// Validate minimum refresh interval, values below the 1s floor are raised by the monitor
+	if c.Monitor.MinRefreshInterval != "" {
+		minimum, err := time.ParseDuration(c.Monitor.MinRefreshInterval)
+		if err != nil {
+			return fmt.Errorf("invalid monitor.min_refresh_interval: %s (%w)", c.Monitor.MinRefreshInterval, err)
+		}
+		if minimum > 5*time.Minute {
+			return fmt.Errorf("monitor.min_refresh_interval must be at most 5m, got: %s", c.Monitor.MinRefreshInterval)
+		}
+	}
+
+	// Validate requests table window
+	if c.Monitor.ListWindow != "" {
+		window, err := time.ParseDuration(c.Monitor.ListWindow)
+		if err != nil {
+			return fmt.Errorf("invalid monitor.list_window: %s (%w)", c.Monitor.ListWindow, err)
+		}
+		if window < time.Minute {
+			return fmt.Errorf("monitor.list_window must be at least 1m, got: %s", c.Monitor.ListWindow)
+		}
+	}
+
+	// Validate rolling window filter
+	if c.Monitor.RollingDays < 0 || c.Monitor.RollingDays > 365 {
+		return fmt.Errorf("monitor.rolling_days must be between 0 and 365, got: %d", c.Monitor.RollingDays)
+	}
+
+	// Validate data freshness threshold
+	if c.Monitor.StaleAfter != "" {
+		staleAfter, err := time.ParseDuration(c.Monitor.StaleAfter)
+		if err != nil {
+			return fmt.Errorf("invalid monitor.stale_after: %s (%w)", c.Monitor.StaleAfter, err)
+		}
+		if staleAfter < 0 {
+			return fmt.Errorf("monitor.stale_after must not be negative, got: %s", c.Monitor.StaleAfter)
+		}
+	}
+
+	// Validate block grace period, it must end before the next block does
+	if c.Monitor.BlockGracePeriod != "" {
+		grace, err := time.ParseDuration(c.Monitor.BlockGracePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid monitor.block_grace_period: %s (%w)", c.Monitor.BlockGracePeriod, err)
+		}
+		if grace < 0 || grace >= entity.TimeBlockDuration {
+			return fmt.Errorf("monitor.block_grace_period must be at least 0s and shorter than the 5h block, got: %s", c.Monitor.BlockGracePeriod)
+		}
+	}
+
+	// Validate project label, it becomes a key=value label filter
+	if strings.ContainsAny(c.Monitor.ProjectLabel, "=,") || c.Monitor.ProjectLabel != strings.TrimSpace(c.Monitor.ProjectLabel) {
+		return fmt.Errorf("invalid monitor.project_label: %q (must be a label key without '=', ',' or surrounding spaces)", c.Monitor.ProjectLabel)
+	}
+
+	// Validate client-side stats cache
+	switch c.Monitor.StatsCache.Backend {
+	case "", "memory":
+	case "file":
+		ttl, err := time.ParseDuration(c.Monitor.StatsCache.TTL)
+		if err != nil {
+			return fmt.Errorf("invalid monitor.stats_cache.ttl: %s (%w)", c.Monitor.StatsCache.TTL, err)
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("monitor.stats_cache.ttl must be positive, got: %s", c.Monitor.StatsCache.TTL)
+		}
+		if c.Monitor.StatsCache.Path == "" {
+			return fmt.Errorf("monitor.stats_cache.path must be set for the file backend")
+		}
+	default:
+		return fmt.Errorf("invalid monitor.stats_cache.backend: %s (must be one of: memory, file)", c.Monitor.StatsCache.Backend)
+	}
+
+	// Validate notification thresholds
+	for i, threshold := range c.Monitor.Notifications.DailyCost {
+		if threshold <= 0 {
+			return fmt.Errorf("monitor.notifications.daily_cost[%d] must be > 0, got: %g", i, threshold)
+		}
+	}
+	for i, threshold := range c.Monitor.Notifications.DailyPlanUsage {
+		if threshold <= 0 {
+			return fmt.Errorf("monitor.notifications.daily_plan_usage[%d] must be > 0, got: %d", i, threshold)
+		}
+	}
+	for i, window := range c.Monitor.Notifications.QuietHours {
+		if _, err := entity.ParseQuietHours(window); err != nil {
+			return fmt.Errorf("invalid monitor.notifications.quiet_hours[%d]: %w", i, err)
+		}
+	}
+
+	// Validate warning banner thresholds and auto dismiss
+	for i, threshold := range c.Monitor.BudgetBanner.Thresholds {
+		if threshold <= 0 {
+			return fmt.Errorf("monitor.budget_banner.thresholds[%d] must be > 0, got: %d", i, threshold)
+		}
+	}
+	if c.Monitor.BudgetBanner.AutoDismiss != "" {
+		autoDismiss, err := time.ParseDuration(c.Monitor.BudgetBanner.AutoDismiss)
+		if err != nil {
+			return fmt.Errorf("invalid monitor.budget_banner.auto_dismiss: %s (%w)", c.Monitor.BudgetBanner.AutoDismiss, err)
+		}
+		if autoDismiss < 0 {
+			return fmt.Errorf("monitor.budget_banner.auto_dismiss must be >= 0, got: %s", c.Monitor.BudgetBanner.AutoDismiss)
+		}
+	}
+
+	// Validate format error exit code, shells reserve codes above 125
+	if c.Monitor.FormatErrorExitCode < 0 || c.Monitor.FormatErrorExitCode > 125 {
+		return fmt.Errorf("monitor.format_error_exit_code must be between 0 and 125, got: %d", c.Monitor.FormatErrorExitCode)
+	}
+
+	// Validate number locale
+	if _, err := entity.ParseNumberLocale(c.Monitor.Locale); err != nil {
+		return fmt.Errorf("invalid monitor.locale: %w", err)
+	}
+
+	// Validate token count precision
+	if c.Monitor.TokenDecimals < -1 || c.Monitor.TokenDecimals > 1 {
+		return fmt.Errorf("invalid monitor.token_decimals: %d (must be 0, 1 or -1 for the defaults)", c.Monitor.TokenDecimals)
+	}
+
+	// Validate plan usage precision
+	if c.Monitor.PercentDecimals < 0 || c.Monitor.PercentDecimals > 1 {
+		return fmt.Errorf("invalid monitor.percent_decimals: %d (must be 0 or 1)", c.Monitor.PercentDecimals)
+	}
+
+	// Validate export cost decimals, float64 holds about 15 significant digits
+	if c.Monitor.ExportCostDecimals < 0 || c.Monitor.ExportCostDecimals > 15 {
+		return fmt.Errorf("invalid monitor.export_cost_decimals: %d (must be between 0 and 15)", c.Monitor.ExportCostDecimals)
+	}
+
+	// Validate schedule if provided
+	if c.Monitor.Schedule != "" {
+		if _, err := entity.ParseSchedule(c.Monitor.Schedule); err != nil {
+			return fmt.Errorf("invalid monitor.schedule: %w", err)
+		}
+	}
+
+	// Validate minimum cost filter
+	if c.Monitor.MinCost < 0 {
+		return fmt.Errorf("monitor.min_cost must be >= 0, got: %g", c.Monitor.MinCost)
+	}
+
+	// Validate negligible cost threshold
+	if c.Monitor.NegligibleCost < 0 {
+		return fmt.Errorf("monitor.negligible_cost must be >= 0, got: %g", c.Monitor.NegligibleCost)
+	}
+
+	// Validate model rates
+	if err := validateModelRates("claude.rates", c.Claude.Rates); err != nil {
+		return err
+	}
+
+	// Validate model tier overrides
+	for i, override := range c.Claude.ModelTiers {
+		if strings.TrimSpace(override.Model) == "" {
+			return fmt.Errorf("claude.model_tiers[%d].model must not be empty", i)
+		}
+		if _, err := entity.ParseModelTier(override.Tier); err != nil {
+			return fmt.Errorf("claude.model_tiers[%d]: %w", i, err)
+		}
+	}
+
+	// Validate model display names
+	for i, name := range c.Claude.ModelNames {
+		if strings.TrimSpace(name.Model) == "" {
+			return fmt.Errorf("claude.model_names[%d].model must not be empty", i)
+		}
+		if strings.TrimSpace(name.Name) == "" {
+			return fmt.Errorf("claude.model_names[%d].name must not be empty (model: %s)", i, name.Model)
+		}
+	}
+
 	// Validate max_tokens
 	if c.Claude.MaxTokens < 0 {
 		return fmt.Errorf("claude.max_tokens must be >= 0, got: %d", c.Claude.MaxTokens)
 	}
 
+	// Validate base_tokens
+	if c.Claude.BaseTokens < 0 {
+		return fmt.Errorf("claude.base_tokens must be >= 0, got: %d", c.Claude.BaseTokens)
+	}
+
+	// Validate block_token_metric
+	if _, err := entity.ParseBlockTokenMetric(c.Claude.BlockTokenMetric); err != nil {
+		return fmt.Errorf("invalid claude.block_token_metric: %s (must be limited to count input + output tokens, or total to count cache tokens too)", c.Claude.BlockTokenMetric)
+	}
+
+	// Validate soft_limit, a percentage below the hard limit
+	if c.Claude.SoftLimit < 0 || c.Claude.SoftLimit >= 100 {
+		return fmt.Errorf("claude.soft_limit must be between 0 and 99, got: %d", c.Claude.SoftLimit)
+	}
+
 	// Validate retention
 	if err := c.Server.ValidateRetention(); err != nil {
 		return fmt.Errorf("invalid server.retention: %w", err)
 	}
 
+	// Retention cleanup needs write access to the database
+	if c.Server.ReadOnly && c.Server.IsRetentionEnabled() {
+		return fmt.Errorf("server.retention cannot be used with server.read_only (got retention: %s)", c.Server.Retention)
+	}
+
+	// Validate the stored request limit, evicting also needs write access
+	if c.Server.MaxRecords < 0 {
+		return fmt.Errorf("server.max_records must not be negative, got: %d", c.Server.MaxRecords)
+	}
+	if c.Server.ReadOnly && c.Server.MaxRecords > 0 {
+		return fmt.Errorf("server.max_records cannot be used with server.read_only (got max_records: %d)", c.Server.MaxRecords)
+	}
+
+	// Validate database write policy
+	if c.Database.WritePolicy != "" && c.Database.WritePolicy != "safe" && c.Database.WritePolicy != "fast" {
+		return fmt.Errorf("invalid database.write_policy: %s (must be safe to fsync every write, or fast to skip fsync at the risk of losing or corrupting data on a crash)", c.Database.WritePolicy)
+	}
+
+	// Validate future timestamp handling
+	if c.Server.FutureTimestamp != "" && c.Server.FutureTimestamp != "clamp" && c.Server.FutureTimestamp != "reject" {
+		return fmt.Errorf("invalid server.future_timestamp: %s (must be one of: clamp, reject)", c.Server.FutureTimestamp)
+	}
+	if c.Server.ClockSkewTolerance != "" {
+		tolerance, err := time.ParseDuration(c.Server.ClockSkewTolerance)
+		if err != nil {
+			return fmt.Errorf("invalid server.clock_skew_tolerance: %s (%w)", c.Server.ClockSkewTolerance, err)
+		}
+		if tolerance < 0 {
+			return fmt.Errorf("server.clock_skew_tolerance must not be negative, got: %s", c.Server.ClockSkewTolerance)
+		}
+	}
+	if c.Server.DedupWindow != "" {
+		window, err := time.ParseDuration(c.Server.DedupWindow)
+		if err != nil {
+			return fmt.Errorf("invalid server.dedup_window: %s (%w)", c.Server.DedupWindow, err)
+		}
+		if window < 0 {
+			return fmt.Errorf("server.dedup_window must not be negative, got: %s", c.Server.DedupWindow)
+		}
+	}
+
+	// Validate max query period
+	if err := c.Server.ValidateMaxQueryPeriod(); err != nil {
+		return fmt.Errorf("invalid server.max_query_period: %w", err)
+	}
+
+	// Validate the stats stream interval
+	if c.Server.StreamInterval != "" {
+		interval, err := time.ParseDuration(c.Server.StreamInterval)
+		if err != nil {
+			return fmt.Errorf("invalid server.stream_interval: %s (%w)", c.Server.StreamInterval, err)
+		}
+		if interval < time.Second {
+			return fmt.Errorf("server.stream_interval must be at least 1s, got: %s", c.Server.StreamInterval)
+		}
+	}
+
+	// Validate the bulk append batch size
+	if c.Server.MaxBulkAppend < 0 {
+		return fmt.Errorf("server.max_bulk_append must be >= 0, got: %d", c.Server.MaxBulkAppend)
+	}
+
+	// Validate the peer allow-list
+	for i, network := range c.Server.AllowedNetworks {
+		if _, err := netip.ParsePrefix(strings.TrimSpace(network)); err != nil {
+			return fmt.Errorf("invalid server.allowed_networks[%d]: %s (must be a CIDR such as 10.0.0.0/8)", i, network)
+		}
+	}
+
+	// Validate query rate limit
+	if c.Server.RateLimit.RequestsPerSecond < 0 {
+		return fmt.Errorf("server.rate_limit.requests_per_second must be >= 0, got: %g", c.Server.RateLimit.RequestsPerSecond)
+	}
+	if c.Server.RateLimit.RequestsPerSecond > 0 && c.Server.RateLimit.Burst < 1 {
+		return fmt.Errorf("server.rate_limit.burst must be at least 1 when rate limiting is enabled, got: %d", c.Server.RateLimit.Burst)
+	}
+
+	// Validate the StatsD emitter, only checked when enabled
+	if err := c.Server.Metrics.Validate(); err != nil {
+		return err
+	}
+	if err := c.Server.StatsD.Validate(); err != nil {
+		return err
+	}
+	if err := c.Server.AutoExport.Validate(); err != nil {
+		return err
+	}
+
+	// Validate the extra OTLP listeners, receiving needs write access
+	if c.Server.ReadOnly && len(c.Server.Listeners) > 0 {
+		return fmt.Errorf("server.listeners cannot be used with server.read_only")
+	}
+	if err := c.Server.ValidateListeners(); err != nil {
+		return err
+	}
+
 	// Validate cache TTL
 	if c.Server.Cache.Stats.TTL != "" {
 		_, err := time.ParseDuration(c.Server.Cache.Stats.TTL)
@@ -221,6 +991,11 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate cache size bound
+	if c.Server.Cache.Stats.MaxEntries < 0 {
+		return fmt.Errorf("server.cache.stats.max_entries must not be negative (got %d)", c.Server.Cache.Stats.MaxEntries)
+	}
+
 	return nil
 }
 
@@ -249,6 +1024,271 @@ func (s *Server) IsRetentionEnabled() bool {
 	return s.Retention != "" && s.Retention != "never"
 }
 
+// GetMaxRecords returns the maximum number of stored requests, 0 means unlimited
+func (s *Server) GetMaxRecords() int {
+	return s.MaxRecords
+}
+
+// IsReadOnly returns true if the server runs in query-only mode
+func (s *Server) IsReadOnly() bool {
+	return s.ReadOnly
+}
+
+// GetAuthToken returns the token clients must present, or empty if auth is disabled
+func (s *Server) GetAuthToken() string {
+	return s.AuthToken
+}
+
+// GetRateLimit returns the per-client query rate limit; a zero rate means disabled
+func (s *Server) GetRateLimit() (float64, int) {
+	return s.RateLimit.RequestsPerSecond, s.RateLimit.Burst
+}
+
+// IsSyncDisabled returns true when writes skip fsync for throughput
+func (d *Database) IsSyncDisabled() bool {
+	return d.WritePolicy == "fast"
+}
+
+// GetFutureTimestampPolicy returns how records stamped beyond the clock skew tolerance are handled
+func (s *Server) GetFutureTimestampPolicy() string {
+	if s.FutureTimestamp == "" {
+		return "clamp"
+	}
+	return s.FutureTimestamp
+}
+
+// GetClockSkewTolerance returns how far ahead of the server clock a record may be stamped
+func (s *Server) GetClockSkewTolerance() time.Duration {
+	if s.ClockSkewTolerance == "" {
+		return 5 * time.Minute
+	}
+
+	tolerance, err := time.ParseDuration(s.ClockSkewTolerance)
+	if err != nil {
+		return 5 * time.Minute // Should not happen after validation
+	}
+
+	return tolerance
+}
+
+// GetDedupWindow returns how close requests of the same session, model and usage must be to count as duplicates
+func (s *Server) GetDedupWindow() time.Duration {
+	if s.DedupWindow == "" {
+		return time.Second
+	}
+
+	window, err := time.ParseDuration(s.DedupWindow)
+	if err != nil {
+		return time.Second // Should not happen after validation
+	}
+
+	return window
+}
+
+// GetStreamInterval returns how often streamed stats are pushed when no new data arrives
+func (s *Server) GetStreamInterval() time.Duration {
+	if s.StreamInterval == "" {
+		return 10 * time.Second
+	}
+
+	interval, err := time.ParseDuration(s.StreamInterval)
+	if err != nil {
+		return 10 * time.Second // Should not happen after validation
+	}
+
+	return interval
+}
+
+// GetAllowedNetworks returns the networks peers must connect from, empty when every peer is allowed
+func (s *Server) GetAllowedNetworks() []netip.Prefix {
+	networks := make([]netip.Prefix, 0, len(s.AllowedNetworks))
+	for _, network := range s.AllowedNetworks {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(network))
+		if err != nil {
+			continue // Should not happen after validation
+		}
+		networks = append(networks, prefix.Masked())
+	}
+	return networks
+}
+
+// GetMaxBulkAppend returns the most records a BulkAppend call may carry
+func (s *Server) GetMaxBulkAppend() int {
+	if s.MaxBulkAppend == 0 {
+		return query.DefaultMaxBulkAppend
+	}
+	return s.MaxBulkAppend
+}
+
+// ValidateMaxQueryPeriod validates the max query period configuration
+func (s *Server) ValidateMaxQueryPeriod() error {
+	if s.MaxQueryPeriod == "" || s.MaxQueryPeriod == "never" {
+		return nil // No limit is valid
+	}
+
+	duration, err := s.parseRetentionDuration(s.MaxQueryPeriod)
+	if err != nil {
+		return fmt.Errorf("invalid duration format: %s", s.MaxQueryPeriod)
+	}
+
+	// A positive limit below an hour would reject the TUI's own hour filter
+	if duration != 0 && duration < time.Hour {
+		return fmt.Errorf("max query period must be at least 1h, got: %s", s.MaxQueryPeriod)
+	}
+
+	return nil
+}
+
+// GetMaxQueryPeriod returns the longest explicit range a request list query may cover, or zero if unlimited
+func (s *Server) GetMaxQueryPeriod() time.Duration {
+	if s.MaxQueryPeriod == "" || s.MaxQueryPeriod == "never" {
+		return 0
+	}
+
+	duration, err := s.parseRetentionDuration(s.MaxQueryPeriod)
+	if err != nil {
+		return 0 // Should not happen after validation
+	}
+
+	return duration
+}
+
+// Validate checks that every metric and attribute name is set when metrics ingestion is enabled
+func (m *Metrics) Validate() error {
+	if !m.Enabled {
+		return nil
+	}
+
+	names := []struct{ key, value string }{
+		{"token_metric", m.TokenMetric},
+		{"cost_metric", m.CostMetric},
+		{"type_attribute", m.TypeAttribute},
+		{"model_attribute", m.ModelAttribute},
+		{"session_attribute", m.SessionAttribute},
+	}
+	for _, name := range names {
+		if strings.TrimSpace(name.value) == "" {
+			return fmt.Errorf("server.metrics.%s must be set when server.metrics.enabled is true", name.key)
+		}
+	}
+	if m.TokenMetric == m.CostMetric {
+		return fmt.Errorf("server.metrics.token_metric and server.metrics.cost_metric must differ, got: %s", m.TokenMetric)
+	}
+	return nil
+}
+
+// GetMetricNames returns the token and cost metrics to ingest, both empty when metrics ingestion is disabled
+func (s *Server) GetMetricNames() (tokenMetric, costMetric string) {
+	if !s.Metrics.Enabled {
+		return "", ""
+	}
+	return s.Metrics.TokenMetric, s.Metrics.CostMetric
+}
+
+// GetMetricAttributes returns the data point attributes holding the token type, model and session ID
+func (s *Server) GetMetricAttributes() (typeAttribute, modelAttribute, sessionAttribute string) {
+	return s.Metrics.TypeAttribute, s.Metrics.ModelAttribute, s.Metrics.SessionAttribute
+}
+
+// ValidateListeners checks each extra OTLP listener has its own address, a token and a sender value
+func (s *Server) ValidateListeners() error {
+	addresses := map[string]bool{s.Address: true}
+	for i, listener := range s.Listeners {
+		if _, _, err := net.SplitHostPort(listener.Address); err != nil {
+			return fmt.Errorf("invalid server.listeners[%d].address: %s (must be host:port)", i, listener.Address)
+		}
+		if addresses[listener.Address] {
+			return fmt.Errorf("server.listeners[%d].address must differ from server.address and other listeners, got: %s", i, listener.Address)
+		}
+		addresses[listener.Address] = true
+
+		if strings.TrimSpace(listener.AuthToken) == "" {
+			return fmt.Errorf("server.listeners[%d].auth_token must not be empty", i)
+		}
+		if strings.TrimSpace(listener.Value) == "" {
+			return fmt.Errorf("server.listeners[%d].value must not be empty", i)
+		}
+	}
+	return nil
+}
+
+// GetListeners returns the extra OTLP listeners, labeling requests as user when no label is set
+func (s *Server) GetListeners() []grpcserver.Listener {
+	listeners := make([]grpcserver.Listener, 0, len(s.Listeners))
+	for _, listener := range s.Listeners {
+		label := strings.TrimSpace(listener.Label)
+		if label == "" {
+			label = "user"
+		}
+		listeners = append(listeners, grpcserver.Listener{
+			Address:   listener.Address,
+			AuthToken: listener.AuthToken,
+			Label:     label,
+			Value:     strings.TrimSpace(listener.Value),
+		})
+	}
+	return listeners
+}
+
+// Validate checks the StatsD address, interval and metric names when an address is set
+func (s *StatsD) Validate() error {
+	if s.Address == "" {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(s.Address); err != nil {
+		return fmt.Errorf("invalid server.statsd.address: %s (must be host:port)", s.Address)
+	}
+
+	interval, err := time.ParseDuration(s.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid server.statsd.interval: %s (%w)", s.Interval, err)
+	}
+	if interval < time.Second {
+		return fmt.Errorf("server.statsd.interval must be at least 1s, got: %s", s.Interval)
+	}
+
+	for key, name := range s.Names {
+		if _, err := entity.ParseUsageGauge(key); err != nil {
+			return fmt.Errorf("invalid server.statsd.names: %w", err)
+		}
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid server.statsd.names: empty name for %s", key)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the auto export interval and format when a directory is set
+func (a *AutoExport) Validate() error {
+	if a.Directory == "" {
+		return nil
+	}
+
+	if _, err := entity.ParseExportInterval(a.Interval); err != nil {
+		return fmt.Errorf("invalid server.auto_export.interval: %w", err)
+	}
+	if _, err := cli.ParseExportFormat(a.Format); err != nil {
+		return fmt.Errorf("invalid server.auto_export.format: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatsDInterval returns how often usage gauges are pushed to StatsD, zero when the emitter is disabled
+func (s *Server) GetStatsDInterval() time.Duration {
+	if s.StatsD.Address == "" {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(s.StatsD.Interval)
+	if err != nil {
+		return 0 // Should not happen after validation
+	}
+	return interval
+}
+
 // GetRetentionDuration returns the retention duration or zero if disabled
 func (s *Server) GetRetentionDuration() time.Duration {
 	if !s.IsRetentionEnabled() {
@@ -282,11 +1322,63 @@ func (s *Server) parseRetentionDuration(retention string) (time.Duration, error)
 	return time.ParseDuration(retention)
 }
 
+// GetQuietHours returns the parsed quiet hours windows, skipping invalid ones
+func (n *Notifications) GetQuietHours() []entity.QuietHours {
+	var windows []entity.QuietHours
+	for _, value := range n.QuietHours {
+		window, err := entity.ParseQuietHours(value)
+		if err != nil {
+			continue // Should not happen after validation
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// GetAuthScheme returns how the auth token is presented to the server, raw when unset
+func (m *Monitor) GetAuthScheme() entity.AuthScheme {
+	scheme, err := entity.ParseAuthScheme(m.AuthScheme)
+	if err != nil {
+		return entity.AuthSchemeRaw // Should not happen after validation
+	}
+	return scheme
+}
+
+// GetDurationStyle returns how durations are rendered, the default style when unset
+func (m *Monitor) GetDurationStyle() entity.DurationStyle {
+	style, err := entity.ParseDurationStyle(m.DurationFormat)
+	if err != nil {
+		return entity.DurationStyleDefault // Should not happen after validation
+	}
+	return style
+}
+
+// GetSchedule returns the parsed schedule of @schedule_cost, false when none is configured
+func (m *Monitor) GetSchedule() (entity.Schedule, bool) {
+	if m.Schedule == "" {
+		return entity.Schedule{}, false
+	}
+	schedule, err := entity.ParseSchedule(m.Schedule)
+	if err != nil {
+		return entity.Schedule{}, false // Should not happen after validation
+	}
+	return schedule, true
+}
+
+// GetNumberLocale returns the configured number locale, falling back to en-US when it is invalid
+func (m *Monitor) GetNumberLocale() entity.NumberLocale {
+	locale, err := entity.ParseNumberLocale(m.Locale)
+	if err != nil {
+		return entity.DefaultNumberLocale.WithGrouping(m.NumberGrouping)
+	}
+	return locale.WithGrouping(m.NumberGrouping)
+}
+
 // GetTokenLimit returns the effective token limit based on plan and config
 func (c *Claude) GetTokenLimit() int {
 	// If max_tokens is explicitly set, use it
 	if c.MaxTokens > 0 {
-		return c.MaxTokens
+		return int(c.MaxTokens)
 	}
 
 	// Otherwise, use plan defaults
@@ -302,7 +1394,21 @@ func (c *Claude) GetTokenLimit() int {
 	}
 }
 
+// GetBlockTokenMetric returns which premium tokens count against the block token limit
+func (c *Claude) GetBlockTokenMetric() entity.BlockTokenMetric {
+	metric, err := entity.ParseBlockTokenMetric(c.BlockTokenMetric)
+	if err != nil {
+		return entity.BlockTokenMetricLimited // Should not happen after validation
+	}
+	return metric
+}
+
 // GetClaudePlan returns the configured Claude plan, implementing PlanConfig interface
 func (c *Config) GetClaudePlan() string {
 	return c.Claude.Plan
 }
+
+// GetClaudePlanPrice returns the configured plan price override, 0 keeps the plan's price
+func (c *Config) GetClaudePlanPrice() float64 {
+	return c.Claude.PlanPrice
+}
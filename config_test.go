@@ -1,9 +1,16 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	grpcserver "github.com/elct9620/ccmon/handler/grpc"
+	"github.com/elct9620/ccmon/handler/grpc/query"
+	"github.com/spf13/viper"
 )
 
 func TestServer_ValidateRetention(t *testing.T) {
@@ -213,13 +220,46 @@ func TestServer_GetRetentionDuration(t *testing.T) {
 	}
 }
 
-func TestConfig_ValidateRetentionIntegration(t *testing.T) {
+func TestServer_GetListeners(t *testing.T) {
+	server := &Server{
+		Listeners: []Listener{
+			{Address: "0.0.0.0:4318", AuthToken: "alice-token", Value: "alice"},
+			{Address: "0.0.0.0:4319", AuthToken: "ci-token", Label: "team", Value: " platform "},
+		},
+	}
+
+	got := server.GetListeners()
+	want := []grpcserver.Listener{
+		{Address: "0.0.0.0:4318", AuthToken: "alice-token", Label: "user", Value: "alice"},
+		{Address: "0.0.0.0:4319", AuthToken: "ci-token", Label: "team", Value: "platform"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetListeners() = %+v, want %+v", got, want)
+	}
+}
+
+func TestServer_GetMaxBulkAppend(t *testing.T) {
 	tests := []struct {
-		name    string
-		config  Config
-		wantErr bool
-		errMsg  string
+		name          string
+		maxBulkAppend int
+		want          int
 	}{
+		{name: "unset uses the query service default", maxBulkAppend: 0, want: query.DefaultMaxBulkAppend},
+		{name: "configured limit", maxBulkAppend: 5000, want: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{MaxBulkAppend: tt.maxBulkAppend}
+			if got := server.GetMaxBulkAppend(); got != tt.want {
+				t.Errorf("GetMaxBulkAppend() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateRetentionIntegration(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
 		{
 			name: "valid config with retention",
 			config: Config{
@@ -255,24 +295,2754 @@ func TestConfig_ValidateRetentionIntegration(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid server.retention",
 		},
-	}
+		{
+			name: "valid read-only config without retention",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					ReadOnly:  true,
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid read-only config with retention",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "7d",
+					ReadOnly:  true,
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "cannot be used with server.read_only",
+		},
+	})
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := tt.config.Validate()
+func TestConfig_ValidateDatabase(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid fast database write policy",
+			config: Config{
+				Database: Database{
+					WritePolicy: "fast",
+				},
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid database write policy",
+			config: Config{
+				Database: Database{
+					WritePolicy: "nosync",
+				},
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid database.write_policy",
+		},
+	})
+}
 
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Config.Validate() expected error but got none")
-					return
-				}
-				if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
-					t.Errorf("Config.Validate() error = %v, want error containing %v", err, tt.errMsg)
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Config.Validate() unexpected error = %v", err)
-				}
+func TestConfig_ValidateServer(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "invalid negative cache max entries",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Cache: ServerCache{
+						Stats: CacheStats{Enabled: true, TTL: "1m", MaxEntries: -1},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "max_entries must not be negative",
+		},
+		{
+			name: "invalid future timestamp policy",
+			config: Config{
+				Server: Server{
+					Address:         "127.0.0.1:4317",
+					Retention:       "never",
+					FutureTimestamp: "drop",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.future_timestamp",
+		},
+		{
+			name: "invalid negative clock skew tolerance",
+			config: Config{
+				Server: Server{
+					Address:            "127.0.0.1:4317",
+					Retention:          "never",
+					ClockSkewTolerance: "-1m",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "clock_skew_tolerance must not be negative",
+		},
+		{
+			name: "valid reject future timestamps",
+			config: Config{
+				Server: Server{
+					Address:            "127.0.0.1:4317",
+					Retention:          "never",
+					FutureTimestamp:    "reject",
+					ClockSkewTolerance: "1m",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "disabled dedup window",
+			config: Config{
+				Server: Server{
+					Address:     "127.0.0.1:4317",
+					Retention:   "never",
+					DedupWindow: "0",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid dedup window",
+			config: Config{
+				Server: Server{
+					Address:     "127.0.0.1:4317",
+					Retention:   "never",
+					DedupWindow: "soon",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.dedup_window",
+		},
+		{
+			name: "invalid negative dedup window",
+			config: Config{
+				Server: Server{
+					Address:     "127.0.0.1:4317",
+					Retention:   "never",
+					DedupWindow: "-1s",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.dedup_window must not be negative",
+		},
+		{
+			name: "valid stream interval",
+			config: Config{
+				Server: Server{
+					Address:        "127.0.0.1:4317",
+					Retention:      "never",
+					StreamInterval: "30s",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid stream interval",
+			config: Config{
+				Server: Server{
+					Address:        "127.0.0.1:4317",
+					Retention:      "never",
+					StreamInterval: "soon",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.stream_interval",
+		},
+		{
+			name: "stream interval below one second",
+			config: Config{
+				Server: Server{
+					Address:        "127.0.0.1:4317",
+					Retention:      "never",
+					StreamInterval: "500ms",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.stream_interval must be at least 1s",
+		},
+		{
+			name: "valid max bulk append",
+			config: Config{
+				Server: Server{
+					Address:       "127.0.0.1:4317",
+					Retention:     "never",
+					MaxBulkAppend: 5000,
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative max bulk append",
+			config: Config{
+				Server: Server{
+					Address:       "127.0.0.1:4317",
+					Retention:     "never",
+					MaxBulkAppend: -1,
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.max_bulk_append must be >= 0",
+		},
+		{
+			name: "valid max records",
+			config: Config{
+				Server: Server{
+					Address:    "127.0.0.1:4317",
+					Retention:  "never",
+					MaxRecords: 1000,
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative max records",
+			config: Config{
+				Server: Server{
+					Address:    "127.0.0.1:4317",
+					Retention:  "never",
+					MaxRecords: -1,
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.max_records must not be negative",
+		},
+		{
+			name: "max records with read only",
+			config: Config{
+				Server: Server{
+					Address:    "127.0.0.1:4317",
+					Retention:  "never",
+					MaxRecords: 1000,
+					ReadOnly:   true,
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.max_records cannot be used with server.read_only",
+		},
+		{
+			name: "valid max query period in days",
+			config: Config{
+				Server: Server{
+					Address:        "127.0.0.1:4317",
+					Retention:      "never",
+					MaxQueryPeriod: "90d",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid disabled max query period",
+			config: Config{
+				Server: Server{
+					Address:        "127.0.0.1:4317",
+					Retention:      "never",
+					MaxQueryPeriod: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid max query period format",
+			config: Config{
+				Server: Server{
+					Address:        "127.0.0.1:4317",
+					Retention:      "never",
+					MaxQueryPeriod: "a year",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.max_query_period",
+		},
+		{
+			name: "invalid max query period below an hour",
+			config: Config{
+				Server: Server{
+					Address:        "127.0.0.1:4317",
+					Retention:      "never",
+					MaxQueryPeriod: "30m",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "max query period must be at least 1h",
+		},
+		{
+			name: "invalid negative rate limit",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					RateLimit: RateLimit{RequestsPerSecond: -1, Burst: 20},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "requests_per_second must be >= 0",
+		},
+		{
+			name: "invalid rate limit without burst",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					RateLimit: RateLimit{RequestsPerSecond: 5, Burst: 0},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "burst must be at least 1",
+		},
+		{
+			name: "valid rate limit",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					RateLimit: RateLimit{RequestsPerSecond: 5, Burst: 20},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid allowed networks",
+			config: Config{
+				Server: Server{
+					Address:         "127.0.0.1:4317",
+					Retention:       "never",
+					AllowedNetworks: []string{"10.0.0.0/8", "fd00::/8"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid allowed network",
+			config: Config{
+				Server: Server{
+					Address:         "127.0.0.1:4317",
+					Retention:       "never",
+					AllowedNetworks: []string{"10.0.0.0/8", "10.0.0.1"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.allowed_networks[1]",
+		},
+	})
+}
+
+func TestConfig_ValidateListeners(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid listeners",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Listeners: []Listener{
+						{Address: "0.0.0.0:4318", AuthToken: "alice-token", Value: "alice"},
+						{Address: "0.0.0.0:4319", AuthToken: "bob-token", Value: "bob"},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "listener with invalid address",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Listeners: []Listener{
+						{Address: "4318", AuthToken: "alice-token", Value: "alice"},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.listeners[0].address: 4318",
+		},
+		{
+			name: "listener reusing server address",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Listeners: []Listener{
+						{Address: "127.0.0.1:4317", AuthToken: "alice-token", Value: "alice"},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.listeners[0].address must differ",
+		},
+		{
+			name: "listeners sharing an address",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Listeners: []Listener{
+						{Address: "0.0.0.0:4318", AuthToken: "alice-token", Value: "alice"},
+						{Address: "0.0.0.0:4318", AuthToken: "bob-token", Value: "bob"},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.listeners[1].address must differ",
+		},
+		{
+			name: "listener without auth token",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Listeners: []Listener{
+						{Address: "0.0.0.0:4318", AuthToken: "", Value: "alice"},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.listeners[0].auth_token must not be empty",
+		},
+		{
+			name: "listener without value",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Listeners: []Listener{
+						{Address: "0.0.0.0:4318", AuthToken: "alice-token", Value: ""},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.listeners[0].value must not be empty",
+		},
+		{
+			name: "listeners with read only",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					ReadOnly:  true,
+					Listeners: []Listener{
+						{Address: "0.0.0.0:4318", AuthToken: "alice-token", Value: "alice"},
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.listeners cannot be used with server.read_only",
+		},
+	})
+}
+
+func TestConfig_ValidatePlanPrice(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid plan price",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:      "pro",
+					PlanPrice: 17,
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid negative plan price",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:      "pro",
+					PlanPrice: -1,
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.plan_price must be >= 0",
+		},
+		{
+			name: "invalid plan price without a plan",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:      "unset",
+					PlanPrice: 17,
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.plan_price requires claude.plan",
+		},
+	})
+}
+
+func TestConfig_ValidateModels(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid model rates",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:  "pro",
+					Rates: []ModelRate{{Model: "claude-*sonnet*", Input: 3, CacheRead: 0.3}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid model rate without model",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:  "pro",
+					Rates: []ModelRate{{Model: " ", Input: 3, CacheRead: 0.3}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.rates[0].model must not be empty",
+		},
+		{
+			name: "invalid negative output rate",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:  "pro",
+					Rates: []ModelRate{{Model: "claude-*sonnet*", Input: 3, Output: -15}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.rates[0] rates must be >= 0 (model: claude-*sonnet*)",
+		},
+		{
+			name: "invalid negative model rate",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:  "pro",
+					Rates: []ModelRate{{Model: "claude-*", Input: 3, CacheRead: -1}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "rates must be >= 0",
+		},
+		{
+			name: "valid model tier overrides",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:       "pro",
+					ModelTiers: []ModelTierOverride{{Model: "local-llama", Tier: "base"}, {Model: "claude-3-5-haiku-20241022", Tier: "Premium"}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid model tier without model",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:       "pro",
+					ModelTiers: []ModelTierOverride{{Model: "", Tier: "base"}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.model_tiers[0].model must not be empty",
+		},
+		{
+			name: "invalid model tier name",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:       "pro",
+					ModelTiers: []ModelTierOverride{{Model: "local-llama", Tier: "sonnet"}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid model tier",
+		},
+		{
+			name: "valid model display names",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:       "pro",
+					ModelNames: []ModelName{{Model: "claude-3-5-sonnet-*", Name: "Sonnet 3.5"}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid model display name without model",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:       "pro",
+					ModelNames: []ModelName{{Model: " ", Name: "Sonnet"}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.model_names[0].model must not be empty",
+		},
+		{
+			name: "invalid model display name without name",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:       "pro",
+					ModelNames: []ModelName{{Model: "claude-*-sonnet-*", Name: ""}},
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.model_names[0].name must not be empty (model: claude-*-sonnet-*)",
+		},
+	})
+}
+
+func TestConfig_ValidateMonitor(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "invalid negative min cost",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					MinCost:  -0.5,
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.min_cost must be >= 0",
+		},
+		{
+			name: "invalid negative negligible cost",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:       "UTC",
+					NegligibleCost: -0.01,
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.negligible_cost must be >= 0",
+		},
+		{
+			name: "valid schedule",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Schedule: "mon-fri 09:00-17:00",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid schedule",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Schedule: "weekdays 9-5",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.schedule",
+		},
+		{
+			name: "valid summary default command",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:       "UTC",
+					DefaultCommand: "summary",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid format default command",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:       "UTC",
+					DefaultCommand: "format",
+					DefaultFormat:  "@daily_cost",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "format default command requires a format",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:       "UTC",
+					DefaultCommand: "format",
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.default_format is required",
+		},
+		{
+			name: "invalid default command",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:       "UTC",
+					DefaultCommand: "export",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.default_command",
+		},
+		{
+			name: "valid list window",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					ListWindow: "2h",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid list window",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					ListWindow: "two hours",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.list_window",
+		},
+		{
+			name: "list window too short",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					ListWindow: "30s",
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.list_window must be at least 1m",
+		},
+		{
+			name: "min refresh interval below floor",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:           "UTC",
+					MinRefreshInterval: "500ms",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid min refresh interval",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:           "UTC",
+					MinRefreshInterval: "fast",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.min_refresh_interval",
+		},
+		{
+			name: "min refresh interval too long",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:           "UTC",
+					MinRefreshInterval: "10m",
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.min_refresh_interval must be at most 5m",
+		},
+		{
+			name: "valid project label",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:     "UTC",
+					ProjectLabel: "project",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "project label with a value",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:     "UTC",
+					ProjectLabel: "project=web",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.project_label",
+		},
+		{
+			name: "project label with several keys",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:     "UTC",
+					ProjectLabel: "project,team",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.project_label",
+		},
+		{
+			name: "disabled stale after",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					StaleAfter: "0",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid stale after",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					StaleAfter: "an hour",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.stale_after",
+		},
+		{
+			name: "negative stale after",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					StaleAfter: "-1h",
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.stale_after must not be negative",
+		},
+		{
+			name: "valid rolling days",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:    "UTC",
+					RollingDays: 14,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative rolling days",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:    "UTC",
+					RollingDays: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.rolling_days must be between 0 and 365",
+		},
+		{
+			name: "rolling days over a year",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:    "UTC",
+					RollingDays: 366,
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.rolling_days must be between 0 and 365",
+		},
+		{
+			name: "bearer auth scheme",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					AuthScheme: "bearer",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown auth scheme",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					AuthScheme: "digest",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.auth_scheme",
+		},
+	})
+}
+
+func TestConfig_ValidateMonitorDisplay(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "invalid sparkline interval below one minute",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:          "UTC",
+					SparklineInterval: "30s",
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.sparkline_interval must be at least 1m",
+		},
+		{
+			name: "invalid sparkline bucket count",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:          "UTC",
+					SparklineInterval: "1h",
+					SparklineBuckets:  -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.sparkline_buckets must be between 0 and 60",
+		},
+		{
+			name: "empty format error output with zero exit code",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:            "UTC",
+					FormatError:         "",
+					FormatErrorExitCode: 0,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid format error exit code",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:            "UTC",
+					FormatErrorExitCode: 255,
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.format_error_exit_code must be between 0 and 125",
+		},
+		{
+			name: "valid number locale",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:       "UTC",
+					Locale:         "de_DE",
+					NumberGrouping: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid number locale",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Locale:   "klingon",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.locale",
+		},
+		{
+			name: "valid token decimals",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:      "UTC",
+					TokenDecimals: 1,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid token decimals",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:      "UTC",
+					TokenDecimals: 2,
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.token_decimals",
+		},
+		{
+			name: "valid percent decimals",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:        "UTC",
+					PercentDecimals: 1,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid percent decimals",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:        "UTC",
+					PercentDecimals: 2,
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.percent_decimals",
+		},
+		{
+			name: "valid export cost decimals",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:           "UTC",
+					ExportCostDecimals: 15,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid export cost decimals",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:           "UTC",
+					ExportCostDecimals: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.export_cost_decimals",
+		},
+		{
+			name: "invalid stats column",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:     "UTC",
+					StatsColumns: []string{"cost", "latency"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.stats_columns entry: latency",
+		},
+		{
+			name: "invalid stats alignment",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					StatsAlign: "center",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.stats_align",
+		},
+		{
+			name: "invalid daily order",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					DailyOrder: "ascending",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.daily_order",
+		},
+		{
+			name: "valid cumulative cost column",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					CostColumn: "cumulative",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid cost column",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:   "UTC",
+					CostColumn: "running",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.cost_column",
+		},
+	})
+}
+
+func TestConfig_ValidateNotifications(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid notification thresholds",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Notifications: Notifications{
+						Enabled:        true,
+						DailyCost:      []float64{10, 20.5},
+						DailyPlanUsage: []int{80, 100},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid notification cost threshold",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Notifications: Notifications{
+						DailyCost: []float64{10, 0},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.notifications.daily_cost[1] must be > 0",
+		},
+		{
+			name: "invalid notification plan usage threshold",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Notifications: Notifications{
+						DailyPlanUsage: []int{-80},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.notifications.daily_plan_usage[0] must be > 0",
+		},
+		{
+			name: "valid notification quiet hours",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Notifications: Notifications{
+						QuietHours: []string{"22:00-07:00", "12:00-13:00"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid notification quiet hours",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Notifications: Notifications{
+						QuietHours: []string{"22:00-07:00", "10pm-7am"},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.notifications.quiet_hours[1]",
+		},
+		{
+			name: "valid budget banner",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					BudgetBanner: BudgetBanner{
+						Thresholds:  []int{90, 100},
+						AutoDismiss: "30s",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid budget banner threshold",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					BudgetBanner: BudgetBanner{
+						Thresholds: []int{90, 0},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.budget_banner.thresholds[1] must be > 0",
+		},
+		{
+			name: "invalid budget banner auto dismiss",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					BudgetBanner: BudgetBanner{
+						AutoDismiss: "soon",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.budget_banner.auto_dismiss: soon",
+		},
+		{
+			name: "negative budget banner auto dismiss",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					BudgetBanner: BudgetBanner{
+						AutoDismiss: "-1s",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.budget_banner.auto_dismiss must be >= 0",
+		},
+	})
+}
+
+func TestConfig_ValidateStatsCache(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "file stats cache",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					StatsCache: MonitorStatsCache{
+						Backend: "file",
+						TTL:     "10s",
+						Path:    "/tmp/ccmon-stats",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid stats cache backend",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					StatsCache: MonitorStatsCache{
+						Backend: "redis",
+						TTL:     "10s",
+						Path:    "/tmp/ccmon-stats",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.stats_cache.backend",
+		},
+		{
+			name: "invalid stats cache ttl",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					StatsCache: MonitorStatsCache{
+						Backend: "file",
+						TTL:     "soon",
+						Path:    "/tmp/ccmon-stats",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.stats_cache.ttl",
+		},
+		{
+			name: "non-positive stats cache ttl",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					StatsCache: MonitorStatsCache{
+						Backend: "file",
+						TTL:     "0s",
+						Path:    "/tmp/ccmon-stats",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.stats_cache.ttl must be positive",
+		},
+		{
+			name: "file stats cache without path",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					StatsCache: MonitorStatsCache{
+						Backend: "file",
+						TTL:     "10s",
+						Path:    "",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.stats_cache.path must be set",
+		},
+	})
+}
+
+func TestConfig_ValidateBlock(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid block grace period",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:         "UTC",
+					BlockGracePeriod: "5m",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid block grace period",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:         "UTC",
+					BlockGracePeriod: "soon",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.block_grace_period",
+		},
+		{
+			name: "negative block grace period",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:         "UTC",
+					BlockGracePeriod: "-1m",
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.block_grace_period must be at least 0s and shorter than the 5h block",
+		},
+		{
+			name: "block grace period as long as a block",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone:         "UTC",
+					BlockGracePeriod: "5h",
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.block_grace_period must be at least 0s and shorter than the 5h block",
+		},
+		{
+			name: "valid soft limit",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:      "pro",
+					SoftLimit: 80,
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "soft limit at the hard limit",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:      "pro",
+					SoftLimit: 100,
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.soft_limit must be between 0 and 99",
+		},
+		{
+			name: "negative soft limit",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:      "pro",
+					SoftLimit: -5,
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.soft_limit must be between 0 and 99",
+		},
+		{
+			name: "total block token metric",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:             "pro",
+					BlockTokenMetric: "total",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid block token metric",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:             "pro",
+					BlockTokenMetric: "cache",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid claude.block_token_metric",
+		},
+		{
+			name: "negative base tokens",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan:       "pro",
+					BaseTokens: -1,
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "claude.base_tokens must be >= 0",
+		},
+	})
+}
+
+func TestConfig_ValidateStatsD(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid statsd",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					StatsD:    StatsD{Address: "127.0.0.1:8125", Interval: "10s", Names: map[string]string{"daily_cost": "cost.today"}},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "statsd address without port",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					StatsD:    StatsD{Address: "localhost", Interval: "10s"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.statsd.address",
+		},
+		{
+			name: "statsd interval below a second",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					StatsD:    StatsD{Address: "127.0.0.1:8125", Interval: "500ms"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.statsd.interval must be at least 1s",
+		},
+		{
+			name: "statsd name for unknown gauge",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					StatsD:    StatsD{Address: "127.0.0.1:8125", Interval: "10s", Names: map[string]string{"daily_tokens": "tokens"}},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.statsd.names",
+		},
+		{
+			name: "statsd disabled ignores other settings",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					StatsD:    StatsD{Interval: "bogus"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+	})
+}
+
+func TestConfig_ValidateMetricsIngestion(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid metrics ingestion",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Metrics:   Metrics{Enabled: true, TokenMetric: "claude_code.token.usage", CostMetric: "claude_code.cost.usage", TypeAttribute: "type", ModelAttribute: "model", SessionAttribute: "session.id"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "metrics ingestion without model attribute",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Metrics:   Metrics{Enabled: true, TokenMetric: "claude_code.token.usage", CostMetric: "claude_code.cost.usage", TypeAttribute: "type", ModelAttribute: "", SessionAttribute: "session.id"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.metrics.model_attribute must be set when server.metrics.enabled is true",
+		},
+		{
+			name: "metrics ingestion with the same token and cost metric",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Metrics:   Metrics{Enabled: true, TokenMetric: "claude_code.token.usage", CostMetric: "claude_code.token.usage", TypeAttribute: "type", ModelAttribute: "model", SessionAttribute: "session.id"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.metrics.token_metric and server.metrics.cost_metric must differ",
+		},
+		{
+			name: "metrics ingestion disabled ignores other settings",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Metrics:   Metrics{TokenMetric: ""},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+	})
+}
+
+func TestConfig_ValidateAutoExport(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "valid auto export",
+			config: Config{
+				Server: Server{
+					Address:    "127.0.0.1:4317",
+					Retention:  "never",
+					AutoExport: AutoExport{Directory: "/var/backups/ccmon", Interval: "month", Format: "jsonl"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "auto export with unknown interval",
+			config: Config{
+				Server: Server{
+					Address:    "127.0.0.1:4317",
+					Retention:  "never",
+					AutoExport: AutoExport{Directory: "/var/backups/ccmon", Interval: "week", Format: "csv"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.auto_export.interval",
+		},
+		{
+			name: "auto export with unknown format",
+			config: Config{
+				Server: Server{
+					Address:    "127.0.0.1:4317",
+					Retention:  "never",
+					AutoExport: AutoExport{Directory: "/var/backups/ccmon", Interval: "day", Format: "xml"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.auto_export.format",
+		},
+		{
+			name: "auto export disabled ignores other settings",
+			config: Config{
+				Server: Server{
+					Address:    "127.0.0.1:4317",
+					Retention:  "never",
+					AutoExport: AutoExport{Interval: "bogus"},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: false,
+		},
+	})
+}
+
+func TestConfig_ValidateStrictStartup(t *testing.T) {
+	runConfigValidationTests(t, []configValidationTest{
+		{
+			name: "empty timezone without strict startup",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty timezone with strict startup",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Startup: Startup{Strict: true},
+			},
+			wantErr: true,
+			errMsg:  "monitor.timezone is empty",
+		},
+		{
+			name: "local timezone with strict startup",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "Local",
+				},
+				Startup: Startup{Strict: true},
+			},
+			wantErr: true,
+			errMsg:  "monitor.timezone \"Local\"",
+		},
+		{
+			name: "iana timezone with strict startup",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "Asia/Tokyo",
+				},
+				Startup: Startup{Strict: true},
+			},
+			wantErr: false,
+		},
+	})
+}
+
+// configValidationTest is a Config.Validate case, with the error message the error must contain
+type configValidationTest struct {
+	name    string
+	config  Config
+	wantErr bool
+	errMsg  string
+}
+
+// runConfigValidationTests runs each case as a subtest of t
+func runConfigValidationTests(t *testing.T, tests []configValidationTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Config.Validate() expected error but got none")
+					return
+				}
+				if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Config.Validate() error = %v, want error containing %v", err, tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Config.Validate() unexpected error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigDecodeHook_TokenCount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		content    string
+		maxTokens  TokenCount
+		baseTokens TokenCount
+		errMsg     string
+	}{
+		{
+			name:       "plain integers",
+			content:    "[claude]\nmax_tokens = 44000\nbase_tokens = 0\n",
+			maxTokens:  44_000,
+			baseTokens: 0,
+		},
+		{
+			name:       "suffixed strings",
+			content:    "[claude]\nmax_tokens = \"44k\"\nbase_tokens = \"1.2M\"\n",
+			maxTokens:  44_000,
+			baseTokens: 1_200_000,
+		},
+		{
+			name:    "unknown suffix",
+			content: "[claude]\nmax_tokens = \"44x\"\n",
+			errMsg:  `invalid token count "44x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := viper.New()
+			v.SetConfigType("toml")
+			if err := v.ReadConfig(strings.NewReader(tt.content)); err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+
+			var config Config
+			err := v.Unmarshal(&config, viper.DecodeHook(configDecodeHook()))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Claude.MaxTokens != tt.maxTokens || config.Claude.BaseTokens != tt.baseTokens {
+				t.Errorf("got max_tokens %d and base_tokens %d, want %d and %d", config.Claude.MaxTokens, config.Claude.BaseTokens, tt.maxTokens, tt.baseTokens)
+			}
+		})
+	}
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []ModelRate
+		errMsg  string
+	}{
+		{
+			name: "reads every rate",
+			content: `[[rates]]
+model = "claude-*sonnet*"
+input = 3.0
+output = 15.0
+cache_read = 0.3
+cache_creation = 3.75
+`,
+			want: []ModelRate{{Model: "claude-*sonnet*", Input: 3.0, Output: 15.0, CacheRead: 0.3, CacheCreation: 3.75}},
+		},
+		{
+			name:    "requires at least one rate",
+			content: "# nothing here\n",
+			errMsg:  "has no [[rates]] entries",
+		},
+		{
+			name: "rejects negative rates",
+			content: `[[rates]]
+model = "claude-*"
+cache_creation = -1
+`,
+			errMsg: "rates[0] rates must be >= 0 (model: claude-*)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "rates.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write rates file: %v", err)
+			}
+
+			rates, err := LoadRatesFile(path)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("LoadRatesFile() error = %v, want containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadRatesFile() unexpected error: %v", err)
+			}
+			if len(rates) != len(tt.want) {
+				t.Fatalf("LoadRatesFile() = %+v, want %+v", rates, tt.want)
+			}
+			for i := range tt.want {
+				if rates[i] != tt.want[i] {
+					t.Errorf("rate %d = %+v, want %+v", i, rates[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := LoadRatesFile(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("LoadRatesFile() expected an error for a missing file")
+	}
+}
+
+func TestBindEnv(t *testing.T) {
+	content := `[server]
+address = "127.0.0.1:4317"
+retention = "never"
+
+[claude]
+plan = "pro"
+
+[monitor]
+timezone = "Asia/Tokyo"
+`
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantPlan     string
+		wantPrice    float64
+		wantTimezone string
+		errMsg       string
+	}{
+		{
+			name:         "config file without environment",
+			wantPlan:     "pro",
+			wantTimezone: "Asia/Tokyo",
+		},
+		{
+			name:         "environment overrides the config file",
+			env:          map[string]string{"CCMON_PLAN": "max", "CCMON_PLAN_PRICE": "90", "CCMON_TIMEZONE": "America/New_York"},
+			wantPlan:     "max",
+			wantPrice:    90,
+			wantTimezone: "America/New_York",
+		},
+		{
+			name:   "unknown plan fails validation",
+			env:    map[string]string{"CCMON_PLAN": "team"},
+			errMsg: "invalid claude plan: team",
+		},
+		{
+			name:   "invalid timezone fails validation",
+			env:    map[string]string{"CCMON_TIMEZONE": "Mars/Olympus"},
+			errMsg: "invalid timezone: Mars/Olympus",
+		},
+		{
+			name:   "invalid price fails to decode",
+			env:    map[string]string{"CCMON_PLAN_PRICE": "twenty"},
+			errMsg: "plan_price",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			v := viper.New()
+			v.SetDefault("claude.plan", "unset")
+			v.SetDefault("claude.plan_price", 0.0)
+			v.SetDefault("monitor.timezone", "UTC")
+			bindEnv(v)
+			v.SetConfigType("toml")
+			if err := v.ReadConfig(strings.NewReader(content)); err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+
+			var config Config
+			err := v.Unmarshal(&config, viper.DecodeHook(configDecodeHook()))
+			if err == nil {
+				err = config.Validate()
+			}
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if config.Claude.Plan != tt.wantPlan {
+				t.Errorf("plan = %q, want %q", config.Claude.Plan, tt.wantPlan)
+			}
+			if config.Claude.PlanPrice != tt.wantPrice {
+				t.Errorf("plan price = %v, want %v", config.Claude.PlanPrice, tt.wantPrice)
+			}
+			if config.Monitor.Timezone != tt.wantTimezone {
+				t.Errorf("timezone = %q, want %q", config.Monitor.Timezone, tt.wantTimezone)
+			}
+		})
+	}
+}
+
+func TestBindStrictStartupEnv(t *testing.T) {
+	content := `[startup]
+strict = false
+
+[monitor]
+timezone = "Asia/Tokyo"
+`
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantStrict bool
+		errMsg     string
+	}{
+		{name: "config file without environment", wantStrict: false},
+		{name: "environment enables strict startup", env: map[string]string{"CCMON_STRICT_STARTUP": "true"}, wantStrict: true},
+		{name: "invalid value fails to decode", env: map[string]string{"CCMON_STRICT_STARTUP": "sometimes"}, errMsg: "strict"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			v := viper.New()
+			v.SetDefault("startup.strict", false)
+			bindStrictStartupEnv(v)
+			v.SetConfigType("toml")
+			if err := v.ReadConfig(strings.NewReader(content)); err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+
+			var config Config
+			err := v.Unmarshal(&config, viper.DecodeHook(configDecodeHook()))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if config.Startup.Strict != tt.wantStrict {
+				t.Errorf("strict startup = %v, want %v", config.Startup.Strict, tt.wantStrict)
 			}
 		})
 	}
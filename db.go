@@ -17,7 +17,13 @@ const (
 
 // NewDatabase creates a new database instance
 func NewDatabase(dbPath string) (*bbolt.DB, error) {
-	return NewDatabaseWithOptions(dbPath, false)
+	return NewDatabaseWithOptions(dbPath, false, false)
+}
+
+// NewDatabaseWithoutSync creates a new database instance that does not fsync after each write.
+// This is faster for heavy ingestion, but a crash or power loss can lose recent writes or corrupt the file.
+func NewDatabaseWithoutSync(dbPath string) (*bbolt.DB, error) {
+	return NewDatabaseWithOptions(dbPath, false, true)
 }
 
 // NewDatabaseReadOnly creates a new read-only database instance
@@ -26,11 +32,11 @@ func NewDatabaseReadOnly(dbPath string) (*bbolt.DB, error) {
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("database file does not exist: %s (run server mode first to create it)", dbPath)
 	}
-	return NewDatabaseWithOptions(dbPath, true)
+	return NewDatabaseWithOptions(dbPath, true, false)
 }
 
 // NewDatabaseWithOptions creates a new database instance with specified options
-func NewDatabaseWithOptions(dbPath string, readOnly bool) (*bbolt.DB, error) {
+func NewDatabaseWithOptions(dbPath string, readOnly bool, noSync bool) (*bbolt.DB, error) {
 	// Create directory if it doesn't exist (for write mode)
 	if !readOnly {
 		dir := filepath.Dir(dbPath)
@@ -42,6 +48,7 @@ func NewDatabaseWithOptions(dbPath string, readOnly bool) (*bbolt.DB, error) {
 	options := &bbolt.Options{
 		Timeout:  1 * time.Second,
 		ReadOnly: readOnly,
+		NoSync:   noSync,
 	}
 
 	db, err := bbolt.Open(dbPath, 0600, options)
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,8 @@ type APIRequest struct {
 	tokens    Token
 	cost      Cost
 	duration  time.Duration
+	labels    map[string]string
+	note      string
 }
 
 // NewAPIRequest creates a new APIRequest entity
@@ -60,7 +63,72 @@ func (a APIRequest) DurationMS() int64 {
 	return int64(a.duration / time.Millisecond)
 }
 
+// CostPerThousandTokens returns the effective cost per 1K total tokens.
+// Returns false when the request has no tokens, as the rate is undefined.
+func (a APIRequest) CostPerThousandTokens() (Cost, bool) {
+	total := a.tokens.Total()
+	if total <= 0 {
+		return Cost{}, false
+	}
+	return NewCost(a.cost.Amount() / (float64(total) / 1000)), true
+}
+
+// WithLabels returns a copy of the request carrying the given labels
+func (a APIRequest) WithLabels(labels map[string]string) APIRequest {
+	a.labels = copyLabels(labels)
+	return a
+}
+
+// WithCost returns a copy of the request with its cost replaced
+func (a APIRequest) WithCost(cost Cost) APIRequest {
+	a.cost = cost
+	return a
+}
+
+// Labels returns a copy of the request labels (nil when the request has none)
+func (a APIRequest) Labels() map[string]string {
+	return copyLabels(a.labels)
+}
+
+// Label returns the value of the label with the given key
+func (a APIRequest) Label(key string) (string, bool) {
+	value, ok := a.labels[key]
+	return value, ok
+}
+
+// WithNote returns a copy of the request carrying the given note
+func (a APIRequest) WithNote(note string) APIRequest {
+	a.note = note
+	return a
+}
+
+// Note returns the manual note attached to the request (empty when none)
+func (a APIRequest) Note() string {
+	return a.note
+}
+
+// HealthCheckSessionPrefix marks the session ID of synthetic health check records
+const HealthCheckSessionPrefix = "ccmon-healthcheck-"
+
+// IsHealthCheck returns true for a synthetic health check record rather than real usage
+func (a APIRequest) IsHealthCheck() bool {
+	return strings.HasPrefix(a.sessionID, HealthCheckSessionPrefix)
+}
+
 // ID returns a unique identifier for the API request
 func (a APIRequest) ID() string {
 	return fmt.Sprintf("%s_%s", a.timestamp.Format(time.RFC3339Nano), a.sessionID)
 }
+
+// copyLabels copies a label map to keep APIRequest immutable
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]string, len(labels))
+	for key, value := range labels {
+		copied[key] = value
+	}
+	return copied
+}
@@ -108,3 +108,76 @@ func TestAPIRequest_ID_Uniqueness(t *testing.T) {
 		t.Errorf("Expected different IDs for different sessions, got same ID: %v", id3)
 	}
 }
+
+func TestAPIRequest_WithNote(t *testing.T) {
+	t.Parallel()
+
+	original := NewAPIRequest("session123", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), "claude-3-opus", NewToken(100, 50, 0, 0), NewCost(1.5), 1000)
+	annotated := original.WithNote("the big refactor run")
+
+	if annotated.Note() != "the big refactor run" {
+		t.Errorf("Note() = %q, want %q", annotated.Note(), "the big refactor run")
+	}
+	if original.Note() != "" {
+		t.Errorf("Expected original request to stay unannotated, got %q", original.Note())
+	}
+	if annotated.ID() != original.ID() {
+		t.Errorf("Expected note to keep the request ID, got %q and %q", annotated.ID(), original.ID())
+	}
+}
+
+func TestAPIRequest_CostPerThousandTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		tokens     Token
+		cost       float64
+		expected   float64
+		expectedOK bool
+	}{
+		{
+			name:       "cost spread over total tokens",
+			tokens:     NewToken(1500, 500, 0, 0),
+			cost:       0.03,
+			expected:   0.015,
+			expectedOK: true,
+		},
+		{
+			name:       "cache tokens count towards the total",
+			tokens:     NewToken(100, 100, 600, 200),
+			cost:       0.01,
+			expected:   0.01,
+			expectedOK: true,
+		},
+		{
+			name:       "free request has a zero rate",
+			tokens:     NewToken(1000, 0, 0, 0),
+			cost:       0,
+			expected:   0,
+			expectedOK: true,
+		},
+		{
+			name:       "no tokens has no rate",
+			tokens:     NewToken(0, 0, 0, 0),
+			cost:       0.01,
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := NewAPIRequest("session123", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), "claude-3-opus", tt.tokens, NewCost(tt.cost), 1000)
+			rate, ok := req.CostPerThousandTokens()
+
+			if ok != tt.expectedOK {
+				t.Fatalf("CostPerThousandTokens() ok = %v, want %v", ok, tt.expectedOK)
+			}
+			if diff := rate.Amount() - tt.expected; diff > 1e-12 || diff < -1e-12 {
+				t.Errorf("CostPerThousandTokens() = %v, want %v", rate.Amount(), tt.expected)
+			}
+		})
+	}
+}
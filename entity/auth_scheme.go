@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// AuthScheme is how a client presents its auth token in the "authorization" metadata
+type AuthScheme string
+
+const (
+	AuthSchemeRaw    AuthScheme = "raw"    // The token as is
+	AuthSchemeBearer AuthScheme = "bearer" // "Bearer <token>"
+	AuthSchemeBasic  AuthScheme = "basic"  // "Basic <base64 of ccmon:token>"
+)
+
+// BasicAuthUser is the user name sent with the token as password by the basic scheme
+const BasicAuthUser = "ccmon"
+
+// ParseAuthScheme validates an auth scheme name, empty selects the raw scheme
+func ParseAuthScheme(value string) (AuthScheme, error) {
+	switch AuthScheme(strings.ToLower(strings.TrimSpace(value))) {
+	case "", AuthSchemeRaw:
+		return AuthSchemeRaw, nil
+	case AuthSchemeBearer:
+		return AuthSchemeBearer, nil
+	case AuthSchemeBasic:
+		return AuthSchemeBasic, nil
+	default:
+		return "", fmt.Errorf("invalid auth scheme %q (expected raw, bearer or basic)", value)
+	}
+}
+
+// Authorization returns the "authorization" metadata value presenting the token with the scheme
+func (s AuthScheme) Authorization(token string) string {
+	switch s {
+	case AuthSchemeBearer:
+		return "Bearer " + token
+	case AuthSchemeBasic:
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(BasicAuthUser+":"+token))
+	default:
+		return token
+	}
+}
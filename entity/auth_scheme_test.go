@@ -0,0 +1,35 @@
+package entity
+
+import "testing"
+
+func TestAuthScheme_Authorization(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: "secret"},
+		{value: "raw", want: "secret"},
+		{value: "Bearer", want: "Bearer secret"},
+		{value: "basic", want: "Basic Y2Ntb246c2VjcmV0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			scheme, err := ParseAuthScheme(tt.value)
+			if err != nil {
+				t.Fatalf("ParseAuthScheme(%q) unexpected error: %v", tt.value, err)
+			}
+			if got := scheme.Authorization("secret"); got != tt.want {
+				t.Errorf("Authorization() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseAuthScheme("digest"); err == nil {
+		t.Error("ParseAuthScheme(\"digest\") expected error")
+	}
+}
@@ -1,17 +1,39 @@
 package entity
 
 import (
+	"fmt"
 	"time"
 )
 
 // TimeBlockDuration represents the duration of each Claude token limit block
 const TimeBlockDuration = 5 * time.Hour
 
+// BlockTokenMetric selects which premium tokens count against the block token limit
+type BlockTokenMetric string
+
+const (
+	BlockTokenMetricLimited BlockTokenMetric = "limited" // Input + output tokens, as Claude's rate limits count them (default)
+	BlockTokenMetricTotal   BlockTokenMetric = "total"   // Input, output, cache read and cache creation tokens
+)
+
+// ParseBlockTokenMetric parses a block token metric name; empty selects BlockTokenMetricLimited
+func ParseBlockTokenMetric(value string) (BlockTokenMetric, error) {
+	switch BlockTokenMetric(value) {
+	case "", BlockTokenMetricLimited:
+		return BlockTokenMetricLimited, nil
+	case BlockTokenMetricTotal:
+		return BlockTokenMetricTotal, nil
+	default:
+		return "", fmt.Errorf("unknown block token metric %q (must be limited or total)", value)
+	}
+}
+
 // Block represents a specific 5-hour token limit block for Claude
 // This is a value object representing a concrete time period with optional token limit
 type Block struct {
-	startAt    time.Time // Concrete timestamp when this block starts
-	tokenLimit int       // Token limit for this block (0 = no limit)
+	startAt     time.Time        // Concrete timestamp when this block starts
+	tokenLimit  int              // Token limit for this block (0 = no limit)
+	tokenMetric BlockTokenMetric // Premium tokens counted against the limit, empty counts limited tokens
 }
 
 // NewBlock creates a new Block from a concrete start timestamp without token limit
@@ -30,6 +52,21 @@ func NewBlockWithLimit(startAt time.Time, tokenLimit int) Block {
 	}
 }
 
+// InferBlock returns the block containing now, anchored at the hour of the first request of the day.
+// Without a request yet (zero firstRequestAt), the block starts at the current hour as the next request would start it.
+func InferBlock(firstRequestAt time.Time, timezone *time.Location, now time.Time, tokenLimit int) Block {
+	anchor := firstRequestAt
+	if anchor.IsZero() {
+		anchor = now
+	}
+
+	// Round down in the given timezone so half-hour offsets still start on the hour
+	anchorInTz := anchor.In(timezone)
+	blockStart := time.Date(anchorInTz.Year(), anchorInTz.Month(), anchorInTz.Day(), anchorInTz.Hour(), 0, 0, 0, timezone)
+
+	return NewBlockWithLimit(blockStart.UTC(), tokenLimit).NextBlock(now)
+}
+
 // StartAt returns the start time of this block
 func (b Block) StartAt() time.Time {
 	return b.startAt
@@ -45,6 +82,28 @@ func (b Block) TokenLimit() int {
 	return b.tokenLimit
 }
 
+// WithTokenMetric returns a copy of the block counting the given premium tokens against its limit
+func (b Block) WithTokenMetric(metric BlockTokenMetric) Block {
+	b.tokenMetric = metric
+	return b
+}
+
+// TokenMetric returns which premium tokens count against the limit
+func (b Block) TokenMetric() BlockTokenMetric {
+	if b.tokenMetric == "" {
+		return BlockTokenMetricLimited
+	}
+	return b.tokenMetric
+}
+
+// UsedTokens returns how many of the premium tokens count against the limit
+func (b Block) UsedTokens(premiumTokens Token) int64 {
+	if b.TokenMetric() == BlockTokenMetricTotal {
+		return premiumTokens.Total()
+	}
+	return premiumTokens.Limited()
+}
+
 // HasLimit returns true if this block has a token limit configured
 func (b Block) HasLimit() bool {
 	return b.tokenLimit > 0
@@ -58,7 +117,7 @@ func (b Block) CalculateProgress(premiumTokens Token) float64 {
 	}
 
 	// Only premium tokens count toward limits (Haiku is free)
-	used := premiumTokens.Limited()
+	used := b.UsedTokens(premiumTokens)
 	limit := int64(b.tokenLimit)
 
 	if limit == 0 {
@@ -75,7 +134,7 @@ func (b Block) IsLimitExceeded(premiumTokens Token) bool {
 		return false
 	}
 
-	used := premiumTokens.Limited()
+	used := b.UsedTokens(premiumTokens)
 	return used > int64(b.tokenLimit)
 }
 
@@ -97,7 +156,26 @@ func (b Block) NextBlock(now time.Time) Block {
 	delta := now.Sub(b.startAt)
 	blockIndex := int(delta / TimeBlockDuration)
 
-	// Create new block at the appropriate position, preserving token limit
+	// Create new block at the appropriate position, preserving token limit and metric
 	newStart := b.startAt.Add(time.Duration(blockIndex) * TimeBlockDuration)
-	return NewBlockWithLimit(newStart, b.tokenLimit)
+	return NewBlockWithLimit(newStart, b.tokenLimit).WithTokenMetric(b.tokenMetric)
+}
+
+// NextBlockAfterGrace returns NextBlock, but keeps this block until grace has passed after its end.
+// A zero grace behaves as NextBlock.
+func (b Block) NextBlockAfterGrace(now time.Time, grace time.Duration) Block {
+	if now.Before(b.EndAt().Add(grace)) {
+		return b
+	}
+	return b.NextBlock(now)
+}
+
+// InGracePeriod returns true if now is past the end of this block but still within grace of it
+func (b Block) InGracePeriod(now time.Time, grace time.Duration) bool {
+	return !now.Before(b.EndAt()) && now.Before(b.EndAt().Add(grace))
+}
+
+// PreviousBlock returns the block immediately before this one, preserving the token limit and metric
+func (b Block) PreviousBlock() Block {
+	return NewBlockWithLimit(b.startAt.Add(-TimeBlockDuration), b.tokenLimit).WithTokenMetric(b.tokenMetric)
 }
@@ -73,6 +73,199 @@ func TestBlock_NextBlock(t *testing.T) {
 	}
 }
 
+func TestBlock_NextBlockAfterGrace(t *testing.T) {
+	blockStart := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC) // 10am - 3pm block
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		grace       time.Duration
+		wantStart   time.Time
+		wantInGrace bool
+	}{
+		{
+			name:      "within block",
+			now:       time.Date(2025, 1, 1, 14, 59, 0, 0, time.UTC),
+			grace:     5 * time.Minute,
+			wantStart: blockStart,
+		},
+		{
+			name:        "at block end keeps block during grace",
+			now:         time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC),
+			grace:       5 * time.Minute,
+			wantStart:   blockStart,
+			wantInGrace: true,
+		},
+		{
+			name:        "within grace",
+			now:         time.Date(2025, 1, 1, 15, 4, 59, 0, time.UTC),
+			grace:       5 * time.Minute,
+			wantStart:   blockStart,
+			wantInGrace: true,
+		},
+		{
+			name:      "grace passed advances to next block",
+			now:       time.Date(2025, 1, 1, 15, 5, 0, 0, time.UTC),
+			grace:     5 * time.Minute,
+			wantStart: time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "zero grace advances at block end",
+			now:       time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "far past advances multiple blocks",
+			now:       time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC),
+			grace:     5 * time.Minute,
+			wantStart: time.Date(2025, 1, 2, 6, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := NewBlockWithLimit(blockStart, 7000).WithTokenMetric(BlockTokenMetricTotal)
+
+			next := block.NextBlockAfterGrace(tt.now, tt.grace)
+			if !next.StartAt().Equal(tt.wantStart) {
+				t.Errorf("NextBlockAfterGrace() start = %v, want %v", next.StartAt(), tt.wantStart)
+			}
+			if next.TokenLimit() != 7000 || next.TokenMetric() != BlockTokenMetricTotal {
+				t.Errorf("NextBlockAfterGrace() lost limit or metric: %d, %s", next.TokenLimit(), next.TokenMetric())
+			}
+
+			if got := block.InGracePeriod(tt.now, tt.grace); got != tt.wantInGrace {
+				t.Errorf("InGracePeriod() = %v, want %v", got, tt.wantInGrace)
+			}
+		})
+	}
+}
+
+func TestBlock_PreviousBlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		blockStart time.Time
+		tokenLimit int
+		wantStart  time.Time
+	}{
+		{
+			name:       "previous block within the same day",
+			blockStart: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+			tokenLimit: 7000,
+			wantStart:  time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "previous block crosses midnight",
+			blockStart: time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+			tokenLimit: 0,
+			wantStart:  time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := NewBlockWithLimit(tt.blockStart, tt.tokenLimit)
+			previous := block.PreviousBlock()
+
+			if !previous.StartAt().Equal(tt.wantStart) {
+				t.Errorf("PreviousBlock() start = %v, want %v", previous.StartAt(), tt.wantStart)
+			}
+			if !previous.EndAt().Equal(block.StartAt()) {
+				t.Errorf("PreviousBlock() end = %v, want %v", previous.EndAt(), block.StartAt())
+			}
+			if previous.TokenLimit() != tt.tokenLimit {
+				t.Errorf("PreviousBlock() token limit = %d, want %d", previous.TokenLimit(), tt.tokenLimit)
+			}
+		})
+	}
+}
+
+func TestBlock_TokenMetric(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	premiumTokens := NewToken(2000, 1500, 2800, 700)
+
+	tests := []struct {
+		name         string
+		metric       BlockTokenMetric
+		wantMetric   BlockTokenMetric
+		wantUsed     int64
+		wantProgress float64
+	}{
+		{
+			name:         "unset counts limited tokens",
+			metric:       "",
+			wantMetric:   BlockTokenMetricLimited,
+			wantUsed:     3500,
+			wantProgress: 50,
+		},
+		{
+			name:         "limited counts input and output tokens",
+			metric:       BlockTokenMetricLimited,
+			wantMetric:   BlockTokenMetricLimited,
+			wantUsed:     3500,
+			wantProgress: 50,
+		},
+		{
+			name:         "total counts cache tokens too",
+			metric:       BlockTokenMetricTotal,
+			wantMetric:   BlockTokenMetricTotal,
+			wantUsed:     7000,
+			wantProgress: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := NewBlockWithLimit(start, 7000).WithTokenMetric(tt.metric)
+
+			if block.TokenMetric() != tt.wantMetric {
+				t.Errorf("TokenMetric() = %q, want %q", block.TokenMetric(), tt.wantMetric)
+			}
+			if used := block.UsedTokens(premiumTokens); used != tt.wantUsed {
+				t.Errorf("UsedTokens() = %d, want %d", used, tt.wantUsed)
+			}
+			if progress := block.CalculateProgress(premiumTokens); progress != tt.wantProgress {
+				t.Errorf("CalculateProgress() = %v, want %v", progress, tt.wantProgress)
+			}
+			if block.IsLimitExceeded(premiumTokens) {
+				t.Errorf("IsLimitExceeded() = true at exactly the limit")
+			}
+			// The metric carries over to the following and preceding blocks
+			if next := block.NextBlock(start.Add(6 * time.Hour)); next.TokenMetric() != tt.wantMetric {
+				t.Errorf("NextBlock().TokenMetric() = %q, want %q", next.TokenMetric(), tt.wantMetric)
+			}
+			if previous := block.PreviousBlock(); previous.TokenMetric() != tt.wantMetric {
+				t.Errorf("PreviousBlock().TokenMetric() = %q, want %q", previous.TokenMetric(), tt.wantMetric)
+			}
+		})
+	}
+}
+
+func TestParseBlockTokenMetric(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    BlockTokenMetric
+		wantErr bool
+	}{
+		{value: "", want: BlockTokenMetricLimited},
+		{value: "limited", want: BlockTokenMetricLimited},
+		{value: "total", want: BlockTokenMetricTotal},
+		{value: "cache", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseBlockTokenMetric(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBlockTokenMetric(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBlockTokenMetric(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestBlock_ValueObjectBehavior(t *testing.T) {
 	loc, _ := time.LoadLocation("UTC")
 
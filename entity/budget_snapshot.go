@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+// BudgetSnapshot is the monthly plan usage at a point in time
+type BudgetSnapshot struct {
+	plan    Plan
+	cost    Cost
+	period  Period
+	takenAt time.Time
+}
+
+// NewBudgetSnapshot creates a snapshot of the cost spent in the month against the plan
+func NewBudgetSnapshot(plan Plan, cost Cost, period Period, takenAt time.Time) BudgetSnapshot {
+	return BudgetSnapshot{
+		plan:    plan,
+		cost:    cost,
+		period:  period,
+		takenAt: takenAt,
+	}
+}
+
+// PlanName returns the name of the plan the usage is measured against
+func (s BudgetSnapshot) PlanName() string {
+	return s.plan.Name()
+}
+
+// Cost returns the cost spent in the month
+func (s BudgetSnapshot) Cost() Cost {
+	return s.cost
+}
+
+// Budget returns the monthly plan price, zero when no plan is configured
+func (s BudgetSnapshot) Budget() Cost {
+	if !s.plan.IsValid() {
+		return NewCost(0)
+	}
+	return s.plan.Price()
+}
+
+// Usage returns the percentage of the monthly budget used, 0 without a plan
+func (s BudgetSnapshot) Usage() int {
+	return s.plan.CalculateUsagePercentage(s.cost)
+}
+
+// Period returns the month the cost was spent in
+func (s BudgetSnapshot) Period() Period {
+	return s.period
+}
+
+// TakenAt returns when the snapshot was taken
+func (s BudgetSnapshot) TakenAt() time.Time {
+	return s.takenAt
+}
+
+// IsExceeded returns true if a monthly budget is configured and the usage reached 100%
+func (s BudgetSnapshot) IsExceeded() bool {
+	return s.Budget().Amount() > 0 && s.Usage() >= 100
+}
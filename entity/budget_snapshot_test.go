@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetSnapshot_IsExceeded(t *testing.T) {
+	t.Parallel()
+
+	month := NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC))
+	takenAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		plan          Plan
+		cost          float64
+		expectedUsage int
+		expected      bool
+	}{
+		{name: "below the budget", plan: NewPlan("pro", NewCost(20)), cost: 19.99, expectedUsage: 99},
+		{name: "exactly the budget", plan: NewPlan("pro", NewCost(20)), cost: 20, expectedUsage: 100, expected: true},
+		{name: "over the budget", plan: NewPlan("max", NewCost(100)), cost: 150, expectedUsage: 150, expected: true},
+		{name: "unset plan has no budget", plan: NewPlan("unset", NewCost(0)), cost: 500},
+		{name: "unknown plan has no budget", plan: NewPlan("enterprise", NewCost(20)), cost: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			snapshot := NewBudgetSnapshot(tt.plan, NewCost(tt.cost), month, takenAt)
+			if snapshot.Usage() != tt.expectedUsage {
+				t.Errorf("Usage() = %d, want %d", snapshot.Usage(), tt.expectedUsage)
+			}
+			if snapshot.IsExceeded() != tt.expected {
+				t.Errorf("IsExceeded() = %v, want %v", snapshot.IsExceeded(), tt.expected)
+			}
+		})
+	}
+}
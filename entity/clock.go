@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Clock provides the current time, so "now" can be frozen in tests and previews
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the wall clock
+type SystemClock struct{}
+
+// Now returns the current wall clock time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always reports the same instant
+type FixedClock struct {
+	at time.Time
+}
+
+// NewFixedClock creates a clock frozen at the given time
+func NewFixedClock(at time.Time) FixedClock {
+	return FixedClock{at: at}
+}
+
+// Now returns the frozen time
+func (c FixedClock) Now() time.Time {
+	return c.at
+}
+
+// clockTimeLayouts are the accepted formats for ParseClockTime, most specific first
+var clockTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseClockTime parses an RFC 3339 timestamp, or a date with optional time in the given timezone
+func ParseClockTime(value string, timezone *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if timezone == nil {
+		timezone = time.UTC
+	}
+	for _, layout := range clockTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, timezone); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q (expected RFC 3339, YYYY-MM-DD or YYYY-MM-DD HH:MM)", value)
+}
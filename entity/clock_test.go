@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClockTime(t *testing.T) {
+	t.Parallel()
+
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+
+	tests := []struct {
+		name        string
+		value       string
+		timezone    *time.Location
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "RFC 3339 keeps its own offset",
+			value:    "2025-07-01T18:00:00Z",
+			timezone: taipei,
+			expected: time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "date and time in the given timezone",
+			value:    "2025-07-01 18:00",
+			timezone: taipei,
+			expected: time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "date with seconds",
+			value:    "2025-07-01 18:00:30",
+			timezone: time.UTC,
+			expected: time.Date(2025, 7, 1, 18, 0, 30, 0, time.UTC),
+		},
+		{
+			name:     "date only starts at midnight",
+			value:    " 2025-07-01 ",
+			timezone: taipei,
+			expected: time.Date(2025, 6, 30, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "nil timezone uses UTC",
+			value:    "2025-07-01",
+			expected: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "invalid value",
+			value:       "tomorrow",
+			timezone:    time.UTC,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseClockTime(tt.value, tt.timezone)
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("ParseClockTime(%q) expected an error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseClockTime(%q) error = %v", tt.value, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseClockTime(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFixedClock_Now(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC)
+	clock := NewFixedClock(at)
+
+	if !clock.Now().Equal(at) || !clock.Now().Equal(clock.Now()) {
+		t.Errorf("Expected the clock to stay at %v, got %v", at, clock.Now())
+	}
+}
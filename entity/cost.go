@@ -19,3 +19,37 @@ func (c Cost) Amount() float64 {
 func (c Cost) Add(other Cost) Cost {
 	return Cost{amount: c.amount + other.amount}
 }
+
+// ShareOf returns the cost as a percentage of total, 0 when total has no cost
+func (c Cost) ShareOf(total Cost) float64 {
+	if total.amount <= 0 {
+		return 0
+	}
+	return c.amount / total.amount * 100
+}
+
+// CostStyle controls how precisely a cost is rendered for display
+type CostStyle int
+
+const (
+	CostStyleDefault CostStyle = iota // One decimal place: $15.0
+	CostStyleCompact                  // Whole dollars: $15
+	CostStyleFull                     // Cents: $15.03
+)
+
+// Format renders the cost in dollars using the given style
+func (c Cost) Format(style CostStyle) string {
+	return c.FormatLocale(style, DefaultNumberLocale)
+}
+
+// FormatLocale renders the cost in dollars using the given style and number separators
+func (c Cost) FormatLocale(style CostStyle, locale NumberLocale) string {
+	switch style {
+	case CostStyleCompact:
+		return "$" + locale.FormatFloat(c.amount, 0)
+	case CostStyleFull:
+		return "$" + locale.FormatFloat(c.amount, 2)
+	default:
+		return "$" + locale.FormatFloat(c.amount, 1)
+	}
+}
@@ -0,0 +1,90 @@
+package entity
+
+import "testing"
+
+func TestCost_Format(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		amount   float64
+		style    CostStyle
+		expected string
+	}{
+		{name: "default keeps one decimal", amount: 15.03, style: CostStyleDefault, expected: "$15.0"},
+		{name: "compact rounds to whole dollars", amount: 15.03, style: CostStyleCompact, expected: "$15"},
+		{name: "compact rounds up", amount: 15.6, style: CostStyleCompact, expected: "$16"},
+		{name: "full keeps cents", amount: 15.03, style: CostStyleFull, expected: "$15.03"},
+		{name: "zero cost", amount: 0, style: CostStyleFull, expected: "$0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NewCost(tt.amount).Format(tt.style); got != tt.expected {
+				t.Errorf("Format() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCost_FormatLocale(t *testing.T) {
+	t.Parallel()
+
+	germany, err := ParseNumberLocale("de-DE")
+	if err != nil {
+		t.Fatalf("ParseNumberLocale() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		amount   float64
+		style    CostStyle
+		locale   NumberLocale
+		expected string
+	}{
+		{name: "default locale matches Format", amount: 1234.56, style: CostStyleDefault, locale: DefaultNumberLocale, expected: "$1234.6"},
+		{name: "comma decimal separator", amount: 15.03, style: CostStyleDefault, locale: germany, expected: "$15,0"},
+		{name: "comma decimal with cents", amount: 15.03, style: CostStyleFull, locale: germany, expected: "$15,03"},
+		{name: "grouping", amount: 1234.56, style: CostStyleFull, locale: germany.WithGrouping(true), expected: "$1.234,56"},
+		{name: "compact with grouping", amount: 1234567, style: CostStyleCompact, locale: DefaultNumberLocale.WithGrouping(true), expected: "$1,234,567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NewCost(tt.amount).FormatLocale(tt.style, tt.locale); got != tt.expected {
+				t.Errorf("FormatLocale() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCost_ShareOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		amount   float64
+		total    float64
+		expected float64
+	}{
+		{name: "part of the total", amount: 0.4, total: 1.0, expected: 40},
+		{name: "whole total", amount: 2.5, total: 2.5, expected: 100},
+		{name: "free request", amount: 0, total: 1.0, expected: 0},
+		{name: "zero total", amount: 0.4, total: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := NewCost(tt.amount).ShareOf(NewCost(tt.total))
+			if got < tt.expected-0.0001 || got > tt.expected+0.0001 {
+				t.Errorf("ShareOf() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
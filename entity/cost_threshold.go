@@ -0,0 +1,61 @@
+package entity
+
+import "sort"
+
+// FilterByMinCost returns the requests whose cost is at least minCost, along
+// with the number of requests that were hidden. A non-positive threshold keeps
+// every request. This is a view filter and never affects aggregate stats.
+func FilterByMinCost(requests []APIRequest, minCost Cost) ([]APIRequest, int) {
+	if minCost.Amount() <= 0 {
+		return requests, 0
+	}
+
+	kept := make([]APIRequest, 0, len(requests))
+	for _, req := range requests {
+		if req.Cost().Amount() >= minCost.Amount() {
+			kept = append(kept, req)
+		}
+	}
+
+	return kept, len(requests) - len(kept)
+}
+
+// SplitNegligible separates the requests cheaper than the threshold from the
+// rest, keeping their order. A non-positive threshold marks nothing as negligible.
+func SplitNegligible(requests []APIRequest, threshold Cost) ([]APIRequest, []APIRequest) {
+	if threshold.Amount() <= 0 {
+		return requests, nil
+	}
+
+	var kept, negligible []APIRequest
+	for _, req := range requests {
+		if req.Cost().Amount() < threshold.Amount() {
+			negligible = append(negligible, req)
+		} else {
+			kept = append(kept, req)
+		}
+	}
+
+	return kept, negligible
+}
+
+// CumulativeCosts returns the running total of cost at each request, summed in
+// timestamp order regardless of how the requests are sorted. Requests with the
+// same timestamp keep their relative order.
+func CumulativeCosts(requests []APIRequest) []Cost {
+	order := make([]int, len(requests))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return requests[order[a]].Timestamp().Before(requests[order[b]].Timestamp())
+	})
+
+	totals := make([]Cost, len(requests))
+	running := NewCost(0)
+	for _, i := range order {
+		running = running.Add(requests[i].Cost())
+		totals[i] = running
+	}
+	return totals
+}
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFilterByMinCost(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(cost float64) APIRequest {
+		return NewAPIRequest("session", now, "claude-3-sonnet", NewToken(100, 50, 0, 0), NewCost(cost), 1000)
+	}
+	requests := []APIRequest{newRequest(0), newRequest(0.001), newRequest(0.01), newRequest(0.5)}
+
+	tests := []struct {
+		name       string
+		minCost    float64
+		wantKept   int
+		wantHidden int
+	}{
+		{name: "zero threshold keeps everything", minCost: 0, wantKept: 4, wantHidden: 0},
+		{name: "negative threshold keeps everything", minCost: -1, wantKept: 4, wantHidden: 0},
+		{name: "threshold is inclusive", minCost: 0.01, wantKept: 2, wantHidden: 2},
+		{name: "threshold above all costs", minCost: 1, wantKept: 0, wantHidden: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			kept, hidden := FilterByMinCost(requests, NewCost(tt.minCost))
+			if len(kept) != tt.wantKept {
+				t.Errorf("FilterByMinCost() kept %d, want %d", len(kept), tt.wantKept)
+			}
+			if hidden != tt.wantHidden {
+				t.Errorf("FilterByMinCost() hidden %d, want %d", hidden, tt.wantHidden)
+			}
+		})
+	}
+}
+
+func TestSplitNegligible(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(cost float64) APIRequest {
+		return NewAPIRequest("session", now, "claude-3-sonnet", NewToken(100, 50, 0, 0), NewCost(cost), 1000)
+	}
+	requests := []APIRequest{newRequest(0), newRequest(0.001), newRequest(0.01), newRequest(0.5)}
+
+	tests := []struct {
+		name           string
+		threshold      float64
+		wantKept       int
+		wantNegligible int
+	}{
+		{name: "zero threshold marks nothing", threshold: 0, wantKept: 4, wantNegligible: 0},
+		{name: "negative threshold marks nothing", threshold: -1, wantKept: 4, wantNegligible: 0},
+		{name: "threshold itself is kept", threshold: 0.01, wantKept: 2, wantNegligible: 2},
+		{name: "threshold above all costs", threshold: 1, wantKept: 0, wantNegligible: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			kept, negligible := SplitNegligible(requests, NewCost(tt.threshold))
+			if len(kept) != tt.wantKept {
+				t.Errorf("SplitNegligible() kept %d, want %d", len(kept), tt.wantKept)
+			}
+			if len(negligible) != tt.wantNegligible {
+				t.Errorf("SplitNegligible() negligible %d, want %d", len(negligible), tt.wantNegligible)
+			}
+		})
+	}
+}
+
+func TestCumulativeCosts(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(minutes int, cost float64) APIRequest {
+		return NewAPIRequest("session", start.Add(time.Duration(minutes)*time.Minute), "claude-3-sonnet", NewToken(100, 50, 0, 0), NewCost(cost), 1000)
+	}
+
+	tests := []struct {
+		name     string
+		requests []APIRequest
+		want     []float64
+	}{
+		{name: "no requests", requests: nil, want: []float64{}},
+		{
+			name:     "oldest first",
+			requests: []APIRequest{newRequest(0, 0.10), newRequest(1, 0.25), newRequest(2, 0.05)},
+			want:     []float64{0.10, 0.35, 0.40},
+		},
+		{
+			name:     "newest first sums from the bottom",
+			requests: []APIRequest{newRequest(2, 0.05), newRequest(1, 0.25), newRequest(0, 0.10)},
+			want:     []float64{0.40, 0.35, 0.10},
+		},
+		{
+			name:     "same timestamp keeps list order",
+			requests: []APIRequest{newRequest(0, 0.10), newRequest(0, 0.20)},
+			want:     []float64{0.10, 0.30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := CumulativeCosts(tt.requests)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CumulativeCosts() returned %d costs, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if math.Abs(got[i].Amount()-want) > 1e-9 {
+					t.Errorf("CumulativeCosts()[%d] = %v, want %v", i, got[i].Amount(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DurationRange matches API requests by duration. The minimum is inclusive and the maximum
+// exclusive, so adjacent ranges such as [0, 30s) and [30s, ∞) never overlap. A zero bound is open.
+type DurationRange struct {
+	min time.Duration
+	max time.Duration
+}
+
+// NewDurationRange creates a duration range, use 0 for an open bound
+func NewDurationRange(min, max time.Duration) (DurationRange, error) {
+	if min < 0 || max < 0 {
+		return DurationRange{}, fmt.Errorf("duration bounds must not be negative, got min %s and max %s", min, max)
+	}
+	if max > 0 && min >= max {
+		return DurationRange{}, fmt.Errorf("minimum duration %s must be below the maximum %s", min, max)
+	}
+
+	return DurationRange{min: min, max: max}, nil
+}
+
+// ParseDurationRange parses bounds such as "30s" or "1m30s", an empty bound is open
+func ParseDurationRange(min, max string) (DurationRange, error) {
+	parse := func(name, value string) (time.Duration, error) {
+		if strings.TrimSpace(value) == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s duration %q: %w", name, value, err)
+		}
+		return d, nil
+	}
+
+	minDuration, err := parse("minimum", min)
+	if err != nil {
+		return DurationRange{}, err
+	}
+	maxDuration, err := parse("maximum", max)
+	if err != nil {
+		return DurationRange{}, err
+	}
+
+	return NewDurationRange(minDuration, maxDuration)
+}
+
+// Min returns the inclusive lower bound, 0 when open
+func (r DurationRange) Min() time.Duration {
+	return r.min
+}
+
+// Max returns the exclusive upper bound, 0 when open
+func (r DurationRange) Max() time.Duration {
+	return r.max
+}
+
+// IsEmpty returns true if both bounds are open and every request matches
+func (r DurationRange) IsEmpty() bool {
+	return r.min == 0 && r.max == 0
+}
+
+// Matches returns true if the request duration is at least the minimum and below the maximum
+func (r DurationRange) Matches(req APIRequest) bool {
+	duration := time.Duration(req.DurationMS()) * time.Millisecond
+	if duration < r.min {
+		return false
+	}
+	return r.max == 0 || duration < r.max
+}
+
+// String returns the range as e.g. "≥30s", "<1m0s" or "30s-1m0s"
+func (r DurationRange) String() string {
+	switch {
+	case r.min > 0 && r.max > 0:
+		return r.min.String() + "-" + r.max.String()
+	case r.min > 0:
+		return "≥" + r.min.String()
+	case r.max > 0:
+		return "<" + r.max.String()
+	default:
+		return "any"
+	}
+}
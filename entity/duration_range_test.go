@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDurationRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		min         string
+		max         string
+		want        string
+		expectError bool
+	}{
+		{name: "both bounds empty", want: "any"},
+		{name: "minimum only", min: "30s", want: "≥30s"},
+		{name: "maximum only", max: "1m", want: "<1m0s"},
+		{name: "both bounds", min: " 10s ", max: "1m", want: "10s-1m0s"},
+		{name: "invalid minimum", min: "fast", expectError: true},
+		{name: "invalid maximum", max: "10", expectError: true},
+		{name: "negative bound", min: "-1s", expectError: true},
+		{name: "minimum equal to maximum", min: "30s", max: "30s", expectError: true},
+		{name: "minimum above maximum", min: "1m", max: "30s", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDurationRange(tt.min, tt.max)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseDurationRange(%q, %q) expected error, got %s", tt.min, tt.max, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDurationRange(%q, %q) error = %v", tt.min, tt.max, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseDurationRange(%q, %q) = %q, want %q", tt.min, tt.max, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationRange_Matches(t *testing.T) {
+	t.Parallel()
+
+	requestTaking := func(durationMS int64) APIRequest {
+		return NewAPIRequest("session-1", time.Now(), "claude-3-5-sonnet-20241022", NewToken(10, 5, 0, 0), NewCost(0.01), durationMS)
+	}
+	mustRange := func(min, max time.Duration) DurationRange {
+		r, err := NewDurationRange(min, max)
+		if err != nil {
+			t.Fatalf("NewDurationRange(%s, %s) error = %v", min, max, err)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		r          DurationRange
+		durationMS int64
+		expected   bool
+	}{
+		{name: "empty range matches everything", r: DurationRange{}, durationMS: 0, expected: true},
+		{name: "minimum is inclusive", r: mustRange(30*time.Second, 0), durationMS: 30_000, expected: true},
+		{name: "below the minimum", r: mustRange(30*time.Second, 0), durationMS: 29_999, expected: false},
+		{name: "maximum is exclusive", r: mustRange(0, 30*time.Second), durationMS: 30_000, expected: false},
+		{name: "just below the maximum", r: mustRange(0, 30*time.Second), durationMS: 29_999, expected: true},
+		{name: "inside both bounds", r: mustRange(10*time.Second, time.Minute), durationMS: 45_000, expected: true},
+		{name: "lower edge of both bounds", r: mustRange(10*time.Second, time.Minute), durationMS: 10_000, expected: true},
+		{name: "upper edge of both bounds", r: mustRange(10*time.Second, time.Minute), durationMS: 60_000, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.r.Matches(requestTaking(tt.durationMS)); got != tt.expected {
+				t.Errorf("%s.Matches(%dms) = %v, want %v", tt.r, tt.durationMS, got, tt.expected)
+			}
+		})
+	}
+}
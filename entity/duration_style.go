@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// DurationStyle controls how durations such as block time remaining are rendered
+type DurationStyle string
+
+const (
+	DurationStyleDefault DurationStyle = "default" // 2h 15m
+	DurationStyleCompact DurationStyle = "compact" // 2h15m
+	DurationStyleClock   DurationStyle = "clock"   // 2:15:00
+)
+
+// ParseDurationStyle converts a configuration value into a DurationStyle
+func ParseDurationStyle(value string) (DurationStyle, error) {
+	switch DurationStyle(value) {
+	case "", DurationStyleDefault:
+		return DurationStyleDefault, nil
+	case DurationStyleCompact:
+		return DurationStyleCompact, nil
+	case DurationStyleClock:
+		return DurationStyleClock, nil
+	default:
+		return "", fmt.Errorf("unknown duration format %q (must be one of: default, compact, clock)", value)
+	}
+}
+
+// Format renders a duration in the style; negative durations render as zero.
+// The default style keeps counting hours past a day (e.g., 26h 5m), only compact splits out days.
+func (s DurationStyle) Format(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	totalSeconds := int(d.Seconds())
+	hours := totalSeconds / 3600
+	minutes := (totalSeconds / 60) % 60
+	seconds := totalSeconds % 60
+
+	switch s {
+	case DurationStyleClock:
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	case DurationStyleCompact:
+		if d < time.Minute {
+			return fmt.Sprintf("%ds", seconds)
+		} else if d < time.Hour {
+			return fmt.Sprintf("%dm%ds", minutes, seconds)
+		} else if d < 24*time.Hour {
+			return fmt.Sprintf("%dh%dm", hours, minutes)
+		}
+		return fmt.Sprintf("%dd%dh%dm", hours/24, hours%24, minutes)
+	default:
+		if d < time.Minute {
+			return fmt.Sprintf("%ds", seconds)
+		} else if d < time.Hour {
+			return fmt.Sprintf("%dm %ds", minutes, seconds)
+		}
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationStyle_Format(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		style    DurationStyle
+		want     string
+	}{
+		{name: "default sub-minute", duration: 45 * time.Second, style: DurationStyleDefault, want: "45s"},
+		{name: "default minutes", duration: 5*time.Minute + 3*time.Second, style: DurationStyleDefault, want: "5m 3s"},
+		{name: "default hours", duration: 2*time.Hour + 15*time.Minute, style: DurationStyleDefault, want: "2h 15m"},
+		{name: "default over a day", duration: 26*time.Hour + 5*time.Minute, style: DurationStyleDefault, want: "26h 5m"},
+		{name: "compact sub-minute", duration: 45 * time.Second, style: DurationStyleCompact, want: "45s"},
+		{name: "compact minutes", duration: 5*time.Minute + 3*time.Second, style: DurationStyleCompact, want: "5m3s"},
+		{name: "compact hours", duration: 2*time.Hour + 15*time.Minute, style: DurationStyleCompact, want: "2h15m"},
+		{name: "compact over a day", duration: 26*time.Hour + 5*time.Minute, style: DurationStyleCompact, want: "1d2h5m"},
+		{name: "clock sub-minute", duration: 45 * time.Second, style: DurationStyleClock, want: "0:00:45"},
+		{name: "clock hours", duration: 2*time.Hour + 15*time.Minute, style: DurationStyleClock, want: "2:15:00"},
+		{name: "clock over a day", duration: 26*time.Hour + 5*time.Minute, style: DurationStyleClock, want: "26:05:00"},
+		{name: "empty style uses default", duration: 26*time.Hour + 5*time.Minute, style: "", want: "26h 5m"},
+		{name: "negative duration clamps to zero", duration: -time.Minute, style: DurationStyleDefault, want: "0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.style.Format(tt.duration); got != tt.want {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDurationStyle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    DurationStyle
+		wantErr bool
+	}{
+		{name: "empty uses default", value: "", want: DurationStyleDefault},
+		{name: "default", value: "default", want: DurationStyleDefault},
+		{name: "compact", value: "compact", want: DurationStyleCompact},
+		{name: "clock", value: "clock", want: DurationStyleClock},
+		{name: "unknown", value: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDurationStyle(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseDurationStyle() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDurationStyle() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDurationStyle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
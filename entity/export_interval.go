@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExportInterval is the calendar period each scheduled export covers
+type ExportInterval string
+
+const (
+	ExportIntervalDay   ExportInterval = "day"
+	ExportIntervalMonth ExportInterval = "month"
+)
+
+// ParseExportInterval validates an export interval name
+func ParseExportInterval(value string) (ExportInterval, error) {
+	switch ExportInterval(strings.ToLower(strings.TrimSpace(value))) {
+	case ExportIntervalDay:
+		return ExportIntervalDay, nil
+	case ExportIntervalMonth:
+		return ExportIntervalMonth, nil
+	default:
+		return "", fmt.Errorf("invalid export interval %q (expected day or month)", value)
+	}
+}
+
+// Previous returns the last completed day or month before now, following the calendar of the timezone
+func (i ExportInterval) Previous(now time.Time, timezone *time.Location) Period {
+	local := now.In(timezone)
+	end := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, timezone)
+	start := end.AddDate(0, 0, -1)
+	if i == ExportIntervalMonth {
+		end = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, timezone)
+		start = end.AddDate(0, -1, 0)
+	}
+
+	return NewPeriod(start.UTC(), end.Add(-time.Nanosecond).UTC())
+}
+
+// Stamp returns the date naming the export of the period starting at start, e.g. 2025-07-01 or 2025-07
+func (i ExportInterval) Stamp(start time.Time, timezone *time.Location) string {
+	if i == ExportIntervalMonth {
+		return start.In(timezone).Format("2006-01")
+	}
+	return start.In(timezone).Format("2006-01-02")
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExportInterval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    ExportInterval
+		wantErr bool
+	}{
+		{value: "day", want: ExportIntervalDay},
+		{value: " Month ", want: ExportIntervalMonth},
+		{value: "week", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseExportInterval(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseExportInterval(%q) expected error, got %q", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseExportInterval(%q) unexpected error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseExportInterval(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportInterval_Previous(t *testing.T) {
+	t.Parallel()
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name      string
+		interval  ExportInterval
+		now       time.Time
+		timezone  *time.Location
+		wantStart time.Time
+		wantStamp string
+	}{
+		{
+			name:      "previous day",
+			interval:  ExportIntervalDay,
+			now:       time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC),
+			timezone:  time.UTC,
+			wantStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			wantStamp: "2025-07-01",
+		},
+		{
+			name:      "previous day follows the timezone",
+			interval:  ExportIntervalDay,
+			now:       time.Date(2025, 7, 1, 20, 0, 0, 0, time.UTC), // July 2nd in Tokyo
+			timezone:  tokyo,
+			wantStart: time.Date(2025, 6, 30, 15, 0, 0, 0, time.UTC),
+			wantStamp: "2025-07-01",
+		},
+		{
+			name:      "previous month across the year",
+			interval:  ExportIntervalMonth,
+			now:       time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			timezone:  time.UTC,
+			wantStart: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			wantStamp: "2024-12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			period := tt.interval.Previous(tt.now, tt.timezone)
+			if !period.StartAt().Equal(tt.wantStart) {
+				t.Errorf("expected start %v, got %v", tt.wantStart, period.StartAt())
+			}
+			wantEnd := tt.wantStart.In(tt.timezone).AddDate(0, 0, 1)
+			if tt.interval == ExportIntervalMonth {
+				wantEnd = tt.wantStart.In(tt.timezone).AddDate(0, 1, 0)
+			}
+			if !period.EndAt().Equal(wantEnd.Add(-time.Nanosecond)) {
+				t.Errorf("expected end just before %v, got %v", wantEnd, period.EndAt())
+			}
+			if stamp := tt.interval.Stamp(period.StartAt(), tt.timezone); stamp != tt.wantStamp {
+				t.Errorf("expected stamp %q, got %q", tt.wantStamp, stamp)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NoProjectGroup is the group of requests without the project label
+const NoProjectGroup = "(none)"
+
+// NegligibleGroup is the group of requests below the negligible cost threshold
+const NegligibleGroup = "(negligible)"
+
+// GroupBy is the dimension requests are grouped by when aggregating stats per group
+type GroupBy string
+
+const (
+	GroupByModel   GroupBy = "model"
+	GroupBySession GroupBy = "session"
+	GroupByDay     GroupBy = "day"
+	GroupByProject GroupBy = "project" // Value of the configured project label
+)
+
+// ParseGroupBy validates a grouping dimension
+func ParseGroupBy(value string) (GroupBy, error) {
+	switch GroupBy(strings.ToLower(strings.TrimSpace(value))) {
+	case GroupByModel:
+		return GroupByModel, nil
+	case GroupBySession:
+		return GroupBySession, nil
+	case GroupByDay:
+		return GroupByDay, nil
+	case GroupByProject:
+		return GroupByProject, nil
+	default:
+		return "", fmt.Errorf("invalid group %q (expected model, session, day or project)", value)
+	}
+}
+
+// Key returns the group of the request: its model, its session ID ("unknown" when empty),
+// its local date in the timezone, or the value of the project label ("(none)" without it)
+func (g GroupBy) Key(req APIRequest, timezone *time.Location, projectLabel string) string {
+	switch g {
+	case GroupBySession:
+		if req.SessionID() == "" {
+			return UnknownSessionID
+		}
+		return req.SessionID()
+	case GroupByDay:
+		return req.Timestamp().In(timezone).Format("2006-01-02")
+	case GroupByProject:
+		if value, ok := req.Label(projectLabel); ok && value != "" {
+			return value
+		}
+		return NoProjectGroup
+	default:
+		return req.Model().String()
+	}
+}
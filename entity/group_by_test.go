@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected GroupBy
+		wantErr  bool
+	}{
+		{input: "model", expected: GroupByModel},
+		{input: " Session ", expected: GroupBySession},
+		{input: "DAY", expected: GroupByDay},
+		{input: "project", expected: GroupByProject},
+		{input: "user", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseGroupBy(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseGroupBy(%q) expected an error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGroupBy(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseGroupBy(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGroupBy_Key(t *testing.T) {
+	t.Parallel()
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	timestamp := time.Date(2025, 1, 6, 20, 0, 0, 0, time.UTC) // Already Jan 7 in Tokyo
+	labeled := NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.5), 1000).
+		WithLabels(map[string]string{"project": "ccmon"})
+	unlabeled := NewAPIRequest("", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.5), 1000)
+
+	tests := []struct {
+		name     string
+		groupBy  GroupBy
+		request  APIRequest
+		expected string
+	}{
+		{name: "model", groupBy: GroupByModel, request: labeled, expected: "claude-sonnet-4-20250514"},
+		{name: "session", groupBy: GroupBySession, request: labeled, expected: "session-1"},
+		{name: "unknown session", groupBy: GroupBySession, request: unlabeled, expected: UnknownSessionID},
+		{name: "day in timezone", groupBy: GroupByDay, request: labeled, expected: "2025-01-07"},
+		{name: "project", groupBy: GroupByProject, request: labeled, expected: "ccmon"},
+		{name: "no project", groupBy: GroupByProject, request: unlabeled, expected: NoProjectGroup},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.groupBy.Key(tt.request, tokyo, "project"); got != tt.expected {
+				t.Errorf("Key() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LabelFilter matches API requests carrying a label with the given value
+type LabelFilter struct {
+	key   string
+	value string
+}
+
+// NewLabelFilter creates a new label filter
+func NewLabelFilter(key, value string) LabelFilter {
+	return LabelFilter{
+		key:   key,
+		value: value,
+	}
+}
+
+// ParseLabelFilter parses a "key=value" expression into a label filter
+func ParseLabelFilter(expr string) (LabelFilter, error) {
+	key, value, ok := strings.Cut(expr, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return LabelFilter{}, fmt.Errorf("invalid label filter %q: expected key=value", expr)
+	}
+
+	return NewLabelFilter(key, strings.TrimSpace(value)), nil
+}
+
+// ParseLabelFilters parses a comma-separated list of "key=value" expressions
+func ParseLabelFilters(expr string) ([]LabelFilter, error) {
+	var filters []LabelFilter
+	for _, part := range strings.Split(expr, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		filter, err := ParseLabelFilter(part)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// Key returns the label key
+func (f LabelFilter) Key() string {
+	return f.key
+}
+
+// Value returns the expected label value
+func (f LabelFilter) Value() string {
+	return f.value
+}
+
+// String returns the filter as a "key=value" expression
+func (f LabelFilter) String() string {
+	return f.key + "=" + f.value
+}
+
+// Matches returns true if the request carries the label with the expected value.
+// Requests without the label never match.
+func (f LabelFilter) Matches(req APIRequest) bool {
+	value, ok := req.Label(f.key)
+	return ok && value == f.value
+}
+
+// MatchesLabelFilters returns true if the request matches all the given filters
+func MatchesLabelFilters(req APIRequest, filters []LabelFilter) bool {
+	for _, filter := range filters {
+		if !filter.Matches(req) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLabelFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		expr        string
+		want        []string
+		expectError bool
+	}{
+		{
+			name: "single filter",
+			expr: "environment=prod",
+			want: []string{"environment=prod"},
+		},
+		{
+			name: "multiple filters with spaces",
+			expr: "environment = prod, user.id=alice",
+			want: []string{"environment=prod", "user.id=alice"},
+		},
+		{
+			name: "empty value",
+			expr: "environment=",
+			want: []string{"environment="},
+		},
+		{
+			name: "empty expression",
+			expr: "",
+			want: nil,
+		},
+		{
+			name:        "missing separator",
+			expr:        "environment",
+			expectError: true,
+		},
+		{
+			name:        "missing key",
+			expr:        "=prod",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filters, err := ParseLabelFilters(tt.expr)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseLabelFilters(%q) expected error", tt.expr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLabelFilters(%q) unexpected error: %v", tt.expr, err)
+			}
+
+			if len(filters) != len(tt.want) {
+				t.Fatalf("ParseLabelFilters(%q) returned %d filters, want %d", tt.expr, len(filters), len(tt.want))
+			}
+			for i, filter := range filters {
+				if filter.String() != tt.want[i] {
+					t.Errorf("filter[%d] = %q, want %q", i, filter.String(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesLabelFilters(t *testing.T) {
+	t.Parallel()
+
+	req := NewAPIRequest("session", time.Now(), "claude-3-sonnet", NewToken(100, 50, 0, 0), NewCost(0.01), 1000).
+		WithLabels(map[string]string{"environment": "prod", "user.id": "alice"})
+
+	tests := []struct {
+		name    string
+		request APIRequest
+		filters []LabelFilter
+		want    bool
+	}{
+		{
+			name:    "no filters match everything",
+			request: req,
+			want:    true,
+		},
+		{
+			name:    "matching label",
+			request: req,
+			filters: []LabelFilter{NewLabelFilter("environment", "prod")},
+			want:    true,
+		},
+		{
+			name:    "all filters must match",
+			request: req,
+			filters: []LabelFilter{NewLabelFilter("environment", "prod"), NewLabelFilter("user.id", "bob")},
+			want:    false,
+		},
+		{
+			name:    "absent label does not match",
+			request: req,
+			filters: []LabelFilter{NewLabelFilter("organization.id", "acme")},
+			want:    false,
+		},
+		{
+			name:    "request without labels does not match",
+			request: NewAPIRequest("session", time.Now(), "claude-3-sonnet", NewToken(100, 50, 0, 0), NewCost(0.01), 1000),
+			filters: []LabelFilter{NewLabelFilter("environment", "")},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := MatchesLabelFilters(tt.request, tt.filters); got != tt.want {
+				t.Errorf("MatchesLabelFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIRequest_LabelsAreCopied(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"environment": "prod"}
+	req := NewAPIRequest("session", time.Now(), "claude-3-sonnet", NewToken(100, 50, 0, 0), NewCost(0.01), 1000).WithLabels(labels)
+
+	labels["environment"] = "dev"
+	req.Labels()["environment"] = "staging"
+
+	if value, _ := req.Label("environment"); value != "prod" {
+		t.Errorf("Label(environment) = %q, want %q", value, "prod")
+	}
+}
@@ -21,6 +21,11 @@ func (m Model) IsBase() bool {
 	return strings.Contains(strings.ToLower(string(m)), "haiku")
 }
 
+// IsOpus returns true if this is an Opus model
+func (m Model) IsOpus() bool {
+	return strings.Contains(strings.ToLower(string(m)), "opus")
+}
+
 // String returns the string representation of the model
 func (m Model) String() string {
 	return string(m)
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ModelIgnoreList matches models that must be excluded from aggregation.
+// Patterns are case-insensitive globs where "*" matches any run of characters
+// (including "/") and "?" matches a single character.
+type ModelIgnoreList struct {
+	patterns []*regexp.Regexp
+}
+
+// NewModelIgnoreList compiles the given patterns, skipping blank entries
+func NewModelIgnoreList(patterns []string) ModelIgnoreList {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		compiled = append(compiled, compileModelGlob(pattern))
+	}
+
+	return ModelIgnoreList{patterns: compiled}
+}
+
+// compileModelGlob converts a case-insensitive model glob into an anchored expression
+func compileModelGlob(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(strings.ToLower(pattern))
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// IsEmpty returns true when no model is ignored
+func (l ModelIgnoreList) IsEmpty() bool {
+	return len(l.patterns) == 0
+}
+
+// Matches returns true if the model matches any ignore pattern
+func (l ModelIgnoreList) Matches(model Model) bool {
+	name := strings.ToLower(model.String())
+	for _, pattern := range l.patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the requests whose model is not ignored
+func (l ModelIgnoreList) Filter(requests []APIRequest) []APIRequest {
+	if l.IsEmpty() {
+		return requests
+	}
+
+	kept := make([]APIRequest, 0, len(requests))
+	for _, req := range requests {
+		if !l.Matches(req.Model()) {
+			kept = append(kept, req)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModelIgnoreList_Matches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		patterns []string
+		model    string
+		want     bool
+	}{
+		{name: "exact name", patterns: []string{"llama3"}, model: "llama3", want: true},
+		{name: "case insensitive", patterns: []string{"Llama3"}, model: "LLAMA3", want: true},
+		{name: "wildcard prefix", patterns: []string{"local-*"}, model: "local-qwen-32b", want: true},
+		{name: "wildcard crosses slashes", patterns: []string{"ollama/*"}, model: "ollama/library/llama3", want: true},
+		{name: "single character wildcard", patterns: []string{"llama?"}, model: "llama3", want: true},
+		{name: "pattern must match the whole name", patterns: []string{"llama"}, model: "llama3", want: false},
+		{name: "regex characters are literal", patterns: []string{"model.v1"}, model: "modelxv1", want: false},
+		{name: "blank patterns are skipped", patterns: []string{"  "}, model: "claude-3-opus", want: false},
+		{name: "claude models are kept", patterns: []string{"local-*", "llama*"}, model: "claude-3-5-sonnet-20241022", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NewModelIgnoreList(tt.patterns).Matches(NewModel(tt.model)); got != tt.want {
+				t.Errorf("Matches(%q) with %v = %v, want %v", tt.model, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelIgnoreList_Filter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(model string) APIRequest {
+		return NewAPIRequest("session", now, model, NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	}
+	requests := []APIRequest{newRequest("claude-3-opus"), newRequest("local-llama"), newRequest("claude-3-haiku")}
+
+	kept := NewModelIgnoreList([]string{"local-*"}).Filter(requests)
+	if len(kept) != 2 {
+		t.Fatalf("Filter() kept %d requests, want 2", len(kept))
+	}
+	for _, req := range kept {
+		if req.Model() == "local-llama" {
+			t.Error("Filter() kept an ignored model")
+		}
+	}
+
+	if all := NewModelIgnoreList(nil).Filter(requests); len(all) != 3 {
+		t.Errorf("Empty ignore list kept %d requests, want 3", len(all))
+	}
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ModelDisplayName is a short name shown instead of the raw name of models matching a glob pattern
+type ModelDisplayName struct {
+	pattern *regexp.Regexp
+	name    string
+}
+
+// NewModelDisplayName creates a display name for models matching the case-insensitive glob pattern
+func NewModelDisplayName(pattern, name string) ModelDisplayName {
+	return ModelDisplayName{
+		pattern: compileModelGlob(strings.TrimSpace(pattern)),
+		name:    strings.TrimSpace(name),
+	}
+}
+
+// Matches returns true if the display name applies to the model
+func (n ModelDisplayName) Matches(model Model) bool {
+	return n.pattern.MatchString(strings.ToLower(model.String()))
+}
+
+// ModelNameMap holds the configured display names, first match wins.
+// Only what is shown changes, stored requests keep the raw model name.
+type ModelNameMap struct {
+	names []ModelDisplayName
+}
+
+// NewModelNameMap creates a map from the display names in priority order
+func NewModelNameMap(names ...ModelDisplayName) ModelNameMap {
+	return ModelNameMap{names: names}
+}
+
+// IsEmpty returns true when no display name is configured
+func (m ModelNameMap) IsEmpty() bool {
+	return len(m.names) == 0
+}
+
+// DisplayName returns the name of the first matching entry, or the raw model name when none matches
+func (m ModelNameMap) DisplayName(model Model) string {
+	for _, name := range m.names {
+		if name.Matches(model) {
+			return name.name
+		}
+	}
+	return model.String()
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "testing"
+
+func TestModelNameMap_DisplayName(t *testing.T) {
+	t.Parallel()
+
+	names := NewModelNameMap(
+		NewModelDisplayName("claude-3-5-sonnet-20241022", "Sonnet 3.5"),
+		NewModelDisplayName("claude-*sonnet*", "Sonnet"),
+		NewModelDisplayName(" claude-3-5-haiku-* ", " Haiku 3.5 "),
+	)
+
+	tests := []struct {
+		name  string
+		names ModelNameMap
+		model string
+		want  string
+	}{
+		{name: "exact name", names: names, model: "claude-3-5-sonnet-20241022", want: "Sonnet 3.5"},
+		{name: "first match wins", names: names, model: "claude-sonnet-4-20250514", want: "Sonnet"},
+		{name: "case-insensitive with trimmed values", names: names, model: "Claude-3-5-Haiku-20241022", want: "Haiku 3.5"},
+		{name: "unmapped model keeps the raw name", names: names, model: "claude-opus-4-20250514", want: "claude-opus-4-20250514"},
+		{name: "empty map keeps the raw name", names: NewModelNameMap(), model: "claude-3-5-sonnet-20241022", want: "claude-3-5-sonnet-20241022"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.names.DisplayName(NewModel(tt.model)); got != tt.want {
+				t.Errorf("DisplayName(%q) = %q, want %q", tt.model, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelTier is the usage tier a model is counted in
+type ModelTier string
+
+const (
+	ModelTierBase    ModelTier = "base"    // Counted as base usage, not against token limits
+	ModelTierPremium ModelTier = "premium" // Counted as premium usage against token limits
+	ModelTierOpus    ModelTier = "opus"    // Premium usage listed on its own, as Opus draws on the limits fastest
+)
+
+// ParseModelTier validates a tier name
+func ParseModelTier(value string) (ModelTier, error) {
+	switch ModelTier(strings.ToLower(strings.TrimSpace(value))) {
+	case ModelTierBase:
+		return ModelTierBase, nil
+	case ModelTierPremium:
+		return ModelTierPremium, nil
+	case ModelTierOpus:
+		return ModelTierOpus, nil
+	default:
+		return "", fmt.Errorf("invalid model tier %q (expected base, premium or opus)", value)
+	}
+}
+
+// IsPremium returns true if the tier counts as premium usage against token limits
+func (t ModelTier) IsPremium() bool {
+	return t != ModelTierBase
+}
+
+// ModelClassifier assigns models to tiers.
+// Overrides match model names case-insensitively; other models fall back to Model.IsBase and Model.IsOpus.
+type ModelClassifier struct {
+	overrides map[string]ModelTier
+}
+
+// NewModelClassifier creates a classifier with the given model name to tier overrides
+func NewModelClassifier(overrides map[string]ModelTier) ModelClassifier {
+	normalized := make(map[string]ModelTier, len(overrides))
+	for model, tier := range overrides {
+		normalized[strings.ToLower(strings.TrimSpace(model))] = tier
+	}
+	return ModelClassifier{overrides: normalized}
+}
+
+// HasOverrides returns true if any model is reassigned
+func (c ModelClassifier) HasOverrides() bool {
+	return len(c.overrides) > 0
+}
+
+// Overrides returns a copy of the model name to tier overrides
+func (c ModelClassifier) Overrides() map[string]ModelTier {
+	overrides := make(map[string]ModelTier, len(c.overrides))
+	for model, tier := range c.overrides {
+		overrides[model] = tier
+	}
+	return overrides
+}
+
+// Override returns the tier assigned to the model, if any
+func (c ModelClassifier) Override(model Model) (ModelTier, bool) {
+	tier, ok := c.overrides[strings.ToLower(model.String())]
+	return tier, ok
+}
+
+// Classify returns the tier of the model
+func (c ModelClassifier) Classify(model Model) ModelTier {
+	if tier, ok := c.Override(model); ok {
+		return tier
+	}
+	if model.IsBase() {
+		return ModelTierBase
+	}
+	if model.IsOpus() {
+		return ModelTierOpus
+	}
+	return ModelTierPremium
+}
+
+// Merge returns a classifier with the overrides of both, those of other win for the same model
+func (c ModelClassifier) Merge(other ModelClassifier) ModelClassifier {
+	overrides := c.Overrides()
+	for model, tier := range other.overrides {
+		overrides[model] = tier
+	}
+	return ModelClassifier{overrides: overrides}
+}
+
+// WithOverride returns a classifier assigning the model to the tier; an empty tier removes the override
+func (c ModelClassifier) WithOverride(model Model, tier ModelTier) ModelClassifier {
+	overrides := c.Overrides()
+	if tier == "" {
+		delete(overrides, strings.ToLower(model.String()))
+	} else {
+		overrides[strings.ToLower(model.String())] = tier
+	}
+	return ModelClassifier{overrides: overrides}
+}
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModelClassifier_Classify(t *testing.T) {
+	t.Parallel()
+
+	classifier := NewModelClassifier(map[string]ModelTier{
+		"Local-Llama":               ModelTierBase,
+		"claude-3-5-haiku-20241022": ModelTierPremium,
+		"claude-opus-4-local":       ModelTierPremium,
+	})
+
+	tests := []struct {
+		name     string
+		model    string
+		expected ModelTier
+	}{
+		{name: "haiku defaults to base", model: "claude-3-haiku-20240307", expected: ModelTierBase},
+		{name: "opus defaults to opus", model: "claude-3-opus-20240229", expected: ModelTierOpus},
+		{name: "other models default to premium", model: "claude-sonnet-4-20250514", expected: ModelTierPremium},
+		{name: "override moves opus to premium", model: "claude-opus-4-local", expected: ModelTierPremium},
+		{name: "override moves a model to base", model: "local-llama", expected: ModelTierBase},
+		{name: "override moves haiku to premium", model: "claude-3-5-haiku-20241022", expected: ModelTierPremium},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := classifier.Classify(NewModel(tt.model)); got != tt.expected {
+				t.Errorf("Classify(%q) = %q, want %q", tt.model, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModelClassifier_WithOverride(t *testing.T) {
+	t.Parallel()
+
+	original := NewModelClassifier(nil)
+	model := NewModel("claude-sonnet-4")
+
+	reassigned := original.WithOverride(model, ModelTierBase)
+	if reassigned.Classify(model) != ModelTierBase {
+		t.Errorf("expected override to apply, got %q", reassigned.Classify(model))
+	}
+	if original.HasOverrides() {
+		t.Error("expected the original classifier to be unchanged")
+	}
+
+	reset := reassigned.WithOverride(model, "")
+	if reset.HasOverrides() || reset.Classify(model) != ModelTierPremium {
+		t.Errorf("expected reset to restore the default tier, got %q", reset.Classify(model))
+	}
+}
+
+func TestModelClassifier_Merge(t *testing.T) {
+	t.Parallel()
+
+	configured := NewModelClassifier(map[string]ModelTier{"local-llama": ModelTierBase, "local-mistral": ModelTierBase})
+	merged := configured.Merge(NewModelClassifier(map[string]ModelTier{"Local-Llama": ModelTierOpus}))
+
+	if tier := merged.Classify(NewModel("local-llama")); tier != ModelTierOpus {
+		t.Errorf("expected the merged override to win, got %q", tier)
+	}
+	if tier := merged.Classify(NewModel("local-mistral")); tier != ModelTierBase {
+		t.Errorf("expected the configured override to be kept, got %q", tier)
+	}
+	if tier := configured.Classify(NewModel("local-llama")); tier != ModelTierBase {
+		t.Errorf("expected the original classifier to be unchanged, got %q", tier)
+	}
+}
+
+func TestParseModelTier(t *testing.T) {
+	t.Parallel()
+
+	if tier, err := ParseModelTier(" Base "); err != nil || tier != ModelTierBase {
+		t.Errorf("ParseModelTier(\" Base \") = %q, %v", tier, err)
+	}
+	if tier, err := ParseModelTier("OPUS"); err != nil || tier != ModelTierOpus {
+		t.Errorf("ParseModelTier(\"OPUS\") = %q, %v", tier, err)
+	}
+	if _, err := ParseModelTier("sonnet"); err == nil {
+		t.Error("expected an error for an unknown tier")
+	}
+}
+
+func TestNewClassifiedStatsFromRequests(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	requests := []APIRequest{
+		NewAPIRequest("s", now, "claude-3-haiku-20240307", NewToken(100, 0, 0, 0), NewCost(0.1), 100),
+		NewAPIRequest("s", now, "local-llama", NewToken(200, 0, 0, 0), NewCost(0.2), 100),
+		NewAPIRequest("s", now, "claude-opus-4-20250514", NewToken(400, 0, 0, 0), NewCost(0.4), 100),
+	}
+	period := NewPeriod(now.Add(-time.Hour), now)
+
+	defaults := NewStatsFromRequests(requests, period)
+	if defaults.BaseRequests() != 1 || defaults.PremiumRequests() != 2 {
+		t.Fatalf("expected 1 base and 2 premium requests (opus included) by default, got %d/%d", defaults.BaseRequests(), defaults.PremiumRequests())
+	}
+
+	classified := NewClassifiedStatsFromRequests(requests, period, NewModelClassifier(map[string]ModelTier{"local-llama": ModelTierBase}))
+	if classified.BaseRequests() != 2 || classified.PremiumRequests() != 1 {
+		t.Errorf("expected 2 base requests after override, got %d/%d", classified.BaseRequests(), classified.PremiumRequests())
+	}
+	if classified.BaseTokens().Total() != 300 {
+		t.Errorf("expected 300 base tokens, got %d", classified.BaseTokens().Total())
+	}
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// ModelUsage summarizes how often and when a model was used
+type ModelUsage struct {
+	model       Model
+	firstSeenAt time.Time
+	lastSeenAt  time.Time
+	requests    int
+}
+
+// NewModelUsage creates a usage summary for a model
+func NewModelUsage(model Model, firstSeenAt, lastSeenAt time.Time, requests int) ModelUsage {
+	return ModelUsage{
+		model:       model,
+		firstSeenAt: firstSeenAt,
+		lastSeenAt:  lastSeenAt,
+		requests:    requests,
+	}
+}
+
+// Model returns the summarized model
+func (u ModelUsage) Model() Model {
+	return u.model
+}
+
+// FirstSeenAt returns the timestamp of the earliest request for the model
+func (u ModelUsage) FirstSeenAt() time.Time {
+	return u.firstSeenAt
+}
+
+// LastSeenAt returns the timestamp of the latest request for the model
+func (u ModelUsage) LastSeenAt() time.Time {
+	return u.lastSeenAt
+}
+
+// Requests returns the number of requests for the model
+func (u ModelUsage) Requests() int {
+	return u.requests
+}
+
+// Add returns the summary including one more request at the timestamp
+func (u ModelUsage) Add(timestamp time.Time) ModelUsage {
+	if timestamp.Before(u.firstSeenAt) {
+		u.firstSeenAt = timestamp
+	}
+	if timestamp.After(u.lastSeenAt) {
+		u.lastSeenAt = timestamp
+	}
+	u.requests++
+	return u
+}
+
+// SortModelUsages orders the summaries by request count, most used first, then by model name
+func SortModelUsages(usages []ModelUsage) {
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].requests != usages[j].requests {
+			return usages[i].requests > usages[j].requests
+		}
+		return usages[i].model.String() < usages[j].model.String()
+	})
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NumberLocale controls the decimal and digit grouping separators of rendered numbers
+type NumberLocale struct {
+	name     string
+	decimal  string
+	grouping string
+	grouped  bool
+}
+
+// numberLocales lists the supported locales by lowercase name
+var numberLocales = map[string]NumberLocale{
+	"en-us": {name: "en-US", decimal: ".", grouping: ","},
+	"en-gb": {name: "en-GB", decimal: ".", grouping: ","},
+	"ja-jp": {name: "ja-JP", decimal: ".", grouping: ","},
+	"zh-cn": {name: "zh-CN", decimal: ".", grouping: ","},
+	"de-de": {name: "de-DE", decimal: ",", grouping: "."},
+	"es-es": {name: "es-ES", decimal: ",", grouping: "."},
+	"it-it": {name: "it-IT", decimal: ",", grouping: "."},
+	"nl-nl": {name: "nl-NL", decimal: ",", grouping: "."},
+	"pt-br": {name: "pt-BR", decimal: ",", grouping: "."},
+	"fr-fr": {name: "fr-FR", decimal: ",", grouping: "\u202f"}, // narrow no-break space
+	"de-ch": {name: "de-CH", decimal: ".", grouping: "'"},
+}
+
+// DefaultNumberLocale renders numbers in the en-US style without grouping, e.g. 1234.5
+var DefaultNumberLocale = numberLocales["en-us"]
+
+// ParseNumberLocale looks up a locale by name such as "de-DE", case-insensitive and accepting "de_DE"
+func ParseNumberLocale(name string) (NumberLocale, error) {
+	if strings.TrimSpace(name) == "" {
+		return DefaultNumberLocale, nil
+	}
+
+	key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"))
+	locale, ok := numberLocales[key]
+	if !ok {
+		return NumberLocale{}, fmt.Errorf("unsupported locale %q (must be one of: %s)", name, strings.Join(SupportedNumberLocales(), ", "))
+	}
+	return locale, nil
+}
+
+// SupportedNumberLocales returns the names of the supported locales
+func SupportedNumberLocales() []string {
+	return []string{"en-US", "en-GB", "ja-JP", "zh-CN", "de-DE", "es-ES", "it-IT", "nl-NL", "pt-BR", "fr-FR", "de-CH"}
+}
+
+// Name returns the locale name, e.g. "de-DE"
+func (l NumberLocale) Name() string {
+	return l.name
+}
+
+// WithGrouping returns the locale with digit grouping of the integer part enabled or disabled
+func (l NumberLocale) WithGrouping(enabled bool) NumberLocale {
+	l.grouped = enabled
+	return l
+}
+
+// FormatFloat renders value with the given number of decimal places, e.g. "1.234,50" for de-DE with grouping
+func (l NumberLocale) FormatFloat(value float64, precision int) string {
+	formatted := strconv.FormatFloat(value, 'f', precision, 64)
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	integer, fraction, hasFraction := strings.Cut(formatted, ".")
+	if l.grouped {
+		integer = groupDigits(integer, l.grouping)
+	}
+
+	// A zero value locale behaves like the default instead of dropping the separator
+	decimal := l.decimal
+	if decimal == "" {
+		decimal = DefaultNumberLocale.decimal
+	}
+
+	if !hasFraction {
+		return sign + integer
+	}
+	return sign + integer + decimal + fraction
+}
+
+// FormatPercent renders a percentage with the given decimal places and a "%" sign. The value is
+// truncated rather than rounded, so a usage just below a limit never shows as reaching it.
+func (l NumberLocale) FormatPercent(percent float64, decimals int) string {
+	scale := math.Pow(10, float64(decimals))
+	// The epsilon keeps values like 155.5 computed as 155.49999… from dropping a digit
+	truncated := math.Trunc(percent*scale+percentEpsilon) / scale
+	return l.FormatFloat(truncated, decimals) + "%"
+}
+
+// percentEpsilon absorbs floating point error when truncating percentages
+const percentEpsilon = 1e-9
+
+// FormatTokenCount abbreviates a token count with K and M suffixes using the given decimal places,
+// rounding halves up and switching to M when thousands round up to 1000, e.g. 999950 is "1.0M"
+func (l NumberLocale) FormatTokenCount(count int64, thousandDecimals, millionDecimals int) string {
+	if count < 1_000 {
+		return strconv.FormatInt(count, 10)
+	}
+
+	if count < 1_000_000 {
+		scaled, factor := roundToUnit(count, 1_000, thousandDecimals)
+		if scaled < 1_000*factor {
+			return l.FormatFloat(float64(scaled)/float64(factor), thousandDecimals) + "K"
+		}
+	}
+
+	scaled, factor := roundToUnit(count, 1_000_000, millionDecimals)
+	return l.FormatFloat(float64(scaled)/float64(factor), millionDecimals) + "M"
+}
+
+// roundToUnit rounds count to the given decimals of unit, returning the rounded value scaled by 10^decimals and that factor
+func roundToUnit(count int64, unit int64, decimals int) (int64, int64) {
+	factor := int64(1)
+	for i := 0; i < decimals; i++ {
+		factor *= 10
+	}
+	step := unit / factor
+	return (count + step/2) / step, factor
+}
+
+// groupDigits inserts the separator between every three digits from the right
+func groupDigits(digits string, separator string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package entity
+
+import "testing"
+
+func TestParseNumberLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "", expected: "en-US"},
+		{input: "en-US", expected: "en-US"},
+		{input: "de_de", expected: "de-DE"},
+		{input: " FR-fr ", expected: "fr-FR"},
+		{input: "xx-XX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			locale, err := ParseNumberLocale(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseNumberLocale(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNumberLocale(%q) error = %v", tt.input, err)
+			}
+			if locale.Name() != tt.expected {
+				t.Errorf("ParseNumberLocale(%q) = %q, want %q", tt.input, locale.Name(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestNumberLocale_FormatFloat(t *testing.T) {
+	t.Parallel()
+
+	france, _ := ParseNumberLocale("fr-FR")
+	switzerland, _ := ParseNumberLocale("de-CH")
+
+	tests := []struct {
+		name      string
+		locale    NumberLocale
+		value     float64
+		precision int
+		expected  string
+	}{
+		{name: "default without grouping", locale: DefaultNumberLocale, value: 1234567.891, precision: 2, expected: "1234567.89"},
+		{name: "default with grouping", locale: DefaultNumberLocale.WithGrouping(true), value: 1234567.891, precision: 2, expected: "1,234,567.89"},
+		{name: "short integer is not grouped", locale: DefaultNumberLocale.WithGrouping(true), value: 999.5, precision: 1, expected: "999.5"},
+		{name: "negative with grouping", locale: DefaultNumberLocale.WithGrouping(true), value: -12345, precision: 0, expected: "-12,345"},
+		{name: "french separators", locale: france.WithGrouping(true), value: 12345.6, precision: 1, expected: "12\u202f345,6"},
+		{name: "swiss apostrophe", locale: switzerland.WithGrouping(true), value: 12345.6, precision: 2, expected: "12'345.60"},
+		{name: "zero value locale uses a dot", locale: NumberLocale{}, value: 1.5, precision: 1, expected: "1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.locale.FormatFloat(tt.value, tt.precision); got != tt.expected {
+				t.Errorf("FormatFloat() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNumberLocale_FormatPercent(t *testing.T) {
+	t.Parallel()
+
+	germany, _ := ParseNumberLocale("de-DE")
+
+	tests := []struct {
+		name     string
+		locale   NumberLocale
+		percent  float64
+		decimals int
+		expected string
+	}{
+		{name: "whole percent", locale: DefaultNumberLocale, percent: 155.5, decimals: 0, expected: "155%"},
+		{name: "one decimal", locale: DefaultNumberLocale, percent: 155.5, decimals: 1, expected: "155.5%"},
+		{name: "floating point error keeps the digit", locale: DefaultNumberLocale, percent: 31.1 / 20 * 100, decimals: 1, expected: "155.5%"},
+		{name: "near a threshold truncates", locale: DefaultNumberLocale, percent: 89.96, decimals: 1, expected: "89.9%"},
+		{name: "near a threshold as integer", locale: DefaultNumberLocale, percent: 99.99, decimals: 0, expected: "99%"},
+		{name: "locale decimal separator", locale: germany, percent: 155.5, decimals: 1, expected: "155,5%"},
+		{name: "zero", locale: DefaultNumberLocale, percent: 0, decimals: 1, expected: "0.0%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.locale.FormatPercent(tt.percent, tt.decimals); got != tt.expected {
+				t.Errorf("FormatPercent() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNumberLocale_FormatTokenCount(t *testing.T) {
+	t.Parallel()
+
+	germany, _ := ParseNumberLocale("de-DE")
+
+	tests := []struct {
+		name     string
+		locale   NumberLocale
+		count    int64
+		decimals int
+		expected string
+	}{
+		{name: "below a thousand", locale: DefaultNumberLocale, count: 999, decimals: 1, expected: "999"},
+		{name: "one thousand", locale: DefaultNumberLocale, count: 1000, decimals: 1, expected: "1.0K"},
+		{name: "1499 with one decimal", locale: DefaultNumberLocale, count: 1499, decimals: 1, expected: "1.5K"},
+		{name: "1500 with one decimal", locale: DefaultNumberLocale, count: 1500, decimals: 1, expected: "1.5K"},
+		{name: "1250 rounds half up", locale: DefaultNumberLocale, count: 1250, decimals: 1, expected: "1.3K"},
+		{name: "999 without decimals", locale: DefaultNumberLocale, count: 999, decimals: 0, expected: "999"},
+		{name: "1000 without decimals", locale: DefaultNumberLocale, count: 1000, decimals: 0, expected: "1K"},
+		{name: "1499 without decimals", locale: DefaultNumberLocale, count: 1499, decimals: 0, expected: "1K"},
+		{name: "1500 without decimals", locale: DefaultNumberLocale, count: 1500, decimals: 0, expected: "2K"},
+		{name: "2500 without decimals", locale: DefaultNumberLocale, count: 2500, decimals: 0, expected: "3K"},
+		{name: "thousands rounding up to a million", locale: DefaultNumberLocale, count: 999_950, decimals: 1, expected: "1.0M"},
+		{name: "thousands rounding up without decimals", locale: DefaultNumberLocale, count: 999_500, decimals: 0, expected: "1M"},
+		{name: "millions", locale: DefaultNumberLocale, count: 1_500_000, decimals: 1, expected: "1.5M"},
+		{name: "locale decimal separator", locale: germany, count: 1500, decimals: 1, expected: "1,5K"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.locale.FormatTokenCount(tt.count, tt.decimals, tt.decimals); got != tt.expected {
+				t.Errorf("FormatTokenCount(%d, %d) = %q, want %q", tt.count, tt.decimals, got, tt.expected)
+			}
+		})
+	}
+}
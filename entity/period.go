@@ -36,6 +36,23 @@ func (p Period) EndAt() time.Time {
 	return p.endAt
 }
 
+// ClipEnd returns the period ending at t when t is before its end, e.g. to leave out requests after a
+// point in time. The end never moves before the start, so a t before the period matches nothing.
+func (p Period) ClipEnd(t time.Time) Period {
+	if t.IsZero() || !t.Before(p.endAt) {
+		return p
+	}
+	if t.Before(p.startAt) {
+		return NewPeriod(p.startAt, p.startAt.Add(-time.Nanosecond))
+	}
+	return NewPeriod(p.startAt, t)
+}
+
+// Overlaps returns true if the periods share at least one point in time, both ends included
+func (p Period) Overlaps(other Period) bool {
+	return !p.startAt.After(other.endAt) && !other.startAt.After(p.endAt)
+}
+
 // IsAllTime returns true if this period represents all time
 func (p Period) IsAllTime() bool {
 	return p.startAt.IsZero()
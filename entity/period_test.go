@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriod_ClipEnd(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 7, 31, 23, 59, 59, 0, time.UTC)
+	period := NewPeriod(start, end)
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		wantEnd time.Time
+	}{
+		{name: "within the period", at: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC), wantEnd: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "after the period", at: time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC), wantEnd: end},
+		{name: "zero time", at: time.Time{}, wantEnd: end},
+		{name: "before the period matches nothing", at: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), wantEnd: start.Add(-time.Nanosecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clipped := period.ClipEnd(tt.at)
+			if !clipped.StartAt().Equal(start) {
+				t.Errorf("expected start %v, got %v", start, clipped.StartAt())
+			}
+			if !clipped.EndAt().Equal(tt.wantEnd) {
+				t.Errorf("expected end %v, got %v", tt.wantEnd, clipped.EndAt())
+			}
+		})
+	}
+}
+
+func TestPeriod_Overlaps(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	period := NewPeriod(base, base.Add(time.Hour))
+
+	tests := []struct {
+		name  string
+		other Period
+		want  bool
+	}{
+		{name: "inside", other: NewPeriod(base.Add(time.Minute), base.Add(2*time.Minute)), want: true},
+		{name: "touching the end", other: NewPeriod(base.Add(time.Hour), base.Add(2*time.Hour)), want: true},
+		{name: "single point at the start", other: NewPeriod(base, base), want: true},
+		{name: "before", other: NewPeriod(base.Add(-2*time.Hour), base.Add(-time.Nanosecond)), want: false},
+		{name: "after", other: NewPeriod(base.Add(time.Hour+time.Nanosecond), base.Add(2*time.Hour)), want: false},
+		{name: "all time", other: NewAllTimePeriod(base.Add(time.Minute)), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := period.Overlaps(tt.other); got != tt.want {
+				t.Errorf("Overlaps() = %v, want %v", got, tt.want)
+			}
+			if got := tt.other.Overlaps(period); got != tt.want {
+				t.Errorf("Overlaps() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -33,29 +33,45 @@ func (p Plan) IsValid() bool {
 }
 
 func (p Plan) CalculateUsagePercentage(actualCost Cost) int {
+	return int(p.UsagePercent(actualCost))
+}
+
+// UsagePercent returns the percentage of the plan price used without truncating it
+func (p Plan) UsagePercent(actualCost Cost) float64 {
 	if !p.IsValid() || p.price.Amount() == 0 {
 		return 0
 	}
 
-	percentage := (actualCost.Amount() / p.price.Amount()) * 100
-	return int(percentage)
+	return (actualCost.Amount() / p.price.Amount()) * 100
 }
 
 // CalculateUsagePercentageInPeriod calculates the percentage of period budget used
 // based on the actual cost for a specific period and the plan's period budget
 func (p Plan) CalculateUsagePercentageInPeriod(actualCost Cost, period Period) int {
+	return int(p.UsagePercentInPeriod(actualCost, period))
+}
+
+// UsagePercentInPeriod returns the percentage of the period budget used without truncating it
+func (p Plan) UsagePercentInPeriod(actualCost Cost, period Period) float64 {
 	if !p.IsValid() || p.price.Amount() == 0 {
 		return 0
 	}
 
-	// Calculate days in the month that contains the period start time
-	periodStart := period.StartAt()
-	daysInMonth := time.Date(periodStart.Year(), periodStart.Month()+1, 0, 0, 0, 0, 0, periodStart.Location()).Day()
+	// Calculate percentage: (actual cost / period budget) * 100
+	return (actualCost.Amount() / p.CalculatePeriodBudget(period).Amount()) * 100
+}
 
-	// Calculate period budget (plan price / days in month)
-	periodBudget := p.price.Amount() / float64(daysInMonth)
+// CalculatePeriodBudget returns the daily share of the plan price for the month
+// containing the period start (plan price / days in month)
+func (p Plan) CalculatePeriodBudget(period Period) Cost {
+	if !p.IsValid() || p.price.Amount() == 0 {
+		return NewCost(0)
+	}
 
-	// Calculate percentage: (actual cost / period budget) * 100
-	percentage := (actualCost.Amount() / periodBudget) * 100
-	return int(percentage)
+	return NewCost(p.price.Amount() / float64(DaysInMonth(period.StartAt())))
+}
+
+// DaysInMonth returns the number of days in the month containing t
+func DaysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
 }
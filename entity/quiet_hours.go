@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily time-of-day window such as "22:00-07:00", which may span midnight
+type QuietHours struct {
+	start int // minutes after midnight, inclusive
+	end   int // minutes after midnight, exclusive
+}
+
+// ParseQuietHours parses a "HH:MM-HH:MM" window; the end before the start means it spans midnight
+func ParseQuietHours(value string) (QuietHours, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q (expected HH:MM-HH:MM)", value)
+	}
+
+	start, err := time.Parse("15:04", strings.TrimSpace(startStr))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q (expected HH:MM-HH:MM)", value)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(endStr))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q (expected HH:MM-HH:MM)", value)
+	}
+
+	quiet := QuietHours{
+		start: start.Hour()*60 + start.Minute(),
+		end:   end.Hour()*60 + end.Minute(),
+	}
+	if quiet.start == quiet.end {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q (start and end must differ)", value)
+	}
+
+	return quiet, nil
+}
+
+// Contains reports whether the wall clock time of t falls inside the window
+// Convert t to the configured timezone first, the window has no timezone of its own
+func (q QuietHours) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
+
+// String returns the window in "HH:MM-HH:MM" form
+func (q QuietHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", q.start/60, q.start%60, q.end/60, q.end%60)
+}
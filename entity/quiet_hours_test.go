@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "same day window", value: "12:00-13:30", want: "12:00-13:30"},
+		{name: "overnight window", value: "22:00-07:00", want: "22:00-07:00"},
+		{name: "spaces are trimmed", value: " 9:05 - 10:00 ", want: "09:05-10:00"},
+		{name: "missing separator", value: "22:00", wantErr: true},
+		{name: "invalid time", value: "25:00-07:00", wantErr: true},
+		{name: "empty window", value: "07:00-07:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseQuietHours(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseQuietHours(%q) expected error, got %v", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQuietHours(%q) unexpected error: %v", tt.value, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseQuietHours(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 15, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		window string
+		time   time.Time
+		want   bool
+	}{
+		{name: "inside same day window", window: "12:00-13:30", time: at(13, 0), want: true},
+		{name: "start is inclusive", window: "12:00-13:30", time: at(12, 0), want: true},
+		{name: "end is exclusive", window: "12:00-13:30", time: at(13, 30), want: false},
+		{name: "before overnight window", window: "22:00-07:00", time: at(21, 59), want: false},
+		{name: "late evening in overnight window", window: "22:00-07:00", time: at(23, 0), want: true},
+		{name: "early morning in overnight window", window: "22:00-07:00", time: at(3, 0), want: true},
+		{name: "after overnight window", window: "22:00-07:00", time: at(7, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			quiet, err := ParseQuietHours(tt.window)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := quiet.Contains(tt.time); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.time.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
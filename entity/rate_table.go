@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tokensPerMillion converts per-million-token rates to per-token costs
+const tokensPerMillion = 1_000_000
+
+// ModelRate is the USD price per million tokens for models matching a glob pattern
+type ModelRate struct {
+	pattern       *regexp.Regexp
+	input         float64
+	output        float64
+	cacheRead     float64
+	cacheCreation float64
+}
+
+// NewModelRate creates a rate for models matching the case-insensitive glob pattern
+func NewModelRate(pattern string, inputPerMillion, cacheReadPerMillion float64) ModelRate {
+	return ModelRate{
+		pattern:   compileModelGlob(strings.TrimSpace(pattern)),
+		input:     inputPerMillion,
+		cacheRead: cacheReadPerMillion,
+	}
+}
+
+// WithOutputRates returns a copy of the rate that also prices output and cache creation tokens
+func (r ModelRate) WithOutputRates(outputPerMillion, cacheCreationPerMillion float64) ModelRate {
+	r.output = outputPerMillion
+	r.cacheCreation = cacheCreationPerMillion
+	return r
+}
+
+// Matches returns true if the rate applies to the model
+func (r ModelRate) Matches(model Model) bool {
+	return r.pattern.MatchString(strings.ToLower(model.String()))
+}
+
+// CacheSavings returns what cache reads saved compared to sending the same tokens as fresh input
+func (r ModelRate) CacheSavings(tokens Token) Cost {
+	saved := float64(tokens.CacheRead()) * (r.input - r.cacheRead) / tokensPerMillion
+	return NewCost(saved)
+}
+
+// Cost returns the price of the tokens at this rate
+func (r ModelRate) Cost(tokens Token) Cost {
+	cost := float64(tokens.Input())*r.input +
+		float64(tokens.Output())*r.output +
+		float64(tokens.CacheRead())*r.cacheRead +
+		float64(tokens.CacheCreation())*r.cacheCreation
+	return NewCost(cost / tokensPerMillion)
+}
+
+// RateTable holds the configured per-model rates, first match wins
+type RateTable struct {
+	rates []ModelRate
+}
+
+// NewRateTable creates a rate table in lookup order
+func NewRateTable(rates ...ModelRate) RateTable {
+	return RateTable{rates: rates}
+}
+
+// IsEmpty returns true when no rates are configured
+func (t RateTable) IsEmpty() bool {
+	return len(t.rates) == 0
+}
+
+// Lookup returns the first rate matching the model
+func (t RateTable) Lookup(model Model) (ModelRate, bool) {
+	for _, rate := range t.rates {
+		if rate.Matches(model) {
+			return rate, true
+		}
+	}
+	return ModelRate{}, false
+}
+
+// CacheSavings sums the cache savings of the requests.
+// It reports false when a request with cache reads has no rate, as the total would be understated.
+func (t RateTable) CacheSavings(requests []APIRequest) (Cost, bool) {
+	if t.IsEmpty() {
+		return NewCost(0), false
+	}
+
+	total := NewCost(0)
+	for _, req := range requests {
+		if req.Tokens().CacheRead() == 0 {
+			continue
+		}
+
+		rate, ok := t.Lookup(req.Model())
+		if !ok {
+			return NewCost(0), false
+		}
+		total = total.Add(rate.CacheSavings(req.Tokens()))
+	}
+
+	return total, true
+}
+
+// Reprice returns copies of the requests with their cost recalculated from the token counts.
+// Requests for models without a rate keep their recorded cost.
+func (t RateTable) Reprice(requests []APIRequest) []APIRequest {
+	if t.IsEmpty() {
+		return requests
+	}
+
+	repriced := make([]APIRequest, 0, len(requests))
+	for _, req := range requests {
+		if rate, ok := t.Lookup(req.Model()); ok {
+			req = req.WithCost(rate.Cost(req.Tokens()))
+		}
+		repriced = append(repriced, req)
+	}
+	return repriced
+}
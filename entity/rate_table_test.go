@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateTable_CacheSavings(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cacheRequest := func(model string, cacheRead int64) APIRequest {
+		return NewAPIRequest("session-1", now, model, NewToken(100, 50, cacheRead, 0), NewCost(0.01), 1000)
+	}
+
+	sonnet := NewModelRate("claude-*sonnet*", 3.0, 0.3)
+	haiku := NewModelRate("claude-*haiku*", 0.8, 0.08)
+
+	tests := []struct {
+		name          string
+		table         RateTable
+		requests      []APIRequest
+		expected      float64
+		expectedFound bool
+	}{
+		{
+			name:     "no rates configured",
+			table:    NewRateTable(),
+			requests: []APIRequest{cacheRequest("claude-3-5-sonnet-20241022", 1_000_000)},
+		},
+		{
+			name:          "savings use the matching model rate",
+			table:         NewRateTable(sonnet, haiku),
+			requests:      []APIRequest{cacheRequest("claude-3-5-sonnet-20241022", 1_000_000), cacheRequest("claude-3-5-haiku-20241022", 1_000_000)},
+			expected:      2.7 + 0.72,
+			expectedFound: true,
+		},
+		{
+			name:          "model patterns are case insensitive",
+			table:         NewRateTable(sonnet),
+			requests:      []APIRequest{cacheRequest("Claude-Sonnet-4", 2_000_000)},
+			expected:      5.4,
+			expectedFound: true,
+		},
+		{
+			name:          "requests without cache reads need no rate",
+			table:         NewRateTable(sonnet),
+			requests:      []APIRequest{cacheRequest("claude-3-5-sonnet-20241022", 1_000_000), cacheRequest("claude-3-opus-20240229", 0)},
+			expected:      2.7,
+			expectedFound: true,
+		},
+		{
+			name:     "unpriced cache reads hide the savings",
+			table:    NewRateTable(sonnet),
+			requests: []APIRequest{cacheRequest("claude-3-5-sonnet-20241022", 1_000_000), cacheRequest("claude-3-opus-20240229", 1_000)},
+		},
+		{
+			name:          "no requests save nothing",
+			table:         NewRateTable(sonnet),
+			expectedFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			savings, found := tt.table.CacheSavings(tt.requests)
+			if found != tt.expectedFound {
+				t.Fatalf("CacheSavings() found = %v, want %v", found, tt.expectedFound)
+			}
+			if diff := savings.Amount() - tt.expected; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("CacheSavings() = %f, want %f", savings.Amount(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRateTable_LookupFirstMatchWins(t *testing.T) {
+	t.Parallel()
+
+	table := NewRateTable(NewModelRate("claude-opus-4*", 15.0, 1.5), NewModelRate("claude-*", 3.0, 0.3))
+
+	rate, ok := table.Lookup(NewModel("claude-opus-4-20250514"))
+	if !ok {
+		t.Fatal("expected a matching rate")
+	}
+	if savings := rate.CacheSavings(NewToken(0, 0, 1_000_000, 0)).Amount(); savings != 13.5 {
+		t.Errorf("expected the first matching rate to apply (13.5), got %f", savings)
+	}
+
+	if _, ok := table.Lookup(NewModel("local-llama")); ok {
+		t.Error("expected no rate for an unmatched model")
+	}
+}
+
+func TestModelRate_Cost(t *testing.T) {
+	t.Parallel()
+
+	sonnet := NewModelRate("claude-*sonnet*", 3.0, 0.3).WithOutputRates(15.0, 3.75)
+
+	tests := []struct {
+		name     string
+		rate     ModelRate
+		tokens   Token
+		expected float64
+	}{
+		{
+			name:     "every token type is priced",
+			rate:     sonnet,
+			tokens:   NewToken(1_000_000, 1_000_000, 1_000_000, 1_000_000),
+			expected: 3.0 + 15.0 + 0.3 + 3.75,
+		},
+		{
+			name:     "rates scale per million tokens",
+			rate:     sonnet,
+			tokens:   NewToken(1_000, 2_000, 0, 0),
+			expected: 0.003 + 0.03,
+		},
+		{
+			name:     "output is free without output rates",
+			rate:     NewModelRate("claude-*", 3.0, 0.3),
+			tokens:   NewToken(1_000_000, 1_000_000, 0, 0),
+			expected: 3.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cost := tt.rate.Cost(tt.tokens)
+			if diff := cost.Amount() - tt.expected; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Cost() = %f, want %f", cost.Amount(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRateTable_Reprice(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	requests := []APIRequest{
+		NewAPIRequest("session-1", now, "claude-3-5-sonnet-20241022", NewToken(1_000_000, 0, 0, 0), NewCost(1.0), 1000),
+		NewAPIRequest("session-1", now, "local-llama", NewToken(1_000_000, 0, 0, 0), NewCost(0.5), 1000),
+	}
+
+	repriced := NewRateTable(NewModelRate("claude-*sonnet*", 6.0, 0.6)).Reprice(requests)
+	if len(repriced) != len(requests) {
+		t.Fatalf("Reprice() returned %d requests, want %d", len(repriced), len(requests))
+	}
+	if got := repriced[0].Cost().Amount(); got != 6.0 {
+		t.Errorf("matched model cost = %f, want 6.0", got)
+	}
+	if got := repriced[1].Cost().Amount(); got != 0.5 {
+		t.Errorf("unmatched model cost = %f, want the recorded 0.5", got)
+	}
+	if got := requests[0].Cost().Amount(); got != 1.0 {
+		t.Errorf("original request cost changed to %f", got)
+	}
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// scheduleDays maps the day names of a schedule to weekdays
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Schedule is a recurring window such as "mon-fri 09:00-17:00", a time of day range on selected weekdays.
+// A window whose end is before its start spans midnight and belongs to the day it starts on.
+type Schedule struct {
+	days  [7]bool // indexed by time.Weekday
+	start int     // minutes after midnight, inclusive
+	end   int     // minutes after midnight, exclusive
+}
+
+// ParseSchedule parses "<days> HH:MM-HH:MM", where days is "*" for every day or a comma-separated
+// list of day names and ranges such as "mon-fri" or "sat,sun"
+func ParseSchedule(value string) (Schedule, error) {
+	fields := strings.Fields(strings.ToLower(value))
+	if len(fields) != 2 {
+		return Schedule{}, fmt.Errorf("invalid schedule %q (expected e.g. \"mon-fri 09:00-17:00\")", value)
+	}
+
+	var schedule Schedule
+	if err := schedule.parseDays(fields[0]); err != nil {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: %w", value, err)
+	}
+
+	startStr, endStr, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return Schedule{}, fmt.Errorf("invalid schedule %q (expected HH:MM-HH:MM after the days)", value)
+	}
+	start, err := time.Parse("15:04", startStr)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid schedule %q (expected HH:MM-HH:MM after the days)", value)
+	}
+	end, err := time.Parse("15:04", endStr)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid schedule %q (expected HH:MM-HH:MM after the days)", value)
+	}
+
+	schedule.start = start.Hour()*60 + start.Minute()
+	schedule.end = end.Hour()*60 + end.Minute()
+	if schedule.start == schedule.end {
+		return Schedule{}, fmt.Errorf("invalid schedule %q (start and end must differ)", value)
+	}
+
+	return schedule, nil
+}
+
+// parseDays enables the days of a "*" or comma-separated day list, ranges may wrap around the week
+func (s *Schedule) parseDays(value string) error {
+	if value == "*" {
+		for day := range s.days {
+			s.days[day] = true
+		}
+		return nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		from, ok := scheduleDays[fromStr]
+		if !ok {
+			return fmt.Errorf("unknown day %q (expected sun, mon, tue, wed, thu, fri or sat)", fromStr)
+		}
+		if !isRange {
+			s.days[from] = true
+			continue
+		}
+
+		to, ok := scheduleDays[toStr]
+		if !ok {
+			return fmt.Errorf("unknown day %q (expected sun, mon, tue, wed, thu, fri or sat)", toStr)
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			s.days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Window returns the window containing t, or the latest one before it, in the location of t.
+// Window times follow the wall clock, so across a DST change a window is an hour shorter or longer.
+func (s Schedule) Window(t time.Time) Period {
+	location := t.Location()
+	year, month, day := t.Date()
+
+	// A week back always reaches an enabled day, an overnight window may have started the day before
+	for offset := 0; offset <= 7; offset++ {
+		date := time.Date(year, month, day-offset, 0, 0, 0, 0, location)
+		if !s.days[date.Weekday()] {
+			continue
+		}
+
+		start := time.Date(year, month, day-offset, s.start/60, s.start%60, 0, 0, location)
+		if start.After(t) {
+			continue
+		}
+
+		endDay := day - offset
+		if s.end < s.start {
+			endDay++
+		}
+		end := time.Date(year, month, endDay, s.end/60, s.end%60, 0, 0, location)
+		return NewPeriod(start.UTC(), end.Add(-time.Nanosecond).UTC())
+	}
+
+	return NewPeriod(t.UTC(), t.UTC())
+}
+
+// String returns the schedule in its canonical "mon,tue 09:00-17:00" form
+func (s Schedule) String() string {
+	var days []string
+	for _, name := range []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"} {
+		if s.days[scheduleDays[name]] {
+			days = append(days, name)
+		}
+	}
+	if len(days) == len(s.days) {
+		days = []string{"*"}
+	}
+	return fmt.Sprintf("%s %02d:%02d-%02d:%02d", strings.Join(days, ","), s.start/60, s.start%60, s.end/60, s.end%60)
+}
@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "weekday range", value: "mon-fri 09:00-17:00", want: "mon,tue,wed,thu,fri 09:00-17:00"},
+		{name: "day list", value: "sat,sun 10:00-14:00", want: "sun,sat 10:00-14:00"},
+		{name: "every day", value: "* 22:00-02:00", want: "* 22:00-02:00"},
+		{name: "range wrapping the week", value: "fri-mon 08:00-12:00", want: "sun,mon,fri,sat 08:00-12:00"},
+		{name: "case and spaces are ignored", value: "  Mon-Wed   9:05-10:00 ", want: "mon,tue,wed 09:05-10:00"},
+		{name: "missing time range", value: "mon-fri", wantErr: true},
+		{name: "missing days", value: "09:00-17:00", wantErr: true},
+		{name: "unknown day", value: "monday 09:00-17:00", wantErr: true},
+		{name: "empty day in list", value: "mon,,fri 09:00-17:00", wantErr: true},
+		{name: "invalid time", value: "mon 09:00-25:00", wantErr: true},
+		{name: "empty window", value: "mon 09:00-09:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSchedule(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSchedule(%q) expected error, got %v", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) unexpected error: %v", tt.value, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseSchedule(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Window(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name      string
+		schedule  string
+		at        time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "inside today's window",
+			schedule:  "mon-fri 09:00-17:00",
+			at:        time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC), // Wednesday
+			wantStart: time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 2, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "window start is included",
+			schedule:  "mon-fri 09:00-17:00",
+			at:        time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 2, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "before today's window uses the previous day",
+			schedule:  "mon-fri 09:00-17:00",
+			at:        time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "weekend uses friday",
+			schedule:  "mon-fri 09:00-17:00",
+			at:        time.Date(2025, 7, 6, 12, 0, 0, 0, time.UTC), // Sunday
+			wantStart: time.Date(2025, 7, 4, 9, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 4, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "single day before its window uses last week",
+			schedule:  "wed 09:00-17:00",
+			at:        time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 6, 25, 9, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 6, 25, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "overnight window after midnight belongs to the start day",
+			schedule:  "fri 22:00-02:00",
+			at:        time.Date(2025, 7, 5, 1, 0, 0, 0, time.UTC), // Saturday
+			wantStart: time.Date(2025, 7, 4, 22, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 5, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "window follows the location of the time",
+			schedule:  "* 09:00-17:00",
+			at:        time.Date(2025, 7, 2, 10, 0, 0, 0, tokyo),
+			wantStart: time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "spring forward shortens the window",
+			schedule:  "sun 01:00-05:00",
+			at:        time.Date(2025, 3, 9, 6, 0, 0, 0, newYork),
+			wantStart: time.Date(2025, 3, 9, 6, 0, 0, 0, time.UTC), // 01:00 EST
+			wantEnd:   time.Date(2025, 3, 9, 9, 0, 0, 0, time.UTC), // 05:00 EDT
+		},
+		{
+			name:      "fall back lengthens the window",
+			schedule:  "sun 00:00-04:00",
+			at:        time.Date(2025, 11, 2, 5, 0, 0, 0, newYork),
+			wantStart: time.Date(2025, 11, 2, 4, 0, 0, 0, time.UTC), // 00:00 EDT
+			wantEnd:   time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC), // 04:00 EST
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseSchedule(tt.schedule)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) unexpected error: %v", tt.schedule, err)
+			}
+
+			period := schedule.Window(tt.at)
+			if !period.StartAt().Equal(tt.wantStart) {
+				t.Errorf("expected start %v, got %v", tt.wantStart, period.StartAt())
+			}
+			// The period ends just before the window end
+			if !period.EndAt().Equal(tt.wantEnd.Add(-time.Nanosecond)) {
+				t.Errorf("expected end %v, got %v", tt.wantEnd, period.EndAt().Add(time.Nanosecond))
+			}
+		})
+	}
+}
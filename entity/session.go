@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// UnknownSessionID is the bucket used for requests without a session ID
+const UnknownSessionID = "unknown"
+
+// CountDistinctSessions counts the distinct session IDs across the given requests.
+// Requests with an empty session ID are grouped into a single "unknown" bucket
+// when includeUnknown is true, otherwise they are excluded from the count.
+func CountDistinctSessions(requests []APIRequest, includeUnknown bool) int {
+	sessions := make(map[string]struct{})
+
+	for _, req := range requests {
+		sessionID := req.SessionID()
+		if sessionID == "" {
+			if !includeUnknown {
+				continue
+			}
+			sessionID = UnknownSessionID
+		}
+		sessions[sessionID] = struct{}{}
+	}
+
+	return len(sessions)
+}
+
+// LongestRequestGap returns the longest idle time between consecutive requests, in timestamp order.
+// Returns false with fewer than two requests, as there is no gap to measure.
+func LongestRequestGap(requests []APIRequest) (time.Duration, bool) {
+	if len(requests) < 2 {
+		return 0, false
+	}
+
+	timestamps := make([]time.Time, len(requests))
+	for i, req := range requests {
+		timestamps[i] = req.Timestamp()
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+
+	var longest time.Duration
+	for i := 1; i < len(timestamps); i++ {
+		if gap := timestamps[i].Sub(timestamps[i-1]); gap > longest {
+			longest = gap
+		}
+	}
+
+	return longest, true
+}
@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountDistinctSessions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(sessionID string) APIRequest {
+		return NewAPIRequest(sessionID, now, "claude-3-sonnet", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	}
+
+	tests := []struct {
+		name           string
+		requests       []APIRequest
+		includeUnknown bool
+		want           int
+	}{
+		{
+			name:           "no requests",
+			requests:       nil,
+			includeUnknown: true,
+			want:           0,
+		},
+		{
+			name: "counts each session once",
+			requests: []APIRequest{
+				newRequest("session-a"),
+				newRequest("session-a"),
+				newRequest("session-b"),
+			},
+			includeUnknown: true,
+			want:           2,
+		},
+		{
+			name: "empty session IDs grouped into unknown bucket",
+			requests: []APIRequest{
+				newRequest("session-a"),
+				newRequest(""),
+				newRequest(""),
+			},
+			includeUnknown: true,
+			want:           2,
+		},
+		{
+			name: "empty session IDs excluded",
+			requests: []APIRequest{
+				newRequest("session-a"),
+				newRequest(""),
+				newRequest(""),
+			},
+			includeUnknown: false,
+			want:           1,
+		},
+		{
+			name: "only empty session IDs excluded",
+			requests: []APIRequest{
+				newRequest(""),
+			},
+			includeUnknown: false,
+			want:           0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := CountDistinctSessions(tt.requests, tt.includeUnknown); got != tt.want {
+				t.Errorf("CountDistinctSessions() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLongestRequestGap(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	newRequest := func(offset time.Duration) APIRequest {
+		return NewAPIRequest("session", baseTime.Add(offset), "claude-sonnet-4", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	}
+
+	tests := []struct {
+		name          string
+		requests      []APIRequest
+		want          time.Duration
+		wantAvailable bool
+	}{
+		{
+			name:     "no requests",
+			requests: []APIRequest{},
+		},
+		{
+			name:     "single request has no gap",
+			requests: []APIRequest{newRequest(0)},
+		},
+		{
+			name: "unordered requests are sorted first",
+			requests: []APIRequest{
+				newRequest(3 * time.Hour),
+				newRequest(0),
+				newRequest(10 * time.Minute),
+				newRequest(20 * time.Minute),
+			},
+			want:          2*time.Hour + 40*time.Minute,
+			wantAvailable: true,
+		},
+		{
+			name:          "simultaneous requests have a zero gap",
+			requests:      []APIRequest{newRequest(0), newRequest(0)},
+			want:          0,
+			wantAvailable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, available := LongestRequestGap(tt.requests)
+			if available != tt.wantAvailable {
+				t.Fatalf("LongestRequestGap() available = %v, want %v", available, tt.wantAvailable)
+			}
+			if got != tt.want {
+				t.Errorf("LongestRequestGap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
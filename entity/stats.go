@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 // Stats represents aggregated statistics for API requests
 type Stats struct {
 	baseRequests    int
@@ -9,6 +11,19 @@ type Stats struct {
 	baseCost        Cost
 	premiumCost     Cost
 	period          Period
+
+	// Requests below the negligible cost threshold, also counted in their tier
+	negligibleRequests int
+	negligibleTokens   Token
+	negligibleCost     Cost
+
+	// Distinct session IDs, and whether requests without one exist
+	sessions       int
+	unknownSession bool
+
+	// Longest idle time between consecutive requests, unavailable with fewer than two requests
+	longestGap          time.Duration
+	longestGapAvailable bool
 }
 
 // BaseRequests returns the number of base model requests
@@ -56,11 +71,109 @@ func (s Stats) TotalCost() Cost {
 	return s.baseCost.Add(s.premiumCost)
 }
 
+// NegligibleRequests returns the number of requests below the negligible cost threshold
+func (s Stats) NegligibleRequests() int {
+	return s.negligibleRequests
+}
+
+// NegligibleTokens returns the token usage of requests below the negligible cost threshold
+func (s Stats) NegligibleTokens() Token {
+	return s.negligibleTokens
+}
+
+// NegligibleCost returns the cost of requests below the negligible cost threshold
+func (s Stats) NegligibleCost() Cost {
+	return s.negligibleCost
+}
+
+// WithNegligible returns a copy of the stats with the requests cheaper than the threshold
+// counted in the negligible bucket. The tier totals are unchanged, and a non-positive
+// threshold leaves the bucket empty.
+func (s Stats) WithNegligible(requests []APIRequest, threshold Cost) Stats {
+	s.negligibleRequests = 0
+	s.negligibleTokens = Token{}
+	s.negligibleCost = Cost{}
+
+	_, negligible := SplitNegligible(requests, threshold)
+	for _, req := range negligible {
+		s.negligibleRequests++
+		s.negligibleTokens = s.negligibleTokens.Add(req.Tokens())
+		s.negligibleCost = s.negligibleCost.Add(req.Cost())
+	}
+	return s
+}
+
+// WithNegligibleTotals returns a copy of the stats with the given negligible bucket, used to
+// restore stats calculated elsewhere, e.g. by the server or in a cache file
+func (s Stats) WithNegligibleTotals(requests int, tokens Token, cost Cost) Stats {
+	s.negligibleRequests = requests
+	s.negligibleTokens = tokens
+	s.negligibleCost = cost
+	return s
+}
+
+// Sessions returns the number of distinct sessions. Requests without a session ID are counted
+// as a single "unknown" session when includeUnknown is true, otherwise they are left out.
+func (s Stats) Sessions(includeUnknown bool) int {
+	if includeUnknown && s.unknownSession {
+		return s.sessions + 1
+	}
+	return s.sessions
+}
+
+// HasUnknownSession returns true if some requests have no session ID
+func (s Stats) HasUnknownSession() bool {
+	return s.unknownSession
+}
+
+// WithSessions returns a copy of the stats with the given session count, used to restore stats
+// calculated elsewhere, e.g. by the server or in a cache file
+func (s Stats) WithSessions(sessions int, unknownSession bool) Stats {
+	s.sessions = sessions
+	s.unknownSession = unknownSession
+	return s
+}
+
+// LongestGap returns the longest idle time between consecutive requests.
+// Returns false with fewer than two requests, as there is no gap to measure.
+func (s Stats) LongestGap() (time.Duration, bool) {
+	return s.longestGap, s.longestGapAvailable
+}
+
+// WithLongestGap returns a copy of the stats with the given longest gap, used to restore stats
+// calculated elsewhere, e.g. by the server or in a cache file
+func (s Stats) WithLongestGap(gap time.Duration, available bool) Stats {
+	s.longestGap = gap
+	s.longestGapAvailable = available
+	return s
+}
+
 // Period returns the time period for these statistics
 func (s Stats) Period() Period {
 	return s.period
 }
 
+// TokensPerDollar returns the total tokens bought by each dollar spent
+// Returns false when nothing was spent, as the ratio is undefined
+func (s Stats) TokensPerDollar() (float64, bool) {
+	cost := s.TotalCost().Amount()
+	if cost <= 0 {
+		return 0, false
+	}
+
+	return float64(s.TotalTokens().Total()) / cost, true
+}
+
+// CostPerSession returns the average total cost of each of the given sessions
+// Returns false when there are no sessions, as the average is undefined
+func (s Stats) CostPerSession(sessions int) (Cost, bool) {
+	if sessions <= 0 {
+		return NewCost(0), false
+	}
+
+	return NewCost(s.TotalCost().Amount() / float64(sessions)), true
+}
+
 // PremiumTokenBurnRate returns the premium token consumption rate per minute
 // Returns 0 for all-time periods or zero duration periods
 func (s Stats) PremiumTokenBurnRate() float64 {
@@ -100,12 +213,18 @@ func NewStats(baseRequests, premiumRequests int, baseTokens, premiumTokens Token
 
 // NewStatsFromRequests calculates statistics from a list of API requests
 func NewStatsFromRequests(requests []APIRequest, period Period) Stats {
+	return NewClassifiedStatsFromRequests(requests, period, ModelClassifier{})
+}
+
+// NewClassifiedStatsFromRequests calculates statistics, assigning tiers with the classifier
+func NewClassifiedStatsFromRequests(requests []APIRequest, period Period, classifier ModelClassifier) Stats {
 	var baseRequests, premiumRequests int
 	var baseTokens, premiumTokens Token
 	var baseCost, premiumCost Cost
 
 	for _, req := range requests {
-		if req.Model().IsBase() {
+		// Opus is premium usage, only listed on its own when reviewing tiers
+		if !classifier.Classify(req.Model()).IsPremium() {
 			baseRequests++
 			baseTokens = baseTokens.Add(req.Tokens())
 			baseCost = baseCost.Add(req.Cost())
@@ -116,7 +235,7 @@ func NewStatsFromRequests(requests []APIRequest, period Period) Stats {
 		}
 	}
 
-	return NewStats(
+	stats := NewStats(
 		baseRequests,
 		premiumRequests,
 		baseTokens,
@@ -125,4 +244,10 @@ func NewStatsFromRequests(requests []APIRequest, period Period) Stats {
 		premiumCost,
 		period,
 	)
+
+	// Counted alongside the totals, so a session count or gap never needs the requests again
+	knownSessions := CountDistinctSessions(requests, false)
+	longestGap, longestGapAvailable := LongestRequestGap(requests)
+	return stats.WithSessions(knownSessions, CountDistinctSessions(requests, true) > knownSessions).
+		WithLongestGap(longestGap, longestGapAvailable)
 }
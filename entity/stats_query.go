@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// StatsQuery identifies a statistics calculation: the period and the options that change its result.
+// Two queries with the same key always produce the same stats, so caches use the key to store them.
+type StatsQuery struct {
+	period         Period
+	negligibleCost Cost
+	classifier     ModelClassifier
+}
+
+// NewStatsQuery creates a query for the stats of the period without any options
+func NewStatsQuery(period Period) StatsQuery {
+	return StatsQuery{period: period}
+}
+
+// Period returns the period to calculate the stats for
+func (q StatsQuery) Period() Period {
+	return q.period
+}
+
+// NegligibleCost returns the threshold below which requests are counted in the negligible bucket
+func (q StatsQuery) NegligibleCost() Cost {
+	return q.negligibleCost
+}
+
+// WithNegligibleCost returns a copy of the query counting requests cheaper than the threshold
+// in the negligible bucket; a non-positive threshold disables the bucket
+func (q StatsQuery) WithNegligibleCost(threshold Cost) StatsQuery {
+	if threshold.Amount() <= 0 {
+		threshold = Cost{}
+	}
+	q.negligibleCost = threshold
+	return q
+}
+
+// Classifier returns the model tier overrides to aggregate with, on top of the repository's own
+func (q StatsQuery) Classifier() ModelClassifier {
+	return q.classifier
+}
+
+// WithClassifier returns a copy of the query assigning tiers with the classifier's overrides
+func (q StatsQuery) WithClassifier(classifier ModelClassifier) StatsQuery {
+	q.classifier = classifier
+	return q
+}
+
+// WithPeriod returns a copy of the query for another period, keeping the options
+func (q StatsQuery) WithPeriod(period Period) StatsQuery {
+	q.period = period
+	return q
+}
+
+// Key returns a file name safe identifier of the query, made of the period timestamps and
+// the options that are set (e.g., 1700000000000000000_1700003600000000000_n10000_t1a2b3c4d)
+func (q StatsQuery) Key() string {
+	key := fmt.Sprintf("%d_%d", q.period.StartAt().UnixNano(), q.period.EndAt().UnixNano())
+	if q.negligibleCost.Amount() > 0 {
+		// The threshold in micro dollars keeps the key free of decimal points
+		key += fmt.Sprintf("_n%d", int64(math.Round(q.negligibleCost.Amount()*1e6)))
+	}
+	if q.classifier.HasOverrides() {
+		key += fmt.Sprintf("_t%08x", overridesHash(q.classifier.Overrides()))
+	}
+	return key
+}
+
+// overridesHash returns a hash of the overrides that does not depend on the map order
+func overridesHash(overrides map[string]ModelTier) uint32 {
+	models := make([]string, 0, len(overrides))
+	for model := range overrides {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	hash := fnv.New32a()
+	for _, model := range models {
+		fmt.Fprintf(hash, "%s=%s\n", model, overrides[model])
+	}
+	return hash.Sum32()
+}
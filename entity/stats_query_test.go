@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsQuery_Key(t *testing.T) {
+	t.Parallel()
+
+	period := NewPeriod(time.Unix(100, 0), time.Unix(200, 0))
+
+	tests := []struct {
+		name  string
+		query StatsQuery
+		want  string
+	}{
+		{name: "period only", query: NewStatsQuery(period), want: "100000000000_200000000000"},
+		{name: "negligible threshold", query: NewStatsQuery(period).WithNegligibleCost(NewCost(0.01)), want: "100000000000_200000000000_n10000"},
+		{name: "non-positive threshold is unset", query: NewStatsQuery(period).WithNegligibleCost(NewCost(-1)), want: "100000000000_200000000000"},
+		{name: "period replaced", query: NewStatsQuery(period).WithNegligibleCost(NewCost(0.5)).WithPeriod(NewPeriod(time.Unix(0, 0), time.Unix(1, 0))), want: "0_1000000000_n500000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.query.Key(); got != tt.want {
+				t.Errorf("Key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsQuery_KeyClassifier(t *testing.T) {
+	t.Parallel()
+
+	period := NewPeriod(time.Unix(100, 0), time.Unix(200, 0))
+	base := NewStatsQuery(period)
+	llama := base.WithClassifier(NewModelClassifier(map[string]ModelTier{"local-llama": ModelTierBase, "local-mistral": ModelTierOpus}))
+	same := base.WithClassifier(NewModelClassifier(map[string]ModelTier{"local-mistral": ModelTierOpus, "Local-Llama": ModelTierBase}))
+	premium := base.WithClassifier(NewModelClassifier(map[string]ModelTier{"local-llama": ModelTierPremium, "local-mistral": ModelTierOpus}))
+
+	if base.WithClassifier(NewModelClassifier(nil)).Key() != base.Key() {
+		t.Error("expected a classifier without overrides to keep the key")
+	}
+	if !strings.HasPrefix(llama.Key(), base.Key()+"_t") {
+		t.Errorf("expected the overrides in the key, got %q", llama.Key())
+	}
+	if llama.Key() != same.Key() {
+		t.Errorf("expected the same overrides to share a key, got %q and %q", llama.Key(), same.Key())
+	}
+	if llama.Key() == premium.Key() {
+		t.Error("expected different overrides to have different keys")
+	}
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -160,3 +161,201 @@ func TestNewStatsFromRequests_ModelClassification(t *testing.T) {
 		})
 	}
 }
+
+func TestStats_TokensPerDollar(t *testing.T) {
+	t.Parallel()
+
+	period := NewPeriod(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name   string
+		stats  Stats
+		want   float64
+		wantOK bool
+	}{
+		{
+			name:   "tokens across tiers over total cost",
+			stats:  NewStats(1, 1, NewToken(1000, 500, 0, 0), NewToken(2000, 500, 1000, 0), NewCost(0.5), NewCost(1.5), period),
+			want:   2500,
+			wantOK: true,
+		},
+		{
+			name:   "zero cost is undefined",
+			stats:  NewStats(1, 0, NewToken(1000, 500, 0, 0), NewToken(0, 0, 0, 0), NewCost(0), NewCost(0), period),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := tt.stats.TokensPerDollar()
+			if ok != tt.wantOK {
+				t.Fatalf("TokensPerDollar() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("TokensPerDollar() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStats_CostPerSession(t *testing.T) {
+	t.Parallel()
+
+	period := NewPeriod(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	stats := NewStats(1, 2, NewToken(1000, 500, 0, 0), NewToken(2000, 500, 0, 0), NewCost(0.5), NewCost(4), period)
+
+	tests := []struct {
+		name     string
+		sessions int
+		want     float64
+		wantOK   bool
+	}{
+		{
+			name:     "total cost over sessions",
+			sessions: 3,
+			want:     1.5,
+			wantOK:   true,
+		},
+		{
+			name:     "single session",
+			sessions: 1,
+			want:     4.5,
+			wantOK:   true,
+		},
+		{
+			name:     "zero sessions is undefined",
+			sessions: 0,
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := stats.CostPerSession(tt.sessions)
+			if ok != tt.wantOK {
+				t.Fatalf("CostPerSession() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got.Amount() != tt.want {
+				t.Errorf("CostPerSession() = %v, want %v", got.Amount(), tt.want)
+			}
+		})
+	}
+}
+
+func TestStats_WithNegligible(t *testing.T) {
+	t.Parallel()
+
+	period := NewPeriod(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	timestamp := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []APIRequest{
+		NewAPIRequest("session", timestamp, "claude-3-haiku", NewToken(100, 50, 0, 0), NewCost(0.001), 1000),
+		NewAPIRequest("session", timestamp, "claude-3-sonnet", NewToken(200, 100, 0, 0), NewCost(0.004), 1000),
+		NewAPIRequest("session", timestamp, "claude-3-sonnet", NewToken(1000, 500, 0, 0), NewCost(0.5), 1000),
+	}
+	stats := NewStatsFromRequests(requests, period)
+
+	tests := []struct {
+		name         string
+		threshold    float64
+		wantRequests int
+		wantTokens   int64
+		wantCost     float64
+	}{
+		{name: "zero threshold buckets nothing", threshold: 0, wantRequests: 0, wantTokens: 0, wantCost: 0},
+		{name: "threshold is exclusive", threshold: 0.004, wantRequests: 1, wantTokens: 150, wantCost: 0.001},
+		{name: "cheap requests across tiers", threshold: 0.01, wantRequests: 2, wantTokens: 450, wantCost: 0.005},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := stats.WithNegligible(requests, NewCost(tt.threshold))
+			if got.NegligibleRequests() != tt.wantRequests {
+				t.Errorf("NegligibleRequests() = %d, want %d", got.NegligibleRequests(), tt.wantRequests)
+			}
+			if got.NegligibleTokens().Total() != tt.wantTokens {
+				t.Errorf("NegligibleTokens().Total() = %d, want %d", got.NegligibleTokens().Total(), tt.wantTokens)
+			}
+			if math.Abs(got.NegligibleCost().Amount()-tt.wantCost) > 1e-9 {
+				t.Errorf("NegligibleCost() = %v, want %v", got.NegligibleCost().Amount(), tt.wantCost)
+			}
+			// The bucket is a classification, the tier totals still count every request
+			if got.TotalRequests() != stats.TotalRequests() || got.TotalCost() != stats.TotalCost() {
+				t.Errorf("totals changed to %d requests, $%v", got.TotalRequests(), got.TotalCost().Amount())
+			}
+		})
+	}
+}
+
+func TestStats_Sessions(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	period := NewPeriod(baseTime, baseTime.Add(24*time.Hour))
+	request := func(sessionID string) APIRequest {
+		return NewAPIRequest(sessionID, baseTime, "claude-sonnet-4-20250514", NewToken(10, 5, 0, 0), NewCost(0.01), 100)
+	}
+
+	tests := []struct {
+		name            string
+		requests        []APIRequest
+		wantKnown       int
+		wantWithUnknown int
+	}{
+		{name: "no requests", requests: nil, wantKnown: 0, wantWithUnknown: 0},
+		{name: "repeated sessions count once", requests: []APIRequest{request("a"), request("a"), request("b")}, wantKnown: 2, wantWithUnknown: 2},
+		{name: "requests without a session form one unknown session", requests: []APIRequest{request("a"), request(""), request("")}, wantKnown: 1, wantWithUnknown: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stats := NewStatsFromRequests(tt.requests, period)
+			if got := stats.Sessions(false); got != tt.wantKnown {
+				t.Errorf("Sessions(false) = %d, want %d", got, tt.wantKnown)
+			}
+			if got := stats.Sessions(true); got != tt.wantWithUnknown {
+				t.Errorf("Sessions(true) = %d, want %d", got, tt.wantWithUnknown)
+			}
+		})
+	}
+}
+
+func TestStats_LongestGap(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	period := NewPeriod(baseTime, baseTime.Add(24*time.Hour))
+	request := func(offset time.Duration) APIRequest {
+		return NewAPIRequest("a", baseTime.Add(offset), "claude-sonnet-4-20250514", NewToken(10, 5, 0, 0), NewCost(0.01), 100)
+	}
+
+	tests := []struct {
+		name          string
+		requests      []APIRequest
+		wantGap       time.Duration
+		wantAvailable bool
+	}{
+		{name: "no requests", requests: nil},
+		{name: "single request", requests: []APIRequest{request(0)}},
+		{name: "out of order requests", requests: []APIRequest{request(3 * time.Hour), request(0), request(30 * time.Minute)}, wantGap: 150 * time.Minute, wantAvailable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gap, available := NewStatsFromRequests(tt.requests, period).LongestGap()
+			if gap != tt.wantGap || available != tt.wantAvailable {
+				t.Errorf("LongestGap() = %v, %v, want %v, %v", gap, available, tt.wantGap, tt.wantAvailable)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// StorageInfo describes how much the store holds, to watch its growth and decide when to prune
+type StorageInfo struct {
+	sizeBytes int64
+	records   int
+	oldestAt  time.Time
+	newestAt  time.Time
+}
+
+// NewStorageInfo creates a storage description; the timestamps are zero when no request is stored
+func NewStorageInfo(sizeBytes int64, records int, oldestAt, newestAt time.Time) StorageInfo {
+	return StorageInfo{
+		sizeBytes: sizeBytes,
+		records:   records,
+		oldestAt:  oldestAt,
+		newestAt:  newestAt,
+	}
+}
+
+// SizeBytes returns the size of the store on disk
+func (i StorageInfo) SizeBytes() int64 {
+	return i.sizeBytes
+}
+
+// Records returns the number of stored requests
+func (i StorageInfo) Records() int {
+	return i.records
+}
+
+// OldestAt returns the timestamp of the oldest stored request
+func (i StorageInfo) OldestAt() time.Time {
+	return i.oldestAt
+}
+
+// NewestAt returns the timestamp of the newest stored request
+func (i StorageInfo) NewestAt() time.Time {
+	return i.newestAt
+}
+
+// IsEmpty returns true if no request is stored
+func (i StorageInfo) IsEmpty() bool {
+	return i.records == 0
+}
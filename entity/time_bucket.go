@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeBucket is the calendar granularity used to group requests over time
+type TimeBucket string
+
+const (
+	TimeBucketHour TimeBucket = "hour"
+	TimeBucketDay  TimeBucket = "day"
+	TimeBucketWeek TimeBucket = "week" // Weeks start on Monday
+)
+
+// ParseTimeBucket validates a bucket name
+func ParseTimeBucket(value string) (TimeBucket, error) {
+	switch TimeBucket(strings.ToLower(strings.TrimSpace(value))) {
+	case TimeBucketHour:
+		return TimeBucketHour, nil
+	case TimeBucketDay:
+		return TimeBucketDay, nil
+	case TimeBucketWeek:
+		return TimeBucketWeek, nil
+	default:
+		return "", fmt.Errorf("invalid time bucket %q (expected hour, day or week)", value)
+	}
+}
+
+// Start returns the start of the bucket containing t in the timezone.
+// Days and weeks follow the local calendar, so they span 23 or 25 hours across DST changes.
+func (b TimeBucket) Start(t time.Time, timezone *time.Location) time.Time {
+	local := t.In(timezone)
+	switch b {
+	case TimeBucketHour:
+		return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, timezone)
+	case TimeBucketWeek:
+		daysSinceMonday := (int(local.Weekday()) + 6) % 7
+		return time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, 0, 0, 0, 0, timezone)
+	default:
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, timezone)
+	}
+}
+
+// Next returns the start of the bucket following the one starting at start
+func (b TimeBucket) Next(start time.Time) time.Time {
+	switch b {
+	case TimeBucketHour:
+		return start.Add(time.Hour)
+	case TimeBucketWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
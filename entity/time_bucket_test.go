@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeBucket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected TimeBucket
+		wantErr  bool
+	}{
+		{input: "hour", expected: TimeBucketHour},
+		{input: " Day ", expected: TimeBucketDay},
+		{input: "WEEK", expected: TimeBucketWeek},
+		{input: "month", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseTimeBucket(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTimeBucket(%q) expected an error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeBucket(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseTimeBucket(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTimeBucket_StartAndNext(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		bucket        TimeBucket
+		at            time.Time
+		expectedStart time.Time
+		expectedNext  time.Time
+	}{
+		{
+			name:          "hour truncates minutes",
+			bucket:        TimeBucketHour,
+			at:            time.Date(2025, 7, 24, 14, 35, 10, 0, time.UTC),
+			expectedStart: time.Date(2025, 7, 24, 14, 0, 0, 0, time.UTC),
+			expectedNext:  time.Date(2025, 7, 24, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "day starts at local midnight",
+			bucket:        TimeBucketDay,
+			at:            time.Date(2025, 7, 24, 2, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 7, 23, 0, 0, 0, 0, newYork),
+			expectedNext:  time.Date(2025, 7, 24, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:          "day spans 23 hours on spring forward",
+			bucket:        TimeBucketDay,
+			at:            time.Date(2025, 3, 9, 12, 0, 0, 0, newYork),
+			expectedStart: time.Date(2025, 3, 9, 0, 0, 0, 0, newYork),
+			expectedNext:  time.Date(2025, 3, 10, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:          "week starts on monday",
+			bucket:        TimeBucketWeek,
+			at:            time.Date(2025, 7, 27, 23, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC),
+			expectedNext:  time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			location := tt.expectedStart.Location()
+			start := tt.bucket.Start(tt.at, location)
+			if !start.Equal(tt.expectedStart) {
+				t.Errorf("Start() = %v, want %v", start, tt.expectedStart)
+			}
+			if next := tt.bucket.Next(start); !next.Equal(tt.expectedNext) {
+				t.Errorf("Next() = %v, want %v", next, tt.expectedNext)
+			}
+		})
+	}
+
+	// The spring forward day is one hour short
+	start := TimeBucketDay.Start(time.Date(2025, 3, 9, 12, 0, 0, 0, newYork), newYork)
+	if length := TimeBucketDay.Next(start).Sub(start); length != 23*time.Hour {
+		t.Errorf("expected a 23 hour day, got %v", length)
+	}
+}
@@ -1,5 +1,12 @@
 package entity
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
 // Token represents token usage for an API request
 type Token struct {
 	input         int64
@@ -62,3 +69,44 @@ func (t Token) Add(other Token) Token {
 		cacheCreation: t.cacheCreation + other.cacheCreation,
 	}
 }
+
+// ParseTokenCount parses a token count written as a plain integer or with a k or M suffix, e.g. "44000",
+// "44k" or "1.2M", the input side of NumberLocale.FormatTokenCount. The result must be a whole number.
+func ParseTokenCount(value string) (int64, error) {
+	text := strings.TrimSpace(value)
+	if count, err := strconv.ParseInt(text, 10, 64); err == nil {
+		if count < 0 {
+			return 0, fmt.Errorf("token count %q must not be negative", value)
+		}
+		return count, nil
+	}
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(text, "k"), strings.HasSuffix(text, "K"):
+		multiplier = 1_000
+	case strings.HasSuffix(text, "m"), strings.HasSuffix(text, "M"):
+		multiplier = 1_000_000
+	default:
+		return 0, fmt.Errorf("invalid token count %q (use a whole number or a k/M suffix, e.g. 44000, 44k or 1.2M)", value)
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(text[:len(text)-1]), 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("invalid token count %q (use a whole number or a k/M suffix, e.g. 44000, 44k or 1.2M)", value)
+	}
+	if number < 0 {
+		return 0, fmt.Errorf("token count %q must not be negative", value)
+	}
+
+	// Round away float error such as 1.2 * 1e6 = 1199999.9999999998, then require a whole count
+	scaled := number * multiplier
+	count := math.Round(scaled)
+	if math.Abs(scaled-count) > 1e-6 {
+		return 0, fmt.Errorf("token count %q is not a whole number of tokens", value)
+	}
+	if count >= math.MaxInt64 {
+		return 0, fmt.Errorf("token count %q is too large", value)
+	}
+	return int64(count), nil
+}
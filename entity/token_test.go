@@ -0,0 +1,50 @@
+package entity
+
+import "testing"
+
+func TestParseTokenCount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "44000", expected: 44_000},
+		{input: "0", expected: 0},
+		{input: " 7000 ", expected: 7_000},
+		{input: "44k", expected: 44_000},
+		{input: "44K", expected: 44_000},
+		{input: "1.5k", expected: 1_500},
+		{input: "1.2M", expected: 1_200_000},
+		{input: "2m", expected: 2_000_000},
+		{input: "0.35M", expected: 350_000},
+		{input: "1.2345k", wantErr: true},
+		{input: "-5", wantErr: true},
+		{input: "-1k", wantErr: true},
+		{input: "44x", wantErr: true},
+		{input: "k", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "1e30M", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			count, err := ParseTokenCount(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTokenCount(%q) expected error, got %d", tt.input, count)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTokenCount(%q) unexpected error: %v", tt.input, err)
+			}
+			if count != tt.expected {
+				t.Errorf("ParseTokenCount(%q) = %d, want %d", tt.input, count, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UsageGauge identifies a usage value pushed as a gauge to a metrics pipeline such as StatsD
+type UsageGauge struct {
+	key string
+}
+
+// Predefined usage gauges
+var (
+	DailyCostGauge       = UsageGauge{key: "daily_cost"}       // USD spent today
+	MonthlyCostGauge     = UsageGauge{key: "monthly_cost"}     // USD spent this month
+	DailyRequestsGauge   = UsageGauge{key: "daily_requests"}   // Requests made today
+	MonthlyRequestsGauge = UsageGauge{key: "monthly_requests"} // Requests made this month
+	BlockTokensGauge     = UsageGauge{key: "block_tokens"}     // Premium tokens counted against the current block limit
+	BlockUsageGauge      = UsageGauge{key: "block_usage"}      // Percentage of the current block limit used
+)
+
+// GetAllUsageGauges returns all available usage gauges
+func GetAllUsageGauges() []UsageGauge {
+	return []UsageGauge{
+		DailyCostGauge,
+		MonthlyCostGauge,
+		DailyRequestsGauge,
+		MonthlyRequestsGauge,
+		BlockTokensGauge,
+		BlockUsageGauge,
+	}
+}
+
+// ParseUsageGauge finds the usage gauge with the given key, e.g. "daily_cost"
+func ParseUsageGauge(key string) (UsageGauge, error) {
+	normalized := strings.ToLower(strings.TrimSpace(key))
+	for _, gauge := range GetAllUsageGauges() {
+		if gauge.key == normalized {
+			return gauge, nil
+		}
+	}
+
+	keys := make([]string, 0, len(GetAllUsageGauges()))
+	for _, gauge := range GetAllUsageGauges() {
+		keys = append(keys, gauge.key)
+	}
+	return UsageGauge{}, fmt.Errorf("unknown usage gauge %q (valid: %s)", key, strings.Join(keys, ", "))
+}
+
+// Key returns the gauge key, also its default metric name
+func (g UsageGauge) Key() string {
+	return g.key
+}
+
+// GaugeValue is a usage gauge measured at publish time
+type GaugeValue struct {
+	gauge UsageGauge
+	value float64
+}
+
+// NewGaugeValue creates a measured value of the gauge
+func NewGaugeValue(gauge UsageGauge, value float64) GaugeValue {
+	return GaugeValue{gauge: gauge, value: value}
+}
+
+// Gauge returns the measured gauge
+func (v GaugeValue) Gauge() UsageGauge {
+	return v.gauge
+}
+
+// Value returns the measured value
+func (v GaugeValue) Value() float64 {
+	return v.value
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "testing"
+
+func TestParseUsageGauge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key     string
+		want    UsageGauge
+		wantErr bool
+	}{
+		{key: "daily_cost", want: DailyCostGauge},
+		{key: "monthly_requests", want: MonthlyRequestsGauge},
+		{key: " Block_Usage ", want: BlockUsageGauge},
+		{key: "daily_tokens", wantErr: true},
+		{key: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseUsageGauge(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseUsageGauge(%q) expected error but got none", tt.key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUsageGauge(%q) unexpected error = %v", tt.key, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseUsageGauge(%q) = %v, want %v", tt.key, got.Key(), tt.want.Key())
+			}
+		})
+	}
+}
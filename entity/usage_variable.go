@@ -8,10 +8,31 @@ type UsageVariable struct {
 
 // Predefined variables for usage queries
 var (
-	DailyCostVariable        = UsageVariable{name: "Daily Cost", key: "@daily_cost"}
-	MonthlyCostVariable      = UsageVariable{name: "Monthly Cost", key: "@monthly_cost"}
-	DailyPlanUsageVariable   = UsageVariable{name: "Daily Plan Usage", key: "@daily_plan_usage"}
-	MonthlyPlanUsageVariable = UsageVariable{name: "Monthly Plan Usage", key: "@monthly_plan_usage"}
+	DailyCostVariable            = UsageVariable{name: "Daily Cost", key: "@daily_cost"}
+	MonthlyCostVariable          = UsageVariable{name: "Monthly Cost", key: "@monthly_cost"}
+	DailyPlanUsageVariable       = UsageVariable{name: "Daily Plan Usage", key: "@daily_plan_usage"}
+	MonthlyPlanUsageVariable     = UsageVariable{name: "Monthly Plan Usage", key: "@monthly_plan_usage"}
+	DailySessionsVariable        = UsageVariable{name: "Daily Sessions", key: "@daily_sessions"}
+	MonthlySessionsVariable      = UsageVariable{name: "Monthly Sessions", key: "@monthly_sessions"}
+	DailyCacheSavingsVariable    = UsageVariable{name: "Daily Cache Savings", key: "@daily_cache_savings"}
+	DailyTokensPerDollarVariable = UsageVariable{name: "Daily Tokens per Dollar", key: "@daily_tokens_per_dollar"}
+	DailyCostPerSessionVariable  = UsageVariable{name: "Daily Cost per Session", key: "@daily_cost_per_session"}
+	DailyMaxGapVariable          = UsageVariable{name: "Daily Max Gap", key: "@daily_max_gap"}
+	TimezoneVariable             = UsageVariable{name: "Timezone", key: "@timezone"}
+	Rolling7dCostVariable        = UsageVariable{name: "Rolling 7 Day Cost", key: "@rolling_7d_cost"}
+	Rolling30dCostVariable       = UsageVariable{name: "Rolling 30 Day Cost", key: "@rolling_30d_cost"}
+	ScheduleCostVariable         = UsageVariable{name: "Schedule Cost", key: "@schedule_cost"}
+	BlockUsedVariable            = UsageVariable{name: "Block Used", key: "@block_used"}
+	BlockLimitVariable           = UsageVariable{name: "Block Limit", key: "@block_limit"}
+	BaseCostVariable             = UsageVariable{name: "Base Cost", key: "@base_cost"}
+	PremiumCostVariable          = UsageVariable{name: "Premium Cost", key: "@premium_cost"}
+	DailyBaseCostVariable        = UsageVariable{name: "Daily Base Cost", key: "@daily_base_cost"}
+	DailyPremiumCostVariable     = UsageVariable{name: "Daily Premium Cost", key: "@daily_premium_cost"}
+	MonthlyBaseCostVariable      = UsageVariable{name: "Monthly Base Cost", key: "@monthly_base_cost"}
+	MonthlyPremiumCostVariable   = UsageVariable{name: "Monthly Premium Cost", key: "@monthly_premium_cost"}
+	BlockUsageVariable           = UsageVariable{name: "Block Usage", key: "@block_usage"}
+	BlockRemainingVariable       = UsageVariable{name: "Block Remaining", key: "@block_remaining"}
+	BlockTimeRemainingVariable   = UsageVariable{name: "Block Time Remaining", key: "@block_time_remaining"}
 )
 
 // GetAllUsageVariables returns all available predefined variables
@@ -21,6 +42,27 @@ func GetAllUsageVariables() []UsageVariable {
 		MonthlyCostVariable,
 		DailyPlanUsageVariable,
 		MonthlyPlanUsageVariable,
+		DailySessionsVariable,
+		MonthlySessionsVariable,
+		DailyCacheSavingsVariable,
+		DailyTokensPerDollarVariable,
+		DailyCostPerSessionVariable,
+		DailyMaxGapVariable,
+		TimezoneVariable,
+		Rolling7dCostVariable,
+		Rolling30dCostVariable,
+		ScheduleCostVariable,
+		BlockUsedVariable,
+		BlockLimitVariable,
+		BaseCostVariable,
+		PremiumCostVariable,
+		DailyBaseCostVariable,
+		DailyPremiumCostVariable,
+		MonthlyBaseCostVariable,
+		MonthlyPremiumCostVariable,
+		BlockUsageVariable,
+		BlockRemainingVariable,
+		BlockTimeRemainingVariable,
 	}
 }
 
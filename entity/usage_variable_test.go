@@ -35,6 +35,132 @@ func TestUsageVariableDefinitions(t *testing.T) {
 			wantKey:  "@monthly_plan_usage",
 			wantName: "Monthly Plan Usage",
 		},
+		{
+			name:     "daily sessions variable",
+			variable: DailySessionsVariable,
+			wantKey:  "@daily_sessions",
+			wantName: "Daily Sessions",
+		},
+		{
+			name:     "monthly sessions variable",
+			variable: MonthlySessionsVariable,
+			wantKey:  "@monthly_sessions",
+			wantName: "Monthly Sessions",
+		},
+		{
+			name:     "daily cache savings variable",
+			variable: DailyCacheSavingsVariable,
+			wantKey:  "@daily_cache_savings",
+			wantName: "Daily Cache Savings",
+		},
+		{
+			name:     "daily tokens per dollar variable",
+			variable: DailyTokensPerDollarVariable,
+			wantKey:  "@daily_tokens_per_dollar",
+			wantName: "Daily Tokens per Dollar",
+		},
+		{
+			name:     "daily cost per session variable",
+			variable: DailyCostPerSessionVariable,
+			wantKey:  "@daily_cost_per_session",
+			wantName: "Daily Cost per Session",
+		},
+		{
+			name:     "daily max gap variable",
+			variable: DailyMaxGapVariable,
+			wantKey:  "@daily_max_gap",
+			wantName: "Daily Max Gap",
+		},
+		{
+			name:     "timezone variable",
+			variable: TimezoneVariable,
+			wantKey:  "@timezone",
+			wantName: "Timezone",
+		},
+		{
+			name:     "rolling 7 day cost variable",
+			variable: Rolling7dCostVariable,
+			wantKey:  "@rolling_7d_cost",
+			wantName: "Rolling 7 Day Cost",
+		},
+		{
+			name:     "rolling 30 day cost variable",
+			variable: Rolling30dCostVariable,
+			wantKey:  "@rolling_30d_cost",
+			wantName: "Rolling 30 Day Cost",
+		},
+		{
+			name:     "schedule cost variable",
+			variable: ScheduleCostVariable,
+			wantKey:  "@schedule_cost",
+			wantName: "Schedule Cost",
+		},
+		{
+			name:     "block used variable",
+			variable: BlockUsedVariable,
+			wantKey:  "@block_used",
+			wantName: "Block Used",
+		},
+		{
+			name:     "block limit variable",
+			variable: BlockLimitVariable,
+			wantKey:  "@block_limit",
+			wantName: "Block Limit",
+		},
+		{
+			name:     "base cost variable",
+			variable: BaseCostVariable,
+			wantKey:  "@base_cost",
+			wantName: "Base Cost",
+		},
+		{
+			name:     "premium cost variable",
+			variable: PremiumCostVariable,
+			wantKey:  "@premium_cost",
+			wantName: "Premium Cost",
+		},
+		{
+			name:     "daily base cost variable",
+			variable: DailyBaseCostVariable,
+			wantKey:  "@daily_base_cost",
+			wantName: "Daily Base Cost",
+		},
+		{
+			name:     "daily premium cost variable",
+			variable: DailyPremiumCostVariable,
+			wantKey:  "@daily_premium_cost",
+			wantName: "Daily Premium Cost",
+		},
+		{
+			name:     "monthly base cost variable",
+			variable: MonthlyBaseCostVariable,
+			wantKey:  "@monthly_base_cost",
+			wantName: "Monthly Base Cost",
+		},
+		{
+			name:     "monthly premium cost variable",
+			variable: MonthlyPremiumCostVariable,
+			wantKey:  "@monthly_premium_cost",
+			wantName: "Monthly Premium Cost",
+		},
+		{
+			name:     "block usage variable",
+			variable: BlockUsageVariable,
+			wantKey:  "@block_usage",
+			wantName: "Block Usage",
+		},
+		{
+			name:     "block remaining variable",
+			variable: BlockRemainingVariable,
+			wantKey:  "@block_remaining",
+			wantName: "Block Remaining",
+		},
+		{
+			name:     "block time remaining variable",
+			variable: BlockTimeRemainingVariable,
+			wantKey:  "@block_time_remaining",
+			wantName: "Block Time Remaining",
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,15 +178,36 @@ func TestUsageVariableDefinitions(t *testing.T) {
 func TestGetAllUsageVariables(t *testing.T) {
 	variables := GetAllUsageVariables()
 
-	if len(variables) != 4 {
-		t.Errorf("Expected 4 variables, got %d", len(variables))
+	if len(variables) != 25 {
+		t.Errorf("Expected 25 variables, got %d", len(variables))
 	}
 
 	expectedKeys := map[string]bool{
-		"@daily_cost":         false,
-		"@monthly_cost":       false,
-		"@daily_plan_usage":   false,
-		"@monthly_plan_usage": false,
+		"@daily_cost":              false,
+		"@monthly_cost":            false,
+		"@daily_plan_usage":        false,
+		"@monthly_plan_usage":      false,
+		"@daily_sessions":          false,
+		"@monthly_sessions":        false,
+		"@daily_cache_savings":     false,
+		"@daily_tokens_per_dollar": false,
+		"@daily_cost_per_session":  false,
+		"@daily_max_gap":           false,
+		"@timezone":                false,
+		"@rolling_7d_cost":         false,
+		"@rolling_30d_cost":        false,
+		"@schedule_cost":           false,
+		"@block_used":              false,
+		"@block_limit":             false,
+		"@base_cost":               false,
+		"@premium_cost":            false,
+		"@daily_base_cost":         false,
+		"@daily_premium_cost":      false,
+		"@monthly_base_cost":       false,
+		"@monthly_premium_cost":    false,
+		"@block_usage":             false,
+		"@block_remaining":         false,
+		"@block_time_remaining":    false,
 	}
 
 	for _, v := range variables {
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// SnapshotSource streams a consistent database snapshot
+type SnapshotSource interface {
+	Snapshot(ctx context.Context, w io.Writer) (int, error)
+}
+
+// BackupResult describes a written backup
+type BackupResult struct {
+	Records        int
+	Size           int64 // Snapshot bytes before compression
+	CompressedSize int64
+}
+
+type BackupHandler struct {
+	source SnapshotSource
+}
+
+func NewBackupHandler(source SnapshotSource) *BackupHandler {
+	return &BackupHandler{
+		source: source,
+	}
+}
+
+// HandleBackup writes a zstd-compressed snapshot to the output path and reports its size.
+// A failed backup never leaves a partial file behind, nor touches a previous file at the output path.
+func (h *BackupHandler) HandleBackup(output string) error {
+	writer, err := OpenExportOutput(output)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.Backup(writer)
+	if err != nil {
+		writer.Discard()
+		return err
+	}
+	if err := writer.Commit(); err != nil {
+		return err
+	}
+
+	// Report on stderr when stdout carries the backup itself
+	report := os.Stdout
+	if output == "" || output == StdoutPath {
+		report = os.Stderr
+	}
+	fmt.Fprintf(report, "Backed up %d requests: %s compressed (%s uncompressed)\n",
+		result.Records, formatByteSize(result.CompressedSize), formatByteSize(result.Size))
+	return nil
+}
+
+// Backup compresses the snapshot into w
+func (h *BackupHandler) Backup(w io.Writer) (*BackupResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	compressed := &countingWriter{w: w}
+	encoder, err := zstd.NewWriter(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start compression: %w", err)
+	}
+
+	raw := &countingWriter{w: encoder}
+	records, err := h.source.Snapshot(ctx, raw)
+	if err != nil {
+		_ = encoder.Close()
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress backup: %w", err)
+	}
+
+	return &BackupResult{
+		Records:        records,
+		Size:           raw.n,
+		CompressedSize: compressed.n,
+	}, nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// formatByteSize renders a byte count with a binary unit (e.g. 1.5 MiB)
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TiB", value)
+}
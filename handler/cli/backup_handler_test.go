@@ -0,0 +1,240 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elct9620/ccmon/handler/cli"
+)
+
+type fakeSnapshotSource struct {
+	data    []byte
+	records int
+	err     error
+}
+
+func (f *fakeSnapshotSource) Snapshot(ctx context.Context, w io.Writer) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	if _, err := w.Write(f.data); err != nil {
+		return 0, err
+	}
+	return f.records, nil
+}
+
+func TestBackupHandler_Backup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		source      *fakeSnapshotSource
+		expectError bool
+	}{
+		{
+			name:   "compresses the snapshot",
+			source: &fakeSnapshotSource{data: bytes.Repeat([]byte("ccmon snapshot page "), 4096), records: 42},
+		},
+		{
+			name:        "source failure is reported",
+			source:      &fakeSnapshotSource{err: errors.New("Unavailable: connection refused")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			result, err := cli.NewBackupHandler(tt.source).Backup(&buf)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Backup() error = %v", err)
+			}
+
+			if result.Records != tt.source.records {
+				t.Errorf("Records = %d, want %d", result.Records, tt.source.records)
+			}
+			if result.Size != int64(len(tt.source.data)) {
+				t.Errorf("Size = %d, want %d", result.Size, len(tt.source.data))
+			}
+			if result.CompressedSize != int64(buf.Len()) || result.CompressedSize >= result.Size {
+				t.Errorf("CompressedSize = %d, written %d, uncompressed %d", result.CompressedSize, buf.Len(), result.Size)
+			}
+		})
+	}
+}
+
+func TestBackupHandler_HandleBackupFailureKeepsPreviousFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "ccmon.db.zst")
+	if err := os.WriteFile(output, []byte("previous backup"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := cli.NewBackupHandler(&fakeSnapshotSource{err: errors.New("Unavailable: connection refused")})
+	if err := handler.HandleBackup(output); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	if content, _ := os.ReadFile(output); string(content) != "previous backup" {
+		t.Errorf("Expected the previous backup to be kept, got %q", content)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+}
+
+func TestRestoreHandler_Restore(t *testing.T) {
+	t.Parallel()
+
+	snapshot := bytes.Repeat([]byte("ccmon database "), 1024)
+
+	tests := []struct {
+		name          string
+		existing      []byte
+		previousBak   []byte
+		force         bool
+		invalidBackup bool
+		expectError   string
+	}{
+		{
+			name: "restores into a new path",
+		},
+		{
+			name:        "existing database requires force",
+			existing:    []byte("current database"),
+			expectError: "use --force",
+		},
+		{
+			name:     "force keeps the previous database",
+			existing: []byte("current database"),
+			force:    true,
+		},
+		{
+			name:        "force keeps the database moved aside by an earlier restore",
+			existing:    []byte("current database"),
+			previousBak: []byte("older database"),
+			force:       true,
+		},
+		{
+			name:          "invalid backup leaves the database untouched",
+			existing:      []byte("current database"),
+			force:         true,
+			invalidBackup: true,
+			expectError:   "failed to decompress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			backupPath := filepath.Join(dir, "ccmon.db.zst")
+			databasePath := filepath.Join(dir, "data", "ccmon.db")
+
+			if tt.invalidBackup {
+				if err := os.WriteFile(backupPath, []byte("not a zstd stream"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				var buf bytes.Buffer
+				if _, err := cli.NewBackupHandler(&fakeSnapshotSource{data: snapshot, records: 7}).Backup(&buf); err != nil {
+					t.Fatalf("Backup() error = %v", err)
+				}
+				if err := os.WriteFile(backupPath, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.existing != nil {
+				if err := os.MkdirAll(filepath.Dir(databasePath), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(databasePath, tt.existing, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.previousBak != nil {
+				if err := os.WriteFile(databasePath+".bak", tt.previousBak, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Count the restored file by checking it holds the snapshot
+			inspect := func(path string) (int, error) {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return 0, err
+				}
+				if bytes.Equal(content, tt.existing) {
+					return 1, nil
+				}
+				if !bytes.Equal(content, snapshot) {
+					return 0, errors.New("unexpected content")
+				}
+				return 7, nil
+			}
+
+			handler := cli.NewRestoreHandler(inspect)
+			handler.SetForce(tt.force)
+			result, err := handler.Restore(backupPath, databasePath)
+
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Restore() error = %v, want %q", err, tt.expectError)
+				}
+				if content, _ := os.ReadFile(databasePath); !bytes.Equal(content, tt.existing) {
+					t.Error("Expected the existing database to be untouched")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+
+			if result.Records != 7 {
+				t.Errorf("Records = %d, want 7", result.Records)
+			}
+			if content, _ := os.ReadFile(databasePath); !bytes.Equal(content, snapshot) {
+				t.Error("Expected the database to hold the restored snapshot")
+			}
+			if tt.existing != nil {
+				if content, _ := os.ReadFile(result.Replaced); !bytes.Equal(content, tt.existing) {
+					t.Errorf("Expected the previous database at %s", result.Replaced)
+				}
+			}
+			if tt.previousBak != nil {
+				if result.Replaced != databasePath+".bak.1" {
+					t.Errorf("Replaced = %s, want %s.bak.1", result.Replaced, databasePath)
+				}
+				if content, _ := os.ReadFile(databasePath + ".bak"); !bytes.Equal(content, tt.previousBak) {
+					t.Error("Expected the earlier .bak file to be untouched")
+				}
+			}
+
+			// No temporary files are left next to the database
+			entries, _ := os.ReadDir(filepath.Dir(databasePath))
+			for _, entry := range entries {
+				if strings.HasPrefix(entry.Name(), ".ccmon-restore-") {
+					t.Errorf("Unexpected leftover file %s", entry.Name())
+				}
+			}
+		})
+	}
+}
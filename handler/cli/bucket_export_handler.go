@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+type BucketExportHandler struct {
+	statsByBucketQuery *usecase.GetStatsByBucketQuery
+	renderer           *BucketExportRenderer
+	bucket             entity.TimeBucket
+	timezone           *time.Location
+}
+
+func NewBucketExportHandler(statsByBucketQuery *usecase.GetStatsByBucketQuery, renderer *BucketExportRenderer, bucket entity.TimeBucket, timezone *time.Location) *BucketExportHandler {
+	return &BucketExportHandler{
+		statsByBucketQuery: statsByBucketQuery,
+		renderer:           renderer,
+		bucket:             bucket,
+		timezone:           timezone,
+	}
+}
+
+// HandleExport writes the totals of every bucket with stored requests to the output path.
+// The destination is opened before querying so an unwritable path fails fast, and is only
+// replaced once the export is complete.
+func (h *BucketExportHandler) HandleExport(output string) error {
+	writer, err := OpenExportOutput(output)
+	if err != nil {
+		return err
+	}
+
+	if err := h.export(writer); err != nil {
+		writer.Discard()
+		return err
+	}
+
+	return writer.Commit()
+}
+
+func (h *BucketExportHandler) export(w io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	buckets, err := h.statsByBucketQuery.Execute(ctx, usecase.GetStatsByBucketParams{
+		Period:   entity.NewAllTimePeriod(time.Now()),
+		Bucket:   h.bucket,
+		Timezone: h.timezone,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query requests: %w", err)
+	}
+
+	if err := h.renderer.Render(w, buckets); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// bucketExportHeader is the column order of the bucketed CSV export
+var bucketExportHeader = []string{"bucket_start", "requests", "input_tokens", "output_tokens", "cache_read_tokens", "cache_creation_tokens", "total_tokens", "cost_usd"}
+
+// BucketExportRenderer serializes per-bucket statistics as CSV, one row per bucket
+type BucketExportRenderer struct {
+	timezone     *time.Location
+	costDecimals int
+}
+
+func NewBucketExportRenderer(timezone *time.Location) *BucketExportRenderer {
+	return &BucketExportRenderer{
+		timezone:     timezone,
+		costDecimals: DefaultExportCostDecimals,
+	}
+}
+
+// SetCostDecimals rounds the bucket costs to the number of decimals
+func (r *BucketExportRenderer) SetCostDecimals(decimals int) {
+	r.costDecimals = decimals
+}
+
+// Render writes the buckets to w with their start time in the renderer's timezone
+func (r *BucketExportRenderer) Render(w io.Writer, buckets []usecase.BucketStats) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(bucketExportHeader); err != nil {
+		return err
+	}
+
+	for _, bucket := range buckets {
+		tokens := bucket.Stats.TotalTokens()
+		record := []string{
+			bucket.StartAt.In(r.timezone).Format(time.RFC3339),
+			strconv.Itoa(bucket.Stats.TotalRequests()),
+			strconv.FormatInt(tokens.Input(), 10),
+			strconv.FormatInt(tokens.Output(), 10),
+			strconv.FormatInt(tokens.CacheRead(), 10),
+			strconv.FormatInt(tokens.CacheCreation(), 10),
+			strconv.FormatInt(tokens.Total(), 10),
+			strconv.FormatFloat(roundCost(bucket.Stats.TotalCost().Amount(), r.costDecimals), 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+type DatesHandler struct {
+	renderer *DatesRenderer
+}
+
+func NewDatesHandler(renderer *DatesRenderer) *DatesHandler {
+	return &DatesHandler{
+		renderer: renderer,
+	}
+}
+
+// HandleDatesQuery prints per-date statistics for the given dates
+func (h *DatesHandler) HandleDatesQuery(dates []time.Time) error {
+	result, err := h.renderer.Render(dates)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(result)
+	return nil
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// dateLayout is the calendar date format accepted by --dates
+const dateLayout = "2006-01-02"
+
+// ParseDateList parses a comma-separated list of YYYY-MM-DD dates in the given timezone.
+// Every date is validated so a typo fails before any query runs.
+func ParseDateList(value string, tz *time.Location) ([]time.Time, error) {
+	var dates []time.Time
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		date, err := time.ParseInLocation(dateLayout, part, tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", part)
+		}
+		dates = append(dates, date)
+	}
+
+	if len(dates) == 0 {
+		return nil, fmt.Errorf("no dates given (expected e.g. 2025-01-06,2025-01-13)")
+	}
+
+	return dates, nil
+}
+
+type DatesRenderer struct {
+	statsByDatesQuery *usecase.GetStatsByDatesQuery
+	numberLocale      entity.NumberLocale
+	tokenDecimals     int
+}
+
+func NewDatesRenderer(statsByDatesQuery *usecase.GetStatsByDatesQuery) *DatesRenderer {
+	return &DatesRenderer{
+		statsByDatesQuery: statsByDatesQuery,
+		numberLocale:      entity.DefaultNumberLocale,
+		tokenDecimals:     defaultTokenDecimals,
+	}
+}
+
+// SetNumberLocale sets the decimal and grouping separators of costs and token counts
+func (r *DatesRenderer) SetNumberLocale(locale entity.NumberLocale) {
+	r.numberLocale = locale
+}
+
+// SetTokenDecimals sets the decimal places of abbreviated token counts, negative keeps the default of 1
+func (r *DatesRenderer) SetTokenDecimals(decimals int) {
+	if decimals < 0 {
+		decimals = defaultTokenDecimals
+	}
+	r.tokenDecimals = decimals
+}
+
+// Render renders a table with one row per date followed by a total row
+func (r *DatesRenderer) Render(dates []time.Time) (string, error) {
+	// Create context with timeout to prevent hanging
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	results, err := r.statsByDatesQuery.Execute(ctx, usecase.GetStatsByDatesParams{Dates: dates})
+	if err != nil {
+		return "", err
+	}
+
+	return r.format(results), nil
+}
+
+func (r *DatesRenderer) format(results []usecase.DateStats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-10s  %8s  %8s  %10s\n", "Date", "Requests", "Tokens", "Cost")
+
+	var totalRequests int
+	var totalTokens entity.Token
+	var totalCost entity.Cost
+	for _, result := range results {
+		stats := result.Stats
+		fmt.Fprintf(&b, "%-10s  %8d  %8s  %10s\n",
+			result.Date.Format(dateLayout),
+			stats.TotalRequests(),
+			formatTokenCount(stats.TotalTokens().Total(), r.numberLocale, r.tokenDecimals),
+			stats.TotalCost().FormatLocale(entity.CostStyleFull, r.numberLocale))
+
+		totalRequests += stats.TotalRequests()
+		totalTokens = totalTokens.Add(stats.TotalTokens())
+		totalCost = totalCost.Add(stats.TotalCost())
+	}
+
+	fmt.Fprintf(&b, "%-10s  %8d  %8s  %10s\n",
+		"Total",
+		totalRequests,
+		formatTokenCount(totalTokens.Total(), r.numberLocale, r.tokenDecimals),
+		totalCost.FormatLocale(entity.CostStyleFull, r.numberLocale))
+
+	return b.String()
+}
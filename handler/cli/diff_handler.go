@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+type DiffHandler struct {
+	diffQuery   *usecase.DiffApiRequestsQuery
+	showRecords bool
+}
+
+func NewDiffHandler(diffQuery *usecase.DiffApiRequestsQuery) *DiffHandler {
+	return &DiffHandler{
+		diffQuery: diffQuery,
+	}
+}
+
+// SetShowRecords lists the requests unique to each file after the summary
+func (h *DiffHandler) SetShowRecords(show bool) {
+	h.showRecords = show
+}
+
+// HandleDiff compares two export files and prints what each has that the other does not
+func (h *DiffHandler) HandleDiff(leftName, rightName string) error {
+	result, err := h.Diff(leftName, rightName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(result)
+	return nil
+}
+
+// Diff compares the two datasets, named after their files, and renders the summary
+func (h *DiffHandler) Diff(leftName, rightName string) (string, error) {
+	diff, err := h.diffQuery.Execute(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("failed to compare requests: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d requests, %s\n", leftName, diff.LeftTotal, formatDiffCost(diff.LeftCost))
+	fmt.Fprintf(&b, "%s: %d requests, %s\n", rightName, diff.RightTotal, formatDiffCost(diff.RightCost))
+	fmt.Fprintf(&b, "In both: %d requests\n", diff.Common)
+	fmt.Fprintf(&b, "Only in %s: %d requests, %s\n", leftName, len(diff.OnlyLeft), formatDiffCost(diff.OnlyLeftCost()))
+	fmt.Fprintf(&b, "Only in %s: %d requests, %s\n", rightName, len(diff.OnlyRight), formatDiffCost(diff.OnlyRightCost()))
+	fmt.Fprintf(&b, "Cost difference: %s\n", formatCostDifference(diff.LeftCost.Amount()-diff.RightCost.Amount()))
+
+	if h.showRecords {
+		writeDiffRecords(&b, leftName, diff.OnlyLeft)
+		writeDiffRecords(&b, rightName, diff.OnlyRight)
+	}
+
+	return b.String(), nil
+}
+
+// writeDiffRecords lists the requests only the named file contains, one per line
+func writeDiffRecords(b *strings.Builder, name string, requests []entity.APIRequest) {
+	if len(requests) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\nOnly in %s:\n", name)
+	for _, req := range requests {
+		fmt.Fprintf(b, "  %s  %s  %s  %s\n",
+			req.Timestamp().UTC().Format(time.RFC3339Nano), req.SessionID(), req.Model(), formatDiffCost(req.Cost()))
+	}
+}
+
+// formatDiffCost renders a cost with six decimals, as a single request often costs less than a cent
+func formatDiffCost(cost entity.Cost) string {
+	return fmt.Sprintf("$%.6f", cost.Amount())
+}
+
+// formatCostDifference renders the left minus right cost with an explicit sign
+func formatCostDifference(amount float64) string {
+	// Summing the same costs in another order may leave a tiny remainder that is not a difference
+	amount = math.Round(amount*1e6) / 1e6
+	if amount < 0 {
+		return fmt.Sprintf("-$%.6f", -amount)
+	}
+	return fmt.Sprintf("+$%.6f", amount)
+}
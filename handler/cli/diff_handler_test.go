@@ -0,0 +1,96 @@
+package cli_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestDiffHandler_Diff(t *testing.T) {
+	at := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	shared := entity.NewAPIRequest("session-1", at, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.5), 1000)
+	laptop := entity.NewAPIRequest("session-1", at.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.25), 1000)
+	desktop := entity.NewAPIRequest("session-2", at.Add(2*time.Minute), "claude-3-5-haiku-20241022", entity.NewToken(10, 5, 0, 0), entity.NewCost(0.01), 500)
+
+	// Export each side the way ccmon writes it, then load the files back like --diff does
+	export := func(name string, format cli.ExportFormat, requests []entity.APIRequest) []entity.APIRequest {
+		mockRepo, _ := testutil.NewMockRepositoryWithData(requests)
+		output := filepath.Join(t.TempDir(), name)
+		if err := cli.NewExportHandler(usecase.NewGetFilteredApiRequestsQuery(mockRepo), cli.NewExportRenderer(format)).HandleExport(output); err != nil {
+			t.Fatalf("Unexpected export error: %v", err)
+		}
+		loaded, err := cli.LoadExportFile(output)
+		if err != nil {
+			t.Fatalf("Unexpected load error: %v", err)
+		}
+		return loaded
+	}
+	left := export("laptop.jsonl", cli.ExportFormatJSONL, []entity.APIRequest{shared, laptop})
+	right := export("desktop.csv", cli.ExportFormatCSV, []entity.APIRequest{shared, desktop})
+
+	summary := "laptop.jsonl: 2 requests, $0.750000\n" +
+		"desktop.csv: 2 requests, $0.510000\n" +
+		"In both: 1 requests\n" +
+		"Only in laptop.jsonl: 1 requests, $0.250000\n" +
+		"Only in desktop.csv: 1 requests, $0.010000\n"
+
+	tests := []struct {
+		name        string
+		left        []entity.APIRequest
+		right       []entity.APIRequest
+		showRecords bool
+		expected    string
+	}{
+		{
+			name:     "summary",
+			left:     left,
+			right:    right,
+			expected: summary + "Cost difference: +$0.240000\n",
+		},
+		{
+			name:        "with records",
+			left:        left,
+			right:       right,
+			showRecords: true,
+			expected: summary + "Cost difference: +$0.240000\n" +
+				"\nOnly in laptop.jsonl:\n" +
+				"  2025-07-01T10:01:00Z  session-1  claude-sonnet-4-20250514  $0.250000\n" +
+				"\nOnly in desktop.csv:\n" +
+				"  2025-07-01T10:02:00Z  session-2  claude-3-5-haiku-20241022  $0.010000\n",
+		},
+		{
+			name:        "identical files list no records",
+			left:        left,
+			right:       left,
+			showRecords: true,
+			expected: "laptop.jsonl: 2 requests, $0.750000\n" +
+				"desktop.csv: 2 requests, $0.750000\n" +
+				"In both: 2 requests\n" +
+				"Only in laptop.jsonl: 0 requests, $0.000000\n" +
+				"Only in desktop.csv: 0 requests, $0.000000\n" +
+				"Cost difference: +$0.000000\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leftRepo, _ := testutil.NewMockRepositoryWithData(tt.left)
+			rightRepo, _ := testutil.NewMockRepositoryWithData(tt.right)
+			handler := cli.NewDiffHandler(usecase.NewDiffApiRequestsQuery(leftRepo, rightRepo))
+			handler.SetShowRecords(tt.showRecords)
+
+			result, err := handler.Diff("laptop.jsonl", "desktop.csv")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
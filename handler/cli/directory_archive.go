@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// DirectoryArchive keeps exports as files named ccmon-<name>.<format> in a directory, rendered like --export
+type DirectoryArchive struct {
+	directory string
+	format    ExportFormat
+	renderer  *ExportRenderer
+}
+
+// NewDirectoryArchive creates an archive writing files of the renderer's format to the directory
+func NewDirectoryArchive(directory string, format ExportFormat, renderer *ExportRenderer) *DirectoryArchive {
+	return &DirectoryArchive{
+		directory: directory,
+		format:    format,
+		renderer:  renderer,
+	}
+}
+
+// Path returns the file the named export is written to
+func (a *DirectoryArchive) Path(name string) string {
+	return filepath.Join(a.directory, fmt.Sprintf("ccmon-%s.%s", name, a.format))
+}
+
+// Exists returns true when the export file is present
+func (a *DirectoryArchive) Exists(name string) (bool, error) {
+	_, err := os.Stat(a.Path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Write renders the requests to the export file, replacing it atomically so an interrupted
+// export is never mistaken for a finished one
+func (a *DirectoryArchive) Write(name string, requests []entity.APIRequest) error {
+	if err := os.MkdirAll(a.directory, 0o755); err != nil {
+		return fmt.Errorf("cannot create export directory %s: %w", a.directory, err)
+	}
+
+	path := a.Path(name)
+	tmp, err := os.CreateTemp(a.directory, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot write export file %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := a.renderer.Render(tmp, requests); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cannot write export file %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("cannot write export file %s: %w", path, err)
+	}
+	return nil
+}
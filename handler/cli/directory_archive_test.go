@@ -0,0 +1,64 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+)
+
+func TestDirectoryArchive(t *testing.T) {
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.5), 1000),
+	}
+
+	tests := []struct {
+		name     string
+		format   cli.ExportFormat
+		wantFile string
+	}{
+		{name: "csv", format: cli.ExportFormatCSV, wantFile: "ccmon-2025-07-01.csv"},
+		{name: "jsonl", format: cli.ExportFormatJSONL, wantFile: "ccmon-2025-07-01.jsonl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := filepath.Join(t.TempDir(), "archive")
+			archive := cli.NewDirectoryArchive(directory, tt.format, cli.NewExportRenderer(tt.format))
+
+			exists, err := archive.Exists("2025-07-01")
+			if err != nil || exists {
+				t.Fatalf("Expected no export before writing, got %v, %v", exists, err)
+			}
+
+			if err := archive.Write("2025-07-01", requests); err != nil {
+				t.Fatalf("Unexpected write error: %v", err)
+			}
+
+			path := filepath.Join(directory, tt.wantFile)
+			if archive.Path("2025-07-01") != path {
+				t.Errorf("Expected path %s, got %s", path, archive.Path("2025-07-01"))
+			}
+			exists, err = archive.Exists("2025-07-01")
+			if err != nil || !exists {
+				t.Errorf("Expected the export to exist, got %v, %v", exists, err)
+			}
+
+			// The file reads back like any --export file, without temporary files left over
+			loaded, err := cli.LoadExportFile(path)
+			if err != nil {
+				t.Fatalf("Unexpected load error: %v", err)
+			}
+			if len(loaded) != len(requests) {
+				t.Errorf("Expected %d requests, got %d", len(requests), len(loaded))
+			}
+			entries, err := os.ReadDir(directory)
+			if err != nil || len(entries) != 1 {
+				t.Errorf("Expected only the export file, got %v, %v", entries, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// StdoutPath is the --output value that selects standard output
+const StdoutPath = "-"
+
+type ExportHandler struct {
+	getFilteredQuery *usecase.GetFilteredApiRequestsQuery
+	renderer         *ExportRenderer
+	minCost          entity.Cost
+	durationRange    entity.DurationRange
+}
+
+func NewExportHandler(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, renderer *ExportRenderer) *ExportHandler {
+	return &ExportHandler{
+		getFilteredQuery: getFilteredQuery,
+		renderer:         renderer,
+	}
+}
+
+// SetMinCost skips requests cheaper than the threshold; zero exports everything
+func (h *ExportHandler) SetMinCost(minCost entity.Cost) {
+	h.minCost = minCost
+}
+
+// SetDurationRange exports only requests whose duration falls in the range; an empty range exports everything
+func (h *ExportHandler) SetDurationRange(durationRange entity.DurationRange) {
+	h.durationRange = durationRange
+}
+
+// HandleExport writes every stored request to the output path.
+// The destination is opened before querying so an unwritable path fails fast, and is only
+// replaced once the export is complete.
+func (h *ExportHandler) HandleExport(output string) error {
+	writer, err := OpenExportOutput(output)
+	if err != nil {
+		return err
+	}
+
+	if err := h.export(writer); err != nil {
+		writer.Discard()
+		return err
+	}
+
+	return writer.Commit()
+}
+
+func (h *ExportHandler) export(w io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	requests, err := h.getFilteredQuery.Execute(ctx, usecase.GetFilteredApiRequestsParams{
+		Period:   entity.NewAllTimePeriod(time.Now()),
+		Duration: h.durationRange,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query requests: %w", err)
+	}
+
+	requests, hidden := entity.FilterByMinCost(requests, h.minCost)
+	if hidden > 0 {
+		// Report on stderr so stdout stays a clean export stream
+		fmt.Fprintf(os.Stderr, "Skipped %d requests below $%g\n", hidden, h.minCost.Amount())
+	}
+
+	if err := h.renderer.Render(w, requests); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return nil
+}
+
+// ExportOutput is an export destination. Files are written to a temporary file in the same
+// directory and only replace the destination on Commit, so a failed export keeps the previous file.
+type ExportOutput struct {
+	io.Writer
+	file *os.File // Temporary file, nil for stdout
+	path string
+}
+
+// OpenExportOutput opens the export destination, creating parent directories as needed.
+// An empty path or "-" selects stdout, which is never closed.
+func OpenExportOutput(path string) (*ExportOutput, error) {
+	if path == "" || path == StdoutPath {
+		return &ExportOutput{Writer: os.Stdout}, nil
+	}
+
+	dir := filepath.Dir(path)
+	if dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("cannot create output directory %s: %w", dir, err)
+		}
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("cannot write output file %s: %w", path, err)
+	}
+
+	return &ExportOutput{Writer: file, file: file, path: path}, nil
+}
+
+// Commit replaces the destination with the written export
+func (o *ExportOutput) Commit() error {
+	if o.file == nil {
+		return nil
+	}
+
+	if err := o.file.Close(); err != nil {
+		_ = os.Remove(o.file.Name())
+		return fmt.Errorf("cannot write output file %s: %w", o.path, err)
+	}
+	if err := os.Rename(o.file.Name(), o.path); err != nil {
+		_ = os.Remove(o.file.Name())
+		return fmt.Errorf("cannot write output file %s: %w", o.path, err)
+	}
+	return nil
+}
+
+// Discard drops the written export, leaving the destination as it was
+func (o *ExportOutput) Discard() {
+	if o.file == nil {
+		return
+	}
+
+	_ = o.file.Close()
+	_ = os.Remove(o.file.Name())
+}
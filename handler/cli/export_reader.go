@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// DetectExportFormat picks the export format from a file extension: .csv, or .jsonl, .ndjson and .json for JSON Lines
+func DetectExportFormat(path string) (ExportFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ExportFormatCSV, nil
+	case ".jsonl", ".ndjson", ".json":
+		return ExportFormatJSONL, nil
+	default:
+		return "", fmt.Errorf("cannot tell the format of %s (expected a .csv or .jsonl file)", path)
+	}
+}
+
+// LoadExportFile reads the requests of a file written by --export, detecting the format from its extension
+func LoadExportFile(path string) ([]entity.APIRequest, error) {
+	format, err := DetectExportFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read export file %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	requests, err := ReadExport(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export file %s: %w", path, err)
+	}
+	return requests, nil
+}
+
+// ReadExport parses requests written by ExportRenderer. Only the timestamp column is required, so
+// exports narrowed with --fields load too; missing numbers are zero and a missing model is unknown.
+func ReadExport(r io.Reader, format ExportFormat) ([]entity.APIRequest, error) {
+	switch format {
+	case ExportFormatCSV:
+		return readCSVExport(r)
+	case ExportFormatJSONL:
+		return readJSONLExport(r)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func readCSVExport(r io.Reader) ([]entity.APIRequest, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return []entity.APIRequest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]entity.APIRequest, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				fields[strings.ToLower(strings.TrimSpace(name))] = record[i]
+			}
+		}
+
+		req, err := parseExportRecord(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
+func readJSONLExport(r io.Reader) ([]entity.APIRequest, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	requests := make([]entity.APIRequest, 0)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		// Numbers are kept as written so token counts never pass through float64
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var values map[string]any
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		fields := make(map[string]string, len(values))
+		for name, value := range values {
+			fields[strings.ToLower(name)] = fmt.Sprint(value)
+		}
+
+		req, err := parseExportRecord(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		requests = append(requests, req)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// parseExportRecord converts the named column values of one exported request
+func parseExportRecord(fields map[string]string) (entity.APIRequest, error) {
+	value, ok := fields["timestamp"]
+	if !ok || strings.TrimSpace(value) == "" {
+		return entity.APIRequest{}, fmt.Errorf("missing timestamp")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+	if err != nil {
+		return entity.APIRequest{}, fmt.Errorf("invalid timestamp %q: %w", value, err)
+	}
+
+	integers := make(map[string]int64)
+	for _, name := range []string{"input_tokens", "output_tokens", "cache_read_tokens", "cache_creation_tokens", "duration_ms"} {
+		value := strings.TrimSpace(fields[name])
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return entity.APIRequest{}, fmt.Errorf("invalid %s %q", name, value)
+		}
+		integers[name] = parsed
+	}
+
+	var cost float64
+	if value := strings.TrimSpace(fields["cost_usd"]); value != "" {
+		cost, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return entity.APIRequest{}, fmt.Errorf("invalid cost_usd %q", value)
+		}
+	}
+
+	tokens := entity.NewToken(integers["input_tokens"], integers["output_tokens"], integers["cache_read_tokens"], integers["cache_creation_tokens"])
+	return entity.NewAPIRequest(fields["session_id"], timestamp, fields["model"], tokens, entity.NewCost(cost), integers["duration_ms"]), nil
+}
@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// ExportFormat identifies the serialization used by the export commands
+type ExportFormat string
+
+const (
+	ExportFormatCSV   ExportFormat = "csv"
+	ExportFormatJSONL ExportFormat = "jsonl"
+)
+
+// DefaultExportCostDecimals is the number of decimals exported costs are rounded to
+const DefaultExportCostDecimals = 6
+
+// ParseExportFormat validates an export format name
+func ParseExportFormat(value string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	case ExportFormatJSONL:
+		return ExportFormatJSONL, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q (expected csv or jsonl)", value)
+	}
+}
+
+// exportColumn describes a single exported field and how to read it from a request
+type exportColumn struct {
+	name  string
+	value func(req entity.APIRequest) any
+}
+
+// modelNameColumn is the display name of the model, exported by default only when display names are configured
+const modelNameColumn = "model_name"
+
+// exportColumns is the ordered column set shared by every export format
+var exportColumns = []exportColumn{
+	{name: "timestamp", value: func(req entity.APIRequest) any { return req.Timestamp().UTC().Format(time.RFC3339Nano) }},
+	{name: "session_id", value: func(req entity.APIRequest) any { return req.SessionID() }},
+	{name: "model", value: func(req entity.APIRequest) any { return req.Model().String() }},
+	{name: modelNameColumn, value: func(req entity.APIRequest) any { return req.Model().String() }}, // Mapped by the renderer
+	{name: "input_tokens", value: func(req entity.APIRequest) any { return req.Tokens().Input() }},
+	{name: "output_tokens", value: func(req entity.APIRequest) any { return req.Tokens().Output() }},
+	{name: "cache_read_tokens", value: func(req entity.APIRequest) any { return req.Tokens().CacheRead() }},
+	{name: "cache_creation_tokens", value: func(req entity.APIRequest) any { return req.Tokens().CacheCreation() }},
+	{name: "total_tokens", value: func(req entity.APIRequest) any { return req.Tokens().Total() }},
+	{name: "cost_usd", value: func(req entity.APIRequest) any { return req.Cost().Amount() }},
+	{name: "duration_ms", value: func(req entity.APIRequest) any { return req.DurationMS() }},
+}
+
+// ExportFieldNames returns the names of every exportable column in order
+func ExportFieldNames() []string {
+	names := make([]string, len(exportColumns))
+	for i, column := range exportColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// ParseExportFields parses a comma-separated list of column names for --fields.
+// Every name is validated so a typo fails before the export begins.
+func ParseExportFields(value string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+
+		if _, ok := findExportColumn(name); !ok {
+			return nil, fmt.Errorf("unknown export field %q (expected any of: %s)", part, strings.Join(ExportFieldNames(), ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate export field %q", name)
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no export fields given (expected e.g. timestamp,model,cost_usd)")
+	}
+
+	return fields, nil
+}
+
+// findExportColumn looks up a column by name
+func findExportColumn(name string) (exportColumn, bool) {
+	for _, column := range exportColumns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return exportColumn{}, false
+}
+
+// defaultExportColumns returns the columns exported without --fields, the model name only when it differs from the model
+func defaultExportColumns(modelNames entity.ModelNameMap) []exportColumn {
+	if !modelNames.IsEmpty() {
+		return exportColumns
+	}
+
+	columns := make([]exportColumn, 0, len(exportColumns)-1)
+	for _, column := range exportColumns {
+		if column.name != modelNameColumn {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// ExportRenderer serializes API requests into an export format
+type ExportRenderer struct {
+	format       ExportFormat
+	columns      []exportColumn // nil exports the default columns
+	costDecimals int
+	modelNames   entity.ModelNameMap
+}
+
+func NewExportRenderer(format ExportFormat) *ExportRenderer {
+	return &ExportRenderer{
+		format:       format,
+		costDecimals: DefaultExportCostDecimals,
+	}
+}
+
+// SetModelNames exports the display name of each model in a model_name column next to the raw model
+func (r *ExportRenderer) SetModelNames(modelNames entity.ModelNameMap) {
+	r.modelNames = modelNames
+}
+
+// SetCostDecimals rounds exported costs to the number of decimals, dropping float noise such as 0.500000000001
+func (r *ExportRenderer) SetCostDecimals(decimals int) {
+	r.costDecimals = decimals
+}
+
+// SetFields selects and orders the exported columns; an empty list exports the default columns
+func (r *ExportRenderer) SetFields(fields []string) error {
+	if len(fields) == 0 {
+		r.columns = nil
+		return nil
+	}
+
+	columns := make([]exportColumn, len(fields))
+	for i, name := range fields {
+		column, ok := findExportColumn(name)
+		if !ok {
+			return fmt.Errorf("unknown export field %q", name)
+		}
+		columns[i] = column
+	}
+
+	r.columns = columns
+	return nil
+}
+
+// Render writes the requests to w in the renderer's format
+func (r *ExportRenderer) Render(w io.Writer, requests []entity.APIRequest) error {
+	switch r.format {
+	case ExportFormatCSV:
+		return r.renderCSV(w, requests)
+	case ExportFormatJSONL:
+		return r.renderJSONL(w, requests)
+	default:
+		return fmt.Errorf("unsupported export format %q", r.format)
+	}
+}
+
+func (r *ExportRenderer) renderCSV(w io.Writer, requests []entity.APIRequest) error {
+	writer := csv.NewWriter(w)
+	columns := r.activeColumns()
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for _, req := range requests {
+		for i, column := range columns {
+			record[i] = formatCSVValue(r.columnValue(column, req))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (r *ExportRenderer) renderJSONL(w io.Writer, requests []entity.APIRequest) error {
+	encoder := json.NewEncoder(w)
+	columns := r.activeColumns()
+
+	for _, req := range requests {
+		// Marshal through an ordered slice so keys follow the column order
+		fields := make([]string, len(columns))
+		for i, column := range columns {
+			key, err := json.Marshal(column.name)
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(r.columnValue(column, req))
+			if err != nil {
+				return err
+			}
+			fields[i] = string(key) + ":" + string(value)
+		}
+
+		if err := encoder.Encode(json.RawMessage("{" + strings.Join(fields, ",") + "}")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// activeColumns returns the selected columns, or the default ones without --fields
+func (r *ExportRenderer) activeColumns() []exportColumn {
+	if r.columns == nil {
+		return defaultExportColumns(r.modelNames)
+	}
+	return r.columns
+}
+
+// columnValue reads a column of the request, with costs, the only float columns, rounded
+func (r *ExportRenderer) columnValue(column exportColumn, req entity.APIRequest) any {
+	if column.name == modelNameColumn {
+		return r.modelNames.DisplayName(req.Model())
+	}
+
+	value := column.value(req)
+	if amount, ok := value.(float64); ok {
+		return roundCost(amount, r.costDecimals)
+	}
+	return value
+}
+
+// roundCost rounds a cost to the number of decimals. The result is the float closest to the
+// rounded decimal, so it is written without the noise of the summed or multiplied original.
+func roundCost(amount float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(amount*scale) / scale
+}
+
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -2,14 +2,24 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/usecase"
 )
 
+// variableTokenPattern matches @-prefixed words not preceded by a word character, so e-mail
+// addresses such as user@example.com are not taken for variables
+var variableTokenPattern = regexp.MustCompile(`(?:^|[^\w@])(@\w+)`)
+
 type FormatRenderer struct {
 	usageVariablesQuery *usecase.GetUsageVariablesQuery
+	strict              bool
 }
 
 func NewFormatRenderer(usageVariablesQuery *usecase.GetUsageVariablesQuery) *FormatRenderer {
@@ -18,11 +28,34 @@ func NewFormatRenderer(usageVariablesQuery *usecase.GetUsageVariablesQuery) *For
 	}
 }
 
+// SetCostStyle overrides the precision of cost and block token variables for this renderer
+func (r *FormatRenderer) SetCostStyle(style entity.CostStyle) {
+	r.usageVariablesQuery.SetCostStyle(style)
+}
+
+// SetNumberLocale overrides the decimal and grouping separators of cost variables for this renderer
+func (r *FormatRenderer) SetNumberLocale(locale entity.NumberLocale) {
+	r.usageVariablesQuery.SetNumberLocale(locale)
+}
+
+// SetStrict rejects format strings with unknown @ variables instead of leaving them intact
+func (r *FormatRenderer) SetStrict(strict bool) {
+	r.strict = strict
+}
+
 func (r *FormatRenderer) Render(formatString string) (string, error) {
+	// Typos are reported before querying, so they surface even when the server is down
+	if r.strict {
+		if err := ValidateFormatString(formatString); err != nil {
+			return "", err
+		}
+	}
+
 	// Create context with timeout to prevent hanging
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
+	r.usageVariablesQuery.SetUsedVariables(usedVariables(formatString))
 	variableMap, err := r.usageVariablesQuery.Execute(ctx)
 	if err != nil {
 		return "", err
@@ -31,13 +64,129 @@ func (r *FormatRenderer) Render(formatString string) (string, error) {
 	return r.substituteVariables(formatString, variableMap), nil
 }
 
+// RenderJSON renders every usage variable as one indented JSON object with numeric values,
+// keyed by the variable name without "@", e.g. {"daily_cost": 1.24, ...}
+func (r *FormatRenderer) RenderJSON() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// Every variable is printed, so every one is queried
+	r.usageVariablesQuery.SetUsedVariables(nil)
+	values, err := r.usageVariablesQuery.ExecuteValues(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	object := make(map[string]any, len(values))
+	for key, value := range values {
+		object[strings.TrimPrefix(key, "@")] = value
+	}
+
+	data, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode usage variables: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// Explain renders the format string followed by how each usage variable in it was computed,
+// e.g. the period, daily cost, plan price and days in month behind @daily_plan_usage
+func (r *FormatRenderer) Explain(formatString string) (string, error) {
+	if r.strict {
+		if err := ValidateFormatString(formatString); err != nil {
+			return "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// One query for both, so the breakdown always matches the rendered values
+	r.usageVariablesQuery.SetUsedVariables(usedVariables(formatString))
+	explanations, err := r.usageVariablesQuery.Explain(ctx)
+	if err != nil {
+		return "", err
+	}
+	variableMap := make(map[string]string, len(explanations))
+	byKey := make(map[string]usecase.UsageVariableExplanation, len(explanations))
+	for _, explanation := range explanations {
+		variableMap[explanation.Variable.Key()] = explanation.Value
+		byKey[explanation.Variable.Key()] = explanation
+	}
+
+	var b strings.Builder
+	b.WriteString(r.substituteVariables(formatString, variableMap))
+	b.WriteString("\n")
+
+	explained := make([]string, 0)
+	for _, match := range variableTokenPattern.FindAllStringSubmatch(formatString, -1) {
+		explanation, ok := byKey[match[1]]
+		if !ok || slices.Contains(explained, match[1]) {
+			continue
+		}
+		explained = append(explained, match[1])
+
+		fmt.Fprintf(&b, "\n%s = %s\n", explanation.Variable.Key(), explanation.Value)
+		for _, input := range explanation.Inputs {
+			fmt.Fprintf(&b, "  %-18s %s\n", input.Name+":", input.Value)
+		}
+	}
+	if len(explained) == 0 {
+		b.WriteString("\nNo usage variables to explain\n")
+	}
+
+	return b.String(), nil
+}
+
 func (r *FormatRenderer) substituteVariables(input string, variableMap map[string]string) string {
-	result := input
+	// Longest first, so @daily_cost does not replace the start of @daily_cost_per_session
+	variables := make([]string, 0, len(variableMap))
+	for variable := range variableMap {
+		variables = append(variables, variable)
+	}
+	slices.SortFunc(variables, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+
+	pairs := make([]string, 0, len(variables)*2)
+	for _, variable := range variables {
+		pairs = append(pairs, variable, variableMap[variable])
+	}
+	return strings.NewReplacer(pairs...).Replace(input)
+}
 
-	// Replace all variables in the format string
-	for variable, value := range variableMap {
-		result = strings.ReplaceAll(result, variable, value)
+// usedVariables returns the @ variables in the format string, e.g. "@daily_cost"
+func usedVariables(formatString string) []string {
+	keys := make([]string, 0)
+	for _, match := range variableTokenPattern.FindAllStringSubmatch(formatString, -1) {
+		keys = append(keys, match[1])
+	}
+	return keys
+}
+
+// ValidateFormatString returns an error listing the @ variables in the format string that are not
+// known usage variables. A variable must be followed by a non-word character, e.g. "@daily_cost%".
+func ValidateFormatString(formatString string) error {
+	known := make(map[string]bool)
+	names := make([]string, 0)
+	for _, variable := range entity.GetAllUsageVariables() {
+		known[variable.Key()] = true
+		names = append(names, variable.Key())
 	}
 
-	return result
+	unknown := make([]string, 0)
+	for _, match := range variableTokenPattern.FindAllStringSubmatch(formatString, -1) {
+		token := match[1]
+		if !known[token] && !slices.Contains(unknown, token) {
+			unknown = append(unknown, token)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown format variables: %s (must be one of: %s)", strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+	return nil
 }
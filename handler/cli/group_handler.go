@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+type GroupHandler struct {
+	renderer *GroupRenderer
+}
+
+func NewGroupHandler(renderer *GroupRenderer) *GroupHandler {
+	return &GroupHandler{
+		renderer: renderer,
+	}
+}
+
+// HandleGroupQuery prints this month's stats per group
+func (h *GroupHandler) HandleGroupQuery(groupBy entity.GroupBy) error {
+	result, err := h.renderer.Render(groupBy)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(result)
+	return nil
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// groupJSON is one group of the --group-by JSON output, named like the export columns
+type groupJSON struct {
+	Group               string  `json:"group"`
+	Requests            int     `json:"requests"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	TotalTokens         int64   `json:"total_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+}
+
+type GroupRenderer struct {
+	statsByGroupQuery *usecase.GetStatsByGroupQuery
+	timezone          *time.Location
+	projectLabel      string
+	json              bool
+	numberLocale      entity.NumberLocale
+	tokenDecimals     int
+}
+
+func NewGroupRenderer(statsByGroupQuery *usecase.GetStatsByGroupQuery, timezone *time.Location, projectLabel string) *GroupRenderer {
+	return &GroupRenderer{
+		statsByGroupQuery: statsByGroupQuery,
+		timezone:          timezone,
+		projectLabel:      projectLabel,
+		numberLocale:      entity.DefaultNumberLocale,
+		tokenDecimals:     defaultTokenDecimals,
+	}
+}
+
+// SetJSON renders the groups as a JSON array instead of a table
+func (r *GroupRenderer) SetJSON(enabled bool) {
+	r.json = enabled
+}
+
+// SetNumberLocale sets the decimal and grouping separators of costs and token counts in the table
+func (r *GroupRenderer) SetNumberLocale(locale entity.NumberLocale) {
+	r.numberLocale = locale
+}
+
+// SetTokenDecimals sets the decimal places of abbreviated token counts, negative keeps the default of 1
+func (r *GroupRenderer) SetTokenDecimals(decimals int) {
+	if decimals < 0 {
+		decimals = defaultTokenDecimals
+	}
+	r.tokenDecimals = decimals
+}
+
+// Render renders this month's stats per group, the most expensive first
+func (r *GroupRenderer) Render(groupBy entity.GroupBy) (string, error) {
+	// Create context with timeout to prevent hanging
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	results, err := r.statsByGroupQuery.Execute(ctx, usecase.GetStatsByGroupParams{
+		GroupBy:      groupBy,
+		Timezone:     r.timezone,
+		ProjectLabel: r.projectLabel,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if r.json {
+		return formatGroupsJSON(results)
+	}
+	return r.format(groupBy, results), nil
+}
+
+func (r *GroupRenderer) format(groupBy entity.GroupBy, results []usecase.GroupStats) string {
+	header := strings.ToUpper(string(groupBy[:1])) + string(groupBy[1:])
+	width := len(header)
+	for _, result := range results {
+		width = max(width, len(result.Group))
+	}
+	width = max(width, len("Total"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s  %8s  %8s  %10s\n", width, header, "Requests", "Tokens", "Cost")
+
+	var totalRequests int
+	var totalTokens entity.Token
+	var totalCost entity.Cost
+	for _, result := range results {
+		stats := result.Stats
+		fmt.Fprintf(&b, "%-*s  %8d  %8s  %10s\n",
+			width, result.Group,
+			stats.TotalRequests(),
+			formatTokenCount(stats.TotalTokens().Total(), r.numberLocale, r.tokenDecimals),
+			stats.TotalCost().FormatLocale(entity.CostStyleFull, r.numberLocale))
+
+		totalRequests += stats.TotalRequests()
+		totalTokens = totalTokens.Add(stats.TotalTokens())
+		totalCost = totalCost.Add(stats.TotalCost())
+	}
+
+	fmt.Fprintf(&b, "%-*s  %8d  %8s  %10s\n",
+		width, "Total",
+		totalRequests,
+		formatTokenCount(totalTokens.Total(), r.numberLocale, r.tokenDecimals),
+		totalCost.FormatLocale(entity.CostStyleFull, r.numberLocale))
+
+	return b.String()
+}
+
+// formatGroupsJSON renders the groups as an indented JSON array with raw token counts and costs
+func formatGroupsJSON(results []usecase.GroupStats) (string, error) {
+	groups := make([]groupJSON, len(results))
+	for i, result := range results {
+		tokens := result.Stats.TotalTokens()
+		groups[i] = groupJSON{
+			Group:               result.Group,
+			Requests:            result.Stats.TotalRequests(),
+			InputTokens:         tokens.Input(),
+			OutputTokens:        tokens.Output(),
+			CacheReadTokens:     tokens.CacheRead(),
+			CacheCreationTokens: tokens.CacheCreation(),
+			TotalTokens:         tokens.Total(),
+			CostUSD:             result.Stats.TotalCost().Amount(),
+		}
+	}
+
+	data, err := json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode groups: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// HealthChecker runs an ingestion health check against the server
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) (*usecase.HealthCheckResult, error)
+}
+
+type HealthCheckHandler struct {
+	checker HealthChecker
+}
+
+func NewHealthCheckHandler(checker HealthChecker) *HealthCheckHandler {
+	return &HealthCheckHandler{
+		checker: checker,
+	}
+}
+
+// HandleHealthCheck prints a single status line and returns an error when the check fails
+func (h *HealthCheckHandler) HandleHealthCheck() error {
+	result, err := h.Check()
+	fmt.Println(result)
+	return err
+}
+
+// Check runs the health check and formats its outcome with timings
+func (h *HealthCheckHandler) Check() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	result, err := h.checker.HealthCheck(ctx)
+	elapsed := time.Since(start)
+	if err != nil {
+		return fmt.Sprintf("❌ ERROR %v (after %s)", err, formatCheckDuration(elapsed)), err
+	}
+
+	return fmt.Sprintf("✅ OK ingest=%s verify=%s cleanup=%s total=%s",
+		formatCheckDuration(result.IngestDuration),
+		formatCheckDuration(result.VerifyDuration),
+		formatCheckDuration(result.CleanupDuration),
+		formatCheckDuration(elapsed),
+	), nil
+}
+
+// formatCheckDuration formats a duration in milliseconds with sub-millisecond precision
+func formatCheckDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
@@ -0,0 +1,62 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+type fakeHealthChecker struct {
+	result *usecase.HealthCheckResult
+	err    error
+}
+
+func (f *fakeHealthChecker) HealthCheck(ctx context.Context) (*usecase.HealthCheckResult, error) {
+	return f.result, f.err
+}
+
+func TestHealthCheckHandler_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		checker     *fakeHealthChecker
+		expected    []string
+		expectError bool
+	}{
+		{
+			name: "healthy server reports phase timings",
+			checker: &fakeHealthChecker{result: &usecase.HealthCheckResult{
+				IngestDuration:  1500 * time.Microsecond,
+				VerifyDuration:  250 * time.Microsecond,
+				CleanupDuration: 2 * time.Millisecond,
+			}},
+			expected: []string{"✅ OK", "ingest=1.5ms", "verify=0.2ms", "cleanup=2.0ms", "total="},
+		},
+		{
+			name:        "failed check reports the reason",
+			checker:     &fakeHealthChecker{err: errors.New("Internal: health check failed: verify failed: model mismatch")},
+			expected:    []string{"❌ ERROR", "model mismatch", "after"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := cli.NewHealthCheckHandler(tt.checker)
+
+			output, err := handler.Check()
+			if tt.expectError != (err != nil) {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			for _, part := range tt.expected {
+				if !strings.Contains(output, part) {
+					t.Errorf("Expected output %q to contain %q", output, part)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+)
+
+type HoursHandler struct {
+	renderer *HoursRenderer
+}
+
+func NewHoursHandler(renderer *HoursRenderer) *HoursHandler {
+	return &HoursHandler{
+		renderer: renderer,
+	}
+}
+
+// HandleHoursQuery prints cost by hour of day over the last days
+func (h *HoursHandler) HandleHoursQuery(days int) error {
+	result, err := h.renderer.Render(days)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(result)
+	return nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// hoursBarWidth is the width of the bar of the most expensive hour
+const hoursBarWidth = 30
+
+type HoursRenderer struct {
+	statsByHourQuery *usecase.GetStatsByHourOfDayQuery
+	timezone         *time.Location
+	numberLocale     entity.NumberLocale
+	tokenDecimals    int
+}
+
+func NewHoursRenderer(statsByHourQuery *usecase.GetStatsByHourOfDayQuery, timezone *time.Location) *HoursRenderer {
+	return &HoursRenderer{
+		statsByHourQuery: statsByHourQuery,
+		timezone:         timezone,
+		numberLocale:     entity.DefaultNumberLocale,
+		tokenDecimals:    defaultTokenDecimals,
+	}
+}
+
+// SetNumberLocale sets the decimal and grouping separators of costs and token counts
+func (r *HoursRenderer) SetNumberLocale(locale entity.NumberLocale) {
+	r.numberLocale = locale
+}
+
+// SetTokenDecimals sets the decimal places of abbreviated token counts, negative keeps the default of 1
+func (r *HoursRenderer) SetTokenDecimals(decimals int) {
+	if decimals < 0 {
+		decimals = defaultTokenDecimals
+	}
+	r.tokenDecimals = decimals
+}
+
+// Render renders a bar chart of cost by hour of day over the last days, followed by the peak hour
+func (r *HoursRenderer) Render(days int) (string, error) {
+	// Create context with timeout to prevent hanging
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	results, err := r.statsByHourQuery.Execute(ctx, usecase.GetStatsByHourOfDayParams{Days: days, Timezone: r.timezone})
+	if err != nil {
+		return "", err
+	}
+
+	return r.format(results), nil
+}
+
+func (r *HoursRenderer) format(results []usecase.HourOfDayStats) string {
+	peak := -1
+	var peakCost float64
+	for i, result := range results {
+		if cost := result.Stats.TotalCost().Amount(); cost > peakCost {
+			peak = i
+			peakCost = cost
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-5s  %8s  %8s  %10s\n", "Hour", "Requests", "Tokens", "Cost")
+
+	for _, result := range results {
+		stats := result.Stats
+		bar := ""
+		if peakCost > 0 {
+			bar = strings.Repeat("█", int(stats.TotalCost().Amount()/peakCost*hoursBarWidth+0.5))
+		}
+
+		fmt.Fprintf(&b, "%02d:00  %8d  %8s  %10s  %s\n",
+			result.Hour,
+			stats.TotalRequests(),
+			formatTokenCount(stats.TotalTokens().Total(), r.numberLocale, r.tokenDecimals),
+			stats.TotalCost().FormatLocale(entity.CostStyleFull, r.numberLocale),
+			bar)
+	}
+
+	if peak >= 0 {
+		fmt.Fprintf(&b, "Peak: %02d:00-%02d:00 (%s)\n",
+			results[peak].Hour, (results[peak].Hour+1)%usecase.HoursPerDay,
+			results[peak].Stats.TotalCost().FormatLocale(entity.CostStyleFull, r.numberLocale))
+	}
+
+	return b.String()
+}
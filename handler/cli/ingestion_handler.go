@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IngestionSwitch pauses or resumes the server's ingestion
+type IngestionSwitch interface {
+	SetIngestionPaused(ctx context.Context, paused bool) (wasPaused bool, err error)
+}
+
+type IngestionHandler struct {
+	ingestion IngestionSwitch
+}
+
+func NewIngestionHandler(ingestion IngestionSwitch) *IngestionHandler {
+	return &IngestionHandler{
+		ingestion: ingestion,
+	}
+}
+
+// HandleSetPaused pauses or resumes ingestion and prints the resulting state
+func (h *IngestionHandler) HandleSetPaused(paused bool) error {
+	result, err := h.SetPaused(paused)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result)
+	return nil
+}
+
+// SetPaused pauses or resumes ingestion and describes the change
+func (h *IngestionHandler) SetPaused(paused bool) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	wasPaused, err := h.ingestion.SetIngestionPaused(ctx, paused)
+	if err != nil {
+		return "", fmt.Errorf("failed to set ingestion state: %w", err)
+	}
+
+	switch {
+	case paused && wasPaused:
+		return "Ingestion was already paused", nil
+	case paused:
+		return "Ingestion paused, exporters retry until it is resumed", nil
+	case !wasPaused:
+		return "Ingestion was not paused", nil
+	default:
+		return "Ingestion resumed", nil
+	}
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elct9620/ccmon/handler/cli"
+)
+
+type fakeIngestionSwitch struct {
+	paused bool
+	err    error
+}
+
+func (f *fakeIngestionSwitch) SetIngestionPaused(ctx context.Context, paused bool) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	wasPaused := f.paused
+	f.paused = paused
+	return wasPaused, nil
+}
+
+func TestIngestionHandler_SetPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		ingestion   *fakeIngestionSwitch
+		paused      bool
+		expected    string
+		expectError bool
+	}{
+		{
+			name:      "pause",
+			ingestion: &fakeIngestionSwitch{},
+			paused:    true,
+			expected:  "Ingestion paused, exporters retry until it is resumed",
+		},
+		{
+			name:      "pause twice",
+			ingestion: &fakeIngestionSwitch{paused: true},
+			paused:    true,
+			expected:  "Ingestion was already paused",
+		},
+		{
+			name:      "resume",
+			ingestion: &fakeIngestionSwitch{paused: true},
+			expected:  "Ingestion resumed",
+		},
+		{
+			name:      "resume without pause",
+			ingestion: &fakeIngestionSwitch{},
+			expected:  "Ingestion was not paused",
+		},
+		{
+			name:        "server error",
+			ingestion:   &fakeIngestionSwitch{err: errors.New("PermissionDenied: server.auth_token must be configured to use this method")},
+			paused:      true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := cli.NewIngestionHandler(tt.ingestion)
+
+			output, err := handler.SetPaused(tt.paused)
+			if tt.expectError != (err != nil) {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if output != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package cli_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -81,6 +86,20 @@ func TestFormatQueryEndToEnd(t *testing.T) {
 			requests:       createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
 			expectedOutput: fmt.Sprintf("Daily: $15.0 Monthly: $155.0 Usage: %s", calculateExpectedDailyUsage(15.0, 20.0)),
 		},
+		{
+			name:           "tier costs mixed with total costs",
+			formatString:   "H:@base_cost S:@premium_cost of @daily_cost | month H:@monthly_base_cost S:@monthly_premium_cost of @monthly_cost",
+			plan:           entity.NewPlan("pro", entity.NewCost(20.0)),
+			requests:       createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
+			expectedOutput: "H:$5.0 S:$10.0 of $15.0 | month H:$55.0 S:$100.0 of $155.0",
+		},
+		{
+			name:           "daily tier costs next to plan usage",
+			formatString:   "@daily_base_cost/@daily_premium_cost (@daily_plan_usage)",
+			plan:           entity.NewPlan("pro", entity.NewCost(20.0)),
+			requests:       createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
+			expectedOutput: fmt.Sprintf("$5.0/$10.0 (%s)", calculateExpectedDailyUsage(15.0, 20.0)),
+		},
 		{
 			name:           "format string with emojis and custom text",
 			formatString:   "💰 Daily: @daily_cost | 📊 Monthly: @monthly_cost | 📈 @daily_plan_usage of plan",
@@ -96,9 +115,9 @@ func TestFormatQueryEndToEnd(t *testing.T) {
 			expectedOutput: "0% 0%",
 		},
 		{
-			name:           "plan repository error - fallback to unset",
+			name:           "plan unavailable - fallback to unset",
 			formatString:   "@daily_cost @daily_plan_usage",
-			planErr:        fmt.Errorf("failed to get plan"),
+			planErr:        fmt.Errorf("failed to get plan: %w", usecase.ErrPlanUnavailable),
 			requests:       createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
 			expectedOutput: "$15.0 0%",
 		},
@@ -116,6 +135,14 @@ func TestFormatQueryEndToEnd(t *testing.T) {
 			requests:       []entity.APIRequest{},
 			expectedOutput: "$0.0 $0.0 0%",
 		},
+		{
+			name:           "plan repository failure",
+			formatString:   "@daily_cost @daily_plan_usage",
+			planErr:        fmt.Errorf("failed to read plans"),
+			requests:       createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
+			expectedOutput: "❌ ERROR",
+			expectError:    true,
+		},
 		{
 			name:           "repository error",
 			formatString:   "@daily_cost",
@@ -132,6 +159,13 @@ func TestFormatQueryEndToEnd(t *testing.T) {
 			requests:       createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
 			expectedOutput: fmt.Sprintf("$15.0 $155.0 %s 775%%", calculateExpectedDailyUsage(15.0, 20.0)),
 		},
+		{
+			name:           "distinct session counts",
+			formatString:   "@daily_sessions/@monthly_sessions",
+			plan:           entity.NewPlan("pro", entity.NewCost(20.0)),
+			requests:       createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
+			expectedOutput: "5/20",
+		},
 	}
 
 	for _, tt := range tests {
@@ -150,8 +184,10 @@ func TestFormatQueryEndToEnd(t *testing.T) {
 			// Create real services with timezone
 			periodFactory := service.NewTimePeriodFactory(timezone)
 			calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
 			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
 				calculateStatsQuery,
+				sessionsQuery,
 				mockPlanRepo,
 				periodFactory,
 			)
@@ -236,13 +272,15 @@ func TestTimeZoneConsistency(t *testing.T) {
 			}
 
 			// Setup using factory
-			_, mockStatsRepo := testutil.NewMockRepositoryWithData(requests)
+			mockRepo, mockStatsRepo := testutil.NewMockRepositoryWithData(requests)
 			mockPlanRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0)))
 
 			periodFactory := service.NewTimePeriodFactory(timezone)
 			calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
 			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
 				calculateStatsQuery,
+				sessionsQuery,
 				mockPlanRepo,
 				periodFactory,
 			)
@@ -293,15 +331,151 @@ func TestTimeZoneConsistency(t *testing.T) {
 	}
 }
 
+func TestJSONQueryEndToEnd(t *testing.T) {
+	timezone, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		plan           entity.Plan
+		requests       []entity.APIRequest
+		repositoryErr  error
+		expectedValues map[string]any // float64 values are compared within 1e-9, nil means JSON null
+		expectError    bool
+	}{
+		{
+			name:     "costs and plan usage with pro plan",
+			plan:     entity.NewPlan("pro", entity.NewCost(20.0)),
+			requests: createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
+			expectedValues: map[string]any{
+				"daily_cost":           15.0,
+				"monthly_cost":         155.0,
+				"monthly_plan_usage":   775.0,
+				"base_cost":            5.0,
+				"premium_cost":         10.0,
+				"monthly_base_cost":    55.0,
+				"monthly_premium_cost": 100.0,
+				"daily_sessions":       5.0,
+				"monthly_sessions":     20.0,
+				"timezone":             "UTC",
+			},
+		},
+		{
+			name:     "unset plan has zero usage",
+			plan:     entity.NewPlan("unset", entity.NewCost(0)),
+			requests: createTestAPIRequests(3, 2, 10, 5, 5.0, 10.0, 50.0, 90.0),
+			expectedValues: map[string]any{
+				"daily_plan_usage":   0.0,
+				"monthly_plan_usage": 0.0,
+			},
+		},
+		{
+			name:     "hidden values are null",
+			plan:     entity.NewPlan("pro", entity.NewCost(20.0)),
+			requests: []entity.APIRequest{},
+			expectedValues: map[string]any{
+				"daily_cost":              0.0,
+				"daily_cost_per_session":  0.0,
+				"daily_tokens_per_dollar": nil,
+				"daily_cache_savings":     nil,
+				"daily_max_gap":           nil,
+				"schedule_cost":           nil,
+				"block_used":              nil,
+				"block_limit":             nil,
+				"block_usage":             0.0,
+				"block_remaining":         0.0,
+				"block_time_remaining":    nil,
+			},
+		},
+		{
+			name:          "repository error",
+			plan:          entity.NewPlan("pro", entity.NewCost(20.0)),
+			repositoryErr: fmt.Errorf("connection refused"),
+			expectError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, mockStatsRepo := testutil.NewMockRepositoryWithData(tt.requests)
+			if tt.repositoryErr != nil {
+				mockRepo.SetError(tt.repositoryErr)
+			}
+
+			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{}),
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(tt.plan),
+				service.NewTimePeriodFactory(timezone),
+			)
+
+			var output strings.Builder
+			queryHandler := cli.NewQueryHandler(cli.NewFormatRenderer(usageVariablesQuery))
+			queryHandler.SetOutput(&output)
+
+			err := queryHandler.HandleJSONQuery()
+
+			var object map[string]any
+			if decodeErr := json.Unmarshal([]byte(output.String()), &object); decodeErr != nil {
+				t.Fatalf("Output is not a JSON object: %v\n%s", decodeErr, output.String())
+			}
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if message, ok := object["error"].(string); !ok || !strings.Contains(message, "connection refused") {
+					t.Errorf("Expected error object, got %s", output.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(object) != len(entity.GetAllUsageVariables()) {
+				t.Errorf("Expected %d keys, got %d", len(entity.GetAllUsageVariables()), len(object))
+			}
+			for _, variable := range entity.GetAllUsageVariables() {
+				if _, ok := object[strings.TrimPrefix(variable.Key(), "@")]; !ok {
+					t.Errorf("Missing key for %s", variable.Key())
+				}
+			}
+
+			for key, expected := range tt.expectedValues {
+				actual, ok := object[key]
+				if !ok {
+					t.Errorf("Missing key %q", key)
+					continue
+				}
+				switch expected := expected.(type) {
+				case float64:
+					if number, ok := actual.(float64); !ok || math.Abs(number-expected) > 1e-9 {
+						t.Errorf("%s = %v, want %v", key, actual, expected)
+					}
+				default:
+					if actual != expected {
+						t.Errorf("%s = %v, want %v", key, actual, expected)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestVariableSubstitutionEdgeCases(t *testing.T) {
 	// Setup basic test environment using factory
-	_, mockStatsRepo := testutil.NewMockRepositoryWithData(createTestAPIRequests(1, 1, 5, 5, 10.0, 20.0, 50.0, 100.0))
+	mockRepo, mockStatsRepo := testutil.NewMockRepositoryWithData(createTestAPIRequests(1, 1, 5, 5, 10.0, 20.0, 50.0, 100.0))
 	mockPlanRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0)))
 
 	periodFactory := service.NewTimePeriodFactory(time.UTC)
 	calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+	sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
 	usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
 		calculateStatsQuery,
+		sessionsQuery,
 		mockPlanRepo,
 		periodFactory,
 	)
@@ -363,6 +537,26 @@ func TestVariableSubstitutionEdgeCases(t *testing.T) {
 			formatString:   "@daily_cost @unknown @monthly_cost",
 			expectedOutput: "$30.0 @unknown $180.0",
 		},
+		{
+			name:           "tier costs with unknown variables",
+			formatString:   "@base_cost @unknown_cost @premium_cost",
+			expectedOutput: "$10.0 @unknown_cost $20.0",
+		},
+		{
+			name:           "longer variable is not replaced by its prefix",
+			formatString:   "@daily_base_cost @base_cost @monthly_premium_cost",
+			expectedOutput: "$10.0 $10.0 $120.0",
+		},
+		{
+			name:           "variable sharing a prefix with a longer one",
+			formatString:   "@daily_cost_per_session @daily_cost",
+			expectedOutput: "$15.00 $30.0",
+		},
+		{
+			name:           "partial tier variable match will substitute",
+			formatString:   "@base_costs",
+			expectedOutput: "$10.0s",
+		},
 	}
 
 	for _, tt := range tests {
@@ -380,6 +574,119 @@ func TestVariableSubstitutionEdgeCases(t *testing.T) {
 	}
 }
 
+func TestStrictFormatValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		formatString string
+		errMsg       string
+	}{
+		{name: "known variables", formatString: "Daily: @daily_cost (@daily_plan_usage%)"},
+		{name: "no variables", formatString: "No variables here"},
+		{name: "e-mail address is not a variable", formatString: "user@example.com @daily_cost"},
+		{name: "misspelled variable", formatString: "@dailycost", errMsg: "unknown format variables: @dailycost (must be one of: @daily_cost,"},
+		{name: "variable with suffix", formatString: "prefix @daily_costsuffix", errMsg: "unknown format variables: @daily_costsuffix "},
+		{name: "tier cost variables", formatString: "@base_cost @premium_cost @daily_base_cost @monthly_premium_cost"},
+		{name: "tier variable with suffix", formatString: "@base_costs", errMsg: "unknown format variables: @base_costs "},
+		{name: "unknown variables listed once", formatString: "@foo @daily_cost @bar @foo", errMsg: "unknown format variables: @foo, @bar "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cli.ValidateFormatString(tt.formatString)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestStrictFormatRender(t *testing.T) {
+	mockRepo, mockStatsRepo := testutil.NewMockRepositoryWithData(createTestAPIRequests(1, 1, 5, 5, 10.0, 20.0, 50.0, 100.0))
+	mockPlanRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0)))
+	usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
+		usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{}),
+		usecase.NewCountSessionsQuery(mockRepo, true),
+		mockPlanRepo,
+		service.NewTimePeriodFactory(time.UTC),
+	)
+
+	renderer := cli.NewFormatRenderer(usageVariablesQuery)
+	renderer.SetStrict(true)
+
+	if _, err := renderer.Render("@daily_cost @unknown"); err == nil {
+		t.Error("Expected strict render to reject @unknown")
+	}
+
+	result, err := renderer.Render("@daily_cost")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != "$30.0" {
+		t.Errorf("Expected output %q, got %q", "$30.0", result)
+	}
+}
+
+func TestExplainFormat(t *testing.T) {
+	mockRepo, mockStatsRepo := testutil.NewMockRepositoryWithData(createTestAPIRequests(1, 1, 5, 5, 10.0, 20.0, 50.0, 100.0))
+	mockPlanRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0)))
+	usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
+		usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{}),
+		usecase.NewCountSessionsQuery(mockRepo, true),
+		mockPlanRepo,
+		service.NewTimePeriodFactory(time.UTC),
+	)
+
+	var output bytes.Buffer
+	handler := cli.NewQueryHandler(cli.NewFormatRenderer(usageVariablesQuery))
+	handler.SetOutput(&output)
+	handler.SetExplain(true)
+
+	if err := handler.HandleFormatQuery("@daily_cost @daily_plan_usage @daily_cost"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result := output.String()
+	usage := calculateExpectedDailyUsage(30.0, 20.0)
+	daysInMonth := entity.DaysInMonth(time.Now().UTC())
+	expected := []string{
+		"$30.0 " + usage + " $30.0\n",
+		"@daily_cost = $30.0\n",
+		"  Requests:          1 base, 1 premium\n",
+		"  Daily cost:        $30.0000\n",
+		"@daily_plan_usage = " + usage + "\n",
+		"  Plan:              pro ($20.0000/month)\n",
+		fmt.Sprintf("  Days in month:     %d\n", daysInMonth),
+		fmt.Sprintf("  Usage:             $30.0000 / $%.4f × 100 = %s (rounded down)\n", 20.0/float64(daysInMonth), usage),
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected explanation to contain %q, got:\n%s", want, result)
+		}
+	}
+	// Repeated variables are explained once, unused ones not at all
+	if strings.Count(result, "@daily_cost = ") != 1 {
+		t.Errorf("Expected @daily_cost to be explained once, got:\n%s", result)
+	}
+	if strings.Contains(result, "@monthly_cost") {
+		t.Errorf("Expected variables missing from the format string to be left out, got:\n%s", result)
+	}
+
+	output.Reset()
+	if err := handler.HandleFormatQuery("no variables"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output.String(), "No usage variables to explain") {
+		t.Errorf("Expected a note without variables, got:\n%s", output.String())
+	}
+}
+
 func TestOutputFormatSpecificationCompliance(t *testing.T) {
 	// Test that output formats exactly match the specification requirements
 	baseRequests := createTestAPIRequests(2, 3, 10, 15, 7.5, 22.5, 75.0, 225.0)
@@ -452,13 +759,15 @@ func TestOutputFormatSpecificationCompliance(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, mockStatsRepo := testutil.NewMockRepositoryWithData(tt.requests)
+			mockRepo, mockStatsRepo := testutil.NewMockRepositoryWithData(tt.requests)
 			mockPlanRepo := testutil.NewMockPlanRepository(tt.plan)
 
 			periodFactory := service.NewTimePeriodFactory(time.UTC)
 			calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
 			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
 				calculateStatsQuery,
+				sessionsQuery,
 				mockPlanRepo,
 				periodFactory,
 			)
@@ -499,10 +808,16 @@ func TestErrorHandlingAndTimeout(t *testing.T) {
 			description:   "Should handle timeout errors gracefully",
 		},
 		{
-			name:        "plan repository error - fallback to unset",
-			planErr:     fmt.Errorf("failed to read config"),
+			name:        "plan unavailable - fallback to unset",
+			planErr:     fmt.Errorf("failed to read config: %w", usecase.ErrPlanUnavailable),
 			expectError: false,
-			description: "Plan repository errors should fallback to unset plan (0%)",
+			description: "Unavailable plans should fallback to unset plan (0%)",
+		},
+		{
+			name:        "plan repository failure",
+			planErr:     fmt.Errorf("failed to read config"),
+			expectError: true,
+			description: "Other plan repository errors should fail instead of hiding the plan",
 		},
 		{
 			name:        "successful execution",
@@ -525,8 +840,10 @@ func TestErrorHandlingAndTimeout(t *testing.T) {
 
 			periodFactory := service.NewTimePeriodFactory(time.UTC)
 			calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
 			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
 				calculateStatsQuery,
+				sessionsQuery,
 				mockPlanRepo,
 				periodFactory,
 			)
@@ -570,3 +887,856 @@ func TestErrorHandlingAndTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestSummaryEndToEnd(t *testing.T) {
+	timezone := time.UTC
+	now := time.Now().In(timezone)
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, timezone)
+	daysInMonth := now.AddDate(0, 1, -now.Day()).Day()
+
+	// Spend exactly the daily budget of a plan priced at $1 per day
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", todayStart, "claude-3-5-sonnet-20241022", 1_000_000, 200_000, 1.0),
+	}
+	blockStart := now.Add(-2 * time.Hour).Truncate(time.Minute)
+	block := entity.NewBlock(blockStart)
+	noDecimals := 0
+
+	tests := []struct {
+		name            string
+		plan            entity.Plan
+		block           *entity.Block
+		locale          string
+		durationStyle   entity.DurationStyle
+		tokenDecimals   *int
+		percentDecimals int
+		expected        []string
+		excluded        []string
+	}{
+		{
+			name:     "plan budget without block",
+			plan:     entity.NewPlan("pro", entity.NewCost(float64(daysInMonth))),
+			expected: []string{"1.0/1.0 (100%)", "1.2M tok"},
+			excluded: []string{"left"},
+		},
+		{
+			name:     "unset plan with block",
+			plan:     entity.NewPlan("unset", entity.NewCost(0)),
+			block:    &block,
+			expected: []string{"$1.0", "1.2M tok", "h", "left"},
+		},
+		{
+			name:          "block time remaining in clock style",
+			plan:          entity.NewPlan("unset", entity.NewCost(0)),
+			block:         &block,
+			durationStyle: entity.DurationStyleClock,
+			expected:      []string{"| 3:00:00 left"},
+		},
+		{
+			name:     "locale decimal separator",
+			plan:     entity.NewPlan("unset", entity.NewCost(0)),
+			locale:   "de-DE",
+			expected: []string{"$1,0", "1,2M tok"},
+		},
+		{
+			name:          "token counts without decimals",
+			plan:          entity.NewPlan("unset", entity.NewCost(0)),
+			tokenDecimals: &noDecimals,
+			expected:      []string{"$1.0", "1M tok"},
+		},
+		{
+			name:            "plan usage with one decimal",
+			plan:            entity.NewPlan("pro", entity.NewCost(float64(daysInMonth))),
+			percentDecimals: 1,
+			expected:        []string{"1.0/1.0 (100.0%)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mockStatsRepo := testutil.NewMockRepositoryWithData(requests)
+			periodFactory := service.NewTimePeriodFactory(timezone)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+			summaryQuery := usecase.NewGetSummaryQuery(calculateStatsQuery, testutil.NewMockPlanRepository(tt.plan), periodFactory)
+
+			renderer := cli.NewSummaryRenderer(summaryQuery, tt.block)
+			if tt.locale != "" {
+				locale, err := entity.ParseNumberLocale(tt.locale)
+				if err != nil {
+					t.Fatalf("Unexpected locale error: %v", err)
+				}
+				renderer.SetNumberLocale(locale)
+			}
+			if tt.tokenDecimals != nil {
+				renderer.SetTokenDecimals(*tt.tokenDecimals)
+			}
+			renderer.SetPercentDecimals(tt.percentDecimals)
+			if tt.durationStyle != "" {
+				renderer.SetDurationStyle(tt.durationStyle)
+				renderer.SetClock(entity.NewFixedClock(blockStart.Add(2 * time.Hour)))
+			}
+			result, err := renderer.Render()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if strings.Contains(result, "\n") {
+				t.Errorf("Summary should be a single line, got %q", result)
+			}
+			for _, part := range tt.expected {
+				if !strings.Contains(result, part) {
+					t.Errorf("Expected summary %q to contain %q", result, part)
+				}
+			}
+			for _, part := range tt.excluded {
+				if strings.Contains(result, part) {
+					t.Errorf("Expected summary %q not to contain %q", result, part)
+				}
+			}
+		})
+	}
+}
+
+func TestExportEndToEnd(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", timestamp, "claude-3-5-sonnet-20241022", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("session-2", timestamp.Add(time.Minute), "claude-3-haiku-20240307", 10, 5, 0.01),
+	}
+
+	tests := []struct {
+		name     string
+		format   cli.ExportFormat
+		output   string
+		expected []string
+	}{
+		{
+			name:   "csv into nested directory",
+			format: cli.ExportFormatCSV,
+			output: filepath.Join("nested", "dir", "export.csv"),
+			expected: []string{
+				"timestamp,session_id,model,input_tokens,output_tokens,cache_read_tokens,cache_creation_tokens,total_tokens,cost_usd,duration_ms",
+				"2024-03-01T10:00:00Z,session-1,claude-3-5-sonnet-20241022,100,50,0,0,150,0.25,1500",
+			},
+		},
+		{
+			name:   "json lines",
+			format: cli.ExportFormatJSONL,
+			output: "export.jsonl",
+			expected: []string{
+				`{"timestamp":"2024-03-01T10:00:00Z","session_id":"session-1","model":"claude-3-5-sonnet-20241022","input_tokens":100,"output_tokens":50,"cache_read_tokens":0,"cache_creation_tokens":0,"total_tokens":150,"cost_usd":0.25,"duration_ms":1500}`,
+				`"session_id":"session-2"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(requests)
+			handler := cli.NewExportHandler(usecase.NewGetFilteredApiRequestsQuery(mockRepo), cli.NewExportRenderer(tt.format))
+
+			output := filepath.Join(t.TempDir(), tt.output)
+			if err := handler.HandleExport(output); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			content, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("Failed to read export: %v", err)
+			}
+			for _, part := range tt.expected {
+				if !strings.Contains(string(content), part) {
+					t.Errorf("Expected export to contain %q, got:\n%s", part, content)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadExportFileRoundTrip(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 123_000_000, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", timestamp, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 2_000, 300), entity.NewCost(0.25), 1500),
+		entity.NewAPIRequest("session-2", timestamp.Add(time.Minute), "claude-3-haiku-20240307", entity.NewToken(10, 5, 0, 0), entity.NewCost(0.0001), 42_000),
+	}
+
+	tests := []struct {
+		name   string
+		format cli.ExportFormat
+		output string
+		fields []string
+	}{
+		{name: "csv", format: cli.ExportFormatCSV, output: "export.csv"},
+		{name: "json lines", format: cli.ExportFormatJSONL, output: "export.jsonl"},
+		{name: "csv with reordered fields", format: cli.ExportFormatCSV, output: "fields.csv", fields: []string{"cost_usd", "timestamp", "duration_ms", "model", "session_id", "input_tokens", "output_tokens", "cache_read_tokens", "cache_creation_tokens"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(requests)
+			renderer := cli.NewExportRenderer(tt.format)
+			if err := renderer.SetFields(tt.fields); err != nil {
+				t.Fatalf("Unexpected fields error: %v", err)
+			}
+
+			output := filepath.Join(t.TempDir(), tt.output)
+			if err := cli.NewExportHandler(usecase.NewGetFilteredApiRequestsQuery(mockRepo), renderer).HandleExport(output); err != nil {
+				t.Fatalf("Unexpected export error: %v", err)
+			}
+
+			loaded, err := cli.LoadExportFile(output)
+			if err != nil {
+				t.Fatalf("Unexpected load error: %v", err)
+			}
+			if len(loaded) != len(requests) {
+				t.Fatalf("Expected %d requests, got %d", len(requests), len(loaded))
+			}
+			for i, want := range requests {
+				got := loaded[i]
+				if got.SessionID() != want.SessionID() || got.Model() != want.Model() || !got.Timestamp().Equal(want.Timestamp()) {
+					t.Errorf("Request %d: expected %s %s %v, got %s %s %v", i, want.SessionID(), want.Model(), want.Timestamp(), got.SessionID(), got.Model(), got.Timestamp())
+				}
+				if got.Tokens() != want.Tokens() || got.Cost().Amount() != want.Cost().Amount() || got.DurationMS() != want.DurationMS() {
+					t.Errorf("Request %d: expected %+v, got %+v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadExportFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		errMsg string
+	}{
+		{name: "unknown extension", path: write("usage.txt", ""), errMsg: "expected a .csv or .jsonl file"},
+		{name: "missing file", path: filepath.Join(dir, "missing.csv"), errMsg: "cannot read export file"},
+		{name: "missing timestamp column", path: write("no-time.csv", "session_id,cost_usd\nsession-1,0.25\n"), errMsg: "line 2: missing timestamp"},
+		{name: "invalid number", path: write("bad.jsonl", `{"timestamp":"2024-03-01T10:00:00Z","input_tokens":"many"}`), errMsg: `line 1: invalid input_tokens "many"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cli.LoadExportFile(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestBucketExportEndToEnd(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), "claude-3-5-sonnet-20241022", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC), "claude-3-haiku-20240307", 10, 5, 0.01),
+	})
+	handler := cli.NewBucketExportHandler(usecase.NewGetStatsByBucketQuery(mockRepo), cli.NewBucketExportRenderer(tokyo), entity.TimeBucketDay, tokyo)
+
+	output := filepath.Join(t.TempDir(), "daily.csv")
+	if err := handler.HandleExport(output); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	expected := "bucket_start,requests,input_tokens,output_tokens,cache_read_tokens,cache_creation_tokens,total_tokens,cost_usd\n" +
+		"2024-03-01T00:00:00+09:00,1,100,50,0,0,150,0.25\n" +
+		"2024-03-02T00:00:00+09:00,1,10,5,0,0,15,0.01\n"
+	if string(content) != expected {
+		t.Errorf("Expected export:\n%s\ngot:\n%s", expected, content)
+	}
+}
+
+func TestExportUnwritableOutput(t *testing.T) {
+	// A regular file in place of the parent directory makes the path unwritable
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatalf("Failed to create blocker file: %v", err)
+	}
+
+	callCount := 0
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	repo := testutil.NewInstrumentedRepository(mockRepo, &callCount)
+	handler := cli.NewExportHandler(usecase.NewGetFilteredApiRequestsQuery(repo), cli.NewExportRenderer(cli.ExportFormatCSV))
+
+	err := handler.HandleExport(filepath.Join(blocker, "export.csv"))
+	if err == nil {
+		t.Fatal("Expected error for unwritable output path")
+	}
+	if callCount != 0 {
+		t.Errorf("Expected export to fail before querying, got %d repository calls", callCount)
+	}
+}
+
+func TestExportFailureKeepsPreviousOutput(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "export.csv")
+	if err := os.WriteFile(output, []byte("previous export\n"), 0o644); err != nil {
+		t.Fatalf("Failed to create previous export: %v", err)
+	}
+
+	mockRepo := testutil.NewMockAPIRequestRepositoryWithError(fmt.Errorf("database locked"))
+	handler := cli.NewExportHandler(usecase.NewGetFilteredApiRequestsQuery(mockRepo), cli.NewExportRenderer(cli.ExportFormatCSV))
+
+	if err := handler.HandleExport(output); err == nil {
+		t.Fatal("Expected error when the query fails")
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil || string(content) != "previous export\n" {
+		t.Errorf("Expected the previous export to be kept, got %q (%v)", content, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to list output directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected cli.ExportFormat
+		wantErr  bool
+	}{
+		{input: "csv", expected: cli.ExportFormatCSV},
+		{input: "JSONL", expected: cli.ExportFormatJSONL},
+		{input: "sqlite", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := cli.ParseExportFormat(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExportFields(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", timestamp, "claude-3-5-sonnet-20241022", 100, 50, 0.25),
+	}
+
+	tests := []struct {
+		name     string
+		format   cli.ExportFormat
+		fields   string
+		expected string
+	}{
+		{
+			name:     "csv keeps the selected order",
+			format:   cli.ExportFormatCSV,
+			fields:   "cost_usd, timestamp,MODEL",
+			expected: "cost_usd,timestamp,model\n0.25,2024-03-01T10:00:00Z,claude-3-5-sonnet-20241022\n",
+		},
+		{
+			name:     "json lines use the same selection",
+			format:   cli.ExportFormatJSONL,
+			fields:   "model,total_tokens",
+			expected: `{"model":"claude-3-5-sonnet-20241022","total_tokens":150}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := cli.ParseExportFields(tt.fields)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			renderer := cli.NewExportRenderer(tt.format)
+			if err := renderer.SetFields(fields); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var output strings.Builder
+			if err := renderer.Render(&output, requests); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, output.String())
+			}
+		})
+	}
+}
+
+func TestParseExportFields(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		errMsg   string
+	}{
+		{input: "timestamp,model,cost_usd", expected: []string{"timestamp", "model", "cost_usd"}},
+		{input: " Duration_MS ,, session_id", expected: []string{"duration_ms", "session_id"}},
+		{input: "timestamp,price", errMsg: `unknown export field "price"`},
+		{input: "model,model", errMsg: `duplicate export field "model"`},
+		{input: " , ", errMsg: "no export fields given"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := cli.ParseExportFields(tt.input)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExportMinCost(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("expensive", timestamp, "claude-3-5-sonnet-20241022", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("trivial", timestamp.Add(time.Minute), "claude-3-haiku-20240307", 10, 5, 0.0001),
+	})
+
+	handler := cli.NewExportHandler(usecase.NewGetFilteredApiRequestsQuery(mockRepo), cli.NewExportRenderer(cli.ExportFormatCSV))
+	handler.SetMinCost(entity.NewCost(0.01))
+
+	output := filepath.Join(t.TempDir(), "export.csv")
+	if err := handler.HandleExport(output); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if !strings.Contains(string(content), "expensive") {
+		t.Errorf("Expected export to keep requests above the threshold, got:\n%s", content)
+	}
+	if strings.Contains(string(content), "trivial") {
+		t.Errorf("Expected export to skip requests below the threshold, got:\n%s", content)
+	}
+}
+
+func TestExportCostDecimals(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	// Added at run time, 0.1 + 0.2 is 0.30000000000000004
+	tenCents, twentyCents := 0.1, 0.2
+	noisy := testutil.CreateTestAPIRequest("noisy", timestamp, "claude-3-5-sonnet-20241022", 100, 50, tenCents+twentyCents)
+	precise := testutil.CreateTestAPIRequest("precise", timestamp.Add(time.Minute), "claude-3-haiku-20240307", 10, 5, 0.123456)
+	two, zero := 2, 0
+
+	tests := []struct {
+		name     string
+		format   cli.ExportFormat
+		decimals *int
+		expected string
+	}{
+		{
+			name:     "csv default decimals",
+			format:   cli.ExportFormatCSV,
+			expected: "session_id,cost_usd\nnoisy,0.3\nprecise,0.123456\n",
+		},
+		{
+			name:     "json lines default decimals",
+			format:   cli.ExportFormatJSONL,
+			expected: "{\"session_id\":\"noisy\",\"cost_usd\":0.3}\n{\"session_id\":\"precise\",\"cost_usd\":0.123456}\n",
+		},
+		{
+			name:     "fewer decimals",
+			format:   cli.ExportFormatCSV,
+			decimals: &two,
+			expected: "session_id,cost_usd\nnoisy,0.3\nprecise,0.12\n",
+		},
+		{
+			name:     "whole dollars",
+			format:   cli.ExportFormatCSV,
+			decimals: &zero,
+			expected: "session_id,cost_usd\nnoisy,0\nprecise,0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := cli.NewExportRenderer(tt.format)
+			if err := renderer.SetFields([]string{"session_id", "cost_usd"}); err != nil {
+				t.Fatalf("Unexpected fields error: %v", err)
+			}
+			if tt.decimals != nil {
+				renderer.SetCostDecimals(*tt.decimals)
+			}
+
+			var buf bytes.Buffer
+			if err := renderer.Render(&buf, []entity.APIRequest{noisy, precise}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected export:\n%s\ngot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+
+	t.Run("bucket sums", func(t *testing.T) {
+		first := testutil.CreateTestAPIRequest("session-1", timestamp, "claude-3-5-sonnet-20241022", 100, 50, tenCents)
+		second := testutil.CreateTestAPIRequest("session-2", timestamp.Add(time.Minute), "claude-3-5-sonnet-20241022", 100, 50, twentyCents)
+
+		var buf bytes.Buffer
+		err := cli.NewBucketExportRenderer(time.UTC).Render(&buf, []usecase.BucketStats{{
+			StartAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Stats:   entity.NewStatsFromRequests([]entity.APIRequest{first, second}, entity.NewAllTimePeriod(timestamp)),
+		}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.HasSuffix(buf.String(), ",0.3\n") {
+			t.Errorf("Expected the bucket cost rounded to 0.3, got:\n%s", buf.String())
+		}
+	})
+}
+
+func TestExportRenderer_ModelNames(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", timestamp, "claude-3-5-sonnet-20241022", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("session-2", timestamp, "claude-opus-4-20250514", 100, 50, 1.5),
+	}
+	names := entity.NewModelNameMap(entity.NewModelDisplayName("claude-3-5-sonnet-*", "Sonnet 3.5"))
+
+	tests := []struct {
+		name     string
+		format   cli.ExportFormat
+		names    entity.ModelNameMap
+		fields   []string
+		expected string
+	}{
+		{
+			name:     "default columns leave out the name without a mapping",
+			format:   cli.ExportFormatCSV,
+			expected: "timestamp,session_id,model,input_tokens,output_tokens,cache_read_tokens,cache_creation_tokens,total_tokens,cost_usd,duration_ms\n",
+		},
+		{
+			name:   "default columns add the name next to the raw model",
+			format: cli.ExportFormatCSV,
+			names:  names,
+			expected: "timestamp,session_id,model,model_name,input_tokens,output_tokens,cache_read_tokens,cache_creation_tokens,total_tokens,cost_usd,duration_ms\n" +
+				"2024-03-01T10:00:00Z,session-1,claude-3-5-sonnet-20241022,Sonnet 3.5,100,50,0,0,150,0.25,1500\n" +
+				"2024-03-01T10:00:00Z,session-2,claude-opus-4-20250514,claude-opus-4-20250514,100,50,0,0,150,1.5,1500\n",
+		},
+		{
+			name:     "selected fields",
+			format:   cli.ExportFormatJSONL,
+			names:    names,
+			fields:   []string{"model_name", "cost_usd"},
+			expected: "{\"model_name\":\"Sonnet 3.5\",\"cost_usd\":0.25}\n{\"model_name\":\"claude-opus-4-20250514\",\"cost_usd\":1.5}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := cli.NewExportRenderer(tt.format)
+			renderer.SetModelNames(tt.names)
+			if err := renderer.SetFields(tt.fields); err != nil {
+				t.Fatalf("Unexpected fields error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := renderer.Render(&buf, requests); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			// Without a mapping only the header is compared, the rows are covered by the other export tests
+			got := buf.String()
+			if tt.names.IsEmpty() {
+				got = strings.SplitAfter(got, "\n")[0]
+			}
+			if got != tt.expected {
+				t.Errorf("Expected export:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExportDurationRange(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	requestTaking := func(sessionID string, offset time.Duration, durationMS int64) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, timestamp.Add(offset), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.25), durationMS)
+	}
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		requestTaking("quick", 0, 1_500),
+		requestTaking("hung", time.Minute, 120_000),
+	})
+
+	durationRange, err := entity.ParseDurationRange("30s", "")
+	if err != nil {
+		t.Fatalf("Unexpected range error: %v", err)
+	}
+	handler := cli.NewExportHandler(usecase.NewGetFilteredApiRequestsQuery(mockRepo), cli.NewExportRenderer(cli.ExportFormatCSV))
+	handler.SetDurationRange(durationRange)
+
+	output := filepath.Join(t.TempDir(), "export.csv")
+	if err := handler.HandleExport(output); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if !strings.Contains(string(content), "hung") {
+		t.Errorf("Expected export to keep requests of at least 30s, got:\n%s", content)
+	}
+	if strings.Contains(string(content), "quick") {
+		t.Errorf("Expected export to skip shorter requests, got:\n%s", content)
+	}
+}
+
+func TestDatesEndToEnd(t *testing.T) {
+	timezone := time.UTC
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 1, 6, 10, 0, 0, 0, timezone), "claude-3-5-sonnet-20241022", 1_000, 500, 1.25),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 1, 13, 10, 0, 0, 0, timezone), "claude-3-5-sonnet-20241022", 2_000, 0, 0.75),
+	}
+
+	_, mockStatsRepo := testutil.NewMockRepositoryWithData(requests)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+	statsByDatesQuery := usecase.NewGetStatsByDatesQuery(calculateStatsQuery, service.NewTimePeriodFactory(timezone))
+
+	dates, err := cli.ParseDateList("2025-01-06, 2025-01-13,2025-01-20", timezone)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result, err := cli.NewDatesRenderer(statsByDatesQuery).Render(dates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected header, 3 dates and a total, got %q", result)
+	}
+	expected := [][]string{
+		{"Date", "Requests", "Tokens", "Cost"},
+		{"2025-01-06", "1", "1.5K", "$1.25"},
+		{"2025-01-13", "1", "2.0K", "$0.75"},
+		{"2025-01-20", "0", "0", "$0.00"},
+		{"Total", "2", "3.5K", "$2.00"},
+	}
+	for i, fields := range expected {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(fields, " ") {
+			t.Errorf("Line %d: expected %v, got %v", i, fields, got)
+		}
+	}
+}
+
+func TestPeakHoursEndToEnd(t *testing.T) {
+	timezone := time.UTC
+	now := time.Now().In(timezone)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, timezone)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", today.Add(-48*time.Hour+9*time.Hour), "claude-3-5-sonnet-20241022", 1_000, 500, 1.50),
+		testutil.CreateTestAPIRequest("session-2", today.Add(-24*time.Hour+9*time.Hour+30*time.Minute), "claude-3-5-sonnet-20241022", 2_000, 0, 1.50),
+		testutil.CreateTestAPIRequest("session-3", today.Add(-24*time.Hour+22*time.Hour), "claude-3-5-sonnet-20241022", 500, 0, 0.75),
+	}
+
+	mockAPIRepo, _ := testutil.NewMockRepositoryWithData(requests)
+	statsByHourQuery := usecase.NewGetStatsByHourOfDayQuery(mockAPIRepo, service.NewTimePeriodFactory(timezone))
+
+	result, err := cli.NewHoursRenderer(statsByHourQuery, timezone).Render(7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	if len(lines) != 26 {
+		t.Fatalf("Expected header, 24 hours and the peak, got %q", result)
+	}
+
+	expected := map[int][]string{
+		0:  {"Hour", "Requests", "Tokens", "Cost"},
+		1:  {"00:00", "0", "0", "$0.00"},
+		10: {"09:00", "2", "3.5K", "$3.00", strings.Repeat("█", 30)},
+		23: {"22:00", "1", "500", "$0.75", strings.Repeat("█", 8)},
+		25: {"Peak:", "09:00-10:00", "($3.00)"},
+	}
+	for i, fields := range expected {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(fields, " ") {
+			t.Errorf("Line %d: expected %v, got %v", i, fields, got)
+		}
+	}
+}
+
+func TestGroupByEndToEnd(t *testing.T) {
+	timezone := time.UTC
+	now := time.Date(2025, 1, 20, 12, 0, 0, 0, timezone)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-2*time.Hour), "claude-sonnet-4-20250514", 1_000, 500, 1.25),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-time.Hour), "claude-3-5-haiku-20241022", 2_000, 0, 0.25),
+		testutil.CreateTestAPIRequest("session-2", now.AddDate(0, 0, -10), "claude-sonnet-4-20250514", 500, 0, 0.75),
+	}
+
+	mockAPIRepo, _ := testutil.NewMockRepositoryWithData(requests)
+	periodFactory := service.NewTimePeriodFactory(timezone)
+	periodFactory.SetClock(entity.NewFixedClock(now))
+	statsByGroupQuery := usecase.NewGetStatsByGroupQuery(mockAPIRepo, periodFactory)
+
+	t.Run("table", func(t *testing.T) {
+		result, err := cli.NewGroupRenderer(statsByGroupQuery, timezone, "").Render(entity.GroupByModel)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+		expected := [][]string{
+			{"Model", "Requests", "Tokens", "Cost"},
+			{"claude-sonnet-4-20250514", "2", "2.0K", "$2.00"},
+			{"claude-3-5-haiku-20241022", "1", "2.0K", "$0.25"},
+			{"Total", "3", "4.0K", "$2.25"},
+		}
+		if len(lines) != len(expected) {
+			t.Fatalf("Expected header, 2 models and a total, got %q", result)
+		}
+		for i, fields := range expected {
+			if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(fields, " ") {
+				t.Errorf("Line %d: expected %v, got %v", i, fields, got)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		renderer := cli.NewGroupRenderer(statsByGroupQuery, timezone, "")
+		renderer.SetJSON(true)
+		result, err := renderer.Render(entity.GroupByDay)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var groups []map[string]any
+		if err := json.Unmarshal([]byte(result), &groups); err != nil {
+			t.Fatalf("Expected a JSON array, got %q: %v", result, err)
+		}
+		if len(groups) != 2 {
+			t.Fatalf("Expected 2 days, got %d", len(groups))
+		}
+		if groups[0]["group"] != "2025-01-20" || groups[0]["requests"] != float64(2) || groups[0]["cost_usd"] != 1.5 {
+			t.Errorf("Unexpected first group: %v", groups[0])
+		}
+		if groups[1]["group"] != "2025-01-10" || groups[1]["total_tokens"] != float64(500) {
+			t.Errorf("Unexpected second group: %v", groups[1])
+		}
+	})
+}
+
+func TestParseDateList(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "single date", input: "2025-01-06", expected: []string{"2025-01-06"}},
+		{name: "list with spaces", input: "2025-01-06, 2025-01-13", expected: []string{"2025-01-06", "2025-01-13"}},
+		{name: "invalid date in list", input: "2025-01-06,2025-13-01", wantErr: true},
+		{name: "wrong layout", input: "01/06/2025", wantErr: true},
+		{name: "empty list", input: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates, err := cli.ParseDateList(tt.input, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(dates) != len(tt.expected) {
+				t.Fatalf("Expected %d dates, got %d", len(tt.expected), len(dates))
+			}
+			for i, date := range dates {
+				if got := date.Format("2006-01-02"); got != tt.expected[i] {
+					t.Errorf("Date %d: expected %s, got %s", i, tt.expected[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryHandlerErrorOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		errorOutput *string
+		expected    string
+	}{
+		{name: "default error output", expected: cli.DefaultFormatErrorOutput},
+		{name: "custom error output", errorOutput: stringPtr("@daily_cost=?"), expected: "@daily_cost=?"},
+		{name: "empty error output", errorOutput: stringPtr(""), expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, mockStatsRepo := testutil.NewMockRepositoryWithData(nil)
+			mockRepo.SetError(fmt.Errorf("connection refused"))
+
+			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{}),
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0))),
+				service.NewTimePeriodFactory(time.UTC),
+			)
+
+			var output strings.Builder
+			queryHandler := cli.NewQueryHandler(cli.NewFormatRenderer(usageVariablesQuery))
+			queryHandler.SetOutput(&output)
+			if tt.errorOutput != nil {
+				queryHandler.SetErrorOutput(*tt.errorOutput)
+			}
+
+			if err := queryHandler.HandleFormatQuery("@daily_cost"); err == nil {
+				t.Fatal("Expected error from failed query")
+			}
+			if output.String() != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, output.String())
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
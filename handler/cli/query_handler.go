@@ -1,27 +1,69 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 )
 
+// DefaultFormatErrorOutput is printed in place of the rendered format string when a query fails
+const DefaultFormatErrorOutput = "❌ ERROR"
+
 type QueryHandler struct {
-	renderer *FormatRenderer
+	renderer    *FormatRenderer
+	errorOutput string
+	output      io.Writer
+	explain     bool
 }
 
 func NewQueryHandler(renderer *FormatRenderer) *QueryHandler {
 	return &QueryHandler{
-		renderer: renderer,
+		renderer:    renderer,
+		errorOutput: DefaultFormatErrorOutput,
+		output:      os.Stdout,
 	}
 }
 
+// SetErrorOutput sets the text printed when the query fails; an empty string prints nothing
+func (h *QueryHandler) SetErrorOutput(errorOutput string) {
+	h.errorOutput = errorOutput
+}
+
+// SetOutput sets where the rendered result is written, standard output by default
+func (h *QueryHandler) SetOutput(output io.Writer) {
+	h.output = output
+}
+
+// SetExplain follows the rendered result with how each variable in the format string was computed
+func (h *QueryHandler) SetExplain(explain bool) {
+	h.explain = explain
+}
+
 func (h *QueryHandler) HandleFormatQuery(formatString string) error {
 	result, err := h.processFormat(formatString)
 	h.outputResult(result, err)
 	return err
 }
 
+// HandleJSONQuery prints every usage variable as a JSON object instead of substituting a format string.
+// A failed query prints {"error": "..."} in its place and the error is returned.
+func (h *QueryHandler) HandleJSONQuery() error {
+	result, err := h.renderer.RenderJSON()
+	if err != nil {
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		fmt.Fprintln(h.output, string(data))
+		return err
+	}
+	fmt.Fprint(h.output, result)
+	return nil
+}
+
 func (h *QueryHandler) processFormat(formatString string) (string, error) {
 	// Use FormatRenderer to handle variable substitution
+	if h.explain {
+		return h.renderer.Explain(formatString)
+	}
 	return h.renderer.Render(formatString)
 }
 
@@ -29,8 +71,8 @@ func (h *QueryHandler) outputResult(result string, err error) {
 	if err != nil {
 		// Output consistent error message for all failure scenarios
 		// This provides graceful degradation as specified in requirements
-		fmt.Print("❌ ERROR")
+		fmt.Fprint(h.output, h.errorOutput)
 	} else {
-		fmt.Print(result)
+		fmt.Fprint(h.output, result)
 	}
 }
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/elct9620/ccmon/usecase"
+)
+
+type ReplayHandler struct {
+	replayCommand *usecase.ReplayApiRequestsCommand
+	output        io.Writer
+}
+
+func NewReplayHandler(replayCommand *usecase.ReplayApiRequestsCommand) *ReplayHandler {
+	return &ReplayHandler{
+		replayCommand: replayCommand,
+		output:        os.Stdout,
+	}
+}
+
+// SetOutput sets where progress is written, standard output by default
+func (h *ReplayHandler) SetOutput(output io.Writer) {
+	h.output = output
+}
+
+// HandleReplay replays every local request to the remote server, printing a progress line per day.
+// Interrupting with Ctrl+C stops after the current request; running it again resumes.
+func (h *ReplayHandler) HandleReplay(target string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return h.Replay(ctx, target)
+}
+
+// Replay runs the replay and reports its progress and outcome
+func (h *ReplayHandler) Replay(ctx context.Context, target string) error {
+	fmt.Fprintf(h.output, "Replaying to %s\n", target)
+
+	progress, err := h.replayCommand.Execute(ctx, func(progress usecase.ReplayProgress) {
+		fmt.Fprintf(h.output, "%s: %d/%d requests (%d sent, %d already stored)\n",
+			progress.Day.Format(time.DateOnly), progress.Done(), progress.Total, progress.Sent, progress.Skipped)
+	})
+	if err != nil {
+		fmt.Fprintf(h.output, "Stopped after %d/%d requests (%d sent); run --replay again to resume\n", progress.Done(), progress.Total, progress.Sent)
+		return err
+	}
+
+	fmt.Fprintf(h.output, "Replayed %d requests: %d sent, %d already stored\n", progress.Total, progress.Sent, progress.Skipped)
+	return nil
+}
@@ -0,0 +1,92 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// fakeReplayIngester stores replayed requests in the target repository
+type fakeReplayIngester struct {
+	target *testutil.MockAPIRequestRepository
+	err    error
+}
+
+func (f *fakeReplayIngester) Ingest(ctx context.Context, req entity.APIRequest) error {
+	if f.err != nil {
+		return f.err
+	}
+	return f.target.Save(req)
+}
+
+func TestReplayHandler_Replay(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", start, "claude-sonnet-4-20250514", 100, 50, 0.5),
+		testutil.CreateTestAPIRequest("session-1", start.Add(24*time.Hour), "claude-sonnet-4-20250514", 100, 50, 0.5),
+	}
+
+	tests := []struct {
+		name      string
+		stored    []entity.APIRequest
+		ingestErr error
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name: "reports progress per day",
+			expected: []string{
+				"Replaying to remote:4317",
+				"2025-07-01: 1/2 requests (1 sent, 0 already stored)",
+				"2025-07-02: 2/2 requests (2 sent, 0 already stored)",
+				"Replayed 2 requests: 2 sent, 0 already stored",
+			},
+		},
+		{
+			name:     "counts requests already stored",
+			stored:   requests[:1],
+			expected: []string{"Replayed 2 requests: 1 sent, 1 already stored"},
+		},
+		{
+			name:      "explains how to resume after a failure",
+			ingestErr: errors.New("unavailable"),
+			expected:  []string{"Stopped after 0/2 requests (0 sent); run --replay again to resume"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := testutil.NewMockAPIRequestRepository()
+			source.SetMockData(requests)
+			target := testutil.NewMockAPIRequestRepository()
+			target.SetMockData(append([]entity.APIRequest(nil), tt.stored...))
+
+			var output bytes.Buffer
+			handler := cli.NewReplayHandler(usecase.NewReplayApiRequestsCommand(source, target, &fakeReplayIngester{target: target, err: tt.ingestErr}))
+			handler.SetOutput(&output)
+
+			err := handler.Replay(context.Background(), "remote:4317")
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			for _, line := range tt.expected {
+				if !strings.Contains(output.String(), line) {
+					t.Errorf("expected output to contain %q, got:\n%s", line, output.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// DatabaseInspector opens a database file and counts the requests it holds.
+// Opening fails while another process, such as a running server, holds the database.
+type DatabaseInspector func(path string) (int, error)
+
+// RestoreResult describes a restored database
+type RestoreResult struct {
+	Records  int
+	Replaced string // Where the previous database was moved, empty when none existed
+}
+
+type RestoreHandler struct {
+	inspect DatabaseInspector
+	force   bool
+}
+
+func NewRestoreHandler(inspect DatabaseInspector) *RestoreHandler {
+	return &RestoreHandler{
+		inspect: inspect,
+	}
+}
+
+// SetForce allows replacing an existing database; the previous file is kept with a .bak suffix,
+// numbered (.bak.1, .bak.2, ...) when earlier restores left one behind
+func (h *RestoreHandler) SetForce(force bool) {
+	h.force = force
+}
+
+// HandleRestore restores a backup into the database path and reports the record count
+func (h *RestoreHandler) HandleRestore(input, databasePath string) error {
+	result, err := h.Restore(input, databasePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored %d requests to %s\n", result.Records, databasePath)
+	if result.Replaced != "" {
+		fmt.Printf("Previous database moved to %s\n", result.Replaced)
+	}
+	return nil
+}
+
+// Restore decompresses the backup next to the database, verifies it and moves it into place.
+// The existing database is only touched once the backup has been verified.
+func (h *RestoreHandler) Restore(input, databasePath string) (*RestoreResult, error) {
+	exists, err := h.checkDestination(databasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(databasePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create database directory %s: %w", dir, err)
+	}
+
+	tempPath, err := decompressBackup(input, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.Remove(tempPath) }()
+
+	records, err := h.inspect(tempPath)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s is not a valid ccmon database: %w", input, err)
+	}
+
+	result := &RestoreResult{Records: records}
+	if exists {
+		result.Replaced, err = unusedBackupPath(databasePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Rename(databasePath, result.Replaced); err != nil {
+			return nil, fmt.Errorf("cannot move existing database aside: %w", err)
+		}
+	}
+	if err := os.Rename(tempPath, databasePath); err != nil {
+		return nil, fmt.Errorf("cannot move restored database into place: %w", err)
+	}
+
+	return result, nil
+}
+
+// checkDestination refuses to replace a database without force, or one that is in use
+func (h *RestoreHandler) checkDestination(databasePath string) (bool, error) {
+	if _, err := os.Stat(databasePath); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("cannot access database %s: %w", databasePath, err)
+	}
+
+	if !h.force {
+		return true, fmt.Errorf("database %s already exists (use --force to replace it)", databasePath)
+	}
+	if _, err := h.inspect(databasePath); err != nil {
+		return true, fmt.Errorf("cannot open database %s, stop the server before restoring: %w", databasePath, err)
+	}
+
+	return true, nil
+}
+
+// unusedBackupPath returns the first of path.bak, path.bak.1, path.bak.2, ... that does not exist,
+// so a restore never overwrites the database kept by an earlier one
+func unusedBackupPath(path string) (string, error) {
+	candidate := path + ".bak"
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate, nil
+		} else if err != nil {
+			return "", fmt.Errorf("cannot access %s: %w", candidate, err)
+		}
+		candidate = fmt.Sprintf("%s.bak.%d", path, i)
+	}
+}
+
+// decompressBackup writes the decompressed backup to a temporary file in dir and returns its path
+func decompressBackup(input, dir string) (string, error) {
+	source, err := os.Open(input)
+	if err != nil {
+		return "", fmt.Errorf("cannot read backup file %s: %w", input, err)
+	}
+	defer func() { _ = source.Close() }()
+
+	decoder, err := zstd.NewReader(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to start decompression: %w", err)
+	}
+	defer decoder.Close()
+
+	temp, err := os.CreateTemp(dir, ".ccmon-restore-*.db")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temporary database: %w", err)
+	}
+
+	if _, err := io.Copy(temp, decoder); err != nil {
+		_ = temp.Close()
+		_ = os.Remove(temp.Name())
+		return "", fmt.Errorf("failed to decompress backup %s: %w", input, err)
+	}
+	if err := temp.Close(); err != nil {
+		_ = os.Remove(temp.Name())
+		return "", fmt.Errorf("cannot write temporary database: %w", err)
+	}
+
+	return temp.Name(), nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// StorageInfoSource reports the state of the server's database
+type StorageInfoSource interface {
+	GetStorageInfo(ctx context.Context) (entity.StorageInfo, error)
+}
+
+type StorageInfoHandler struct {
+	source   StorageInfoSource
+	timezone *time.Location
+}
+
+func NewStorageInfoHandler(source StorageInfoSource, timezone *time.Location) *StorageInfoHandler {
+	return &StorageInfoHandler{
+		source:   source,
+		timezone: timezone,
+	}
+}
+
+// HandleStorageInfo prints the database size, record count and stored time range
+func (h *StorageInfoHandler) HandleStorageInfo() error {
+	result, err := h.Describe()
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(result)
+	return nil
+}
+
+// Describe returns the state of the database, one value per line with timestamps in the handler's timezone
+func (h *StorageInfoHandler) Describe() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	info, err := h.source.GetStorageInfo(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get storage info: %w", err)
+	}
+
+	oldest, newest := "-", "-"
+	if !info.IsEmpty() {
+		oldest = info.OldestAt().In(h.timezone).Format("2006-01-02 15:04:05")
+		newest = info.NewestAt().In(h.timezone).Format("2006-01-02 15:04:05")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Database size: %s\n", formatByteSize(info.SizeBytes()))
+	fmt.Fprintf(&b, "Records:       %d\n", info.Records())
+	fmt.Fprintf(&b, "Oldest:        %s\n", oldest)
+	fmt.Fprintf(&b, "Newest:        %s\n", newest)
+	return b.String(), nil
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+)
+
+type fakeStorageInfoSource struct {
+	info entity.StorageInfo
+	err  error
+}
+
+func (f *fakeStorageInfoSource) GetStorageInfo(ctx context.Context) (entity.StorageInfo, error) {
+	return f.info, f.err
+}
+
+func TestStorageInfoHandler_Describe(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		source      *fakeStorageInfoSource
+		expected    string
+		expectError bool
+	}{
+		{
+			name: "stored requests in the timezone",
+			source: &fakeStorageInfoSource{info: entity.NewStorageInfo(1572864, 1234,
+				time.Date(2025, 6, 1, 0, 30, 0, 0, time.UTC),
+				time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC))},
+			expected: "Database size: 1.5 MiB\n" +
+				"Records:       1234\n" +
+				"Oldest:        2025-06-01 09:30:00\n" +
+				"Newest:        2025-07-24 21:00:00\n",
+		},
+		{
+			name:   "empty database",
+			source: &fakeStorageInfoSource{info: entity.NewStorageInfo(32768, 0, time.Time{}, time.Time{})},
+			expected: "Database size: 32.0 KiB\n" +
+				"Records:       0\n" +
+				"Oldest:        -\n" +
+				"Newest:        -\n",
+		},
+		{
+			name:        "server error",
+			source:      &fakeStorageInfoSource{err: errors.New("PermissionDenied: server.auth_token must be configured to use this method")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := cli.NewStorageInfoHandler(tt.source, tokyo).Describe()
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
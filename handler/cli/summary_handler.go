@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"fmt"
+)
+
+type SummaryHandler struct {
+	renderer *SummaryRenderer
+}
+
+func NewSummaryHandler(renderer *SummaryRenderer) *SummaryHandler {
+	return &SummaryHandler{
+		renderer: renderer,
+	}
+}
+
+func (h *SummaryHandler) HandleSummaryQuery() error {
+	result, err := h.renderer.Render()
+	if err != nil {
+		// Same graceful degradation as format queries
+		fmt.Print("❌ ERROR")
+		return err
+	}
+
+	fmt.Print(result)
+	return nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+type SummaryRenderer struct {
+	summaryQuery    *usecase.GetSummaryQuery
+	block           *entity.Block
+	clock           entity.Clock
+	numberLocale    entity.NumberLocale
+	durationStyle   entity.DurationStyle
+	tokenDecimals   int
+	percentDecimals int
+}
+
+// NewSummaryRenderer creates a summary renderer, the block portion is only rendered when block is set
+func NewSummaryRenderer(summaryQuery *usecase.GetSummaryQuery, block *entity.Block) *SummaryRenderer {
+	return &SummaryRenderer{
+		summaryQuery:  summaryQuery,
+		block:         block,
+		clock:         entity.SystemClock{},
+		numberLocale:  entity.DefaultNumberLocale,
+		tokenDecimals: defaultTokenDecimals,
+	}
+}
+
+// SetClock sets the source of the current time used for the block time remaining
+func (r *SummaryRenderer) SetClock(clock entity.Clock) {
+	r.clock = clock
+}
+
+// SetNumberLocale sets the decimal and grouping separators of costs and token counts
+func (r *SummaryRenderer) SetNumberLocale(locale entity.NumberLocale) {
+	r.numberLocale = locale
+}
+
+// SetDurationStyle sets how the block time remaining is rendered
+func (r *SummaryRenderer) SetDurationStyle(style entity.DurationStyle) {
+	r.durationStyle = style
+}
+
+// SetTokenDecimals sets the decimal places of abbreviated token counts, negative keeps the default of 1
+func (r *SummaryRenderer) SetTokenDecimals(decimals int) {
+	if decimals < 0 {
+		decimals = defaultTokenDecimals
+	}
+	r.tokenDecimals = decimals
+}
+
+// SetPercentDecimals sets the decimal places of the daily plan usage (default: whole percent)
+func (r *SummaryRenderer) SetPercentDecimals(decimals int) {
+	r.percentDecimals = decimals
+}
+
+// Render renders a single-line summary like "15.0/20.0 (75%) | 1.2M tok | 2h 15m left"
+func (r *SummaryRenderer) Render() (string, error) {
+	// Create context with timeout to prevent hanging
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	summary, err := r.summaryQuery.Execute(ctx, usecase.GetSummaryParams{
+		Block: r.block,
+		Now:   r.clock.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return r.format(summary), nil
+}
+
+func (r *SummaryRenderer) format(summary *usecase.Summary) string {
+	parts := make([]string, 0, 4)
+
+	// Daily cost, against the daily plan budget when a plan is configured
+	if summary.DailyBudget.Amount() > 0 {
+		parts = append(parts, fmt.Sprintf("%s/%s (%s)",
+			r.numberLocale.FormatFloat(summary.DailyCost.Amount(), 1),
+			r.numberLocale.FormatFloat(summary.DailyBudget.Amount(), 1),
+			r.numberLocale.FormatPercent(summary.DailyPlanPercent, r.percentDecimals)))
+	} else {
+		parts = append(parts, summary.DailyCost.FormatLocale(entity.CostStyleDefault, r.numberLocale))
+	}
+
+	parts = append(parts, formatTokenCount(summary.DailyTokens.Total(), r.numberLocale, r.tokenDecimals)+" tok")
+
+	if summary.ScheduleCost != nil {
+		parts = append(parts, summary.ScheduleCost.FormatLocale(entity.CostStyleDefault, r.numberLocale)+" sched")
+	}
+
+	if summary.Block != nil {
+		parts = append(parts, r.durationStyle.Format(summary.BlockTimeRemaining)+" left")
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// defaultTokenDecimals is the decimal places of abbreviated token counts, e.g. 1.2M
+const defaultTokenDecimals = 1
+
+// formatTokenCount formats a token count with K/M suffixes using the locale's decimal separator
+func formatTokenCount(count int64, locale entity.NumberLocale, decimals int) string {
+	return locale.FormatTokenCount(count, decimals, decimals)
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// CommonTimezones are suggested when checking the configured timezone; names missing from
+// the system's timezone database are skipped
+var CommonTimezones = []string{
+	"UTC",
+	"Local",
+	"America/New_York",
+	"America/Chicago",
+	"America/Los_Angeles",
+	"Europe/London",
+	"Europe/Berlin",
+	"Asia/Kolkata",
+	"Asia/Shanghai",
+	"Asia/Taipei",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+}
+
+// timezoneTimeLayout formats the current time in each timezone
+const timezoneTimeLayout = "2006-01-02 15:04:05 MST"
+
+type TimezoneHandler struct {
+	configured string
+	clock      entity.Clock
+}
+
+func NewTimezoneHandler(configured string) *TimezoneHandler {
+	return &TimezoneHandler{
+		configured: configured,
+		clock:      entity.SystemClock{},
+	}
+}
+
+// SetClock sets the source of the current time shown for each timezone
+func (h *TimezoneHandler) SetClock(clock entity.Clock) {
+	h.clock = clock
+}
+
+// HandleTimezoneCheck prints the timezone report and returns an error when the configured timezone is invalid
+func (h *TimezoneHandler) HandleTimezoneCheck() error {
+	result, err := h.Check()
+	fmt.Print(result)
+	return err
+}
+
+// Check reports whether the configured timezone loads, the current time in it and the system
+// timezone, followed by common valid names
+func (h *TimezoneHandler) Check() (string, error) {
+	now := h.clock.Now()
+
+	var b strings.Builder
+	configured := h.configured
+	if configured == "" {
+		// time.LoadLocation treats an empty name as UTC
+		configured = `"" (UTC)`
+	}
+	fmt.Fprintf(&b, "Configured timezone: %s\n", configured)
+
+	location, err := time.LoadLocation(h.configured)
+	if err != nil {
+		fmt.Fprintf(&b, "Status: ❌ invalid (%v)\n", err)
+	} else {
+		fmt.Fprintf(&b, "Status: ✅ valid\n")
+		fmt.Fprintf(&b, "Current time: %s\n", formatTimezoneTime(now, location))
+	}
+	fmt.Fprintf(&b, "UTC time: %s\n", formatTimezoneTime(now, time.UTC))
+	fmt.Fprintf(&b, "System timezone: %s\n", formatTimezoneTime(now, time.Local))
+
+	b.WriteString("\nCommon timezones:\n")
+	for _, name := range CommonTimezones {
+		candidate, loadErr := time.LoadLocation(name)
+		if loadErr != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "  %-20s %s\n", name, formatUTCOffset(now.In(candidate)))
+	}
+
+	if err != nil {
+		return b.String(), fmt.Errorf("invalid timezone %q: %w", h.configured, err)
+	}
+	return b.String(), nil
+}
+
+// formatTimezoneTime formats the time in a location followed by its UTC offset
+func formatTimezoneTime(t time.Time, location *time.Location) string {
+	local := t.In(location)
+	return local.Format(timezoneTimeLayout) + " (" + formatUTCOffset(local) + ")"
+}
+
+// formatUTCOffset formats the offset of a time from UTC, e.g. UTC+08:00
+func formatUTCOffset(t time.Time) string {
+	return "UTC" + t.Format("-07:00")
+}
@@ -0,0 +1,81 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+)
+
+func TestTimezoneHandler_Check(t *testing.T) {
+	now := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		configured  string
+		expected    []string
+		unexpected  []string
+		expectError bool
+	}{
+		{
+			name:       "valid timezone shows local time and offset",
+			configured: "Asia/Tokyo",
+			expected: []string{
+				"Configured timezone: Asia/Tokyo",
+				"Status: ✅ valid",
+				"Current time: 2025-07-01 19:00:00 JST (UTC+09:00)",
+				"UTC time: 2025-07-01 10:00:00 UTC (UTC+00:00)",
+				"Common timezones:",
+				"America/New_York     UTC-04:00",
+			},
+		},
+		{
+			name:       "empty timezone loads as UTC",
+			configured: "",
+			expected: []string{
+				`Configured timezone: "" (UTC)`,
+				"Status: ✅ valid",
+				"Current time: 2025-07-01 10:00:00 UTC (UTC+00:00)",
+			},
+		},
+		{
+			name:       "invalid timezone reports the load error",
+			configured: "Mars/Olympus_Mons",
+			expected: []string{
+				"Configured timezone: Mars/Olympus_Mons",
+				"Status: ❌ invalid (unknown time zone Mars/Olympus_Mons)",
+				"Common timezones:",
+			},
+			unexpected:  []string{"Current time:"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := cli.NewTimezoneHandler(tt.configured)
+			handler.SetClock(entity.NewFixedClock(now))
+
+			result, err := handler.Check()
+			if tt.expectError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(result, expected) {
+					t.Errorf("Expected result to contain %q, got:\n%s", expected, result)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(result, unexpected) {
+					t.Errorf("Expected result not to contain %q, got:\n%s", unexpected, result)
+				}
+			}
+		})
+	}
+}
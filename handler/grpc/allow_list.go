@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/netip"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AllowListInterceptor rejects calls from peers outside the allowed networks.
+// It runs before authentication, so hosts outside the list never reach the token check.
+type AllowListInterceptor struct {
+	networks []netip.Prefix
+}
+
+// NewAllowListInterceptor creates an interceptor allowing peers in networks; no networks disables the check
+func NewAllowListInterceptor(networks []netip.Prefix) *AllowListInterceptor {
+	return &AllowListInterceptor{
+		networks: networks,
+	}
+}
+
+// IsEnabled returns true if the interceptor rejects any peers
+func (a *AllowListInterceptor) IsEnabled() bool {
+	return len(a.networks) > 0
+}
+
+// Unary returns a unary server interceptor enforcing the allow-list
+func (a *AllowListInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.check(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a stream server interceptor enforcing the allow-list
+func (a *AllowListInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.check(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check rejects the call unless the peer address is inside an allowed network
+func (a *AllowListInterceptor) check(ctx context.Context) error {
+	if !a.IsEnabled() {
+		return nil
+	}
+
+	addr, ok := peerAddr(ctx)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "peer address is not allowed")
+	}
+
+	for _, network := range a.networks {
+		if network.Contains(addr) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "peer address %s is not allowed", addr)
+}
+
+// peerAddr returns the IP address of the calling peer, with IPv4-mapped IPv6 addresses unmapped
+func peerAddr(ctx context.Context) (netip.Addr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}, false
+	}
+
+	host := p.Addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestAllowListInterceptor_Unary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		networks     []string
+		client       string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "empty list allows every peer",
+			client:       "203.0.113.5:5000",
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "peer inside a network is allowed",
+			networks:     []string{"10.0.0.0/8", "192.168.1.0/24"},
+			client:       "192.168.1.20:5000",
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "peer outside every network is rejected",
+			networks:     []string{"10.0.0.0/8", "192.168.1.0/24"},
+			client:       "192.168.2.20:5000",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "single host network",
+			networks:     []string{"127.0.0.1/32"},
+			client:       "127.0.0.1:5000",
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "IPv6 peer",
+			networks:     []string{"fd00::/8"},
+			client:       "[fd12::1]:5000",
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "IPv4-mapped IPv6 peer matches an IPv4 network",
+			networks:     []string{"10.0.0.0/8"},
+			client:       "[::ffff:10.1.2.3]:5000",
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing peer is rejected",
+			networks:     []string{"10.0.0.0/8"},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			networks := make([]netip.Prefix, 0, len(tt.networks))
+			for _, network := range tt.networks {
+				networks = append(networks, netip.MustParsePrefix(network))
+			}
+			interceptor := NewAllowListInterceptor(networks)
+
+			ctx := context.Background()
+			if tt.client != "" {
+				addr, err := net.ResolveTCPAddr("tcp", tt.client)
+				if err != nil {
+					t.Fatalf("invalid client address %q: %v", tt.client, err)
+				}
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			}
+
+			_, err := interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/ccmon.v1.QueryService/GetStats"}, handler)
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Errorf("expected code %v, got %v (err: %v)", tt.expectedCode, code, err)
+			}
+		})
+	}
+}
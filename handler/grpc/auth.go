@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationMetadataKey is the metadata key carrying the auth token
+const authorizationMetadataKey = "authorization"
+
+// deleteByPeriodMethod is the full method name of the destructive DeleteByPeriod RPC
+const deleteByPeriodMethod = "/ccmon.v1.QueryService/DeleteByPeriod"
+
+// bulkAppendMethod is the full method name of the BulkAppend RPC, which writes to the store
+const bulkAppendMethod = "/ccmon.v1.QueryService/BulkAppend"
+
+// setIngestionPausedMethod is the full method name of the SetIngestionPaused RPC, which stops ingestion
+const setIngestionPausedMethod = "/ccmon.v1.QueryService/SetIngestionPaused"
+
+// setNoteMethod is the full method name of the SetNote RPC, which writes request notes to the store
+const setNoteMethod = "/ccmon.v1.QueryService/SetNote"
+
+// getStorageInfoMethod is the full method name of the GetStorageInfo RPC, which describes the server's database
+const getStorageInfoMethod = "/ccmon.v1.QueryService/GetStorageInfo"
+
+// AuthInterceptor validates the auth token sent by clients in the request metadata
+type AuthInterceptor struct {
+	token            string
+	protectedMethods map[string]bool
+}
+
+// NewAuthInterceptor creates a new auth interceptor.
+// When token is empty, authentication is disabled and protected methods are rejected,
+// as they must never be reachable without authentication.
+func NewAuthInterceptor(token string, protectedMethods ...string) *AuthInterceptor {
+	protected := make(map[string]bool, len(protectedMethods))
+	for _, method := range protectedMethods {
+		protected[method] = true
+	}
+
+	return &AuthInterceptor{
+		token:            token,
+		protectedMethods: protected,
+	}
+}
+
+// Unary returns a unary server interceptor enforcing authentication
+func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a stream server interceptor enforcing authentication
+func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorize checks the token in the incoming metadata against the configured token
+func (a *AuthInterceptor) authorize(ctx context.Context, fullMethod string) error {
+	if a.token == "" {
+		if a.protectedMethods[fullMethod] {
+			return status.Error(codes.PermissionDenied, "server.auth_token must be configured to use this method")
+		}
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing auth token")
+	}
+
+	values := md.Get(authorizationMetadataKey)
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing auth token")
+	}
+
+	if !a.matches(values[0]) {
+		return status.Error(codes.Unauthenticated, "invalid auth token")
+	}
+
+	return nil
+}
+
+// matches returns true when the authorization value presents the configured token as is, as a
+// "Bearer <token>" or as the password of "Basic" credentials, so clients may use any scheme
+func (a *AuthInterceptor) matches(authorization string) bool {
+	// The raw value is checked first, tokens configured with their scheme keep working
+	if subtle.ConstantTimeCompare([]byte(authorization), []byte(a.token)) == 1 {
+		return true
+	}
+
+	scheme, credentials, ok := strings.Cut(authorization, " ")
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(credentials)), []byte(a.token)) == 1
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(credentials))
+		if err != nil {
+			return false
+		}
+		_, password, ok := strings.Cut(string(decoded), ":")
+		return ok && subtle.ConstantTimeCompare([]byte(password), []byte(a.token)) == 1
+	default:
+		return false
+	}
+}
@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor_Unary(t *testing.T) {
+	t.Parallel()
+
+	const getStatsMethod = "/ccmon.v1.QueryService/GetStats"
+
+	tests := []struct {
+		name         string
+		serverToken  string
+		clientToken  string
+		method       string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "auth disabled allows regular methods",
+			method:       getStatsMethod,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "auth disabled rejects protected methods",
+			method:       deleteByPeriodMethod,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "missing token is rejected",
+			serverToken:  "secret",
+			method:       getStatsMethod,
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "invalid token is rejected",
+			serverToken:  "secret",
+			clientToken:  "wrong",
+			method:       getStatsMethod,
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "valid token allows regular methods",
+			serverToken:  "secret",
+			clientToken:  "secret",
+			method:       getStatsMethod,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "valid token allows protected methods",
+			serverToken:  "secret",
+			clientToken:  "secret",
+			method:       deleteByPeriodMethod,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "auth disabled rejects bulk append",
+			method:       bulkAppendMethod,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "auth disabled rejects pausing ingestion",
+			method:       setIngestionPausedMethod,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "auth disabled rejects setting notes",
+			method:       setNoteMethod,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "valid token allows setting notes",
+			serverToken:  "secret",
+			clientToken:  "secret",
+			method:       setNoteMethod,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "auth disabled rejects storage info",
+			method:       getStorageInfoMethod,
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.clientToken != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationMetadataKey, tt.clientToken))
+			}
+
+			handlerCalled := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCalled = true
+				return "ok", nil
+			}
+
+			interceptor := NewAuthInterceptor(tt.serverToken, deleteByPeriodMethod, bulkAppendMethod, setIngestionPausedMethod, setNoteMethod, getStorageInfoMethod)
+			_, err := interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Errorf("expected code %v, got %v (err: %v)", tt.expectedCode, code, err)
+			}
+			if handlerCalled != (tt.expectedCode == codes.OK) {
+				t.Errorf("handler called = %v, want %v", handlerCalled, tt.expectedCode == codes.OK)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_ClientSchemes(t *testing.T) {
+	t.Parallel()
+
+	const getStatsMethod = "/ccmon.v1.QueryService/GetStats"
+
+	tests := []struct {
+		name         string
+		serverToken  string
+		clientToken  string
+		scheme       entity.AuthScheme
+		expectedCode codes.Code
+	}{
+		{name: "raw", serverToken: "secret", clientToken: "secret", scheme: entity.AuthSchemeRaw, expectedCode: codes.OK},
+		{name: "bearer", serverToken: "secret", clientToken: "secret", scheme: entity.AuthSchemeBearer, expectedCode: codes.OK},
+		{name: "basic", serverToken: "secret", clientToken: "secret", scheme: entity.AuthSchemeBasic, expectedCode: codes.OK},
+		{name: "bearer with wrong token", serverToken: "secret", clientToken: "wrong", scheme: entity.AuthSchemeBearer, expectedCode: codes.Unauthenticated},
+		{name: "basic with wrong token", serverToken: "secret", clientToken: "wrong", scheme: entity.AuthSchemeBasic, expectedCode: codes.Unauthenticated},
+		{name: "server token with its scheme accepts raw", serverToken: "Bearer secret", clientToken: "Bearer secret", scheme: entity.AuthSchemeRaw, expectedCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Capture the metadata the client interceptor sends and hand it to the server interceptor
+			var sent metadata.MD
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				sent, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			}
+			client := repository.NewClientInterceptor(tt.clientToken, tt.scheme)
+			if err := client.Unary()(context.Background(), getStatsMethod, nil, nil, nil, invoker); err != nil {
+				t.Fatalf("unexpected client error: %v", err)
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			}
+			server := NewAuthInterceptor(tt.serverToken)
+			_, err := server.Unary()(metadata.NewIncomingContext(context.Background(), sent), nil, &grpc.UnaryServerInfo{FullMethod: getStatsMethod}, handler)
+
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Errorf("expected code %v, got %v (sent %v, err: %v)", tt.expectedCode, code, sent, err)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_MalformedSchemes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{name: "basic without base64", authorization: "Basic not-base64!"},
+		{name: "basic without password", authorization: "Basic c2VjcmV0"},
+		{name: "unknown scheme", authorization: "Token secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationMetadataKey, tt.authorization))
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			}
+			_, err := NewAuthInterceptor("secret").Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/ccmon.v1.QueryService/GetStats"}, handler)
+			if code := status.Code(err); code != codes.Unauthenticated {
+				t.Errorf("expected code %v, got %v", codes.Unauthenticated, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package query
+
+import (
+	"bufio"
+	"bytes"
+
+	pb "github.com/elct9620/ccmon/proto"
+)
+
+// backupChunkSize keeps each streamed message well below the default 4 MiB gRPC limit
+const backupChunkSize = 256 * 1024
+
+// backupChunkWriter batches snapshot bytes into fixed-size stream messages
+type backupChunkWriter struct {
+	*bufio.Writer
+}
+
+func newBackupChunkWriter(stream pb.QueryService_BackupServer) *backupChunkWriter {
+	return &backupChunkWriter{
+		Writer: bufio.NewWriterSize(chunkSender{stream: stream}, backupChunkSize),
+	}
+}
+
+// chunkSender sends every write as chunks of at most backupChunkSize bytes
+type chunkSender struct {
+	stream pb.QueryService_BackupServer
+}
+
+func (c chunkSender) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		end := min(written+backupChunkSize, len(p))
+		// Copy the bytes, as the message must not change after Send and the caller reuses p
+		if err := c.stream.Send(&pb.BackupChunk{Data: bytes.Clone(p[written:end])}); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
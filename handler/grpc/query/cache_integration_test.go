@@ -33,7 +33,7 @@ func TestQueryService_GetStats_CacheIntegration(t *testing.T) {
 		cache := service.NewInMemoryStatsCache(1 * time.Minute)
 		mockStatsRepo := testutil.NewMockStatsRepository(mockRepo)
 		calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, cache)
-		queryService := NewService(nil, calculateStatsQuery)
+		queryService := NewService(nil, calculateStatsQuery, nil, nil, nil)
 
 		// Create request for specific time period
 		req := &pb.GetStatsRequest{
@@ -91,7 +91,7 @@ func TestQueryService_GetStats_CacheIntegration(t *testing.T) {
 
 		cache := service.NewInMemoryStatsCache(1 * time.Minute)
 		calculateStatsQuery := usecase.NewCalculateStatsQuery(instrumentedStatsRepo, cache)
-		queryService := NewService(nil, calculateStatsQuery)
+		queryService := NewService(nil, calculateStatsQuery, nil, nil, nil)
 
 		req := &pb.GetStatsRequest{
 			StartTime: timestamppb.New(baseTime),
@@ -147,7 +147,7 @@ func TestQueryService_GetStats_CacheIntegration(t *testing.T) {
 		cache := service.NewInMemoryStatsCache(50 * time.Millisecond)
 		mockStatsRepo := testutil.NewMockStatsRepository(mockRepo)
 		calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, cache)
-		queryService := NewService(nil, calculateStatsQuery)
+		queryService := NewService(nil, calculateStatsQuery, nil, nil, nil)
 
 		req := &pb.GetStatsRequest{
 			StartTime: timestamppb.New(baseTime),
@@ -207,7 +207,7 @@ func TestQueryService_GetStats_CacheIntegration(t *testing.T) {
 		// Use NoOpStatsCache to simulate disabled cache
 		noOpCache := &service.NoOpStatsCache{}
 		calculateStatsQuery := usecase.NewCalculateStatsQuery(instrumentedStatsRepo, noOpCache)
-		queryService := NewService(nil, calculateStatsQuery)
+		queryService := NewService(nil, calculateStatsQuery, nil, nil, nil)
 
 		req := &pb.GetStatsRequest{
 			StartTime: timestamppb.New(baseTime),
@@ -262,7 +262,7 @@ func TestQueryService_GetStats_CacheIntegration(t *testing.T) {
 		cache := service.NewInMemoryStatsCache(1 * time.Minute)
 		mockStatsRepo := testutil.NewMockStatsRepository(mockRepo)
 		calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, cache)
-		queryService := NewService(nil, calculateStatsQuery)
+		queryService := NewService(nil, calculateStatsQuery, nil, nil, nil)
 
 		ctx := context.Background()
 
@@ -2,28 +2,133 @@ package query
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
 	pb "github.com/elct9620/ccmon/proto"
 	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Service implements the QueryService gRPC interface
 type Service struct {
 	pb.UnimplementedQueryServiceServer
-	getFilteredQuery    *usecase.GetFilteredApiRequestsQuery
-	calculateStatsQuery *usecase.CalculateStatsQuery
+	getFilteredQuery      *usecase.GetFilteredApiRequestsQuery
+	calculateStatsQuery   *usecase.CalculateStatsQuery
+	deleteByPeriodCommand *usecase.DeleteRequestsByPeriodCommand
+	healthCheckCommand    *usecase.HealthCheckCommand
+	setNoteCommand        *usecase.SetRequestNoteCommand
+	createBackupQuery     *usecase.CreateBackupQuery
+	getDashboardQuery     *usecase.GetDashboardQuery
+	getModelsQuery        *usecase.GetModelsQuery
+	getStorageInfoQuery   *usecase.GetStorageInfoQuery
+	appendCommand         *usecase.AppendApiRequestCommand
+	evictCommand          *usecase.EvictExcessRecordsCommand
+	maxQueryPeriod        time.Duration
+	maxBulkAppend         int
+	streamInterval        time.Duration
+	statsNotifier         *statsNotifier
 }
 
+// DefaultStreamInterval is how often StreamStats pushes stats when no new data arrives
+const DefaultStreamInterval = 10 * time.Second
+
+// DefaultMaxBulkAppend is the most records BulkAppend accepts in one call
+const DefaultMaxBulkAppend = 1000
+
 // NewService creates a new query service instance
-func NewService(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery) *Service {
+func NewService(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, deleteByPeriodCommand *usecase.DeleteRequestsByPeriodCommand, healthCheckCommand *usecase.HealthCheckCommand, setNoteCommand *usecase.SetRequestNoteCommand) *Service {
 	return &Service{
-		getFilteredQuery:    getFilteredQuery,
-		calculateStatsQuery: calculateStatsQuery,
+		getFilteredQuery:      getFilteredQuery,
+		calculateStatsQuery:   calculateStatsQuery,
+		deleteByPeriodCommand: deleteByPeriodCommand,
+		healthCheckCommand:    healthCheckCommand,
+		setNoteCommand:        setNoteCommand,
+		streamInterval:        DefaultStreamInterval,
+		maxBulkAppend:         DefaultMaxBulkAppend,
+		statsNotifier:         newStatsNotifier(),
+	}
+}
+
+// SetBackupQuery enables streaming database snapshots through Backup
+func (s *Service) SetBackupQuery(query *usecase.CreateBackupQuery) {
+	s.createBackupQuery = query
+}
+
+// SetDashboardQuery enables returning stats, block and plan together through GetDashboard
+func (s *Service) SetDashboardQuery(query *usecase.GetDashboardQuery) {
+	s.getDashboardQuery = query
+}
+
+// SetModelsQuery enables listing the stored models through GetModels
+func (s *Service) SetModelsQuery(query *usecase.GetModelsQuery) {
+	s.getModelsQuery = query
+}
+
+// SetStorageInfoQuery enables reporting the database size and contents through GetStorageInfo
+func (s *Service) SetStorageInfoQuery(query *usecase.GetStorageInfoQuery) {
+	s.getStorageInfoQuery = query
+}
+
+// SetAppendCommand enables storing batches of records through BulkAppend
+func (s *Service) SetAppendCommand(command *usecase.AppendApiRequestCommand) {
+	s.appendCommand = command
+}
+
+// SetEvictCommand enables trimming the stored requests to a maximum after each BulkAppend
+func (s *Service) SetEvictCommand(command *usecase.EvictExcessRecordsCommand) {
+	s.evictCommand = command
+}
+
+// SetMaxBulkAppend limits the records BulkAppend accepts in one call
+func (s *Service) SetMaxBulkAppend(maxRecords int) {
+	s.maxBulkAppend = maxRecords
+}
+
+// SetMaxQueryPeriod limits the explicit range GetAPIRequests accepts; zero disables the limit.
+// Aggregated queries are exempt since they return a summary rather than every record.
+func (s *Service) SetMaxQueryPeriod(maxPeriod time.Duration) {
+	s.maxQueryPeriod = maxPeriod
+}
+
+// SetStreamInterval sets how often StreamStats pushes stats when no new data arrives
+func (s *Service) SetStreamInterval(interval time.Duration) {
+	s.streamInterval = interval
+}
+
+// NotifyNewData pushes fresh stats to every open StreamStats call, e.g. once an export is stored.
+// While streams are open, the cached stats of periods overlapping the stored requests are dropped
+// first; otherwise GetStats keeps its cache TTL.
+func (s *Service) NotifyNewData(stored entity.Period) {
+	if s.statsNotifier.len() == 0 {
+		return
+	}
+	if s.calculateStatsQuery != nil {
+		s.calculateStatsQuery.InvalidateCachePeriod(stored)
 	}
+	s.statsNotifier.notify()
+}
+
+// queryError maps a failed query to a status code, so clients can tell a store that cannot be read
+// from a missing plan or an unexpected failure
+func queryError(action string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, usecase.ErrRepositoryUnavailable), errors.Is(err, usecase.ErrIngestionPaused):
+		code = codes.Unavailable
+	case errors.Is(err, usecase.ErrPlanUnavailable):
+		code = codes.FailedPrecondition
+	}
+	return status.Errorf(code, "%s: %v", action, err)
 }
 
 // GetStats returns aggregated statistics based on time range
@@ -31,45 +136,70 @@ func (s *Service) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.Ge
 	// Convert proto timestamps to entity.Period
 	period := convertTimestampsToPeriod(req.StartTime, req.EndTime)
 
-	// Get stats via usecase
-	params := usecase.CalculateStatsParams{Period: period}
-	stats, err := s.calculateStatsQuery.Execute(ctx, params)
+	classifier, err := convertProtoToClassifier(req.ModelTiers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get stats: %w", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// Convert to protobuf response
-	pbStats := &pb.Stats{
-		BaseRequests:    int32(stats.BaseRequests()),
-		PremiumRequests: int32(stats.PremiumRequests()),
-		TotalRequests:   int32(stats.TotalRequests()),
-		BaseTokens:      convertTokenToProto(stats.BaseTokens()),
-		PremiumTokens:   convertTokenToProto(stats.PremiumTokens()),
-		TotalTokens:     convertTokenToProto(stats.TotalTokens()),
-		BaseCost:        convertCostToProto(stats.BaseCost()),
-		PremiumCost:     convertCostToProto(stats.PremiumCost()),
-		TotalCost:       convertCostToProto(stats.TotalCost()),
+	// Get stats via usecase
+	params := usecase.CalculateStatsParams{
+		Period:         period,
+		NegligibleCost: entity.NewCost(req.NegligibleCost),
+		Classifier:     classifier,
+	}
+	stats, err := s.calculateStatsQuery.Execute(ctx, params)
+	if err != nil {
+		return nil, queryError("failed to get stats", err)
 	}
 
+	// Convert to protobuf response; clients check the flag to tell older servers ignoring the overrides
 	return &pb.GetStatsResponse{
-		Stats: pbStats,
+		Stats:             convertStatsToProto(stats),
+		ModelTiersApplied: true,
 	}, nil
 }
 
+// convertProtoToClassifier converts the requested tier overrides, rejecting unknown tiers
+func convertProtoToClassifier(overrides []*pb.ModelTierOverride) (entity.ModelClassifier, error) {
+	tiers := make(map[string]entity.ModelTier, len(overrides))
+	for _, override := range overrides {
+		tier, err := entity.ParseModelTier(override.GetTier())
+		if err != nil {
+			return entity.ModelClassifier{}, fmt.Errorf("model %q: %w", override.GetModel(), err)
+		}
+		tiers[override.GetModel()] = tier
+	}
+	return entity.NewModelClassifier(tiers), nil
+}
+
 // GetAPIRequests returns API request records based on filters
 func (s *Service) GetAPIRequests(ctx context.Context, req *pb.GetAPIRequestsRequest) (*pb.GetAPIRequestsResponse, error) {
 	// Convert proto timestamps to entity.Period
 	period := convertTimestampsToPeriod(req.StartTime, req.EndTime)
 
+	// Open-ended (all time) queries are never shortened, so exports and session counts stay complete;
+	// they are left to retention
+	if s.maxQueryPeriod > 0 && req.StartTime != nil {
+		if length := period.EndAt().Sub(period.StartAt()); length > s.maxQueryPeriod {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("requested period of %s exceeds the server maximum of %s", length, s.maxQueryPeriod))
+		}
+	}
+
+	durationRange, err := entity.NewDurationRange(time.Duration(req.MinDurationMs)*time.Millisecond, time.Duration(req.MaxDurationMs)*time.Millisecond)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// Get requests via usecase with limit and offset
 	params := usecase.GetFilteredApiRequestsParams{
-		Period: period,
-		Limit:  int(req.Limit),
-		Offset: int(req.Offset),
+		Period:   period,
+		Limit:    int(req.Limit),
+		Offset:   int(req.Offset),
+		Duration: durationRange,
 	}
 	requests, err := s.getFilteredQuery.Execute(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get requests: %w", err)
+		return nil, queryError("failed to get requests", err)
 	}
 
 	// Note: TotalCount is now the count of returned records since pagination
@@ -82,12 +212,383 @@ func (s *Service) GetAPIRequests(ctx context.Context, req *pb.GetAPIRequestsRequ
 		pbRequests[i] = convertAPIRequestToProto(apiReq)
 	}
 
+	// Clients check the flag to tell older servers ignoring the duration range
 	return &pb.GetAPIRequestsResponse{
-		Requests:   pbRequests,
-		TotalCount: int32(totalCount),
+		Requests:        pbRequests,
+		TotalCount:      int32(totalCount),
+		DurationApplied: true,
+	}, nil
+}
+
+// DeleteByPeriod deletes API request records within the given time range.
+// Without a confirmation token it only previews the deletion and returns the token to confirm it.
+func (s *Service) DeleteByPeriod(ctx context.Context, req *pb.DeleteByPeriodRequest) (*pb.DeleteByPeriodResponse, error) {
+	if s.deleteByPeriodCommand == nil {
+		return nil, status.Error(codes.Unimplemented, "delete operation is not available on this server")
+	}
+
+	// Both boundaries are required to prevent accidental mass deletion
+	if req.StartTime == nil || req.EndTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time are required")
+	}
+
+	params := usecase.DeleteRequestsByPeriodParams{
+		Period:            entity.NewPeriod(req.StartTime.AsTime(), req.EndTime.AsTime()),
+		ConfirmationToken: req.ConfirmationToken,
+	}
+	result, err := s.deleteByPeriodCommand.Execute(ctx, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnboundedPeriod):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, usecase.ErrInvalidConfirmationToken):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, fmt.Errorf("failed to delete requests: %w", err)
+		}
+	}
+
+	return &pb.DeleteByPeriodResponse{
+		MatchedCount:      int32(result.MatchedCount),
+		DeletedCount:      int32(result.DeletedCount),
+		ConfirmationToken: result.ConfirmationToken,
+	}, nil
+}
+
+// HealthCheck round-trips a synthetic record through the OTLP receiver and store
+func (s *Service) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	if s.healthCheckCommand == nil {
+		return nil, status.Error(codes.Unimplemented, "health check requires ingestion, which is disabled on this server")
+	}
+
+	result, err := s.healthCheckCommand.Execute(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "health check failed: %v", err)
+	}
+
+	return &pb.HealthCheckResponse{
+		IngestMicros:  result.IngestDuration.Microseconds(),
+		VerifyMicros:  result.VerifyDuration.Microseconds(),
+		CleanupMicros: result.CleanupDuration.Microseconds(),
+	}, nil
+}
+
+// SetNote attaches a manual note to the request identified by session ID and timestamp
+func (s *Service) SetNote(ctx context.Context, req *pb.SetNoteRequest) (*pb.SetNoteResponse, error) {
+	if s.setNoteCommand == nil {
+		return nil, status.Error(codes.Unimplemented, "notes cannot be edited on a read-only server")
+	}
+
+	if req.SessionId == "" || req.Timestamp == nil {
+		return nil, status.Error(codes.InvalidArgument, "session_id and timestamp are required")
+	}
+
+	err := s.setNoteCommand.Execute(ctx, usecase.SetRequestNoteParams{
+		SessionID: req.SessionId,
+		Timestamp: req.Timestamp.AsTime(),
+		Note:      req.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRequestNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, usecase.ErrRequestNoteTooLong):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to set note: %v", err)
+		}
+	}
+
+	return &pb.SetNoteResponse{}, nil
+}
+
+// BulkAppend stores a batch of records in a single transaction. Records already stored are skipped,
+// so a client can resend the batch after a failed call; invalid records are reported by position.
+func (s *Service) BulkAppend(ctx context.Context, req *pb.BulkAppendRequest) (*pb.BulkAppendResponse, error) {
+	if s.appendCommand == nil {
+		return nil, status.Error(codes.Unimplemented, "bulk append requires ingestion, which is disabled on this server")
+	}
+
+	if len(req.Requests) > s.maxBulkAppend {
+		return nil, status.Errorf(codes.InvalidArgument, "batch of %d records exceeds the server maximum of %d", len(req.Requests), s.maxBulkAppend)
+	}
+
+	params := make([]usecase.AppendApiRequestParams, len(req.Requests))
+	for i, record := range req.Requests {
+		var timestamp time.Time // Left zero when missing so the record is rejected
+		if record.Timestamp != nil {
+			timestamp = record.Timestamp.AsTime()
+		}
+		params[i] = usecase.AppendApiRequestParams{
+			SessionID:  record.SessionId,
+			Timestamp:  timestamp,
+			Model:      record.Model,
+			Tokens:     entity.NewToken(record.InputTokens, record.OutputTokens, record.CacheReadTokens, record.CacheCreationTokens),
+			Cost:       entity.NewCost(record.CostUsd),
+			DurationMS: record.DurationMs,
+			Labels:     record.Labels,
+		}
+	}
+
+	result, err := s.appendCommand.ExecuteBatch(ctx, params)
+	if err != nil {
+		return nil, queryError("failed to append requests", err)
+	}
+
+	if result.Saved > 0 {
+		s.evictExcess(ctx, result.Saved)
+		s.NotifyNewData(appendedPeriod(params))
+	}
+
+	failures := make([]*pb.BulkAppendFailure, len(result.Failures))
+	for i, failure := range result.Failures {
+		failures[i] = &pb.BulkAppendFailure{
+			Index:  int32(failure.Index),
+			Reason: failure.Reason,
+		}
+	}
+
+	return &pb.BulkAppendResponse{
+		SavedCount:   int32(result.Saved),
+		SkippedCount: int32(result.Skipped),
+		Failures:     failures,
+	}, nil
+}
+
+// SetIngestionPaused pauses or resumes storing requests received over OTLP and BulkAppend.
+// Pausing returns once the requests being stored are written, so the database can then be backed up
+// or compacted; exporters get a retryable Unavailable status until ingestion resumes.
+func (s *Service) SetIngestionPaused(ctx context.Context, req *pb.SetIngestionPausedRequest) (*pb.SetIngestionPausedResponse, error) {
+	if s.appendCommand == nil {
+		return nil, status.Error(codes.Unimplemented, "ingestion is disabled on this server")
+	}
+
+	wasPaused := s.appendCommand.Paused()
+	s.appendCommand.SetPaused(req.Paused)
+	if wasPaused != req.Paused {
+		if req.Paused {
+			log.Println("Ingestion paused, exporters are asked to retry until it resumes")
+		} else {
+			log.Println("Ingestion resumed")
+		}
+	}
+
+	return &pb.SetIngestionPausedResponse{
+		Paused:    req.Paused,
+		WasPaused: wasPaused,
 	}, nil
 }
 
+// evictExcess trims the stored requests to the maximum once per batch
+func (s *Service) evictExcess(ctx context.Context, saved int) {
+	if s.evictCommand == nil {
+		return
+	}
+
+	result, err := s.evictCommand.Execute(ctx, usecase.EvictExcessRecordsParams{Saved: saved})
+	if err != nil {
+		log.Printf("Failed to evict requests over server.max_records: %v", err)
+		return
+	}
+	if result.DeletedCount > 0 {
+		log.Printf("Evicted %d oldest requests over server.max_records", result.DeletedCount)
+	}
+}
+
+// Backup streams a consistent database snapshot, reporting the record count in the final chunk
+func (s *Service) Backup(req *pb.BackupRequest, stream pb.QueryService_BackupServer) error {
+	if s.createBackupQuery == nil {
+		return status.Error(codes.Unimplemented, "backup is not available on this server")
+	}
+
+	writer := newBackupChunkWriter(stream)
+	result, err := s.createBackupQuery.Execute(stream.Context(), usecase.CreateBackupParams{Writer: writer})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "backup failed: %v", err)
+	}
+
+	return stream.Send(&pb.BackupChunk{RecordCount: int64(result.Records)})
+}
+
+// GetDashboard returns the period stats, block stats and plan in a single response
+func (s *Service) GetDashboard(ctx context.Context, req *pb.GetDashboardRequest) (*pb.GetDashboardResponse, error) {
+	if s.getDashboardQuery == nil {
+		return nil, status.Error(codes.Unimplemented, "dashboard is not available on this server")
+	}
+
+	params := usecase.GetDashboardParams{
+		Period:         convertTimestampsToPeriod(req.StartTime, req.EndTime),
+		NegligibleCost: entity.NewCost(req.NegligibleCost),
+	}
+	if req.BlockStart != nil {
+		block := entity.NewBlock(req.BlockStart.AsTime())
+		params.Block = &block
+	}
+
+	dashboard, err := s.getDashboardQuery.Execute(ctx, params)
+	if err != nil {
+		return nil, queryError("failed to get dashboard", err)
+	}
+
+	resp := &pb.GetDashboardResponse{
+		Stats: convertStatsToProto(dashboard.Stats),
+		Plan: &pb.Plan{
+			Name:  dashboard.Plan.Name(),
+			Price: convertCostToProto(dashboard.Plan.Price()),
+		},
+	}
+	if dashboard.Block != nil {
+		resp.Block = &pb.Block{
+			StartTime: timestamppb.New(dashboard.Block.StartAt()),
+			EndTime:   timestamppb.New(dashboard.Block.EndAt()),
+			Stats:     convertStatsToProto(dashboard.BlockStats),
+		}
+	}
+
+	return resp, nil
+}
+
+// GetModels returns the distinct models in the store, most used first
+func (s *Service) GetModels(ctx context.Context, req *pb.GetModelsRequest) (*pb.GetModelsResponse, error) {
+	if s.getModelsQuery == nil {
+		return nil, status.Error(codes.Unimplemented, "model listing is not available on this server")
+	}
+
+	models, err := s.getModelsQuery.Execute(ctx)
+	if err != nil {
+		return nil, queryError("failed to get models", err)
+	}
+
+	resp := &pb.GetModelsResponse{
+		Models: make([]*pb.ModelSummary, len(models)),
+	}
+	for i, model := range models {
+		resp.Models[i] = &pb.ModelSummary{
+			Name:         model.Model().String(),
+			FirstSeen:    timestamppb.New(model.FirstSeenAt()),
+			LastSeen:     timestamppb.New(model.LastSeenAt()),
+			RequestCount: int32(model.Requests()),
+		}
+	}
+
+	return resp, nil
+}
+
+// GetStorageInfo reports the database file size, the record count and the stored time range
+func (s *Service) GetStorageInfo(ctx context.Context, req *pb.GetStorageInfoRequest) (*pb.GetStorageInfoResponse, error) {
+	if s.getStorageInfoQuery == nil {
+		return nil, status.Error(codes.Unimplemented, "storage info is not available on this server")
+	}
+
+	info, err := s.getStorageInfoQuery.Execute(ctx)
+	if err != nil {
+		return nil, queryError("failed to get storage info", err)
+	}
+
+	resp := &pb.GetStorageInfoResponse{
+		SizeBytes:   info.SizeBytes(),
+		RecordCount: int64(info.Records()),
+	}
+	if !info.IsEmpty() {
+		resp.OldestTime = timestamppb.New(info.OldestAt())
+		resp.NewestTime = timestamppb.New(info.NewestAt())
+	}
+
+	return resp, nil
+}
+
+// StreamStats sends the stats of the requested period right away, then again on every
+// interval and whenever new data is stored, until the client disconnects
+func (s *Service) StreamStats(req *pb.StreamStatsRequest, stream pb.QueryService_StreamStatsServer) error {
+	if req.WindowSeconds < 0 {
+		return status.Error(codes.InvalidArgument, "window_seconds must not be negative")
+	}
+	if req.StartTime != nil && req.EndTime != nil && req.EndTime.AsTime().Before(req.StartTime.AsTime()) {
+		return status.Error(codes.InvalidArgument, "end_time must not be before start_time")
+	}
+
+	newData, unsubscribe := s.statsNotifier.subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(s.streamInterval)
+	defer ticker.Stop()
+
+	ctx := stream.Context()
+	for {
+		if err := s.sendStatsUpdate(ctx, req, stream); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-newData:
+		}
+	}
+}
+
+// sendStatsUpdate computes the stats of the requested period as of now and sends them
+func (s *Service) sendStatsUpdate(ctx context.Context, req *pb.StreamStatsRequest, stream pb.QueryService_StreamStatsServer) error {
+	// The stats cache keys periods by the second, so streams watching the same period share results
+	period := streamPeriod(req, time.Now().UTC())
+
+	stats, err := s.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{
+		Period:         period,
+		NegligibleCost: entity.NewCost(req.NegligibleCost),
+	})
+	if err != nil {
+		return queryError("failed to get stats", err)
+	}
+
+	update := &pb.StatsUpdate{
+		Stats:   convertStatsToProto(stats),
+		EndTime: timestamppb.New(period.EndAt()),
+	}
+	if !period.IsAllTime() {
+		update.StartTime = timestamppb.New(period.StartAt())
+	}
+
+	return stream.Send(update)
+}
+
+// appendedPeriod returns the period spanning the timestamps of a batch, skipping records without one
+func appendedPeriod(params []usecase.AppendApiRequestParams) entity.Period {
+	var first, last time.Time
+	for _, param := range params {
+		if param.Timestamp.IsZero() {
+			continue
+		}
+		if first.IsZero() || param.Timestamp.Before(first) {
+			first = param.Timestamp
+		}
+		if param.Timestamp.After(last) {
+			last = param.Timestamp
+		}
+	}
+	return entity.NewPeriod(first, last)
+}
+
+// streamPeriod resolves the period of a StreamStats request at the given time
+func streamPeriod(req *pb.StreamStatsRequest, now time.Time) entity.Period {
+	end := now
+	if req.EndTime != nil {
+		end = req.EndTime.AsTime()
+	}
+
+	switch {
+	case req.StartTime != nil:
+		return entity.NewPeriod(req.StartTime.AsTime(), end)
+	case req.WindowSeconds > 0:
+		return entity.NewPeriodFromDuration(end, time.Duration(req.WindowSeconds)*time.Second)
+	default:
+		return entity.NewAllTimePeriod(end)
+	}
+}
+
 // convertTimestampsToPeriod converts protobuf timestamps to entity.Period
 func convertTimestampsToPeriod(startTime, endTime *timestamppb.Timestamp) entity.Period {
 	// Handle nil timestamps - use all time period
@@ -114,6 +615,32 @@ func convertTimestampsToPeriod(startTime, endTime *timestamppb.Timestamp) entity
 	return entity.NewPeriod(start, end)
 }
 
+// convertStatsToProto converts entity.Stats to protobuf Stats
+func convertStatsToProto(stats entity.Stats) *pb.Stats {
+	longestGap, longestGapAvailable := stats.LongestGap()
+	return &pb.Stats{
+		BaseRequests:    int32(stats.BaseRequests()),
+		PremiumRequests: int32(stats.PremiumRequests()),
+		TotalRequests:   int32(stats.TotalRequests()),
+		BaseTokens:      convertTokenToProto(stats.BaseTokens()),
+		PremiumTokens:   convertTokenToProto(stats.PremiumTokens()),
+		TotalTokens:     convertTokenToProto(stats.TotalTokens()),
+		BaseCost:        convertCostToProto(stats.BaseCost()),
+		PremiumCost:     convertCostToProto(stats.PremiumCost()),
+		TotalCost:       convertCostToProto(stats.TotalCost()),
+
+		NegligibleRequests: int32(stats.NegligibleRequests()),
+		NegligibleTokens:   convertTokenToProto(stats.NegligibleTokens()),
+		NegligibleCost:     convertCostToProto(stats.NegligibleCost()),
+
+		Sessions:       int32(stats.Sessions(false)),
+		UnknownSession: stats.HasUnknownSession(),
+
+		LongestGapMs:        longestGap.Milliseconds(),
+		LongestGapAvailable: longestGapAvailable,
+	}
+}
+
 // convertTokenToProto converts entity.Token to protobuf Token
 func convertTokenToProto(token entity.Token) *pb.Token {
 	return &pb.Token{
@@ -147,5 +674,7 @@ func convertAPIRequestToProto(req entity.APIRequest) *pb.APIRequest {
 		TotalTokens:         req.Tokens().Total(),
 		CostUsd:             req.Cost().Amount(),
 		DurationMs:          req.DurationMS(),
+		Labels:              req.Labels(),
+		Note:                req.Note(),
 	}
 }
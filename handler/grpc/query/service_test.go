@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
 	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -241,7 +245,7 @@ func TestQueryService_GetStats(t *testing.T) {
 			calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
 
 			// Create service
-			service := NewService(nil, calculateStatsQuery) // getFilteredQuery not needed for this test
+			service := NewService(nil, calculateStatsQuery, nil, nil, nil) // getFilteredQuery not needed for this test
 
 			// Create request
 			req := &pb.GetStatsRequest{}
@@ -278,6 +282,43 @@ func TestQueryService_GetStats(t *testing.T) {
 	}
 }
 
+func TestQueryService_GetStats_NegligibleCost(t *testing.T) {
+	now := time.Now().UTC()
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		mustCreateAPIRequest("cheap", now.Add(-2*time.Hour), "claude-3-haiku-20240307", entity.NewToken(10, 5, 0, 0), entity.NewCost(0.001), 100),
+		mustCreateAPIRequest("costly", now.Add(-time.Hour), "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.5), 1000),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), service.NewInMemoryStatsCache(time.Minute))
+	s := NewService(nil, calculateStatsQuery, nil, nil, nil)
+
+	start := timestamppb.New(now.Add(-24 * time.Hour))
+	end := timestamppb.New(now)
+
+	// The same period is cached per threshold, so a client without one never sees another's bucket
+	for _, tt := range []struct {
+		threshold    float64
+		wantRequests int32
+		wantCost     float64
+	}{
+		{threshold: 0.01, wantRequests: 1, wantCost: 0.001},
+		{threshold: 0, wantRequests: 0, wantCost: 0},
+		{threshold: 1, wantRequests: 2, wantCost: 0.501},
+	} {
+		resp, err := s.GetStats(context.Background(), &pb.GetStatsRequest{StartTime: start, EndTime: end, NegligibleCost: tt.threshold})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Stats.NegligibleRequests != tt.wantRequests || resp.Stats.NegligibleCost.Amount != tt.wantCost {
+			t.Errorf("threshold %v: expected %d negligible requests costing %v, got %d costing %v",
+				tt.threshold, tt.wantRequests, tt.wantCost, resp.Stats.NegligibleRequests, resp.Stats.NegligibleCost.Amount)
+		}
+		if resp.Stats.TotalRequests != 2 {
+			t.Errorf("threshold %v: expected the totals to keep both requests, got %d", tt.threshold, resp.Stats.TotalRequests)
+		}
+	}
+}
+
 func TestQueryService_GetAPIRequests(t *testing.T) {
 	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
 
@@ -472,7 +513,7 @@ func TestQueryService_GetAPIRequests(t *testing.T) {
 			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(mockRepo)
 
 			// Create service
-			service := NewService(getFilteredQuery, nil) // calculateStatsQuery not needed for this test
+			service := NewService(getFilteredQuery, nil, nil, nil, nil) // calculateStatsQuery not needed for this test
 
 			// Call service
 			ctx := context.Background()
@@ -509,6 +550,257 @@ func TestQueryService_GetAPIRequests(t *testing.T) {
 	}
 }
 
+func TestQueryService_GetAPIRequests_MaxQueryPeriod(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		maxQueryPeriod time.Duration
+		requestParams  *pb.GetAPIRequestsRequest
+		expectedCode   codes.Code
+	}{
+		{
+			name:           "period_within_limit",
+			maxQueryPeriod: 30 * 24 * time.Hour,
+			requestParams: &pb.GetAPIRequestsRequest{
+				StartTime: timestamppb.New(baseTime.Add(-7 * 24 * time.Hour)),
+				EndTime:   timestamppb.New(baseTime),
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:           "period_exceeds_limit",
+			maxQueryPeriod: 30 * 24 * time.Hour,
+			requestParams: &pb.GetAPIRequestsRequest{
+				StartTime: timestamppb.New(baseTime.Add(-31 * 24 * time.Hour)),
+				EndTime:   timestamppb.New(baseTime),
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:           "open_ended_start_exceeds_limit",
+			maxQueryPeriod: 30 * 24 * time.Hour,
+			requestParams: &pb.GetAPIRequestsRequest{
+				StartTime: timestamppb.New(baseTime.Add(-365 * 24 * time.Hour)),
+				EndTime:   nil,
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:           "all_time_is_exempt",
+			maxQueryPeriod: 30 * 24 * time.Hour,
+			requestParams: &pb.GetAPIRequestsRequest{
+				StartTime: nil,
+				EndTime:   nil,
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:           "limit_disabled",
+			maxQueryPeriod: 0,
+			requestParams: &pb.GetAPIRequestsRequest{
+				StartTime: timestamppb.New(baseTime.Add(-10 * 365 * 24 * time.Hour)),
+				EndTime:   timestamppb.New(baseTime),
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), nil, nil, nil, nil)
+			service.SetMaxQueryPeriod(tt.maxQueryPeriod)
+
+			_, err := service.GetAPIRequests(context.Background(), tt.requestParams)
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Expected code %v, got %v (err: %v)", tt.expectedCode, code, err)
+			}
+			if tt.expectedCode == codes.InvalidArgument {
+				expected := fmt.Sprintf("exceeds the server maximum of %s", tt.maxQueryPeriod)
+				if msg := status.Convert(err).Message(); !strings.Contains(msg, expected) {
+					t.Errorf("Expected message to contain %q, got %q", expected, msg)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryService_GetAPIRequests_MaxQueryPeriodOpenEnded(t *testing.T) {
+	now := time.Now().UTC()
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	for _, req := range []entity.APIRequest{
+		entity.NewAPIRequest("recent", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+		entity.NewAPIRequest("old", now.Add(-60*24*time.Hour), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+	} {
+		if err := mockRepo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+	service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), nil, nil, nil, nil)
+	service.SetMaxQueryPeriod(30 * 24 * time.Hour)
+
+	// Records older than the limit are still listed rather than silently cut off
+	resp, err := service.GetAPIRequests(context.Background(), &pb.GetAPIRequestsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resp.Requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(resp.Requests))
+	}
+}
+
+func TestQueryService_GetAPIRequests_Duration(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	requestTaking := func(sessionID string, durationMS int64) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, baseTime, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), durationMS)
+	}
+
+	tests := []struct {
+		name          string
+		minDurationMs int64
+		maxDurationMs int64
+		expectedCode  codes.Code
+		expected      []string
+	}{
+		{
+			name:     "no_bounds",
+			expected: []string{"fast", "at-min", "at-max"},
+		},
+		{
+			name:          "min_is_inclusive",
+			minDurationMs: 30_000,
+			expected:      []string{"at-min", "at-max"},
+		},
+		{
+			name:          "max_is_exclusive",
+			maxDurationMs: 60_000,
+			expected:      []string{"fast", "at-min"},
+		},
+		{
+			name:          "negative_bound",
+			minDurationMs: -1,
+			expectedCode:  codes.InvalidArgument,
+		},
+		{
+			name:          "min_not_below_max",
+			minDurationMs: 60_000,
+			maxDurationMs: 30_000,
+			expectedCode:  codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData([]entity.APIRequest{
+				requestTaking("fast", 2_000),
+				requestTaking("at-min", 30_000),
+				requestTaking("at-max", 60_000),
+			})
+			service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), nil, nil, nil, nil)
+
+			resp, err := service.GetAPIRequests(context.Background(), &pb.GetAPIRequestsRequest{
+				MinDurationMs: tt.minDurationMs,
+				MaxDurationMs: tt.maxDurationMs,
+			})
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Expected code %v, got %v (err: %v)", tt.expectedCode, code, err)
+			}
+			if err != nil {
+				return
+			}
+
+			got := make([]string, len(resp.Requests))
+			for i, req := range resp.Requests {
+				got[i] = req.SessionId
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected sessions %v, got %v", tt.expected, got)
+			}
+			if !resp.DurationApplied {
+				t.Error("Expected the response to report the duration range as applied")
+			}
+		})
+	}
+}
+
+func TestQueryService_QueryErrorCodes(t *testing.T) {
+	planRepo := func(err error) *testutil.MockPlanRepository {
+		repo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20)))
+		repo.SetError(err)
+		return repo
+	}
+
+	tests := []struct {
+		name         string
+		repoErr      error
+		planErr      error
+		call         func(s *Service) error
+		expectedCode codes.Code
+	}{
+		{
+			name:    "stats_repository_failure",
+			repoErr: fmt.Errorf("database locked"),
+			call: func(s *Service) error {
+				_, err := s.GetStats(context.Background(), &pb.GetStatsRequest{})
+				return err
+			},
+			expectedCode: codes.Unavailable,
+		},
+		{
+			name:    "requests_repository_failure",
+			repoErr: fmt.Errorf("database locked"),
+			call: func(s *Service) error {
+				_, err := s.GetAPIRequests(context.Background(), &pb.GetAPIRequestsRequest{})
+				return err
+			},
+			expectedCode: codes.Unavailable,
+		},
+		{
+			name:    "cancelled_request",
+			repoErr: context.Canceled,
+			call: func(s *Service) error {
+				_, err := s.GetStats(context.Background(), &pb.GetStatsRequest{})
+				return err
+			},
+			expectedCode: codes.Canceled,
+		},
+		{
+			name:    "dashboard_falls_back_on_unavailable_plan",
+			planErr: usecase.ErrPlanUnavailable,
+			call: func(s *Service) error {
+				_, err := s.GetDashboard(context.Background(), &pb.GetDashboardRequest{})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:    "dashboard_plan_repository_failure",
+			planErr: fmt.Errorf("config unreadable"),
+			call: func(s *Service) error {
+				_, err := s.GetDashboard(context.Background(), &pb.GetDashboardRequest{})
+				return err
+			},
+			expectedCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, statsRepo := testutil.NewMockRepositoryPair()
+			mockRepo.SetError(tt.repoErr)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), calculateStatsQuery, nil, nil, nil)
+			service.SetDashboardQuery(usecase.NewGetDashboardQuery(calculateStatsQuery, planRepo(tt.planErr)))
+
+			if code := status.Code(tt.call(service)); code != tt.expectedCode {
+				t.Errorf("Expected code %v, got %v", tt.expectedCode, code)
+			}
+		})
+	}
+}
+
 func TestQueryService_ConvertTimestampsToPeriod(t *testing.T) {
 	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
 
@@ -577,3 +869,348 @@ func TestQueryService_ConvertTimestampsToPeriod(t *testing.T) {
 		})
 	}
 }
+
+// fakeStatsStream collects the updates sent by StreamStats
+type fakeStatsStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	updates chan *pb.StatsUpdate
+}
+
+func (f *fakeStatsStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeStatsStream) Send(update *pb.StatsUpdate) error {
+	f.updates <- update
+	return nil
+}
+
+func receiveStatsUpdate(t *testing.T, updates <-chan *pb.StatsUpdate) *pb.StatsUpdate {
+	t.Helper()
+
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a stats update")
+		return nil
+	}
+}
+
+func TestQueryService_StreamStats(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+
+	t.Run("pushes_on_interval", func(t *testing.T) {
+		mockRepo := testutil.NewMockAPIRequestRepository()
+		mockRepo.SetMockData([]entity.APIRequest{
+			mustCreateAPIRequest("session1", baseTime, "claude-3-haiku-20240307", entity.NewToken(100, 50, 10, 5), entity.NewCost(0.15), 1000),
+		})
+		queryService := NewService(nil, usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), &service.NoOpStatsCache{}), nil, nil, nil)
+		queryService.SetStreamInterval(10 * time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream := &fakeStatsStream{ctx: ctx, updates: make(chan *pb.StatsUpdate, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- queryService.StreamStats(&pb.StreamStatsRequest{}, stream)
+		}()
+
+		for i := 0; i < 3; i++ {
+			update := receiveStatsUpdate(t, stream.updates)
+			if update.Stats.TotalRequests != 1 {
+				t.Errorf("Update %d: expected 1 request, got %d", i, update.Stats.TotalRequests)
+			}
+			if update.StartTime != nil {
+				t.Errorf("Update %d: expected no start time for all time, got %v", i, update.StartTime.AsTime())
+			}
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected a clean return on disconnect, got %v", err)
+		}
+	})
+
+	t.Run("new_data_bypasses_cached_stats", func(t *testing.T) {
+		mockRepo := testutil.NewMockAPIRequestRepository()
+		mockRepo.SetMockData([]entity.APIRequest{
+			mustCreateAPIRequest("session1", baseTime, "claude-3-haiku-20240307", entity.NewToken(100, 50, 10, 5), entity.NewCost(0.15), 1000),
+		})
+		cache := service.NewInMemoryStatsCache(time.Hour)
+		queryService := NewService(nil, usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), cache), nil, nil, nil)
+		queryService.SetStreamInterval(time.Hour)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream := &fakeStatsStream{ctx: ctx, updates: make(chan *pb.StatsUpdate, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- queryService.StreamStats(&pb.StreamStatsRequest{
+				StartTime: timestamppb.New(baseTime.Add(-time.Hour)),
+				EndTime:   timestamppb.New(baseTime.Add(time.Hour)),
+			}, stream)
+		}()
+
+		if update := receiveStatsUpdate(t, stream.updates); update.Stats.TotalRequests != 1 {
+			t.Fatalf("Expected 1 request initially, got %d", update.Stats.TotalRequests)
+		}
+
+		if err := mockRepo.Save(mustCreateAPIRequest("session2", baseTime.Add(time.Minute), "claude-3-sonnet-20240229", entity.NewToken(200, 100, 20, 10), entity.NewCost(0.70), 1500)); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+		queryService.NotifyNewData(entity.NewPeriod(baseTime.Add(time.Minute), baseTime.Add(time.Minute)))
+
+		update := receiveStatsUpdate(t, stream.updates)
+		if update.Stats.TotalRequests != 2 {
+			t.Errorf("Expected 2 requests after new data, got %d", update.Stats.TotalRequests)
+		}
+		if !update.StartTime.AsTime().Equal(baseTime.Add(-time.Hour)) || !update.EndTime.AsTime().Equal(baseTime.Add(time.Hour)) {
+			t.Errorf("Expected the fixed period, got %v - %v", update.StartTime.AsTime(), update.EndTime.AsTime())
+		}
+
+		cancel()
+		<-done
+		if subscribers := queryService.statsNotifier.len(); subscribers != 0 {
+			t.Errorf("Expected the subscription to be removed on disconnect, got %d", subscribers)
+		}
+	})
+}
+
+func TestStreamPeriod(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-2 * time.Hour)
+	end := now.Add(3 * time.Hour)
+
+	tests := []struct {
+		name          string
+		req           *pb.StreamStatsRequest
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:        "all time",
+			req:         &pb.StreamStatsRequest{},
+			expectedEnd: now,
+		},
+		{
+			name:          "rolling window",
+			req:           &pb.StreamStatsRequest{WindowSeconds: 3600},
+			expectedStart: now.Add(-time.Hour),
+			expectedEnd:   now,
+		},
+		{
+			name:          "fixed start up to now",
+			req:           &pb.StreamStatsRequest{StartTime: timestamppb.New(start), WindowSeconds: 3600},
+			expectedStart: start,
+			expectedEnd:   now,
+		},
+		{
+			name:          "fixed period",
+			req:           &pb.StreamStatsRequest{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)},
+			expectedStart: start,
+			expectedEnd:   end,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			period := streamPeriod(tt.req, now)
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("StartAt() = %v, want %v", period.StartAt(), tt.expectedStart)
+			}
+			if !period.EndAt().Equal(tt.expectedEnd) {
+				t.Errorf("EndAt() = %v, want %v", period.EndAt(), tt.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestQueryService_BulkAppend(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	record := func(sessionID string, timestamp time.Time) *pb.APIRequest {
+		return &pb.APIRequest{
+			SessionId:    sessionID,
+			Timestamp:    timestamppb.New(timestamp),
+			Model:        "claude-3-5-sonnet-20241022",
+			InputTokens:  100,
+			OutputTokens: 50,
+			CostUsd:      0.01,
+			Labels:       map[string]string{"user": "alice"},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		withCommand   bool
+		requests      []*pb.APIRequest
+		expectedCode  codes.Code
+		expectedSaved int32
+		expectedSkip  int32
+		expectedFails []int32
+	}{
+		{
+			name:         "disabled_without_append_command",
+			requests:     []*pb.APIRequest{record("session-a", baseTime)},
+			expectedCode: codes.Unimplemented,
+		},
+		{
+			name:         "batch_exceeds_limit",
+			withCommand:  true,
+			requests:     []*pb.APIRequest{record("session-a", baseTime), record("session-a", baseTime.Add(time.Minute)), record("session-a", baseTime.Add(2*time.Minute))},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:          "saves_new_and_skips_stored",
+			withCommand:   true,
+			requests:      []*pb.APIRequest{record("stored", baseTime), record("session-a", baseTime.Add(time.Minute))},
+			expectedCode:  codes.OK,
+			expectedSaved: 1,
+			expectedSkip:  1,
+		},
+		{
+			name:          "reports_records_without_timestamp",
+			withCommand:   true,
+			requests:      []*pb.APIRequest{record("session-a", baseTime), {SessionId: "session-b", Model: "claude-3-5-sonnet-20241022"}},
+			expectedCode:  codes.OK,
+			expectedSaved: 1,
+			expectedFails: []int32{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData([]entity.APIRequest{
+				mustCreateAPIRequest("stored", baseTime, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 0),
+			})
+			service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), nil, nil, nil, nil)
+			service.SetMaxBulkAppend(2)
+			if tt.withCommand {
+				service.SetAppendCommand(usecase.NewAppendApiRequestCommand(mockRepo))
+			}
+
+			resp, err := service.BulkAppend(context.Background(), &pb.BulkAppendRequest{Requests: tt.requests})
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Expected code %v, got %v (err: %v)", tt.expectedCode, code, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if resp.SavedCount != tt.expectedSaved || resp.SkippedCount != tt.expectedSkip {
+				t.Errorf("Expected %d saved and %d skipped, got %d and %d", tt.expectedSaved, tt.expectedSkip, resp.SavedCount, resp.SkippedCount)
+			}
+			if len(resp.Failures) != len(tt.expectedFails) {
+				t.Fatalf("Expected %d failures, got %v", len(tt.expectedFails), resp.Failures)
+			}
+			for i, failure := range resp.Failures {
+				if failure.Index != tt.expectedFails[i] {
+					t.Errorf("Expected failure at index %d, got %d", tt.expectedFails[i], failure.Index)
+				}
+			}
+
+			stored, err := mockRepo.FindAll()
+			if err != nil {
+				t.Fatalf("FindAll failed: %v", err)
+			}
+			for _, req := range stored {
+				if req.SessionID() == "session-a" && req.Labels()["user"] != "alice" {
+					t.Errorf("Expected labels to be stored, got %v", req.Labels())
+				}
+			}
+		})
+	}
+}
+
+func TestQueryService_SetIngestionPaused(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), nil, nil, nil, nil)
+
+	if _, err := service.SetIngestionPaused(context.Background(), &pb.SetIngestionPausedRequest{Paused: true}); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("Expected Unimplemented without an append command, got %v", err)
+	}
+
+	appendCommand := usecase.NewAppendApiRequestCommand(mockRepo)
+	service.SetAppendCommand(appendCommand)
+
+	resp, err := service.SetIngestionPaused(context.Background(), &pb.SetIngestionPausedRequest{Paused: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.Paused || resp.WasPaused || !appendCommand.Paused() {
+		t.Errorf("Expected ingestion to be paused from running, got %+v", resp)
+	}
+
+	// Batches are refused with a retryable status while paused
+	_, err = service.BulkAppend(context.Background(), &pb.BulkAppendRequest{Requests: []*pb.APIRequest{
+		{SessionId: "session-a", Timestamp: timestamppb.New(time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)), Model: "claude-3-5-sonnet-20241022"},
+	}})
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf("Expected Unavailable for BulkAppend while paused, got %v (err: %v)", code, err)
+	}
+
+	resp, err = service.SetIngestionPaused(context.Background(), &pb.SetIngestionPausedRequest{Paused: false})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Paused || !resp.WasPaused || appendCommand.Paused() {
+		t.Errorf("Expected ingestion to be resumed from paused, got %+v", resp)
+	}
+}
+
+// fixedStorageInfoRepository returns the same storage description on every call
+type fixedStorageInfoRepository struct {
+	info entity.StorageInfo
+}
+
+func (r fixedStorageInfoRepository) GetStorageInfo() (entity.StorageInfo, error) {
+	return r.info, nil
+}
+
+func TestQueryService_GetStorageInfo(t *testing.T) {
+	service := NewService(usecase.NewGetFilteredApiRequestsQuery(testutil.NewMockAPIRequestRepository()), nil, nil, nil, nil)
+
+	if _, err := service.GetStorageInfo(context.Background(), &pb.GetStorageInfoRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("Expected Unimplemented without a storage info query, got %v", err)
+	}
+
+	oldest := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	newest := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		info        entity.StorageInfo
+		wantRange   bool
+		wantSize    int64
+		wantRecords int64
+	}{
+		{name: "stored requests", info: entity.NewStorageInfo(65536, 42, oldest, newest), wantRange: true, wantSize: 65536, wantRecords: 42},
+		{name: "empty store leaves the range unset", info: entity.NewStorageInfo(32768, 0, time.Time{}, time.Time{}), wantSize: 32768},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.SetStorageInfoQuery(usecase.NewGetStorageInfoQuery(fixedStorageInfoRepository{info: tt.info}))
+
+			resp, err := service.GetStorageInfo(context.Background(), &pb.GetStorageInfoRequest{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.SizeBytes != tt.wantSize || resp.RecordCount != tt.wantRecords {
+				t.Errorf("Expected %d bytes and %d records, got %d and %d", tt.wantSize, tt.wantRecords, resp.SizeBytes, resp.RecordCount)
+			}
+			if (resp.OldestTime != nil) != tt.wantRange || (resp.NewestTime != nil) != tt.wantRange {
+				t.Fatalf("Expected the range set = %v, got %v - %v", tt.wantRange, resp.OldestTime, resp.NewestTime)
+			}
+			if tt.wantRange && (!resp.OldestTime.AsTime().Equal(oldest) || !resp.NewestTime.AsTime().Equal(newest)) {
+				t.Errorf("Expected range %v - %v, got %v - %v", oldest, newest, resp.OldestTime.AsTime(), resp.NewestTime.AsTime())
+			}
+		})
+	}
+}
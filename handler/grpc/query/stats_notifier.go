@@ -0,0 +1,53 @@
+package query
+
+import "sync"
+
+// statsNotifier wakes every open StreamStats call when new data is stored
+type statsNotifier struct {
+	mutex       sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func newStatsNotifier() *statsNotifier {
+	return &statsNotifier{
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// subscribe returns a channel signalled on new data and a function to stop receiving
+func (n *statsNotifier) subscribe() (<-chan struct{}, func()) {
+	// One pending signal is enough, a burst of stored requests needs a single recomputation
+	ch := make(chan struct{}, 1)
+
+	n.mutex.Lock()
+	n.subscribers[ch] = struct{}{}
+	n.mutex.Unlock()
+
+	return ch, func() {
+		n.mutex.Lock()
+		delete(n.subscribers, ch)
+		n.mutex.Unlock()
+	}
+}
+
+// notify signals every subscriber without blocking the caller
+func (n *statsNotifier) notify() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for ch := range n.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A signal is already pending
+		}
+	}
+}
+
+// len returns the number of open subscriptions
+func (n *statsNotifier) len() int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return len(n.subscribers)
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// queryServicePrefix matches every method of the query service
+const queryServicePrefix = "/ccmon.v1.QueryService/"
+
+// rateLimitSweepSize is the number of tracked clients that triggers pruning idle buckets
+const rateLimitSweepSize = 1024
+
+// RateLimitInterceptor limits query calls per client with a token bucket.
+// Clients are identified by peer host, so every monitor on the same machine shares a bucket.
+// OTLP ingestion is never limited, as dropping telemetry would lose usage data.
+type RateLimitInterceptor struct {
+	rate  float64 // tokens added per second
+	burst float64 // bucket capacity
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokenBucket tracks the remaining tokens of a single client
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimitInterceptor creates a rate limiter allowing requestsPerSecond calls with bursts up to burst.
+// A non-positive rate disables limiting; a burst below 1 is raised to 1.
+func NewRateLimitInterceptor(requestsPerSecond float64, burst int) *RateLimitInterceptor {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimitInterceptor{
+		rate:    requestsPerSecond,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// IsEnabled returns true if the interceptor limits any calls
+func (r *RateLimitInterceptor) IsEnabled() bool {
+	return r.rate > 0
+}
+
+// Unary returns a unary server interceptor enforcing the rate limit
+func (r *RateLimitInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := r.limit(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a stream server interceptor enforcing the rate limit
+func (r *RateLimitInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := r.limit(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// limit consumes a token for the calling client, rejecting the call when the bucket is empty
+func (r *RateLimitInterceptor) limit(ctx context.Context, fullMethod string) error {
+	if !r.IsEnabled() || !strings.HasPrefix(fullMethod, queryServicePrefix) {
+		return nil
+	}
+
+	if !r.allow(clientKey(ctx)) {
+		return status.Error(codes.ResourceExhausted, "query rate limit exceeded, retry later")
+	}
+
+	return nil
+}
+
+// allow refills the client's bucket for the elapsed time and takes one token if available
+func (r *RateLimitInterceptor) allow(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	bucket, ok := r.buckets[key]
+	if !ok {
+		if len(r.buckets) >= rateLimitSweepSize {
+			r.sweep(now)
+		}
+		bucket = &tokenBucket{tokens: r.burst, last: now}
+		r.buckets[key] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * r.rate
+	if bucket.tokens > r.burst {
+		bucket.tokens = r.burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// sweep drops buckets that have refilled completely, as they behave the same as a new bucket
+func (r *RateLimitInterceptor) sweep(now time.Time) {
+	for key, bucket := range r.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*r.rate >= r.burst {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// clientKey identifies the calling client by peer host, ignoring the ephemeral port
+func clientKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
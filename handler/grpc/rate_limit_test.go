@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimitInterceptor_Unary(t *testing.T) {
+	t.Parallel()
+
+	const getStatsMethod = "/ccmon.v1.QueryService/GetStats"
+	const exportLogsMethod = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+
+	type call struct {
+		client       string
+		method       string
+		advance      time.Duration
+		expectedCode codes.Code
+	}
+
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		calls []call
+	}{
+		{
+			name: "disabled allows every call",
+			rate: 0,
+			calls: []call{
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+			},
+		},
+		{
+			name:  "calls beyond the burst are rejected",
+			rate:  1,
+			burst: 2,
+			calls: []call{
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.ResourceExhausted},
+			},
+		},
+		{
+			name:  "bucket refills over time",
+			rate:  2,
+			burst: 1,
+			calls: []call{
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.ResourceExhausted},
+				{client: "10.0.0.1:5000", method: getStatsMethod, advance: 500 * time.Millisecond, expectedCode: codes.OK},
+			},
+		},
+		{
+			name:  "clients have separate buckets",
+			rate:  1,
+			burst: 1,
+			calls: []call{
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.2:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.ResourceExhausted},
+			},
+		},
+		{
+			name:  "connections from the same host share a bucket",
+			rate:  1,
+			burst: 1,
+			calls: []call{
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5001", method: getStatsMethod, expectedCode: codes.ResourceExhausted},
+			},
+		},
+		{
+			name:  "telemetry ingestion is not limited",
+			rate:  1,
+			burst: 1,
+			calls: []call{
+				{client: "10.0.0.1:5000", method: exportLogsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: exportLogsMethod, expectedCode: codes.OK},
+				{client: "10.0.0.1:5000", method: getStatsMethod, expectedCode: codes.OK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+			interceptor := NewRateLimitInterceptor(tt.rate, tt.burst)
+			interceptor.now = func() time.Time { return now }
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			}
+
+			for i, c := range tt.calls {
+				now = now.Add(c.advance)
+
+				addr, err := net.ResolveTCPAddr("tcp", c.client)
+				if err != nil {
+					t.Fatalf("invalid client address %q: %v", c.client, err)
+				}
+				ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+				_, err = interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: c.method}, handler)
+				if code := status.Code(err); code != c.expectedCode {
+					t.Errorf("call %d: expected code %v, got %v (err: %v)", i, c.expectedCode, code, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package receiver
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	metricsv1 "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsdata "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// metricsSeriesRetention is how long the last value of a cumulative series is kept without new points
+const metricsSeriesRetention = 24 * time.Hour
+
+// MetricsMapping names the metrics and attributes that carry usage in OTLP metric exports
+type MetricsMapping struct {
+	TokenMetric      string // Sum of tokens, split by the type attribute
+	CostMetric       string // Sum of cost in USD
+	TypeAttribute    string // Token type: input, output, cacheRead or cacheCreation
+	ModelAttribute   string
+	SessionAttribute string
+}
+
+// DefaultMetricsMapping returns the metric and attribute names Claude Code exports
+func DefaultMetricsMapping() MetricsMapping {
+	return MetricsMapping{
+		TokenMetric:      "claude_code.token.usage",
+		CostMetric:       "claude_code.cost.usage",
+		TypeAttribute:    "type",
+		ModelAttribute:   "model",
+		SessionAttribute: "session.id",
+	}
+}
+
+// metricsReceiver handles metrics exports, ignored unless a mapping is set
+type metricsReceiver struct {
+	metricsv1.UnimplementedMetricsServiceServer
+	receiver  *Receiver
+	startedAt time.Time
+
+	mutex   sync.Mutex
+	mapping *MetricsMapping
+	series  map[string]metricsSeries // Last value of each cumulative series
+}
+
+// metricsSeries is the last value seen of a cumulative series
+type metricsSeries struct {
+	value  float64
+	seenAt time.Time
+}
+
+// metricsUsage accumulates the usage of one session and model at one point in time
+type metricsUsage struct {
+	sessionID     string
+	model         string
+	timestamp     time.Time
+	input         int64
+	output        int64
+	cacheRead     int64
+	cacheCreation int64
+	cost          float64
+	labels        map[string]string
+}
+
+func newMetricsReceiver(receiver *Receiver) *metricsReceiver {
+	return &metricsReceiver{
+		receiver:  receiver,
+		startedAt: receiver.now(),
+		series:    make(map[string]metricsSeries),
+	}
+}
+
+func (r *metricsReceiver) setMapping(mapping MetricsMapping) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.mapping = &mapping
+}
+
+func (r *metricsReceiver) Export(ctx context.Context, req *metricsv1.ExportMetricsServiceRequest) (*metricsv1.ExportMetricsServiceResponse, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Without a mapping the usage arrives through the logs path only
+	if r.mapping == nil {
+		return &metricsv1.ExportMetricsServiceResponse{}, nil
+	}
+	// Rejected before counting, so cumulative points are counted once resent
+	if err := r.receiver.pausedError(); err != nil {
+		return nil, err
+	}
+
+	now := r.receiver.now()
+	usages := make(map[string]*metricsUsage)
+	for _, rm := range req.ResourceMetrics {
+		resourceAttrs := resourceAttributes(rm.Resource)
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				if metric.Name != r.mapping.TokenMetric && metric.Name != r.mapping.CostMetric {
+					continue
+				}
+				// Only sums carry usage; gauges, histograms and summaries are ignored
+				sum := metric.GetSum()
+				if sum == nil {
+					continue
+				}
+
+				cumulative := sum.AggregationTemporality == metricsdata.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+				for _, point := range sum.DataPoints {
+					value := dataPointValue(point)
+					if cumulative {
+						var counted bool
+						if value, counted = r.delta(metric.Name, point, value, now); !counted {
+							continue
+						}
+					}
+					if value <= 0 {
+						continue
+					}
+
+					if metric.Name == r.mapping.CostMetric {
+						r.usageFor(usages, point, resourceAttrs, now).cost += value
+						continue
+					}
+					tokens := int64(math.Round(value))
+					switch tokenType, _ := pointAttribute(point, r.mapping.TypeAttribute); tokenType {
+					case "input":
+						r.usageFor(usages, point, resourceAttrs, now).input += tokens
+					case "output":
+						r.usageFor(usages, point, resourceAttrs, now).output += tokens
+					case "cacheRead", "cache_read":
+						r.usageFor(usages, point, resourceAttrs, now).cacheRead += tokens
+					case "cacheCreation", "cache_creation":
+						r.usageFor(usages, point, resourceAttrs, now).cacheCreation += tokens
+					}
+				}
+			}
+		}
+	}
+	r.pruneSeries(now)
+
+	var rejected int64
+	var stored storedBatch
+	for _, apiReq := range r.requestsFrom(usages) {
+		timestamp, ok := r.receiver.checkTimestamp(apiReq.SessionID(), apiReq.Timestamp())
+		if !ok {
+			rejected++
+			continue
+		}
+		if r.receiver.store(entity.NewAPIRequest(apiReq.SessionID(), timestamp, string(apiReq.Model()), apiReq.Tokens(), apiReq.Cost(), 0).WithLabels(apiReq.Labels())) {
+			stored.add(timestamp)
+		}
+	}
+	r.receiver.finishExport(ctx, stored)
+
+	if rejected > 0 {
+		return &metricsv1.ExportMetricsServiceResponse{
+			PartialSuccess: &metricsv1.ExportMetricsPartialSuccess{
+				RejectedDataPoints: rejected,
+				ErrorMessage:       "timestamp too far in the future",
+			},
+		}, nil
+	}
+
+	return &metricsv1.ExportMetricsServiceResponse{}, nil
+}
+
+// usageFor returns the usage the data point adds to, keyed by session, model and time.
+// Resource and point attributes not mapped to a request field are kept as labels.
+func (r *metricsReceiver) usageFor(usages map[string]*metricsUsage, point *metricsdata.NumberDataPoint, resourceAttrs []*commonv1.KeyValue, now time.Time) *metricsUsage {
+	sessionID, _ := pointAttribute(point, r.mapping.SessionAttribute)
+	model, _ := pointAttribute(point, r.mapping.ModelAttribute)
+	timestamp := now.UTC()
+	if point.TimeUnixNano != 0 {
+		timestamp = time.Unix(0, int64(point.TimeUnixNano)).UTC()
+	}
+
+	key := sessionID + "\x00" + model + "\x00" + strconv.FormatInt(timestamp.UnixNano(), 10)
+	usage, ok := usages[key]
+	if !ok {
+		usage = &metricsUsage{
+			sessionID: sessionID,
+			model:     model,
+			timestamp: timestamp,
+			labels:    make(map[string]string),
+		}
+		usages[key] = usage
+	}
+
+	for _, attrs := range [][]*commonv1.KeyValue{resourceAttrs, point.Attributes} {
+		for _, attr := range attrs {
+			switch attr.Key {
+			case r.mapping.TypeAttribute, r.mapping.ModelAttribute, r.mapping.SessionAttribute:
+				continue
+			}
+			if value, ok := attributeString(attr.Value); ok {
+				usage.labels[attr.Key] = value
+			}
+		}
+	}
+	return usage
+}
+
+// requestsFrom converts the accumulated usage to API requests in a stable order. Requests are
+// stored by timestamp and session, so models of one session reported at the same time are
+// spaced a nanosecond apart to be stored separately.
+func (r *metricsReceiver) requestsFrom(usages map[string]*metricsUsage) []entity.APIRequest {
+	sorted := make([]*metricsUsage, 0, len(usages))
+	for _, usage := range usages {
+		sorted = append(sorted, usage)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.sessionID != b.sessionID {
+			return a.sessionID < b.sessionID
+		}
+		if !a.timestamp.Equal(b.timestamp) {
+			return a.timestamp.Before(b.timestamp)
+		}
+		return a.model < b.model
+	})
+
+	requests := make([]entity.APIRequest, 0, len(sorted))
+	offset := time.Duration(0)
+	for i, usage := range sorted {
+		if i > 0 && sorted[i-1].sessionID == usage.sessionID && sorted[i-1].timestamp.Equal(usage.timestamp) {
+			offset++
+		} else {
+			offset = 0
+		}
+
+		tokens := entity.NewToken(usage.input, usage.output, usage.cacheRead, usage.cacheCreation)
+		requests = append(requests, entity.NewAPIRequest(usage.sessionID, usage.timestamp.Add(offset), usage.model, tokens, entity.NewCost(usage.cost), 0).WithLabels(usage.labels))
+	}
+	return requests
+}
+
+// delta converts a cumulative data point to the increase since the previous point of its series.
+// The first point of a series counts in full when the series started after the receiver did;
+// otherwise it only sets the baseline, so a restarted server does not count earlier usage again.
+func (r *metricsReceiver) delta(name string, point *metricsdata.NumberDataPoint, value float64, now time.Time) (float64, bool) {
+	key := seriesKey(name, point)
+	previous, seen := r.series[key]
+	r.series[key] = metricsSeries{value: value, seenAt: now}
+
+	if !seen {
+		started := time.Unix(0, int64(point.StartTimeUnixNano))
+		return value, point.StartTimeUnixNano != 0 && !started.Before(r.startedAt)
+	}
+	// A lower value means the exporter restarted the series
+	if value < previous.value {
+		return value, true
+	}
+	return value - previous.value, true
+}
+
+// pruneSeries forgets cumulative series without points for a day, e.g. of ended sessions
+func (r *metricsReceiver) pruneSeries(now time.Time) {
+	for key, series := range r.series {
+		if now.Sub(series.seenAt) > metricsSeriesRetention {
+			delete(r.series, key)
+		}
+	}
+}
+
+// seriesKey identifies a series by metric name, start time and attributes
+func seriesKey(name string, point *metricsdata.NumberDataPoint) string {
+	attrs := make([]string, 0, len(point.Attributes))
+	for _, attr := range point.Attributes {
+		value, _ := attributeString(attr.Value)
+		attrs = append(attrs, attr.Key+"="+value)
+	}
+	sort.Strings(attrs)
+	return name + "\x00" + strconv.FormatUint(point.StartTimeUnixNano, 10) + "\x00" + strings.Join(attrs, "\x00")
+}
+
+// dataPointValue returns the value of an integer or double data point
+func dataPointValue(point *metricsdata.NumberDataPoint) float64 {
+	switch v := point.Value.(type) {
+	case *metricsdata.NumberDataPoint_AsInt:
+		return float64(v.AsInt)
+	case *metricsdata.NumberDataPoint_AsDouble:
+		return v.AsDouble
+	default:
+		return 0
+	}
+}
+
+// pointAttribute returns a data point attribute as a string
+func pointAttribute(point *metricsdata.NumberDataPoint, key string) (string, bool) {
+	for _, attr := range point.Attributes {
+		if attr.Key == key {
+			return attributeString(attr.Value)
+		}
+	}
+	return "", false
+}
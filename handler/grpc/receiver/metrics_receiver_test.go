@@ -0,0 +1,312 @@
+package receiver
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+	metricsv1 "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsdata "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// stringAttribute builds a string OTLP attribute
+func stringAttribute(key, value string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: key, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}}}
+}
+
+// tokenPoint builds a Claude Code token usage data point
+func tokenPoint(session, model, tokenType string, value int64, start, at time.Time) *metricsdata.NumberDataPoint {
+	return &metricsdata.NumberDataPoint{
+		Attributes: []*commonv1.KeyValue{
+			stringAttribute("session.id", session),
+			stringAttribute("model", model),
+			stringAttribute("type", tokenType),
+			stringAttribute("user.id", "alice"),
+		},
+		StartTimeUnixNano: uint64(start.UnixNano()),
+		TimeUnixNano:      uint64(at.UnixNano()),
+		Value:             &metricsdata.NumberDataPoint_AsInt{AsInt: value},
+	}
+}
+
+// costPoint builds a Claude Code cost usage data point
+func costPoint(session, model string, value float64, start, at time.Time) *metricsdata.NumberDataPoint {
+	return &metricsdata.NumberDataPoint{
+		Attributes: []*commonv1.KeyValue{
+			stringAttribute("session.id", session),
+			stringAttribute("model", model),
+		},
+		StartTimeUnixNano: uint64(start.UnixNano()),
+		TimeUnixNano:      uint64(at.UnixNano()),
+		Value:             &metricsdata.NumberDataPoint_AsDouble{AsDouble: value},
+	}
+}
+
+// sumMetric wraps data points in a sum metric
+func sumMetric(name string, temporality metricsdata.AggregationTemporality, points ...*metricsdata.NumberDataPoint) *metricsdata.Metric {
+	return &metricsdata.Metric{
+		Name: name,
+		Data: &metricsdata.Metric_Sum{Sum: &metricsdata.Sum{
+			AggregationTemporality: temporality,
+			IsMonotonic:            true,
+			DataPoints:             points,
+		}},
+	}
+}
+
+// metricsRequest wraps metrics in an export request with a project resource attribute
+func metricsRequest(metrics ...*metricsdata.Metric) *metricsv1.ExportMetricsServiceRequest {
+	return &metricsv1.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricsdata.ResourceMetrics{
+			{
+				Resource:     &resourcev1.Resource{Attributes: []*commonv1.KeyValue{stringAttribute("project", "web")}},
+				ScopeMetrics: []*metricsdata.ScopeMetrics{{Metrics: metrics}},
+			},
+		},
+	}
+}
+
+// storedRequests returns the saved requests ordered by timestamp
+func storedRequests(t *testing.T, repo *testutil.MockAPIRequestRepository) []entity.APIRequest {
+	t.Helper()
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Timestamp().Before(requests[j].Timestamp()) })
+	return requests
+}
+
+func TestMetricsReceiver_DeltaDataPoints(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	start := now.Add(-time.Minute)
+	delta := metricsdata.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+
+	tests := []struct {
+		name            string
+		mapping         *MetricsMapping
+		request         *metricsv1.ExportMetricsServiceRequest
+		expectedTokens  []entity.Token
+		expectedCosts   []float64
+		expectedModels  []string
+		expectedLabels  map[string]string
+		forbiddenLabels []string
+	}{
+		{
+			name:    "tokens and cost of one model become one request",
+			mapping: &MetricsMapping{TokenMetric: "claude_code.token.usage", CostMetric: "claude_code.cost.usage", TypeAttribute: "type", ModelAttribute: "model", SessionAttribute: "session.id"},
+			request: metricsRequest(
+				sumMetric("claude_code.token.usage", delta,
+					tokenPoint("session-1", "claude-sonnet-4", "input", 100, start, now),
+					tokenPoint("session-1", "claude-sonnet-4", "output", 50, start, now),
+					tokenPoint("session-1", "claude-sonnet-4", "cacheRead", 1000, start, now),
+					tokenPoint("session-1", "claude-sonnet-4", "cacheCreation", 10, start, now),
+				),
+				sumMetric("claude_code.cost.usage", delta, costPoint("session-1", "claude-sonnet-4", 0.25, start, now)),
+				sumMetric("claude_code.session.count", delta, costPoint("session-1", "claude-sonnet-4", 1, start, now)),
+			),
+			expectedTokens:  []entity.Token{entity.NewToken(100, 50, 1000, 10)},
+			expectedCosts:   []float64{0.25},
+			expectedModels:  []string{"claude-sonnet-4"},
+			expectedLabels:  map[string]string{"project": "web", "user.id": "alice"},
+			forbiddenLabels: []string{"type", "model", "session.id"},
+		},
+		{
+			name:    "models of one session reported together are stored separately",
+			mapping: func() *MetricsMapping { m := DefaultMetricsMapping(); return &m }(),
+			request: metricsRequest(
+				sumMetric("claude_code.token.usage", delta,
+					tokenPoint("session-1", "claude-sonnet-4", "input", 100, start, now),
+					tokenPoint("session-1", "claude-3-5-haiku", "input", 20, start, now),
+				),
+			),
+			expectedTokens: []entity.Token{entity.NewToken(20, 0, 0, 0), entity.NewToken(100, 0, 0, 0)},
+			expectedCosts:  []float64{0, 0},
+			expectedModels: []string{"claude-3-5-haiku", "claude-sonnet-4"},
+		},
+		{
+			name:    "gauges and unknown token types are ignored",
+			mapping: func() *MetricsMapping { m := DefaultMetricsMapping(); return &m }(),
+			request: metricsRequest(
+				&metricsdata.Metric{
+					Name: "claude_code.token.usage",
+					Data: &metricsdata.Metric_Gauge{Gauge: &metricsdata.Gauge{DataPoints: []*metricsdata.NumberDataPoint{
+						tokenPoint("session-1", "claude-sonnet-4", "input", 100, start, now),
+					}}},
+				},
+				sumMetric("claude_code.token.usage", delta, tokenPoint("session-1", "claude-sonnet-4", "reasoning", 100, start, now)),
+			),
+		},
+		{
+			name: "custom metric and attribute names",
+			mapping: &MetricsMapping{
+				TokenMetric:      "llm.tokens",
+				CostMetric:       "llm.cost",
+				TypeAttribute:    "token.kind",
+				ModelAttribute:   "llm.model",
+				SessionAttribute: "conversation",
+			},
+			request: metricsRequest(
+				sumMetric("llm.tokens", delta, &metricsdata.NumberDataPoint{
+					Attributes: []*commonv1.KeyValue{
+						stringAttribute("conversation", "c-1"),
+						stringAttribute("llm.model", "claude-opus-4"),
+						stringAttribute("token.kind", "output"),
+					},
+					TimeUnixNano: uint64(now.UnixNano()),
+					Value:        &metricsdata.NumberDataPoint_AsDouble{AsDouble: 42},
+				}),
+				sumMetric("llm.cost", delta, &metricsdata.NumberDataPoint{
+					Attributes: []*commonv1.KeyValue{
+						stringAttribute("conversation", "c-1"),
+						stringAttribute("llm.model", "claude-opus-4"),
+					},
+					TimeUnixNano: uint64(now.UnixNano()),
+					Value:        &metricsdata.NumberDataPoint_AsDouble{AsDouble: 1.5},
+				}),
+			),
+			expectedTokens:  []entity.Token{entity.NewToken(0, 42, 0, 0)},
+			expectedCosts:   []float64{1.5},
+			expectedModels:  []string{"claude-opus-4"},
+			forbiddenLabels: []string{"token.kind", "llm.model", "conversation"},
+		},
+		{
+			name:    "without a mapping metrics are ignored",
+			mapping: nil,
+			request: metricsRequest(
+				sumMetric("claude_code.token.usage", delta, tokenPoint("session-1", "claude-sonnet-4", "input", 100, start, now)),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+			if tt.mapping != nil {
+				receiver.SetMetricsMapping(*tt.mapping)
+			}
+
+			resp, err := receiver.GetMetricsServiceServer().Export(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			if resp.PartialSuccess != nil {
+				t.Errorf("Expected full success, got %v", resp.PartialSuccess)
+			}
+
+			requests := storedRequests(t, mockRepo)
+			if len(requests) != len(tt.expectedTokens) {
+				t.Fatalf("Expected %d requests, got %d", len(tt.expectedTokens), len(requests))
+			}
+
+			for i, req := range requests {
+				if req.Tokens() != tt.expectedTokens[i] {
+					t.Errorf("request %d: expected tokens %+v, got %+v", i, tt.expectedTokens[i], req.Tokens())
+				}
+				if req.Cost().Amount() != tt.expectedCosts[i] {
+					t.Errorf("request %d: expected cost %v, got %v", i, tt.expectedCosts[i], req.Cost().Amount())
+				}
+				if string(req.Model()) != tt.expectedModels[i] {
+					t.Errorf("request %d: expected model %s, got %s", i, tt.expectedModels[i], req.Model())
+				}
+				for key, value := range tt.expectedLabels {
+					if got, _ := req.Label(key); got != value {
+						t.Errorf("request %d: expected label %s=%s, got %q", i, key, value, got)
+					}
+				}
+				for _, key := range tt.forbiddenLabels {
+					if _, ok := req.Label(key); ok {
+						t.Errorf("request %d: expected mapped attribute %s not to be a label", i, key)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMetricsReceiver_CumulativeDataPoints(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	cumulative := metricsdata.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+	receiver.SetMetricsMapping(DefaultMetricsMapping())
+	receiver.metrics.startedAt = now.Add(-time.Hour)
+	metricsService := receiver.GetMetricsServiceServer()
+
+	export := func(points ...*metricsdata.NumberDataPoint) {
+		t.Helper()
+		if _, err := metricsService.Export(context.Background(), metricsRequest(sumMetric("claude_code.token.usage", cumulative, points...))); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+
+	// The old session started before the receiver, its first total is only a baseline
+	oldStart := now.Add(-2 * time.Hour)
+	newStart := now.Add(-10 * time.Minute)
+	export(
+		tokenPoint("old-session", "claude-sonnet-4", "input", 5000, oldStart, now),
+		tokenPoint("new-session", "claude-sonnet-4", "input", 300, newStart, now),
+	)
+	export(
+		tokenPoint("old-session", "claude-sonnet-4", "input", 5200, oldStart, now.Add(time.Minute)),
+		tokenPoint("new-session", "claude-sonnet-4", "input", 300, newStart, now.Add(time.Minute)),
+	)
+	// A lower total means the exporter restarted the series
+	export(tokenPoint("new-session", "claude-sonnet-4", "input", 40, newStart, now.Add(2*time.Minute)))
+
+	requests := storedRequests(t, mockRepo)
+	expected := []struct {
+		session string
+		input   int64
+	}{
+		{"new-session", 300},
+		{"old-session", 200},
+		{"new-session", 40},
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("Expected %d requests, got %d", len(expected), len(requests))
+	}
+	for i, req := range requests {
+		if req.SessionID() != expected[i].session || req.Tokens().Input() != expected[i].input {
+			t.Errorf("request %d: expected %s with %d input tokens, got %s with %d", i, expected[i].session, expected[i].input, req.SessionID(), req.Tokens().Input())
+		}
+	}
+}
+
+func TestMetricsReceiver_FutureTimestampRejected(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	delta := metricsdata.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+	receiver.SetMetricsMapping(DefaultMetricsMapping())
+	receiver.SetFutureTimestampPolicy(FutureTimestampReject, DefaultClockSkewTolerance)
+	receiver.now = func() time.Time { return now }
+
+	future := now.Add(48 * time.Hour)
+	resp, err := receiver.GetMetricsServiceServer().Export(context.Background(), metricsRequest(
+		sumMetric("claude_code.token.usage", delta,
+			tokenPoint("future-session", "claude-sonnet-4", "input", 100, future, future),
+			tokenPoint("current-session", "claude-sonnet-4", "input", 100, now, now),
+		),
+	))
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if resp.PartialSuccess == nil || resp.PartialSuccess.RejectedDataPoints != 1 {
+		t.Fatalf("Expected 1 rejected data point, got %v", resp.PartialSuccess)
+	}
+	requests := storedRequests(t, mockRepo)
+	if len(requests) != 1 || requests[0].SessionID() != "current-session" {
+		t.Errorf("Expected only the current session to be stored, got %d requests", len(requests))
+	}
+}
@@ -2,18 +2,37 @@ package receiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/usecase"
 	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	metricsv1 "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	tracesv1 "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
 	logsdata "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultClockSkewTolerance is how far ahead of the server clock a record may be stamped
+const DefaultClockSkewTolerance = 5 * time.Minute
+
+// FutureTimestampPolicy decides what happens to records stamped beyond the clock skew tolerance
+type FutureTimestampPolicy string
+
+const (
+	// FutureTimestampClamp stores the record with the server's current time
+	FutureTimestampClamp FutureTimestampPolicy = "clamp"
+	// FutureTimestampReject drops the record and reports it as rejected to the exporter
+	FutureTimestampReject FutureTimestampPolicy = "reject"
 )
 
 // Receiver handles OTLP message processing
@@ -21,15 +40,79 @@ type Receiver struct {
 	requestChan   chan entity.APIRequest
 	program       *tea.Program
 	appendCommand *usecase.AppendApiRequestCommand
+	onStored      func(stored entity.Period)
+	evictCommand  *usecase.EvictExcessRecordsCommand
+
+	senderLabel string // Label stamped on every stored request, empty when not set
+	senderValue string
+
+	futureTimestampPolicy FutureTimestampPolicy
+	clockSkewTolerance    time.Duration
+	now                   func() time.Time
+
+	metrics *metricsReceiver
 }
 
 // NewReceiver creates a new OTLP receiver
 func NewReceiver(requestChan chan entity.APIRequest, program *tea.Program, appendCommand *usecase.AppendApiRequestCommand) *Receiver {
-	return &Receiver{
-		requestChan:   requestChan,
-		program:       program,
-		appendCommand: appendCommand,
+	r := &Receiver{
+		requestChan:           requestChan,
+		program:               program,
+		appendCommand:         appendCommand,
+		futureTimestampPolicy: FutureTimestampClamp,
+		clockSkewTolerance:    DefaultClockSkewTolerance,
+		now:                   time.Now,
+	}
+	r.metrics = newMetricsReceiver(r)
+	return r
+}
+
+// SetMetricsMapping enables ingesting usage from metric data points named by the mapping.
+// Without it metrics exports are accepted and ignored, as Claude Code also sends the usage as logs.
+func (r *Receiver) SetMetricsMapping(mapping MetricsMapping) {
+	r.metrics.setMapping(mapping)
+}
+
+// SetStoredHandler registers a function called once per export that stored requests, with the period
+// spanning their timestamps, e.g. to push fresh stats
+func (r *Receiver) SetStoredHandler(handler func(stored entity.Period)) {
+	r.onStored = handler
+}
+
+// SetEvictCommand enables trimming the stored requests to a maximum after each export
+func (r *Receiver) SetEvictCommand(command *usecase.EvictExcessRecordsCommand) {
+	r.evictCommand = command
+}
+
+// SetSenderLabel stamps every stored request with key=value, replacing a label of the same
+// name sent by the client, so requests carry who sent them
+func (r *Receiver) SetSenderLabel(key, value string) {
+	r.senderLabel = key
+	r.senderValue = value
+}
+
+// SetFutureTimestampPolicy sets how records stamped more than tolerance ahead of now are handled
+func (r *Receiver) SetFutureTimestampPolicy(policy FutureTimestampPolicy, tolerance time.Duration) {
+	r.futureTimestampPolicy = policy
+	r.clockSkewTolerance = tolerance
+}
+
+// checkTimestamp applies the future timestamp policy to a record from a skewed clock.
+// It returns the timestamp to store, or false when the record must be dropped.
+func (r *Receiver) checkTimestamp(sessionID string, timestamp time.Time) (time.Time, bool) {
+	now := r.now().UTC()
+	if !timestamp.After(now.Add(r.clockSkewTolerance)) {
+		return timestamp, true
 	}
+
+	ahead := timestamp.Sub(now).Round(time.Second)
+	if r.futureTimestampPolicy == FutureTimestampReject {
+		log.Printf("Warning: rejected request from session %s stamped %v ahead of server time (%s)", sessionID, ahead, timestamp.Format(time.RFC3339))
+		return timestamp, false
+	}
+
+	log.Printf("Warning: clamped request from session %s stamped %v ahead of server time (%s) to now", sessionID, ahead, timestamp.Format(time.RFC3339))
+	return now, true
 }
 
 // GetTraceServiceServer returns the trace service implementation
@@ -39,7 +122,7 @@ func (r *Receiver) GetTraceServiceServer() tracesv1.TraceServiceServer {
 
 // GetMetricsServiceServer returns the metrics service implementation
 func (r *Receiver) GetMetricsServiceServer() metricsv1.MetricsServiceServer {
-	return &metricsReceiver{}
+	return r.metrics
 }
 
 // GetLogsServiceServer returns the logs service implementation
@@ -47,6 +130,116 @@ func (r *Receiver) GetLogsServiceServer() logsv1.LogsServiceServer {
 	return &logsReceiver{receiver: r}
 }
 
+// Ingest submits an API request through the OTLP logs path, encoded the same way
+// Claude Code sends it, so parsing and storage are exercised end-to-end
+func (r *Receiver) Ingest(ctx context.Context, req entity.APIRequest) error {
+	_, err := r.GetLogsServiceServer().Export(ctx, service.NewAPIRequestLogs(req))
+	return err
+}
+
+// pausedError returns an Unavailable status while ingestion is paused, which OTLP exporters retry,
+// so no telemetry is lost during maintenance
+func (r *Receiver) pausedError() error {
+	if r.appendCommand == nil || !r.appendCommand.Paused() {
+		return nil
+	}
+	return status.Error(codes.Unavailable, "ingestion is paused for maintenance, retry later")
+}
+
+// storedBatch tracks the requests saved by one export, so follow-up work runs once per export
+type storedBatch struct {
+	saved       int
+	first, last time.Time
+}
+
+// add counts a saved request and widens the span to its timestamp
+func (b *storedBatch) add(timestamp time.Time) {
+	if b.saved == 0 || timestamp.Before(b.first) {
+		b.first = timestamp
+	}
+	if b.saved == 0 || timestamp.After(b.last) {
+		b.last = timestamp
+	}
+	b.saved++
+}
+
+// finishExport evicts requests over the maximum and reports the stored span once per export
+func (r *Receiver) finishExport(ctx context.Context, batch storedBatch) {
+	r.evictExcess(ctx, batch.saved)
+	if batch.saved > 0 && r.onStored != nil {
+		r.onStored(entity.NewPeriod(batch.first, batch.last))
+	}
+}
+
+// evictExcess trims the stored requests to the maximum once per export rather than per request
+func (r *Receiver) evictExcess(ctx context.Context, saved int) {
+	if r.evictCommand == nil || saved == 0 {
+		return
+	}
+
+	result, err := r.evictCommand.Execute(ctx, usecase.EvictExcessRecordsParams{Saved: saved})
+	if err != nil {
+		log.Printf("Failed to evict requests over server.max_records: %v", err)
+		return
+	}
+	if result.DeletedCount > 0 {
+		log.Printf("Evicted %d oldest requests over server.max_records", result.DeletedCount)
+	}
+}
+
+// store saves a received API request and forwards it to the request channel, if any.
+// It returns whether the request was saved.
+func (r *Receiver) store(apiReq entity.APIRequest) bool {
+	if r.senderLabel != "" {
+		labels := apiReq.Labels()
+		if labels == nil {
+			labels = make(map[string]string, 1)
+		}
+		labels[r.senderLabel] = r.senderValue
+		apiReq = apiReq.WithLabels(labels)
+	}
+
+	log.Printf("Received API request: session=%s, model=%s, tokens=%d, cost=$%.4f",
+		apiReq.SessionID(), apiReq.Model(), apiReq.Tokens().Total(), apiReq.Cost().Amount())
+
+	saved := false
+
+	// Save via usecase command
+	if r.appendCommand != nil {
+		params := usecase.AppendApiRequestParams{
+			SessionID:  apiReq.SessionID(),
+			Timestamp:  apiReq.Timestamp(),
+			Model:      string(apiReq.Model()),
+			Tokens:     apiReq.Tokens(),
+			Cost:       apiReq.Cost(),
+			DurationMS: apiReq.DurationMS(),
+			Labels:     apiReq.Labels(),
+		}
+		if err := r.appendCommand.Execute(context.Background(), params); errors.Is(err, usecase.ErrDuplicateRequest) {
+			log.Printf("Skipped duplicate request: session=%s, model=%s", apiReq.SessionID(), apiReq.Model())
+			return false
+		} else if errors.Is(err, usecase.ErrIngestionPaused) {
+			// Paused during the export, which then fails so the exporter resends it
+			return false
+		} else if err != nil {
+			log.Printf("Failed to save request via usecase: %v", err)
+		} else {
+			saved = true
+		}
+	}
+
+	// Send to channel (non-blocking) - only used in old architecture
+	if r.requestChan != nil {
+		select {
+		case r.requestChan <- apiReq:
+		default:
+			// Channel is full, drop the request
+		}
+	}
+
+	return saved
+}
+
 // traceReceiver handles trace exports (ignored)
 type traceReceiver struct {
 	tracesv1.UnimplementedTraceServiceServer
@@ -57,16 +250,6 @@ func (r *traceReceiver) Export(ctx context.Context, req *tracesv1.ExportTraceSer
 	return &tracesv1.ExportTraceServiceResponse{}, nil
 }
 
-// metricsReceiver handles metrics exports (ignored)
-type metricsReceiver struct {
-	metricsv1.UnimplementedMetricsServiceServer
-}
-
-func (r *metricsReceiver) Export(ctx context.Context, req *metricsv1.ExportMetricsServiceRequest) (*metricsv1.ExportMetricsServiceResponse, error) {
-	// Silently ignore metrics - we're focusing on logs for token usage
-	return &metricsv1.ExportMetricsServiceResponse{}, nil
-}
-
 // logsReceiver handles log exports
 type logsReceiver struct {
 	logsv1.UnimplementedLogsServiceServer
@@ -74,6 +257,12 @@ type logsReceiver struct {
 }
 
 func (r *logsReceiver) Export(ctx context.Context, req *logsv1.ExportLogsServiceRequest) (*logsv1.ExportLogsServiceResponse, error) {
+	if err := r.receiver.pausedError(); err != nil {
+		return nil, err
+	}
+
+	var rejected int64
+	var stored storedBatch
 	for _, rl := range req.ResourceLogs {
 		for _, sl := range rl.ScopeLogs {
 			for _, logRecord := range sl.LogRecords {
@@ -84,34 +273,11 @@ func (r *logsReceiver) Export(ctx context.Context, req *logsv1.ExportLogsService
 
 				// Check if this is an API request log
 				if body, ok := logRecord.Body.Value.(*commonv1.AnyValue_StringValue); ok && body.StringValue == "claude_code.api_request" {
-					apiReq := r.parseAPIRequest(logRecord)
-					if apiReq != nil {
-						log.Printf("Received API request: session=%s, model=%s, tokens=%d, cost=$%.4f",
-							apiReq.SessionID(), apiReq.Model(), apiReq.Tokens().Total(), apiReq.Cost().Amount())
-
-						// Save via usecase command
-						if r.receiver.appendCommand != nil {
-							params := usecase.AppendApiRequestParams{
-								SessionID:  apiReq.SessionID(),
-								Timestamp:  apiReq.Timestamp(),
-								Model:      string(apiReq.Model()),
-								Tokens:     apiReq.Tokens(),
-								Cost:       apiReq.Cost(),
-								DurationMS: apiReq.DurationMS(),
-							}
-							if err := r.receiver.appendCommand.Execute(context.Background(), params); err != nil {
-								log.Printf("Failed to save request via usecase: %v", err)
-							}
-						}
-
-						// Send to channel (non-blocking) - only used in old architecture
-						if r.receiver.requestChan != nil {
-							select {
-							case r.receiver.requestChan <- *apiReq:
-							default:
-								// Channel is full, drop the request
-							}
-						}
+					apiReq := r.parseAPIRequest(logRecord, resourceAttributes(rl.Resource))
+					if apiReq == nil {
+						rejected++
+					} else if r.receiver.store(*apiReq) {
+						stored.add(apiReq.Timestamp())
 					}
 				} else if body, ok := logRecord.Body.Value.(*commonv1.AnyValue_StringValue); ok && body.StringValue != "" {
 					// Log unsupported event types for analysis
@@ -120,16 +286,40 @@ func (r *logsReceiver) Export(ctx context.Context, req *logsv1.ExportLogsService
 			}
 		}
 	}
+	r.receiver.finishExport(ctx, stored)
+	// Requests already stored are skipped as duplicates when the export is resent
+	if err := r.receiver.pausedError(); err != nil {
+		return nil, err
+	}
+
+	if rejected > 0 {
+		return &logsv1.ExportLogsServiceResponse{
+			PartialSuccess: &logsv1.ExportLogsPartialSuccess{
+				RejectedLogRecords: rejected,
+				ErrorMessage:       "timestamp too far in the future",
+			},
+		}, nil
+	}
 
 	return &logsv1.ExportLogsServiceResponse{}, nil
 }
 
-// parseAPIRequest extracts API request data from a log record
-func (r *logsReceiver) parseAPIRequest(logRecord *logsdata.LogRecord) *entity.APIRequest {
+// parseAPIRequest extracts API request data from a log record.
+// Resource and record attributes not mapped to a request field are kept as labels,
+// with record attributes taking precedence. It returns nil when the future timestamp
+// policy rejects the record.
+func (r *logsReceiver) parseAPIRequest(logRecord *logsdata.LogRecord, resourceAttrs []*commonv1.KeyValue) *entity.APIRequest {
 	var sessionID, timestampStr, model string
 	var inputTokens, outputTokens, cacheReadTokens, cacheCreationTokens int64
 	var costUSD float64
 	var durationMS int64
+	labels := make(map[string]string)
+
+	for _, attr := range resourceAttrs {
+		if value, ok := attributeString(attr.Value); ok {
+			labels[attr.Key] = value
+		}
+	}
 
 	for _, attr := range logRecord.Attributes {
 		switch attr.Key {
@@ -181,6 +371,12 @@ func (r *logsReceiver) parseAPIRequest(logRecord *logsdata.LogRecord) *entity.AP
 					log.Printf("Warning: failed to parse duration_ms '%s': %v", v.StringValue, err)
 				}
 			}
+		case "event.name":
+			// Always "api_request" for the records we parse, not worth keeping
+		default:
+			if value, ok := attributeString(attr.Value); ok {
+				labels[attr.Key] = value
+			}
 		}
 	}
 
@@ -189,9 +385,41 @@ func (r *logsReceiver) parseAPIRequest(logRecord *logsdata.LogRecord) *entity.AP
 	if err != nil {
 		timestamp = time.Now().UTC()
 	}
+	timestamp, ok := r.receiver.checkTimestamp(sessionID, timestamp)
+	if !ok {
+		return nil
+	}
 
 	tokens := entity.NewToken(inputTokens, outputTokens, cacheReadTokens, cacheCreationTokens)
 	cost := entity.NewCost(costUSD)
-	req := entity.NewAPIRequest(sessionID, timestamp, model, tokens, cost, durationMS)
+	req := entity.NewAPIRequest(sessionID, timestamp, model, tokens, cost, durationMS).WithLabels(labels)
 	return &req
 }
+
+// resourceAttributes returns the attributes of an OTLP resource, if any
+func resourceAttributes(resource *resourcev1.Resource) []*commonv1.KeyValue {
+	if resource == nil {
+		return nil
+	}
+	return resource.Attributes
+}
+
+// attributeString converts a scalar OTLP attribute value to a label string
+func attributeString(value *commonv1.AnyValue) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+
+	switch v := value.Value.(type) {
+	case *commonv1.AnyValue_StringValue:
+		return v.StringValue, true
+	case *commonv1.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue), true
+	case *commonv1.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10), true
+	case *commonv1.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
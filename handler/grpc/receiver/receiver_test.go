@@ -20,6 +20,8 @@ import (
 	metricsdata "go.opentelemetry.io/proto/otlp/metrics/v1"
 	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
 	tracesdata "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Helper function to create OTLP log request with Claude Code API request data
@@ -270,6 +272,51 @@ func TestOTLPReceiver_LogsServiceExport(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "extra_attributes_captured_as_labels",
+			request: func() *logsv1.ExportLogsServiceRequest {
+				req := createClaudeCodeLogRequest(
+					"labeled-session",
+					validTimestamp,
+					"claude-3-sonnet-20240229",
+					100, 50, 10, 5,
+					0.30,
+					500,
+				)
+				req.ResourceLogs[0].Resource.Attributes = []*commonv1.KeyValue{
+					{Key: "environment", Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: "staging"}}},
+					{Key: "service.name", Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: "claude-code"}}},
+				}
+				record := req.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+				record.Attributes = append(record.Attributes,
+					&commonv1.KeyValue{Key: "environment", Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: "prod"}}},
+					&commonv1.KeyValue{Key: "user.id", Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: "alice"}}},
+					&commonv1.KeyValue{Key: "attempt", Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_IntValue{IntValue: 2}}},
+				)
+				return req
+			}(),
+			expectedSavedCount: 1,
+			validateSaved: func(t *testing.T, saved entity.APIRequest) {
+				expected := map[string]string{
+					"environment":  "prod", // record attributes override resource attributes
+					"service.name": "claude-code",
+					"user.id":      "alice",
+					"attempt":      "2",
+				}
+				labels := saved.Labels()
+				if len(labels) != len(expected) {
+					t.Errorf("Expected %d labels, got %d: %v", len(expected), len(labels), labels)
+				}
+				for key, value := range expected {
+					if labels[key] != value {
+						t.Errorf("Expected label %s=%s, got %q", key, value, labels[key])
+					}
+				}
+				if _, ok := saved.Label("session.id"); ok {
+					t.Error("Mapped attributes should not be kept as labels")
+				}
+			},
+		},
 		{
 			name: "empty_request",
 			request: &logsv1.ExportLogsServiceRequest{
@@ -640,3 +687,277 @@ func TestOTLPReceiver_UnsupportedEventLogging(t *testing.T) {
 		})
 	}
 }
+
+func TestOTLPReceiver_FutureTimestamps(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		policy            FutureTimestampPolicy
+		timestamp         time.Time
+		expectedSaved     bool
+		expectedTimestamp time.Time
+		expectedRejected  int64
+		expectedLog       string
+	}{
+		{
+			name:              "timestamp within tolerance is kept",
+			policy:            FutureTimestampClamp,
+			timestamp:         now.Add(4 * time.Minute),
+			expectedSaved:     true,
+			expectedTimestamp: now.Add(4 * time.Minute),
+		},
+		{
+			name:              "future timestamp is clamped to now",
+			policy:            FutureTimestampClamp,
+			timestamp:         now.Add(48 * time.Hour),
+			expectedSaved:     true,
+			expectedTimestamp: now,
+			expectedLog:       "clamped request from session future-session stamped 48h0m0s ahead",
+		},
+		{
+			name:             "future timestamp is rejected",
+			policy:           FutureTimestampReject,
+			timestamp:        now.Add(48 * time.Hour),
+			expectedSaved:    false,
+			expectedRejected: 1,
+			expectedLog:      "rejected request from session future-session stamped 48h0m0s ahead",
+		},
+		{
+			name:              "past timestamp is never adjusted",
+			policy:            FutureTimestampReject,
+			timestamp:         now.Add(-48 * time.Hour),
+			expectedSaved:     true,
+			expectedTimestamp: now.Add(-48 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			originalOutput := log.Writer()
+			log.SetOutput(&buf)
+			defer log.SetOutput(originalOutput)
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+			receiver.SetFutureTimestampPolicy(tt.policy, DefaultClockSkewTolerance)
+			receiver.now = func() time.Time { return now }
+
+			request := createClaudeCodeLogRequest(
+				"future-session",
+				tt.timestamp.Format(time.RFC3339),
+				"claude-3-sonnet-20240229",
+				100, 50, 0, 0,
+				0.10,
+				500,
+			)
+			resp, err := receiver.GetLogsServiceServer().Export(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			if got := resp.GetPartialSuccess().GetRejectedLogRecords(); got != tt.expectedRejected {
+				t.Errorf("Expected %d rejected records, got %d", tt.expectedRejected, got)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if tt.expectedSaved {
+				if len(requests) != 1 {
+					t.Fatalf("Expected 1 saved request, got %d", len(requests))
+				}
+				if !requests[0].Timestamp().Equal(tt.expectedTimestamp) {
+					t.Errorf("Expected timestamp %v, got %v", tt.expectedTimestamp, requests[0].Timestamp())
+				}
+			} else if len(requests) != 0 {
+				t.Errorf("Expected no saved requests, got %d", len(requests))
+			}
+
+			if tt.expectedLog != "" && !strings.Contains(buf.String(), tt.expectedLog) {
+				t.Errorf("Expected log to contain %q, got: %s", tt.expectedLog, buf.String())
+			}
+		})
+	}
+}
+
+// countingRecordLimitRepository counts how often the store is counted and trimmed
+type countingRecordLimitRepository struct {
+	*testutil.MockAPIRequestRepository
+	countCalls  int
+	deleteCalls int
+}
+
+func (r *countingRecordLimitRepository) CountRequests() (int, error) {
+	r.countCalls++
+	return r.MockAPIRequestRepository.CountRequests()
+}
+
+func (r *countingRecordLimitRepository) DeleteOldestBeyond(maxRecords int) (int, error) {
+	r.deleteCalls++
+	return r.MockAPIRequestRepository.DeleteOldestBeyond(maxRecords)
+}
+
+func TestOTLPReceiver_EvictsExcessPerExport(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	limitRepo := &countingRecordLimitRepository{MockAPIRequestRepository: mockRepo}
+
+	receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+	receiver.SetEvictCommand(usecase.NewEvictExcessRecordsCommand(limitRepo, 2))
+
+	batch := func(sessionIDs ...string) *logsv1.ExportLogsServiceRequest {
+		request := createClaudeCodeLogRequest(sessionIDs[0], "2025-06-01T10:00:00Z", "claude-3-sonnet-20240229", 100, 50, 0, 0, 0.10, 500)
+		scope := request.ResourceLogs[0].ScopeLogs[0]
+		for i, sessionID := range sessionIDs[1:] {
+			timestamp := fmt.Sprintf("2025-06-01T10:%02d:00Z", i+1)
+			scope.LogRecords = append(scope.LogRecords, createClaudeCodeLogRequest(sessionID, timestamp, "claude-3-sonnet-20240229", 100, 50, 0, 0, 0.10, 500).ResourceLogs[0].ScopeLogs[0].LogRecords...)
+		}
+		return request
+	}
+
+	// A batch of three requests is trimmed once, keeping the newest two
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), batch("session-1", "session-2", "session-3")); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if limitRepo.countCalls != 1 || limitRepo.deleteCalls != 1 {
+		t.Errorf("Expected 1 count and 1 delete for the batch, got %d and %d", limitRepo.countCalls, limitRepo.deleteCalls)
+	}
+
+	requests, _ := mockRepo.FindAll()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 stored requests, got %d", len(requests))
+	}
+	for _, req := range requests {
+		if req.SessionID() == "session-1" {
+			t.Errorf("Expected the oldest request to be evicted")
+		}
+	}
+
+	// An export without saved requests leaves the store alone
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), &logsv1.ExportLogsServiceRequest{}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if limitRepo.countCalls != 1 || limitRepo.deleteCalls != 1 {
+		t.Errorf("Expected no repository calls for an empty export, got %d counts and %d deletes", limitRepo.countCalls, limitRepo.deleteCalls)
+	}
+}
+
+func TestOTLPReceiver_StoredHandlerPerExport(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+
+	var stored []entity.Period
+	receiver.SetStoredHandler(func(period entity.Period) {
+		stored = append(stored, period)
+	})
+
+	request := createClaudeCodeLogRequest("session-1", "2025-06-01T10:05:00Z", "claude-3-sonnet-20240229", 100, 50, 0, 0, 0.10, 500)
+	for _, record := range []struct{ sessionID, timestamp string }{
+		{"session-2", "2025-06-01T10:00:00Z"},
+		{"session-3", "2025-06-01T10:10:00Z"},
+	} {
+		request.ResourceLogs[0].ScopeLogs[0].LogRecords = append(request.ResourceLogs[0].ScopeLogs[0].LogRecords,
+			createClaudeCodeLogRequest(record.sessionID, record.timestamp, "claude-3-sonnet-20240229", 100, 50, 0, 0, 0.10, 500).ResourceLogs[0].ScopeLogs[0].LogRecords...)
+	}
+
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("Expected one notification for the export, got %d", len(stored))
+	}
+	wantStart := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2025, 6, 1, 10, 10, 0, 0, time.UTC)
+	if !stored[0].StartAt().Equal(wantStart) || !stored[0].EndAt().Equal(wantEnd) {
+		t.Errorf("Expected the span %v - %v, got %v - %v", wantStart, wantEnd, stored[0].StartAt(), stored[0].EndAt())
+	}
+
+	// An export without stored requests reports nothing
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), &logsv1.ExportLogsServiceRequest{}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("Expected no notification without stored requests, got %d", len(stored))
+	}
+}
+
+func TestOTLPReceiver_SenderLabel(t *testing.T) {
+	tests := []struct {
+		name           string
+		clientLabels   map[string]string
+		expectedLabels map[string]string
+	}{
+		{
+			name:           "stamps requests without labels",
+			expectedLabels: map[string]string{"user": "alice"},
+		},
+		{
+			name:           "keeps other client labels",
+			clientLabels:   map[string]string{"environment": "prod"},
+			expectedLabels: map[string]string{"user": "alice", "environment": "prod"},
+		},
+		{
+			name:           "replaces the label sent by the client",
+			clientLabels:   map[string]string{"user": "bob"},
+			expectedLabels: map[string]string{"user": "alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+			receiver.SetSenderLabel("user", "alice")
+
+			request := createClaudeCodeLogRequest("session-1", "2025-06-01T10:00:00Z", "claude-3-sonnet-20240229", 100, 50, 0, 0, 0.10, 500)
+			for key, value := range tt.clientLabels {
+				request.ResourceLogs[0].Resource.Attributes = append(request.ResourceLogs[0].Resource.Attributes, &commonv1.KeyValue{
+					Key:   key,
+					Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}},
+				})
+			}
+
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != 1 {
+				t.Fatalf("Expected 1 stored request, got %d", len(requests))
+			}
+			labels := requests[0].Labels()
+			if len(labels) != len(tt.expectedLabels) {
+				t.Errorf("Expected labels %v, got %v", tt.expectedLabels, labels)
+			}
+			for key, value := range tt.expectedLabels {
+				if labels[key] != value {
+					t.Errorf("Expected label %s=%s, got %q", key, value, labels[key])
+				}
+			}
+		})
+	}
+}
+
+func TestOTLPReceiver_PausedIngestion(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	appendCommand := usecase.NewAppendApiRequestCommand(mockRepo)
+	receiver := NewReceiver(nil, nil, appendCommand)
+	request := createClaudeCodeLogRequest("session-1", "2025-06-01T10:00:00Z", "claude-3-sonnet-20240229", 100, 50, 0, 0, 0.10, 500)
+
+	// Exporters retry Unavailable, so the request is resent once ingestion resumes
+	appendCommand.SetPaused(true)
+	_, err := receiver.GetLogsServiceServer().Export(context.Background(), request)
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Fatalf("Expected Unavailable while paused, got %v (err: %v)", code, err)
+	}
+	if requests, _ := mockRepo.FindAll(); len(requests) != 0 {
+		t.Fatalf("Expected no stored requests while paused, got %d", len(requests))
+	}
+
+	appendCommand.SetPaused(false)
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+		t.Fatalf("Export failed after resuming: %v", err)
+	}
+	if requests, _ := mockRepo.FindAll(); len(requests) != 1 {
+		t.Errorf("Expected 1 stored request after resuming, got %d", len(requests))
+	}
+}
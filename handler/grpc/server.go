@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/netip"
 	"os"
 	"os/signal"
 	"syscall"
@@ -24,17 +25,82 @@ import (
 type ServerConfig interface {
 	IsRetentionEnabled() bool
 	GetRetentionDuration() time.Duration
+	IsReadOnly() bool
+	GetAuthToken() string
+	GetRateLimit() (requestsPerSecond float64, burst int)
+	GetFutureTimestampPolicy() string
+	GetClockSkewTolerance() time.Duration
+	GetMaxQueryPeriod() time.Duration
+	GetStatsDInterval() time.Duration
+	GetStreamInterval() time.Duration
+	GetMetricNames() (tokenMetric, costMetric string)
+	GetMetricAttributes() (typeAttribute, modelAttribute, sessionAttribute string)
+	GetListeners() []Listener
+	GetMaxBulkAppend() int
+	GetAllowedNetworks() []netip.Prefix
+}
+
+// Listener is an additional OTLP receiver address with its own auth token. Requests received
+// on it are labeled Label=Value, e.g. user=alice, so a shared server can attribute them.
+type Listener struct {
+	Address   string
+	AuthToken string
+	Label     string
+	Value     string
 }
 
 // RunServer runs the headless OTLP server mode
-func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, cleanupCommand *usecase.CleanupOldRecordsCommand, serverConfig ServerConfig) error {
-	log.Println("Starting ccmon in server mode...")
+func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, cleanupCommand *usecase.CleanupOldRecordsCommand, evictCommand *usecase.EvictExcessRecordsCommand, deleteByPeriodCommand *usecase.DeleteRequestsByPeriodCommand, setNoteCommand *usecase.SetRequestNoteCommand, createBackupQuery *usecase.CreateBackupQuery, getDashboardQuery *usecase.GetDashboardQuery, getModelsQuery *usecase.GetModelsQuery, getStorageInfoQuery *usecase.GetStorageInfoQuery, publishMetricsCommand *usecase.PublishUsageMetricsCommand, autoExportCommand *usecase.AutoExportCommand, serverConfig ServerConfig) error {
+	readOnly := serverConfig.IsReadOnly()
+	if readOnly {
+		log.Println("Starting ccmon in query-only server mode (OTLP receiver disabled)...")
+	} else {
+		log.Println("Starting ccmon in server mode...")
+	}
 
 	// Create the OTLP receiver
-	otlpReceiver := receiver.NewReceiver(nil, nil, appendCommand) // No channel or TUI program needed
+	otlpReceiver := newOTLPReceiver(appendCommand, serverConfig)
+	if tokenMetric, costMetric := serverConfig.GetMetricNames(); tokenMetric != "" {
+		log.Printf("Ingesting usage from the %s and %s metrics", tokenMetric, costMetric)
+	}
+
+	// Trim the store once at startup, e.g. after lowering the limit, then after each export
+	if evictCommand != nil && !readOnly {
+		runEviction(evictCommand)
+		otlpReceiver.SetEvictCommand(evictCommand)
+	}
+
+	// The health check submits through the receiver, so it needs a writable store
+	var healthCheckCommand *usecase.HealthCheckCommand
+	if !readOnly {
+		healthCheckCommand = usecase.NewHealthCheckCommand(otlpReceiver, getFilteredQuery, deleteByPeriodCommand)
+	}
+
+	// Notes are written to the store, so they are unavailable on a read-only database
+	if readOnly {
+		setNoteCommand = nil
+	}
 
 	// Create the query service
-	queryService := query.NewService(getFilteredQuery, calculateStatsQuery)
+	queryService := query.NewService(getFilteredQuery, calculateStatsQuery, deleteByPeriodCommand, healthCheckCommand, setNoteCommand)
+	queryService.SetBackupQuery(createBackupQuery)
+	queryService.SetDashboardQuery(getDashboardQuery)
+	queryService.SetModelsQuery(getModelsQuery)
+	queryService.SetStorageInfoQuery(getStorageInfoQuery)
+	queryService.SetMaxQueryPeriod(serverConfig.GetMaxQueryPeriod())
+	queryService.SetStreamInterval(serverConfig.GetStreamInterval())
+	// Batches are written to the store, so they are only accepted alongside the OTLP receiver.
+	// The append command is shared by every receiver, so pausing it stops all ingestion.
+	if !readOnly {
+		queryService.SetAppendCommand(appendCommand)
+		queryService.SetEvictCommand(evictCommand)
+		queryService.SetMaxBulkAppend(serverConfig.GetMaxBulkAppend())
+	}
+	// Streamed stats are pushed as soon as a request is stored
+	otlpReceiver.SetStoredHandler(queryService.NotifyNewData)
+	if maxQueryPeriod := serverConfig.GetMaxQueryPeriod(); maxQueryPeriod > 0 {
+		log.Printf("Request list queries limited to a period of %v", maxQueryPeriod)
+	}
 
 	// Set up gRPC server
 	lis, err := net.Listen("tcp", address)
@@ -42,15 +108,27 @@ func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, g
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	grpcServer := grpc.NewServer()
-
-	// Register the OTLP services
-	tracesv1.RegisterTraceServiceServer(grpcServer, otlpReceiver.GetTraceServiceServer())
-	metricsv1.RegisterMetricsServiceServer(grpcServer, otlpReceiver.GetMetricsServiceServer())
-	logsv1.RegisterLogsServiceServer(grpcServer, otlpReceiver.GetLogsServiceServer())
-
-	// Register the query service
-	pb.RegisterQueryServiceServer(grpcServer, queryService)
+	// Peers outside the allowed networks are rejected before their token is checked
+	allowListInterceptor := NewAllowListInterceptor(serverConfig.GetAllowedNetworks())
+	if allowListInterceptor.IsEnabled() {
+		log.Printf("Accepting connections only from %d allowed networks", len(serverConfig.GetAllowedNetworks()))
+	}
+	// Destructive, writing and diagnostic methods are only reachable with an auth token configured
+	authInterceptor := NewAuthInterceptor(serverConfig.GetAuthToken(), deleteByPeriodMethod, bulkAppendMethod, setIngestionPausedMethod, setNoteMethod, getStorageInfoMethod)
+	if serverConfig.GetAuthToken() != "" {
+		log.Printf("Auth token required on %s, including OTLP exports (set OTEL_EXPORTER_OTLP_HEADERS=\"authorization=<token>\")", address)
+	}
+	// Rate limiting runs after auth so rejected clients never consume tokens
+	requestsPerSecond, burst := serverConfig.GetRateLimit()
+	rateLimitInterceptor := NewRateLimitInterceptor(requestsPerSecond, burst)
+	if rateLimitInterceptor.IsEnabled() {
+		log.Printf("Query rate limit: %g requests/s per client, burst %d", requestsPerSecond, burst)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(allowListInterceptor.Unary(), authInterceptor.Unary(), rateLimitInterceptor.Unary()),
+		grpc.ChainStreamInterceptor(allowListInterceptor.Stream(), authInterceptor.Stream(), rateLimitInterceptor.Stream()),
+	)
+	registerServices(grpcServer, otlpReceiver, queryService, readOnly)
 
 	// Create a context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -65,11 +143,36 @@ func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, g
 		cancel()
 	}()
 
-	// Start cleanup scheduler if retention is enabled
-	if serverConfig.IsRetentionEnabled() {
+	// Start cleanup scheduler if retention is enabled (never on a read-only database)
+	if serverConfig.IsRetentionEnabled() && !readOnly {
 		startCleanupScheduler(ctx, cleanupCommand, serverConfig)
 	}
 
+	// Push usage gauges to StatsD when an address is configured
+	if publishMetricsCommand != nil && serverConfig.GetStatsDInterval() > 0 {
+		startMetricsPublisher(ctx, publishMetricsCommand, serverConfig.GetStatsDInterval())
+	}
+
+	// Export each completed period when an export directory is configured
+	if autoExportCommand != nil {
+		startAutoExporter(ctx, autoExportCommand)
+	}
+
+	// Serve the additional OTLP listeners, each labeling requests with who sent them
+	if !readOnly {
+		for _, listener := range serverConfig.GetListeners() {
+			listenerReceiver := newOTLPReceiver(appendCommand, serverConfig)
+			listenerReceiver.SetSenderLabel(listener.Label, listener.Value)
+			if evictCommand != nil {
+				listenerReceiver.SetEvictCommand(evictCommand)
+			}
+			listenerReceiver.SetStoredHandler(queryService.NotifyNewData)
+			if err := startListener(ctx, listener, listenerReceiver, allowListInterceptor); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Handle graceful shutdown
 	go func() {
 		<-ctx.Done()
@@ -77,7 +180,11 @@ func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, g
 	}()
 
 	// Start the gRPC server
-	log.Printf("gRPC server (OTLP + Query) listening on %s\n", address)
+	if readOnly {
+		log.Printf("gRPC server (Query only) listening on %s\n", address)
+	} else {
+		log.Printf("gRPC server (OTLP + Query) listening on %s\n", address)
+	}
 	if err := grpcServer.Serve(lis); err != nil {
 		return fmt.Errorf("failed to start gRPC server: %w", err)
 	}
@@ -85,6 +192,70 @@ func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, g
 	return nil
 }
 
+// newOTLPReceiver creates an OTLP receiver storing through appendCommand with the configured
+// future timestamp policy and metrics mapping
+func newOTLPReceiver(appendCommand *usecase.AppendApiRequestCommand, serverConfig ServerConfig) *receiver.Receiver {
+	otlpReceiver := receiver.NewReceiver(nil, nil, appendCommand) // No channel or TUI program needed
+	otlpReceiver.SetFutureTimestampPolicy(receiver.FutureTimestampPolicy(serverConfig.GetFutureTimestampPolicy()), serverConfig.GetClockSkewTolerance())
+	if tokenMetric, costMetric := serverConfig.GetMetricNames(); tokenMetric != "" {
+		typeAttribute, modelAttribute, sessionAttribute := serverConfig.GetMetricAttributes()
+		otlpReceiver.SetMetricsMapping(receiver.MetricsMapping{
+			TokenMetric:      tokenMetric,
+			CostMetric:       costMetric,
+			TypeAttribute:    typeAttribute,
+			ModelAttribute:   modelAttribute,
+			SessionAttribute: sessionAttribute,
+		})
+	}
+	return otlpReceiver
+}
+
+// startListener serves the OTLP services of listenerReceiver on the listener address until ctx is done.
+// Only the OTLP services are registered, and every call from an allowed peer must carry the listener's own token.
+func startListener(ctx context.Context, listener Listener, listenerReceiver *receiver.Receiver, allowListInterceptor *AllowListInterceptor) error {
+	lis, err := net.Listen("tcp", listener.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listener.Address, err)
+	}
+
+	authInterceptor := NewAuthInterceptor(listener.AuthToken)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(allowListInterceptor.Unary(), authInterceptor.Unary()),
+		grpc.ChainStreamInterceptor(allowListInterceptor.Stream(), authInterceptor.Stream()),
+	)
+	registerReceiverServices(grpcServer, listenerReceiver)
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("OTLP listener on %s stopped: %v", listener.Address, err)
+		}
+	}()
+
+	log.Printf("gRPC server (OTLP as %s=%s) listening on %s\n", listener.Label, listener.Value, listener.Address)
+	return nil
+}
+
+// registerServices registers the query service and, unless running read-only, the OTLP receiver services
+func registerServices(grpcServer *grpc.Server, otlpReceiver *receiver.Receiver, queryService *query.Service, readOnly bool) {
+	if !readOnly {
+		registerReceiverServices(grpcServer, otlpReceiver)
+	}
+
+	// Register the query service
+	pb.RegisterQueryServiceServer(grpcServer, queryService)
+}
+
+// registerReceiverServices registers the OTLP trace, metrics and logs services
+func registerReceiverServices(grpcServer *grpc.Server, otlpReceiver *receiver.Receiver) {
+	tracesv1.RegisterTraceServiceServer(grpcServer, otlpReceiver.GetTraceServiceServer())
+	metricsv1.RegisterMetricsServiceServer(grpcServer, otlpReceiver.GetMetricsServiceServer())
+	logsv1.RegisterLogsServiceServer(grpcServer, otlpReceiver.GetLogsServiceServer())
+}
+
 // startCleanupScheduler starts a background cleanup scheduler
 func startCleanupScheduler(ctx context.Context, cleanupCommand *usecase.CleanupOldRecordsCommand, serverConfig ServerConfig) {
 	retentionDuration := serverConfig.GetRetentionDuration()
@@ -111,6 +282,87 @@ func startCleanupScheduler(ctx context.Context, cleanupCommand *usecase.CleanupO
 	}()
 }
 
+// startMetricsPublisher publishes the usage gauges now and then on every interval until ctx is done
+func startMetricsPublisher(ctx context.Context, publishMetricsCommand *usecase.PublishUsageMetricsCommand, interval time.Duration) {
+	log.Printf("Starting StatsD emitter: interval=%v", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		runMetricsPublish(ctx, publishMetricsCommand)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("StatsD emitter stopped")
+				return
+			case <-ticker.C:
+				runMetricsPublish(ctx, publishMetricsCommand)
+			}
+		}
+	}()
+}
+
+// startAutoExporter exports the completed periods missing from the archive now and then checks for a newly completed one every hour until ctx is done
+func startAutoExporter(ctx context.Context, autoExportCommand *usecase.AutoExportCommand) {
+	exportInterval := time.Hour // Periods complete at midnight, an hour late at most is fine for archiving
+
+	log.Printf("Starting auto export: interval=%v", exportInterval)
+
+	go func() {
+		ticker := time.NewTicker(exportInterval)
+		defer ticker.Stop()
+
+		runAutoExport(ctx, autoExportCommand)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Auto export stopped")
+				return
+			case <-ticker.C:
+				runAutoExport(ctx, autoExportCommand)
+			}
+		}
+	}()
+}
+
+// runAutoExport exports the completed periods not archived yet, logging the outcome without stopping the exporter
+func runAutoExport(ctx context.Context, autoExportCommand *usecase.AutoExportCommand) {
+	results, err := autoExportCommand.Execute(ctx)
+	for _, result := range results {
+		if result.Skipped {
+			log.Printf("Auto export skipped %s: already exported", result.Name)
+			continue
+		}
+		log.Printf("Auto export completed: wrote %d requests for %s", result.Exported, result.Name)
+	}
+	if err != nil {
+		log.Printf("Auto export failed: %v", err)
+	}
+}
+
+// runMetricsPublish publishes the usage gauges once, logging failures without stopping the emitter
+func runMetricsPublish(ctx context.Context, publishMetricsCommand *usecase.PublishUsageMetricsCommand) {
+	if _, err := publishMetricsCommand.Execute(ctx); err != nil {
+		log.Printf("StatsD publish failed: %v", err)
+	}
+}
+
+// runEviction trims the stored requests to server.max_records
+func runEviction(evictCommand *usecase.EvictExcessRecordsCommand) {
+	result, err := evictCommand.Execute(context.Background(), usecase.EvictExcessRecordsParams{})
+	if err != nil {
+		log.Printf("Eviction failed: %v", err)
+		return
+	}
+
+	if result.DeletedCount > 0 {
+		log.Printf("Eviction completed: deleted %d oldest requests over server.max_records", result.DeletedCount)
+	}
+}
+
 // runCleanup performs a single cleanup operation
 func runCleanup(ctx context.Context, cleanupCommand *usecase.CleanupOldRecordsCommand, retentionDuration time.Duration) {
 	cutoffTime := time.Now().Add(-retentionDuration)
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"testing"
@@ -17,6 +18,60 @@ import (
 // MockServerConfig implements ServerConfig interface for testing
 type MockServerConfig struct {
 	retention string
+	readOnly  bool
+	authToken string
+}
+
+func (m MockServerConfig) GetAuthToken() string {
+	return m.authToken
+}
+
+func (m MockServerConfig) GetRateLimit() (float64, int) {
+	return 0, 0
+}
+
+func (m MockServerConfig) GetFutureTimestampPolicy() string {
+	return "clamp"
+}
+
+func (m MockServerConfig) GetClockSkewTolerance() time.Duration {
+	return 5 * time.Minute
+}
+
+func (m MockServerConfig) GetMaxQueryPeriod() time.Duration {
+	return 0
+}
+
+func (m MockServerConfig) GetStatsDInterval() time.Duration {
+	return 0
+}
+
+func (m MockServerConfig) GetStreamInterval() time.Duration {
+	return 10 * time.Second
+}
+
+func (m MockServerConfig) GetMetricNames() (string, string) {
+	return "", ""
+}
+
+func (m MockServerConfig) GetMetricAttributes() (string, string, string) {
+	return "", "", ""
+}
+
+func (m MockServerConfig) GetListeners() []Listener {
+	return nil
+}
+
+func (m MockServerConfig) GetMaxBulkAppend() int {
+	return 1000
+}
+
+func (m MockServerConfig) GetAllowedNetworks() []netip.Prefix {
+	return nil
+}
+
+func (m MockServerConfig) IsReadOnly() bool {
+	return m.readOnly
 }
 
 func (m MockServerConfig) IsRetentionEnabled() bool {
@@ -1,7 +1,9 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net"
 	"testing"
 	"time"
@@ -14,11 +16,11 @@ import (
 	"github.com/elct9620/ccmon/testutil"
 	"github.com/elct9620/ccmon/usecase"
 	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
-	metricsv1 "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
-	tracesv1 "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -49,15 +51,17 @@ func setupTestServer(t *testing.T) (*grpc.Server, *bufconn.Listener, pb.QuerySer
 	otlpReceiver := receiver.NewReceiver(nil, nil, appendCommand)
 
 	// Create the query service
-	queryService := query.NewService(getFilteredQuery, calculateStatsQuery)
-
-	// Register OTLP services
-	tracesv1.RegisterTraceServiceServer(grpcServer, otlpReceiver.GetTraceServiceServer())
-	metricsv1.RegisterMetricsServiceServer(grpcServer, otlpReceiver.GetMetricsServiceServer())
-	logsv1.RegisterLogsServiceServer(grpcServer, otlpReceiver.GetLogsServiceServer())
-
-	// Register the query service
-	pb.RegisterQueryServiceServer(grpcServer, queryService)
+	deleteByPeriodCommand := usecase.NewDeleteRequestsByPeriodCommand(mockRepo)
+	healthCheckCommand := usecase.NewHealthCheckCommand(otlpReceiver, getFilteredQuery, deleteByPeriodCommand)
+	setNoteCommand := usecase.NewSetRequestNoteCommand(mockRepo)
+	queryService := query.NewService(getFilteredQuery, calculateStatsQuery, deleteByPeriodCommand, healthCheckCommand, setNoteCommand)
+	planRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0)))
+	queryService.SetDashboardQuery(usecase.NewGetDashboardQuery(calculateStatsQuery, planRepo))
+	queryService.SetModelsQuery(usecase.NewGetModelsQuery(mockRepo))
+	otlpReceiver.SetStoredHandler(queryService.NotifyNewData)
+
+	// Register OTLP and query services
+	registerServices(grpcServer, otlpReceiver, queryService, false)
 
 	// Start server in background
 	go func() {
@@ -173,6 +177,133 @@ func TestGRPCServer_QueryService_GetStats(t *testing.T) {
 	}
 }
 
+func TestGRPCServer_QueryService_GetDashboard(t *testing.T) {
+	_, _, client, mockRepo := setupTestServer(t)
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		mustCreateAPIRequest("session1", now.Add(-6*time.Hour), "claude-3-haiku-20240307", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.10), 1000),
+		mustCreateAPIRequest("session1", now.Add(-2*time.Hour), "claude-3-sonnet-20240229", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.50), 1000),
+		mustCreateAPIRequest("session2", now.Add(-time.Hour), "claude-3-sonnet-20240229", entity.NewToken(300, 100, 0, 0), entity.NewCost(0.70), 1000),
+	}
+	for _, req := range requests {
+		if err := mockRepo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	blockStart := now.Add(-3 * time.Hour)
+	tests := []struct {
+		name                  string
+		blockStart            *time.Time
+		expectedBlockRequests int32
+	}{
+		{
+			name: "period stats and plan without block",
+		},
+		{
+			name:                  "block stats and boundaries when block is requested",
+			blockStart:            &blockStart,
+			expectedBlockRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pb.GetDashboardRequest{
+				StartTime: timestamppb.New(now.Add(-24 * time.Hour)),
+				EndTime:   timestamppb.New(now),
+			}
+			if tt.blockStart != nil {
+				req.BlockStart = timestamppb.New(*tt.blockStart)
+			}
+
+			resp, err := client.GetDashboard(context.Background(), req)
+			if err != nil {
+				t.Fatalf("GetDashboard failed: %v", err)
+			}
+
+			if resp.Stats.TotalRequests != 3 {
+				t.Errorf("Expected 3 period requests, got %d", resp.Stats.TotalRequests)
+			}
+			if resp.Plan.Name != "pro" || resp.Plan.Price.Amount != 20.0 {
+				t.Errorf("Expected pro plan at $20, got %s at $%.2f", resp.Plan.Name, resp.Plan.Price.Amount)
+			}
+
+			if tt.blockStart == nil {
+				if resp.Block != nil {
+					t.Errorf("Expected no block, got %v", resp.Block)
+				}
+				return
+			}
+			if resp.Block == nil {
+				t.Fatal("Expected block in response")
+			}
+			if !resp.Block.StartTime.AsTime().Equal(*tt.blockStart) || !resp.Block.EndTime.AsTime().Equal(tt.blockStart.Add(5*time.Hour)) {
+				t.Errorf("Expected block %v - %v, got %v - %v", *tt.blockStart, tt.blockStart.Add(5*time.Hour),
+					resp.Block.StartTime.AsTime(), resp.Block.EndTime.AsTime())
+			}
+			if resp.Block.Stats.TotalRequests != tt.expectedBlockRequests {
+				t.Errorf("Expected %d block requests, got %d", tt.expectedBlockRequests, resp.Block.Stats.TotalRequests)
+			}
+		})
+	}
+}
+
+func TestGRPCServer_QueryService_GetDashboardUnavailable(t *testing.T) {
+	queryService := query.NewService(nil, nil, nil, nil, nil)
+
+	_, err := queryService.GetDashboard(context.Background(), &pb.GetDashboardRequest{})
+	if code := status.Code(err); code != codes.Unimplemented {
+		t.Errorf("Expected code %v, got %v", codes.Unimplemented, code)
+	}
+}
+
+func TestGRPCServer_QueryService_GetModels(t *testing.T) {
+	_, _, client, mockRepo := setupTestServer(t)
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		mustCreateAPIRequest("session1", now.Add(-6*time.Hour), "claude-3-haiku-20240307", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.10), 1000),
+		mustCreateAPIRequest("session1", now.Add(-2*time.Hour), "claude-3-sonnet-20240229", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.50), 1000),
+		mustCreateAPIRequest("session2", now.Add(-time.Hour), "claude-3-sonnet-20240229", entity.NewToken(300, 100, 0, 0), entity.NewCost(0.70), 1000),
+	}
+	for _, req := range requests {
+		if err := mockRepo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	resp, err := client.GetModels(context.Background(), &pb.GetModelsRequest{})
+	if err != nil {
+		t.Fatalf("GetModels failed: %v", err)
+	}
+
+	if len(resp.Models) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(resp.Models))
+	}
+	sonnet := resp.Models[0]
+	if sonnet.Name != "claude-3-sonnet-20240229" || sonnet.RequestCount != 2 {
+		t.Errorf("Expected claude-3-sonnet-20240229 with 2 requests first, got %s with %d", sonnet.Name, sonnet.RequestCount)
+	}
+	if !sonnet.FirstSeen.AsTime().Equal(now.Add(-2*time.Hour)) || !sonnet.LastSeen.AsTime().Equal(now.Add(-time.Hour)) {
+		t.Errorf("Expected sonnet seen %v - %v, got %v - %v", now.Add(-2*time.Hour), now.Add(-time.Hour),
+			sonnet.FirstSeen.AsTime(), sonnet.LastSeen.AsTime())
+	}
+	if haiku := resp.Models[1]; haiku.Name != "claude-3-haiku-20240307" || haiku.RequestCount != 1 {
+		t.Errorf("Expected claude-3-haiku-20240307 with 1 request second, got %s with %d", haiku.Name, haiku.RequestCount)
+	}
+}
+
+func TestGRPCServer_QueryService_GetModelsUnavailable(t *testing.T) {
+	queryService := query.NewService(nil, nil, nil, nil, nil)
+
+	_, err := queryService.GetModels(context.Background(), &pb.GetModelsRequest{})
+	if code := status.Code(err); code != codes.Unimplemented {
+		t.Errorf("Expected code %v, got %v", codes.Unimplemented, code)
+	}
+}
+
 func TestGRPCServer_QueryService_GetAPIRequests(t *testing.T) {
 	_, _, client, mockRepo := setupTestServer(t)
 
@@ -291,3 +422,404 @@ func TestGRPCServer_ServiceRegistration(t *testing.T) {
 		t.Error("MetricsService not registered")
 	}
 }
+
+func TestGRPCServer_ReadOnlyServiceRegistration(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	appendCommand := usecase.NewAppendApiRequestCommand(mockRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(mockRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), &service.NoOpStatsCache{})
+
+	grpcServer := grpc.NewServer()
+	registerServices(grpcServer, receiver.NewReceiver(nil, nil, appendCommand), query.NewService(getFilteredQuery, calculateStatsQuery, nil, nil, nil), true)
+
+	services := grpcServer.GetServiceInfo()
+
+	// Query service is the only service exposed in read-only mode
+	if _, exists := services["ccmon.v1.QueryService"]; !exists {
+		t.Error("QueryService not registered")
+	}
+
+	otlpServices := []string{
+		"opentelemetry.proto.collector.logs.v1.LogsService",
+		"opentelemetry.proto.collector.trace.v1.TraceService",
+		"opentelemetry.proto.collector.metrics.v1.MetricsService",
+	}
+	for _, name := range otlpServices {
+		if _, exists := services[name]; exists {
+			t.Errorf("%s should not be registered in read-only mode", name)
+		}
+	}
+}
+
+func TestGRPCServer_ListenerServiceRegistration(t *testing.T) {
+	appendCommand := usecase.NewAppendApiRequestCommand(testutil.NewMockAPIRequestRepository())
+
+	grpcServer := grpc.NewServer()
+	registerReceiverServices(grpcServer, receiver.NewReceiver(nil, nil, appendCommand))
+
+	services := grpcServer.GetServiceInfo()
+
+	// Extra listeners only receive OTLP, queries stay on the main address
+	if _, exists := services["ccmon.v1.QueryService"]; exists {
+		t.Error("QueryService should not be registered on a listener")
+	}
+
+	otlpServices := []string{
+		"opentelemetry.proto.collector.logs.v1.LogsService",
+		"opentelemetry.proto.collector.trace.v1.TraceService",
+		"opentelemetry.proto.collector.metrics.v1.MetricsService",
+	}
+	for _, name := range otlpServices {
+		if _, exists := services[name]; !exists {
+			t.Errorf("%s not registered", name)
+		}
+	}
+}
+
+func TestGRPCServer_QueryService_DeleteByPeriod(t *testing.T) {
+	_, _, client, mockRepo := setupTestServer(t)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		req := mustCreateAPIRequest(
+			"session1", base.Add(time.Duration(i)*time.Hour),
+			"claude-3-5-sonnet-20241022",
+			entity.NewToken(100, 50, 0, 0),
+			entity.NewCost(0.01),
+			1000,
+		)
+		if err := mockRepo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	startTime := timestamppb.New(base.Add(time.Hour))
+	endTime := timestamppb.New(base.Add(2 * time.Hour))
+
+	// Both boundaries are required
+	_, err := client.DeleteByPeriod(ctx, &pb.DeleteByPeriodRequest{StartTime: startTime})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without end_time, got %v", err)
+	}
+
+	// Preview returns the matched count and confirmation token
+	preview, err := client.DeleteByPeriod(ctx, &pb.DeleteByPeriodRequest{StartTime: startTime, EndTime: endTime})
+	if err != nil {
+		t.Fatalf("DeleteByPeriod preview failed: %v", err)
+	}
+	if preview.MatchedCount != 2 || preview.DeletedCount != 0 {
+		t.Errorf("Expected 2 matched and 0 deleted on preview, got %d matched and %d deleted", preview.MatchedCount, preview.DeletedCount)
+	}
+
+	// Wrong confirmation token is rejected
+	_, err = client.DeleteByPeriod(ctx, &pb.DeleteByPeriodRequest{StartTime: startTime, EndTime: endTime, ConfirmationToken: "invalid"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for invalid token, got %v", err)
+	}
+
+	// Confirm the deletion
+	resp, err := client.DeleteByPeriod(ctx, &pb.DeleteByPeriodRequest{StartTime: startTime, EndTime: endTime, ConfirmationToken: preview.ConfirmationToken})
+	if err != nil {
+		t.Fatalf("DeleteByPeriod confirm failed: %v", err)
+	}
+	if resp.DeletedCount != 2 {
+		t.Errorf("Expected 2 deleted, got %d", resp.DeletedCount)
+	}
+
+	remaining, err := mockRepo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("Expected 2 remaining records, got %d", len(remaining))
+	}
+}
+
+func TestGRPCServer_QueryService_HealthCheck(t *testing.T) {
+	_, _, client, mockRepo := setupTestServer(t)
+
+	realRequest := mustCreateAPIRequest(
+		"session1", time.Now().UTC(),
+		"claude-3-5-sonnet-20241022",
+		entity.NewToken(100, 50, 0, 0),
+		entity.NewCost(0.01),
+		1000,
+	)
+	if err := mockRepo.Save(realRequest); err != nil {
+		t.Fatalf("Failed to save request: %v", err)
+	}
+
+	// The synthetic record goes through the real OTLP log parsing
+	resp, err := client.HealthCheck(context.Background(), &pb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("HealthCheck failed: %v", err)
+	}
+	if resp.IngestMicros < 0 || resp.VerifyMicros < 0 || resp.CleanupMicros < 0 {
+		t.Errorf("Expected non-negative phase durations, got %+v", resp)
+	}
+
+	remaining, err := mockRepo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].SessionID() != "session1" {
+		t.Errorf("Expected only the real request to remain, got %d records", len(remaining))
+	}
+}
+
+func TestGRPCServer_QueryService_HealthCheckUnavailable(t *testing.T) {
+	service := query.NewService(nil, nil, nil, nil, nil)
+
+	_, err := service.HealthCheck(context.Background(), &pb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented without a health check command, got %v", err)
+	}
+}
+
+func TestGRPCServer_QueryService_SetNote(t *testing.T) {
+	_, _, client, mockRepo := setupTestServer(t)
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	if err := mockRepo.Save(mustCreateAPIRequest(
+		"session1", timestamp,
+		"claude-3-opus-20240229",
+		entity.NewToken(1000, 500, 0, 0),
+		entity.NewCost(1.5),
+		1000,
+	)); err != nil {
+		t.Fatalf("Failed to save request: %v", err)
+	}
+
+	_, err := client.SetNote(context.Background(), &pb.SetNoteRequest{
+		SessionId: "session1",
+		Timestamp: timestamppb.New(timestamp),
+		Note:      "the big refactor run",
+	})
+	if err != nil {
+		t.Fatalf("SetNote failed: %v", err)
+	}
+
+	// The note is returned with the request
+	resp, err := client.GetAPIRequests(context.Background(), &pb.GetAPIRequestsRequest{})
+	if err != nil {
+		t.Fatalf("GetAPIRequests failed: %v", err)
+	}
+	if len(resp.Requests) != 1 || resp.Requests[0].Note != "the big refactor run" {
+		t.Errorf("Expected the note on the returned request, got %+v", resp.Requests)
+	}
+
+	_, err = client.SetNote(context.Background(), &pb.SetNoteRequest{
+		SessionId: "session2",
+		Timestamp: timestamppb.New(timestamp),
+		Note:      "missing",
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown request, got %v", err)
+	}
+
+	_, err = client.SetNote(context.Background(), &pb.SetNoteRequest{SessionId: "session1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without a timestamp, got %v", err)
+	}
+}
+
+func TestGRPCServer_QueryService_SetNoteUnavailable(t *testing.T) {
+	service := query.NewService(nil, nil, nil, nil, nil)
+
+	_, err := service.SetNote(context.Background(), &pb.SetNoteRequest{
+		SessionId: "session1",
+		Timestamp: timestamppb.Now(),
+		Note:      "note",
+	})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented without a set note command, got %v", err)
+	}
+}
+
+func TestGRPCServer_QueryService_Backup(t *testing.T) {
+	// Larger than a single chunk so the snapshot spans several stream messages
+	snapshot := make([]byte, 600*1024)
+	for i := range snapshot {
+		snapshot[i] = byte(i % 251)
+	}
+
+	tests := []struct {
+		name         string
+		configure    bool
+		err          error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "streams the snapshot with the record count",
+			configure:    true,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "snapshot failure is internal",
+			configure:    true,
+			err:          &testutil.MockError{Message: "disk read failed"},
+			expectedCode: codes.Internal,
+		},
+		{
+			name:         "backup unavailable without a snapshot source",
+			expectedCode: codes.Unimplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lis := bufconn.Listen(1024 * 1024)
+			queryService := query.NewService(nil, nil, nil, nil, nil)
+			if tt.configure {
+				snapshotRepo := testutil.NewMockSnapshotRepository(snapshot, 12)
+				if tt.err != nil {
+					snapshotRepo.SetError(tt.err)
+				}
+				queryService.SetBackupQuery(usecase.NewCreateBackupQuery(snapshotRepo))
+			}
+
+			grpcServer := grpc.NewServer()
+			pb.RegisterQueryServiceServer(grpcServer, queryService)
+			go func() {
+				_ = grpcServer.Serve(lis)
+			}()
+			defer grpcServer.Stop()
+
+			resolver.SetDefaultScheme("passthrough")
+			conn, err := grpc.NewClient("bufnet",
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+					return lis.Dial()
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create client connection: %v", err)
+			}
+			defer func() { _ = conn.Close() }()
+
+			stream, err := pb.NewQueryServiceClient(conn).Backup(context.Background(), &pb.BackupRequest{})
+			if err != nil {
+				t.Fatalf("Backup() error = %v", err)
+			}
+
+			var received []byte
+			var records int64
+			var chunks int
+			for {
+				chunk, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if code := status.Code(err); code != tt.expectedCode {
+						t.Fatalf("Expected code %v, got %v (err: %v)", tt.expectedCode, code, err)
+					}
+					return
+				}
+				chunks++
+				received = append(received, chunk.Data...)
+				records = chunk.RecordCount
+			}
+
+			if tt.expectedCode != codes.OK {
+				t.Fatalf("Expected code %v, stream completed", tt.expectedCode)
+			}
+			if !bytes.Equal(received, snapshot) {
+				t.Errorf("Received %d bytes, want the %d byte snapshot", len(received), len(snapshot))
+			}
+			if records != 12 {
+				t.Errorf("Final record count = %d, want 12", records)
+			}
+			if chunks < 3 {
+				t.Errorf("Expected the snapshot split over several chunks, got %d", chunks)
+			}
+		})
+	}
+}
+
+func TestGRPCServer_QueryService_StreamStats(t *testing.T) {
+	_, lis, client, mockRepo := setupTestServer(t)
+
+	now := time.Now()
+	if err := mockRepo.Save(mustCreateAPIRequest("session1", now.Add(-time.Hour), "claude-3-haiku-20240307", entity.NewToken(100, 50, 10, 5), entity.NewCost(0.15), 1000)); err != nil {
+		t.Fatalf("Failed to save request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamStats(ctx, &pb.StreamStatsRequest{WindowSeconds: int64((24 * time.Hour).Seconds())})
+	if err != nil {
+		t.Fatalf("StreamStats() error = %v", err)
+	}
+
+	initial, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected the initial update, got error: %v", err)
+	}
+	if initial.Stats.TotalRequests != 1 {
+		t.Errorf("Expected 1 request in the initial update, got %d", initial.Stats.TotalRequests)
+	}
+	if initial.StartTime == nil || initial.EndTime.AsTime().Sub(initial.StartTime.AsTime()) != 24*time.Hour {
+		t.Errorf("Expected the update to cover the 24h window, got %v - %v", initial.StartTime, initial.EndTime)
+	}
+
+	// Exporting a request through the OTLP receiver pushes an update before the 10s interval
+	conn, err := grpc.NewClient("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client connection: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	received := mustCreateAPIRequest("session2", now, "claude-3-sonnet-20240229", entity.NewToken(200, 100, 20, 10), entity.NewCost(0.70), 1500)
+	if _, err := logsv1.NewLogsServiceClient(conn).Export(ctx, service.NewAPIRequestLogs(received)); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected an update after new data, got error: %v", err)
+	}
+	if update.Stats.TotalRequests != 2 {
+		t.Errorf("Expected 2 requests after new data, got %d", update.Stats.TotalRequests)
+	}
+}
+
+func TestGRPCServer_QueryService_StreamStatsInvalidRequest(t *testing.T) {
+	_, _, client, _ := setupTestServer(t)
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		req  *pb.StreamStatsRequest
+	}{
+		{
+			name: "negative window",
+			req:  &pb.StreamStatsRequest{WindowSeconds: -60},
+		},
+		{
+			name: "end before start",
+			req:  &pb.StreamStatsRequest{StartTime: timestamppb.New(now), EndTime: timestamppb.New(now.Add(-time.Hour))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.StreamStats(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("StreamStats() error = %v", err)
+			}
+
+			_, err = stream.Recv()
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
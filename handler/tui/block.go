@@ -50,6 +50,17 @@ func parseBlockTime(timeStr string) (int, error) {
 	return hour, nil
 }
 
+// NewCurrentBlock creates the block containing now from a block start time like "5am" or "11pm"
+func NewCurrentBlock(blockTime string, timezone *time.Location, now time.Time, tokenLimit int) (entity.Block, error) {
+	startHour, err := parseBlockTime(blockTime)
+	if err != nil {
+		return entity.Block{}, fmt.Errorf("invalid block time format %s: %w", blockTime, err)
+	}
+
+	// Create current block with token limit based on user's start hour
+	return calculateCurrentBlock(startHour, timezone, now, tokenLimit), nil
+}
+
 // calculateCurrentBlock calculates the current 5-hour block based on user's start hour and timezone
 // Always returns a valid block - either the current block or the next upcoming block.
 func calculateCurrentBlock(userStartHour int, timezone *time.Location, now time.Time, tokenLimit int) entity.Block {
@@ -84,3 +95,9 @@ func calculateCurrentBlock(userStartHour int, timezone *time.Location, now time.
 
 	return entity.NewBlockWithLimit(blockStart.UTC(), tokenLimit)
 }
+
+// NewInferredBlock creates the block containing now, anchored at the hour of the first request of the day.
+// Without a request yet, the block starts at the current hour as the next request would start it.
+func NewInferredBlock(firstRequestAt time.Time, timezone *time.Location, now time.Time, tokenLimit int) entity.Block {
+	return entity.InferBlock(firstRequestAt, timezone, now, tokenLimit)
+}
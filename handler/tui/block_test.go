@@ -252,3 +252,62 @@ func TestCalculateCurrentBlock_TimezoneHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestNewInferredBlock(t *testing.T) {
+	t.Parallel()
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		firstRequestAt time.Time
+		timezone       *time.Location
+		now            time.Time
+		wantStart      time.Time
+	}{
+		{
+			name:           "first request rounded down to the hour",
+			firstRequestAt: time.Date(2025, 1, 1, 9, 40, 0, 0, time.UTC),
+			timezone:       time.UTC,
+			now:            time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+			wantStart:      time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "advanced to the block containing now",
+			firstRequestAt: time.Date(2025, 1, 1, 9, 40, 0, 0, time.UTC),
+			timezone:       time.UTC,
+			now:            time.Date(2025, 1, 1, 15, 30, 0, 0, time.UTC),
+			wantStart:      time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "no request yet starts at the current hour",
+			timezone:  time.UTC,
+			now:       time.Date(2025, 1, 1, 12, 25, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "half hour offset rounds in the display timezone",
+			firstRequestAt: time.Date(2025, 1, 1, 4, 10, 0, 0, time.UTC), // 9:40am IST
+			timezone:       kolkata,
+			now:            time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC),
+			wantStart:      time.Date(2025, 1, 1, 3, 30, 0, 0, time.UTC), // 9am IST
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			block := NewInferredBlock(tt.firstRequestAt, tt.timezone, tt.now, 7000)
+			if !block.StartAt().Equal(tt.wantStart) {
+				t.Errorf("NewInferredBlock() start = %v, want %v", block.StartAt(), tt.wantStart)
+			}
+			if block.TokenLimit() != 7000 {
+				t.Errorf("NewInferredBlock() token limit = %d, want 7000", block.TokenLimit())
+			}
+		})
+	}
+}
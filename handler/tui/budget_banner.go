@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Banner styles, a warning below 100% and an alert once the budget is used up
+var (
+	BannerWarningStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("0")).
+				Background(lipgloss.Color("214")).
+				Padding(0, 1)
+
+	BannerAlertStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("231")).
+				Background(lipgloss.Color("196")).
+				Padding(0, 1)
+)
+
+// BudgetBannerModel warns across the top of the monitor once the daily or monthly plan usage
+// crosses a threshold. A dismissed banner comes back when a higher threshold is crossed.
+type BudgetBannerModel struct {
+	// Data ownership
+	usage   usecase.PlanUsage
+	daily   int // Highest threshold crossed by the daily usage, 0 when none
+	monthly int // Highest threshold crossed by the monthly usage, 0 when none
+
+	// Thresholds hidden by dismissing, lowered again when the usage drops, e.g. on a new day
+	dismissedDaily   int
+	dismissedMonthly int
+
+	// Configuration
+	thresholds  []int         // Ascending percentages
+	autoDismiss time.Duration // Hide the banner after it was shown this long, 0 keeps it until dismissed
+	width       int
+
+	// Auto dismiss timer of the banner currently shown, newer timers replace older ones
+	timerID int
+
+	// Business logic dependencies
+	planUsageQuery *usecase.GetPlanUsageQuery
+}
+
+// NewBudgetBannerModel creates a budget banner model; no thresholds or a nil query disables it
+func NewBudgetBannerModel(planUsageQuery *usecase.GetPlanUsageQuery, thresholds []int, autoDismiss time.Duration) *BudgetBannerModel {
+	sorted := slices.Clone(thresholds)
+	slices.Sort(sorted)
+	return &BudgetBannerModel{
+		thresholds:     sorted,
+		autoDismiss:    autoDismiss,
+		planUsageQuery: planUsageQuery,
+	}
+}
+
+// Init initializes the budget banner model
+func (m *BudgetBannerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the budget banner model
+func (m *BudgetBannerModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case BudgetBannerRefreshMsg:
+		return m, m.refreshUsage()
+	case BudgetBannerDataMsg:
+		// Keep the last banner when the usage could not be read
+		if msg.Usage == nil {
+			return m, nil
+		}
+		wasVisible := m.Visible()
+		previousDaily, previousMonthly := m.daily, m.monthly
+		m.setUsage(*msg.Usage)
+		if m.Visible() && (!wasVisible || m.daily > previousDaily || m.monthly > previousMonthly) {
+			return m, m.startTimer()
+		}
+	case budgetBannerExpiredMsg:
+		if msg.id == m.timerID {
+			m.Dismiss()
+		}
+	case ResizeMsg:
+		m.width = msg.Width
+	}
+	return m, nil
+}
+
+// View renders the banner across the terminal width, empty while nothing new is crossed
+func (m *BudgetBannerModel) View() string {
+	if !m.Visible() {
+		return ""
+	}
+
+	var parts []string
+	if m.daily > 0 {
+		parts = append(parts, fmt.Sprintf("Daily plan usage %s (past %d%%)", formatPlanUsage(m.usage.Daily, m.usage.DailyPercent), m.daily))
+	}
+	if m.monthly > 0 {
+		parts = append(parts, fmt.Sprintf("Monthly plan usage %s (past %d%%)", formatPlanUsage(m.usage.Monthly, m.usage.MonthlyPercent), m.monthly))
+	}
+	text := "⚠ " + strings.Join(parts, " • ") + " — x: dismiss"
+
+	style := BannerWarningStyle
+	if m.usage.Daily >= 100 || m.usage.Monthly >= 100 {
+		style = BannerAlertStyle
+	}
+	if m.width > 0 {
+		style = style.Width(m.width)
+	}
+	return style.Render(text)
+}
+
+// Enabled returns whether the banner has thresholds and a query to check them with
+func (m *BudgetBannerModel) Enabled() bool {
+	return len(m.thresholds) > 0 && m.planUsageQuery != nil
+}
+
+// Visible returns true if the daily or monthly usage crossed a threshold that was not dismissed
+func (m *BudgetBannerModel) Visible() bool {
+	return m.daily > m.dismissedDaily || m.monthly > m.dismissedMonthly
+}
+
+// Dismiss hides the banner until a higher threshold is crossed
+func (m *BudgetBannerModel) Dismiss() {
+	m.dismissedDaily = m.daily
+	m.dismissedMonthly = m.monthly
+}
+
+// setUsage records the usage and the highest threshold each crossed
+func (m *BudgetBannerModel) setUsage(usage usecase.PlanUsage) {
+	m.usage = usage
+	m.daily, m.monthly = 0, 0
+	if usage.IsPlanSet() {
+		m.daily = m.crossedThreshold(usage.Daily)
+		m.monthly = m.crossedThreshold(usage.Monthly)
+	}
+
+	// A dropped usage, e.g. on a new day or month, can warn again
+	m.dismissedDaily = min(m.dismissedDaily, m.daily)
+	m.dismissedMonthly = min(m.dismissedMonthly, m.monthly)
+}
+
+// crossedThreshold returns the highest threshold at or below the usage, 0 when none is crossed
+func (m *BudgetBannerModel) crossedThreshold(usage int) int {
+	crossed := 0
+	for _, threshold := range m.thresholds {
+		if usage >= threshold {
+			crossed = threshold
+		}
+	}
+	return crossed
+}
+
+// formatPlanUsage renders a plan usage as a whole percent, or with the configured decimals of the unrounded percent
+func formatPlanUsage(whole int, percent float64) string {
+	if percentDecimals <= 0 {
+		return fmt.Sprintf("%d%%", whole)
+	}
+	return numberLocale.FormatPercent(percent, percentDecimals)
+}
+
+// startTimer schedules the auto dismiss of the banner shown now, replacing an earlier timer
+func (m *BudgetBannerModel) startTimer() tea.Cmd {
+	if m.autoDismiss <= 0 {
+		return nil
+	}
+
+	m.timerID++
+	id := m.timerID
+	return tea.Tick(m.autoDismiss, func(time.Time) tea.Msg {
+		return budgetBannerExpiredMsg{id: id}
+	})
+}
+
+// refreshUsage handles data fetching for the budget banner model
+func (m *BudgetBannerModel) refreshUsage() tea.Cmd {
+	if !m.Enabled() {
+		return nil
+	}
+
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		usage, err := m.planUsageQuery.Execute(ctx)
+		if err != nil {
+			return BudgetBannerDataMsg{}
+		}
+		return BudgetBannerDataMsg{Usage: usage}
+	})
+}
+
+// Message types for BudgetBannerModel
+type BudgetBannerRefreshMsg struct{}
+
+type BudgetBannerDataMsg struct {
+	Usage *usecase.PlanUsage // nil when the usage could not be read
+}
+
+type budgetBannerExpiredMsg struct {
+	id int
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestBudgetBannerModel_Dismiss(t *testing.T) {
+	plan := entity.NewPlan("pro", entity.NewCost(20))
+
+	steps := []struct {
+		name          string
+		daily         int
+		monthly       int
+		dismiss       bool
+		expectVisible bool
+		expectText    string
+	}{
+		{
+			name:          "below every threshold",
+			daily:         50,
+			monthly:       40,
+			expectVisible: false,
+		},
+		{
+			name:          "daily usage crosses the first threshold",
+			daily:         95,
+			monthly:       40,
+			expectVisible: true,
+			expectText:    "Daily plan usage 95% (past 90%)",
+		},
+		{
+			name:          "dismissed",
+			daily:         95,
+			monthly:       40,
+			dismiss:       true,
+			expectVisible: false,
+		},
+		{
+			name:          "same threshold stays dismissed",
+			daily:         99,
+			monthly:       40,
+			expectVisible: false,
+		},
+		{
+			name:          "higher threshold shows it again",
+			daily:         120,
+			monthly:       40,
+			expectVisible: true,
+			expectText:    "Daily plan usage 120% (past 100%)",
+		},
+		{
+			name:          "dismissed after monthly usage crossed",
+			daily:         120,
+			monthly:       91,
+			dismiss:       true,
+			expectVisible: false,
+		},
+		{
+			name:          "new day lowers the dismissed threshold",
+			daily:         0,
+			monthly:       91,
+			expectVisible: false,
+		},
+		{
+			name:          "crossed again on the new day",
+			daily:         92,
+			monthly:       91,
+			expectVisible: true,
+			expectText:    "Daily plan usage 92% (past 90%) • Monthly plan usage 91% (past 90%)",
+		},
+	}
+
+	banner := NewBudgetBannerModel(nil, []int{100, 90}, 0)
+	for _, step := range steps {
+		banner.Update(BudgetBannerDataMsg{Usage: &usecase.PlanUsage{Plan: plan, Daily: step.daily, Monthly: step.monthly}})
+		if step.dismiss {
+			banner.Dismiss()
+		}
+
+		if banner.Visible() != step.expectVisible {
+			t.Fatalf("%s: expected visible %v, got %v", step.name, step.expectVisible, banner.Visible())
+		}
+		if step.expectText != "" && !strings.Contains(banner.View(), step.expectText) {
+			t.Fatalf("%s: expected %q in %q", step.name, step.expectText, banner.View())
+		}
+	}
+}
+
+func TestBudgetBannerModel_UnsetPlan(t *testing.T) {
+	banner := NewBudgetBannerModel(nil, []int{90}, 0)
+	banner.Update(BudgetBannerDataMsg{Usage: &usecase.PlanUsage{Plan: entity.NewPlan("unset", entity.NewCost(0)), Daily: 500}})
+
+	if banner.Visible() {
+		t.Error("expected no banner without a priced plan")
+	}
+}
+
+func TestBudgetBannerModel_PercentDecimals(t *testing.T) {
+	// $31.1 of a $20 plan this month
+	usage := &usecase.PlanUsage{Plan: entity.NewPlan("pro", entity.NewCost(20)), Monthly: 155, MonthlyPercent: 155.5}
+
+	tests := []struct {
+		name       string
+		decimals   int
+		expectText string
+	}{
+		{
+			name:       "whole percent by default",
+			decimals:   0,
+			expectText: "Monthly plan usage 155% (past 100%)",
+		},
+		{
+			name:       "one decimal",
+			decimals:   1,
+			expectText: "Monthly plan usage 155.5% (past 100%)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPercentDecimals(tt.decimals)
+			defer SetPercentDecimals(0)
+
+			banner := NewBudgetBannerModel(nil, []int{100}, 0)
+			banner.Update(BudgetBannerDataMsg{Usage: usage})
+
+			if view := banner.View(); !strings.Contains(view, tt.expectText) {
+				t.Errorf("expected %q in %q", tt.expectText, view)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,6 +26,7 @@ type DailyUsageTabModel struct {
 
 	// Display mode configuration
 	displayMode DailyDisplayMode
+	sortOrder   SortOrder // Day order, newest first by default
 
 	// Business logic dependencies
 	getUsageQuery *usecase.GetUsageQuery
@@ -76,6 +78,7 @@ func NewDailyUsageTabModel(getUsageQuery *usecase.GetUsageQuery, timezone *time.
 		width:         120,
 		height:        30,
 		displayMode:   FullMode,
+		sortOrder:     SortDescending,
 		getUsageQuery: getUsageQuery,
 	}
 }
@@ -109,7 +112,7 @@ func (m *DailyUsageTabModel) View() string {
 	var b strings.Builder
 
 	// Daily usage header
-	dailyHeader := HeaderStyle.Render("Daily Usage Statistics (Last 30 Days)")
+	dailyHeader := HeaderStyle.Render("Daily Usage Statistics (Last 30 Days, " + m.SortOrderString() + ")")
 	b.WriteString(dailyHeader + "\n")
 
 	// Subtitle explaining premium token focus
@@ -143,6 +146,29 @@ func (m *DailyUsageTabModel) SetSize(width, height int) {
 	m.adjustTableHeight()
 }
 
+// SetSortOrder changes the day order and re-renders rows
+func (m *DailyUsageTabModel) SetSortOrder(order SortOrder) {
+	m.sortOrder = order
+	m.updateTableRows()
+}
+
+// ToggleSortOrder switches between newest and oldest days first
+func (m *DailyUsageTabModel) ToggleSortOrder() {
+	if m.sortOrder == SortDescending {
+		m.SetSortOrder(SortAscending)
+	} else {
+		m.SetSortOrder(SortDescending)
+	}
+}
+
+// SortOrderString returns the day order for display
+func (m *DailyUsageTabModel) SortOrderString() string {
+	if m.sortOrder == SortAscending {
+		return "Oldest First"
+	}
+	return "Newest First"
+}
+
 // UpdateUsage updates the usage data
 func (m *DailyUsageTabModel) UpdateUsage(usage entity.Usage) {
 	m.usage = usage
@@ -249,7 +275,7 @@ func (m *DailyUsageTabModel) refreshUsage() tea.Cmd {
 			usage = entity.Usage{}
 		}
 
-		return UsageDataMsg{Usage: usage}
+		return UsageDataMsg{Usage: usage, Err: err}
 	})
 }
 
@@ -366,7 +392,12 @@ func (m *DailyUsageTabModel) resizeTableColumns() {
 
 // updateTableRows updates the table rows based on current usage data
 func (m *DailyUsageTabModel) updateTableRows() {
+	// Usage lists today first, so oldest first walks it backwards
 	stats := m.usage.GetStats()
+	if m.sortOrder == SortAscending {
+		stats = slices.Clone(stats)
+		slices.Reverse(stats)
+	}
 	rows := make([]table.Row, 0, len(stats)*2) // Pre-allocate for potential sub-rows
 
 	for _, stat := range stats {
@@ -394,14 +425,14 @@ func (m *DailyUsageTabModel) createRowsForStat(stat entity.Stats, date string) [
 		creationCache := FormatTokenCount(stat.PremiumTokens().CacheCreation())
 		total := FormatTokenCount(stat.PremiumTokens().Total())
 		burnRate := FormatBurnRate(stat.PremiumTokenBurnRate())
-		cost := fmt.Sprintf("%.6f", stat.PremiumCost().Amount())
+		cost := formatDecimal(stat.PremiumCost().Amount(), 6)
 		return []table.Row{{date, requests, input, output, readCache, creationCache, total, burnRate, cost}}
 
 	case GroupedMode:
 		// 4 main columns with token details in sub-rows
 		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.PremiumRequests())
 		burnRate := FormatBurnRate(stat.PremiumTokenBurnRate())
-		cost := fmt.Sprintf("%.4f", stat.PremiumCost().Amount())
+		cost := formatDecimal(stat.PremiumCost().Amount(), 4)
 
 		// Main row
 		mainRow := table.Row{date, requests, burnRate, cost}
@@ -425,7 +456,7 @@ func (m *DailyUsageTabModel) createRowsForStat(stat entity.Stats, date string) [
 		// 4 simplified columns
 		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.PremiumRequests())
 		burnRate := FormatBurnRate(stat.PremiumTokenBurnRate())
-		cost := fmt.Sprintf("%.3f", stat.PremiumCost().Amount())
+		cost := formatDecimal(stat.PremiumCost().Amount(), 3)
 		return []table.Row{{date, requests, burnRate, cost}}
 
 	default:
@@ -441,4 +472,5 @@ type UsageRefreshMsg struct{}
 
 type UsageDataMsg struct {
 	Usage entity.Usage
+	Err   error // Failure of the usage query, if any
 }
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/handler/tui"
 	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
@@ -73,7 +74,7 @@ func TestDailyUsageTab_IntegrationWithViewModel(t *testing.T) {
 			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory) // Use same repo for consistency
 
 			// Create the ViewModel
-			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 			// Create teatest model
 			tm := teatest.NewTestModel(
@@ -138,7 +139,7 @@ func TestDailyUsageTab_NavigationFlow(t *testing.T) {
 		periodFactory := service.NewTimePeriodFactory(time.UTC)
 		getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
-		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 		tm := teatest.NewTestModel(
 			t, model,
@@ -208,7 +209,7 @@ func TestDailyUsageTab_FocusManagement(t *testing.T) {
 		periodFactory := service.NewTimePeriodFactory(time.UTC)
 		getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
-		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 		tm := teatest.NewTestModel(
 			t, model,
@@ -285,7 +286,7 @@ func TestDailyUsageTab_KeyboardNavigation(t *testing.T) {
 		periodFactory := service.NewTimePeriodFactory(time.UTC)
 		getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
-		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 		tm := teatest.NewTestModel(
 			t, model,
@@ -334,3 +335,58 @@ func TestDailyUsageTab_KeyboardNavigation(t *testing.T) {
 		tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
 	})
 }
+
+// TestDailyUsageTab_SortOrder tests reversing the day order of the daily usage table
+func TestDailyUsageTab_SortOrder(t *testing.T) {
+	t.Parallel()
+
+	day := func(date time.Time) entity.Stats {
+		return entity.NewStats(0, 1,
+			entity.NewToken(0, 0, 0, 0), entity.NewToken(100, 50, 0, 0),
+			entity.NewCost(0), entity.NewCost(0.1),
+			entity.NewPeriod(date, date.Add(24*time.Hour-time.Nanosecond)))
+	}
+	// Usage lists today first
+	usage := entity.NewUsage([]entity.Stats{
+		day(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)),
+		day(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)),
+		day(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+
+	tests := []struct {
+		name       string
+		toggles    int
+		wantOrder  []string
+		wantHeader string
+	}{
+		{name: "newest first by default", wantOrder: []string{"2025-01-03", "2025-01-02", "2025-01-01"}, wantHeader: "Newest First"},
+		{name: "toggled to oldest first", toggles: 1, wantOrder: []string{"2025-01-01", "2025-01-02", "2025-01-03"}, wantHeader: "Oldest First"},
+		{name: "toggled back", toggles: 2, wantOrder: []string{"2025-01-03", "2025-01-02", "2025-01-01"}, wantHeader: "Newest First"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := tui.NewDailyUsageTabModel(nil, time.UTC)
+			model.SetSize(160, 40)
+			model.UpdateUsage(usage)
+			for i := 0; i < tt.toggles; i++ {
+				model.ToggleSortOrder()
+			}
+
+			view := model.View()
+			if !strings.Contains(view, tt.wantHeader) {
+				t.Errorf("expected header to mention %q, got:\n%s", tt.wantHeader, view)
+			}
+			last := -1
+			for _, date := range tt.wantOrder {
+				index := strings.Index(view, date)
+				if index <= last {
+					t.Fatalf("expected %s after position %d, got %d in:\n%s", date, last, index, view)
+				}
+				last = index
+			}
+		})
+	}
+}
@@ -35,6 +35,16 @@ var (
 	HelpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241"))
 
+	ErrorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196"))
+
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("226"))
+
+	SoftLimitStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("226"))
+
 	BoxStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("240")).
@@ -60,11 +70,29 @@ func FormatNumber(n int64) string {
 	return fmt.Sprintf("%d", n)
 }
 
+// FormatCostPerThousandTokens formats the request's cost per 1K tokens, or "-" without tokens
+func FormatCostPerThousandTokens(req entity.APIRequest) string {
+	rate, ok := req.CostPerThousandTokens()
+	if !ok {
+		return "-"
+	}
+	return "$" + formatDecimal(rate.Amount(), 6)
+}
+
 func FormatCost(cost float64) string {
 	if cost == 0 {
 		return "-"
 	}
-	return fmt.Sprintf("%.6f", cost)
+	return formatDecimal(cost, 6)
+}
+
+// FormatCostShare renders a percentage of the period cost in whole percent, showing shares
+// that would round to zero as "<1%" so they stay apart from free requests
+func FormatCostShare(share float64) string {
+	if share > 0 && share < 0.5 {
+		return "<1%"
+	}
+	return fmt.Sprintf("%.0f%%", share)
 }
 
 func FormatDuration(ms int64) string {
@@ -74,26 +102,46 @@ func FormatDuration(ms int64) string {
 	return fmt.Sprintf("%.1fs", float64(ms)/1000)
 }
 
+// FormatTokenCount abbreviates a token count, e.g. 1.5K or 1.50M with the default precision
 func FormatTokenCount(tokens int64) string {
-	if tokens < 1000 {
-		return fmt.Sprintf("%d", tokens)
-	} else if tokens < 1000000 {
-		return fmt.Sprintf("%.1fK", float64(tokens)/1000)
-	} else {
-		return fmt.Sprintf("%.2fM", float64(tokens)/1000000)
+	if tokenDecimals < 0 {
+		return numberLocale.FormatTokenCount(tokens, 1, 2)
 	}
+	return numberLocale.FormatTokenCount(tokens, tokenDecimals, tokenDecimals)
+}
+
+// tokenDecimals is the decimal places of abbreviated token counts, negative keeps 1 for K and 2 for M
+var tokenDecimals = -1
+
+// SetTokenDecimals sets the decimal places of abbreviated token counts, negative restores the default
+func SetTokenDecimals(decimals int) {
+	tokenDecimals = decimals
+}
+
+// percentDecimals is the decimal places of plan usage percentages, 0 shows whole percents
+var percentDecimals int
+
+// SetPercentDecimals sets the decimal places of plan usage percentages
+func SetPercentDecimals(decimals int) {
+	percentDecimals = decimals
+}
+
+// numberLocale is the decimal and grouping style used for costs and token counts, configured once at startup
+var numberLocale = entity.DefaultNumberLocale
+
+// SetNumberLocale sets the decimal and grouping separators used for costs and token counts
+func SetNumberLocale(locale entity.NumberLocale) {
+	numberLocale = locale
+}
+
+// formatDecimal renders a number with the given decimal places using the configured locale
+func formatDecimal(value float64, precision int) string {
+	return numberLocale.FormatFloat(value, precision)
 }
 
+// FormatDurationFromTime renders a duration in the default style, e.g. 2h 15m
 func FormatDurationFromTime(d time.Duration) string {
-	if d < time.Minute {
-		return fmt.Sprintf("%ds", int(d.Seconds()))
-	} else if d < time.Hour {
-		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
-	} else {
-		hours := int(d.Hours())
-		minutes := int(d.Minutes()) % 60
-		return fmt.Sprintf("%dh %dm", hours, minutes)
-	}
+	return entity.DurationStyleDefault.Format(d)
 }
 
 func FormatBurnRate(tokensPerMinute float64) string {
@@ -111,6 +159,129 @@ func FormatBurnRate(tokensPerMinute float64) string {
 }
 
 // Layout helper functions
+// StatsColumn identifies a configurable column of the stats table
+type StatsColumn string
+
+const (
+	StatsColumnRequests StatsColumn = "reqs"
+	StatsColumnLimited  StatsColumn = "limited"
+	StatsColumnCache    StatsColumn = "cache"
+	StatsColumnTotal    StatsColumn = "total"
+	StatsColumnCost     StatsColumn = "cost"
+	StatsColumnBurnRate StatsColumn = "burn_rate"
+)
+
+// DefaultStatsColumns is the stats table column order used when none is configured
+var DefaultStatsColumns = []StatsColumn{
+	StatsColumnRequests,
+	StatsColumnLimited,
+	StatsColumnCache,
+	StatsColumnTotal,
+	StatsColumnCost,
+	StatsColumnBurnRate,
+}
+
+// statsColumnSpec holds the header, minimum width and share of extra space of a stats column
+type statsColumnSpec struct {
+	header   string
+	minWidth int
+	share    float64
+}
+
+var statsColumnSpecs = map[StatsColumn]statsColumnSpec{
+	StatsColumnRequests: {header: "Reqs", minWidth: 5, share: 0.1},
+	StatsColumnLimited:  {header: "Limited", minWidth: 8, share: 0.15},
+	StatsColumnCache:    {header: "Cache", minWidth: 6, share: 0.1},
+	StatsColumnTotal:    {header: "Total", minWidth: 8, share: 0.15},
+	StatsColumnCost:     {header: "Cost ($)", minWidth: 10, share: 0.1},
+	StatsColumnBurnRate: {header: "Burn Rate", minWidth: 10, share: 0.15},
+}
+
+// ParseStatsColumns validates a configured column order; empty selects DefaultStatsColumns
+func ParseStatsColumns(names []string) ([]StatsColumn, error) {
+	if len(names) == 0 {
+		return DefaultStatsColumns, nil
+	}
+
+	columns := make([]StatsColumn, 0, len(names))
+	seen := make(map[StatsColumn]bool, len(names))
+	for _, name := range names {
+		column := StatsColumn(strings.ToLower(strings.TrimSpace(name)))
+		if _, ok := statsColumnSpecs[column]; !ok {
+			return nil, fmt.Errorf("unknown stats column %q (valid: reqs, limited, cache, total, cost, burn_rate)", name)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate stats column %q", name)
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+// ParseStatsAlign converts a configuration value into whether numeric stats columns are right-aligned
+func ParseStatsAlign(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "left":
+		return false, nil
+	case "right":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown stats alignment %q (valid: left, right)", value)
+	}
+}
+
+// ParseDailyOrder converts a configuration value into the day order of the Daily Usage tab
+func ParseDailyOrder(value string) (SortOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "newest":
+		return SortDescending, nil
+	case "oldest":
+		return SortAscending, nil
+	default:
+		return SortDescending, fmt.Errorf("unknown daily order %q (valid: newest, oldest)", value)
+	}
+}
+
+// MinRefreshInterval is the hard floor of the monitor refresh interval, protecting the server from query storms
+const MinRefreshInterval = time.Second
+
+// MaxRefreshInterval is the longest monitor refresh interval
+const MaxRefreshInterval = 5 * time.Minute
+
+// ProjectsRefreshInterval is how often the monitor detects the projects of the period again,
+// slower than the refresh interval as it reads every request of the period
+const ProjectsRefreshInterval = time.Minute
+
+// ClampRefreshInterval keeps an interval between the minimum, itself never below MinRefreshInterval,
+// and MaxRefreshInterval. It reports whether the interval was changed.
+func ClampRefreshInterval(interval, minimum time.Duration) (time.Duration, bool) {
+	if minimum < MinRefreshInterval {
+		minimum = MinRefreshInterval
+	}
+	if minimum > MaxRefreshInterval {
+		minimum = MaxRefreshInterval
+	}
+	switch {
+	case interval < minimum:
+		return minimum, true
+	case interval > MaxRefreshInterval:
+		return MaxRefreshInterval, true
+	default:
+		return interval, false
+	}
+}
+
+// PadLeft right-aligns s within width
+func PadLeft(s string, width int) string {
+	visualLen := lipgloss.Width(s)
+	if visualLen >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-visualLen) + s
+}
+
 func PadRight(s string, width int) string {
 	// Account for ANSI escape codes when calculating padding
 	visualLen := lipgloss.Width(s)
@@ -121,23 +292,37 @@ func PadRight(s string, width int) string {
 }
 
 func CalculateStatsColumnWidths(availableWidth int) []int {
-	// Base minimum widths for each column
-	minWidths := []int{12, 5, 8, 6, 8, 10, 10} // Model Tier, Reqs, Limited, Cache, Total, Cost, Burn Rate
+	return CalculateStatsColumnWidthsFor(availableWidth, DefaultStatsColumns)
+}
 
-	// Calculate total minimum width
+// CalculateStatsColumnWidthsFor returns the Model Tier width followed by one width per column
+func CalculateStatsColumnWidthsFor(availableWidth int, columns []StatsColumn) []int {
+	// Base minimum widths, Model Tier first
+	minWidths := []int{12}
+	distribution := []float64{0.25}
+	for _, column := range columns {
+		spec := statsColumnSpecs[column]
+		minWidths = append(minWidths, spec.minWidth)
+		distribution = append(distribution, spec.share)
+	}
+
+	// Calculate total minimum width and weight
 	totalMinWidth := 0
 	for _, w := range minWidths {
 		totalMinWidth += w
 	}
+	totalShare := 0.0
+	for _, share := range distribution {
+		totalShare += share
+	}
 
 	// If we have extra space, distribute it proportionally
+	// The default columns' shares add up to 1: favor first column and burn rate column
 	if availableWidth > totalMinWidth {
 		extraSpace := availableWidth - totalMinWidth
-		// Distribute extra space: favor first column and burn rate column
-		distribution := []float64{0.25, 0.1, 0.15, 0.1, 0.15, 0.1, 0.15}
 
 		for i := range minWidths {
-			extra := int(float64(extraSpace) * distribution[i])
+			extra := int(float64(extraSpace) * distribution[i] / totalShare)
 			minWidths[i] += extra
 		}
 	}
@@ -190,6 +375,91 @@ func FormatBlockTime(block entity.Block, timezone *time.Location) string {
 	return fmt.Sprintf("%s - %s", startStr, endStr)
 }
 
+// TimeDisplayMode selects which timezone timestamps and block times are shown in
+type TimeDisplayMode int
+
+const (
+	TimeDisplayLocal TimeDisplayMode = iota // Configured timezone only
+	TimeDisplayUTC                          // UTC only
+	TimeDisplayBoth                         // Configured timezone with UTC alongside
+)
+
+// Next returns the mode that follows m when cycling with the toggle key
+func (m TimeDisplayMode) Next() TimeDisplayMode {
+	switch m {
+	case TimeDisplayLocal:
+		return TimeDisplayUTC
+	case TimeDisplayUTC:
+		return TimeDisplayBoth
+	default:
+		return TimeDisplayLocal
+	}
+}
+
+// String returns the label shown in the status line
+func (m TimeDisplayMode) String() string {
+	switch m {
+	case TimeDisplayUTC:
+		return "UTC"
+	case TimeDisplayBoth:
+		return "Local+UTC"
+	default:
+		return "Local"
+	}
+}
+
+// CostDisplayMode selects whether the requests table shows each request's cost or the running total
+type CostDisplayMode int
+
+const (
+	CostDisplayRequest    CostDisplayMode = iota // Cost of each request
+	CostDisplayCumulative                        // Running total in timestamp order
+)
+
+// ParseCostDisplayMode converts a configuration value into the cost column mode of the requests table
+func ParseCostDisplayMode(value string) (CostDisplayMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "request":
+		return CostDisplayRequest, nil
+	case "cumulative":
+		return CostDisplayCumulative, nil
+	default:
+		return CostDisplayRequest, fmt.Errorf("unknown cost column %q (valid: request, cumulative)", value)
+	}
+}
+
+// Next returns the mode that follows m when toggling with the cost column key
+func (m CostDisplayMode) Next() CostDisplayMode {
+	if m == CostDisplayCumulative {
+		return CostDisplayRequest
+	}
+	return CostDisplayCumulative
+}
+
+// FormatTimestamp formats a request timestamp according to the display mode
+func FormatTimestamp(t time.Time, timezone *time.Location, mode TimeDisplayMode) string {
+	switch mode {
+	case TimeDisplayUTC:
+		return t.UTC().Format("15:04:05 2006-01-02") + " UTC"
+	case TimeDisplayBoth:
+		return t.In(timezone).Format("15:04:05 2006-01-02") + " (" + t.UTC().Format("15:04") + " UTC)"
+	default:
+		return t.In(timezone).Format("15:04:05 2006-01-02")
+	}
+}
+
+// FormatBlockTimeWithMode formats the block period according to the display mode
+func FormatBlockTimeWithMode(block entity.Block, timezone *time.Location, mode TimeDisplayMode) string {
+	switch mode {
+	case TimeDisplayUTC:
+		return FormatBlockTime(block, time.UTC) + " UTC"
+	case TimeDisplayBoth:
+		return FormatBlockTime(block, timezone) + " (" + FormatBlockTime(block, time.UTC) + " UTC)"
+	default:
+		return FormatBlockTime(block, timezone)
+	}
+}
+
 // formatHour formats hour (0-23) into 12-hour format with am/pm
 func formatHour(hour int) string {
 	if hour == 0 {
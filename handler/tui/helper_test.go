@@ -52,3 +52,334 @@ func TestFormatBlockTime(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatTimestamp(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	timestamp := time.Date(2025, 1, 1, 1, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		mode TimeDisplayMode
+		want string
+	}{
+		{name: "local", mode: TimeDisplayLocal, want: "10:30:15 2025-01-01"},
+		{name: "utc", mode: TimeDisplayUTC, want: "01:30:15 2025-01-01 UTC"},
+		{name: "both", mode: TimeDisplayBoth, want: "10:30:15 2025-01-01 (01:30 UTC)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatTimestamp(timestamp, tokyo, tt.mode); got != tt.want {
+				t.Errorf("FormatTimestamp() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBlockTimeWithMode(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	block := entity.NewBlock(time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)) // 10am in Tokyo
+
+	tests := []struct {
+		name string
+		mode TimeDisplayMode
+		want string
+	}{
+		{name: "local", mode: TimeDisplayLocal, want: "10am - 3pm"},
+		{name: "utc", mode: TimeDisplayUTC, want: "1am - 6am UTC"},
+		{name: "both", mode: TimeDisplayBoth, want: "10am - 3pm (1am - 6am UTC)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatBlockTimeWithMode(block, tokyo, tt.mode); got != tt.want {
+				t.Errorf("FormatBlockTimeWithMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeDisplayMode_Next(t *testing.T) {
+	t.Parallel()
+
+	mode := TimeDisplayLocal
+	want := []TimeDisplayMode{TimeDisplayUTC, TimeDisplayBoth, TimeDisplayLocal}
+	for _, expected := range want {
+		mode = mode.Next()
+		if mode != expected {
+			t.Fatalf("Next() = %v, want %v", mode, expected)
+		}
+	}
+}
+
+func TestParseStatsColumns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		names   []string
+		want    []StatsColumn
+		wantErr bool
+	}{
+		{name: "empty uses default", names: nil, want: DefaultStatsColumns},
+		{name: "custom order", names: []string{"cost", "Reqs", " total "}, want: []StatsColumn{StatsColumnCost, StatsColumnRequests, StatsColumnTotal}},
+		{name: "unknown column", names: []string{"cost", "latency"}, wantErr: true},
+		{name: "duplicate column", names: []string{"cost", "cost"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseStatsColumns(tt.names)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStatsColumns() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatsColumns() unexpected error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseStatsColumns() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseStatsColumns()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseStatsAlign(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{value: "", want: false},
+		{value: "left", want: false},
+		{value: "right", want: true},
+		{value: "center", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseStatsAlign(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStatsAlign() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatsAlign() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStatsAlign() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDailyOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    SortOrder
+		wantErr bool
+	}{
+		{value: "", want: SortDescending},
+		{value: "newest", want: SortDescending},
+		{value: "oldest", want: SortAscending},
+		{value: "ascending", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDailyOrder(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseDailyOrder() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDailyOrder() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDailyOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCostDisplayMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    CostDisplayMode
+		wantErr bool
+	}{
+		{value: "", want: CostDisplayRequest},
+		{value: "request", want: CostDisplayRequest},
+		{value: "Cumulative", want: CostDisplayCumulative},
+		{value: "running", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCostDisplayMode(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseCostDisplayMode() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCostDisplayMode() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCostDisplayMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClampRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		interval    time.Duration
+		minimum     time.Duration
+		want        time.Duration
+		wantChanged bool
+	}{
+		{name: "within bounds", interval: 5 * time.Second, minimum: time.Second, want: 5 * time.Second},
+		{name: "below minimum", interval: 2 * time.Second, minimum: 10 * time.Second, want: 10 * time.Second, wantChanged: true},
+		{name: "minimum below floor", interval: 100 * time.Millisecond, minimum: 10 * time.Millisecond, want: MinRefreshInterval, wantChanged: true},
+		{name: "above maximum", interval: 10 * time.Minute, minimum: time.Second, want: MaxRefreshInterval, wantChanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, changed := ClampRefreshInterval(tt.interval, tt.minimum)
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("ClampRefreshInterval() = %v, %v, want %v, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestCalculateStatsColumnWidthsFor(t *testing.T) {
+	t.Parallel()
+
+	// The default column set keeps the original fixed layout
+	want := []int{22, 9, 14, 10, 14, 14, 16}
+	got := CalculateStatsColumnWidthsFor(100, DefaultStatsColumns)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("CalculateStatsColumnWidthsFor(default) = %v, want %v", got, want)
+		}
+	}
+
+	// A subset still spends the available width
+	subset := CalculateStatsColumnWidthsFor(100, []StatsColumn{StatsColumnCost, StatsColumnRequests})
+	if len(subset) != 3 {
+		t.Fatalf("expected 3 widths, got %v", subset)
+	}
+	total := 0
+	for _, width := range subset {
+		total += width
+	}
+	if total < 98 || total > 100 {
+		t.Errorf("expected widths to fill about 100 columns, got %d (%v)", total, subset)
+	}
+}
+
+func TestFormatCostPerThousandTokens(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		request  entity.APIRequest
+		expected string
+	}{
+		{
+			name:     "rate with six decimals",
+			request:  entity.NewAPIRequest("s1", timestamp, "claude-3-5-sonnet-20241022", entity.NewToken(1500, 500, 0, 0), entity.NewCost(0.03), 1000),
+			expected: "$0.015000",
+		},
+		{
+			name:     "zero tokens show a dash",
+			request:  entity.NewAPIRequest("s1", timestamp, "claude-3-5-sonnet-20241022", entity.NewToken(0, 0, 0, 0), entity.NewCost(0.03), 1000),
+			expected: "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatCostPerThousandTokens(tt.request); got != tt.expected {
+				t.Errorf("FormatCostPerThousandTokens() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatCostShare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		share    float64
+		expected string
+	}{
+		{name: "whole percent", share: 40, expected: "40%"},
+		{name: "rounded", share: 33.6, expected: "34%"},
+		{name: "small share", share: 0.2, expected: "<1%"},
+		{name: "zero share", share: 0, expected: "0%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatCostShare(tt.share); got != tt.expected {
+				t.Errorf("FormatCostShare() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
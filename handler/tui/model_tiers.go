@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// ModelTiersModel lists the models in the current period and lets the user reassign their tier
+type ModelTiersModel struct {
+	// Data ownership
+	models []usecase.ModelTierUsage
+	cursor int
+	err    string
+	period entity.Period
+
+	// Business logic dependencies
+	listQuery  *usecase.ListModelTiersQuery
+	setCommand *usecase.SetModelTierCommand
+}
+
+// NewModelTiersModel creates a model tier review backed by the given usecases
+func NewModelTiersModel(listQuery *usecase.ListModelTiersQuery, setCommand *usecase.SetModelTierCommand) *ModelTiersModel {
+	return &ModelTiersModel{
+		listQuery:  listQuery,
+		setCommand: setCommand,
+	}
+}
+
+// Init initializes the model tier review
+func (m *ModelTiersModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model tier review
+func (m *ModelTiersModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ModelTiersDataMsg:
+		if msg.Err != nil {
+			m.err = msg.Err.Error()
+			return m, nil
+		}
+		m.models = msg.Models
+		m.err = ""
+		if m.cursor >= len(m.models) {
+			m.cursor = max(len(m.models)-1, 0)
+		}
+	case ModelTierSavedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err.Error()
+			return m, nil
+		}
+		m.err = ""
+		return m, m.Refresh(m.period)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.models)-1 {
+				m.cursor++
+			}
+		case "b":
+			return m, m.setTier(entity.ModelTierBase)
+		case "p":
+			return m, m.setTier(entity.ModelTierPremium)
+		case "o":
+			return m, m.setTier(entity.ModelTierOpus)
+		case "r":
+			return m, m.setTier("")
+		}
+	}
+	return m, nil
+}
+
+// View renders the model list with the selected row highlighted
+func (m *ModelTiersModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderStyle.Render("Model Tiers") + "\n")
+
+	if len(m.models) == 0 {
+		b.WriteString(HelpStyle.Render("  No models in this period") + "\n")
+	}
+
+	for i, model := range m.models {
+		marker := "  "
+		if i == m.cursor {
+			marker = "> "
+		}
+
+		tierStyle := PremiumStyle
+		if model.Tier == entity.ModelTierBase {
+			tierStyle = BaseStyle
+		}
+		source := "auto"
+		if model.Overridden {
+			source = "override"
+		}
+
+		line := fmt.Sprintf("%s%-40s %6d reqs  ", marker, model.Model.String(), model.Requests)
+		if i == m.cursor {
+			line = StatStyle.Render(line)
+		}
+		b.WriteString(line + tierStyle.Render(fmt.Sprintf("%-8s", model.Tier)) + HelpStyle.Render(" ("+source+")") + "\n")
+	}
+
+	if m.err != "" {
+		b.WriteString(ErrorStyle.Render("  "+m.err) + "\n")
+	}
+
+	return b.String()
+}
+
+// Models returns the listed models, most used first
+func (m *ModelTiersModel) Models() []usecase.ModelTierUsage {
+	return m.models
+}
+
+// Refresh returns a command listing the models in the period
+func (m *ModelTiersModel) Refresh(period entity.Period) tea.Cmd {
+	m.period = period
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		models, err := m.listQuery.Execute(ctx, usecase.ListModelTiersParams{Period: period})
+		return ModelTiersDataMsg{Models: models, Err: err}
+	}
+}
+
+// setTier returns a command saving the tier of the selected model; an empty tier resets it
+func (m *ModelTiersModel) setTier(tier entity.ModelTier) tea.Cmd {
+	if m.cursor >= len(m.models) {
+		return nil
+	}
+
+	model := m.models[m.cursor].Model.String()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := m.setCommand.Execute(ctx, usecase.SetModelTierParams{Model: model, Tier: tier})
+		return ModelTierSavedMsg{Err: err}
+	}
+}
+
+// Message types for model tier review
+type ModelTiersDataMsg struct {
+	Models []usecase.ModelTierUsage
+	Err    error
+}
+
+type ModelTierSavedMsg struct {
+	Err error
+}
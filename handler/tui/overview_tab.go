@@ -18,9 +18,9 @@ type OverviewTabModel struct {
 }
 
 // NewOverviewTabModel creates a new overview tab model
-func NewOverviewTabModel(calculateStatsQuery *usecase.CalculateStatsQuery, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, timezone *time.Location, block *entity.Block) *OverviewTabModel {
+func NewOverviewTabModel(calculateStatsQuery *usecase.CalculateStatsQuery, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, countSessionsQuery *usecase.CountSessionsQuery, timezone *time.Location, block *entity.Block) *OverviewTabModel {
 	return &OverviewTabModel{
-		statsModel:         NewStatsModel(calculateStatsQuery, timezone, block),
+		statsModel:         NewStatsModel(calculateStatsQuery, countSessionsQuery, timezone, block),
 		requestsTableModel: NewRequestsTableModel(getFilteredQuery, timezone),
 		width:              120,
 		height:             30,
@@ -56,6 +56,10 @@ func (m *OverviewTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+		// Streamed stats arrive separately, a failed query keeps the last total
+		if msg.Err == nil && !msg.Streamed {
+			m.requestsTableModel.SetPeriodCost(msg.Stats.TotalCost())
+		}
 
 	case RequestsRefreshMsg:
 		// Forward requests refresh to table model
@@ -110,6 +114,15 @@ func (m *OverviewTabModel) View() string {
 	tableView := m.requestsTableModel.View()
 	b.WriteString(tableView + "\n")
 
+	// Details of the selected request, on one line to keep the table height stable
+	if selected, ok := m.requestsTableModel.SelectedRequest(); ok {
+		details := "  Cost per 1K tokens: " + FormatCostPerThousandTokens(selected)
+		if selected.Note() != "" {
+			details += " • Note: " + selected.Note()
+		}
+		b.WriteString(HelpStyle.Render(details) + "\n")
+	}
+
 	return b.String()
 }
 
@@ -120,7 +133,39 @@ func (m *OverviewTabModel) SetSize(width, height int) {
 
 	// Update sub-component sizes
 	m.statsModel.SetSize(width, height)
-	m.requestsTableModel.SetSize(width, height)
+	m.requestsTableModel.SetSize(width, height-m.statsModel.extraBlockProgressLines())
+}
+
+// SetTimeDisplayMode switches timestamps and block times between local time and UTC
+func (m *OverviewTabModel) SetTimeDisplayMode(mode TimeDisplayMode) {
+	m.statsModel.SetTimeDisplayMode(mode)
+	m.requestsTableModel.SetTimeDisplayMode(mode)
+}
+
+// SetCostDisplayMode switches the requests table cost column between per request costs and the running total
+func (m *OverviewTabModel) SetCostDisplayMode(mode CostDisplayMode) {
+	m.requestsTableModel.SetCostDisplayMode(mode)
+}
+
+// SetModelNames shows the configured display names in the requests table's model column
+func (m *OverviewTabModel) SetModelNames(modelNames entity.ModelNameMap) {
+	m.requestsTableModel.SetModelNames(modelNames)
+}
+
+// SetCostShare adds or removes the requests table column showing each request's share of the period cost
+func (m *OverviewTabModel) SetCostShare(enabled bool) {
+	m.requestsTableModel.SetCostShare(enabled)
+}
+
+// setStreamedStats shows the period stats pushed by the server and the requests' share of their cost
+func (m *OverviewTabModel) setStreamedStats(stats entity.Stats) {
+	m.statsModel.setStreamedStats(stats)
+	m.requestsTableModel.SetPeriodCost(stats.TotalCost())
+}
+
+// SetMinCost sets the minimum cost filter applied to the requests table
+func (m *OverviewTabModel) SetMinCost(minCost entity.Cost) {
+	m.requestsTableModel.SetMinCost(minCost)
 }
 
 // RefreshStats triggers a stats refresh with the given period
@@ -130,13 +175,18 @@ func (m *OverviewTabModel) RefreshStats(period entity.Period) tea.Cmd {
 	return cmd
 }
 
-// RefreshRequests triggers a requests refresh with the given period and sort order
-func (m *OverviewTabModel) RefreshRequests(period entity.Period, sortOrder SortOrder) tea.Cmd {
-	msg := RequestsRefreshMsg{Period: period, SortOrder: sortOrder}
+// RefreshRequests triggers a requests refresh with the given period, sort order, label filters and duration range
+func (m *OverviewTabModel) RefreshRequests(period entity.Period, sortOrder SortOrder, labels []entity.LabelFilter, duration entity.DurationRange) tea.Cmd {
+	msg := RequestsRefreshMsg{Period: period, SortOrder: sortOrder, Labels: labels, Duration: duration}
 	_, cmd := m.requestsTableModel.Update(msg)
 	return cmd
 }
 
+// SelectedRequest returns the request selected in the requests table
+func (m *OverviewTabModel) SelectedRequest() (entity.APIRequest, bool) {
+	return m.requestsTableModel.SelectedRequest()
+}
+
 // GetRequestsTable returns the requests table model for external access
 func (m *OverviewTabModel) GetRequestsTable() *RequestsTableModel {
 	return m.requestsTableModel
@@ -72,7 +72,7 @@ func TestOverviewTab_IntegrationWithViewModel(t *testing.T) {
 			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
 			// Create the ViewModel (starts on overview tab by default)
-			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 			// Create teatest model
 			tm := teatest.NewTestModel(
@@ -123,7 +123,7 @@ func TestOverviewTab_TableInteractions(t *testing.T) {
 		periodFactory := service.NewTimePeriodFactory(time.UTC)
 		getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
-		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 		tm := teatest.NewTestModel(
 			t, model,
@@ -178,7 +178,7 @@ func TestOverviewTab_TimeFiltering(t *testing.T) {
 		periodFactory := service.NewTimePeriodFactory(time.UTC)
 		getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
-		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 		tm := teatest.NewTestModel(
 			t, model,
@@ -11,15 +11,45 @@ import (
 
 // MonitorConfig represents monitor configuration for TUI
 type MonitorConfig struct {
-	Server          string
-	Timezone        string
-	RefreshInterval string
-	TokenLimit      int
-	BlockTime       string
+	Server             string
+	Timezone           string
+	RefreshInterval    string
+	MinRefreshInterval string // Shortest refresh interval, also for runtime changes; empty uses MinRefreshInterval
+	TokenLimit         int
+	BlockTokenMetric   entity.BlockTokenMetric // Premium tokens counted against TokenLimit; empty counts limited tokens
+	BlockTime          string
+	DurationStyle      entity.DurationStyle // Rendering of durations such as block time remaining
+	MinCost            float64              // Hide requests cheaper than this in the table; 0 shows all
+	StatsColumns       []string             // Stats table column order; empty uses the default order
+	StatsAlign         string               // Numeric stats column alignment: left or right
+	HideZeroRows       bool                 // Hide stats table tier rows without requests in the period
+	NegligibleCost     float64              // Show requests cheaper than this as a line under the stats table; 0 hides it
+	DailyOrder         string               // Day order of the Daily Usage tab: newest (default) or oldest
+	SparklineInterval  string               // Header cost trend bucket size; empty uses DefaultSparklineInterval
+	SparklineBuckets   int                  // Header cost trend bucket count; 0 hides the sparkline
+	Clock              entity.Clock         // Source of "now"; nil uses the system clock
+	NumberLocale       entity.NumberLocale  // Decimal and grouping separators of costs and token counts
+	TokenDecimals      int                  // Decimal places of abbreviated token counts; negative uses 1 for K and 2 for M
+	PercentDecimals    int                  // Decimal places of plan usage percentages; 0 shows whole percents
+	ShowPreviousBlock  bool                 // Show the previous block's final usage under the block progress
+	SoftLimit          int                  // Percentage of the token limit marked on the block progress bar; 0 disables it
+	BaseTokenLimit     int                  // Block limit of base tier tokens; 0 shows base usage without a bar
+	ListWindow         string               // Show only requests this recent in the table (e.g. 2h); empty shows the whole period
+	AutoBlock          bool                 // Without BlockTime, anchor the block at the hour of today's first request
+	DurationRange      entity.DurationRange // Show only requests whose duration falls in the range; empty shows all
+	ProjectLabel       string               // Label naming the project of a request; empty disables the project filter
+	StaleAfter         string               // Warn in the footer when the latest request is older (e.g. 1h); empty or 0 disables it
+	BlockGracePeriod   string               // Keep an ended block's progress this long before rolling over (e.g. 5m); empty or 0 rolls over at the end
+	RollingDays        int                  // Days of the "r" rolling window filter; 0 disables it
+	CostColumn         string               // Cost column of the requests table: request (default) or cumulative
+	CostShare          bool                 // Add a column with each request's share of the period cost
+	ModelNames         entity.ModelNameMap  // Display names shown instead of raw model names in the requests table
+	BannerThresholds   []int                // Plan usage percentages showing the warning banner; empty disables it
+	BannerAutoDismiss  string               // Hide the warning banner after it was shown this long (e.g. 30s); empty or 0 keeps it until dismissed
 }
 
 // RunMonitor runs the TUI monitor mode with usecases and config
-func RunMonitor(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUsageQuery *usecase.GetUsageQuery, monitorConfig MonitorConfig) error {
+func RunMonitor(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUsageQuery *usecase.GetUsageQuery, countSessionsQuery *usecase.CountSessionsQuery, dailyBudgetQuery *usecase.GetDailyBudgetQuery, cacheSavingsQuery *usecase.CalculateCacheSavingsQuery, longestGapQuery *usecase.GetLongestGapQuery, setNoteCommand *usecase.SetRequestNoteCommand, listModelTiersQuery *usecase.ListModelTiersQuery, setModelTierCommand *usecase.SetModelTierCommand, getDashboardQuery *usecase.GetDashboardQuery, watchStatsQuery *usecase.WatchStatsQuery, notifyCostAlertsCommand *usecase.NotifyCostAlertsCommand, signalBudgetCommand *usecase.SignalBudgetCommand, planUsageQuery *usecase.GetPlanUsageQuery, firstRequestQuery *usecase.GetFirstRequestTimeQuery, statsCache StatsCacheInvalidator, monitorConfig MonitorConfig) error {
 	// Load timezone for monitor mode
 	timezone, err := time.LoadLocation(monitorConfig.Timezone)
 	if err != nil {
@@ -32,39 +62,174 @@ func RunMonitor(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculate
 		return fmt.Errorf("invalid refresh interval format %s: %w", monitorConfig.RefreshInterval, err)
 	}
 
-	// Validate refresh interval bounds
-	if refreshInterval < time.Second {
-		return fmt.Errorf("refresh interval too short (%v), minimum is 1 second", refreshInterval)
-	}
-	if refreshInterval > 5*time.Minute {
+	// Validate refresh interval bounds, a too short interval is raised to the minimum rather than rejected
+	if refreshInterval > MaxRefreshInterval {
 		return fmt.Errorf("refresh interval too long (%v), maximum is 5 minutes", refreshInterval)
 	}
+	minRefreshInterval := MinRefreshInterval
+	if monitorConfig.MinRefreshInterval != "" {
+		minRefreshInterval, err = time.ParseDuration(monitorConfig.MinRefreshInterval)
+		if err != nil {
+			return fmt.Errorf("invalid minimum refresh interval format %s: %w", monitorConfig.MinRefreshInterval, err)
+		}
+	}
+	if minRefreshInterval < MinRefreshInterval {
+		fmt.Printf("Warning: Minimum refresh interval %v is below the %v floor, using %v.\n", minRefreshInterval, MinRefreshInterval, MinRefreshInterval)
+	}
+	if clamped, changed := ClampRefreshInterval(refreshInterval, minRefreshInterval); changed {
+		fmt.Printf("Warning: Refresh interval %v is below the minimum, using %v.\n", refreshInterval, clamped)
+		refreshInterval = clamped
+	}
 
-	// Parse block configuration if provided
-	var block *entity.Block
-	if monitorConfig.BlockTime != "" {
-		startHour, err := parseBlockTime(monitorConfig.BlockTime)
+	// Configure number separators
+	SetNumberLocale(monitorConfig.NumberLocale)
+	SetTokenDecimals(monitorConfig.TokenDecimals)
+	SetPercentDecimals(monitorConfig.PercentDecimals)
+
+	// Configure stats table layout
+	statsColumns, err := ParseStatsColumns(monitorConfig.StatsColumns)
+	if err != nil {
+		return fmt.Errorf("invalid stats columns: %w", err)
+	}
+	alignRight, err := ParseStatsAlign(monitorConfig.StatsAlign)
+	if err != nil {
+		return fmt.Errorf("invalid stats alignment: %w", err)
+	}
+	dailyOrder, err := ParseDailyOrder(monitorConfig.DailyOrder)
+	if err != nil {
+		return fmt.Errorf("invalid daily order: %w", err)
+	}
+	costDisplay, err := ParseCostDisplayMode(monitorConfig.CostColumn)
+	if err != nil {
+		return fmt.Errorf("invalid cost column: %w", err)
+	}
+
+	// Configure header sparkline
+	sparklineInterval := DefaultSparklineInterval
+	if monitorConfig.SparklineInterval != "" {
+		sparklineInterval, err = time.ParseDuration(monitorConfig.SparklineInterval)
 		if err != nil {
-			return fmt.Errorf("invalid block time format %s: %w", monitorConfig.BlockTime, err)
+			return fmt.Errorf("invalid sparkline interval format %s: %w", monitorConfig.SparklineInterval, err)
 		}
+	}
 
-		if monitorConfig.TokenLimit == 0 {
-			fmt.Printf("Warning: No token limit configured. Set claude.plan or claude.max_tokens in config.\n")
+	// Parse the requests table window
+	var listWindow time.Duration
+	if monitorConfig.ListWindow != "" {
+		listWindow, err = time.ParseDuration(monitorConfig.ListWindow)
+		if err != nil {
+			return fmt.Errorf("invalid list window format %s: %w", monitorConfig.ListWindow, err)
 		}
+	}
 
-		// Create current block with token limit based on user's start hour
-		blockEntity := calculateCurrentBlock(startHour, timezone, time.Now(), monitorConfig.TokenLimit)
-		block = &blockEntity
+	// Parse the data freshness threshold
+	var staleAfter time.Duration
+	if monitorConfig.StaleAfter != "" {
+		staleAfter, err = time.ParseDuration(monitorConfig.StaleAfter)
+		if err != nil {
+			return fmt.Errorf("invalid stale after format %s: %w", monitorConfig.StaleAfter, err)
+		}
+	}
+
+	// Parse the block grace period
+	var blockGracePeriod time.Duration
+	if monitorConfig.BlockGracePeriod != "" {
+		blockGracePeriod, err = time.ParseDuration(monitorConfig.BlockGracePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid block grace period format %s: %w", monitorConfig.BlockGracePeriod, err)
+		}
+	}
+
+	// Parse the warning banner auto dismiss
+	var bannerAutoDismiss time.Duration
+	if monitorConfig.BannerAutoDismiss != "" {
+		bannerAutoDismiss, err = time.ParseDuration(monitorConfig.BannerAutoDismiss)
+		if err != nil {
+			return fmt.Errorf("invalid banner auto dismiss format %s: %w", monitorConfig.BannerAutoDismiss, err)
+		}
+	}
+
+	clock := monitorConfig.Clock
+	if clock == nil {
+		clock = entity.SystemClock{}
+	}
+
+	// Parse block configuration if provided
+	block, err := newMonitorBlock(monitorConfig, timezone, clock)
+	if err != nil {
+		return err
+	}
+
+	if block != nil && monitorConfig.TokenLimit == 0 {
+		fmt.Printf("Warning: No token limit configured. Set claude.plan or claude.max_tokens in config.\n")
 	}
 
 	// Create the view model (which now implements tea.Model directly)
-	model := NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, timezone, block, refreshInterval)
+	model := NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, countSessionsQuery, timezone, block, refreshInterval)
+	model.SetMinRefreshInterval(minRefreshInterval)
+	model.SetMinCost(monitorConfig.MinCost)
+	model.SetDurationRange(monitorConfig.DurationRange)
+	model.SetListWindow(listWindow)
+	model.SetRollingDays(monitorConfig.RollingDays)
+	model.SetStatsLayout(statsColumns, alignRight)
+	model.SetHideZeroRows(monitorConfig.HideZeroRows)
+	model.SetNegligibleCost(monitorConfig.NegligibleCost)
+	model.SetDailySortOrder(dailyOrder)
+	model.SetCostDisplayMode(costDisplay)
+	model.SetCostShare(monitorConfig.CostShare)
+	model.SetModelNames(monitorConfig.ModelNames)
+	model.SetStatsCache(statsCache)
+	model.SetNoteCommand(setNoteCommand)
+	model.SetModelTierUsecases(listModelTiersQuery, setModelTierCommand)
+	model.SetDailyBudgetQuery(dailyBudgetQuery)
+	model.SetCacheSavingsQuery(cacheSavingsQuery)
+	model.SetLongestGapQuery(longestGapQuery)
+	model.SetDashboardQuery(getDashboardQuery)
+	model.SetWatchStatsQuery(watchStatsQuery)
+	model.SetCostAlertsCommand(notifyCostAlertsCommand)
+	model.SetBudgetSignalCommand(signalBudgetCommand)
+	model.SetBudgetBanner(planUsageQuery, monitorConfig.BannerThresholds, bannerAutoDismiss)
+	model.SetSparkline(sparklineInterval, monitorConfig.SparklineBuckets)
+	model.SetClock(clock)
+	model.SetShowPreviousBlock(monitorConfig.ShowPreviousBlock)
+	model.SetBlockGracePeriod(blockGracePeriod)
+	if monitorConfig.AutoBlock && monitorConfig.BlockTime == "" {
+		model.SetFirstRequestQuery(firstRequestQuery)
+	}
+	model.SetDurationStyle(monitorConfig.DurationStyle)
+	model.SetSoftLimit(monitorConfig.SoftLimit)
+	model.SetBaseTokenLimit(monitorConfig.BaseTokenLimit)
+	model.SetFreshness(usecase.NewGetLatestRequestTimeQuery(getFilteredQuery), staleAfter)
+	if monitorConfig.ProjectLabel != "" {
+		model.SetProjectLabel(monitorConfig.ProjectLabel, usecase.NewListLabelValuesQuery(getFilteredQuery))
+	}
 
 	// Create and run the Bubble Tea program
 	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	// Close the stats subscription before the server connection is closed
+	model.SetWatchStatsQuery(nil)
+	if err != nil {
 		return fmt.Errorf("error running TUI: %w", err)
 	}
 
 	return nil
 }
+
+// newMonitorBlock returns the block configured by BlockTime or AutoBlock, nil when block tracking is disabled
+func newMonitorBlock(monitorConfig MonitorConfig, timezone *time.Location, clock entity.Clock) (*entity.Block, error) {
+	if monitorConfig.BlockTime != "" {
+		block, err := NewCurrentBlock(monitorConfig.BlockTime, timezone, clock.Now(), monitorConfig.TokenLimit)
+		if err != nil {
+			return nil, err
+		}
+		block = block.WithTokenMetric(monitorConfig.BlockTokenMetric)
+		return &block, nil
+	}
+	if monitorConfig.AutoBlock {
+		// Starts at the current hour, the first refresh anchors it at today's first request
+		block := NewInferredBlock(time.Time{}, timezone, clock.Now(), monitorConfig.TokenLimit).WithTokenMetric(monitorConfig.BlockTokenMetric)
+		return &block, nil
+	}
+	return nil, nil
+}
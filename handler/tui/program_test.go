@@ -2,6 +2,10 @@ package tui_test
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -25,7 +29,7 @@ func TestProgram_BasicOutput(t *testing.T) {
 	getUsageQuery := CreateTestUsageQuery()
 
 	// Create the ViewModel
-	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 5*time.Second)
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
 
 	// Create teatest model
 	tm := teatest.NewTestModel(
@@ -144,7 +148,7 @@ func TestViewModel_KeyboardInteractions(t *testing.T) {
 			getUsageQuery := CreateTestUsageQuery()
 
 			// Create ViewModel
-			vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, tc.block, 5*time.Second)
+			vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, tc.block, 5*time.Second)
 
 			// Send window size to initialize the view
 			vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
@@ -166,7 +170,7 @@ func TestViewModel_LayoutResponsiveness(t *testing.T) {
 	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
 	getUsageQuery := CreateTestUsageQuery()
 
-	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 5*time.Second)
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
 
 	windowSizes := []struct {
 		name   string
@@ -242,7 +246,7 @@ func TestViewModel_DataFlow(t *testing.T) {
 			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
 			getUsageQuery := CreateTestUsageQuery()
 
-			vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 5*time.Second)
+			vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
 
 			// Initialize the view
 			vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
@@ -295,7 +299,7 @@ func TestViewModel_BlockTracking(t *testing.T) {
 	block := CreateTestBlock()
 	getUsageQuery := CreateTestUsageQuery()
 
-	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, block, 5*time.Second)
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, block, 5*time.Second)
 
 	// Initialize the view
 	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
@@ -404,7 +408,11 @@ func TestHelperFunctions(t *testing.T) {
 		}{
 			{0, "0"},
 			{100, "100"},
+			{999, "999"},
+			{1000, "1.0K"},
+			{1499, "1.5K"},
 			{1500, "1.5K"},
+			{999950, "1.00M"},
 			{1500000, "1.50M"},
 		}
 		for _, tc := range testCases {
@@ -415,6 +423,28 @@ func TestHelperFunctions(t *testing.T) {
 		}
 	})
 
+	t.Run("FormatTokenCount with decimals", func(t *testing.T) {
+		tui.SetTokenDecimals(0)
+		defer tui.SetTokenDecimals(-1)
+
+		testCases := []struct {
+			input    int64
+			expected string
+		}{
+			{999, "999"},
+			{1000, "1K"},
+			{1499, "1K"},
+			{1500, "2K"},
+			{1500000, "2M"},
+		}
+		for _, tc := range testCases {
+			result := tui.FormatTokenCount(tc.input)
+			if result != tc.expected {
+				t.Errorf("FormatTokenCount(%d) = %q, expected %q", tc.input, result, tc.expected)
+			}
+		}
+	})
+
 	t.Run("FormatDurationFromTime", func(t *testing.T) {
 		testCases := []struct {
 			input    time.Duration
@@ -481,7 +511,7 @@ func TestViewModel_GetterMethods(t *testing.T) {
 	block := CreateTestBlock()
 	getUsageQuery := CreateTestUsageQuery()
 
-	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, block, 5*time.Second)
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, block, 5*time.Second)
 
 	// Test all getter methods
 	_ = vm.Requests()
@@ -520,7 +550,7 @@ func TestViewModel_FilterStateCoverage(t *testing.T) {
 	block := CreateTestBlock()
 	getUsageQuery := CreateTestUsageQuery()
 
-	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, block, 5*time.Second)
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, block, 5*time.Second)
 
 	// Initialize
 	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
@@ -591,7 +621,7 @@ func TestProgram_FullInteractiveOutput(t *testing.T) {
 	getUsageQuery := CreateTestUsageQuery()
 
 	// Create the ViewModel
-	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 5*time.Second)
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
 
 	// Create teatest model
 	tm := teatest.NewTestModel(
@@ -679,7 +709,7 @@ func TestProgram_KeyboardInteractions(t *testing.T) {
 			// Create the ViewModel
 			getUsageQuery := CreateTestUsageQuery()
 
-			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 5*time.Second)
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
 
 			// Create teatest model
 			tm := teatest.NewTestModel(
@@ -753,7 +783,7 @@ func TestProgram_SortOrderToggle(t *testing.T) {
 	getUsageQuery := CreateTestUsageQuery()
 
 	// Create the ViewModel
-	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 5*time.Second)
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
 
 	// Create teatest model
 	tm := teatest.NewTestModel(
@@ -824,7 +854,7 @@ func TestProgram_BlockFilterInteraction(t *testing.T) {
 	block := CreateTestBlock()
 	getUsageQuery := CreateTestUsageQuery()
 
-	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, block, 5*time.Second)
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, block, 5*time.Second)
 
 	// Create teatest model
 	tm := teatest.NewTestModel(
@@ -877,7 +907,7 @@ func TestProgram_MultipleFiltersSequence(t *testing.T) {
 	getUsageQuery := CreateTestUsageQuery()
 
 	// Create the ViewModel
-	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 5*time.Second)
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
 
 	// Create teatest model
 	tm := teatest.NewTestModel(
@@ -933,3 +963,1444 @@ func TestProgram_MultipleFiltersSequence(t *testing.T) {
 
 	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
 }
+
+// TestProgram_SessionsLine tests that the distinct session count is rendered in the stats box
+func TestProgram_SessionsLine(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	// Setup test data with four distinct sessions
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+	countSessionsQuery := usecase.NewCountSessionsQuery(apiRepo, true)
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, countSessionsQuery, time.UTC, nil, 5*time.Second)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(120, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Sessions: 4"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{
+		Type:  tea.KeyRunes,
+		Runes: []rune("q"),
+	})
+
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.Sessions() != 4 {
+		t.Errorf("Expected 4 sessions, got %d", model.Sessions())
+	}
+}
+
+// TestProgram_LabelFilter tests filtering the requests table by label
+func TestProgram_LabelFilter(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-opus-20240229", 100, 50, 0.10).
+			WithLabels(map[string]string{"environment": "prod"}),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-30*time.Minute), "claude-3-haiku-20240307", 100, 50, 0.01).
+			WithLabels(map[string]string{"environment": "dev"}),
+		testutil.CreateTestAPIRequest("session-3", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 100, 50, 0.01),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(120, 40),
+	)
+
+	// Open the label filter input and apply a filter
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Label filter:"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	// Keys typed while editing must not trigger shortcuts (e.g. "m" for month)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("environment=prod")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Labels: environment=prod"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	// Allow the filtered requests to load
+	time.Sleep(200 * time.Millisecond)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.GetTimeFilterString() != "All Time" {
+		t.Errorf("Expected time filter to stay 'All Time', got '%s'", model.GetTimeFilterString())
+	}
+	if len(model.LabelFilters()) != 1 || model.LabelFilters()[0].String() != "environment=prod" {
+		t.Errorf("Expected label filter environment=prod, got %v", model.LabelFilters())
+	}
+	if len(model.Requests()) != 1 || model.Requests()[0].SessionID() != "session-1" {
+		t.Errorf("Expected only session-1 to match the label filter, got %d requests", len(model.Requests()))
+	}
+}
+
+// TestProgram_TimeDisplayToggle tests cycling timestamps between local time and UTC
+func TestProgram_TimeDisplayToggle(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, tokyo, nil, 5*time.Second)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Time: Local"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Time: UTC"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Time: Local+UTC"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.TimeDisplayMode() != tui.TimeDisplayBoth {
+		t.Errorf("Expected time display mode Local+UTC, got %s", model.TimeDisplayMode())
+	}
+	if model.Timezone() != tokyo {
+		t.Errorf("Expected configured timezone to be unchanged, got %v", model.Timezone())
+	}
+}
+
+// TestProgram_MinCostFilter tests hiding cheap requests without changing stats
+func TestProgram_MinCostFilter(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-opus-20240229", 100, 50, 0.50),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-30*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.0001),
+		testutil.CreateTestAPIRequest("session-3", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.0002),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetMinCost(0.01)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Min cost: $0.01 (2 hidden)"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	// Toggling off shows every request again
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.Stats().TotalRequests() != 3 {
+		t.Errorf("Expected stats to include all 3 requests, got %d", model.Stats().TotalRequests())
+	}
+	if len(model.Requests()) != 3 {
+		t.Errorf("Expected all 3 requests after disabling the filter, got %d", len(model.Requests()))
+	}
+}
+
+// TestProgram_ListWindow tests listing only recent requests while stats cover the whole period
+func TestProgram_ListWindow(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-5*time.Hour), "claude-3-opus-20240229", 100, 50, 0.50),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-3*time.Hour), "claude-3-haiku-20240307", 10, 5, 0.01),
+		testutil.CreateTestAPIRequest("session-3", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.02),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetListWindow(2 * time.Hour)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("List: Last 2h 0m")) && bytes.Contains(bts, []byte("e=expand list"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+	time.Sleep(100 * time.Millisecond)
+
+	if len(model.Requests()) != 1 {
+		t.Errorf("Expected only the request within the list window, got %d", len(model.Requests()))
+	}
+
+	// Expanding lists the whole period again
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.Stats().TotalRequests() != 3 {
+		t.Errorf("Expected stats to include all 3 requests, got %d", model.Stats().TotalRequests())
+	}
+	if len(model.Requests()) != 3 {
+		t.Errorf("Expected all 3 requests after expanding the list, got %d", len(model.Requests()))
+	}
+}
+
+func TestProgram_RollingFilter(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-20*24*time.Hour), "claude-3-opus-20240229", 100, 50, 0.50),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-10*24*time.Hour), "claude-3-haiku-20240307", 10, 5, 0.01),
+		testutil.CreateTestAPIRequest("session-3", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.02),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetRollingDays(14)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("r=14d"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Last 14 Days"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+	time.Sleep(100 * time.Millisecond)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.GetTimeFilterString() != "Last 14 Days" {
+		t.Errorf("Expected filter 'Last 14 Days', got '%s'", model.GetTimeFilterString())
+	}
+	if model.Stats().TotalRequests() != 2 {
+		t.Errorf("Expected 2 requests within the rolling window, got %d", model.Stats().TotalRequests())
+	}
+}
+
+func TestProgram_RollingFilterDisabled(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), nil, time.UTC, nil, 5*time.Second)
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	// Without monitor.rolling_days the key is ignored
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.GetTimeFilterString() != "All Time" {
+		t.Errorf("Expected filter to stay 'All Time', got '%s'", model.GetTimeFilterString())
+	}
+}
+
+func TestProgram_DurationRange(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	requestTaking := func(sessionID string, offset time.Duration, durationMS int64) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, now.Add(-offset), "claude-3-haiku-20240307", entity.NewToken(10, 5, 0, 0), entity.NewCost(0.01), durationMS)
+	}
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		requestTaking("session-1", 3*time.Hour, 2_000),
+		requestTaking("session-2", 2*time.Hour, 30_000),
+		requestTaking("session-3", time.Hour, 95_000),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	durationRange, err := entity.ParseDurationRange("30s", "")
+	if err != nil {
+		t.Fatalf("Unexpected range error: %v", err)
+	}
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetDurationRange(durationRange)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Duration: ≥30s"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	// Only the list is narrowed, stats keep every request
+	if len(model.Requests()) != 2 {
+		t.Errorf("Expected the 2 requests of at least 30s, got %d", len(model.Requests()))
+	}
+	if model.Stats().TotalRequests() != 3 {
+		t.Errorf("Expected stats to include all 3 requests, got %d", model.Stats().TotalRequests())
+	}
+}
+
+// countingStatsCache records how often the monitor starts a new refresh cycle
+type countingStatsCache struct {
+	*service.RefreshStatsCache
+	invalidations atomic.Int32
+}
+
+func (c *countingStatsCache) Invalidate() {
+	c.invalidations.Add(1)
+	c.RefreshStatsCache.Invalidate()
+}
+
+// TestProgram_StatsCacheInvalidatedOnTick tests the per-cycle stats cache is reset by each refresh tick
+func TestProgram_StatsCacheInvalidatedOnTick(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	cache := &countingStatsCache{RefreshStatsCache: service.NewRefreshStatsCache()}
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, cache)
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 100*time.Millisecond)
+	model.SetStatsCache(cache)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return cache.invalidations.Load() >= 2
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second*2),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.Stats().TotalRequests() == 0 {
+		t.Error("Expected stats to be loaded through the refresh cache")
+	}
+}
+
+// TestProgram_RequestNote tests annotating the selected request with a note
+func TestProgram_RequestNote(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	timestamp := time.Now().UTC().Add(-time.Hour)
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", timestamp, "claude-3-opus-20240229", 100, 50, 2.50),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetNoteCommand(usecase.NewSetRequestNoteCommand(apiRepo))
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("n=note")) && bytes.Contains(bts, []byte("2.500000"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("refactor")})
+	tm.Send(tea.KeyMsg{Type: tea.KeySpace})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("run")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// The saved note is shown below the table for the selected request
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Note: refactor run"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.EditingNote() {
+		t.Error("Expected the note editor to close after saving")
+	}
+	requests, _ := apiRepo.FindAll()
+	if requests[0].Note() != "refactor run" {
+		t.Errorf("Expected stored note %q, got %q", "refactor run", requests[0].Note())
+	}
+}
+
+// TestProgram_CostSparkline tests the header cost trend is fed by interval usage
+func TestProgram_CostSparkline(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	currentHour := time.Now().UTC().Truncate(time.Hour)
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", currentHour.Add(-2*time.Hour), "claude-3-opus-20240229", 100, 50, 4.0),
+		testutil.CreateTestAPIRequest("session-1", currentHour, "claude-3-opus-20240229", 100, 50, 1.0),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetSparkline(time.Hour, 3)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Cost last 3×1h")) && bytes.Contains(bts, []byte("$5.00"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	costs := model.SparklineCosts()
+	if len(costs) != 3 || costs[0] != 4.0 || costs[1] != 0 || costs[2] != 1.0 {
+		t.Errorf("Expected oldest-first costs [4 0 1], got %v", costs)
+	}
+}
+
+func TestProgram_DailyBudget(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	daysInMonth := entity.DaysInMonth(time.Now().UTC())
+
+	tests := []struct {
+		name     string
+		plan     entity.Plan
+		expected string
+	}{
+		{
+			name:     "pro plan shows derived budget",
+			plan:     entity.NewPlan("pro", entity.NewCost(20.0)),
+			expected: fmt.Sprintf("(pro plan $20.00 / %d days)", daysInMonth),
+		},
+		{
+			name:     "unset plan shows no plan",
+			plan:     entity.NewPlan("unset", entity.NewCost(0)),
+			expected: "No plan set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			statsRepo := testutil.NewMockStatsRepository(apiRepo)
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			periodFactory := service.NewTimePeriodFactory(time.UTC)
+			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
+
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+			model.SetDailyBudgetQuery(usecase.NewGetDailyBudgetQuery(testutil.NewMockPlanRepository(tt.plan), periodFactory))
+
+			tm := teatest.NewTestModel(
+				t, model,
+				teatest.WithInitialTermSize(160, 40),
+			)
+
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte("Daily Budget:")) && bytes.Contains(bts, []byte(tt.expected))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Millisecond*500),
+			)
+
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+			tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+		})
+	}
+}
+
+func TestProgram_BudgetBanner(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	tests := []struct {
+		name        string
+		autoDismiss time.Duration
+		dismissKey  bool
+	}{
+		{
+			name:       "dismissed with x",
+			dismissKey: true,
+		},
+		{
+			name:        "auto dismissed",
+			autoDismiss: 200 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			apiRepo.SetMockData([]entity.APIRequest{
+				CreateTestAPIRequest("session-1", time.Now().Add(-time.Minute), "claude-3-5-sonnet-20241022", 1000, 500, 30),
+			})
+			statsRepo := testutil.NewMockStatsRepository(apiRepo)
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			periodFactory := service.NewTimePeriodFactory(time.UTC)
+			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
+			planRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20)))
+
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+			model.SetBudgetBanner(usecase.NewGetPlanUsageQuery(calculateStatsQuery, planRepo, periodFactory), []int{90, 100}, tt.autoDismiss)
+
+			tm := teatest.NewTestModel(
+				t, model,
+				teatest.WithInitialTermSize(160, 40),
+			)
+
+			// $30 of the $20 plan is past the highest threshold this month
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte("Monthly plan usage 150% (past 100%)"))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Second),
+			)
+
+			if tt.dismissKey {
+				tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+			} else {
+				time.Sleep(2 * tt.autoDismiss)
+			}
+
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+			tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+			final := tm.FinalModel(t).(*tui.ViewModel)
+			if final.BudgetBannerVisible() {
+				t.Error("expected the banner to be dismissed")
+			}
+		})
+	}
+}
+
+func TestProgram_BudgetBannerWithoutPlan(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		CreateTestAPIRequest("session-1", time.Now().Add(-time.Minute), "claude-3-5-sonnet-20241022", 1000, 500, 30),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
+	planRepo := testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0)))
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetBudgetBanner(usecase.NewGetPlanUsageQuery(calculateStatsQuery, planRepo, periodFactory), []int{90, 100}, 0)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Monitor Mode"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	// Without a priced plan there is no budget to warn about
+	final := tm.FinalModel(t).(*tui.ViewModel)
+	if final.BudgetBannerVisible() {
+		t.Error("expected no banner without a plan")
+	}
+}
+
+func TestProgram_CacheSavings(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", now.Add(-time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 1_000_000, 0), entity.NewCost(0.5), 1000),
+	}
+
+	tests := []struct {
+		name      string
+		rates     entity.RateTable
+		wantShown bool
+	}{
+		{
+			name:      "configured rates show savings",
+			rates:     entity.NewRateTable(entity.NewModelRate("claude-*sonnet*", 3.0, 0.3)),
+			wantShown: true,
+		},
+		{
+			name:      "missing rates hide savings",
+			rates:     entity.NewRateTable(entity.NewModelRate("claude-*haiku*", 0.8, 0.08)),
+			wantShown: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			apiRepo.SetMockData(requests)
+			statsRepo := testutil.NewMockStatsRepository(apiRepo)
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+
+			countSessionsQuery := usecase.NewCountSessionsQuery(apiRepo, true)
+
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, countSessionsQuery, time.UTC, nil, 5*time.Second)
+			model.SetCacheSavingsQuery(usecase.NewCalculateCacheSavingsQuery(getFilteredQuery, tt.rates))
+
+			tm := teatest.NewTestModel(
+				t, model,
+				teatest.WithInitialTermSize(160, 40),
+			)
+
+			// Sessions arrive with the savings, so the line has been decided once they render
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte("Sessions: 1"))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Millisecond*500),
+			)
+
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+			tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+			if shown := bytes.Contains([]byte(model.View()), []byte("Cache Savings: $2.70")); shown != tt.wantShown {
+				t.Errorf("cache savings shown = %v, want %v", shown, tt.wantShown)
+			}
+		})
+	}
+}
+
+func TestProgram_ModelTierReview(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", now.Add(-3*time.Minute), "local-llama", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.1), 1000),
+		entity.NewAPIRequest("session-1", now.Add(-2*time.Minute), "local-llama", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.1), 1000),
+		entity.NewAPIRequest("session-1", now.Add(-time.Minute), "claude-3-haiku-20240307", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 500),
+	}
+
+	tests := []struct {
+		name             string
+		key              string
+		expectedTier     entity.ModelTier
+		expectedBaseReqs int
+	}{
+		{
+			name:             "mark model as base",
+			key:              "b",
+			expectedTier:     entity.ModelTierBase,
+			expectedBaseReqs: 3,
+		},
+		{
+			name:             "keep model as premium",
+			key:              "p",
+			expectedTier:     entity.ModelTierPremium,
+			expectedBaseReqs: 1,
+		},
+		{
+			name:             "mark model as opus",
+			key:              "o",
+			expectedTier:     entity.ModelTierOpus,
+			expectedBaseReqs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			apiRepo.SetMockData(requests)
+			tierRepo := testutil.NewMockModelTierRepository(entity.NewModelClassifier(nil))
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(apiRepo), service.NewInMemoryStatsCache(time.Minute))
+			calculateStatsQuery.SetModelTierRepository(tierRepo)
+			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+			model.SetModelTierUsecases(usecase.NewListModelTiersQuery(getFilteredQuery, tierRepo), usecase.NewSetModelTierCommand(tierRepo))
+
+			tm := teatest.NewTestModel(
+				t, model,
+				teatest.WithInitialTermSize(160, 40),
+			)
+
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte("t=tiers"))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Millisecond*500),
+			)
+
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte("local-llama")) && bytes.Contains(bts, []byte("r=reset to auto"))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Millisecond*500),
+			)
+
+			// The most used model is selected first
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte("(override)"))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Millisecond*500),
+			)
+
+			tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+			tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+			final := tm.FinalModel(t).(*tui.ViewModel)
+			if final.ReviewingTiers() {
+				t.Error("expected the tier review to be closed")
+			}
+			// Stats are recalculated with the override on the next refresh
+			stats, err := calculateStatsQuery.Execute(context.Background(), usecase.CalculateStatsParams{Period: entity.NewAllTimePeriod(now)})
+			if err != nil {
+				t.Fatalf("unexpected stats error: %v", err)
+			}
+			if stats.BaseRequests() != tt.expectedBaseReqs {
+				t.Errorf("expected %d base requests, got %d", tt.expectedBaseReqs, stats.BaseRequests())
+			}
+			if tier, ok := tierRepo.GetClassifier().Override(entity.NewModel("local-llama")); !ok || tier != tt.expectedTier {
+				t.Errorf("expected override %q, got %q (set: %v)", tt.expectedTier, tier, ok)
+			}
+		})
+	}
+}
+
+// TestProgram_Legend tests opening and closing the legend overlay
+func TestProgram_Legend(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-opus-20240229", 100, 50, 0.50),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("?: Legend"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Input + output tokens")) && bytes.Contains(bts, []byte("Key Bindings"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	// Filter keys are ignored while the legend is open
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	final := tm.FinalModel(t).(*tui.ViewModel)
+	if final.ShowingLegend() {
+		t.Error("expected the legend to be closed")
+	}
+	if final.GetTimeFilterString() != "All Time" {
+		t.Errorf("expected the time filter to stay at All Time, got %s", final.GetTimeFilterString())
+	}
+}
+
+// TestProgram_RefreshIntervalKeys tests changing the refresh interval at runtime, clamped to the minimum
+func TestProgram_RefreshIntervalKeys(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetMinRefreshInterval(2 * time.Second)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("?: Legend"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	// Halving 2.5s would go below the minimum, so it stops at 2s
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("now every 2s"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Millisecond*500),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	final := tm.FinalModel(t).(*tui.ViewModel)
+	if final.RefreshInterval() != 4*time.Second {
+		t.Errorf("expected a 4s refresh interval, got %v", final.RefreshInterval())
+	}
+}
+
+func TestProgram_FixedClock(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected string
+	}{
+		{
+			name:     "two hours into the block",
+			at:       time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC),
+			expected: "Time remaining: 2h",
+		},
+		{
+			name:     "last minutes of the block",
+			at:       time.Date(2025, 7, 1, 9, 45, 0, 0, time.UTC),
+			expected: "Time remaining: 15m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := entity.NewFixedClock(tt.at)
+			block, err := tui.NewCurrentBlock("5am", time.UTC, clock.Now(), 7000)
+			if err != nil {
+				t.Fatalf("NewCurrentBlock() error = %v", err)
+			}
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			statsRepo := testutil.NewMockStatsRepository(apiRepo)
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, &block, 5*time.Second)
+			model.SetClock(clock)
+
+			tm := teatest.NewTestModel(
+				t, model,
+				teatest.WithInitialTermSize(160, 40),
+			)
+
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte(tt.expected)) && bytes.Contains(bts, []byte("At: "+tt.at.Format("15:04:05 2006-01-02")))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Millisecond*500),
+			)
+
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+			tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+		})
+	}
+}
+
+func TestProgram_ProjectFilter(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-web", now.Add(-3*time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01).WithLabels(map[string]string{"project": "web"}),
+		testutil.CreateTestAPIRequest("session-api", now.Add(-2*time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01).WithLabels(map[string]string{"project": "api"}),
+		testutil.CreateTestAPIRequest("session-none", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetProjectLabel("project", usecase.NewListLabelValuesQuery(getFilteredQuery))
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Project: all projects")) && bytes.Contains(bts, []byte("p=project"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	// Projects cycle in name order, so the first one is "api"
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Project: api"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	final := tm.FinalModel(t).(*tui.ViewModel)
+	if final.Project() != "api" {
+		t.Errorf("expected project api, got %q", final.Project())
+	}
+	requests := final.Requests()
+	if len(requests) != 1 || requests[0].SessionID() != "session-api" {
+		t.Errorf("expected only the api request to be listed, got %d requests", len(requests))
+	}
+	// Stats keep covering every project
+	if final.Stats().TotalRequests() != 3 {
+		t.Errorf("expected stats over 3 requests, got %d", final.Stats().TotalRequests())
+	}
+}
+
+// countingAPIRequestRepository records how often the requests of a period are read
+type countingAPIRequestRepository struct {
+	*testutil.MockAPIRequestRepository
+	reads atomic.Int32
+}
+
+func (r *countingAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
+	r.reads.Add(1)
+	return r.MockAPIRequestRepository.FindByPeriodWithLimit(period, limit, offset)
+}
+
+// TestProgram_ProjectFilterRefresh tests the projects are detected again only when the time filter changes,
+// not on every refresh tick
+func TestProgram_ProjectFilterRefresh(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	projectsRepo := &countingAPIRequestRepository{MockAPIRequestRepository: apiRepo}
+	cache := &countingStatsCache{RefreshStatsCache: service.NewRefreshStatsCache()}
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, cache)
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 100*time.Millisecond)
+	model.SetStatsCache(cache)
+	model.SetProjectLabel("project", usecase.NewListLabelValuesQuery(usecase.NewGetFilteredApiRequestsQuery(projectsRepo)))
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return cache.invalidations.Load() >= 3
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second*2),
+	)
+	if reads := projectsRepo.reads.Load(); reads != 1 {
+		t.Errorf("expected the projects to be detected once across refresh ticks, got %d", reads)
+	}
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return projectsRepo.reads.Load() >= 2
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+}
+
+func TestProgram_ProjectFilterWithoutProjects(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getUsageQuery := CreateTestUsageQuery()
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 5*time.Second)
+	model.SetProjectLabel("project", usecase.NewListLabelValuesQuery(getFilteredQuery))
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Project: all"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	// Nothing to cycle through, the filter stays on all
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	time.Sleep(100 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	final := tm.FinalModel(t).(*tui.ViewModel)
+	if final.Project() != "" {
+		t.Errorf("expected no active project, got %q", final.Project())
+	}
+}
+
+func TestProgram_FreshnessFooter(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	tests := []struct {
+		name       string
+		latest     time.Duration // Age of the only stored request
+		staleAfter time.Duration
+		wantStale  bool
+	}{
+		{
+			name:       "recent request",
+			latest:     5 * time.Minute,
+			staleAfter: time.Hour,
+		},
+		{
+			name:       "request older than the threshold",
+			latest:     2 * time.Hour,
+			staleAfter: time.Hour,
+			wantStale:  true,
+		},
+		{
+			name:       "threshold disabled",
+			latest:     48 * time.Hour,
+			staleAfter: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			apiRepo.SetMockData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", time.Now().UTC().Add(-tt.latest), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+			})
+			statsRepo := testutil.NewMockStatsRepository(apiRepo)
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), nil, time.UTC, nil, 5*time.Second)
+			model.SetFreshness(usecase.NewGetLatestRequestTimeQuery(getFilteredQuery), tt.staleAfter)
+
+			tm := teatest.NewTestModel(
+				t, model,
+				teatest.WithInitialTermSize(160, 40),
+			)
+
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte("Latest request: ")) && bytes.Contains(bts, []byte(" ago)"))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Second),
+			)
+
+			time.Sleep(100 * time.Millisecond)
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+			tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+			final := tm.FinalModel(t).(*tui.ViewModel)
+			if final.RefreshError() != nil {
+				t.Errorf("expected no refresh error, got %v", final.RefreshError())
+			}
+			if final.IsDataStale() != tt.wantStale {
+				t.Errorf("expected stale = %v, got %v", tt.wantStale, final.IsDataStale())
+			}
+		})
+	}
+}
+
+func TestProgram_FreshnessFooterRefreshFailed(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetError(&testutil.MockError{Message: "server unavailable"})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), nil, time.UTC, nil, 5*time.Second)
+	model.SetFreshness(usecase.NewGetLatestRequestTimeQuery(getFilteredQuery), time.Hour)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return bytes.Contains(bts, []byte("Refresh failed: server unavailable (data source unreachable, retrying)")) && bytes.Contains(bts, []byte("Updated: never"))
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	final := tm.FinalModel(t).(*tui.ViewModel)
+	if final.RefreshError() == nil {
+		t.Error("expected the failed refresh to be kept")
+	}
+}
+
+// streamingStatsRepository pushes fixed stats to every subscription and records the watched periods
+type streamingStatsRepository struct {
+	stats  entity.Stats
+	err    error
+	mutex  sync.Mutex
+	params []usecase.WatchStatsParams
+}
+
+func (r *streamingStatsRepository) WatchStats(ctx context.Context, params usecase.WatchStatsParams, onUpdate func(entity.Stats)) error {
+	r.mutex.Lock()
+	r.params = append(r.params, params)
+	r.mutex.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	onUpdate(r.stats)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (r *streamingStatsRepository) watched() []usecase.WatchStatsParams {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]usecase.WatchStatsParams(nil), r.params...)
+}
+
+func TestProgram_StreamStats(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	pushed := entity.NewStats(0, 42, entity.Token{}, entity.NewToken(4200, 2100, 0, 0), entity.Cost{}, entity.NewCost(12.34), entity.NewAllTimePeriod(now))
+
+	tests := []struct {
+		name          string
+		err           error
+		wantStreaming bool
+		wantOutput    string
+	}{
+		{
+			name:          "renders pushed stats",
+			wantStreaming: true,
+			wantOutput:    "12.34",
+		},
+		{
+			name:       "falls back to querying when the stream fails",
+			err:        &testutil.MockError{Message: "unknown method StreamStats"},
+			wantOutput: "0.01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			apiRepo.SetMockData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", now.Add(-5*time.Minute), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+			})
+			statsRepo := testutil.NewMockStatsRepository(apiRepo)
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			streamRepo := &streamingStatsRepository{stats: pushed, err: tt.err}
+
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), nil, time.UTC, nil, 5*time.Second)
+			model.SetWatchStatsQuery(usecase.NewWatchStatsQuery(streamRepo))
+
+			tm := teatest.NewTestModel(
+				t, model,
+				teatest.WithInitialTermSize(160, 40),
+			)
+
+			teatest.WaitFor(
+				t, tm.Output(),
+				func(bts []byte) bool {
+					return bytes.Contains(bts, []byte(tt.wantOutput))
+				},
+				teatest.WithCheckInterval(time.Millisecond*50),
+				teatest.WithDuration(time.Second),
+			)
+
+			// Selecting another period subscribes to it
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+			time.Sleep(200 * time.Millisecond)
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+			tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+			final := tm.FinalModel(t).(*tui.ViewModel)
+			if final.IsStreamingStats() != tt.wantStreaming {
+				t.Errorf("expected streaming = %v, got %v", tt.wantStreaming, final.IsStreamingStats())
+			}
+
+			watched := streamRepo.watched()
+			if len(watched) < 2 {
+				t.Fatalf("expected a subscription per selected period, got %v", watched)
+			}
+			if watched[0] != (usecase.WatchStatsParams{}) {
+				t.Errorf("expected the all time period first, got %+v", watched[0])
+			}
+			if last := watched[len(watched)-1]; last.Window != time.Hour {
+				t.Errorf("expected the hour filter to watch a 1h window, got %+v", last)
+			}
+		})
+	}
+}
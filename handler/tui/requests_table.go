@@ -18,16 +18,27 @@ var (
 		Foreground(lipgloss.Color("241"))
 )
 
+// costShareColumnWidth fits "Share" and "100%"
+const costShareColumnWidth = 5
+
 // RequestsTableModel handles the requests table display and interaction and owns its data
 type RequestsTableModel struct {
 	// Data ownership
 	table    table.Model
-	requests []entity.APIRequest
+	fetched  []entity.APIRequest // Requests as returned by the query
+	requests []entity.APIRequest // Requests shown after the view filter
+	hidden   int                 // Requests hidden by the minimum cost filter
+	minCost  entity.Cost
+	total    entity.Cost // Cost of the whole period, for the share column
 
 	// Configuration
-	timezone *time.Location
-	width    int
-	height   int
+	timezone    *time.Location
+	timeDisplay TimeDisplayMode
+	costDisplay CostDisplayMode
+	costShare   bool // Show each request's share of the period cost
+	modelNames  entity.ModelNameMap
+	width       int
+	height      int
 
 	// Business logic dependencies
 	getFilteredQuery *usecase.GetFilteredApiRequestsQuery
@@ -82,10 +93,9 @@ func (m *RequestsTableModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 	case ResizeMsg:
 		m.SetSize(msg.Width, msg.Height)
 	case RequestsRefreshMsg:
-		return m, m.refreshRequests(msg.Period, msg.SortOrder)
+		return m, m.refreshRequests(msg.Period, msg.SortOrder, msg.Labels, msg.Duration)
 	case RequestsDataMsg:
-		m.requests = msg.Requests
-		m.updateTableRows()
+		m.UpdateRequests(msg.Requests)
 	case tea.KeyMsg:
 		// Handle table navigation
 		m.table, cmd = m.table.Update(msg)
@@ -119,12 +129,63 @@ func (m *RequestsTableModel) SetSize(width, height int) {
 	m.adjustTableHeight()
 }
 
+// SetTimeDisplayMode changes the timezone used for the Time column and re-renders rows
+func (m *RequestsTableModel) SetTimeDisplayMode(mode TimeDisplayMode) {
+	m.timeDisplay = mode
+	m.updateTableRows()
+}
+
+// SetCostDisplayMode switches the cost column between per request costs and the running total
+func (m *RequestsTableModel) SetCostDisplayMode(mode CostDisplayMode) {
+	m.costDisplay = mode
+	m.resizeTableColumns()
+}
+
+// SetModelNames shows the configured display names in the model column; unmapped models keep the raw name
+func (m *RequestsTableModel) SetModelNames(modelNames entity.ModelNameMap) {
+	m.modelNames = modelNames
+	m.updateTableRows()
+}
+
+// SetCostShare adds or removes the column showing each request's share of the period cost
+func (m *RequestsTableModel) SetCostShare(enabled bool) {
+	m.costShare = enabled
+	m.resizeTableColumns()
+}
+
+// SetPeriodCost sets the period's total cost the share column is relative to
+func (m *RequestsTableModel) SetPeriodCost(total entity.Cost) {
+	m.total = total
+	m.updateTableRows()
+}
+
 // UpdateRequests updates the requests data
 func (m *RequestsTableModel) UpdateRequests(requests []entity.APIRequest) {
-	m.requests = requests
+	m.fetched = requests
+	m.applyViewFilter()
+}
+
+// SetMinCost hides requests cheaper than the threshold without refetching; zero shows all
+func (m *RequestsTableModel) SetMinCost(minCost entity.Cost) {
+	m.minCost = minCost
+	m.applyViewFilter()
+}
+
+// applyViewFilter rebuilds the visible rows from the fetched requests
+func (m *RequestsTableModel) applyViewFilter() {
+	m.requests, m.hidden = entity.FilterByMinCost(m.fetched, m.minCost)
 	m.updateTableRows()
 }
 
+// SelectedRequest returns the request under the table cursor
+func (m *RequestsTableModel) SelectedRequest() (entity.APIRequest, bool) {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.requests) {
+		return entity.APIRequest{}, false
+	}
+	return m.requests[cursor], true
+}
+
 // GetTable returns the underlying table model for integration with other components
 func (m *RequestsTableModel) GetTable() table.Model {
 	return m.table
@@ -148,9 +209,17 @@ func (m *RequestsTableModel) Focused() bool {
 // updateTableRows updates the table rows based on current requests data
 func (m *RequestsTableModel) updateTableRows() {
 	rows := make([]table.Row, 0, len(m.requests))
-	for _, req := range m.requests {
-		// Format timestamp in configured timezone
-		timestamp := req.Timestamp().In(m.timezone).Format("15:04:05 2006-01-02")
+	var cumulative []entity.Cost
+	if m.costDisplay == CostDisplayCumulative {
+		cumulative = entity.CumulativeCosts(m.requests)
+	}
+	for i, req := range m.requests {
+		// Format timestamp in configured timezone and/or UTC
+		timestamp := FormatTimestamp(req.Timestamp(), m.timezone, m.timeDisplay)
+		cost := req.Cost()
+		if cumulative != nil {
+			cost = cumulative[i]
+		}
 
 		if m.width < 80 {
 			// Compact mode: combine cache and total tokens
@@ -160,25 +229,31 @@ func (m *RequestsTableModel) updateTableRows() {
 
 			rows = append(rows, table.Row{
 				timestamp,
-				req.Model().String(), // Don't truncate - let auto-width handle it
+				m.modelNames.DisplayName(req.Model()), // Don't truncate - let auto-width handle it
 				FormatNumber(req.Tokens().Input()),
 				FormatNumber(req.Tokens().Output()),
 				cacheAndTotal,
-				FormatCost(req.Cost().Amount()),
+				FormatCost(cost.Amount()),
 				FormatDuration(req.DurationMS()),
 			})
+			if m.costShare {
+				rows[len(rows)-1] = append(rows[len(rows)-1], FormatCostShare(req.Cost().ShareOf(m.total)))
+			}
 		} else {
 			// Normal mode: separate columns
 			rows = append(rows, table.Row{
 				timestamp,
-				req.Model().String(), // Don't truncate - let auto-width handle it
+				m.modelNames.DisplayName(req.Model()), // Don't truncate - let auto-width handle it
 				FormatNumber(req.Tokens().Input()),
 				FormatNumber(req.Tokens().Output()),
 				FormatNumber(req.Tokens().Cache()),
 				FormatNumber(req.Tokens().Total()),
-				FormatCost(req.Cost().Amount()),
+				FormatCost(cost.Amount()),
 				FormatDuration(req.DurationMS()),
 			})
+			if m.costShare {
+				rows[len(rows)-1] = append(rows[len(rows)-1], FormatCostShare(req.Cost().ShareOf(m.total)))
+			}
 		}
 	}
 	m.table.SetRows(rows)
@@ -186,8 +261,12 @@ func (m *RequestsTableModel) updateTableRows() {
 
 // resizeTableColumns resizes table columns based on available width
 func (m *RequestsTableModel) resizeTableColumns() {
-	// Calculate auto-width columns based on available terminal width
-	widths := CalculateTableColumnWidths(m.width)
+	// Calculate auto-width columns based on available terminal width, leaving room for the share column
+	available := m.width
+	if m.costShare {
+		available -= costShareColumnWidth + 2
+	}
+	widths := CalculateTableColumnWidths(available)
 
 	// Ensure we have the expected number of width values
 	if len(widths) < 8 {
@@ -195,8 +274,13 @@ func (m *RequestsTableModel) resizeTableColumns() {
 		widths = []int{16, 20, 6, 6, 6, 6, 8, 8}
 	}
 
-	// Define column titles based on available width
+	// Define column titles based on available width, marking running totals with Σ
+	// in a title short enough for the narrowest cost column
 	var columns []table.Column
+	costTitle, compactCostTitle := "Cost ($)", "Cost"
+	if m.costDisplay == CostDisplayCumulative {
+		costTitle, compactCostTitle = "Σ Cost", "Σ Cost"
+	}
 	if m.width < 80 {
 		// Compact layout for narrow terminals - shorter titles
 		// Calculate widths for 7 columns by merging cache+total
@@ -206,7 +290,7 @@ func (m *RequestsTableModel) resizeTableColumns() {
 			{Title: "In", Width: widths[2]},
 			{Title: "Out", Width: widths[3]},
 			{Title: "Tot", Width: widths[4] + widths[5]}, // Combine Cache+Total for space
-			{Title: "Cost", Width: widths[6]},
+			{Title: compactCostTitle, Width: widths[6]},
 			{Title: "Dur", Width: widths[7]},
 		}
 	} else {
@@ -218,11 +302,19 @@ func (m *RequestsTableModel) resizeTableColumns() {
 			{Title: "Output", Width: widths[3]},
 			{Title: "Cache", Width: widths[4]},
 			{Title: "Total", Width: widths[5]},
-			{Title: "Cost ($)", Width: widths[6]},
+			{Title: costTitle, Width: widths[6]},
 			{Title: "Duration", Width: widths[7]},
 		}
 	}
 
+	if m.costShare {
+		shareTitle := "Share"
+		if m.width < 80 {
+			shareTitle = "%"
+		}
+		columns = append(columns, table.Column{Title: shareTitle, Width: costShareColumnWidth})
+	}
+
 	// Clear rows before setting new columns to avoid index out of range
 	m.table.SetRows([]table.Row{})
 	m.table.SetColumns(columns)
@@ -240,17 +332,18 @@ func (m *RequestsTableModel) adjustTableHeight() {
 	// - Status: 2 lines (status + newline)
 	// - Stats box: varies (8-12 lines with borders and content)
 	// - Table header: 1 line
+	// - Selected request details: 1 line
 	// - Help text: 2 lines (newline + help)
 	// - Safety margin: 2 lines
 
-	fixedHeight := 9 // Title, status, table header, help, margins
+	fixedHeight := 10 // Title, status, table header, details, help, margins
 
 	// Calculate stats section height more accurately
-	statsHeight := 10 // Conservative estimate for stats box with borders
+	statsHeight := 12 // Conservative estimate for stats box with borders, sessions and tokens per dollar lines
 
 	// For compact stats, reduce height
 	if m.width < 60 {
-		statsHeight = 8 // Compact stats are shorter
+		statsHeight = 10 // Compact stats are shorter
 	}
 
 	// Calculate remaining height for table with safety margin
@@ -267,7 +360,7 @@ func (m *RequestsTableModel) adjustTableHeight() {
 }
 
 // refreshRequests handles data fetching for the requests table model
-func (m *RequestsTableModel) refreshRequests(period entity.Period, sortOrder SortOrder) tea.Cmd {
+func (m *RequestsTableModel) refreshRequests(period entity.Period, sortOrder SortOrder, labels []entity.LabelFilter, duration entity.DurationRange) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		if m.getFilteredQuery == nil {
 			return RequestsDataMsg{Requests: []entity.APIRequest{}}
@@ -275,13 +368,15 @@ func (m *RequestsTableModel) refreshRequests(period entity.Period, sortOrder Sor
 
 		// Query for display requests (limit to 100 for TUI display)
 		displayParams := usecase.GetFilteredApiRequestsParams{
-			Period: period,
-			Limit:  100,
-			Offset: 0,
+			Period:   period,
+			Limit:    100,
+			Offset:   0,
+			Labels:   labels,
+			Duration: duration,
 		}
 		requests, err := m.getFilteredQuery.Execute(context.Background(), displayParams)
 		if err != nil {
-			return RequestsDataMsg{Requests: []entity.APIRequest{}}
+			return RequestsDataMsg{Requests: []entity.APIRequest{}, Err: err}
 		}
 
 		// Apply sorting based on user preference
@@ -306,12 +401,20 @@ func (m *RequestsTableModel) Requests() []entity.APIRequest {
 	return m.requests
 }
 
+// Hidden returns how many fetched requests are hidden by the minimum cost filter
+func (m *RequestsTableModel) Hidden() int {
+	return m.hidden
+}
+
 // Message types for RequestsTableModel
 type RequestsRefreshMsg struct {
 	Period    entity.Period
 	SortOrder SortOrder
+	Labels    []entity.LabelFilter
+	Duration  entity.DurationRange
 }
 
 type RequestsDataMsg struct {
 	Requests []entity.APIRequest
+	Err      error // Failure of the requests query, if any
 }
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/handler/tui"
 	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
@@ -72,7 +73,7 @@ func TestRequestsTable_IntegrationWithViewModel(t *testing.T) {
 			getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
 			// Create the ViewModel (starts on overview tab with requests table)
-			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+			model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 			// Create teatest model
 			tm := teatest.NewTestModel(
@@ -123,7 +124,7 @@ func TestRequestsTable_Navigation(t *testing.T) {
 		periodFactory := service.NewTimePeriodFactory(time.UTC)
 		getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
 
-		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, 10*time.Millisecond)
+		model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, nil, time.UTC, nil, 10*time.Millisecond)
 
 		tm := teatest.NewTestModel(
 			t, model,
@@ -170,3 +171,130 @@ func TestRequestsTable_Navigation(t *testing.T) {
 		tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
 	})
 }
+
+func TestRequestsTable_CumulativeCost(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-30*time.Minute), "claude-3-opus-20240229", 100, 50, 0.125),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-20*time.Minute), "claude-3-opus-20240229", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.05),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), nil, time.UTC, nil, 5*time.Second)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return strings.Contains(string(bts), "Cost ($)") && strings.Contains(string(bts), "0.250000")
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	// The newest request is listed first and carries the total of all three
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return strings.Contains(string(bts), "Σ Cost") && strings.Contains(string(bts), "0.425000") && strings.Contains(string(bts), "0.375000")
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	if model.CostDisplayMode() != tui.CostDisplayCumulative {
+		t.Errorf("Expected cumulative cost display, got %v", model.CostDisplayMode())
+	}
+}
+
+func TestRequestsTable_CostShare(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-30*time.Minute), "claude-3-opus-20240229", 100, 50, 0.125),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-20*time.Minute), "claude-3-opus-20240229", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.05),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), nil, time.UTC, nil, 5*time.Second)
+	model.SetCostShare(true)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	// Each request's cost relative to the $0.425 of the period
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			output := string(bts)
+			return strings.Contains(output, "Share") && strings.Contains(output, "59%") &&
+				strings.Contains(output, "29%") && strings.Contains(output, "12%")
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+}
+
+func TestRequestsTable_ModelNames(t *testing.T) {
+	t.Parallel()
+	setupTestEnvironment()
+
+	now := time.Now().UTC()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-20*time.Minute), "claude-3-opus-20240229", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.05),
+	})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), nil, time.UTC, nil, 5*time.Second)
+	model.SetModelNames(entity.NewModelNameMap(entity.NewModelDisplayName("claude-3-opus-*", "Opus 3")))
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(160, 40),
+	)
+
+	// Mapped models show their display name, unmapped ones keep the raw name
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			output := string(bts)
+			return strings.Contains(output, "Opus 3") && strings.Contains(output, "claude-3-haiku-20240307") &&
+				!strings.Contains(output, "claude-3-opus-20240229")
+		},
+		teatest.WithCheckInterval(time.Millisecond*50),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// SnapshotWidth is the width a stats snapshot is rendered at, so it has the same layout on any terminal
+const SnapshotWidth = 100
+
+// RenderStatsSnapshot renders the all-time usage statistics of the monitor once, as plain text without
+// colors at SnapshotWidth, e.g. to paste into an issue or a log
+func RenderStatsSnapshot(calculateStatsQuery *usecase.CalculateStatsQuery, countSessionsQuery *usecase.CountSessionsQuery, dailyBudgetQuery *usecase.GetDailyBudgetQuery, cacheSavingsQuery *usecase.CalculateCacheSavingsQuery, longestGapQuery *usecase.GetLongestGapQuery, monitorConfig MonitorConfig) (string, error) {
+	timezone, err := time.LoadLocation(monitorConfig.Timezone)
+	if err != nil {
+		return "", fmt.Errorf("failed to load timezone %s: %w", monitorConfig.Timezone, err)
+	}
+
+	// Configure the same number rendering as the monitor
+	SetNumberLocale(monitorConfig.NumberLocale)
+	SetTokenDecimals(monitorConfig.TokenDecimals)
+	SetPercentDecimals(monitorConfig.PercentDecimals)
+
+	statsColumns, err := ParseStatsColumns(monitorConfig.StatsColumns)
+	if err != nil {
+		return "", fmt.Errorf("invalid stats columns: %w", err)
+	}
+	alignRight, err := ParseStatsAlign(monitorConfig.StatsAlign)
+	if err != nil {
+		return "", fmt.Errorf("invalid stats alignment: %w", err)
+	}
+
+	var blockGracePeriod time.Duration
+	if monitorConfig.BlockGracePeriod != "" {
+		blockGracePeriod, err = time.ParseDuration(monitorConfig.BlockGracePeriod)
+		if err != nil {
+			return "", fmt.Errorf("invalid block grace period format %s: %w", monitorConfig.BlockGracePeriod, err)
+		}
+	}
+
+	clock := monitorConfig.Clock
+	if clock == nil {
+		clock = entity.SystemClock{}
+	}
+	block, err := newMonitorBlock(monitorConfig, timezone, clock)
+	if err != nil {
+		return "", err
+	}
+
+	stats := NewStatsModel(calculateStatsQuery, countSessionsQuery, timezone, block)
+	stats.SetLayout(statsColumns, alignRight)
+	stats.SetHideZeroRows(monitorConfig.HideZeroRows)
+	stats.SetNegligibleCost(entity.NewCost(monitorConfig.NegligibleCost))
+	stats.SetDailyBudgetQuery(dailyBudgetQuery)
+	stats.SetCacheSavingsQuery(cacheSavingsQuery)
+	stats.SetLongestGapQuery(longestGapQuery)
+	stats.SetClock(clock)
+	stats.SetShowPreviousBlock(monitorConfig.ShowPreviousBlock)
+	stats.SetBlockGracePeriod(blockGracePeriod)
+	stats.SetDurationStyle(monitorConfig.DurationStyle)
+	stats.SetSoftLimit(monitorConfig.SoftLimit)
+	stats.SetBaseTokenLimit(monitorConfig.BaseTokenLimit)
+	stats.SetSize(SnapshotWidth, 0)
+
+	// The refresh command runs synchronously, the monitor would receive its message instead
+	data, ok := stats.refreshStats(entity.NewAllTimePeriod(clock.Now().UTC()))().(StatsDataMsg)
+	if !ok {
+		return "", fmt.Errorf("unexpected stats refresh result")
+	}
+	if data.Err != nil {
+		return "", fmt.Errorf("failed to calculate stats: %w", data.Err)
+	}
+	stats.Update(data)
+
+	return plainSnapshot(stats.View()), nil
+}
+
+// plainSnapshot strips the styles from rendered output and the padding left at the end of its lines
+func plainSnapshot(rendered string) string {
+	lines := strings.Split(ansi.Strip(rendered), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
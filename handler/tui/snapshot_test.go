@@ -0,0 +1,106 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestRenderStatsSnapshot(t *testing.T) {
+	// Rendering configures the package-wide number formatting, restore it for the other tests
+	defer tui.SetNumberLocale(entity.DefaultNumberLocale)
+	defer tui.SetTokenDecimals(-1)
+	defer tui.SetPercentDecimals(0)
+
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-2*time.Hour), "claude-3-5-haiku-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-time.Hour), "claude-sonnet-4-20250514", 1000, 500, 1.5),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	countSessionsQuery := usecase.NewCountSessionsQuery(apiRepo, false)
+
+	tests := []struct {
+		name     string
+		config   tui.MonitorConfig
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "stats table",
+			config:   tui.MonitorConfig{Timezone: "UTC"},
+			contains: []string{"Usage Statistics", "Model Tier", "Base (Haiku)", "Premium (S/O)", "Sessions: 2", "Use -b 5am to track token limits"},
+			absent:   []string{"Block Progress"},
+		},
+		{
+			name:     "configured columns",
+			config:   tui.MonitorConfig{Timezone: "UTC", StatsColumns: []string{"cost", "reqs"}},
+			contains: []string{"Cost ($)", "Reqs"},
+			absent:   []string{"Burn Rate"},
+		},
+		{
+			name:     "block progress",
+			config:   tui.MonitorConfig{Timezone: "UTC", BlockTime: "10am", TokenLimit: 7000},
+			contains: []string{"Block Progress", "Premium", "Time remaining: 3h 0m"},
+		},
+		{
+			name:     "configured duration style",
+			config:   tui.MonitorConfig{Timezone: "UTC", BlockTime: "10am", TokenLimit: 7000, DurationStyle: entity.DurationStyleClock},
+			contains: []string{"Time remaining: 3:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Clock = entity.NewFixedClock(now)
+			tt.config.NumberLocale = entity.DefaultNumberLocale
+			tt.config.TokenDecimals = -1
+
+			snapshot, err := tui.RenderStatsSnapshot(calculateStatsQuery, countSessionsQuery, nil, nil, nil, tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Contains(snapshot, "\x1b") {
+				t.Errorf("expected no escape sequences, got %q", snapshot)
+			}
+			for _, line := range strings.Split(snapshot, "\n") {
+				if len([]rune(line)) > tui.SnapshotWidth {
+					t.Errorf("expected lines within %d columns, got %d: %q", tui.SnapshotWidth, len([]rune(line)), line)
+				}
+				if strings.HasSuffix(line, " ") {
+					t.Errorf("expected no trailing spaces, got %q", line)
+				}
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(snapshot, want) {
+					t.Errorf("expected snapshot to contain %q, got:\n%s", want, snapshot)
+				}
+			}
+			for _, unwanted := range tt.absent {
+				if strings.Contains(snapshot, unwanted) {
+					t.Errorf("expected snapshot not to contain %q, got:\n%s", unwanted, snapshot)
+				}
+			}
+
+			// Rendering again gives the same text
+			again, err := tui.RenderStatsSnapshot(calculateStatsQuery, countSessionsQuery, nil, nil, nil, tt.config)
+			if err != nil || again != snapshot {
+				t.Errorf("expected a deterministic snapshot, got:\n%s\nthen:\n%s", snapshot, again)
+			}
+		})
+	}
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		_, err := tui.RenderStatsSnapshot(calculateStatsQuery, countSessionsQuery, nil, nil, nil, tui.MonitorConfig{Timezone: "Mars/Base"})
+		if err == nil || !strings.Contains(err.Error(), "failed to load timezone") {
+			t.Errorf("expected a timezone error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// sparklineLevels are the block characters used from the lowest to the highest value
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Default sparkline configuration: the last 12 hours in hourly buckets
+const (
+	DefaultSparklineInterval = time.Hour
+	DefaultSparklineBuckets  = 12
+)
+
+// RenderSparkline renders values as block characters scaled to the largest value.
+// Zero and negative values, including empty buckets, render as the lowest level.
+func RenderSparkline(values []float64) string {
+	maxValue := 0.0
+	for _, value := range values {
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+
+	var b strings.Builder
+	top := len(sparklineLevels) - 1
+	for _, value := range values {
+		level := 0
+		if maxValue > 0 && value > 0 {
+			level = int(value / maxValue * float64(top))
+			if level > top {
+				level = top
+			}
+		}
+		b.WriteRune(sparklineLevels[level])
+	}
+	return b.String()
+}
+
+// SparklineModel renders a cost trend over recent fixed-size intervals
+type SparklineModel struct {
+	// Data ownership
+	costs []float64 // Oldest bucket first
+
+	// Configuration
+	interval time.Duration
+	buckets  int
+
+	// Business logic dependencies
+	getUsageQuery *usecase.GetUsageQuery
+}
+
+// NewSparklineModel creates a sparkline model; zero buckets disables it
+func NewSparklineModel(getUsageQuery *usecase.GetUsageQuery, interval time.Duration, buckets int) *SparklineModel {
+	return &SparklineModel{
+		interval:      interval,
+		buckets:       buckets,
+		getUsageQuery: getUsageQuery,
+	}
+}
+
+// Init initializes the sparkline model
+func (m *SparklineModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the sparkline model
+func (m *SparklineModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SparklineRefreshMsg:
+		return m, m.refreshSparkline()
+	case SparklineDataMsg:
+		m.costs = msg.Costs
+	}
+	return m, nil
+}
+
+// View renders the sparkline with its range and the total cost it covers
+func (m *SparklineModel) View() string {
+	if !m.Enabled() || len(m.costs) == 0 {
+		return ""
+	}
+
+	total := 0.0
+	for _, cost := range m.costs {
+		total += cost
+	}
+
+	label := fmt.Sprintf("Cost last %d×%s ", m.buckets, formatSparklineInterval(m.interval))
+	return StatusStyle.Render(label) + StatStyle.Render(RenderSparkline(m.costs)) + StatusStyle.Render(" $"+formatDecimal(total, 2))
+}
+
+// SetConfig changes the bucket size and count; zero buckets disables the sparkline
+func (m *SparklineModel) SetConfig(interval time.Duration, buckets int) {
+	m.interval = interval
+	m.buckets = buckets
+	m.costs = nil
+}
+
+// Enabled returns whether the sparkline is configured to render
+func (m *SparklineModel) Enabled() bool {
+	return m.buckets > 0 && m.interval > 0 && m.getUsageQuery != nil
+}
+
+// Costs returns the bucket costs, oldest first
+func (m *SparklineModel) Costs() []float64 {
+	return m.costs
+}
+
+// refreshSparkline handles data fetching for the sparkline model
+func (m *SparklineModel) refreshSparkline() tea.Cmd {
+	if !m.Enabled() {
+		return nil
+	}
+
+	interval, buckets := m.interval, m.buckets
+	return tea.Cmd(func() tea.Msg {
+		usage, err := m.getUsageQuery.ListByInterval(context.Background(), interval, buckets)
+		if err != nil {
+			return SparklineDataMsg{}
+		}
+
+		// Usage is newest first; the sparkline reads left to right in time
+		stats := usage.GetStats()
+		costs := make([]float64, len(stats))
+		for i, stat := range stats {
+			costs[len(stats)-1-i] = stat.TotalCost().Amount()
+		}
+		return SparklineDataMsg{Costs: costs}
+	})
+}
+
+// formatSparklineInterval renders whole-unit intervals compactly (1h instead of 1h0m0s)
+func formatSparklineInterval(interval time.Duration) string {
+	switch {
+	case interval%time.Hour == 0:
+		return fmt.Sprintf("%dh", interval/time.Hour)
+	case interval%time.Minute == 0:
+		return fmt.Sprintf("%dm", interval/time.Minute)
+	default:
+		return interval.String()
+	}
+}
+
+// Message types for SparklineModel
+type SparklineRefreshMsg struct{}
+
+type SparklineDataMsg struct {
+	Costs []float64
+}
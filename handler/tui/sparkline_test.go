@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		expected string
+	}{
+		{
+			name:     "scales to the largest value",
+			values:   []float64{0, 1, 2, 3, 4, 5, 6, 7},
+			expected: "▁▂▃▄▅▆▇█",
+		},
+		{
+			name:     "empty buckets render as the lowest level",
+			values:   []float64{0, 10, 0},
+			expected: "▁█▁",
+		},
+		{
+			name:     "all empty buckets",
+			values:   []float64{0, 0, 0},
+			expected: "▁▁▁",
+		},
+		{
+			name:     "no buckets",
+			values:   nil,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderSparkline(tt.values); got != tt.expected {
+				t.Errorf("RenderSparkline(%v) = %q, want %q", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatSparklineInterval(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		expected string
+	}{
+		{time.Hour, "1h"},
+		{6 * time.Hour, "6h"},
+		{30 * time.Minute, "30m"},
+		{90 * time.Second, "1m30s"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSparklineInterval(tt.interval); got != tt.expected {
+			t.Errorf("formatSparklineInterval(%v) = %q, want %q", tt.interval, got, tt.expected)
+		}
+	}
+}
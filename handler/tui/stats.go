@@ -3,11 +3,15 @@ package tui
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/usecase"
 )
@@ -15,23 +19,68 @@ import (
 // StatsModel handles the rendering of usage statistics and owns its data
 type StatsModel struct {
 	// Data ownership
-	stats      entity.Stats
-	blockStats entity.Stats
-	block      *entity.Block
+	stats              entity.Stats
+	blockStats         entity.Stats
+	previousBlockStats entity.Stats
+	block              *entity.Block
+	sessions           int
+	budget             *usecase.DailyBudget
+	savings            usecase.CacheSavings
+	longestGap         usecase.LongestGap
+	streamed           bool // Period stats are pushed by the server and skipped on refresh
 
 	// Configuration
-	timezone *time.Location
-	width    int
+	timezone    *time.Location
+	timeDisplay TimeDisplayMode
+	width       int
+	columns     []StatsColumn
+	alignRight  bool
+	clock       entity.Clock
+
+	// durationStyle renders the longest gap and block time remaining
+	durationStyle entity.DurationStyle
+
+	// showPreviousBlock adds the final usage of the prior block under the progress bar;
+	// previousBlockStart is the start of the block previousBlockStats were queried for
+	showPreviousBlock  bool
+	previousBlockStart time.Time
+
+	// hideZeroRows leaves out tier rows without requests in the period, the total row always shows
+	hideZeroRows bool
+
+	// negligibleCost is the threshold of the negligible bucket line; 0 hides it
+	negligibleCost entity.Cost
 
 	// Progress bar components
 	progressModel progress.Model
 
+	// softLimit is a percentage of the token limit marked on the progress bar; 0 disables it
+	softLimit         int
+	softProgressModel progress.Model // Used once the soft limit is crossed
+
+	// baseTokenLimit is the block limit of base tier tokens; 0 shows base usage without a bar
+	baseTokenLimit int
+
+	// blockGracePeriod keeps an ended block's progress, dimmed, before rolling to the next block
+	blockGracePeriod   time.Duration
+	graceProgressModel progress.Model // Used while the block is in its grace period
+
+	// firstRequestQuery anchors the block at today's first request, looked up on refresh until one
+	// is found and again on each new day; anchoredDay is the day of the current anchor
+	firstRequestQuery *usecase.GetFirstRequestTimeQuery
+	anchoredDay       time.Time
+
 	// Business logic dependencies
 	calculateStatsQuery *usecase.CalculateStatsQuery
+	countSessionsQuery  *usecase.CountSessionsQuery
+	dailyBudgetQuery    *usecase.GetDailyBudgetQuery
+	cacheSavingsQuery   *usecase.CalculateCacheSavingsQuery
+	longestGapQuery     *usecase.GetLongestGapQuery
+	dashboardQuery      *usecase.GetDashboardQuery
 }
 
 // NewStatsModel creates a new statistics model with usecase dependency
-func NewStatsModel(calculateStatsQuery *usecase.CalculateStatsQuery, timezone *time.Location, block *entity.Block) *StatsModel {
+func NewStatsModel(calculateStatsQuery *usecase.CalculateStatsQuery, countSessionsQuery *usecase.CountSessionsQuery, timezone *time.Location, block *entity.Block) *StatsModel {
 	// Initialize progress model with prettier green to red gradient
 	progressModel := progress.New(
 		progress.WithWidth(40),
@@ -39,14 +88,33 @@ func NewStatsModel(calculateStatsQuery *usecase.CalculateStatsQuery, timezone *t
 		progress.WithoutPercentage(),
 	)
 
+	// Past the soft limit the bar turns amber to red to stand out from the normal gradient
+	softProgressModel := progress.New(
+		progress.WithWidth(40),
+		progress.WithGradient("#f59e0b", "#ef4444"), // Tailwind amber-500 to red-500
+		progress.WithoutPercentage(),
+	)
+
+	// An ended block in its grace period is drawn in gray
+	graceProgressModel := progress.New(
+		progress.WithWidth(40),
+		progress.WithSolidFill("#6b7280"), // Tailwind gray-500
+		progress.WithoutPercentage(),
+	)
+
 	return &StatsModel{
 		stats:               entity.Stats{},
 		blockStats:          entity.Stats{},
 		block:               block,
 		timezone:            timezone,
+		clock:               entity.SystemClock{},
 		width:               120, // Default width
+		columns:             DefaultStatsColumns,
 		progressModel:       progressModel,
+		softProgressModel:   softProgressModel,
+		graceProgressModel:  graceProgressModel,
 		calculateStatsQuery: calculateStatsQuery,
+		countSessionsQuery:  countSessionsQuery,
 	}
 }
 
@@ -63,11 +131,22 @@ func (m *StatsModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 	case StatsRefreshMsg:
 		return m, m.refreshStats(msg.Period)
 	case StatsDataMsg:
-		m.stats = msg.Stats
+		if !msg.Streamed {
+			m.stats = msg.Stats
+		}
 		m.blockStats = msg.BlockStats
+		m.previousBlockStats = msg.PreviousBlockStats
+		m.previousBlockStart = msg.PreviousBlockStart
+		m.sessions = msg.Sessions
+		m.budget = msg.DailyBudget
+		m.savings = msg.CacheSavings
+		m.longestGap = msg.LongestGap
 		if msg.Block != nil {
 			m.block = msg.Block
 		}
+		if !msg.AnchoredDay.IsZero() {
+			m.anchoredDay = msg.AnchoredDay
+		}
 	}
 	return m, nil
 }
@@ -86,16 +165,13 @@ func (m *StatsModel) View() string {
 		return m.renderCompact()
 	}
 
-	// Create table headers
-	headers := []string{"Model Tier", "Reqs", "Limited", "Cache", "Total", "Cost ($)", "Burn Rate"}
-
-	// Calculate dynamic column widths based on available space
-	colWidths := CalculateStatsColumnWidths(availableWidth)
+	// Calculate dynamic column widths based on available space and configured columns
+	colWidths := CalculateStatsColumnWidthsFor(availableWidth, m.columns)
 
 	// Render header row
-	for i, header := range headers {
-		cell := TableHeaderStyle.Render(PadRight(header, colWidths[i]))
-		b.WriteString(cell)
+	b.WriteString(TableHeaderStyle.Render(PadRight("Model Tier", colWidths[0])))
+	for i, column := range m.columns {
+		b.WriteString(TableHeaderStyle.Render(m.padCell(statsColumnSpecs[column].header, colWidths[i+1])))
 	}
 	b.WriteString("\n")
 
@@ -106,42 +182,26 @@ func (m *StatsModel) View() string {
 	b.WriteString("\n")
 
 	// Base (Haiku) row
-	baseRow := []string{
-		BaseStyle.Bold(true).Render("Base (Haiku)"),
-		fmt.Sprintf("%d", m.stats.BaseRequests()),
-		FormatTokenCount(m.stats.BaseTokens().Limited()),
-		FormatTokenCount(m.stats.BaseTokens().Cache()),
-		FormatTokenCount(m.stats.BaseTokens().Total()),
-		fmt.Sprintf("%.6f", m.stats.BaseCost().Amount()),
-		"-", // Base tokens don't count against limits
-	}
-	for i, cell := range baseRow {
-		if i == 0 {
-			b.WriteString(PadRight(cell, colWidths[i]))
-		} else {
-			b.WriteString(BaseStyle.Render(PadRight(cell, colWidths[i])))
-		}
+	if m.showsTierRow(m.stats.BaseRequests()) {
+		m.renderStatsRow(&b, BaseStyle.Bold(true).Render("Base (Haiku)"), BaseStyle, statsRow{
+			requests: m.stats.BaseRequests(),
+			tokens:   m.stats.BaseTokens(),
+			cost:     m.stats.BaseCost(),
+			burnRate: "-", // Base tokens don't count against limits
+		}, colWidths)
+		b.WriteString("\n")
 	}
-	b.WriteString("\n")
 
 	// Premium (S/O) row
-	premiumRow := []string{
-		PremiumStyle.Bold(true).Render("Premium (S/O)"),
-		fmt.Sprintf("%d", m.stats.PremiumRequests()),
-		FormatTokenCount(m.stats.PremiumTokens().Limited()),
-		FormatTokenCount(m.stats.PremiumTokens().Cache()),
-		FormatTokenCount(m.stats.PremiumTokens().Total()),
-		fmt.Sprintf("%.6f", m.stats.PremiumCost().Amount()),
-		FormatBurnRate(m.stats.PremiumTokenBurnRate()),
-	}
-	for i, cell := range premiumRow {
-		if i == 0 {
-			b.WriteString(PadRight(cell, colWidths[i]))
-		} else {
-			b.WriteString(PremiumStyle.Render(PadRight(cell, colWidths[i])))
-		}
+	if m.showsTierRow(m.stats.PremiumRequests()) {
+		m.renderStatsRow(&b, PremiumStyle.Bold(true).Render("Premium (S/O)"), PremiumStyle, statsRow{
+			requests: m.stats.PremiumRequests(),
+			tokens:   m.stats.PremiumTokens(),
+			cost:     m.stats.PremiumCost(),
+			burnRate: FormatBurnRate(m.stats.PremiumTokenBurnRate()),
+		}, colWidths)
+		b.WriteString("\n")
 	}
-	b.WriteString("\n")
 
 	// Separator before total
 	for _, width := range colWidths {
@@ -150,25 +210,40 @@ func (m *StatsModel) View() string {
 	b.WriteString("\n")
 
 	// Total row (burn rate same as premium since base tokens don't count)
-	totalRow := []string{
-		StatStyle.Bold(true).Render("Total"),
-		fmt.Sprintf("%d", m.stats.TotalRequests()),
-		FormatTokenCount(m.stats.TotalTokens().Limited()),
-		FormatTokenCount(m.stats.TotalTokens().Cache()),
-		FormatTokenCount(m.stats.TotalTokens().Total()),
-		fmt.Sprintf("%.6f", m.stats.TotalCost().Amount()),
-		FormatBurnRate(m.stats.PremiumTokenBurnRate()),
-	}
-	for i, cell := range totalRow {
-		if i == 0 {
-			b.WriteString(PadRight(cell, colWidths[i]))
-		} else {
-			b.WriteString(StatStyle.Render(PadRight(cell, colWidths[i])))
-		}
+	m.renderStatsRow(&b, StatStyle.Bold(true).Render("Total"), StatStyle, statsRow{
+		requests: m.stats.TotalRequests(),
+		tokens:   m.stats.TotalTokens(),
+		cost:     m.stats.TotalCost(),
+		burnRate: FormatBurnRate(m.stats.PremiumTokenBurnRate()),
+	}, colWidths)
+
+	// Distinct sessions within the selected period
+	b.WriteString("\n")
+	b.WriteString(StatStyle.Render("Sessions: "))
+	b.WriteString(fmt.Sprintf("%d", m.sessions))
+	b.WriteString(m.renderLongestGap())
+	b.WriteString("\n")
+	b.WriteString(m.renderTokensPerDollar())
+
+	// Explain the daily budget behind @daily_plan_usage
+	if m.budget != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderDailyBudget())
+	}
+
+	// Only priced cache reads are shown, a partial estimate would be misleading
+	if m.savings.Available {
+		b.WriteString("\n")
+		b.WriteString(m.renderCacheSavings())
 	}
 
-	// Add progress bar section if block is configured with limit
-	if m.block != nil && m.block.HasLimit() {
+	if m.showsNegligible() {
+		b.WriteString("\n")
+		b.WriteString(m.renderNegligible())
+	}
+
+	// Add progress bar section if block is configured with a limit for either tier
+	if m.showsBlockProgress() {
 		b.WriteString("\n\n")
 		b.WriteString(m.renderBlockProgress())
 	} else if m.block == nil {
@@ -180,6 +255,65 @@ func (m *StatsModel) View() string {
 	return b.String()
 }
 
+// statsRow holds the values of one model tier row in the stats table
+type statsRow struct {
+	requests int
+	tokens   entity.Token
+	cost     entity.Cost
+	burnRate string
+}
+
+// cell returns the formatted value of the row for a column
+func (r statsRow) cell(column StatsColumn) string {
+	switch column {
+	case StatsColumnRequests:
+		return fmt.Sprintf("%d", r.requests)
+	case StatsColumnLimited:
+		return FormatTokenCount(r.tokens.Limited())
+	case StatsColumnCache:
+		return FormatTokenCount(r.tokens.Cache())
+	case StatsColumnTotal:
+		return FormatTokenCount(r.tokens.Total())
+	case StatsColumnCost:
+		return formatDecimal(r.cost.Amount(), 6)
+	case StatsColumnBurnRate:
+		return r.burnRate
+	default:
+		return ""
+	}
+}
+
+// showsTierRow returns true if a tier row with the number of requests is shown
+func (m *StatsModel) showsTierRow(requests int) bool {
+	return !m.hideZeroRows || requests > 0
+}
+
+// renderStatsRow writes a labelled row with its cells in the configured column order
+func (m *StatsModel) renderStatsRow(b *strings.Builder, label string, style lipgloss.Style, row statsRow, colWidths []int) {
+	b.WriteString(PadRight(label, colWidths[0]))
+	for i, column := range m.columns {
+		b.WriteString(style.Render(m.padCell(row.cell(column), colWidths[i+1])))
+	}
+}
+
+// padCell pads a numeric cell according to the configured alignment
+func (m *StatsModel) padCell(s string, width int) string {
+	if m.alignRight {
+		// Keep a gap so right-aligned values don't run into the previous column
+		return PadLeft(s, width-1) + " "
+	}
+	return PadRight(s, width)
+}
+
+// SetLayout sets the stats table column order and numeric alignment
+func (m *StatsModel) SetLayout(columns []StatsColumn, alignRight bool) {
+	if len(columns) == 0 {
+		columns = DefaultStatsColumns
+	}
+	m.columns = columns
+	m.alignRight = alignRight
+}
+
 // renderCompact renders a compact version of stats for narrow terminals
 func (m *StatsModel) renderCompact() string {
 	var b strings.Builder
@@ -195,20 +329,44 @@ func (m *StatsModel) renderCompact() string {
 	b.WriteString(fmt.Sprintf("%s\n", FormatTokenCount(m.stats.TotalTokens().Total())))
 
 	b.WriteString(StatStyle.Render("Total Cost: "))
-	b.WriteString(fmt.Sprintf("$%.6f\n", m.stats.TotalCost().Amount()))
+	b.WriteString(fmt.Sprintf("$%s\n", formatDecimal(m.stats.TotalCost().Amount(), 6)))
 
-	b.WriteString("\n")
-	b.WriteString(BaseStyle.Render("Base: "))
-	b.WriteString(fmt.Sprintf("%d reqs, %s tokens, $%.6f\n",
-		m.stats.BaseRequests(),
-		FormatTokenCount(m.stats.BaseTokens().Total()),
-		m.stats.BaseCost().Amount()))
-
-	b.WriteString(PremiumStyle.Render("Premium: "))
-	b.WriteString(fmt.Sprintf("%d reqs, %s tokens, $%.6f",
-		m.stats.PremiumRequests(),
-		FormatTokenCount(m.stats.PremiumTokens().Total()),
-		m.stats.PremiumCost().Amount()))
+	b.WriteString(StatStyle.Render("Sessions: "))
+	b.WriteString(fmt.Sprintf("%d", m.sessions))
+	b.WriteString(m.renderLongestGap() + "\n")
+
+	b.WriteString(m.renderTokensPerDollar() + "\n")
+
+	var tiers []string
+	if m.showsTierRow(m.stats.BaseRequests()) {
+		tiers = append(tiers, BaseStyle.Render("Base: ")+fmt.Sprintf("%d reqs, %s tokens, $%s",
+			m.stats.BaseRequests(),
+			FormatTokenCount(m.stats.BaseTokens().Total()),
+			formatDecimal(m.stats.BaseCost().Amount(), 6)))
+	}
+	if m.showsTierRow(m.stats.PremiumRequests()) {
+		tiers = append(tiers, PremiumStyle.Render("Premium: ")+fmt.Sprintf("%d reqs, %s tokens, $%s",
+			m.stats.PremiumRequests(),
+			FormatTokenCount(m.stats.PremiumTokens().Total()),
+			formatDecimal(m.stats.PremiumCost().Amount(), 6)))
+	}
+	if len(tiers) > 0 {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(tiers, "\n"))
+	}
+
+	if m.budget != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderDailyBudget())
+	}
+	if m.savings.Available {
+		b.WriteString("\n")
+		b.WriteString(m.renderCacheSavings())
+	}
+	if m.showsNegligible() {
+		b.WriteString("\n")
+		b.WriteString(m.renderNegligible())
+	}
 
 	// Add burn rate for compact view if not all-time period
 	burnRate := m.stats.PremiumTokenBurnRate()
@@ -218,8 +376,8 @@ func (m *StatsModel) renderCompact() string {
 		b.WriteString(FormatBurnRate(burnRate))
 	}
 
-	// Add progress bar section if block is configured with limit
-	if m.block != nil && m.block.HasLimit() {
+	// Add progress bar section if block is configured with a limit for either tier
+	if m.showsBlockProgress() {
 		b.WriteString("\n\n")
 		b.WriteString(m.renderBlockProgress())
 	} else if m.block == nil {
@@ -231,45 +389,116 @@ func (m *StatsModel) renderCompact() string {
 	return b.String()
 }
 
-// renderBlockProgress renders the block progress bar section
-func (m *StatsModel) renderBlockProgress() string {
-	var b strings.Builder
+// renderDailyBudget renders the daily budget with the plan price and day count it derives from
+func (m *StatsModel) renderDailyBudget() string {
+	if !m.budget.IsPlanSet() {
+		return StatStyle.Render("Daily Budget: ") + HelpStyle.Render("No plan set")
+	}
+
+	return StatStyle.Render("Daily Budget: ") +
+		"$" + formatDecimal(m.budget.Budget.Amount(), 2) +
+		HelpStyle.Render(fmt.Sprintf(" (%s plan $%s / %d days)", m.budget.Plan.Name(), formatDecimal(m.budget.Plan.Price().Amount(), 2), m.budget.DaysInMonth))
+}
 
-	// Calculate progress using Block entity method
-	percentage := m.block.CalculateProgress(m.blockStats.PremiumTokens())
+// renderCacheSavings renders the estimated savings from cache reads in the selected period
+func (m *StatsModel) renderCacheSavings() string {
+	return StatStyle.Render("Cache Savings: ") +
+		"$" + formatDecimal(m.savings.Amount.Amount(), 2) +
+		HelpStyle.Render(" (vs. sending cached tokens as input)")
+}
+
+// showsNegligible returns true if the negligible bucket line is shown
+func (m *StatsModel) showsNegligible() bool {
+	return m.negligibleCost.Amount() > 0
+}
 
-	if percentage > 100 {
-		percentage = 100
+// renderNegligible renders the requests below the negligible cost threshold, which the tier rows also count
+func (m *StatsModel) renderNegligible() string {
+	return StatStyle.Render("Negligible: ") +
+		fmt.Sprintf("%d reqs, %s tokens, $%s",
+			m.stats.NegligibleRequests(),
+			FormatTokenCount(m.stats.NegligibleTokens().Total()),
+			formatDecimal(m.stats.NegligibleCost().Amount(), 6)) +
+		HelpStyle.Render(fmt.Sprintf(" (below $%s each)", strconv.FormatFloat(m.negligibleCost.Amount(), 'f', -1, 64)))
+}
+
+// renderLongestGap renders the longest idle time between requests, shown next to the session count
+func (m *StatsModel) renderLongestGap() string {
+	if m.longestGapQuery == nil {
+		return ""
 	}
 
+	value := "-"
+	if m.longestGap.Available {
+		value = m.durationStyle.Format(m.longestGap.Duration)
+	}
+	return StatStyle.Render("  Longest Gap: ") + value
+}
+
+// renderTokensPerDollar renders how many tokens each dollar bought, "-" when nothing was spent
+func (m *StatsModel) renderTokensPerDollar() string {
+	value := "-"
+	if tokensPerDollar, ok := m.stats.TokensPerDollar(); ok {
+		value = FormatTokenCount(int64(tokensPerDollar))
+	}
+	return StatStyle.Render("Tokens per $: ") + value + m.renderCostPerSession()
+}
+
+// renderCostPerSession renders the average cost of the period's sessions, "$0.0" without sessions
+func (m *StatsModel) renderCostPerSession() string {
+	value := "$" + formatDecimal(0, 1)
+	if costPerSession, ok := m.stats.CostPerSession(m.sessions); ok {
+		value = "$" + formatDecimal(costPerSession.Amount(), 2)
+	}
+	return StatStyle.Render("  Cost per Session: ") + value
+}
+
+// renderBlockProgress renders the block progress bar section
+func (m *StatsModel) renderBlockProgress() string {
+	var b strings.Builder
+
 	// Calculate time remaining until next block
-	now := time.Now().UTC()
+	now := m.clock.Now().UTC()
 	var timeRemaining time.Duration
 	if now.Before(m.block.EndAt()) {
 		timeRemaining = m.block.EndAt().Sub(now)
 	}
+	inGrace := m.block.InGracePeriod(now, m.blockGracePeriod)
 
 	// Block header
 	blockTime := ""
 	if m.block != nil {
-		blockTime = FormatBlockTime(*m.block, m.timezone)
+		blockTime = FormatBlockTimeWithMode(*m.block, m.timezone, m.timeDisplay)
 	}
 	b.WriteString(HeaderStyle.Render(fmt.Sprintf("Block Progress (%s)", blockTime)))
 	b.WriteString("\n\n")
 
-	// Progress bar using calculated percentage
-
-	progressBar := "[" + m.progressModel.ViewAs(percentage/100) + "]"
-	b.WriteString(progressBar)
-	b.WriteString(" ")
-	used := m.blockStats.PremiumTokens().Limited()
-	limit := int64(m.block.TokenLimit())
-	b.WriteString(StatStyle.Render(fmt.Sprintf("%.1f%% (%s/%s tokens)", percentage, FormatTokenCount(used), FormatTokenCount(limit))))
+	// One bar per tier, each against its own limit
+	premiumUsed := m.block.UsedTokens(m.blockStats.PremiumTokens())
+	premiumLimit := int64(m.block.TokenLimit())
+	renderPremiumBar, renderBaseBar := m.renderProgressBar, func(percentage float64) string {
+		return m.progressModel.ViewAs(percentage / 100)
+	}
+	if inGrace {
+		renderPremiumBar, renderBaseBar = m.renderGraceProgressBar, m.renderGraceProgressBar
+	}
+	b.WriteString(m.renderTierProgress("Premium", premiumUsed, premiumLimit, renderPremiumBar))
+	b.WriteString("\n")
+	baseUsed := m.blockStats.BaseTokens().Limited()
+	b.WriteString(m.renderTierProgress("Base", baseUsed, int64(m.baseTokenLimit), renderBaseBar))
 	b.WriteString("\n")
 
+	if m.showPreviousBlock {
+		b.WriteString(m.renderPreviousBlock())
+		b.WriteString("\n")
+	}
+
 	// Time remaining
 	if timeRemaining > 0 {
-		b.WriteString(HelpStyle.Render(fmt.Sprintf("Time remaining: %s", FormatDurationFromTime(timeRemaining))))
+		b.WriteString(HelpStyle.Render(fmt.Sprintf("Time remaining: %s", m.durationStyle.Format(timeRemaining))))
+	} else if inGrace {
+		graceRemaining := m.block.EndAt().Add(m.blockGracePeriod).Sub(now)
+		b.WriteString(HelpStyle.Render(fmt.Sprintf("Block ended, next block in %s", m.durationStyle.Format(graceRemaining))))
 	} else {
 		b.WriteString(HelpStyle.Render("Block expired"))
 	}
@@ -277,48 +506,320 @@ func (m *StatsModel) renderBlockProgress() string {
 	return b.String()
 }
 
+// showsBlockProgress returns true if the block is configured with a limit for either tier
+func (m *StatsModel) showsBlockProgress() bool {
+	return m.block != nil && (m.block.HasLimit() || m.baseTokenLimit > 0)
+}
+
+// extraBlockProgressLines returns how many lines the block progress adds beyond a single bar,
+// so the requests table can leave room for the base tier line
+func (m *StatsModel) extraBlockProgressLines() int {
+	if m.showsBlockProgress() {
+		return 1
+	}
+	return 0
+}
+
+// renderTierProgress renders one labeled tier line of the block progress. With a limit it shows a bar
+// drawn by renderBar and the percentage used, without one only the token usage.
+func (m *StatsModel) renderTierProgress(label string, used, limit int64, renderBar func(percentage float64) string) string {
+	label = fmt.Sprintf("%-8s", label)
+	if limit <= 0 {
+		return label + StatStyle.Render(fmt.Sprintf("%s tokens (no limit)", FormatTokenCount(used)))
+	}
+
+	percentage := float64(used) / float64(limit) * 100
+	barPercentage := min(percentage, 100)
+	return label + "[" + renderBar(barPercentage) + "] " + StatStyle.Render(fmt.Sprintf("%.1f%% (%s/%s tokens)", barPercentage, FormatTokenCount(used), FormatTokenCount(limit)))
+}
+
+// renderProgressBar renders the premium block progress bar, marking the soft limit and
+// switching to the soft limit colors once it is crossed
+func (m *StatsModel) renderProgressBar(percentage float64) string {
+	bar := m.progressBarModel(percentage)
+	rendered := bar.ViewAs(percentage / 100)
+	if m.softLimit <= 0 {
+		return rendered
+	}
+
+	// Replace the cell at the soft limit position with the marker
+	width := bar.Width
+	position := min(int(math.Round(float64(m.softLimit)/100*float64(width))), width-1)
+	return ansi.Cut(rendered, 0, position) + SoftLimitStyle.Render("│") + ansi.Cut(rendered, position+1, width)
+}
+
+// renderGraceProgressBar renders a dimmed progress bar for a block that ended but is within its grace period
+func (m *StatsModel) renderGraceProgressBar(percentage float64) string {
+	return m.graceProgressModel.ViewAs(percentage / 100)
+}
+
+// progressBarModel returns the progress bar colored for the percentage relative to the soft limit
+func (m *StatsModel) progressBarModel(percentage float64) *progress.Model {
+	if m.softLimit > 0 && percentage >= float64(m.softLimit) {
+		return &m.softProgressModel
+	}
+	return &m.progressModel
+}
+
+// renderPreviousBlock renders the final usage of the block before the current one as a dimmed line
+func (m *StatsModel) renderPreviousBlock() string {
+	previous := m.block.PreviousBlock()
+	blockTime := FormatBlockTimeWithMode(previous, m.timezone, m.timeDisplay)
+	used := previous.UsedTokens(m.previousBlockStats.PremiumTokens())
+	percentage := previous.CalculateProgress(m.previousBlockStats.PremiumTokens())
+
+	return HelpStyle.Render(fmt.Sprintf("Previous block (%s): %.1f%% (%s/%s tokens)",
+		blockTime, percentage, FormatTokenCount(used), FormatTokenCount(int64(previous.TokenLimit()))))
+}
+
 // SetSize updates the model size
 func (m *StatsModel) SetSize(width, height int) {
 	m.width = width
 }
 
+// SetDailyBudgetQuery enables the daily budget line below the stats table
+func (m *StatsModel) SetDailyBudgetQuery(query *usecase.GetDailyBudgetQuery) {
+	m.dailyBudgetQuery = query
+}
+
+// SetCacheSavingsQuery enables the cache savings line below the stats table
+func (m *StatsModel) SetCacheSavingsQuery(query *usecase.CalculateCacheSavingsQuery) {
+	m.cacheSavingsQuery = query
+}
+
+// SetLongestGapQuery enables the longest gap between requests next to the session count
+func (m *StatsModel) SetLongestGapQuery(query *usecase.GetLongestGapQuery) {
+	m.longestGapQuery = query
+}
+
+// SetDashboardQuery fetches the period and block stats together instead of one query each
+func (m *StatsModel) SetDashboardQuery(query *usecase.GetDashboardQuery) {
+	m.dashboardQuery = query
+}
+
+// SetSoftLimit marks a percentage of the token limit on the block progress bar; 0 disables it
+func (m *StatsModel) SetSoftLimit(percent int) {
+	m.softLimit = percent
+}
+
+// SetBaseTokenLimit draws the base tier usage of the block as a bar against the limit; 0 shows it without a bar
+func (m *StatsModel) SetBaseTokenLimit(limit int) {
+	m.baseTokenLimit = limit
+}
+
+// forgetPreviousBlock queries the previous block's stats again on the next refresh, e.g. after
+// a model tier change reclassified its requests
+func (m *StatsModel) forgetPreviousBlock() {
+	m.previousBlockStart = time.Time{}
+}
+
+// SetShowPreviousBlock enables the previous block's final usage under the block progress bar
+func (m *StatsModel) SetShowPreviousBlock(enabled bool) {
+	m.showPreviousBlock = enabled
+}
+
+// SetHideZeroRows leaves out the tier rows without requests in the period
+func (m *StatsModel) SetHideZeroRows(hide bool) {
+	m.hideZeroRows = hide
+}
+
+// SetNegligibleCost shows the requests below the threshold as a line under the stats table; 0 hides it
+func (m *StatsModel) SetNegligibleCost(threshold entity.Cost) {
+	m.negligibleCost = threshold
+}
+
+// SetBlockGracePeriod keeps an ended block's progress, dimmed, for the grace period before rolling
+// to the next block; 0 rolls over at the block end
+func (m *StatsModel) SetBlockGracePeriod(grace time.Duration) {
+	m.blockGracePeriod = grace
+}
+
+// SetFirstRequestQuery infers the block from today's first request on refresh, re-anchoring it
+// once the first request of a new day is made
+func (m *StatsModel) SetFirstRequestQuery(query *usecase.GetFirstRequestTimeQuery) {
+	m.firstRequestQuery = query
+}
+
+// inferBlock returns the block anchored at today's first request, unless the block is already
+// anchored today or no request was made yet
+func (m *StatsModel) inferBlock(block entity.Block, anchoredDay time.Time) (entity.Block, time.Time, bool) {
+	now := m.clock.Now()
+	local := now.In(m.timezone)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, m.timezone)
+	if day.Equal(anchoredDay) {
+		return block, anchoredDay, false
+	}
+
+	firstRequestAt, found, err := m.firstRequestQuery.Execute(context.Background(), usecase.GetFirstRequestTimeParams{
+		Period: entity.NewPeriod(day.UTC(), now),
+	})
+	if err != nil || !found {
+		return block, anchoredDay, false
+	}
+
+	inferred := entity.InferBlock(firstRequestAt, m.timezone, now, block.TokenLimit()).WithTokenMetric(block.TokenMetric())
+	return inferred, day, true
+}
+
+// SetDurationStyle sets how the longest gap and block time remaining are rendered
+func (m *StatsModel) SetDurationStyle(style entity.DurationStyle) {
+	m.durationStyle = style
+}
+
+// SetClock sets the source of the current time for block progress
+func (m *StatsModel) SetClock(clock entity.Clock) {
+	m.clock = clock
+}
+
+// SetTimeDisplayMode changes the timezone used for the block time range
+func (m *StatsModel) SetTimeDisplayMode(mode TimeDisplayMode) {
+	m.timeDisplay = mode
+}
+
+// setStreamed sets whether the period stats are pushed by the server rather than refreshed
+func (m *StatsModel) setStreamed(streamed bool) {
+	m.streamed = streamed
+}
+
+// setStreamedStats shows the period stats pushed by the server, skipping them on later refreshes
+func (m *StatsModel) setStreamedStats(stats entity.Stats) {
+	m.stats = stats
+	m.streamed = true
+}
+
+// fetchStats returns the stats of the period and of the block, when block tracking is enabled.
+// The error reports a failed period query, whose stats are then empty.
+func (m *StatsModel) fetchStats(period entity.Period, block *entity.Block) (entity.Stats, entity.Stats, error) {
+	// A dashboard query gathers both, falling back to one query each when it fails, e.g. on the plan
+	if m.dashboardQuery != nil {
+		dashboard, err := m.dashboardQuery.Execute(context.Background(), usecase.GetDashboardParams{
+			Period: period,
+			Block:  block,
+		})
+		if err == nil {
+			return dashboard.Stats, dashboard.BlockStats, nil
+		}
+	}
+
+	// Calculate filtered stats for display
+	statsParams := usecase.CalculateStatsParams{Period: period}
+	stats, statsErr := m.calculateStatsQuery.Execute(context.Background(), statsParams)
+	if statsErr != nil {
+		stats = entity.Stats{}
+	}
+
+	// Calculate block stats for progress bar (only when block tracking is enabled)
+	return stats, m.fetchBlockStats(block), statsErr
+}
+
+// fetchBlockStats returns the stats of the block, when block tracking is enabled
+func (m *StatsModel) fetchBlockStats(block *entity.Block) entity.Stats {
+	if block == nil {
+		return entity.Stats{}
+	}
+
+	stats, err := m.calculateStatsQuery.Execute(context.Background(), usecase.CalculateStatsParams{Period: block.Period()})
+	if err != nil {
+		return entity.Stats{}
+	}
+	return stats
+}
+
 // refreshStats handles data fetching for the stats model
 func (m *StatsModel) refreshStats(period entity.Period) tea.Cmd {
+	// Read here, as the command runs outside the update loop
+	streamed := m.streamed
+	anchoredDay := m.anchoredDay
+	previousBlockStats, previousBlockStart := m.previousBlockStats, m.previousBlockStart
+
 	return tea.Cmd(func() tea.Msg {
 		if m.calculateStatsQuery == nil {
 			return StatsDataMsg{Stats: entity.Stats{}, BlockStats: entity.Stats{}, Block: m.block}
 		}
 
-		// Calculate filtered stats for display
-		statsParams := usecase.CalculateStatsParams{Period: period}
-		stats, err := m.calculateStatsQuery.Execute(context.Background(), statsParams)
-		if err != nil {
-			stats = entity.Stats{}
-		}
-
-		// Update block to current time (may advance to next block automatically)
+		// Update block to current time (may advance to next block automatically once the grace period has passed)
 		var currentBlock *entity.Block
+		var newAnchor time.Time
 		if m.block != nil {
-			nextBlock := m.block.NextBlock(time.Now())
+			block := *m.block
+			if m.firstRequestQuery != nil {
+				if inferred, day, ok := m.inferBlock(block, anchoredDay); ok {
+					block, newAnchor = inferred, day
+				}
+			}
+			nextBlock := block.NextBlockAfterGrace(m.clock.Now(), m.blockGracePeriod)
 			currentBlock = &nextBlock
 		}
 
-		// Calculate block stats for progress bar (only when block tracking is enabled)
-		var blockStats entity.Stats
-		if currentBlock != nil && m.calculateStatsQuery != nil {
-			blockStatsParams := usecase.CalculateStatsParams{
-				Period: currentBlock.Period(),
+		// Streamed period stats are already up to date, only the block needs a query
+		var stats, blockStats entity.Stats
+		var statsErr error
+		if streamed {
+			blockStats = m.fetchBlockStats(currentBlock)
+		} else {
+			stats, blockStats, statsErr = m.fetchStats(period, currentBlock)
+		}
+
+		// The previous block is over, so its stats are final and only queried once per block
+		if !m.showPreviousBlock || currentBlock == nil {
+			previousBlockStats, previousBlockStart = entity.Stats{}, time.Time{}
+		} else if previous := currentBlock.PreviousBlock(); !previous.StartAt().Equal(previousBlockStart) {
+			previousBlockStats, previousBlockStart = entity.Stats{}, time.Time{}
+			calculated, err := m.calculateStatsQuery.Execute(context.Background(), usecase.CalculateStatsParams{Period: previous.Period()})
+			if err == nil {
+				previousBlockStats, previousBlockStart = calculated, previous.StartAt()
 			}
-			calculatedBlockStats, err := m.calculateStatsQuery.Execute(context.Background(), blockStatsParams)
+		}
+
+		// Count distinct sessions within the selected period
+		var sessions int
+		if m.countSessionsQuery != nil {
+			count, err := m.countSessionsQuery.Execute(context.Background(), usecase.CountSessionsParams{Period: period})
+			if err == nil {
+				sessions = count
+			}
+		}
+
+		// The budget follows the current month, independent of the selected period
+		var budget *usecase.DailyBudget
+		if m.dailyBudgetQuery != nil {
+			dailyBudget, err := m.dailyBudgetQuery.Execute(context.Background())
 			if err == nil {
-				blockStats = calculatedBlockStats
+				budget = dailyBudget
+			}
+		}
+
+		// Estimate cache savings for the selected period when rates are configured
+		var savings usecase.CacheSavings
+		if m.cacheSavingsQuery != nil && m.cacheSavingsQuery.IsEnabled() {
+			calculated, err := m.cacheSavingsQuery.Execute(context.Background(), usecase.CalculateCacheSavingsParams{Period: period})
+			if err == nil {
+				savings = calculated
+			}
+		}
+
+		// Longest idle time between requests in the selected period
+		var longestGap usecase.LongestGap
+		if m.longestGapQuery != nil {
+			calculated, err := m.longestGapQuery.Execute(context.Background(), usecase.GetLongestGapParams{Period: period})
+			if err == nil {
+				longestGap = calculated
 			}
 		}
 
 		return StatsDataMsg{
-			Stats:      stats,
-			BlockStats: blockStats,
-			Block:      currentBlock,
+			Stats:              stats,
+			BlockStats:         blockStats,
+			PreviousBlockStats: previousBlockStats,
+			PreviousBlockStart: previousBlockStart,
+			Block:              currentBlock,
+			AnchoredDay:        newAnchor,
+			Sessions:           sessions,
+			DailyBudget:        budget,
+			CacheSavings:       savings,
+			LongestGap:         longestGap,
+			Streamed:           streamed,
+			Err:                statsErr,
 		}
 	})
 }
@@ -338,13 +839,32 @@ func (m *StatsModel) Block() *entity.Block {
 	return m.block
 }
 
+// Sessions returns the distinct session count for the selected period
+func (m *StatsModel) Sessions() int {
+	return m.sessions
+}
+
+// DailyBudget returns the current daily budget, nil when not configured
+func (m *StatsModel) DailyBudget() *usecase.DailyBudget {
+	return m.budget
+}
+
 // Message types for StatsModel
 type StatsRefreshMsg struct {
 	Period entity.Period
 }
 
 type StatsDataMsg struct {
-	Stats      entity.Stats
-	BlockStats entity.Stats
-	Block      *entity.Block
+	Stats              entity.Stats
+	BlockStats         entity.Stats
+	PreviousBlockStats entity.Stats
+	PreviousBlockStart time.Time // Start of the block PreviousBlockStats cover, zero when they are unavailable
+	Block              *entity.Block
+	AnchoredDay        time.Time // Day the block was just anchored at the first request of, zero when unchanged
+	Sessions           int
+	DailyBudget        *usecase.DailyBudget
+	CacheSavings       usecase.CacheSavings
+	LongestGap         usecase.LongestGap
+	Streamed           bool  // Stats are left out as the period stats are pushed by the server
+	Err                error // Failure of the period stats query, if any
 }
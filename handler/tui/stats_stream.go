@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// statsStream is an open subscription to the period stats pushed by the server
+type statsStream struct {
+	params  usecase.WatchStatsParams
+	cancel  context.CancelFunc
+	updates chan statsStreamMsg
+}
+
+// statsStreamMsg carries pushed stats, or reports that the subscription closed
+type statsStreamMsg struct {
+	stream *statsStream
+	stats  entity.Stats
+	closed bool
+	err    error
+}
+
+// startStatsStream subscribes in the background until stop is called or the stream fails
+func startStatsStream(query *usecase.WatchStatsQuery, params usecase.WatchStatsParams) *statsStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &statsStream{
+		params:  params,
+		cancel:  cancel,
+		updates: make(chan statsStreamMsg),
+	}
+
+	go func() {
+		defer close(stream.updates)
+
+		err := query.Execute(ctx, params, func(stats entity.Stats) {
+			select {
+			case stream.updates <- statsStreamMsg{stream: stream, stats: stats}:
+			case <-ctx.Done():
+			}
+		})
+
+		select {
+		case stream.updates <- statsStreamMsg{stream: stream, closed: true, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return stream
+}
+
+// next waits for the following update, nil once the subscription is stopped
+func (s *statsStream) next() tea.Msg {
+	msg, ok := <-s.updates
+	if !ok {
+		return nil
+	}
+	return msg
+}
+
+// stop closes the subscription
+func (s *statsStream) stop() {
+	s.cancel()
+}
@@ -0,0 +1,744 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestStatsModel_Layout(t *testing.T) {
+	t.Parallel()
+
+	stats := entity.NewStats(
+		2, 3,
+		entity.NewToken(100, 50, 0, 0), entity.NewToken(1000, 500, 0, 0),
+		entity.NewCost(0.01), entity.NewCost(1.5),
+		entity.NewAllTimePeriod(time.Now()),
+	)
+
+	tests := []struct {
+		name        string
+		columns     []StatsColumn
+		alignRight  bool
+		wantHeaders []string
+		absent      []string
+		rightAlign  bool
+	}{
+		{
+			name:        "default order",
+			wantHeaders: []string{"Model Tier", "Reqs", "Limited", "Cache", "Total", "Cost ($)", "Burn Rate"},
+		},
+		{
+			name:        "cost first subset",
+			columns:     []StatsColumn{StatsColumnCost, StatsColumnRequests, StatsColumnTotal},
+			wantHeaders: []string{"Model Tier", "Cost ($)", "Reqs", "Total"},
+			absent:      []string{"Limited", "Burn Rate"},
+		},
+		{
+			name:        "right aligned",
+			columns:     []StatsColumn{StatsColumnRequests, StatsColumnCost},
+			alignRight:  true,
+			wantHeaders: []string{"Model Tier", "Reqs", "Cost ($)"},
+			rightAlign:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewStatsModel(nil, nil, time.UTC, nil)
+			model.SetSize(120, 40)
+			model.Update(StatsDataMsg{Stats: stats})
+			if tt.columns != nil || tt.alignRight {
+				model.SetLayout(tt.columns, tt.alignRight)
+			}
+
+			lines := strings.Split(ansi.Strip(model.View()), "\n")
+			var header, totalRow string
+			for _, line := range lines {
+				if strings.HasPrefix(line, "Model Tier") {
+					header = line
+				}
+				if strings.HasPrefix(line, "Total ") {
+					totalRow = line
+				}
+			}
+
+			// Headers appear in the configured order
+			last := -1
+			for _, want := range tt.wantHeaders {
+				index := strings.Index(header, want)
+				if index <= last {
+					t.Fatalf("expected header %q after position %d in %q", want, last, header)
+				}
+				last = index
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(header, absent) {
+					t.Errorf("expected header %q to be hidden in %q", absent, header)
+				}
+			}
+
+			// Right-aligned cells end where the header ends
+			if tt.rightAlign {
+				costHeaderEnd := strings.Index(header, "Cost ($)") + len("Cost ($)")
+				costCellEnd := strings.Index(totalRow, "1.510000") + len("1.510000")
+				if costHeaderEnd != costCellEnd {
+					t.Errorf("expected cost cell to end at %d like its header, got %d\n%s\n%s", costHeaderEnd, costCellEnd, header, totalRow)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsModel_PreviousBlock(t *testing.T) {
+	t.Parallel()
+
+	block := entity.NewBlockWithLimit(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), 7000)
+	previousStats := entity.NewStats(
+		0, 4,
+		entity.NewToken(0, 0, 0, 0), entity.NewToken(3000, 500, 0, 0),
+		entity.NewCost(0), entity.NewCost(2.0),
+		block.PreviousBlock().Period(),
+	)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    bool
+	}{
+		{name: "hidden by default", enabled: false, want: false},
+		{name: "shown when enabled", enabled: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewStatsModel(nil, nil, time.UTC, &block)
+			model.SetSize(120, 40)
+			model.SetClock(entity.NewFixedClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)))
+			model.SetShowPreviousBlock(tt.enabled)
+			model.Update(StatsDataMsg{Block: &block, PreviousBlockStats: previousStats})
+
+			view := ansi.Strip(model.View())
+			got := strings.Contains(view, "Previous block (5am - 10am): 50.0% (3.5K/7.0K tokens)")
+			if got != tt.want {
+				t.Errorf("expected previous block line shown = %v, got view:\n%s", tt.want, view)
+			}
+		})
+	}
+}
+
+func TestStatsModel_SoftLimit(t *testing.T) {
+	t.Parallel()
+
+	block := entity.NewBlockWithLimit(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), 10000)
+
+	tests := []struct {
+		name           string
+		softLimit      int
+		usedTokens     int64
+		wantMarkerAt   int // cell index inside the brackets, -1 when no marker
+		wantSoftColors bool
+	}{
+		{name: "disabled", softLimit: 0, usedTokens: 9000, wantMarkerAt: -1},
+		{name: "below soft limit", softLimit: 80, usedTokens: 5000, wantMarkerAt: 32},
+		{name: "past soft limit", softLimit: 80, usedTokens: 9000, wantMarkerAt: 32, wantSoftColors: true},
+		{name: "marker stays inside the bar", softLimit: 99, usedTokens: 1000, wantMarkerAt: 39},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			blockStats := entity.NewStats(
+				0, 1,
+				entity.NewToken(0, 0, 0, 0), entity.NewToken(tt.usedTokens, 0, 0, 0),
+				entity.NewCost(0), entity.NewCost(1.0),
+				block.Period(),
+			)
+
+			model := NewStatsModel(nil, nil, time.UTC, &block)
+			model.SetSize(120, 40)
+			model.SetClock(entity.NewFixedClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)))
+			model.SetSoftLimit(tt.softLimit)
+			model.Update(StatsDataMsg{Block: &block, BlockStats: blockStats})
+
+			percentage := block.CalculateProgress(blockStats.PremiumTokens())
+			bar := model.renderProgressBar(percentage)
+			cells := []rune(ansi.Strip(bar))
+			if len(cells) != model.progressModel.Width {
+				t.Fatalf("expected the bar to keep its width %d, got %d", model.progressModel.Width, len(cells))
+			}
+
+			markerAt := -1
+			for i, cell := range cells {
+				if cell == '│' {
+					markerAt = i
+				}
+			}
+			if markerAt != tt.wantMarkerAt {
+				t.Errorf("expected marker at %d, got %d in %q", tt.wantMarkerAt, markerAt, string(cells))
+			}
+
+			usesSoftColors := model.progressBarModel(percentage) == &model.softProgressModel
+			if usesSoftColors != tt.wantSoftColors {
+				t.Errorf("expected soft limit colors = %v, got %v", tt.wantSoftColors, usesSoftColors)
+			}
+		})
+	}
+}
+
+func TestStatsModel_TierProgress(t *testing.T) {
+	t.Parallel()
+
+	blockStart := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	blockStats := entity.NewStats(
+		3, 1,
+		entity.NewToken(5000, 1000, 0, 0), entity.NewToken(3000, 500, 0, 0),
+		entity.NewCost(0.01), entity.NewCost(1.0),
+		entity.NewPeriod(blockStart, blockStart.Add(entity.TimeBlockDuration)),
+	)
+
+	tests := []struct {
+		name           string
+		tokenLimit     int
+		baseTokenLimit int
+		wantPremium    string
+		wantBase       string
+	}{
+		{
+			name:        "premium limit only",
+			tokenLimit:  7000,
+			wantPremium: "Premium [",
+			wantBase:    "Base    6.0K tokens (no limit)",
+		},
+		{
+			name:           "both tiers limited",
+			tokenLimit:     7000,
+			baseTokenLimit: 60000,
+			wantPremium:    "] 50.0% (3.5K/7.0K tokens)",
+			wantBase:       "] 10.0% (6.0K/60.0K tokens)",
+		},
+		{
+			name:           "base usage past its limit is capped",
+			tokenLimit:     7000,
+			baseTokenLimit: 3000,
+			wantPremium:    "] 50.0% (3.5K/7.0K tokens)",
+			wantBase:       "] 100.0% (6.0K/3.0K tokens)",
+		},
+		{
+			name:           "base limit only",
+			baseTokenLimit: 60000,
+			wantPremium:    "Premium 3.5K tokens (no limit)",
+			wantBase:       "] 10.0% (6.0K/60.0K tokens)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			block := entity.NewBlockWithLimit(blockStart, tt.tokenLimit)
+			model := NewStatsModel(nil, nil, time.UTC, &block)
+			model.SetSize(120, 40)
+			model.SetClock(entity.NewFixedClock(blockStart.Add(2 * time.Hour)))
+			model.SetBaseTokenLimit(tt.baseTokenLimit)
+			model.Update(StatsDataMsg{Block: &block, BlockStats: blockStats})
+
+			var premiumLine, baseLine string
+			for _, line := range strings.Split(ansi.Strip(model.View()), "\n") {
+				if strings.HasPrefix(line, "Premium ") {
+					premiumLine = line
+				}
+				if strings.HasPrefix(line, "Base    ") {
+					baseLine = line
+				}
+			}
+
+			if !strings.Contains(premiumLine, tt.wantPremium) {
+				t.Errorf("expected premium line to contain %q, got %q", tt.wantPremium, premiumLine)
+			}
+			if !strings.Contains(baseLine, tt.wantBase) {
+				t.Errorf("expected base line to contain %q, got %q", tt.wantBase, baseLine)
+			}
+			if hasBar := strings.Contains(baseLine, "["); hasBar != (tt.baseTokenLimit > 0) {
+				t.Errorf("expected base bar shown = %v, got %q", tt.baseTokenLimit > 0, baseLine)
+			}
+		})
+	}
+}
+
+func TestStatsModel_BlockGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	blockStart := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC) // 10am - 3pm block
+	blockEnd := blockStart.Add(entity.TimeBlockDuration)
+	blockStats := entity.NewStats(
+		0, 1,
+		entity.NewToken(0, 0, 0, 0), entity.NewToken(3000, 500, 0, 0),
+		entity.NewCost(0), entity.NewCost(1.0),
+		entity.NewPeriod(blockStart, blockEnd),
+	)
+
+	tests := []struct {
+		name          string
+		grace         time.Duration
+		now           time.Time
+		wantStatus    string
+		wantNextStart time.Time
+	}{
+		{
+			name:          "within block",
+			grace:         5 * time.Minute,
+			now:           blockEnd.Add(-15 * time.Minute),
+			wantStatus:    "Time remaining: 15m",
+			wantNextStart: blockStart,
+		},
+		{
+			name:          "ended block within grace",
+			grace:         5 * time.Minute,
+			now:           blockEnd.Add(2 * time.Minute),
+			wantStatus:    "Block ended, next block in 3m",
+			wantNextStart: blockStart,
+		},
+		{
+			name:          "without grace",
+			now:           blockEnd.Add(2 * time.Minute),
+			wantStatus:    "Block expired",
+			wantNextStart: blockEnd,
+		},
+		{
+			name:          "grace passed",
+			grace:         5 * time.Minute,
+			now:           blockEnd.Add(5 * time.Minute),
+			wantStatus:    "Block expired",
+			wantNextStart: blockEnd,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			block := entity.NewBlockWithLimit(blockStart, 7000)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(testutil.NewMockAPIRequestRepository()), testutil.NewNoOpStatsCache())
+			model := NewStatsModel(calculateStatsQuery, nil, time.UTC, &block)
+			model.SetSize(120, 40)
+			model.SetClock(entity.NewFixedClock(tt.now))
+			model.SetBlockGracePeriod(tt.grace)
+			model.Update(StatsDataMsg{Block: &block, BlockStats: blockStats})
+
+			view := ansi.Strip(model.View())
+			if !strings.Contains(view, tt.wantStatus) {
+				t.Errorf("expected view to contain %q, got:\n%s", tt.wantStatus, view)
+			}
+			// The ended block keeps showing its usage during the grace period
+			if !strings.Contains(view, "50.0% (3.5K/7.0K tokens)") {
+				t.Errorf("expected block usage in view, got:\n%s", view)
+			}
+
+			msg, ok := model.refreshStats(entity.NewAllTimePeriod(tt.now))().(StatsDataMsg)
+			if !ok {
+				t.Fatalf("expected StatsDataMsg from refresh")
+			}
+			if !msg.Block.StartAt().Equal(tt.wantNextStart) {
+				t.Errorf("expected refreshed block to start at %v, got %v", tt.wantNextStart, msg.Block.StartAt())
+			}
+		})
+	}
+}
+
+// periodCountingStatsRepository records how often the stats of one period are queried
+type periodCountingStatsRepository struct {
+	usecase.StatsRepository
+	period  entity.Period
+	queries int
+}
+
+func (r *periodCountingStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	if query.Period() == r.period {
+		r.queries++
+	}
+	return r.StatsRepository.GetStats(query)
+}
+
+func TestStatsModel_PreviousBlockQueriedOnce(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	block := entity.NewBlockWithLimit(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), 7000)
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 1, 1, 6, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 3000, 500, 2.0),
+	})
+	statsRepo := &periodCountingStatsRepository{StatsRepository: testutil.NewMockStatsRepository(apiRepo), period: block.PreviousBlock().Period()}
+
+	model := NewStatsModel(usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()), nil, time.UTC, &block)
+	model.SetClock(entity.NewFixedClock(now))
+	model.SetShowPreviousBlock(true)
+
+	refresh := func() StatsDataMsg {
+		t.Helper()
+		msg, ok := model.refreshStats(entity.NewAllTimePeriod(now))().(StatsDataMsg)
+		if !ok {
+			t.Fatalf("expected StatsDataMsg from refresh")
+		}
+		model.Update(msg)
+		return msg
+	}
+
+	for range 3 {
+		if msg := refresh(); msg.PreviousBlockStats.TotalRequests() != 1 {
+			t.Fatalf("expected the previous block stats on every refresh, got %d requests", msg.PreviousBlockStats.TotalRequests())
+		}
+	}
+	if statsRepo.queries != 1 {
+		t.Errorf("expected the previous block to be queried once, got %d", statsRepo.queries)
+	}
+
+	// A model tier change reclassifies the requests, so the next refresh queries them again
+	model.forgetPreviousBlock()
+	refresh()
+	if statsRepo.queries != 2 {
+		t.Errorf("expected the previous block to be queried again after forgetting it, got %d", statsRepo.queries)
+	}
+}
+
+func TestStatsModel_InferBlock(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 12, 30, 0, 0, time.UTC)
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(apiRepo), testutil.NewNoOpStatsCache())
+
+	// Started before any request today, so the block begins at the current hour
+	block := entity.InferBlock(time.Time{}, time.UTC, now, 7000)
+	model := NewStatsModel(calculateStatsQuery, nil, time.UTC, &block)
+	model.SetClock(entity.NewFixedClock(now))
+	model.SetFirstRequestQuery(usecase.NewGetFirstRequestTimeQuery(apiRepo))
+
+	refresh := func() StatsDataMsg {
+		t.Helper()
+		msg, ok := model.refreshStats(entity.NewAllTimePeriod(now))().(StatsDataMsg)
+		if !ok {
+			t.Fatalf("expected StatsDataMsg from refresh")
+		}
+		model.Update(msg)
+		return msg
+	}
+
+	if msg := refresh(); !msg.Block.StartAt().Equal(block.StartAt()) || !msg.AnchoredDay.IsZero() {
+		t.Errorf("expected the block to stay at %v without requests, got %v", block.StartAt(), msg.Block.StartAt())
+	}
+
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 0.1),
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 1, 2, 9, 40, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 0.1),
+	})
+	msg := refresh()
+	if want := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC); !msg.Block.StartAt().Equal(want) {
+		t.Errorf("expected the block anchored at %v, got %v", want, msg.Block.StartAt())
+	}
+	if msg.Block.TokenLimit() != 7000 {
+		t.Errorf("expected the token limit to be kept, got %d", msg.Block.TokenLimit())
+	}
+
+	// Once anchored, later refreshes that day do not look up the first request again
+	apiRepo.SetMockData(nil)
+	if msg := refresh(); !msg.AnchoredDay.IsZero() || !msg.Block.StartAt().Equal(time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the anchored block to be kept, got %v", msg.Block.StartAt())
+	}
+}
+
+func TestStatsModel_TokensPerDollar(t *testing.T) {
+	t.Parallel()
+
+	period := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name  string
+		width int
+		stats entity.Stats
+		want  string
+	}{
+		{
+			name:  "tokens per dollar",
+			width: 120,
+			stats: entity.NewStats(0, 2, entity.NewToken(0, 0, 0, 0), entity.NewToken(40000, 10000, 0, 0), entity.NewCost(0), entity.NewCost(2.0), period),
+			want:  "Tokens per $: 25.0K",
+		},
+		{
+			name:  "zero cost shows a dash",
+			width: 120,
+			stats: entity.NewStats(1, 0, entity.NewToken(400, 100, 0, 0), entity.NewToken(0, 0, 0, 0), entity.NewCost(0), entity.NewCost(0), period),
+			want:  "Tokens per $: -",
+		},
+		{
+			name:  "compact view",
+			width: 50,
+			stats: entity.NewStats(0, 2, entity.NewToken(0, 0, 0, 0), entity.NewToken(40000, 10000, 0, 0), entity.NewCost(0), entity.NewCost(2.0), period),
+			want:  "Tokens per $: 25.0K",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewStatsModel(nil, nil, time.UTC, nil)
+			model.SetSize(tt.width, 40)
+			model.Update(StatsDataMsg{Stats: tt.stats})
+
+			if view := ansi.Strip(model.View()); !strings.Contains(view, tt.want) {
+				t.Errorf("expected %q in view:\n%s", tt.want, view)
+			}
+		})
+	}
+}
+
+func TestStatsModel_CostPerSession(t *testing.T) {
+	t.Parallel()
+
+	period := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	stats := entity.NewStats(0, 2, entity.NewToken(0, 0, 0, 0), entity.NewToken(40000, 10000, 0, 0), entity.NewCost(0), entity.NewCost(2.5), period)
+
+	tests := []struct {
+		name     string
+		width    int
+		sessions int
+		want     string
+	}{
+		{
+			name:     "cost over sessions",
+			width:    120,
+			sessions: 2,
+			want:     "Cost per Session: $1.25",
+		},
+		{
+			name:     "zero sessions",
+			width:    120,
+			sessions: 0,
+			want:     "Cost per Session: $0.0",
+		},
+		{
+			name:     "compact view",
+			width:    50,
+			sessions: 2,
+			want:     "Cost per Session: $1.25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewStatsModel(nil, nil, time.UTC, nil)
+			model.SetSize(tt.width, 40)
+			model.Update(StatsDataMsg{Stats: stats, Sessions: tt.sessions})
+
+			if view := ansi.Strip(model.View()); !strings.Contains(view, tt.want) {
+				t.Errorf("expected %q in view:\n%s", tt.want, view)
+			}
+		})
+	}
+}
+
+func TestStatsModel_LongestGap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		withQuery  bool
+		longestGap usecase.LongestGap
+		want       string
+		wantShown  bool
+	}{
+		{
+			name:       "longest gap next to sessions",
+			withQuery:  true,
+			longestGap: usecase.LongestGap{Duration: 2*time.Hour + 15*time.Minute, Available: true},
+			want:       "Sessions: 0  Longest Gap: 2h 15m",
+			wantShown:  true,
+		},
+		{
+			name:       "fewer than two requests shows a dash",
+			withQuery:  true,
+			longestGap: usecase.LongestGap{},
+			want:       "Sessions: 0  Longest Gap: -",
+			wantShown:  true,
+		},
+		{
+			name:       "hidden without the query",
+			withQuery:  false,
+			longestGap: usecase.LongestGap{Duration: time.Hour, Available: true},
+			want:       "Longest Gap",
+			wantShown:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewStatsModel(nil, nil, time.UTC, nil)
+			model.SetSize(120, 40)
+			if tt.withQuery {
+				model.SetLongestGapQuery(usecase.NewGetLongestGapQuery(nil))
+			}
+			model.Update(StatsDataMsg{LongestGap: tt.longestGap})
+
+			view := ansi.Strip(model.View())
+			if got := strings.Contains(view, tt.want); got != tt.wantShown {
+				t.Errorf("expected %q shown = %v, got view:\n%s", tt.want, tt.wantShown, view)
+			}
+		})
+	}
+}
+
+func TestStatsModel_HideZeroRows(t *testing.T) {
+	t.Parallel()
+
+	period := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	premiumOnly := entity.NewStats(0, 2, entity.NewToken(0, 0, 0, 0), entity.NewToken(40000, 10000, 0, 0), entity.NewCost(0), entity.NewCost(2.0), period)
+	empty := entity.NewStats(0, 0, entity.NewToken(0, 0, 0, 0), entity.NewToken(0, 0, 0, 0), entity.NewCost(0), entity.NewCost(0), period)
+
+	tests := []struct {
+		name     string
+		width    int
+		hide     bool
+		stats    entity.Stats
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "all rows by default",
+			width:    120,
+			stats:    premiumOnly,
+			contains: []string{"Base (Haiku)", "Premium (S/O)", "Total"},
+		},
+		{
+			name:     "hides the tier without requests",
+			width:    120,
+			hide:     true,
+			stats:    premiumOnly,
+			contains: []string{"Premium (S/O)", "Total"},
+			absent:   []string{"Base (Haiku)"},
+		},
+		{
+			name:     "total row always shows",
+			width:    120,
+			hide:     true,
+			stats:    empty,
+			contains: []string{"Total"},
+			absent:   []string{"Base (Haiku)", "Premium (S/O)"},
+		},
+		{
+			name:     "compact view",
+			width:    50,
+			hide:     true,
+			stats:    premiumOnly,
+			contains: []string{"Premium: 2 reqs"},
+			absent:   []string{"Base: "},
+		},
+		{
+			name:     "compact view without requests",
+			width:    50,
+			hide:     true,
+			stats:    empty,
+			contains: []string{"Total"},
+			absent:   []string{"Base: ", "Premium: "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewStatsModel(nil, nil, time.UTC, nil)
+			model.SetHideZeroRows(tt.hide)
+			model.SetSize(tt.width, 40)
+			model.Update(StatsDataMsg{Stats: tt.stats})
+
+			view := ansi.Strip(model.View())
+			for _, want := range tt.contains {
+				if !strings.Contains(view, want) {
+					t.Errorf("expected %q in view:\n%s", want, view)
+				}
+			}
+			for _, unwanted := range tt.absent {
+				if strings.Contains(view, unwanted) {
+					t.Errorf("expected no %q in view:\n%s", unwanted, view)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsModel_Negligible(t *testing.T) {
+	t.Parallel()
+
+	period := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session", timestamp, "claude-3-haiku", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.002), 1000),
+		entity.NewAPIRequest("session", timestamp, "claude-sonnet-4", entity.NewToken(40000, 10000, 0, 0), entity.NewCost(2.0), 1000),
+	}
+	stats := entity.NewStatsFromRequests(requests, period).WithNegligible(requests, entity.NewCost(0.01))
+
+	tests := []struct {
+		name      string
+		width     int
+		threshold float64
+		contains  []string
+		absent    []string
+	}{
+		{
+			name:   "hidden by default",
+			width:  120,
+			absent: []string{"Negligible:"},
+		},
+		{
+			name:      "line under the table",
+			width:     120,
+			threshold: 0.01,
+			contains:  []string{"Negligible: 1 reqs, 150 tokens, $0.002", "(below $0.01 each)"},
+		},
+		{
+			name:      "compact view",
+			width:     50,
+			threshold: 0.01,
+			contains:  []string{"Negligible: 1 reqs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewStatsModel(nil, nil, time.UTC, nil)
+			model.SetNegligibleCost(entity.NewCost(tt.threshold))
+			model.SetSize(tt.width, 40)
+			model.Update(StatsDataMsg{Stats: stats})
+
+			view := ansi.Strip(model.View())
+			for _, want := range tt.contains {
+				if !strings.Contains(view, want) {
+					t.Errorf("expected %q in view:\n%s", want, view)
+				}
+			}
+			for _, unwanted := range tt.absent {
+				if strings.Contains(view, unwanted) {
+					t.Errorf("unexpected %q in view:\n%s", unwanted, view)
+				}
+			}
+		})
+	}
+}
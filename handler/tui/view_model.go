@@ -1,10 +1,17 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/usecase"
 )
@@ -18,9 +25,18 @@ const (
 	FilterDay
 	FilterWeek
 	FilterMonth
-	FilterBlock // Current block timeframe
+	FilterBlock   // Current block timeframe
+	FilterRolling // Configured number of days up to now
 )
 
+// DefaultMinCost is the threshold used when the minimum cost filter is toggled without configuration
+const DefaultMinCost = 0.01
+
+// StatsCacheInvalidator drops cached statistics at the start of each refresh cycle
+type StatsCacheInvalidator interface {
+	Invalidate()
+}
+
 // Tab represents the available tabs in the UI
 type Tab int
 
@@ -35,6 +51,10 @@ type ViewModel struct {
 	overviewTab   *OverviewTabModel
 	dailyUsageTab *DailyUsageTabModel
 
+	// Header components
+	sparkline    *SparklineModel
+	budgetBanner *BudgetBannerModel
+
 	// Application state
 	currentTab      Tab
 	width           int
@@ -43,19 +63,89 @@ type ViewModel struct {
 	timeFilter      TimeFilter
 	sortOrder       SortOrder
 	timezone        *time.Location
+	timeDisplay     TimeDisplayMode
+	costDisplay     CostDisplayMode
 	refreshInterval time.Duration
+	minRefresh      time.Duration // Floor of runtime refresh interval changes
+	statsCache      StatsCacheInvalidator
+	clock           entity.Clock
+	frozen          bool // Clock is fixed, e.g. with --at
+	durationStyle   entity.DurationStyle
+
+	// Days of the rolling window filter, 0 disables it
+	rollingDays int
+
+	// Minimum cost view filter for the requests table
+	minCost        float64
+	minCostEnabled bool
+
+	// Recent request window for the requests table, independent of the stats period
+	listWindow        time.Duration
+	listWindowEnabled bool
+
+	// Label filter state for the requests table
+	labelFilters  []entity.LabelFilter
+	editingLabels bool
+	labelInput    string
+	labelError    string
+
+	// Request duration filter for the requests table, e.g. from --min-duration
+	durationRange entity.DurationRange
+
+	// Project filter for the requests table, cycled with "p"; disabled when listProjects is nil
+	projectLabel string
+	listProjects *usecase.ListLabelValuesQuery
+	projects     []string   // Projects detected in the selected period
+	project      string     // Active project, empty for all projects
+	projectsAt   time.Time  // Last project detection, zero before the first one
+	projectsOf   TimeFilter // Time filter of the last project detection
+
+	// Note editor state for the selected request
+	setNoteCommand *usecase.SetRequestNoteCommand
+	editingNote    bool
+	noteInput      string
+	noteError      string
+	noteTarget     entity.APIRequest
+
+	// Desktop notifications for daily spend, nil when alerts are disabled
+	costAlerts *usecase.NotifyCostAlertsCommand
+
+	// Sentinel file for external tools once the monthly budget is used up, nil when disabled
+	budgetSignal *usecase.SignalBudgetCommand
+
+	// Model tier review, nil when overrides cannot be saved
+	modelTiers     *ModelTiersModel
+	reviewingTiers bool
+
+	// Legend and key binding overlay, toggled with "?"
+	showingLegend bool
+
+	// Data freshness footer, hidden when latestRequestQuery is nil
+	latestRequestQuery *usecase.GetLatestRequestTimeQuery
+	staleAfter         time.Duration // Warn when the latest request is older; 0 only warns on failed refreshes
+	lastRefresh        time.Time     // Last successful data fetch, zero before the first one
+	refreshErr         error         // Failure of the last data fetch, cleared by the next success
+	latestRequest      time.Time     // Most recent stored request, zero when there is none
+
+	// Period stats pushed by the server, refreshed on every tick when watchStatsQuery is nil
+	watchStatsQuery *usecase.WatchStatsQuery
+	statsStream     *statsStream // Open subscription, nil when there is none
 }
 
 // NewViewModel creates a new refactored ViewModel with component models
-func NewViewModel(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUsageQuery *usecase.GetUsageQuery, timezone *time.Location, block *entity.Block, refreshInterval time.Duration) *ViewModel {
+func NewViewModel(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUsageQuery *usecase.GetUsageQuery, countSessionsQuery *usecase.CountSessionsQuery, timezone *time.Location, block *entity.Block, refreshInterval time.Duration) *ViewModel {
 	return &ViewModel{
-		overviewTab:     NewOverviewTabModel(calculateStatsQuery, getFilteredQuery, timezone, block),
+		overviewTab:     NewOverviewTabModel(calculateStatsQuery, getFilteredQuery, countSessionsQuery, timezone, block),
 		dailyUsageTab:   NewDailyUsageTabModel(getUsageQuery, timezone),
+		sparkline:       NewSparklineModel(getUsageQuery, DefaultSparklineInterval, DefaultSparklineBuckets),
+		budgetBanner:    NewBudgetBannerModel(nil, nil, 0),
 		currentTab:      TabCurrent,
 		timeFilter:      FilterAll,
 		sortOrder:       SortDescending,
 		timezone:        timezone,
 		refreshInterval: refreshInterval,
+		minRefresh:      MinRefreshInterval,
+		clock:           entity.SystemClock{},
 	}
 }
 
@@ -69,8 +159,13 @@ func (vm *ViewModel) Init() tea.Cmd {
 		tea.EnterAltScreen,
 		vm.overviewTab.Init(),
 		vm.dailyUsageTab.Init(),
-		vm.refreshStats, // Load initial data from database
-		vm.tick(),       // Start periodic refresh
+		vm.refreshStats,      // Load initial data from database
+		vm.refreshSparkline,  // Load the header cost trend
+		vm.refreshBanner,     // Warn about plan usage already past a threshold
+		vm.checkCostAlerts,   // Notify about spend already past a threshold
+		vm.checkBudgetSignal, // Raise or clear the monthly budget signal
+		vm.refreshFreshness,  // Find the latest stored request for the footer
+		vm.tick(),            // Start periodic refresh
 	)
 }
 
@@ -80,6 +175,19 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if vm.editingLabels {
+			return vm, vm.updateLabelInput(msg)
+		}
+		if vm.editingNote {
+			return vm, vm.updateNoteInput(msg)
+		}
+		if vm.reviewingTiers {
+			return vm, vm.updateTierReview(msg)
+		}
+		if vm.showingLegend {
+			return vm, vm.updateLegend(msg)
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return vm, tea.Quit
@@ -103,14 +211,78 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				vm.timeFilter = FilterBlock
 				return vm, vm.refreshStats
 			}
+		case "r":
+			if vm.rollingDays > 0 {
+				vm.timeFilter = FilterRolling
+				return vm, vm.refreshStats
+			}
 		case "o":
-			// Toggle sort order
+			// On the daily tab reverse the days, elsewhere the requests
+			if vm.currentTab == TabDaily {
+				vm.dailyUsageTab.ToggleSortOrder()
+				return vm, nil
+			}
 			if vm.sortOrder == SortDescending {
 				vm.sortOrder = SortAscending
 			} else {
 				vm.sortOrder = SortDescending
 			}
 			return vm, vm.refreshStats
+		case "-":
+			// Refresh more often, never below the minimum interval
+			vm.SetRefreshInterval(vm.refreshInterval / 2)
+		case "+", "=":
+			// Refresh less often
+			vm.SetRefreshInterval(vm.refreshInterval * 2)
+		case "z":
+			// Cycle timestamp display between local time, UTC and both
+			vm.timeDisplay = vm.timeDisplay.Next()
+			vm.overviewTab.SetTimeDisplayMode(vm.timeDisplay)
+		case "s":
+			// Toggle the cost column between per request costs and the running total
+			vm.SetCostDisplayMode(vm.costDisplay.Next())
+		case "c":
+			// Toggle hiding requests below the minimum cost
+			vm.minCostEnabled = !vm.minCostEnabled
+			vm.overviewTab.SetMinCost(vm.activeMinCost())
+		case "e":
+			// Expand the requests table to the full period, or narrow it back to the list window
+			if vm.listWindow > 0 {
+				vm.listWindowEnabled = !vm.listWindowEnabled
+				return vm, vm.refreshStats
+			}
+		case "l":
+			if vm.currentTab == TabCurrent {
+				vm.editingLabels = true
+				vm.labelInput = vm.GetLabelFilterString()
+				vm.labelError = ""
+			}
+		case "n":
+			if vm.currentTab == TabCurrent && vm.setNoteCommand != nil {
+				if selected, ok := vm.overviewTab.SelectedRequest(); ok {
+					vm.editingNote = true
+					vm.noteTarget = selected
+					vm.noteInput = selected.Note()
+					vm.noteError = ""
+				}
+			}
+		case "p":
+			// Cycle the project filter through all projects and each detected one
+			if vm.currentTab == TabCurrent && vm.listProjects != nil {
+				vm.project = vm.nextProject()
+				return vm, vm.refreshStats
+			}
+		case "t":
+			if vm.currentTab == TabCurrent && vm.modelTiers != nil {
+				vm.reviewingTiers = true
+				return vm, vm.modelTiers.Refresh(vm.getTimePeriod())
+			}
+		case "x":
+			// Hide the budget banner until a higher threshold is crossed
+			vm.budgetBanner.Dismiss()
+			return vm, nil
+		case "?":
+			vm.showingLegend = true
 		case "tab":
 			// Switch tabs
 			if vm.currentTab == TabCurrent {
@@ -151,6 +323,7 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		resizeMsg := ResizeMsg{Width: msg.Width, Height: msg.Height}
 		_, cmd1 := vm.overviewTab.Update(resizeMsg)
 		_, cmd2 := vm.dailyUsageTab.Update(resizeMsg)
+		vm.budgetBanner.Update(resizeMsg)
 
 		if cmd1 != nil {
 			cmds = append(cmds, cmd1)
@@ -160,27 +333,95 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tickMsg:
-		// Periodic refresh - refresh based on current tab
+		// Periodic refresh - start a new cache cycle, then refresh based on current tab
+		if vm.statsCache != nil {
+			vm.statsCache.Invalidate()
+		}
 		if vm.currentTab == TabDaily {
-			return vm, tea.Batch(vm.tick(), vm.refreshUsage)
+			return vm, tea.Batch(vm.tick(), vm.refreshUsage, vm.refreshSparkline, vm.refreshBanner, vm.checkCostAlerts, vm.checkBudgetSignal, vm.refreshFreshness)
 		} else {
-			return vm, tea.Batch(vm.tick(), vm.refreshStats)
+			return vm, tea.Batch(vm.tick(), vm.refreshStats, vm.refreshSparkline, vm.refreshBanner, vm.checkCostAlerts, vm.checkBudgetSignal, vm.refreshFreshness)
 		}
 
 	case refreshStatsMsg:
+		// Follow the selected period with the stats subscription; a replaced one stops being skipped on refresh
+		if cmd := vm.syncStatsStream(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 		// Send refresh messages to overview tab with current period
 		if vm.currentTab == TabCurrent {
 			period := vm.getTimePeriod()
 			// Refresh both stats and requests
 			statsCmd := vm.overviewTab.RefreshStats(period)
-			requestsCmd := vm.overviewTab.RefreshRequests(period, vm.sortOrder)
+			requestsCmd := vm.overviewTab.RefreshRequests(vm.getListPeriod(), vm.sortOrder, vm.activeLabelFilters(), vm.durationRange)
 			if statsCmd != nil {
 				cmds = append(cmds, statsCmd)
 			}
 			if requestsCmd != nil {
 				cmds = append(cmds, requestsCmd)
 			}
+			if vm.listProjects != nil && vm.projectsStale() {
+				cmds = append(cmds, vm.refreshProjects(period))
+			}
+		}
+
+	case statsStreamMsg:
+		// Updates of a replaced subscription are dropped
+		if msg.stream != vm.statsStream {
+			return vm, nil
+		}
+		if msg.closed {
+			// Fall back to refreshing the period stats, the next refresh subscribes again
+			vm.statsStream = nil
+			vm.overviewTab.statsModel.setStreamed(false)
+			return vm, nil
+		}
+		vm.overviewTab.setStreamedStats(msg.stats)
+		return vm, msg.stream.next
+
+	case projectsDataMsg:
+		vm.projects = msg.projects
+	case latestRequestMsg:
+		vm.recordRefresh(msg.err)
+		if msg.err == nil {
+			vm.latestRequest = msg.at
+		}
+	case SparklineRefreshMsg, SparklineDataMsg:
+		_, cmd := vm.sparkline.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case BudgetBannerRefreshMsg, BudgetBannerDataMsg, budgetBannerExpiredMsg:
+		_, cmd := vm.budgetBanner.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
 		}
+
+	case noteSavedMsg:
+		if msg.err != nil {
+			vm.noteError = msg.err.Error()
+			return vm, nil
+		}
+		vm.editingNote = false
+		vm.noteError = ""
+		return vm, vm.refreshStats
+
+	case ModelTiersDataMsg:
+		_, cmd := vm.modelTiers.Update(msg)
+		return vm, cmd
+
+	case ModelTierSavedMsg:
+		_, cmd := vm.modelTiers.Update(msg)
+		if msg.Err != nil {
+			return vm, cmd
+		}
+		// Cached stats are keyed by the overrides, so the refresh recalculates them with the new tier
+		vm.overviewTab.statsModel.forgetPreviousBlock()
+		// The server aggregates without local overrides, so its pushed stats no longer apply
+		vm.SetWatchStatsQuery(nil)
+		return vm, tea.Batch(cmd, vm.refreshStats)
+
 	case refreshUsageMsg:
 		// Send refresh message to daily usage tab
 		if vm.currentTab == TabDaily {
@@ -191,6 +432,7 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case StatsDataMsg:
+		vm.recordRefresh(msg.Err)
 		// Forward stats data to overview tab
 		_, cmd := vm.overviewTab.Update(msg)
 		if cmd != nil {
@@ -198,6 +440,7 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case RequestsDataMsg:
+		vm.recordRefresh(msg.Err)
 		// Forward requests data to overview tab
 		_, cmd := vm.overviewTab.Update(msg)
 		if cmd != nil {
@@ -205,6 +448,7 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case UsageDataMsg:
+		vm.recordRefresh(msg.Err)
 		// Forward usage data to daily usage tab
 		_, cmd := vm.dailyUsageTab.Update(msg)
 		if cmd != nil {
@@ -222,19 +466,68 @@ func (vm *ViewModel) View() string {
 	}
 
 	// Common header
-	content := TitleStyle.Render("🖥️  Claude Code Monitor") + "\n"
+	title := TitleStyle.Render("🖥️  Claude Code Monitor")
+	if sparkline := vm.sparkline.View(); sparkline != "" {
+		title = lipgloss.JoinHorizontal(lipgloss.Top, title, "   "+sparkline)
+	}
+	content := title + "\n"
 	content += vm.renderTabNavigation() + "\n"
 
+	// Plan usage warning, shown on every tab until dismissed
+	if banner := vm.budgetBanner.View(); banner != "" {
+		content += banner + "\n"
+	}
+
+	// The legend overlay replaces the tab content until closed
+	if vm.showingLegend {
+		content += "\n" + vm.renderLegend()
+		content += HelpStyle.Render("\n  ?/Esc: close • q: Quit")
+		return content
+	}
+
 	// Tab-specific content
 	switch vm.currentTab {
 	case TabCurrent:
-		// Status line for current tab
-		content += StatusStyle.Render("Monitor Mode | Filter: "+vm.GetTimeFilterString()+" | Sort: "+vm.GetSortOrderString()) + "\n\n"
-		content += vm.overviewTab.View()
+		// Status line for current tab, replaced by the label filter input while editing
+		if vm.editingLabels {
+			content += vm.renderLabelInput() + "\n\n"
+		} else if vm.editingNote {
+			content += vm.renderNoteInput() + "\n\n"
+		} else if vm.reviewingTiers {
+			content += StatusStyle.Render("Model Tiers | Filter: "+vm.GetTimeFilterString()) + "\n\n"
+		} else {
+			status := "Monitor Mode | Filter: " + vm.GetTimeFilterString() + " | Sort: " + vm.GetSortOrderString() + " | Time: " + vm.timeDisplay.String()
+			if len(vm.labelFilters) > 0 {
+				status += " | Labels: " + vm.GetLabelFilterString()
+			}
+			if vm.minCostEnabled {
+				status += " | " + vm.GetMinCostString()
+			}
+			if vm.listWindowEnabled {
+				status += " | " + vm.GetListWindowString()
+			}
+			if !vm.durationRange.IsEmpty() {
+				status += " | Duration: " + vm.durationRange.String()
+			}
+			if vm.frozen {
+				status += " | At: " + FormatTimestamp(vm.clock.Now(), vm.timezone, vm.timeDisplay)
+			}
+			content += StatusStyle.Render(status) + "\n\n"
+		}
+		if vm.reviewingTiers {
+			content += vm.modelTiers.View()
+		} else {
+			content += vm.overviewTab.View()
+		}
 	case TabDaily:
 		content += "\n" + vm.dailyUsageTab.View()
 	}
 
+	// Data freshness footer
+	if footer := vm.renderFreshness(); footer != "" {
+		content += "\n  " + footer
+	}
+
 	// Help text
 	content += vm.renderHelpText()
 
@@ -261,6 +554,10 @@ func (vm *ViewModel) renderTabNavigation() string {
 		content += inactiveTabStyle.Render(" Daily Usage ")
 	}
 
+	if vm.listProjects != nil {
+		content += "   " + StatusStyle.Render("Project: "+vm.GetProjectString())
+	}
+
 	return content
 }
 
@@ -270,18 +567,275 @@ func (vm *ViewModel) renderHelpText() string {
 
 	switch vm.currentTab {
 	case TabCurrent:
+		if vm.reviewingTiers {
+			helpText = "\n  ↑/↓: Navigate • b=base • p=premium • o=opus • r=reset to auto • Esc: close • q: Quit"
+			break
+		}
 		helpText = "\n  ↑/↓: Navigate • Time: h=hour d=day w=week m=month a=all"
 		if vm.Block() != nil {
 			helpText += " b=block"
 		}
-		helpText += " • o=sort • Tab: Switch tabs • q: Quit"
+		if vm.rollingDays > 0 {
+			helpText += fmt.Sprintf(" r=%dd", vm.rollingDays)
+		}
+		helpText += " • o=sort • l=labels • c=min cost • s=running cost • z=utc"
+		if vm.listProjects != nil {
+			helpText += " • p=project"
+		}
+		if vm.listWindow > 0 {
+			helpText += " • e=expand list"
+		}
+		if vm.setNoteCommand != nil {
+			helpText += " • n=note"
+		}
+		if vm.modelTiers != nil {
+			helpText += " • t=tiers"
+		}
+		helpText += " • ?: Legend • Tab: Switch tabs • q: Quit"
 	case TabDaily:
-		helpText = "\n  ↑/↓: Navigate • Tab: Switch tabs • q: Quit"
+		helpText = "\n  ↑/↓: Navigate • o=sort days • ?: Legend • Tab: Switch tabs • q: Quit"
 	}
 
 	return HelpStyle.Render(helpText)
 }
 
+// renderLegend renders what the colors and token columns mean, followed by every key binding
+func (vm *ViewModel) renderLegend() string {
+	var b strings.Builder
+	b.WriteString(HeaderStyle.Render("Legend") + "\n")
+	b.WriteString("  " + BaseStyle.Bold(true).Render(fmt.Sprintf("%-14s", "Base (Haiku)")) + HelpStyle.Render("Haiku and models marked base; tokens do not count against the plan limit") + "\n")
+	b.WriteString("  " + PremiumStyle.Bold(true).Render(fmt.Sprintf("%-14s", "Premium (S/O)")) + HelpStyle.Render("Sonnet, Opus and all other models; tokens count against the plan limit") + "\n")
+	b.WriteString("  " + StatStyle.Render(fmt.Sprintf("%-14s", "Limited")) + HelpStyle.Render("Input + output tokens, the ones counted against the plan limit") + "\n")
+	b.WriteString("  " + StatStyle.Render(fmt.Sprintf("%-14s", "Cache")) + HelpStyle.Render("Cache read + cache creation tokens") + "\n")
+
+	b.WriteString("\n" + HeaderStyle.Render("Key Bindings") + "\n")
+	bindings := [][2]string{
+		{"h d w m a", "Filter by hour, day, week, month or all time"},
+	}
+	if vm.Block() != nil {
+		bindings = append(bindings, [2]string{"b", "Filter by the current block"})
+	}
+	if vm.rollingDays > 0 {
+		bindings = append(bindings, [2]string{"r", fmt.Sprintf("Filter by the last %s up to now", formatRollingDays(vm.rollingDays))})
+	}
+	bindings = append(bindings,
+		[2]string{"o", "Toggle sort order of requests, or of days on the Daily Usage tab"},
+		[2]string{"l", "Filter requests by labels"},
+		[2]string{"c", "Hide requests below the minimum cost"},
+		[2]string{"s", "Toggle request costs and their running total"},
+		[2]string{"z", "Cycle local, UTC and both timestamps"},
+		[2]string{"- +", fmt.Sprintf("Refresh more or less often (now every %v)", vm.refreshInterval)},
+	)
+	if vm.listProjects != nil {
+		bindings = append(bindings, [2]string{"p", "Cycle the project filter of requests"})
+	}
+	if vm.listWindow > 0 {
+		bindings = append(bindings, [2]string{"e", "Expand the request list to the whole period"})
+	}
+	if vm.setNoteCommand != nil {
+		bindings = append(bindings, [2]string{"n", "Edit the note of the selected request"})
+	}
+	if vm.modelTiers != nil {
+		bindings = append(bindings, [2]string{"t", "Review model tiers"})
+	}
+	if vm.budgetBanner.Enabled() {
+		bindings = append(bindings, [2]string{"x", "Dismiss the plan usage warning until a higher threshold is crossed"})
+	}
+	bindings = append(bindings,
+		[2]string{"Tab", "Switch between Current and Daily Usage"},
+		[2]string{"?", "Show or hide this legend"},
+		[2]string{"q", "Quit"},
+	)
+	for _, binding := range bindings {
+		b.WriteString("  " + StatStyle.Render(fmt.Sprintf("%-14s", binding[0])) + HelpStyle.Render(binding[1]) + "\n")
+	}
+
+	return b.String()
+}
+
+// renderFreshness renders when data was last fetched and when the latest request was stored,
+// as a warning when the last fetch failed or the latest request is older than staleAfter.
+// It is hidden with a fixed clock, whose data does not change.
+func (vm *ViewModel) renderFreshness() string {
+	if vm.latestRequestQuery == nil || vm.frozen {
+		return ""
+	}
+
+	now := vm.clock.Now()
+	updated := "Updated: never"
+	if !vm.lastRefresh.IsZero() {
+		updated = "Updated: " + FormatTimestamp(vm.lastRefresh, vm.timezone, vm.timeDisplay)
+	}
+	latest := "Latest request: none"
+	if !vm.latestRequest.IsZero() {
+		latest = "Latest request: " + FormatTimestamp(vm.latestRequest, vm.timezone, vm.timeDisplay) +
+			" (" + vm.durationStyle.Format(now.Sub(vm.latestRequest)) + " ago)"
+	}
+	line := updated + " • " + latest
+
+	if vm.refreshErr != nil {
+		return WarningStyle.Render("⚠ " + describeRefreshError(vm.refreshErr) + " • " + line)
+	}
+	if vm.IsDataStale() {
+		return WarningStyle.Render("⚠ " + line)
+	}
+	return HelpStyle.Render(line)
+}
+
+// describeRefreshError explains a failed data fetch, hinting at what to check when the usecases
+// tell which part failed
+func describeRefreshError(err error) string {
+	message := "Refresh failed: " + err.Error()
+	switch {
+	case errors.Is(err, usecase.ErrRepositoryUnavailable):
+		return message + " (data source unreachable, retrying)"
+	case errors.Is(err, usecase.ErrPlanUnavailable):
+		return message + " (check claude.plan)"
+	default:
+		return message
+	}
+}
+
+// recordRefresh tracks the outcome of a data fetch for the freshness footer
+func (vm *ViewModel) recordRefresh(err error) {
+	if err != nil {
+		vm.refreshErr = err
+		return
+	}
+	vm.refreshErr = nil
+	vm.lastRefresh = vm.clock.Now()
+}
+
+// refreshFreshness finds the latest stored request up to now
+func (vm *ViewModel) refreshFreshness() tea.Msg {
+	if vm.latestRequestQuery == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	at, _, err := vm.latestRequestQuery.Execute(ctx, usecase.GetLatestRequestTimeParams{Period: entity.NewAllTimePeriod(vm.clock.Now().UTC())})
+	return latestRequestMsg{at: at, err: err}
+}
+
+// renderLabelInput renders the label filter input line
+func (vm *ViewModel) renderLabelInput() string {
+	line := StatusStyle.Render("Label filter: "+vm.labelInput+"█") +
+		HelpStyle.Render("  key=value[,key=value] • Enter: apply • Esc: cancel")
+	if vm.labelError != "" {
+		line += " " + ErrorStyle.Render(vm.labelError)
+	}
+	return line
+}
+
+// updateLabelInput handles key input while editing the label filter
+func (vm *ViewModel) updateLabelInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEsc:
+		vm.editingLabels = false
+		vm.labelError = ""
+	case tea.KeyEnter:
+		filters, err := entity.ParseLabelFilters(vm.labelInput)
+		if err != nil {
+			vm.labelError = err.Error()
+			return nil
+		}
+		vm.labelFilters = filters
+		vm.editingLabels = false
+		vm.labelError = ""
+		return vm.refreshStats
+	default:
+		vm.labelInput = editInput(vm.labelInput, msg)
+	}
+	return nil
+}
+
+// renderNoteInput renders the note editor for the selected request
+func (vm *ViewModel) renderNoteInput() string {
+	line := StatusStyle.Render("Note: "+vm.noteInput+"█") +
+		HelpStyle.Render("  Enter: save • Esc: cancel • empty clears")
+	if vm.noteError != "" {
+		line += " " + ErrorStyle.Render(vm.noteError)
+	}
+	return line
+}
+
+// updateNoteInput handles key input while editing a request note
+func (vm *ViewModel) updateNoteInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEsc:
+		vm.editingNote = false
+		vm.noteError = ""
+	case tea.KeyEnter:
+		return vm.saveNote(vm.noteTarget, vm.noteInput)
+	default:
+		vm.noteInput = editInput(vm.noteInput, msg)
+	}
+	return nil
+}
+
+// saveNote returns a command storing the note through the set note command
+func (vm *ViewModel) saveNote(target entity.APIRequest, note string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := vm.setNoteCommand.Execute(ctx, usecase.SetRequestNoteParams{
+			SessionID: target.SessionID(),
+			Timestamp: target.Timestamp(),
+			Note:      note,
+		})
+		return noteSavedMsg{err: err}
+	}
+}
+
+// updateTierReview handles key input while reviewing model tiers
+func (vm *ViewModel) updateTierReview(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "esc", "t":
+		vm.reviewingTiers = false
+		return vm.refreshStats
+	}
+
+	_, cmd := vm.modelTiers.Update(msg)
+	return cmd
+}
+
+// updateLegend handles keys while the legend overlay is open
+func (vm *ViewModel) updateLegend(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "esc", "?":
+		vm.showingLegend = false
+	}
+	return nil
+}
+
+// editInput applies a text editing key to a single-line input
+func editInput(input string, msg tea.KeyMsg) string {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		runes := []rune(input)
+		if len(runes) > 0 {
+			return string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		return ""
+	case tea.KeySpace:
+		return input + " "
+	case tea.KeyRunes:
+		return input + string(msg.Runes)
+	}
+	return input
+}
+
 // Business logic methods
 func (vm *ViewModel) GetTimeFilterString() string {
 	switch vm.timeFilter {
@@ -295,14 +849,300 @@ func (vm *ViewModel) GetTimeFilterString() string {
 		return "Last 30 Days"
 	case FilterBlock:
 		if vm.Block() != nil {
-			return "Current Block (" + FormatBlockTime(*vm.Block(), vm.timezone) + ")"
+			return "Current Block (" + FormatBlockTimeWithMode(*vm.Block(), vm.timezone, vm.timeDisplay) + ")"
 		}
 		return "Block (not configured)"
+	case FilterRolling:
+		return "Last " + formatRollingDays(vm.rollingDays)
 	default:
 		return "All Time"
 	}
 }
 
+// GetLabelFilterString returns the active label filters as a comma-separated expression
+func (vm *ViewModel) GetLabelFilterString() string {
+	parts := make([]string, len(vm.labelFilters))
+	for i, filter := range vm.labelFilters {
+		parts[i] = filter.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// GetProjectString returns the active project, "all projects" without one or "all" when none were detected
+func (vm *ViewModel) GetProjectString() string {
+	if vm.project != "" {
+		return vm.project
+	}
+	if len(vm.projects) == 0 {
+		return "all"
+	}
+	return "all projects"
+}
+
+// GetMinCostString describes the active minimum cost filter and how many rows it hides
+func (vm *ViewModel) GetMinCostString() string {
+	return fmt.Sprintf("Min cost: $%s (%d hidden)",
+		strconv.FormatFloat(vm.activeMinCost().Amount(), 'f', -1, 64),
+		vm.overviewTab.requestsTableModel.Hidden())
+}
+
+// GetListWindowString describes the recent request window applied to the requests table
+func (vm *ViewModel) GetListWindowString() string {
+	return "List: Last " + vm.durationStyle.Format(vm.listWindow)
+}
+
+// SetStatsLayout sets the stats table column order and numeric alignment
+func (vm *ViewModel) SetStatsLayout(columns []StatsColumn, alignRight bool) {
+	vm.overviewTab.statsModel.SetLayout(columns, alignRight)
+}
+
+// SetDailyBudgetQuery enables the daily budget explanation in the stats section
+func (vm *ViewModel) SetDailyBudgetQuery(query *usecase.GetDailyBudgetQuery) {
+	vm.overviewTab.statsModel.SetDailyBudgetQuery(query)
+}
+
+// SetCacheSavingsQuery enables the cache savings estimate in the stats section
+func (vm *ViewModel) SetCacheSavingsQuery(query *usecase.CalculateCacheSavingsQuery) {
+	vm.overviewTab.statsModel.SetCacheSavingsQuery(query)
+}
+
+// SetDashboardQuery fetches the period and block stats in one call on each refresh
+func (vm *ViewModel) SetDashboardQuery(query *usecase.GetDashboardQuery) {
+	vm.overviewTab.statsModel.SetDashboardQuery(query)
+}
+
+// SetDailySortOrder sets the initial day order of the Daily Usage tab
+func (vm *ViewModel) SetDailySortOrder(order SortOrder) {
+	vm.dailyUsageTab.SetSortOrder(order)
+}
+
+// SetSoftLimit marks a percentage of the token limit on the block progress bar; 0 disables it
+func (vm *ViewModel) SetSoftLimit(percent int) {
+	vm.overviewTab.statsModel.SetSoftLimit(percent)
+}
+
+// SetBaseTokenLimit draws the base tier usage of the block as a bar against the limit; 0 shows it without a bar
+func (vm *ViewModel) SetBaseTokenLimit(limit int) {
+	vm.overviewTab.statsModel.SetBaseTokenLimit(limit)
+}
+
+// SetFirstRequestQuery anchors the block at today's first request, looked up on refresh
+func (vm *ViewModel) SetFirstRequestQuery(query *usecase.GetFirstRequestTimeQuery) {
+	vm.overviewTab.statsModel.SetFirstRequestQuery(query)
+}
+
+// SetShowPreviousBlock shows the previous block's final usage under the block progress
+func (vm *ViewModel) SetShowPreviousBlock(enabled bool) {
+	vm.overviewTab.statsModel.SetShowPreviousBlock(enabled)
+}
+
+// SetHideZeroRows leaves out the stats table's tier rows without requests in the selected period
+func (vm *ViewModel) SetHideZeroRows(hide bool) {
+	vm.overviewTab.statsModel.SetHideZeroRows(hide)
+}
+
+// SetNegligibleCost shows the requests cheaper than the threshold as a line under the stats table; 0 hides it
+func (vm *ViewModel) SetNegligibleCost(threshold float64) {
+	vm.overviewTab.statsModel.SetNegligibleCost(entity.NewCost(threshold))
+}
+
+// SetBlockGracePeriod keeps an ended block's progress, dimmed, for the grace period before rolling to the next block
+func (vm *ViewModel) SetBlockGracePeriod(grace time.Duration) {
+	vm.overviewTab.statsModel.SetBlockGracePeriod(grace)
+}
+
+// SetDurationStyle sets how durations such as block time remaining and request age are rendered
+func (vm *ViewModel) SetDurationStyle(style entity.DurationStyle) {
+	vm.durationStyle = style
+	vm.overviewTab.statsModel.SetDurationStyle(style)
+}
+
+// SetCostAlertsCommand enables desktop notifications when daily spend crosses a threshold
+func (vm *ViewModel) SetCostAlertsCommand(command *usecase.NotifyCostAlertsCommand) {
+	vm.costAlerts = command
+}
+
+// SetBudgetSignalCommand enables the sentinel file raised while the monthly plan usage is at or above 100%
+func (vm *ViewModel) SetBudgetSignalCommand(command *usecase.SignalBudgetCommand) {
+	vm.budgetSignal = command
+}
+
+// SetLongestGapQuery enables the longest gap between requests in the stats section
+func (vm *ViewModel) SetLongestGapQuery(query *usecase.GetLongestGapQuery) {
+	vm.overviewTab.statsModel.SetLongestGapQuery(query)
+}
+
+// SetSparkline sets the header cost trend bucket size and count; zero buckets hides it
+func (vm *ViewModel) SetSparkline(interval time.Duration, buckets int) {
+	vm.sparkline.SetConfig(interval, buckets)
+}
+
+// SetBudgetBanner enables the plan usage warning banner at the thresholds in percent, hidden
+// after autoDismiss when it is positive; no thresholds or a nil query disables it
+func (vm *ViewModel) SetBudgetBanner(query *usecase.GetPlanUsageQuery, thresholds []int, autoDismiss time.Duration) {
+	vm.budgetBanner = NewBudgetBannerModel(query, thresholds, autoDismiss)
+	vm.budgetBanner.width = vm.width
+}
+
+// BudgetBannerVisible returns true if the plan usage warning banner is shown
+func (vm *ViewModel) BudgetBannerVisible() bool {
+	return vm.budgetBanner.Visible()
+}
+
+// SparklineCosts returns the header cost trend buckets, oldest first
+func (vm *ViewModel) SparklineCosts() []float64 {
+	return vm.sparkline.Costs()
+}
+
+// SetClock sets the source of the current time; a fixed clock is shown in the status line
+func (vm *ViewModel) SetClock(clock entity.Clock) {
+	vm.clock = clock
+	_, vm.frozen = clock.(entity.FixedClock)
+	vm.overviewTab.statsModel.SetClock(clock)
+}
+
+// SetStatsCache sets the cache invalidated on every refresh tick
+func (vm *ViewModel) SetStatsCache(cache StatsCacheInvalidator) {
+	vm.statsCache = cache
+}
+
+// SetNoteCommand enables editing notes on the selected request
+func (vm *ViewModel) SetNoteCommand(command *usecase.SetRequestNoteCommand) {
+	vm.setNoteCommand = command
+}
+
+// SetModelTierUsecases enables reviewing and overriding model tiers
+func (vm *ViewModel) SetModelTierUsecases(listQuery *usecase.ListModelTiersQuery, setCommand *usecase.SetModelTierCommand) {
+	if listQuery == nil || setCommand == nil {
+		vm.modelTiers = nil
+		return
+	}
+	vm.modelTiers = NewModelTiersModel(listQuery, setCommand)
+}
+
+// ReviewingTiers returns whether the model tier review is open
+func (vm *ViewModel) ReviewingTiers() bool {
+	return vm.reviewingTiers
+}
+
+// ShowingLegend returns whether the legend overlay is open
+func (vm *ViewModel) ShowingLegend() bool {
+	return vm.showingLegend
+}
+
+// EditingNote returns whether the note editor is open
+func (vm *ViewModel) EditingNote() bool {
+	return vm.editingNote
+}
+
+// SetProjectLabel enables the project filter over the values of the label key, e.g. "project"
+func (vm *ViewModel) SetProjectLabel(key string, query *usecase.ListLabelValuesQuery) {
+	vm.projectLabel = key
+	vm.listProjects = query
+}
+
+// SetWatchStatsQuery subscribes to the period stats pushed by the server instead of querying
+// them on every refresh; nil stops a subscription and goes back to refreshing them
+func (vm *ViewModel) SetWatchStatsQuery(query *usecase.WatchStatsQuery) {
+	vm.stopStatsStream()
+	vm.watchStatsQuery = query
+}
+
+// IsStreamingStats returns whether the period stats currently come from the server's stream
+func (vm *ViewModel) IsStreamingStats() bool {
+	return vm.overviewTab.statsModel.streamed
+}
+
+// SetFreshness enables the data freshness footer; a positive staleAfter warns when the latest
+// request is older, 0 only warns when a refresh fails
+func (vm *ViewModel) SetFreshness(query *usecase.GetLatestRequestTimeQuery, staleAfter time.Duration) {
+	vm.latestRequestQuery = query
+	vm.staleAfter = staleAfter
+}
+
+// IsDataStale returns whether the latest request is older than staleAfter
+func (vm *ViewModel) IsDataStale() bool {
+	if vm.staleAfter <= 0 || vm.latestRequest.IsZero() {
+		return false
+	}
+	return vm.clock.Now().Sub(vm.latestRequest) > vm.staleAfter
+}
+
+// RefreshError returns the failure of the last data fetch, nil when it succeeded
+func (vm *ViewModel) RefreshError() error {
+	return vm.refreshErr
+}
+
+// Project returns the active project filter, empty for all projects
+func (vm *ViewModel) Project() string {
+	return vm.project
+}
+
+// SetMinCost configures the minimum cost filter; a positive threshold enables it immediately
+func (vm *ViewModel) SetMinCost(threshold float64) {
+	vm.minCost = threshold
+	vm.minCostEnabled = threshold > 0
+	vm.overviewTab.SetMinCost(vm.activeMinCost())
+}
+
+// SetModelNames shows short display names instead of raw model names in the requests table
+func (vm *ViewModel) SetModelNames(modelNames entity.ModelNameMap) {
+	vm.overviewTab.SetModelNames(modelNames)
+}
+
+// SetCostShare adds a requests table column with each request's share of the period cost
+func (vm *ViewModel) SetCostShare(enabled bool) {
+	vm.overviewTab.SetCostShare(enabled)
+}
+
+// SetCostDisplayMode switches the cost column of the requests table between per request costs
+// and the running total in timestamp order
+func (vm *ViewModel) SetCostDisplayMode(mode CostDisplayMode) {
+	vm.costDisplay = mode
+	vm.overviewTab.SetCostDisplayMode(mode)
+}
+
+// SetDurationRange shows only requests whose duration falls in the range; an empty range shows all
+func (vm *ViewModel) SetDurationRange(durationRange entity.DurationRange) {
+	vm.durationRange = durationRange
+}
+
+// SetRollingDays enables the "r" filter covering exactly days×24h up to now; 0 disables it
+func (vm *ViewModel) SetRollingDays(days int) {
+	vm.rollingDays = days
+}
+
+// rollingWindow returns the length of the rolling window filter
+func (vm *ViewModel) rollingWindow() time.Duration {
+	return time.Duration(vm.rollingDays) * 24 * time.Hour
+}
+
+// formatRollingDays formats the rolling window as "14 Days" or "1 Day"
+func formatRollingDays(days int) string {
+	if days == 1 {
+		return "1 Day"
+	}
+	return fmt.Sprintf("%d Days", days)
+}
+
+// SetListWindow limits the requests table to the most recent window of the selected period
+// while stats keep covering the whole period; a positive window enables it immediately
+func (vm *ViewModel) SetListWindow(window time.Duration) {
+	vm.listWindow = window
+	vm.listWindowEnabled = window > 0
+}
+
+// activeMinCost returns the threshold to apply, falling back to DefaultMinCost when none is configured
+func (vm *ViewModel) activeMinCost() entity.Cost {
+	if !vm.minCostEnabled {
+		return entity.NewCost(0)
+	}
+	if vm.minCost > 0 {
+		return entity.NewCost(vm.minCost)
+	}
+	return entity.NewCost(DefaultMinCost)
+}
+
 func (vm *ViewModel) GetSortOrderString() string {
 	switch vm.sortOrder {
 	case SortDescending:
@@ -317,31 +1157,191 @@ func (vm *ViewModel) GetSortOrderString() string {
 func (vm *ViewModel) getTimePeriod() entity.Period {
 	switch vm.timeFilter {
 	case FilterHour:
-		return entity.NewPeriodFromDuration(time.Now().UTC(), time.Hour)
+		return entity.NewPeriodFromDuration(vm.clock.Now().UTC(), time.Hour)
 	case FilterDay:
-		return entity.NewPeriodFromDuration(time.Now().UTC(), 24*time.Hour)
+		return entity.NewPeriodFromDuration(vm.clock.Now().UTC(), 24*time.Hour)
 	case FilterWeek:
-		return entity.NewPeriodFromDuration(time.Now().UTC(), 7*24*time.Hour)
+		return entity.NewPeriodFromDuration(vm.clock.Now().UTC(), 7*24*time.Hour)
 	case FilterMonth:
-		return entity.NewPeriodFromDuration(time.Now().UTC(), 30*24*time.Hour)
+		return entity.NewPeriodFromDuration(vm.clock.Now().UTC(), 30*24*time.Hour)
 	case FilterBlock:
 		if vm.Block() != nil {
 			return vm.Block().Period()
 		}
-		return entity.NewAllTimePeriod(time.Now().UTC())
+		return entity.NewAllTimePeriod(vm.clock.Now().UTC())
+	case FilterRolling:
+		return entity.NewPeriodFromDuration(vm.clock.Now().UTC(), vm.rollingWindow())
+	default:
+		return entity.NewAllTimePeriod(vm.clock.Now().UTC())
+	}
+}
+
+// getListPeriod returns the period of the requests table, the selected period narrowed to the list window
+func (vm *ViewModel) getListPeriod() entity.Period {
+	period := vm.getTimePeriod()
+	if !vm.listWindowEnabled {
+		return period
+	}
+
+	windowStart := vm.clock.Now().UTC().Add(-vm.listWindow)
+	if period.StartAt().After(windowStart) {
+		return period
+	}
+	return entity.NewPeriod(windowStart, period.EndAt())
+}
+
+// watchStatsParams returns the stats subscription matching the selected period
+func (vm *ViewModel) watchStatsParams() usecase.WatchStatsParams {
+	switch vm.timeFilter {
+	case FilterHour:
+		return usecase.WatchStatsParams{Window: time.Hour}
+	case FilterDay:
+		return usecase.WatchStatsParams{Window: 24 * time.Hour}
+	case FilterWeek:
+		return usecase.WatchStatsParams{Window: 7 * 24 * time.Hour}
+	case FilterMonth:
+		return usecase.WatchStatsParams{Window: 30 * 24 * time.Hour}
+	case FilterBlock:
+		if vm.Block() != nil {
+			return usecase.WatchStatsParams{Start: vm.Block().StartAt(), End: vm.Block().EndAt()}
+		}
+		return usecase.WatchStatsParams{}
+	case FilterRolling:
+		return usecase.WatchStatsParams{Window: vm.rollingWindow()}
 	default:
-		return entity.NewAllTimePeriod(time.Now().UTC())
+		return usecase.WatchStatsParams{}
+	}
+}
+
+// syncStatsStream subscribes to the stats of the selected period, replacing a subscription
+// to another period. Returns nil when the subscription is unchanged or streaming is disabled.
+func (vm *ViewModel) syncStatsStream() tea.Cmd {
+	if vm.watchStatsQuery == nil || vm.frozen {
+		return nil
 	}
+
+	params := vm.watchStatsParams()
+	if vm.statsStream != nil && vm.statsStream.params == params {
+		return nil
+	}
+
+	vm.stopStatsStream()
+	vm.statsStream = startStatsStream(vm.watchStatsQuery, params)
+	return vm.statsStream.next
+}
+
+// stopStatsStream closes the subscription, refreshing the period stats again until the next one
+func (vm *ViewModel) stopStatsStream() {
+	if vm.statsStream == nil {
+		return
+	}
+	vm.statsStream.stop()
+	vm.statsStream = nil
+	vm.overviewTab.statsModel.setStreamed(false)
 }
 
 func (vm *ViewModel) refreshStats() tea.Msg {
 	return refreshStatsMsg{}
 }
 
+// projectsStale returns whether the projects should be detected again, at most once per
+// ProjectsRefreshInterval unless the time filter changed since the last detection
+func (vm *ViewModel) projectsStale() bool {
+	now := vm.clock.Now()
+	return vm.projectsAt.IsZero() || vm.projectsOf != vm.timeFilter || now.Sub(vm.projectsAt) >= ProjectsRefreshInterval
+}
+
+// refreshProjects detects the projects of the period. Failures keep the previous list.
+func (vm *ViewModel) refreshProjects(period entity.Period) tea.Cmd {
+	vm.projectsAt = vm.clock.Now()
+	vm.projectsOf = vm.timeFilter
+	return func() tea.Msg {
+		projects, err := vm.listProjects.Execute(context.Background(), usecase.ListLabelValuesParams{Period: period, Key: vm.projectLabel})
+		if err != nil {
+			return nil
+		}
+		return projectsDataMsg{projects: projects}
+	}
+}
+
+// nextProject returns the project after the active one, wrapping back to all projects
+func (vm *ViewModel) nextProject() string {
+	if vm.project == "" {
+		if len(vm.projects) == 0 {
+			return ""
+		}
+		return vm.projects[0]
+	}
+	index := slices.Index(vm.projects, vm.project)
+	if index+1 >= len(vm.projects) {
+		return ""
+	}
+	return vm.projects[index+1]
+}
+
+// activeLabelFilters returns the label filters of the requests table including the project filter
+func (vm *ViewModel) activeLabelFilters() []entity.LabelFilter {
+	if vm.project == "" {
+		return vm.labelFilters
+	}
+	return append(slices.Clone(vm.labelFilters), entity.NewLabelFilter(vm.projectLabel, vm.project))
+}
+
+// checkCostAlerts sends desktop notifications for newly crossed spend thresholds.
+// Failures are dropped so a missing notification daemon never disturbs the monitor.
+func (vm *ViewModel) checkCostAlerts() tea.Msg {
+	if vm.costAlerts == nil || !vm.costAlerts.IsEnabled() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_ = vm.costAlerts.Execute(ctx)
+	return nil
+}
+
+// checkBudgetSignal raises or clears the monthly budget signal on every refresh.
+// Failures are dropped like cost alerts so an unwritable path never disturbs the monitor.
+func (vm *ViewModel) checkBudgetSignal() tea.Msg {
+	if vm.budgetSignal == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, _ = vm.budgetSignal.Execute(ctx)
+	return nil
+}
+
+func (vm *ViewModel) refreshSparkline() tea.Msg {
+	return SparklineRefreshMsg{}
+}
+
+func (vm *ViewModel) refreshBanner() tea.Msg {
+	return BudgetBannerRefreshMsg{}
+}
+
 func (vm *ViewModel) refreshUsage() tea.Msg {
 	return refreshUsageMsg{}
 }
 
+// SetMinRefreshInterval sets the shortest refresh interval of runtime changes, never below MinRefreshInterval
+func (vm *ViewModel) SetMinRefreshInterval(minimum time.Duration) {
+	vm.minRefresh, _ = ClampRefreshInterval(minimum, MinRefreshInterval)
+}
+
+// SetRefreshInterval changes the refresh interval from the next tick on, clamped to the minimum and
+// MaxRefreshInterval. It returns the interval in effect.
+func (vm *ViewModel) SetRefreshInterval(interval time.Duration) time.Duration {
+	vm.refreshInterval, _ = ClampRefreshInterval(interval, vm.minRefresh)
+	return vm.refreshInterval
+}
+
+// RefreshInterval returns the current refresh interval
+func (vm *ViewModel) RefreshInterval() time.Duration {
+	return vm.refreshInterval
+}
+
 // tick returns a command that sends a tick message using the configured refresh interval
 func (vm *ViewModel) tick() tea.Cmd {
 	return tea.Tick(vm.refreshInterval, func(t time.Time) tea.Msg {
@@ -367,6 +1367,14 @@ func (vm *ViewModel) Timezone() *time.Location {
 	return vm.timezone
 }
 
+func (vm *ViewModel) TimeDisplayMode() TimeDisplayMode {
+	return vm.timeDisplay
+}
+
+func (vm *ViewModel) CostDisplayMode() CostDisplayMode {
+	return vm.costDisplay
+}
+
 func (vm *ViewModel) Block() *entity.Block {
 	// Return block from overview tab stats model (it manages block state now)
 	return vm.overviewTab.statsModel.Block()
@@ -382,6 +1390,11 @@ func (vm *ViewModel) BlockStats() entity.Stats {
 	return vm.overviewTab.statsModel.BlockStats()
 }
 
+func (vm *ViewModel) Sessions() int {
+	// Return distinct session count from overview tab stats model
+	return vm.overviewTab.statsModel.Sessions()
+}
+
 func (vm *ViewModel) Requests() []entity.APIRequest {
 	// Return requests from overview tab requests table model
 	return vm.overviewTab.requestsTableModel.Requests()
@@ -392,6 +1405,10 @@ func (vm *ViewModel) Table() table.Model {
 	return vm.overviewTab.requestsTableModel.table
 }
 
+func (vm *ViewModel) LabelFilters() []entity.LabelFilter {
+	return vm.labelFilters
+}
+
 func (vm *ViewModel) TokenLimit() int {
 	if vm.Block() != nil {
 		return vm.Block().TokenLimit()
@@ -403,3 +1420,13 @@ func (vm *ViewModel) TokenLimit() int {
 type tickMsg time.Time
 type refreshStatsMsg struct{}
 type refreshUsageMsg struct{}
+type projectsDataMsg struct {
+	projects []string
+}
+type latestRequestMsg struct {
+	at  time.Time // Zero when no request is stored
+	err error
+}
+type noteSavedMsg struct {
+	err error
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/handler/cli"
 	grpcserver "github.com/elct9620/ccmon/handler/grpc"
 	"github.com/elct9620/ccmon/handler/tui"
@@ -37,7 +41,35 @@ func createStatsCache(cacheConfig CacheStats) usecase.StatsCache {
 		ttl = time.Minute
 	}
 
-	return service.NewInMemoryStatsCache(ttl)
+	return service.NewInMemoryStatsCacheWithLimit(ttl, cacheConfig.MaxEntries)
+}
+
+// monitorStatsCache caches server stats for the monitor and --format; the monitor invalidates it on every tick
+type monitorStatsCache interface {
+	usecase.StatsCache
+	Invalidate()
+}
+
+// createMonitorStatsCache creates the client-side stats cache, per process unless the file backend is configured
+func createMonitorStatsCache(cacheConfig MonitorStatsCache, server string) monitorStatsCache {
+	if cacheConfig.Backend != "file" {
+		return service.NewRefreshStatsCache()
+	}
+
+	ttl, err := time.ParseDuration(cacheConfig.TTL)
+	if err != nil {
+		log.Printf("Invalid stats cache TTL '%s', using 10 second default: %v", cacheConfig.TTL, err)
+		ttl = 10 * time.Second
+	}
+
+	// One directory per server, so monitors of different servers never share stats
+	serverDir := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, server)
+	return service.NewFileStatsCache(filepath.Join(cacheConfig.Path, serverDir), ttl)
 }
 
 func main() {
@@ -46,16 +78,85 @@ func main() {
 	var blockTime string
 	var showVersion bool
 	var formatString string
+	var showSummary bool
+	var exportFormat string
+	var exportOutput string
+	var healthCheck bool
+	var pauseIngestion bool
+	var resumeIngestion bool
+	var storageInfo bool
+	var compactNumbers bool
+	var fullNumbers bool
+	var strictFormat bool
+	var explainFormat bool
+	var backupOutput string
+	var restoreInput string
+	var forceRestore bool
+	var replayRequests bool
+	var atTime string
+	var datesList string
+	var peakHoursDays int
+	var groupBy string
+	var groupJSON bool
+	var checkTimezones bool
+	var exportFields string
+	var exportBucket string
+	var openMonitor bool
+	var ratesFile string
+	var minDuration string
+	var maxDuration string
+	var loadFile string
+	var diffFiles string
+	var diffRecords bool
+	var statsSnapshot bool
 	pflag.BoolVarP(&serverMode, "server", "s", false, "Run as OTLP server (headless mode)")
 	pflag.StringVarP(&blockTime, "block", "b", "", "Set block start time for token tracking (e.g., '5am', '11pm')")
 	pflag.BoolVarP(&showVersion, "version", "v", false, "Show version information")
 	pflag.StringVar(&formatString, "format", "", "Format string for quick query (e.g., '@daily_cost')")
+	pflag.BoolVar(&compactNumbers, "compact", false, "Render --format costs in whole dollars and token counts in whole K or M (e.g. $15, 1M)")
+	pflag.BoolVar(&fullNumbers, "full", false, "Render --format costs with full cent precision and exact token counts (e.g. $15.03, 1200000)")
+	pflag.BoolVar(&strictFormat, "strict", false, "Reject --format strings with unknown @ variables instead of leaving them intact")
+	pflag.BoolVar(&explainFormat, "explain", false, "After the --format result, show how each variable in it was computed")
+	pflag.BoolVar(&showSummary, "summary", false, "Print a single-line usage summary for shell prompts (add --block to include block time left)")
+	pflag.StringVar(&exportFormat, "export", "", "Export all requests in the given format (csv, jsonl)")
+	pflag.BoolVar(&healthCheck, "healthcheck", false, "Verify the server ingests telemetry end-to-end with a synthetic record and exit")
+	pflag.BoolVar(&pauseIngestion, "pause-ingestion", false, "Make the server stop storing received telemetry until --resume-ingestion (exporters retry meanwhile)")
+	pflag.BoolVar(&resumeIngestion, "resume-ingestion", false, "Make the server store received telemetry again after --pause-ingestion")
+	pflag.BoolVar(&storageInfo, "storage-info", false, "Print the server's database size, record count and oldest and newest request, then exit")
+	pflag.StringVar(&backupOutput, "backup", "", "Write a zstd-compressed snapshot of the server database to a file ('-' for stdout)")
+	pflag.StringVar(&restoreInput, "restore", "", "Restore a --backup file into the configured database path (server must be stopped)")
+	pflag.BoolVar(&replayRequests, "replay", false, "Submit the requests in database.path to the monitor.server receiver, skipping ones it already stores (local server must be stopped)")
+	pflag.StringVar(&diffFiles, "diff", "", "Compare two --export files (e.g. 'laptop.jsonl,desktop.csv') and report the requests only one of them has")
+	pflag.BoolVar(&diffRecords, "diff-records", false, "List the requests unique to each --diff file")
+	pflag.BoolVar(&forceRestore, "force", false, "Allow --restore to replace an existing database, keeping it as a .bak file")
+	pflag.StringVar(&atTime, "at", "", "Evaluate the monitor, --format and --summary as if it were this time (e.g. '2025-07-01 18:00', in monitor.timezone)")
+	pflag.StringVar(&datesList, "dates", "", "Print stats for each listed day (e.g. '2025-01-06,2025-01-13', in monitor.timezone)")
+	pflag.IntVar(&peakHoursDays, "peak-hours", 0, "Print cost by hour of day over the last N days (e.g. 30, hours in monitor.timezone)")
+	pflag.StringVar(&groupBy, "group-by", "", "Print this month's stats per model, session, day or project, the most expensive first (project uses monitor.project_label)")
+	pflag.BoolVar(&groupJSON, "json", false, "Print --group-by as a JSON array instead of a table, or every usage variable as a JSON object")
+	pflag.BoolVar(&checkTimezones, "timezones", false, "Check the configured monitor.timezone and list common timezone names")
+	pflag.StringVar(&exportFields, "fields", "", "Comma-separated columns and order of --export (e.g. 'timestamp,model,cost_usd')")
+	pflag.StringVar(&exportBucket, "bucket", "", "Export request count, tokens and cost per time bucket instead of each request: hour, day or week (csv only, in monitor.timezone)")
+	pflag.BoolVar(&statsSnapshot, "snapshot", false, "Print the monitor's all-time usage statistics once as plain fixed-width text, e.g. to paste into an issue")
+	pflag.BoolVar(&openMonitor, "tui", false, "Open the monitor even when monitor.default_command selects another command")
+	pflag.StringVar(&ratesFile, "rates", "", "Recalculate costs of --summary, --format, --dates, --peak-hours, --group-by and --export from a TOML rate table (stored costs are unchanged)")
+	pflag.StringVar(&exportOutput, "output", cli.StdoutPath, "Export destination file, created with parent directories ('-' for stdout)")
+	pflag.StringVar(&minDuration, "min-duration", "", "Only list requests taking at least this long in --export and the monitor (e.g. '30s', inclusive)")
+	pflag.StringVar(&loadFile, "load", "", "Review requests from an --export CSV or JSON Lines file instead of querying the server")
+	pflag.StringVar(&maxDuration, "max-duration", "", "Only list requests taking less than this in --export and the monitor (e.g. '2m', exclusive)")
 
 	// Add help flag
 	pflag.BoolP("help", "h", false, "Show help")
 
 	// Load configuration (this will parse flags internally)
 	config, err := LoadConfig()
+	if err != nil && checkTimezones && config != nil {
+		// An invalid timezone fails validation, which is exactly what --timezones explains
+		if err := cli.NewTimezoneHandler(config.Monitor.Timezone).HandleTimezoneCheck(); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
 		os.Exit(1)
@@ -81,9 +182,71 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Bare ccmon runs the configured default command; explicit command flags always win
+	if !hasCommandFlag(pflag.CommandLine) {
+		switch config.Monitor.DefaultCommand {
+		case "summary":
+			showSummary = true
+		case "format":
+			formatString = config.Monitor.DefaultFormat
+		}
+	}
+
+	// Handle timezone check - reports the configured timezone without contacting the server
+	if checkTimezones {
+		if err := cli.NewTimezoneHandler(config.Monitor.Timezone).HandleTimezoneCheck(); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle restore mode - works on the database file directly, so it runs without a server
+	if restoreInput != "" {
+		restoreHandler := cli.NewRestoreHandler(countDatabaseRecords)
+		restoreHandler.SetForce(forceRestore)
+		if err := restoreHandler.HandleRestore(restoreInput, config.Database.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Restore failed: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle replay mode - reads the local database file and submits its requests to a remote receiver
+	if replayRequests {
+		if err := replayDatabase(config.Database.Path, config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme()); err != nil {
+			fmt.Fprintf(os.Stderr, "Replay failed: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle diff mode - compares two export files, so it runs without a server
+	if diffFiles != "" {
+		if err := diffExportFiles(diffFiles, diffRecords); err != nil {
+			fmt.Fprintf(os.Stderr, "Diff failed: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Strict startup refuses to run with a plan that would silently fall back
+	if config.Startup.Strict {
+		if err := checkStrictStartup(config, dataFS); err != nil {
+			fmt.Fprintf(os.Stderr, "Strict startup: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	if serverMode {
-		// Server mode: Use BoltDB repository
-		db, err := NewDatabase(config.Database.Path)
+		// Server mode: Use BoltDB repository (read-only for query-only servers)
+		openDatabase := NewDatabase
+		if config.Server.ReadOnly {
+			openDatabase = NewDatabaseReadOnly
+		} else if config.Database.IsSyncDisabled() {
+			openDatabase = NewDatabaseWithoutSync
+			log.Printf("Database write policy: fast (fsync disabled; a crash or power loss can lose recent requests or corrupt the database)")
+		}
+		db, err := openDatabase(config.Database.Path)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
 			os.Exit(1)
@@ -99,66 +262,310 @@ func main() {
 		// Create cache
 		statsCache := createStatsCache(config.Server.Cache.Stats)
 
+		// Ignored models are still stored but left out of every aggregation and list
+		ignoredModels := entity.NewModelIgnoreList(config.Server.IgnoreModels)
+
 		// Create stats repository for server side
 		statsRepo := repository.NewBoltDBStatsRepository(repo)
+		statsRepo.SetIgnoredModels(ignoredModels)
+		statsRepo.SetClassifier(newModelClassifier(config.Claude.ModelTiers))
 
 		// Create usecases
 		appendCommand := usecase.NewAppendApiRequestCommand(repo)
+		appendCommand.SetDedupWindow(config.Server.GetDedupWindow())
 		getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(repo)
+		getFilteredQuery.SetIgnoredModels(ignoredModels)
 		calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, statsCache)
 		cleanupCommand := usecase.NewCleanupOldRecordsCommand(repo)
+		var evictCommand *usecase.EvictExcessRecordsCommand
+		if maxRecords := config.Server.GetMaxRecords(); maxRecords > 0 {
+			evictCommand = usecase.NewEvictExcessRecordsCommand(repo, maxRecords)
+			log.Printf("Keeping at most %d stored requests", maxRecords)
+		}
+		deleteByPeriodCommand := usecase.NewDeleteRequestsByPeriodCommand(repo)
+		deleteByPeriodCommand.SetCalculateStatsQuery(calculateStatsQuery)
+		setNoteCommand := usecase.NewSetRequestNoteCommand(repo)
+		snapshotRepository := repository.NewBoltDBSnapshotRepository(db)
+		createBackupQuery := usecase.NewCreateBackupQuery(snapshotRepository)
+		getStorageInfoQuery := usecase.NewGetStorageInfoQuery(snapshotRepository)
+		planRepository, err := repository.NewEmbeddedPlanRepository(config, dataFS)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize plan repository: %v\n", err)
+			os.Exit(1)
+		}
+		getDashboardQuery := usecase.NewGetDashboardQuery(calculateStatsQuery, planRepository)
+		getModelsQuery := usecase.NewGetModelsQuery(repo)
+		getModelsQuery.SetIgnoredModels(ignoredModels)
 		// Note: getUsageQuery would be used if we add usage endpoints to gRPC server
 		// Server mode uses UTC timezone for consistency
 		periodFactory := service.NewTimePeriodFactory(time.UTC)
 		_ = usecase.NewGetUsageQuery(repo, periodFactory) // Avoid unused variable
 
+		// Push usage gauges to StatsD when an address is configured
+		var publishMetricsCommand *usecase.PublishUsageMetricsCommand
+		if config.Server.StatsD.Address != "" {
+			publisher, err := newStatsDPublisher(config.Server.StatsD)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize StatsD emitter: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() {
+				if err := publisher.Close(); err != nil {
+					log.Printf("Error closing StatsD emitter: %v", err)
+				}
+			}()
+
+			// Days, months and blocks follow the monitor timezone so the gauges match @daily_cost and the TUI
+			metricsTimezone, err := time.LoadLocation(config.Monitor.Timezone)
+			if err != nil {
+				metricsTimezone = time.UTC
+			}
+			metricsPeriodFactory := service.NewTimePeriodFactory(metricsTimezone)
+			metricsPeriodFactory.SetMonthlyIncludesToday(config.Monitor.MonthlyIncludeToday)
+			publishMetricsCommand = usecase.NewPublishUsageMetricsCommand(calculateStatsQuery, usecase.NewGetFirstRequestTimeQuery(repo), metricsPeriodFactory, publisher)
+			publishMetricsCommand.SetBlockLimit(metricsTimezone, config.Claude.GetTokenLimit())
+			publishMetricsCommand.SetBlockTokenMetric(config.Claude.GetBlockTokenMetric())
+		}
+
+		// Archive each completed day or month when an export directory is configured
+		var autoExportCommand *usecase.AutoExportCommand
+		if config.Server.AutoExport.Directory != "" {
+			exportFormat, _ := cli.ParseExportFormat(config.Server.AutoExport.Format) // Checked by Validate
+			exportInterval, _ := entity.ParseExportInterval(config.Server.AutoExport.Interval)
+			// Days and months follow the monitor timezone, as with the StatsD gauges
+			exportTimezone, err := time.LoadLocation(config.Monitor.Timezone)
+			if err != nil {
+				exportTimezone = time.UTC
+			}
+			exportRenderer := cli.NewExportRenderer(exportFormat)
+			exportRenderer.SetCostDecimals(config.Monitor.ExportCostDecimals)
+			exportRenderer.SetModelNames(newModelNameMap(config.Claude.ModelNames))
+			archive := cli.NewDirectoryArchive(config.Server.AutoExport.Directory, exportFormat, exportRenderer)
+			autoExportCommand = usecase.NewAutoExportCommand(getFilteredQuery, getStorageInfoQuery, archive, exportInterval, exportTimezone)
+			log.Printf("Exporting each %s to %s", exportInterval, archive.Path("<date>"))
+		}
+
 		// Run server with usecases
-		if err := grpcserver.RunServer(config.Server.Address, appendCommand, getFilteredQuery, calculateStatsQuery, cleanupCommand, &config.Server); err != nil {
+		if err := grpcserver.RunServer(config.Server.Address, appendCommand, getFilteredQuery, calculateStatsQuery, cleanupCommand, evictCommand, deleteByPeriodCommand, setNoteCommand, createBackupQuery, getDashboardQuery, getModelsQuery, getStorageInfoQuery, publishMetricsCommand, autoExportCommand, &config.Server); err != nil {
 			fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
 			os.Exit(1)
 		}
 	} else {
-		// Monitor mode: Use gRPC repository
-		repo, err := repository.NewGRPCAPIRequestRepository(config.Monitor.Server)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to initialize gRPC repository: %v\n", err)
+		if loadFile != "" && (healthCheck || backupOutput != "" || pauseIngestion || resumeIngestion || storageInfo) {
+			fmt.Fprintf(os.Stderr, "--load cannot be used with --healthcheck, --backup, --pause-ingestion, --resume-ingestion or --storage-info, which need the server\n")
 			os.Exit(1)
 		}
-		defer func() {
-			if err := repo.Close(); err != nil {
-				log.Printf("Error closing gRPC repository: %v", err)
+
+		// Handle pause and resume - the server stops or restarts storing received telemetry
+		if pauseIngestion || resumeIngestion {
+			if pauseIngestion && resumeIngestion {
+				fmt.Fprintf(os.Stderr, "--pause-ingestion and --resume-ingestion cannot be used together\n")
+				os.Exit(1)
 			}
-		}()
+			ingestionClient, err := repository.NewGRPCIngestionClient(config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize ingestion client: %v\n", err)
+				os.Exit(1)
+			}
+			err = cli.NewIngestionHandler(ingestionClient).HandleSetPaused(pauseIngestion)
+			if closeErr := ingestionClient.Close(); closeErr != nil {
+				log.Printf("Error closing ingestion client: %v", closeErr)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 
-		// Create cache
-		statsCache := createStatsCache(config.Server.Cache.Stats)
+		// Handle storage info - the server describes its database, e.g. to decide when to prune
+		if storageInfo {
+			timezone, err := time.LoadLocation(config.Monitor.Timezone)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+				os.Exit(1)
+			}
+			storageInfoClient, err := repository.NewGRPCStorageInfoClient(config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize storage info client: %v\n", err)
+				os.Exit(1)
+			}
+			err = cli.NewStorageInfoHandler(storageInfoClient, timezone).HandleStorageInfo()
+			if closeErr := storageInfoClient.Close(); closeErr != nil {
+				log.Printf("Error closing storage info client: %v", closeErr)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 
-		// Create gRPC stats repository for TUI mode
-		tuiStatsRepo, err := repository.NewGRPCStatsRepository(config.Monitor.Server)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to initialize gRPC stats repository: %v\n", err)
-			os.Exit(1)
+		// Handle healthcheck mode - the server round-trips a synthetic record through its receiver
+		if healthCheck {
+			checker, err := repository.NewGRPCHealthCheckClient(config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize health check client: %v\n", err)
+				os.Exit(1)
+			}
+			err = cli.NewHealthCheckHandler(checker).HandleHealthCheck()
+			if closeErr := checker.Close(); closeErr != nil {
+				log.Printf("Error closing health check client: %v", closeErr)
+			}
+			if err != nil {
+				os.Exit(1)
+			}
+			os.Exit(0)
 		}
-		defer func() {
-			if err := tuiStatsRepo.Close(); err != nil {
-				log.Printf("Error closing TUI stats repository: %v", err)
+
+		// Handle backup mode - the server snapshots its database within a read transaction
+		if backupOutput != "" {
+			backupClient, err := repository.NewGRPCBackupClient(config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize backup client: %v\n", err)
+				os.Exit(1)
 			}
-		}()
+			err = cli.NewBackupHandler(backupClient).HandleBackup(backupOutput)
+			if closeErr := backupClient.Close(); closeErr != nil {
+				log.Printf("Error closing backup client: %v", closeErr)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Backup failed: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		// Monitor mode: Use gRPC repository, or the requests of an export file for offline review
+		var repo usecase.APIRequestRepository
+		var tuiStatsRepo usecase.StatsRepository
+		var tuiStatsStreamRepo usecase.StatsStreamRepository
+		var tuiDurationRepo usecase.DurationFilterRepository
+		if loadFile != "" {
+			requests, err := cli.LoadExportFile(loadFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --load: %v\n", err)
+				os.Exit(1)
+			}
+			memoryRepo := repository.NewInMemoryAPIRequestRepository(requests)
+			memoryStatsRepo := repository.NewBoltDBStatsRepository(memoryRepo)
+			memoryStatsRepo.SetIgnoredModels(entity.NewModelIgnoreList(config.Server.IgnoreModels))
+			repo = memoryRepo
+			tuiStatsRepo = memoryStatsRepo
+		} else {
+			grpcRepo, err := repository.NewGRPCAPIRequestRepository(config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize gRPC repository: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() {
+				if err := grpcRepo.Close(); err != nil {
+					log.Printf("Error closing gRPC repository: %v", err)
+				}
+			}()
+
+			// Create gRPC stats repository for TUI mode
+			grpcStatsRepo, err := repository.NewGRPCStatsRepository(config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize gRPC stats repository: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() {
+				if err := grpcStatsRepo.Close(); err != nil {
+					log.Printf("Error closing TUI stats repository: %v", err)
+				}
+			}()
+
+			repo = grpcRepo
+			tuiStatsRepo = grpcStatsRepo
+			tuiStatsStreamRepo = grpcStatsRepo
+			tuiDurationRepo = grpcRepo
+		}
+
+		// Cache stats per refresh cycle; the TUI invalidates it on every tick. Repriced and loaded
+		// requests differ from the server's, so only plain server stats go to a shared cache.
+		var statsCache monitorStatsCache = service.NewRefreshStatsCache()
+		if ratesFile == "" && loadFile == "" {
+			statsCache = createMonitorStatsCache(config.Monitor.StatsCache, config.Monitor.Server)
+		}
+
+		// A what-if rate table reprices requests from their token counts, so stats are
+		// recalculated from the repriced requests instead of the server aggregates
+		var requestRepo usecase.APIRequestRepository = repo
+		var statsRepository usecase.StatsRepository = tuiStatsRepo
+		if ratesFile != "" {
+			rates, err := LoadRatesFile(ratesFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --rates: %v\n", err)
+				os.Exit(1)
+			}
+			requestRepo = repository.NewRepricedAPIRequestRepository(repo, newRateTable(rates))
+			statsRepository = repository.NewBoltDBStatsRepository(requestRepo)
+		}
 
 		// Create query usecases (no append command needed for monitor)
-		getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(repo)
-		calculateStatsQuery := usecase.NewCalculateStatsQuery(tuiStatsRepo, statsCache)
+		getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(requestRepo)
+		// The server filters durations before the list limit; repriced requests are filtered locally
+		if ratesFile == "" && tuiDurationRepo != nil {
+			getFilteredQuery.SetDurationFilterRepository(tuiDurationRepo)
+		}
+		// Tier overrides edited in the monitor are saved to their own file and sent with every stats query
+		tierRepository, err := repository.NewFileModelTierRepository(config.ModelTiersFile(), newModelClassifier(config.Claude.ModelTiers))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid model tiers: %v\n", err)
+			os.Exit(1)
+		}
+		calculateStatsQuery := usecase.NewCalculateStatsQuery(repository.NewClassifiedStatsRepository(statsRepository, requestRepo), statsCache)
+		calculateStatsQuery.SetModelTierRepository(tierRepository)
+		calculateStatsQuery.SetNegligibleCost(entity.NewCost(config.Monitor.NegligibleCost))
 		timezone, err := time.LoadLocation(config.Monitor.Timezone)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
 			os.Exit(1)
 		}
+		// Freeze "now" when previewing a given time, otherwise follow the wall clock
+		var clock entity.Clock = entity.SystemClock{}
+		var asOf time.Time
+		if atTime != "" {
+			at, err := entity.ParseClockTime(atTime, timezone)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --at: %v\n", err)
+				os.Exit(1)
+			}
+			clock = entity.NewFixedClock(at)
+			asOf = at
+		}
+		// With --at, stats, sessions, gaps and request lists leave out the requests stored later
+		calculateStatsQuery.SetAsOf(asOf)
+		getFilteredQuery.SetAsOf(asOf)
+		// Duration bounds narrow request lists, aggregates are unaffected
+		durationRange, err := entity.ParseDurationRange(minDuration, maxDuration)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --min-duration or --max-duration: %v\n", err)
+			os.Exit(1)
+		}
+		// --json without --group-by prints the usage variables instead of a format string
+		usageJSON := groupJSON && groupBy == ""
+		if !durationRange.IsEmpty() && (formatString != "" || usageJSON || showSummary || datesList != "" || peakHoursDays > 0 || groupBy != "" || exportBucket != "") {
+			fmt.Fprintf(os.Stderr, "--min-duration and --max-duration only apply to --export without --bucket and the monitor\n")
+			os.Exit(1)
+		}
 		periodFactory := service.NewTimePeriodFactory(timezone)
+		periodFactory.SetClock(clock)
+		periodFactory.SetMonthlyIncludesToday(config.Monitor.MonthlyIncludeToday)
 		getUsageQuery := usecase.NewGetUsageQuery(repo, periodFactory)
+		getUsageQuery.SetClock(clock)
+		getUsageQuery.SetAsOf(asOf)
+		countSessionsQuery := usecase.NewCountSessionsQuery(repo, config.Monitor.IncludeUnknownSessions)
+		countSessionsQuery.SetStatsQuery(calculateStatsQuery)
+		countSessionsQuery.SetAsOf(asOf)
+		longestGapQuery := usecase.NewGetLongestGapQuery(getFilteredQuery)
+		longestGapQuery.SetStatsQuery(calculateStatsQuery)
+		cacheSavingsQuery := usecase.NewCalculateCacheSavingsQuery(getFilteredQuery, newRateTable(config.Claude.Rates))
 
 		// Convert config to TUI-specific struct
 		// Handle format query mode - bypass TUI and output directly to stdout
-		if formatString != "" {
+		if formatString != "" || usageJSON {
 			// Create plan repository for usage percentage calculations
 			planRepository, err := repository.NewEmbeddedPlanRepository(config, dataFS)
 			if err != nil {
@@ -166,50 +573,545 @@ func main() {
 				os.Exit(1)
 			}
 
-			// Create gRPC stats repository for efficient stats retrieval
-			statsRepo, err := repository.NewGRPCStatsRepository(config.Monitor.Server)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to initialize stats repository: %v\n", err)
-				os.Exit(1)
-			}
-			defer func() {
-				if err := statsRepo.Close(); err != nil {
-					log.Printf("Error closing stats repository: %v", err)
+			// Repriced requests need the locally recalculated stats
+			formatCalculateStatsQuery := calculateStatsQuery
+			if ratesFile == "" && loadFile == "" {
+				// Create gRPC stats repository for efficient stats retrieval
+				statsRepo, err := repository.NewGRPCStatsRepository(config.Monitor.Server, config.Monitor.AuthToken, config.Monitor.GetAuthScheme())
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to initialize stats repository: %v\n", err)
+					os.Exit(1)
 				}
-			}()
+				defer func() {
+					if err := statsRepo.Close(); err != nil {
+						log.Printf("Error closing stats repository: %v", err)
+					}
+				}()
 
-			// Create CalculateStatsQuery that uses gRPC StatsRepository
-			formatCalculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, statsCache)
+				// Create CalculateStatsQuery that uses gRPC StatsRepository
+				formatCalculateStatsQuery = usecase.NewCalculateStatsQuery(repository.NewClassifiedStatsRepository(statsRepo, repo), statsCache)
+				formatCalculateStatsQuery.SetModelTierRepository(tierRepository)
+				formatCalculateStatsQuery.SetNegligibleCost(entity.NewCost(config.Monitor.NegligibleCost))
+				formatCalculateStatsQuery.SetAsOf(asOf)
+			}
 
 			// Create GetUsageVariablesQuery with format-optimized dependencies
 			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
 				formatCalculateStatsQuery,
+				countSessionsQuery,
 				planRepository,
 				periodFactory,
 			)
+			usageVariablesQuery.SetCacheSavingsQuery(cacheSavingsQuery)
+			usageVariablesQuery.SetLongestGapQuery(longestGapQuery)
+			usageVariablesQuery.SetTimezone(timezone)
+			usageVariablesQuery.SetPercentDecimals(config.Monitor.PercentDecimals)
+			usageVariablesQuery.SetTokenDecimals(config.Monitor.TokenDecimals)
+			usageVariablesQuery.SetDurationStyle(config.Monitor.GetDurationStyle())
+			usageVariablesQuery.SetClock(clock)
+			if schedule, ok := config.Monitor.GetSchedule(); ok {
+				usageVariablesQuery.SetSchedule(schedule, periodFactory)
+			}
+			block, err := newCommandBlock(blockTime, config, repo, periodFactory, timezone, clock.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			if block != nil {
+				usageVariablesQuery.SetBlock(*block)
+			}
 
 			// Create format renderer and query handler
 			renderer := cli.NewFormatRenderer(usageVariablesQuery)
+			renderer.SetNumberLocale(config.Monitor.GetNumberLocale())
+			renderer.SetStrict(strictFormat)
+			switch {
+			case compactNumbers && fullNumbers:
+				fmt.Fprintf(os.Stderr, "--compact and --full cannot be used together\n")
+				os.Exit(1)
+			case compactNumbers:
+				renderer.SetCostStyle(entity.CostStyleCompact)
+			case fullNumbers:
+				renderer.SetCostStyle(entity.CostStyleFull)
+			}
 			queryHandler := cli.NewQueryHandler(renderer)
+			queryHandler.SetErrorOutput(config.Monitor.FormatError)
+			queryHandler.SetExplain(explainFormat)
+
+			if usageJSON {
+				if explainFormat || strictFormat {
+					fmt.Fprintf(os.Stderr, "--explain and --strict only apply to --format, not --json\n")
+					os.Exit(1)
+				}
+				if err := queryHandler.HandleJSONQuery(); err != nil {
+					os.Exit(config.Monitor.FormatErrorExitCode)
+				}
+				os.Exit(0)
+			}
 
 			if err := queryHandler.HandleFormatQuery(formatString); err != nil {
+				// Strict and explain modes are for checking format strings, so the reason is worth showing
+				if strictFormat || explainFormat {
+					fmt.Fprintf(os.Stderr, "\n%v\n", err)
+				}
+				os.Exit(config.Monitor.FormatErrorExitCode)
+			}
+			os.Exit(0)
+		}
+
+		// Handle summary mode - single stats call for shell prompts
+		if showSummary {
+			planRepository, err := repository.NewEmbeddedPlanRepository(config, dataFS)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize plan repository: %v\n", err)
+				os.Exit(1)
+			}
+
+			block, err := newCommandBlock(blockTime, config, repo, periodFactory, timezone, clock.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+
+			summaryQuery := usecase.NewGetSummaryQuery(calculateStatsQuery, planRepository, periodFactory)
+			if schedule, ok := config.Monitor.GetSchedule(); ok {
+				summaryQuery.SetSchedule(schedule, periodFactory)
+			}
+			summaryRenderer := cli.NewSummaryRenderer(summaryQuery, block)
+			summaryRenderer.SetClock(clock)
+			summaryRenderer.SetNumberLocale(config.Monitor.GetNumberLocale())
+			summaryRenderer.SetDurationStyle(config.Monitor.GetDurationStyle())
+			summaryRenderer.SetTokenDecimals(config.Monitor.TokenDecimals)
+			summaryRenderer.SetPercentDecimals(config.Monitor.PercentDecimals)
+			summaryHandler := cli.NewSummaryHandler(summaryRenderer)
+
+			if err := summaryHandler.HandleSummaryQuery(); err != nil {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		// Handle dates mode - per-day stats for an explicit list of dates
+		if datesList != "" {
+			dates, err := cli.ParseDateList(datesList, timezone)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --dates: %v\n", err)
+				os.Exit(1)
+			}
+
+			statsByDatesQuery := usecase.NewGetStatsByDatesQuery(calculateStatsQuery, periodFactory)
+			datesRenderer := cli.NewDatesRenderer(statsByDatesQuery)
+			datesRenderer.SetNumberLocale(config.Monitor.GetNumberLocale())
+			datesRenderer.SetTokenDecimals(config.Monitor.TokenDecimals)
+			datesHandler := cli.NewDatesHandler(datesRenderer)
+
+			if err := datesHandler.HandleDatesQuery(dates); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to query stats: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		// Handle peak hours mode - cost by hour of day collapsed across dates
+		if peakHoursDays > 0 {
+			statsByHourQuery := usecase.NewGetStatsByHourOfDayQuery(requestRepo, periodFactory)
+			hoursRenderer := cli.NewHoursRenderer(statsByHourQuery, timezone)
+			hoursRenderer.SetNumberLocale(config.Monitor.GetNumberLocale())
+			hoursRenderer.SetTokenDecimals(config.Monitor.TokenDecimals)
+			hoursHandler := cli.NewHoursHandler(hoursRenderer)
+
+			if err := hoursHandler.HandleHoursQuery(peakHoursDays); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to query stats: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		// Handle group mode - this month's stats per model, session, day or project
+		if groupBy != "" {
+			dimension, err := entity.ParseGroupBy(groupBy)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --group-by: %v\n", err)
+				os.Exit(1)
+			}
+			if dimension == entity.GroupByProject && config.Monitor.ProjectLabel == "" {
+				fmt.Fprintf(os.Stderr, "--group-by project requires monitor.project_label\n")
+				os.Exit(1)
+			}
+
+			statsByGroupQuery := usecase.NewGetStatsByGroupQuery(requestRepo, periodFactory)
+			statsByGroupQuery.SetModelNames(newModelNameMap(config.Claude.ModelNames))
+			if config.Monitor.GroupNegligible {
+				statsByGroupQuery.SetNegligibleCost(entity.NewCost(config.Monitor.NegligibleCost))
+			}
+			groupRenderer := cli.NewGroupRenderer(statsByGroupQuery, timezone, config.Monitor.ProjectLabel)
+			groupRenderer.SetJSON(groupJSON)
+			groupRenderer.SetNumberLocale(config.Monitor.GetNumberLocale())
+			groupRenderer.SetTokenDecimals(config.Monitor.TokenDecimals)
+			groupHandler := cli.NewGroupHandler(groupRenderer)
+
+			if err := groupHandler.HandleGroupQuery(dimension); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to query stats: %v\n", err)
 				os.Exit(1)
 			}
 			os.Exit(0)
 		}
+		// Handle export mode - write stored requests to a file or stdout
+		if exportFormat != "" {
+			format, err := cli.ParseExportFormat(exportFormat)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+
+			// Bucketed exports aggregate requests into plot-ready rows
+			if exportBucket != "" {
+				bucket, err := entity.ParseTimeBucket(exportBucket)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Invalid --bucket: %v\n", err)
+					os.Exit(1)
+				}
+				if format != cli.ExportFormatCSV || exportFields != "" {
+					fmt.Fprintf(os.Stderr, "--bucket only supports --export csv without --fields\n")
+					os.Exit(1)
+				}
+
+				bucketRenderer := cli.NewBucketExportRenderer(timezone)
+				bucketRenderer.SetCostDecimals(config.Monitor.ExportCostDecimals)
+				bucketHandler := cli.NewBucketExportHandler(usecase.NewGetStatsByBucketQuery(requestRepo), bucketRenderer, bucket, timezone)
+				if err := bucketHandler.HandleExport(exportOutput); err != nil {
+					fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+					os.Exit(1)
+				}
+				os.Exit(0)
+			}
+
+			exportRenderer := cli.NewExportRenderer(format)
+			exportRenderer.SetCostDecimals(config.Monitor.ExportCostDecimals)
+			exportRenderer.SetModelNames(newModelNameMap(config.Claude.ModelNames))
+			if exportFields != "" {
+				fields, err := cli.ParseExportFields(exportFields)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Invalid --fields: %v\n", err)
+					os.Exit(1)
+				}
+				if err := exportRenderer.SetFields(fields); err != nil {
+					fmt.Fprintf(os.Stderr, "Invalid --fields: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			exportHandler := cli.NewExportHandler(getFilteredQuery, exportRenderer)
+			exportHandler.SetMinCost(entity.NewCost(config.Monitor.MinCost))
+			exportHandler.SetDurationRange(durationRange)
+			if err := exportHandler.HandleExport(exportOutput); err != nil {
+				fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		if ratesFile != "" {
+			fmt.Fprintf(os.Stderr, "--rates only applies to --summary, --format, --dates, --peak-hours, --group-by and --export\n")
+			os.Exit(1)
+		}
 
 		monitorConfig := tui.MonitorConfig{
-			Server:          config.Monitor.Server,
-			Timezone:        config.Monitor.Timezone,
-			RefreshInterval: config.Monitor.RefreshInterval,
-			TokenLimit:      config.Claude.GetTokenLimit(),
-			BlockTime:       blockTime,
+			Server:             config.Monitor.Server,
+			Timezone:           config.Monitor.Timezone,
+			RefreshInterval:    config.Monitor.RefreshInterval,
+			MinRefreshInterval: config.Monitor.MinRefreshInterval,
+			TokenLimit:         config.Claude.GetTokenLimit(),
+			BlockTokenMetric:   config.Claude.GetBlockTokenMetric(),
+			BlockTime:          blockTime,
+			DurationStyle:      config.Monitor.GetDurationStyle(),
+			MinCost:            config.Monitor.MinCost,
+			StatsColumns:       config.Monitor.StatsColumns,
+			StatsAlign:         config.Monitor.StatsAlign,
+			HideZeroRows:       config.Monitor.HideZeroRows,
+			NegligibleCost:     config.Monitor.NegligibleCost,
+			DailyOrder:         config.Monitor.DailyOrder,
+			SparklineInterval:  config.Monitor.SparklineInterval,
+			SparklineBuckets:   config.Monitor.SparklineBuckets,
+			Clock:              clock,
+			NumberLocale:       config.Monitor.GetNumberLocale(),
+			TokenDecimals:      config.Monitor.TokenDecimals,
+			PercentDecimals:    config.Monitor.PercentDecimals,
+			ShowPreviousBlock:  config.Monitor.ShowPreviousBlock,
+			SoftLimit:          config.Claude.SoftLimit,
+			BaseTokenLimit:     int(config.Claude.BaseTokens),
+			ListWindow:         config.Monitor.ListWindow,
+			AutoBlock:          config.Monitor.AutoBlock,
+			DurationRange:      durationRange,
+			ProjectLabel:       config.Monitor.ProjectLabel,
+			StaleAfter:         config.Monitor.StaleAfter,
+			BlockGracePeriod:   config.Monitor.BlockGracePeriod,
+			RollingDays:        config.Monitor.RollingDays,
+			CostColumn:         config.Monitor.CostColumn,
+			CostShare:          config.Monitor.CostShare,
+			ModelNames:         newModelNameMap(config.Claude.ModelNames),
+			BannerThresholds:   config.Monitor.BudgetBanner.Thresholds,
+			BannerAutoDismiss:  config.Monitor.BudgetBanner.AutoDismiss,
+		}
+
+		// Plan repository explains the daily budget next to the stats
+		planRepository, err := repository.NewEmbeddedPlanRepository(config, dataFS)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize plan repository: %v\n", err)
+			os.Exit(1)
+		}
+		dailyBudgetQuery := usecase.NewGetDailyBudgetQuery(planRepository, periodFactory)
+
+		// Handle snapshot mode - renders the monitor's stats once instead of opening it
+		if statsSnapshot {
+			snapshot, err := tui.RenderStatsSnapshot(calculateStatsQuery, countSessionsQuery, dailyBudgetQuery, cacheSavingsQuery, longestGapQuery, monitorConfig)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Snapshot failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(snapshot)
+			os.Exit(0)
+		}
+
+		// Compose the period and block stats from the cached stats query, so --at, the negligible bucket
+		// and the local plan apply, and servers without the dashboard RPC need no probing
+		getDashboardQuery := usecase.NewGetDashboardQuery(calculateStatsQuery, planRepository)
+
+		// Subscribe to the period stats pushed by the server; they are aggregated without local
+		// tier overrides, so those keep querying the stats with the overrides on every refresh. They
+		// don't stop at --at either.
+		var watchStatsQuery *usecase.WatchStatsQuery
+		if config.Monitor.StreamStats && tuiStatsStreamRepo != nil && !tierRepository.GetClassifier().HasOverrides() && atTime == "" {
+			watchStatsQuery = usecase.NewWatchStatsQuery(tuiStatsStreamRepo)
+			watchStatsQuery.SetNegligibleCost(entity.NewCost(config.Monitor.NegligibleCost))
+		}
+
+		// Desktop notifications when daily spend crosses a threshold
+		var notifyCostAlertsCommand *usecase.NotifyCostAlertsCommand
+		if config.Monitor.Notifications.Enabled {
+			notifier := service.NewDesktopNotifier()
+			if !notifier.IsSupported() {
+				log.Printf("Desktop notifications are not available on this system, spend alerts are disabled")
+			}
+			notifyCostAlertsCommand = usecase.NewNotifyCostAlertsCommand(usecase.NewGetSummaryQuery(calculateStatsQuery, planRepository, periodFactory), periodFactory, notifier)
+			notifyCostAlertsCommand.SetThresholds(config.Monitor.Notifications.DailyCost, config.Monitor.Notifications.DailyPlanUsage)
+			notifyCostAlertsCommand.SetQuietHours(config.Monitor.Notifications.GetQuietHours(), timezone)
+			notifyCostAlertsCommand.SetClock(clock)
+		}
+
+		// Sentinel file for external tools while the monthly budget is used up
+		var signalBudgetCommand *usecase.SignalBudgetCommand
+		if config.Monitor.BudgetSignalFile != "" {
+			signalBudgetCommand = usecase.NewSignalBudgetCommand(calculateStatsQuery, planRepository, periodFactory, service.NewFileBudgetSignal(config.Monitor.BudgetSignalFile))
+			signalBudgetCommand.SetClock(clock)
+		}
+
+		// Banner across the monitor while plan usage is past a threshold
+		var planUsageQuery *usecase.GetPlanUsageQuery
+		if len(config.Monitor.BudgetBanner.Thresholds) > 0 {
+			planUsageQuery = usecase.NewGetPlanUsageQuery(calculateStatsQuery, planRepository, periodFactory)
 		}
 
 		// Run monitor with usecases and config - TUI handler owns block logic
-		if err := tui.RunMonitor(getFilteredQuery, calculateStatsQuery, getUsageQuery, monitorConfig); err != nil {
+		if err := tui.RunMonitor(getFilteredQuery, calculateStatsQuery, getUsageQuery, countSessionsQuery, dailyBudgetQuery, cacheSavingsQuery, longestGapQuery, usecase.NewSetRequestNoteCommand(repo), usecase.NewListModelTiersQuery(getFilteredQuery, tierRepository), usecase.NewSetModelTierCommand(tierRepository), getDashboardQuery, watchStatsQuery, notifyCostAlertsCommand, signalBudgetCommand, planUsageQuery, usecase.NewGetFirstRequestTimeQuery(repo), statsCache, monitorConfig); err != nil {
 			fmt.Fprintf(os.Stderr, "Monitor error: %v\n", err)
 			os.Exit(1)
 		}
 	}
 }
+
+// commandFlags are the flags that select what ccmon does, as opposed to flags that configure it
+var commandFlags = []string{"tui", "server", "version", "format", "json", "summary", "export", "healthcheck", "backup", "restore", "replay", "diff", "snapshot", "dates", "peak-hours", "group-by", "timezones", "help"}
+
+// hasCommandFlag returns true if any flag selecting a command was given on the command line
+func hasCommandFlag(flags *pflag.FlagSet) bool {
+	for _, name := range commandFlags {
+		if flags.Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// findFirstRequestToday returns when today's first request was made, or zero time when there is none
+// A failed lookup is logged and treated as no request so the monitor still starts
+func findFirstRequestToday(repo usecase.APIRequestRepository, periodFactory usecase.PeriodFactory) time.Time {
+	query := usecase.NewGetFirstRequestTimeQuery(repo)
+	firstRequestAt, _, err := query.Execute(context.Background(), usecase.GetFirstRequestTimeParams{Period: periodFactory.CreateDaily()})
+	if err != nil {
+		log.Printf("Failed to find today's first request, anchoring the block at the current hour: %v", err)
+		return time.Time{}
+	}
+	return firstRequestAt
+}
+
+// checkStrictStartup resolves the plan without the lenient fallback, a paid plan must also end up
+// with a price so budgets are not silently zero. The timezone is checked by Config.Validate.
+func checkStrictStartup(config *Config, plansFS repository.FileSystem) error {
+	planRepository, err := repository.NewEmbeddedPlanRepository(config, plansFS)
+	if err != nil {
+		return err
+	}
+	planRepository.SetStrict(true)
+
+	plan, err := planRepository.GetConfiguredPlan()
+	if err != nil {
+		return fmt.Errorf("claude.plan: %w", err)
+	}
+	if plan.Name() != "unset" && plan.Price().Amount() <= 0 {
+		return fmt.Errorf("claude.plan %q has no price; set claude.plan_price", plan.Name())
+	}
+	return nil
+}
+
+// newRateTable converts the configured model rates in lookup order
+func newRateTable(rates []ModelRate) entity.RateTable {
+	modelRates := make([]entity.ModelRate, 0, len(rates))
+	for _, rate := range rates {
+		modelRates = append(modelRates, entity.NewModelRate(rate.Model, rate.Input, rate.CacheRead).WithOutputRates(rate.Output, rate.CacheCreation))
+	}
+	return entity.NewRateTable(modelRates...)
+}
+
+// newModelNameMap converts the configured display names in lookup order
+func newModelNameMap(names []ModelName) entity.ModelNameMap {
+	displayNames := make([]entity.ModelDisplayName, 0, len(names))
+	for _, name := range names {
+		displayNames = append(displayNames, entity.NewModelDisplayName(name.Model, name.Name))
+	}
+	return entity.NewModelNameMap(displayNames...)
+}
+
+// newModelClassifier converts the configured tier overrides; invalid tiers are rejected by Validate
+func newModelClassifier(overrides []ModelTierOverride) entity.ModelClassifier {
+	tiers := make(map[string]entity.ModelTier, len(overrides))
+	for _, override := range overrides {
+		tier, err := entity.ParseModelTier(override.Tier)
+		if err != nil {
+			continue
+		}
+		tiers[override.Model] = tier
+	}
+	return entity.NewModelClassifier(tiers)
+}
+
+// newStatsDPublisher creates the StatsD emitter with the configured prefix, tags and metric names
+func newStatsDPublisher(statsDConfig StatsD) (*service.StatsDPublisher, error) {
+	publisher, err := service.NewStatsDPublisher(statsDConfig.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[entity.UsageGauge]string, len(statsDConfig.Names))
+	for key, name := range statsDConfig.Names {
+		gauge, err := entity.ParseUsageGauge(key)
+		if err != nil {
+			continue // Rejected by Validate
+		}
+		names[gauge] = name
+	}
+
+	publisher.SetPrefix(statsDConfig.Prefix)
+	publisher.SetTags(statsDConfig.Tags)
+	publisher.SetNames(names)
+	return publisher, nil
+}
+
+// newCommandBlock returns the block of a one-shot command: the --block block containing now, the block
+// inferred from today's first request with monitor.auto_block, or nil when block tracking is off.
+// Today's first request is only looked up for the inferred block.
+func newCommandBlock(blockTime string, config *Config, repo usecase.APIRequestRepository, periodFactory usecase.PeriodFactory, timezone *time.Location, now time.Time) (*entity.Block, error) {
+	switch {
+	case blockTime != "":
+		block, err := tui.NewCurrentBlock(blockTime, timezone, now, config.Claude.GetTokenLimit())
+		if err != nil {
+			return nil, err
+		}
+		block = block.WithTokenMetric(config.Claude.GetBlockTokenMetric())
+		return &block, nil
+	case config.Monitor.AutoBlock:
+		firstRequestAt := findFirstRequestToday(repo, periodFactory)
+		block := tui.NewInferredBlock(firstRequestAt, timezone, now, config.Claude.GetTokenLimit()).WithTokenMetric(config.Claude.GetBlockTokenMetric())
+		return &block, nil
+	default:
+		return nil, nil
+	}
+}
+
+// replayDatabase submits every request of a database file to the OTLP receiver of the server at address,
+// looking up the requests it already stores through its query service; both are sent the auth token with the scheme
+func replayDatabase(path string, address string, authToken string, authScheme entity.AuthScheme) error {
+	db, err := NewDatabaseReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	target, err := repository.NewGRPCAPIRequestRepository(address, authToken, authScheme)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := target.Close(); err != nil {
+			log.Printf("Error closing gRPC repository: %v", err)
+		}
+	}()
+
+	ingester, err := service.NewOTLPTelemetryClient(address, authToken, authScheme)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := ingester.Close(); err != nil {
+			log.Printf("Error closing OTLP client: %v", err)
+		}
+	}()
+
+	replayCommand := usecase.NewReplayApiRequestsCommand(repository.NewBoltDBAPIRequestRepository(db), target, ingester)
+	return cli.NewReplayHandler(replayCommand).HandleReplay(address)
+}
+
+// countDatabaseRecords opens a database file read-only and counts its requests
+func countDatabaseRecords(path string) (int, error) {
+	db, err := NewDatabaseReadOnly(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	return repository.NewBoltDBSnapshotRepository(db).CountRecords()
+}
+
+// diffExportFiles compares the requests of two comma-separated export files
+func diffExportFiles(files string, showRecords bool) error {
+	paths := strings.Split(files, ",")
+	if len(paths) != 2 || strings.TrimSpace(paths[0]) == "" || strings.TrimSpace(paths[1]) == "" {
+		return fmt.Errorf("--diff needs two files, e.g. 'laptop.jsonl,desktop.csv'")
+	}
+	left, right := strings.TrimSpace(paths[0]), strings.TrimSpace(paths[1])
+
+	leftRequests, err := cli.LoadExportFile(left)
+	if err != nil {
+		return err
+	}
+	rightRequests, err := cli.LoadExportFile(right)
+	if err != nil {
+		return err
+	}
+
+	diffQuery := usecase.NewDiffApiRequestsQuery(
+		repository.NewInMemoryAPIRequestRepository(leftRequests),
+		repository.NewInMemoryAPIRequestRepository(rightRequests),
+	)
+	diffHandler := cli.NewDiffHandler(diffQuery)
+	diffHandler.SetShowRecords(showRecords)
+	return diffHandler.HandleDiff(left, right)
+}
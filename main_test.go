@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestHasCommandFlag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "bare invocation", args: []string{}, want: false},
+		{name: "configuration flags only", args: []string{"--monitor-server", "remote:4317", "-b", "5am"}, want: false},
+		{name: "summary flag", args: []string{"--summary"}, want: true},
+		{name: "format flag", args: []string{"--format", "@daily_cost"}, want: true},
+		{name: "json flag", args: []string{"--json"}, want: true},
+		{name: "server flag", args: []string{"-s"}, want: true},
+		{name: "tui flag", args: []string{"--tui"}, want: true},
+		{name: "group by flag", args: []string{"--group-by", "model"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flags := pflag.NewFlagSet("ccmon", pflag.ContinueOnError)
+			flags.BoolP("server", "s", false, "")
+			flags.Bool("tui", false, "")
+			flags.StringP("block", "b", "", "")
+			flags.String("format", "", "")
+			flags.Bool("summary", false, "")
+			flags.Bool("json", false, "")
+			flags.String("group-by", "", "")
+			flags.String("monitor-server", "", "")
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+
+			if got := hasCommandFlag(flags); got != tt.want {
+				t.Errorf("hasCommandFlag(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// plansFS serves a fixed plans.json
+type plansFS string
+
+func (fs plansFS) ReadFile(name string) ([]byte, error) {
+	return []byte(fs), nil
+}
+
+func TestCheckStrictStartup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		plan   string
+		price  float64
+		plans  string
+		errMsg string
+	}{
+		{name: "built-in plan", plan: "pro"},
+		{name: "unset plan", plan: "unset"},
+		{
+			name:   "plan missing from plans.json",
+			plan:   "max20",
+			plans:  `{"plans": {"unset": {"name": "unset", "price": 0}}}`,
+			errMsg: "claude.plan: plan \"max20\" is not in plans.json",
+		},
+		{
+			name:   "paid plan without a price",
+			plan:   "max",
+			plans:  `{"plans": {"max": {"name": "max", "price": 0}}}`,
+			errMsg: "claude.plan \"max\" has no price",
+		},
+		{
+			name:  "price override fills a missing price",
+			plan:  "max",
+			price: 90,
+			plans: `{"plans": {"max": {"name": "max", "price": 0}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := &Config{
+				Claude: Claude{Plan: tt.plan, PlanPrice: tt.price},
+			}
+
+			var err error
+			if tt.plans == "" {
+				err = checkStrictStartup(config, dataFS)
+			} else {
+				err = checkStrictStartup(config, plansFS(tt.plans))
+			}
+
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("checkStrictStartup() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("checkStrictStartup() error = %v, want containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -26,6 +26,26 @@ type QueryServiceClient interface {
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	// GetAPIRequests returns API request records
 	GetAPIRequests(ctx context.Context, in *GetAPIRequestsRequest, opts ...grpc.CallOption) (*GetAPIRequestsResponse, error)
+	// DeleteByPeriod deletes API request records within a time range (requires auth)
+	DeleteByPeriod(ctx context.Context, in *DeleteByPeriodRequest, opts ...grpc.CallOption) (*DeleteByPeriodResponse, error)
+	// HealthCheck round-trips a synthetic record through the OTLP receiver and store
+	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+	// SetNote attaches a manual note to the request identified by session and timestamp
+	SetNote(ctx context.Context, in *SetNoteRequest, opts ...grpc.CallOption) (*SetNoteResponse, error)
+	// Backup streams a consistent snapshot of the database file
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (QueryService_BackupClient, error)
+	// GetDashboard returns the period stats, block stats and plan in a single call
+	GetDashboard(ctx context.Context, in *GetDashboardRequest, opts ...grpc.CallOption) (*GetDashboardResponse, error)
+	// GetModels returns the distinct models in the store with their usage
+	GetModels(ctx context.Context, in *GetModelsRequest, opts ...grpc.CallOption) (*GetModelsResponse, error)
+	// StreamStats pushes the stats of a period on a server-driven interval and whenever new data arrives
+	StreamStats(ctx context.Context, in *StreamStatsRequest, opts ...grpc.CallOption) (QueryService_StreamStatsClient, error)
+	// BulkAppend stores a batch of API request records in a single transaction (requires auth)
+	BulkAppend(ctx context.Context, in *BulkAppendRequest, opts ...grpc.CallOption) (*BulkAppendResponse, error)
+	// SetIngestionPaused pauses or resumes storing received requests, e.g. for maintenance (requires auth)
+	SetIngestionPaused(ctx context.Context, in *SetIngestionPausedRequest, opts ...grpc.CallOption) (*SetIngestionPausedResponse, error)
+	// GetStorageInfo reports the database file size, record count and stored time range (requires auth)
+	GetStorageInfo(ctx context.Context, in *GetStorageInfoRequest, opts ...grpc.CallOption) (*GetStorageInfoResponse, error)
 }
 
 type queryServiceClient struct {
@@ -54,6 +74,142 @@ func (c *queryServiceClient) GetAPIRequests(ctx context.Context, in *GetAPIReque
 	return out, nil
 }
 
+func (c *queryServiceClient) DeleteByPeriod(ctx context.Context, in *DeleteByPeriodRequest, opts ...grpc.CallOption) (*DeleteByPeriodResponse, error) {
+	out := new(DeleteByPeriodResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/DeleteByPeriod", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
+	out := new(HealthCheckResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/HealthCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) SetNote(ctx context.Context, in *SetNoteRequest, opts ...grpc.CallOption) (*SetNoteResponse, error) {
+	out := new(SetNoteResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/SetNote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (QueryService_BackupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[0], "/ccmon.v1.QueryService/Backup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryServiceBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type QueryService_BackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type queryServiceBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *queryServiceClient) GetDashboard(ctx context.Context, in *GetDashboardRequest, opts ...grpc.CallOption) (*GetDashboardResponse, error) {
+	out := new(GetDashboardResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/GetDashboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetModels(ctx context.Context, in *GetModelsRequest, opts ...grpc.CallOption) (*GetModelsResponse, error) {
+	out := new(GetModelsResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/GetModels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) StreamStats(ctx context.Context, in *StreamStatsRequest, opts ...grpc.CallOption) (QueryService_StreamStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[1], "/ccmon.v1.QueryService/StreamStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryServiceStreamStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type QueryService_StreamStatsClient interface {
+	Recv() (*StatsUpdate, error)
+	grpc.ClientStream
+}
+
+type queryServiceStreamStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceStreamStatsClient) Recv() (*StatsUpdate, error) {
+	m := new(StatsUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *queryServiceClient) BulkAppend(ctx context.Context, in *BulkAppendRequest, opts ...grpc.CallOption) (*BulkAppendResponse, error) {
+	out := new(BulkAppendResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/BulkAppend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) SetIngestionPaused(ctx context.Context, in *SetIngestionPausedRequest, opts ...grpc.CallOption) (*SetIngestionPausedResponse, error) {
+	out := new(SetIngestionPausedResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/SetIngestionPaused", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetStorageInfo(ctx context.Context, in *GetStorageInfoRequest, opts ...grpc.CallOption) (*GetStorageInfoResponse, error) {
+	out := new(GetStorageInfoResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/GetStorageInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 // All implementations must embed UnimplementedQueryServiceServer
 // for forward compatibility
@@ -62,6 +218,26 @@ type QueryServiceServer interface {
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	// GetAPIRequests returns API request records
 	GetAPIRequests(context.Context, *GetAPIRequestsRequest) (*GetAPIRequestsResponse, error)
+	// DeleteByPeriod deletes API request records within a time range (requires auth)
+	DeleteByPeriod(context.Context, *DeleteByPeriodRequest) (*DeleteByPeriodResponse, error)
+	// HealthCheck round-trips a synthetic record through the OTLP receiver and store
+	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	// SetNote attaches a manual note to the request identified by session and timestamp
+	SetNote(context.Context, *SetNoteRequest) (*SetNoteResponse, error)
+	// Backup streams a consistent snapshot of the database file
+	Backup(*BackupRequest, QueryService_BackupServer) error
+	// GetDashboard returns the period stats, block stats and plan in a single call
+	GetDashboard(context.Context, *GetDashboardRequest) (*GetDashboardResponse, error)
+	// GetModels returns the distinct models in the store with their usage
+	GetModels(context.Context, *GetModelsRequest) (*GetModelsResponse, error)
+	// StreamStats pushes the stats of a period on a server-driven interval and whenever new data arrives
+	StreamStats(*StreamStatsRequest, QueryService_StreamStatsServer) error
+	// BulkAppend stores a batch of API request records in a single transaction (requires auth)
+	BulkAppend(context.Context, *BulkAppendRequest) (*BulkAppendResponse, error)
+	// SetIngestionPaused pauses or resumes storing received requests, e.g. for maintenance (requires auth)
+	SetIngestionPaused(context.Context, *SetIngestionPausedRequest) (*SetIngestionPausedResponse, error)
+	// GetStorageInfo reports the database file size, record count and stored time range (requires auth)
+	GetStorageInfo(context.Context, *GetStorageInfoRequest) (*GetStorageInfoResponse, error)
 	mustEmbedUnimplementedQueryServiceServer()
 }
 
@@ -75,6 +251,36 @@ func (UnimplementedQueryServiceServer) GetStats(context.Context, *GetStatsReques
 func (UnimplementedQueryServiceServer) GetAPIRequests(context.Context, *GetAPIRequestsRequest) (*GetAPIRequestsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAPIRequests not implemented")
 }
+func (UnimplementedQueryServiceServer) DeleteByPeriod(context.Context, *DeleteByPeriodRequest) (*DeleteByPeriodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteByPeriod not implemented")
+}
+func (UnimplementedQueryServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
+}
+func (UnimplementedQueryServiceServer) SetNote(context.Context, *SetNoteRequest) (*SetNoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNote not implemented")
+}
+func (UnimplementedQueryServiceServer) Backup(*BackupRequest, QueryService_BackupServer) error {
+	return status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (UnimplementedQueryServiceServer) GetDashboard(context.Context, *GetDashboardRequest) (*GetDashboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDashboard not implemented")
+}
+func (UnimplementedQueryServiceServer) GetModels(context.Context, *GetModelsRequest) (*GetModelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModels not implemented")
+}
+func (UnimplementedQueryServiceServer) StreamStats(*StreamStatsRequest, QueryService_StreamStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStats not implemented")
+}
+func (UnimplementedQueryServiceServer) BulkAppend(context.Context, *BulkAppendRequest) (*BulkAppendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkAppend not implemented")
+}
+func (UnimplementedQueryServiceServer) SetIngestionPaused(context.Context, *SetIngestionPausedRequest) (*SetIngestionPausedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetIngestionPaused not implemented")
+}
+func (UnimplementedQueryServiceServer) GetStorageInfo(context.Context, *GetStorageInfoRequest) (*GetStorageInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageInfo not implemented")
+}
 func (UnimplementedQueryServiceServer) mustEmbedUnimplementedQueryServiceServer() {}
 
 // UnsafeQueryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -124,6 +330,192 @@ func _QueryService_GetAPIRequests_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_DeleteByPeriod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteByPeriodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).DeleteByPeriod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/DeleteByPeriod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).DeleteByPeriod(ctx, req.(*DeleteByPeriodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/HealthCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).HealthCheck(ctx, req.(*HealthCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_SetNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).SetNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/SetNote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).SetNote(ctx, req.(*SetNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).Backup(m, &queryServiceBackupServer{stream})
+}
+
+type QueryService_BackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type queryServiceBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_GetDashboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDashboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetDashboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/GetDashboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetDashboard(ctx, req.(*GetDashboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/GetModels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetModels(ctx, req.(*GetModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).StreamStats(m, &queryServiceStreamStatsServer{stream})
+}
+
+type QueryService_StreamStatsServer interface {
+	Send(*StatsUpdate) error
+	grpc.ServerStream
+}
+
+type queryServiceStreamStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceStreamStatsServer) Send(m *StatsUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_BulkAppend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkAppendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).BulkAppend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/BulkAppend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).BulkAppend(ctx, req.(*BulkAppendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_SetIngestionPaused_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetIngestionPausedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).SetIngestionPaused(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/SetIngestionPaused",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).SetIngestionPaused(ctx, req.(*SetIngestionPausedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetStorageInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetStorageInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/GetStorageInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetStorageInfo(ctx, req.(*GetStorageInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // QueryService_ServiceDesc is the grpc.ServiceDesc for QueryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -139,7 +531,50 @@ var QueryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAPIRequests",
 			Handler:    _QueryService_GetAPIRequests_Handler,
 		},
+		{
+			MethodName: "DeleteByPeriod",
+			Handler:    _QueryService_DeleteByPeriod_Handler,
+		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _QueryService_HealthCheck_Handler,
+		},
+		{
+			MethodName: "SetNote",
+			Handler:    _QueryService_SetNote_Handler,
+		},
+		{
+			MethodName: "GetDashboard",
+			Handler:    _QueryService_GetDashboard_Handler,
+		},
+		{
+			MethodName: "GetModels",
+			Handler:    _QueryService_GetModels_Handler,
+		},
+		{
+			MethodName: "BulkAppend",
+			Handler:    _QueryService_BulkAppend_Handler,
+		},
+		{
+			MethodName: "SetIngestionPaused",
+			Handler:    _QueryService_SetIngestionPaused_Handler,
+		},
+		{
+			MethodName: "GetStorageInfo",
+			Handler:    _QueryService_GetStorageInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Backup",
+			Handler:       _QueryService_Backup_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamStats",
+			Handler:       _QueryService_StreamStats_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/query.proto",
 }
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
@@ -15,6 +17,9 @@ const (
 	metadataBucket = "metadata"
 )
 
+// ErrReadOnlyRepository is returned when a write is attempted on a read-only database
+var ErrReadOnlyRepository = errors.New("write operation not supported on read-only database")
+
 // BoltDBAPIRequestRepository implements APIRequestRepository using BoltDB
 type BoltDBAPIRequestRepository struct {
 	db *bbolt.DB
@@ -29,9 +34,27 @@ func NewBoltDBAPIRequestRepository(db *bbolt.DB) *BoltDBAPIRequestRepository {
 
 // Save stores an API request entity
 func (r *BoltDBAPIRequestRepository) Save(req entity.APIRequest) error {
+	if r.db.IsReadOnly() {
+		return ErrReadOnlyRepository
+	}
 	return r.saveRequest(req)
 }
 
+// SaveBatch saves the API requests in a single transaction, so either all or none are stored
+func (r *BoltDBAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	if r.db.IsReadOnly() {
+		return ErrReadOnlyRepository
+	}
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		for _, req := range reqs {
+			if err := r.putRequest(tx, req); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // FindByPeriodWithLimit retrieves API requests filtered by time period with limit and offset
 // Use limit = 0 for no limit (fetch all records)
 // Use offset = 0 when no offset is needed
@@ -69,6 +92,10 @@ func (r *BoltDBAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
 // DeleteOlderThan deletes API requests older than the specified cutoff time
 // Returns the number of deleted records and any error
 func (r *BoltDBAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
+	if r.db.IsReadOnly() {
+		return 0, ErrReadOnlyRepository
+	}
+
 	deletedCount := 0
 
 	err := r.db.Update(func(tx *bbolt.Tx) error {
@@ -108,6 +135,185 @@ func (r *BoltDBAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int,
 	return deletedCount, err
 }
 
+// CountRequests returns the number of stored API requests
+func (r *BoltDBAPIRequestRepository) CountRequests() (int, error) {
+	count := 0
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		count = tx.Bucket([]byte(requestsBucket)).Stats().KeyN
+		return nil
+	})
+	return count, err
+}
+
+// DeleteOldestBeyond deletes the oldest API requests until at most maxRecords remain
+// Keys start with the timestamp, so the first keys are the oldest requests
+// Health check probes are skipped, so a running health check can still read its record back
+// Returns the number of deleted records and any error
+func (r *BoltDBAPIRequestRepository) DeleteOldestBeyond(maxRecords int) (int, error) {
+	if r.db.IsReadOnly() {
+		return 0, ErrReadOnlyRepository
+	}
+
+	deletedCount := 0
+
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(requestsBucket))
+
+		excess := bucket.Stats().KeyN - maxRecords
+		if excess <= 0 {
+			return nil
+		}
+
+		// Collect keys to delete
+		keysToDelete := make([][]byte, 0, excess)
+		c := bucket.Cursor()
+		for k, _ := c.First(); k != nil && len(keysToDelete) < excess; k, _ = c.Next() {
+			// Keys are "<timestamp>_<session ID>"
+			if _, sessionID, _ := strings.Cut(string(k), "_"); strings.HasPrefix(sessionID, entity.HealthCheckSessionPrefix) {
+				continue
+			}
+
+			// Make a copy of the key since it's only valid for the life of the transaction
+			keyToDelete := make([]byte, len(k))
+			copy(keyToDelete, k)
+			keysToDelete = append(keysToDelete, keyToDelete)
+		}
+
+		// Delete collected keys
+		for _, key := range keysToDelete {
+			if err := bucket.Delete(key); err != nil {
+				return fmt.Errorf("failed to delete key %s: %w", string(key), err)
+			}
+			deletedCount++
+		}
+
+		return nil
+	})
+
+	return deletedCount, err
+}
+
+// DeleteByPeriod deletes API requests whose timestamp falls within the period
+// Returns the number of deleted records and any error
+func (r *BoltDBAPIRequestRepository) DeleteByPeriod(period entity.Period) (int, error) {
+	if r.db.IsReadOnly() {
+		return 0, ErrReadOnlyRepository
+	}
+
+	deletedCount := 0
+
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(requestsBucket))
+		c := bucket.Cursor()
+
+		// Use the same key range as queryTimeRangeWithLimit so deletions match queries
+		startKey := []byte(period.StartAt().Format(time.RFC3339Nano))
+		endKey := []byte(period.EndAt().Format(time.RFC3339Nano) + "\xff")
+
+		// Collect keys to delete
+		var keysToDelete [][]byte
+		for k, _ := c.Seek(startKey); k != nil && string(k) < string(endKey); k, _ = c.Next() {
+			// Make a copy of the key since it's only valid for the life of the transaction
+			keyToDelete := make([]byte, len(k))
+			copy(keyToDelete, k)
+			keysToDelete = append(keysToDelete, keyToDelete)
+		}
+
+		// Delete collected keys
+		for _, key := range keysToDelete {
+			if err := bucket.Delete(key); err != nil {
+				return fmt.Errorf("failed to delete key %s: %w", string(key), err)
+			}
+			deletedCount++
+		}
+
+		return nil
+	})
+
+	return deletedCount, err
+}
+
+// UpdateNote replaces the note of the request identified by session ID and timestamp
+// Returns false when no request matches
+func (r *BoltDBAPIRequestRepository) UpdateNote(sessionID string, timestamp time.Time, note string) (bool, error) {
+	if r.db.IsReadOnly() {
+		return false, ErrReadOnlyRepository
+	}
+
+	found := false
+
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(requestsBucket))
+		c := bucket.Cursor()
+
+		// Scan the same key range a single-instant query would match
+		startKey := []byte(timestamp.Format(time.RFC3339Nano))
+		endKey := []byte(timestamp.Format(time.RFC3339Nano) + "\xff")
+
+		for k, v := c.Seek(startKey); k != nil && string(k) < string(endKey); k, v = c.Next() {
+			var req schema.APIRequest
+			if err := json.Unmarshal(v, &req); err != nil {
+				// Skip malformed entries
+				continue
+			}
+			if req.SessionID != sessionID || !req.Timestamp.Equal(timestamp) {
+				continue
+			}
+
+			req.Note = note
+			data, err := json.Marshal(req)
+			if err != nil {
+				return fmt.Errorf("failed to serialize request: %w", err)
+			}
+
+			// Make a copy of the key since it's only valid for the life of the transaction
+			key := make([]byte, len(k))
+			copy(key, k)
+			found = true
+			return bucket.Put(key, data)
+		}
+
+		return nil
+	})
+
+	return found, err
+}
+
+// FindModels summarizes every stored model in a single pass over the requests,
+// without loading the requests into memory
+func (r *BoltDBAPIRequestRepository) FindModels() ([]entity.ModelUsage, error) {
+	summaries := make(map[string]entity.ModelUsage)
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(requestsBucket))
+		return bucket.ForEach(func(k, v []byte) error {
+			var req schema.APIRequest
+			if err := json.Unmarshal(v, &req); err != nil {
+				// Skip malformed entries
+				return nil
+			}
+
+			if usage, ok := summaries[req.Model]; ok {
+				summaries[req.Model] = usage.Add(req.Timestamp)
+			} else {
+				summaries[req.Model] = entity.NewModelUsage(entity.NewModel(req.Model), req.Timestamp, req.Timestamp, 1)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]entity.ModelUsage, 0, len(summaries))
+	for _, usage := range summaries {
+		models = append(models, usage)
+	}
+	entity.SortModelUsages(models)
+
+	return models, nil
+}
+
 // Close closes the database connection
 func (r *BoltDBAPIRequestRepository) Close() error {
 	return r.db.Close()
@@ -116,22 +322,27 @@ func (r *BoltDBAPIRequestRepository) Close() error {
 // saveRequest saves an API request to the database
 func (r *BoltDBAPIRequestRepository) saveRequest(req entity.APIRequest) error {
 	return r.db.Update(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(requestsBucket))
+		return r.putRequest(tx, req)
+	})
+}
 
-		// Use entity's ID method for key generation
-		key := req.ID()
+// putRequest writes an API request within the transaction
+func (r *BoltDBAPIRequestRepository) putRequest(tx *bbolt.Tx, req entity.APIRequest) error {
+	bucket := tx.Bucket([]byte(requestsBucket))
 
-		// Convert entity to database schema
-		dbReq := r.convertFromEntity(req)
+	// Use entity's ID method for key generation
+	key := req.ID()
 
-		// Serialize request to JSON
-		data, err := json.Marshal(dbReq)
-		if err != nil {
-			return fmt.Errorf("failed to serialize request: %w", err)
-		}
+	// Convert entity to database schema
+	dbReq := r.convertFromEntity(req)
 
-		return bucket.Put([]byte(key), data)
-	})
+	// Serialize request to JSON
+	data, err := json.Marshal(dbReq)
+	if err != nil {
+		return fmt.Errorf("failed to serialize request: %w", err)
+	}
+
+	return bucket.Put([]byte(key), data)
 }
 
 // queryTimeRangeWithLimit queries requests within a time range with limit and offset
@@ -293,7 +504,7 @@ func (r *BoltDBAPIRequestRepository) convertToEntity(dbReq schema.APIRequest) en
 		tokens,
 		cost,
 		dbReq.DurationMS,
-	)
+	).WithLabels(dbReq.Labels).WithNote(dbReq.Note)
 }
 
 // convertFromEntity converts an entity APIRequest to a database APIRequest
@@ -309,6 +520,8 @@ func (r *BoltDBAPIRequestRepository) convertFromEntity(e entity.APIRequest) sche
 		TotalTokens:         e.Tokens().Total(),
 		CostUSD:             e.Cost().Amount(),
 		DurationMS:          e.DurationMS(),
+		Labels:              e.Labels(),
+		Note:                e.Note(),
 	}
 }
 
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -295,6 +298,535 @@ func TestBoltDBAPIRequestRepository_DeleteOlderThanDatabaseError(t *testing.T) {
 	}
 }
 
+func TestBoltDBAPIRequestRepository_DeleteByPeriod(t *testing.T) {
+	t.Parallel()
+
+	records := []schema.APIRequest{
+		createTestRecord("session1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+		createTestRecord("session2", time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)),
+		createTestRecord("session3", time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC)),
+		createTestRecord("session4", time.Date(2025, 1, 4, 10, 0, 0, 0, time.UTC)),
+	}
+
+	tests := []struct {
+		name              string
+		period            entity.Period
+		expectedDeleted   int
+		expectedRemaining []string // session IDs that should remain
+	}{
+		{
+			name: "delete records within period",
+			period: entity.NewPeriod(
+				time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 3, 23, 59, 59, 0, time.UTC),
+			),
+			expectedDeleted:   2,
+			expectedRemaining: []string{"session1", "session4"},
+		},
+		{
+			name: "inclusive period boundaries",
+			period: entity.NewPeriod(
+				time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+			),
+			expectedDeleted:   2,
+			expectedRemaining: []string{"session3", "session4"},
+		},
+		{
+			name: "no records in period",
+			period: entity.NewPeriod(
+				time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC),
+			),
+			expectedDeleted:   0,
+			expectedRemaining: []string{"session1", "session2", "session3", "session4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := bbolt.Open(createTempDB(t), 0600, nil)
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			defer func() {
+				if err := db.Close(); err != nil {
+					t.Logf("Failed to close database: %v", err)
+				}
+			}()
+
+			err = db.Update(func(tx *bbolt.Tx) error {
+				_, err := tx.CreateBucket([]byte(requestsBucket))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to create bucket: %v", err)
+			}
+
+			repo := NewBoltDBAPIRequestRepository(db)
+			for _, record := range records {
+				if err := repo.Save(createTestEntity(record.SessionID, record.Timestamp)); err != nil {
+					t.Fatalf("Failed to save test record: %v", err)
+				}
+			}
+
+			deletedCount, err := repo.DeleteByPeriod(tt.period)
+			if err != nil {
+				t.Fatalf("DeleteByPeriod() error = %v", err)
+			}
+			if deletedCount != tt.expectedDeleted {
+				t.Errorf("DeleteByPeriod() deleted count = %d, want %d", deletedCount, tt.expectedDeleted)
+			}
+
+			remaining, err := repo.FindAll()
+			if err != nil {
+				t.Fatalf("Failed to fetch remaining records: %v", err)
+			}
+
+			var remainingSessions []string
+			for _, record := range remaining {
+				remainingSessions = append(remainingSessions, record.SessionID())
+			}
+			sort.Strings(remainingSessions)
+
+			if strings.Join(remainingSessions, ",") != strings.Join(tt.expectedRemaining, ",") {
+				t.Errorf("Remaining sessions = %v, want %v", remainingSessions, tt.expectedRemaining)
+			}
+		})
+	}
+}
+
+func TestBoltDBAPIRequestRepository_Labels(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	labeled := createTestEntity("session1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)).
+		WithLabels(map[string]string{"environment": "prod", "user.id": "alice"})
+	unlabeled := createTestEntity("session2", time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC))
+	for _, req := range []entity.APIRequest{labeled, unlabeled} {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Failed to save test record: %v", err)
+		}
+	}
+
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("FindAll() returned %d records, want 2", len(requests))
+	}
+
+	if value, _ := requests[0].Label("environment"); value != "prod" {
+		t.Errorf("Label(environment) = %q, want %q", value, "prod")
+	}
+	if value, _ := requests[0].Label("user.id"); value != "alice" {
+		t.Errorf("Label(user.id) = %q, want %q", value, "alice")
+	}
+	if len(requests[1].Labels()) != 0 {
+		t.Errorf("Expected no labels on unlabeled record, got %v", requests[1].Labels())
+	}
+}
+
+func TestBoltDBAPIRequestRepository_UpdateNote(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	annotatedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	prunedAt := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	for _, req := range []entity.APIRequest{
+		createTestEntity("session1", annotatedAt),
+		createTestEntity("session2", annotatedAt),
+		createTestEntity("session1", prunedAt),
+	} {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Failed to save test record: %v", err)
+		}
+	}
+
+	found, err := repo.UpdateNote("session1", annotatedAt, "the big refactor run")
+	if err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	if !found {
+		t.Fatal("UpdateNote() found = false, want true")
+	}
+	if _, err := repo.UpdateNote("session1", prunedAt, "pruned with its request"); err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+
+	found, err = repo.UpdateNote("session3", annotatedAt, "missing")
+	if err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	if found {
+		t.Error("UpdateNote() found = true for unknown session, want false")
+	}
+
+	// Pruning removes the old request together with its note but keeps the others
+	if _, err := repo.DeleteOlderThan(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("DeleteOlderThan() error = %v", err)
+	}
+
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("FindAll() returned %d records, want 2", len(requests))
+	}
+
+	notes := make(map[string]string)
+	for _, req := range requests {
+		notes[req.SessionID()] = req.Note()
+	}
+	if notes["session1"] != "the big refactor run" {
+		t.Errorf("Note(session1) = %q, want %q", notes["session1"], "the big refactor run")
+	}
+	if notes["session2"] != "" {
+		t.Errorf("Note(session2) = %q, want empty", notes["session2"])
+	}
+}
+
+func TestBoltDBAPIRequestRepository_SaveBatch(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	batch := []entity.APIRequest{
+		createTestEntity("session1", timestamp),
+		createTestEntity("session2", timestamp),
+		createTestEntity("session1", timestamp.Add(time.Minute)),
+	}
+
+	// Saving the same batch twice stores each request once under its ID
+	for range 2 {
+		if err := repo.SaveBatch(batch); err != nil {
+			t.Fatalf("SaveBatch() error = %v", err)
+		}
+	}
+
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(requests) != len(batch) {
+		t.Errorf("FindAll() returned %d records, want %d", len(requests), len(batch))
+	}
+}
+
+func TestBoltDBAPIRequestRepository_FindModels(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	firstAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	lastAt := time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC)
+	for _, req := range []entity.APIRequest{
+		entity.NewAPIRequest("session1", lastAt, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+		entity.NewAPIRequest("session1", firstAt, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+		entity.NewAPIRequest("session2", firstAt.Add(time.Hour), "claude-3-5-haiku-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+	} {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Failed to save test record: %v", err)
+		}
+	}
+
+	models, err := repo.FindModels()
+	if err != nil {
+		t.Fatalf("FindModels() error = %v", err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("FindModels() returned %d models, want 2", len(models))
+	}
+
+	sonnet := models[0]
+	if sonnet.Model().String() != "claude-sonnet-4-20250514" || sonnet.Requests() != 2 {
+		t.Errorf("models[0] = %s with %d requests, want claude-sonnet-4-20250514 with 2", sonnet.Model().String(), sonnet.Requests())
+	}
+	if !sonnet.FirstSeenAt().Equal(firstAt) || !sonnet.LastSeenAt().Equal(lastAt) {
+		t.Errorf("models[0] seen %v - %v, want %v - %v", sonnet.FirstSeenAt(), sonnet.LastSeenAt(), firstAt, lastAt)
+	}
+	if haiku := models[1]; haiku.Model().String() != "claude-3-5-haiku-20241022" || haiku.Requests() != 1 {
+		t.Errorf("models[1] = %s with %d requests, want claude-3-5-haiku-20241022 with 1", haiku.Model().String(), haiku.Requests())
+	}
+}
+
+func TestBoltDBAPIRequestRepository_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	dbPath := createTempDB(t)
+
+	// Seed the database with a single record
+	db, err := bbolt.Open(dbPath, 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+	if err := NewBoltDBAPIRequestRepository(db).Save(createTestEntity("session1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))); err != nil {
+		t.Fatalf("Failed to save test record: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+
+	// Reopen the database in read-only mode
+	readOnlyDB, err := bbolt.Open(dbPath, 0600, &bbolt.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Failed to open read-only database: %v", err)
+	}
+	defer func() {
+		if err := readOnlyDB.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	repo := NewBoltDBAPIRequestRepository(readOnlyDB)
+
+	if err := repo.Save(createTestEntity("session2", time.Now())); !errors.Is(err, ErrReadOnlyRepository) {
+		t.Errorf("Save() error = %v, want %v", err, ErrReadOnlyRepository)
+	}
+
+	deletedCount, err := repo.DeleteOlderThan(time.Now())
+	if !errors.Is(err, ErrReadOnlyRepository) {
+		t.Errorf("DeleteOlderThan() error = %v, want %v", err, ErrReadOnlyRepository)
+	}
+	if deletedCount != 0 {
+		t.Errorf("DeleteOlderThan() deleted count = %d, want 0", deletedCount)
+	}
+
+	if _, err := repo.DeleteByPeriod(entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Now())); !errors.Is(err, ErrReadOnlyRepository) {
+		t.Errorf("DeleteByPeriod() error = %v, want %v", err, ErrReadOnlyRepository)
+	}
+
+	if _, err := repo.DeleteOldestBeyond(0); !errors.Is(err, ErrReadOnlyRepository) {
+		t.Errorf("DeleteOldestBeyond() error = %v, want %v", err, ErrReadOnlyRepository)
+	}
+
+	if _, err := repo.UpdateNote("session1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), "note"); !errors.Is(err, ErrReadOnlyRepository) {
+		t.Errorf("UpdateNote() error = %v, want %v", err, ErrReadOnlyRepository)
+	}
+
+	if err := repo.SaveBatch([]entity.APIRequest{createTestEntity("session3", time.Now())}); !errors.Is(err, ErrReadOnlyRepository) {
+		t.Errorf("SaveBatch() error = %v, want %v", err, ErrReadOnlyRepository)
+	}
+
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("FindAll() returned %d records, want 1", len(requests))
+	}
+}
+
+func TestBoltDBAPIRequestRepository_DeleteOldestBeyond(t *testing.T) {
+	t.Parallel()
+
+	// Saved out of order, eviction follows the timestamps rather than insertion order
+	records := []entity.APIRequest{
+		createTestEntity("session3", time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC)),
+		createTestEntity("session1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+		createTestEntity("session4", time.Date(2025, 1, 4, 10, 0, 0, 0, time.UTC)),
+		createTestEntity("session2", time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)),
+	}
+
+	tests := []struct {
+		name              string
+		records           []entity.APIRequest
+		maxRecords        int
+		expectedDeleted   int
+		expectedRemaining []string // session IDs that should remain
+	}{
+		{
+			name:              "evict oldest beyond maximum",
+			records:           records,
+			maxRecords:        2,
+			expectedDeleted:   2,
+			expectedRemaining: []string{"session3", "session4"},
+		},
+		{
+			name:              "at maximum",
+			records:           records,
+			maxRecords:        4,
+			expectedDeleted:   0,
+			expectedRemaining: []string{"session1", "session2", "session3", "session4"},
+		},
+		{
+			name:              "under maximum",
+			records:           records,
+			maxRecords:        10,
+			expectedDeleted:   0,
+			expectedRemaining: []string{"session1", "session2", "session3", "session4"},
+		},
+		{
+			name:              "zero maximum",
+			records:           records,
+			maxRecords:        0,
+			expectedDeleted:   4,
+			expectedRemaining: []string{},
+		},
+		{
+			name:              "empty database",
+			records:           nil,
+			maxRecords:        2,
+			expectedDeleted:   0,
+			expectedRemaining: []string{},
+		},
+		{
+			name:              "health check probe is kept",
+			records:           append([]entity.APIRequest{createTestEntity(entity.HealthCheckSessionPrefix+"probe", time.Unix(3600, 0).UTC())}, records...),
+			maxRecords:        2,
+			expectedDeleted:   3,
+			expectedRemaining: []string{entity.HealthCheckSessionPrefix + "probe", "session4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := bbolt.Open(createTempDB(t), 0600, nil)
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			defer func() {
+				if err := db.Close(); err != nil {
+					t.Logf("Failed to close database: %v", err)
+				}
+			}()
+
+			err = db.Update(func(tx *bbolt.Tx) error {
+				_, err := tx.CreateBucket([]byte(requestsBucket))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to create bucket: %v", err)
+			}
+
+			repo := NewBoltDBAPIRequestRepository(db)
+			for _, record := range tt.records {
+				if err := repo.Save(record); err != nil {
+					t.Fatalf("Failed to save test record: %v", err)
+				}
+			}
+
+			count, err := repo.CountRequests()
+			if err != nil {
+				t.Fatalf("CountRequests() error = %v", err)
+			}
+			if count != len(tt.records) {
+				t.Errorf("CountRequests() = %d, want %d", count, len(tt.records))
+			}
+
+			deletedCount, err := repo.DeleteOldestBeyond(tt.maxRecords)
+			if err != nil {
+				t.Fatalf("DeleteOldestBeyond() error = %v", err)
+			}
+			if deletedCount != tt.expectedDeleted {
+				t.Errorf("DeleteOldestBeyond() deleted count = %d, want %d", deletedCount, tt.expectedDeleted)
+			}
+
+			remaining, err := repo.FindAll()
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+			if len(remaining) != len(tt.expectedRemaining) {
+				t.Fatalf("FindAll() returned %d records, want %d", len(remaining), len(tt.expectedRemaining))
+			}
+
+			remainingSessions := make(map[string]bool)
+			for _, record := range remaining {
+				remainingSessions[record.SessionID()] = true
+			}
+			for _, sessionID := range tt.expectedRemaining {
+				if !remainingSessions[sessionID] {
+					t.Errorf("Expected session %s to remain", sessionID)
+				}
+			}
+		})
+	}
+}
+
 // Helper functions
 
 func createTempDB(t *testing.T) string {
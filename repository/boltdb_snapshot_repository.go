@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/repository/schema"
+	"go.etcd.io/bbolt"
+)
+
+// BoltDBSnapshotRepository implements usecase.SnapshotRepository by copying the BoltDB file
+type BoltDBSnapshotRepository struct {
+	db *bbolt.DB
+}
+
+// NewBoltDBSnapshotRepository creates a new BoltDB snapshot repository instance
+func NewBoltDBSnapshotRepository(db *bbolt.DB) *BoltDBSnapshotRepository {
+	return &BoltDBSnapshotRepository{
+		db: db,
+	}
+}
+
+// WriteSnapshot copies the database within a single read transaction, so writes made
+// while the copy runs are never partially included and the server keeps ingesting
+func (r *BoltDBSnapshotRepository) WriteSnapshot(w io.Writer) (int, error) {
+	var records int
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		records = countRequests(tx)
+		_, err := tx.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return records, nil
+}
+
+// CountRecords returns the number of stored requests
+func (r *BoltDBSnapshotRepository) CountRecords() (int, error) {
+	var records int
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		records = countRequests(tx)
+		return nil
+	})
+	return records, err
+}
+
+// GetStorageInfo returns the size of the database file with the record count and the stored time range.
+// Keys start with the timestamp, so the first and last keys hold the oldest and newest requests.
+func (r *BoltDBSnapshotRepository) GetStorageInfo() (entity.StorageInfo, error) {
+	stat, err := os.Stat(r.db.Path())
+	if err != nil {
+		return entity.StorageInfo{}, fmt.Errorf("failed to read database size: %w", err)
+	}
+
+	var records int
+	var oldestAt, newestAt time.Time
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		records = countRequests(tx)
+		if records == 0 {
+			return nil
+		}
+
+		c := tx.Bucket([]byte(requestsBucket)).Cursor()
+		_, first := c.First()
+		oldest, err := requestTimestamp(first)
+		if err != nil {
+			return err
+		}
+		_, last := c.Last()
+		newest, err := requestTimestamp(last)
+		if err != nil {
+			return err
+		}
+
+		oldestAt, newestAt = oldest, newest
+		return nil
+	})
+	if err != nil {
+		return entity.StorageInfo{}, err
+	}
+
+	return entity.NewStorageInfo(stat.Size(), records, oldestAt, newestAt), nil
+}
+
+// requestTimestamp returns the timestamp of a stored request
+func requestTimestamp(data []byte) (time.Time, error) {
+	var req schema.APIRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return time.Time{}, fmt.Errorf("failed to deserialize request: %w", err)
+	}
+	return req.Timestamp, nil
+}
+
+// countRequests returns the number of keys in the requests bucket
+func countRequests(tx *bbolt.Tx) int {
+	bucket := tx.Bucket([]byte(requestsBucket))
+	if bucket == nil {
+		return 0
+	}
+	return bucket.Stats().KeyN
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"go.etcd.io/bbolt"
+)
+
+func TestBoltDBSnapshotRepository_WriteSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		records int
+	}{
+		{name: "empty database", records: 0},
+		{name: "database with requests", records: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := bbolt.Open(createTempDB(t), 0600, nil)
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			defer func() { _ = db.Close() }()
+
+			err = db.Update(func(tx *bbolt.Tx) error {
+				_, err := tx.CreateBucket([]byte(requestsBucket))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to create bucket: %v", err)
+			}
+
+			requestRepo := NewBoltDBAPIRequestRepository(db)
+			base := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+			for i := 0; i < tt.records; i++ {
+				req := entity.NewAPIRequest("session", base.Add(time.Duration(i)*time.Minute), "claude-3-sonnet", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+				if err := requestRepo.Save(req); err != nil {
+					t.Fatalf("Failed to save request: %v", err)
+				}
+			}
+
+			snapshotPath := filepath.Join(t.TempDir(), "snapshot.db")
+			snapshot, err := os.Create(snapshotPath)
+			if err != nil {
+				t.Fatalf("Failed to create snapshot file: %v", err)
+			}
+
+			records, err := NewBoltDBSnapshotRepository(db).WriteSnapshot(snapshot)
+			if closeErr := snapshot.Close(); closeErr != nil {
+				t.Fatalf("Failed to close snapshot file: %v", closeErr)
+			}
+			if err != nil {
+				t.Fatalf("WriteSnapshot() error = %v", err)
+			}
+			if records != tt.records {
+				t.Errorf("WriteSnapshot() records = %d, want %d", records, tt.records)
+			}
+
+			// The snapshot is a complete database that opens on its own
+			restored, err := bbolt.Open(snapshotPath, 0600, &bbolt.Options{ReadOnly: true})
+			if err != nil {
+				t.Fatalf("Failed to open snapshot: %v", err)
+			}
+			defer func() { _ = restored.Close() }()
+
+			count, err := NewBoltDBSnapshotRepository(restored).CountRecords()
+			if err != nil {
+				t.Fatalf("CountRecords() error = %v", err)
+			}
+			if count != tt.records {
+				t.Errorf("CountRecords() = %d, want %d", count, tt.records)
+			}
+
+			requests, err := NewBoltDBAPIRequestRepository(restored).FindAll()
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+			if len(requests) != tt.records {
+				t.Errorf("FindAll() returned %d requests, want %d", len(requests), tt.records)
+			}
+		})
+	}
+}
+
+func TestBoltDBSnapshotRepository_GetStorageInfo(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		records    int
+		wantOldest time.Time
+		wantNewest time.Time
+	}{
+		{name: "empty database", records: 0},
+		{name: "database with requests", records: 3, wantOldest: base, wantNewest: base.Add(2 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := createTempDB(t)
+			db, err := bbolt.Open(path, 0600, nil)
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			defer func() { _ = db.Close() }()
+
+			err = db.Update(func(tx *bbolt.Tx) error {
+				_, err := tx.CreateBucket([]byte(requestsBucket))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to create bucket: %v", err)
+			}
+
+			requestRepo := NewBoltDBAPIRequestRepository(db)
+			for i := tt.records - 1; i >= 0; i-- {
+				req := entity.NewAPIRequest("session", base.Add(time.Duration(i)*time.Minute), "claude-3-sonnet", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+				if err := requestRepo.Save(req); err != nil {
+					t.Fatalf("Failed to save request: %v", err)
+				}
+			}
+
+			info, err := NewBoltDBSnapshotRepository(db).GetStorageInfo()
+			if err != nil {
+				t.Fatalf("GetStorageInfo() error = %v", err)
+			}
+			if info.Records() != tt.records {
+				t.Errorf("Records() = %d, want %d", info.Records(), tt.records)
+			}
+			if !info.OldestAt().Equal(tt.wantOldest) || !info.NewestAt().Equal(tt.wantNewest) {
+				t.Errorf("range = %v - %v, want %v - %v", info.OldestAt(), info.NewestAt(), tt.wantOldest, tt.wantNewest)
+			}
+
+			stat, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("Failed to stat database: %v", err)
+			}
+			if info.SizeBytes() != stat.Size() || info.SizeBytes() == 0 {
+				t.Errorf("SizeBytes() = %d, want the file size %d", info.SizeBytes(), stat.Size())
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 // This is used on the server side where we have direct access to the BoltDB request data
 type BoltDBStatsRepository struct {
 	apiRequestRepository usecase.APIRequestRepository
+	ignoredModels        entity.ModelIgnoreList
+	classifier           entity.ModelClassifier
 }
 
 // NewBoltDBStatsRepository creates a new BoltDBStatsRepository
@@ -18,14 +20,28 @@ func NewBoltDBStatsRepository(apiRequestRepository usecase.APIRequestRepository)
 	}
 }
 
-// GetStatsByPeriod retrieves statistics by calculating them from API requests
-func (r *BoltDBStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+// SetIgnoredModels excludes requests for the matching models from the calculated stats
+func (r *BoltDBStatsRepository) SetIgnoredModels(ignoredModels entity.ModelIgnoreList) {
+	r.ignoredModels = ignoredModels
+}
+
+// SetClassifier sets the model tier overrides applied when splitting base and premium usage
+func (r *BoltDBStatsRepository) SetClassifier(classifier entity.ModelClassifier) {
+	r.classifier = classifier
+}
+
+// GetStats retrieves statistics by calculating them from API requests
+func (r *BoltDBStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
 	// Get all requests for the period (no limit)
+	period := query.Period()
 	requests, err := r.apiRequestRepository.FindByPeriodWithLimit(period, 0, 0)
 	if err != nil {
 		return entity.Stats{}, err
 	}
 
-	// Calculate stats from requests
-	return entity.NewStatsFromRequests(requests, period), nil
+	// Calculate stats from requests, leaving out ignored models entirely
+	requests = r.ignoredModels.Filter(requests)
+	// Overrides sent with the query win over the configured ones, e.g. edited in a monitor
+	stats := entity.NewClassifiedStatsFromRequests(requests, period, r.classifier.Merge(query.Classifier()))
+	return stats.WithNegligible(requests, query.NegligibleCost()), nil
 }
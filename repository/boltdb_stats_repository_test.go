@@ -9,13 +9,14 @@ import (
 	"github.com/elct9620/ccmon/testutil"
 )
 
-func TestBoltDBStatsRepository_GetStatsByPeriod(t *testing.T) {
+func TestBoltDBStatsRepository_GetStats(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name          string
 		requests      []entity.APIRequest
 		period        entity.Period
+		ignoredModels []string
 		repositoryErr error
 		expectedStats entity.Stats
 		expectError   bool
@@ -85,6 +86,42 @@ func TestBoltDBStatsRepository_GetStatsByPeriod(t *testing.T) {
 			),
 			expectError: false,
 		},
+		{
+			name: "ignored models are excluded from base and premium stats",
+			requests: []entity.APIRequest{
+				entity.NewAPIRequest(
+					"session1",
+					time.Date(2025, 7, 24, 10, 0, 0, 0, time.UTC),
+					"claude-3-haiku-20240307", // base model
+					entity.NewToken(100, 80, 0, 0),
+					entity.NewCost(5.0),
+					1000,
+				),
+				entity.NewAPIRequest(
+					"session2",
+					time.Date(2025, 7, 24, 11, 0, 0, 0, time.UTC),
+					"local-llama-70b", // would otherwise count as premium
+					entity.NewToken(200, 150, 0, 0),
+					entity.NewCost(10.0),
+					2000,
+				),
+			},
+			period: entity.NewPeriod(
+				time.Date(2025, 7, 24, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 7, 24, 23, 59, 59, 999999999, time.UTC),
+			),
+			ignoredModels: []string{"local-*"},
+			expectedStats: entity.NewStats(
+				1, 0, // only the base request remains
+				entity.NewToken(100, 80, 0, 0), entity.NewToken(0, 0, 0, 0),
+				entity.NewCost(5.0), entity.NewCost(0),
+				entity.NewPeriod(
+					time.Date(2025, 7, 24, 0, 0, 0, 0, time.UTC),
+					time.Date(2025, 7, 24, 23, 59, 59, 999999999, time.UTC),
+				),
+			),
+			expectError: false,
+		},
 		{
 			name:          "repository error",
 			requests:      []entity.APIRequest{},
@@ -107,9 +144,10 @@ func TestBoltDBStatsRepository_GetStatsByPeriod(t *testing.T) {
 
 			// Create BoltDBStatsRepository
 			statsRepo := NewBoltDBStatsRepository(mockRepo)
+			statsRepo.SetIgnoredModels(entity.NewModelIgnoreList(tt.ignoredModels))
 
 			// Execute
-			result, err := statsRepo.GetStatsByPeriod(tt.period)
+			result, err := statsRepo.GetStats(entity.NewStatsQuery(tt.period))
 
 			// Verify error expectation
 			if tt.expectError {
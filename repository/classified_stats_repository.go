@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// ClassifiedStatsRepository implements usecase.StatsRepository for servers that may be older than
+// the model tier overrides. Queries are forwarded with their overrides, and only when the server
+// cannot apply them are the stats recalculated from the requests, so a reassigned model is still
+// reflected immediately.
+type ClassifiedStatsRepository struct {
+	statsRepository      usecase.StatsRepository
+	apiRequestRepository usecase.APIRequestRepository
+	unsupported          atomic.Bool // Set once the server rejected the overrides, to skip asking again
+}
+
+// NewClassifiedStatsRepository creates a new ClassifiedStatsRepository
+func NewClassifiedStatsRepository(statsRepository usecase.StatsRepository, apiRequestRepository usecase.APIRequestRepository) *ClassifiedStatsRepository {
+	return &ClassifiedStatsRepository{
+		statsRepository:      statsRepository,
+		apiRequestRepository: apiRequestRepository,
+	}
+}
+
+// GetStats uses the aggregated stats unless the server cannot apply the query's overrides
+func (r *ClassifiedStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	if !query.Classifier().HasOverrides() || !r.unsupported.Load() {
+		stats, err := r.statsRepository.GetStats(query)
+		if !errors.Is(err, usecase.ErrModelTiersUnsupported) {
+			return stats, err
+		}
+		r.unsupported.Store(true)
+	}
+
+	period := query.Period()
+	requests, err := r.apiRequestRepository.FindByPeriodWithLimit(period, 0, 0)
+	if err != nil {
+		return entity.Stats{}, err
+	}
+
+	stats := entity.NewClassifiedStatsFromRequests(requests, period, query.Classifier())
+	return stats.WithNegligible(requests, query.NegligibleCost()), nil
+}
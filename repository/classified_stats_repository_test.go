@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// tierStatsRepository stands in for a server, rejecting overrides unless it supports them
+type tierStatsRepository struct {
+	usecase.StatsRepository
+	supported bool
+	calls     int
+}
+
+func (r *tierStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	r.calls++
+	if query.Classifier().HasOverrides() && !r.supported {
+		return entity.Stats{}, usecase.ErrModelTiersUnsupported
+	}
+	return r.StatsRepository.GetStats(query)
+}
+
+func TestClassifiedStatsRepository_GetStats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	period := entity.NewPeriod(now.Add(-time.Hour), now)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", now.Add(-30*time.Minute), "claude-3-haiku-20240307", 100, 50, 0.1),
+		testutil.CreateTestAPIRequest("s1", now.Add(-20*time.Minute), "local-llama", 100, 50, 0.2),
+	}
+
+	tests := []struct {
+		name            string
+		overrides       map[string]entity.ModelTier
+		supported       bool
+		expectedBase    int
+		expectedPremium int
+		expectedCalls   int
+	}{
+		{
+			name:            "no overrides use the aggregated stats",
+			expectedBase:    1,
+			expectedPremium: 1,
+			expectedCalls:   2,
+		},
+		{
+			name:            "overrides are applied by the server",
+			overrides:       map[string]entity.ModelTier{"local-llama": entity.ModelTierBase},
+			supported:       true,
+			expectedBase:    2,
+			expectedPremium: 0,
+			expectedCalls:   2,
+		},
+		{
+			name:            "older servers are asked once, then requests are reclassified",
+			overrides:       map[string]entity.ModelTier{"local-llama": entity.ModelTierBase},
+			expectedBase:    2,
+			expectedPremium: 0,
+			expectedCalls:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			apiRepo.SetMockData(requests)
+			statsRepo := &tierStatsRepository{StatsRepository: NewBoltDBStatsRepository(apiRepo), supported: tt.supported}
+
+			repo := NewClassifiedStatsRepository(statsRepo, apiRepo)
+			query := entity.NewStatsQuery(period).WithClassifier(entity.NewModelClassifier(tt.overrides))
+			for i := 0; i < 2; i++ {
+				stats, err := repo.GetStats(query)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if stats.BaseRequests() != tt.expectedBase || stats.PremiumRequests() != tt.expectedPremium {
+					t.Errorf("expected %d base / %d premium requests, got %d / %d",
+						tt.expectedBase, tt.expectedPremium, stats.BaseRequests(), stats.PremiumRequests())
+				}
+			}
+
+			if statsRepo.calls != tt.expectedCalls {
+				t.Errorf("expected %d stats repository calls, got %d", tt.expectedCalls, statsRepo.calls)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
 )
 
 type FileSystem interface {
@@ -19,6 +20,7 @@ type EmbeddedPlanRepository struct {
 	config PlanConfig
 	dataFS FileSystem
 	plans  map[string]PlanData
+	strict bool
 }
 
 type PlanData struct {
@@ -32,6 +34,7 @@ type PlansDocument struct {
 
 type PlanConfig interface {
 	GetClaudePlan() string
+	GetClaudePlanPrice() float64 // 0 keeps the price in plans.json
 }
 
 func NewEmbeddedPlanRepository(config PlanConfig, dataFS FileSystem) (*EmbeddedPlanRepository, error) {
@@ -52,6 +55,11 @@ func NewEmbeddedPlanRepository(config PlanConfig, dataFS FileSystem) (*EmbeddedP
 	}, nil
 }
 
+// SetStrict disables the fallback to the unset plan, a plan missing from plans.json is reported instead
+func (r *EmbeddedPlanRepository) SetStrict(strict bool) {
+	r.strict = strict
+}
+
 func (r *EmbeddedPlanRepository) GetConfiguredPlan() (entity.Plan, error) {
 	planName := r.config.GetClaudePlan()
 	if planName == "" {
@@ -59,10 +67,18 @@ func (r *EmbeddedPlanRepository) GetConfiguredPlan() (entity.Plan, error) {
 	}
 
 	planData, exists := r.plans[planName]
+	if !exists && !r.strict {
+		planData, exists = r.plans["unset"]
+	}
 	if !exists {
-		planData = r.plans["unset"]
+		return entity.Plan{}, fmt.Errorf("plan %q is not in plans.json: %w", planName, usecase.ErrPlanUnavailable)
+	}
+
+	price := planData.Price
+	if override := r.config.GetClaudePlanPrice(); override > 0 {
+		price = override
 	}
 
-	cost := entity.NewCost(planData.Price)
+	cost := entity.NewCost(price)
 	return entity.NewPlan(planData.Name, cost), nil
 }
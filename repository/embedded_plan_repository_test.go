@@ -2,7 +2,10 @@ package repository
 
 import (
 	"embed"
+	"errors"
 	"testing"
+
+	"github.com/elct9620/ccmon/usecase"
 )
 
 //go:embed testdata/*
@@ -22,13 +25,18 @@ func (fs testDataEmbedFS) ReadFile(name string) ([]byte, error) {
 var mockDataFS = testDataEmbedFS{testDataFS}
 
 type mockPlanConfig struct {
-	plan string
+	plan  string
+	price float64
 }
 
 func (m *mockPlanConfig) GetClaudePlan() string {
 	return m.plan
 }
 
+func (m *mockPlanConfig) GetClaudePlanPrice() float64 {
+	return m.price
+}
+
 func TestNewEmbeddedPlanRepository(t *testing.T) {
 	config := &mockPlanConfig{plan: "pro"}
 
@@ -101,6 +109,98 @@ func TestGetConfiguredPlan(t *testing.T) {
 	}
 }
 
+// staticFS serves the same content for every file
+type staticFS []byte
+
+func (fs staticFS) ReadFile(name string) ([]byte, error) {
+	return fs, nil
+}
+
+func TestGetConfiguredPlanPriceOverride(t *testing.T) {
+	tests := []struct {
+		name         string
+		configPlan   string
+		configPrice  float64
+		expectedName string
+		expectedCost float64
+	}{
+		{"price overrides the plan price", "pro", 17.0, "pro", 17.0},
+		{"zero keeps the plan price", "max", 0, "max", 100.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewEmbeddedPlanRepository(&mockPlanConfig{plan: tt.configPlan, price: tt.configPrice}, mockDataFS)
+			if err != nil {
+				t.Fatalf("Failed to create repository: %v", err)
+			}
+
+			plan, err := repo.GetConfiguredPlan()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if plan.Name() != tt.expectedName {
+				t.Errorf("Expected plan name %s, got %s", tt.expectedName, plan.Name())
+			}
+			if plan.Price().Amount() != tt.expectedCost {
+				t.Errorf("Expected plan cost %.1f, got %.1f", tt.expectedCost, plan.Price().Amount())
+			}
+		})
+	}
+}
+
+func TestGetConfiguredPlanUnavailable(t *testing.T) {
+	// Without an unset plan there is nothing to fall back to
+	dataFS := staticFS(`{"plans": {"pro": {"name": "pro", "price": 20}}}`)
+
+	repo, err := NewEmbeddedPlanRepository(&mockPlanConfig{plan: "invalid"}, dataFS)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	if _, err := repo.GetConfiguredPlan(); !errors.Is(err, usecase.ErrPlanUnavailable) {
+		t.Errorf("Expected ErrPlanUnavailable, got %v", err)
+	}
+}
+
+func TestGetConfiguredPlanStrict(t *testing.T) {
+	tests := []struct {
+		name         string
+		plan         string
+		expectedName string
+		wantErr      bool
+	}{
+		{"configured plan resolves", "pro", "pro", false},
+		{"empty plan resolves to unset", "", "unset", false},
+		{"missing plan does not fall back", "invalid", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewEmbeddedPlanRepository(&mockPlanConfig{plan: tt.plan}, mockDataFS)
+			if err != nil {
+				t.Fatalf("Failed to create repository: %v", err)
+			}
+			repo.SetStrict(true)
+
+			plan, err := repo.GetConfiguredPlan()
+			if tt.wantErr {
+				if !errors.Is(err, usecase.ErrPlanUnavailable) {
+					t.Errorf("Expected ErrPlanUnavailable, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if plan.Name() != tt.expectedName {
+				t.Errorf("Expected plan name %s, got %s", tt.expectedName, plan.Name())
+			}
+		})
+	}
+}
+
 func TestPlanRepositoryInterface(t *testing.T) {
 	config := &mockPlanConfig{plan: "pro"}
 
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/spf13/viper"
+)
+
+// modelTiersFileKey is the key holding the model tier overrides in the overrides file
+const modelTiersFileKey = "model_tiers"
+
+// modelTierEntry is a [[model_tiers]] entry of the overrides file
+type modelTierEntry struct {
+	Model string `mapstructure:"model"`
+	Tier  string `mapstructure:"tier"`
+}
+
+// FileModelTierRepository implements usecase.ModelTierRepository on top of a TOML file of its own,
+// so saving overrides never rewrites the config file or drops its comments. Once the file exists,
+// its overrides replace the configured ones.
+type FileModelTierRepository struct {
+	mu         sync.RWMutex
+	path       string
+	classifier entity.ModelClassifier
+}
+
+// NewFileModelTierRepository creates a repository persisting overrides to the file at path, starting
+// from the configured classifier until the file is saved
+func NewFileModelTierRepository(path string, classifier entity.ModelClassifier) (*FileModelTierRepository, error) {
+	repo := &FileModelTierRepository{
+		path:       path,
+		classifier: classifier,
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return repo, nil
+	}
+
+	loaded, err := readModelTiersFile(path)
+	if err != nil {
+		return nil, err
+	}
+	repo.classifier = loaded
+	return repo, nil
+}
+
+// GetClassifier returns a classifier with the current overrides
+func (r *FileModelTierRepository) GetClassifier() entity.ModelClassifier {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.classifier
+}
+
+// SaveClassifier writes the overrides to the file, then makes them current
+func (r *FileModelTierRepository) SaveClassifier(classifier entity.ModelClassifier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	v.Set(modelTiersFileKey, modelTierEntries(classifier))
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create model tiers directory: %w", err)
+	}
+	if err := v.WriteConfigAs(r.path); err != nil {
+		return fmt.Errorf("failed to write model tiers %s: %w", r.path, err)
+	}
+
+	r.classifier = classifier
+	return nil
+}
+
+// readModelTiersFile reads the overrides of the file, rejecting unknown tiers
+func readModelTiersFile(path string) (entity.ModelClassifier, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("toml")
+	if err := v.ReadInConfig(); err != nil {
+		return entity.ModelClassifier{}, fmt.Errorf("failed to read model tiers %s: %w", path, err)
+	}
+
+	var entries []modelTierEntry
+	if err := v.UnmarshalKey(modelTiersFileKey, &entries); err != nil {
+		return entity.ModelClassifier{}, fmt.Errorf("failed to parse model tiers %s: %w", path, err)
+	}
+
+	tiers := make(map[string]entity.ModelTier, len(entries))
+	for i, entry := range entries {
+		tier, err := entity.ParseModelTier(entry.Tier)
+		if err != nil {
+			return entity.ModelClassifier{}, fmt.Errorf("%s: model_tiers[%d]: %w", path, i, err)
+		}
+		tiers[entry.Model] = tier
+	}
+	return entity.NewModelClassifier(tiers), nil
+}
+
+// modelTierEntries converts the overrides into file entries sorted by model name
+func modelTierEntries(classifier entity.ModelClassifier) []map[string]any {
+	overrides := classifier.Overrides()
+
+	models := make([]string, 0, len(overrides))
+	for model := range overrides {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	entries := make([]map[string]any, 0, len(models))
+	for _, model := range models {
+		entries = append(entries, map[string]any{
+			"model": model,
+			"tier":  string(overrides[model]),
+		})
+	}
+	return entries
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestFileModelTierRepository_SaveClassifier(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.toml")
+	config := "# My settings\n[claude]\nplan = \"max\"\n"
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "ccmon", "model_tiers.toml")
+	repo, err := NewFileModelTierRepository(path, entity.NewModelClassifier(map[string]entity.ModelTier{"local-mistral": entity.ModelTierBase}))
+	if err != nil {
+		t.Fatalf("NewFileModelTierRepository failed: %v", err)
+	}
+	if repo.GetClassifier().Classify(entity.NewModel("local-mistral")) != entity.ModelTierBase {
+		t.Error("expected the configured overrides before the file exists")
+	}
+
+	classifier := repo.GetClassifier().
+		WithOverride(entity.NewModel("local-mistral"), "").
+		WithOverride(entity.NewModel("claude-3.5-local"), entity.ModelTierOpus)
+	if err := repo.SaveClassifier(classifier); err != nil {
+		t.Fatalf("SaveClassifier failed: %v", err)
+	}
+	if repo.GetClassifier().Classify(entity.NewModel("claude-3.5-local")) != entity.ModelTierOpus {
+		t.Error("expected the saved override to be current")
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil || string(content) != config {
+		t.Errorf("expected the config file to be untouched, got:\n%s", content)
+	}
+
+	// The saved file replaces the configured overrides, so the reset one stays reset
+	reloaded, err := NewFileModelTierRepository(path, entity.NewModelClassifier(map[string]entity.ModelTier{"local-mistral": entity.ModelTierBase}))
+	if err != nil {
+		t.Fatalf("failed to reload the saved file: %v", err)
+	}
+	overrides := reloaded.GetClassifier().Overrides()
+	if len(overrides) != 1 || overrides["claude-3.5-local"] != entity.ModelTierOpus {
+		t.Errorf("unexpected reloaded overrides: %v", overrides)
+	}
+}
+
+func TestNewFileModelTierRepository_InvalidTier(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "model_tiers.toml")
+	if err := os.WriteFile(path, []byte("[[model_tiers]]\nmodel = \"local-llama\"\ntier = \"sonnet\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileModelTierRepository(path, entity.NewModelClassifier(nil)); err == nil {
+		t.Error("expected an error for an unknown tier")
+	}
+}
@@ -8,8 +8,11 @@ import (
 
 	"github.com/elct9620/ccmon/entity"
 	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -20,10 +23,13 @@ type GRPCAPIRequestRepository struct {
 }
 
 // NewGRPCAPIRequestRepository creates a new gRPC repository instance
-func NewGRPCAPIRequestRepository(serverAddress string) (*GRPCAPIRequestRepository, error) {
+func NewGRPCAPIRequestRepository(serverAddress string, authToken string, authScheme entity.AuthScheme) (*GRPCAPIRequestRepository, error) {
 	// Create connection with timeout
+	interceptor := NewClientInterceptor(authToken, authScheme)
 	conn, err := grpc.NewClient(serverAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor.Unary()),
+		grpc.WithStreamInterceptor(interceptor.Stream()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
@@ -42,27 +48,58 @@ func (r *GRPCAPIRequestRepository) Save(req entity.APIRequest) error {
 	return errors.New("save operation not supported in monitor mode (read-only repository)")
 }
 
+// SaveBatch is not supported in monitor mode (read-only repository)
+func (r *GRPCAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	return errors.New("save operation not supported in monitor mode (read-only repository)")
+}
+
 // FindByPeriodWithLimit retrieves API requests filtered by time period with limit and offset via gRPC
 // Use limit = 0 for no limit (fetch all records)
 // Use offset = 0 when no offset is needed
 func (r *GRPCAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
-	// Convert entity.Period to protobuf timestamps
-	var startTime, endTime *timestamppb.Timestamp
+	resp, err := r.getAPIRequests(newGetAPIRequestsRequest(period, limit, offset))
+	if err != nil {
+		return nil, err
+	}
+	return convertProtoToAPIRequests(resp.Requests), nil
+}
+
+// FindByPeriodAndDuration retrieves API requests of the period within the duration range via gRPC, the
+// server filters them before applying limit and offset. Servers that ignore the range fail with
+// usecase.ErrDurationFilterUnsupported.
+func (r *GRPCAPIRequestRepository) FindByPeriodAndDuration(period entity.Period, durationRange entity.DurationRange, limit int, offset int) ([]entity.APIRequest, error) {
+	req := newGetAPIRequestsRequest(period, limit, offset)
+	req.MinDurationMs = durationRange.Min().Milliseconds()
+	req.MaxDurationMs = durationRange.Max().Milliseconds()
 
+	resp, err := r.getAPIRequests(req)
+	if err != nil {
+		return nil, err
+	}
+	if !durationRange.IsEmpty() && !resp.DurationApplied {
+		return nil, fmt.Errorf("server ignored the duration range: %w", usecase.ErrDurationFilterUnsupported)
+	}
+	return convertProtoToAPIRequests(resp.Requests), nil
+}
+
+// newGetAPIRequestsRequest creates a gRPC request for the period with limit and offset
+func newGetAPIRequestsRequest(period entity.Period, limit int, offset int) *pb.GetAPIRequestsRequest {
+	// Convert entity.Period to protobuf timestamps
+	var startTime *timestamppb.Timestamp
 	if !period.IsAllTime() {
 		startTime = timestamppb.New(period.StartAt())
 	}
-	endTime = timestamppb.New(period.EndAt())
 
-	// Create gRPC request with timestamps, limit and offset
-	req := &pb.GetAPIRequestsRequest{
+	return &pb.GetAPIRequestsRequest{
 		StartTime: startTime,
-		EndTime:   endTime,
+		EndTime:   timestamppb.New(period.EndAt()),
 		Limit:     int32(limit),
 		Offset:    int32(offset),
 	}
+}
 
-	// Call gRPC service
+// getAPIRequests calls the gRPC GetAPIRequests service
+func (r *GRPCAPIRequestRepository) getAPIRequests(req *pb.GetAPIRequestsRequest) (*pb.GetAPIRequestsResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -70,14 +107,16 @@ func (r *GRPCAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, l
 	if err != nil {
 		return nil, fmt.Errorf("failed to get API requests via gRPC: %w", err)
 	}
+	return resp, nil
+}
 
-	// Convert protobuf responses to entities
-	entities := make([]entity.APIRequest, len(resp.Requests))
-	for i, pbReq := range resp.Requests {
+// convertProtoToAPIRequests converts protobuf responses to entities
+func convertProtoToAPIRequests(pbRequests []*pb.APIRequest) []entity.APIRequest {
+	entities := make([]entity.APIRequest, len(pbRequests))
+	for i, pbReq := range pbRequests {
 		entities[i] = convertProtoToAPIRequest(pbReq)
 	}
-
-	return entities, nil
+	return entities
 }
 
 // FindAll retrieves all API requests via gRPC
@@ -91,6 +130,32 @@ func (r *GRPCAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, e
 	return 0, errors.New("delete operation not supported in monitor mode (read-only repository)")
 }
 
+// DeleteByPeriod is not supported in monitor mode (read-only repository)
+func (r *GRPCAPIRequestRepository) DeleteByPeriod(period entity.Period) (int, error) {
+	return 0, errors.New("delete operation not supported in monitor mode (read-only repository)")
+}
+
+// UpdateNote sets the note of a request through the server's SetNote RPC
+// Returns false when the server reports no matching request
+func (r *GRPCAPIRequestRepository) UpdateNote(sessionID string, timestamp time.Time, note string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.client.SetNote(ctx, &pb.SetNoteRequest{
+		SessionId: sessionID,
+		Timestamp: timestamppb.New(timestamp),
+		Note:      note,
+	})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to set note via gRPC: %w", err)
+	}
+
+	return true, nil
+}
+
 // Close closes the gRPC connection
 func (r *GRPCAPIRequestRepository) Close() error {
 	return r.conn.Close()
@@ -117,5 +182,5 @@ func convertProtoToAPIRequest(pbReq *pb.APIRequest) entity.APIRequest {
 		tokens,
 		cost,
 		pbReq.DurationMs,
-	)
+	).WithLabels(pbReq.Labels).WithNote(pbReq.Note)
 }
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCBackupClient downloads database snapshots via gRPC Backup
+type GRPCBackupClient struct {
+	client pb.QueryServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewGRPCBackupClient creates a new gRPC backup client instance
+func NewGRPCBackupClient(serverAddress string, authToken string, authScheme entity.AuthScheme) (*GRPCBackupClient, error) {
+	interceptor := NewClientInterceptor(authToken, authScheme)
+	conn, err := grpc.NewClient(serverAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor.Unary()),
+		grpc.WithStreamInterceptor(interceptor.Stream()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+	}
+
+	return &GRPCBackupClient{
+		client: pb.NewQueryServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// Snapshot writes the server's database snapshot to w and returns the number of requests it holds
+func (c *GRPCBackupClient) Snapshot(ctx context.Context, w io.Writer) (int, error) {
+	stream, err := c.client.Backup(ctx, &pb.BackupRequest{})
+	if err != nil {
+		return 0, describeStatusError(err)
+	}
+
+	records := -1
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, describeStatusError(err)
+		}
+
+		if len(chunk.Data) > 0 {
+			if _, err := w.Write(chunk.Data); err != nil {
+				return 0, err
+			}
+		}
+		if chunk.RecordCount > 0 || len(chunk.Data) == 0 {
+			records = int(chunk.RecordCount)
+		}
+	}
+
+	// The final chunk carries the count, so its absence means the stream ended early
+	if records < 0 {
+		return 0, fmt.Errorf("backup stream ended before completion")
+	}
+
+	return records, nil
+}
+
+// Close closes the gRPC connection
+func (c *GRPCBackupClient) Close() error {
+	return c.conn.Close()
+}
+
+// describeStatusError surfaces the server's reason rather than the full gRPC status text
+func describeStatusError(err error) error {
+	if st, ok := status.FromError(err); ok {
+		return fmt.Errorf("%s: %s", st.Code(), st.Message())
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authorizationMetadataKey is the metadata key carrying the auth token
+const authorizationMetadataKey = "authorization"
+
+// ClientInterceptor attaches the auth token to outgoing gRPC calls
+type ClientInterceptor struct {
+	token  string
+	scheme entity.AuthScheme
+}
+
+// NewClientInterceptor creates a new client interceptor for the given auth token, presented with the scheme
+func NewClientInterceptor(token string, scheme entity.AuthScheme) *ClientInterceptor {
+	return &ClientInterceptor{token: token, scheme: scheme}
+}
+
+// Unary returns a unary client interceptor that attaches the auth token
+func (c *ClientInterceptor) Unary() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(c.attachToken(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// Stream returns a stream client interceptor that attaches the auth token
+func (c *ClientInterceptor) Stream() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(c.attachToken(ctx), desc, cc, method, opts...)
+	}
+}
+
+// attachToken adds the auth token to the outgoing metadata when configured
+func (c *ClientInterceptor) attachToken(ctx context.Context) context.Context {
+	if c.token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, authorizationMetadataKey, c.scheme.Authorization(c.token))
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCHealthCheckClient runs the server-side ingestion health check via gRPC HealthCheck
+type GRPCHealthCheckClient struct {
+	client pb.QueryServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewGRPCHealthCheckClient creates a new gRPC health check client instance
+func NewGRPCHealthCheckClient(serverAddress string, authToken string, authScheme entity.AuthScheme) (*GRPCHealthCheckClient, error) {
+	interceptor := NewClientInterceptor(authToken, authScheme)
+	conn, err := grpc.NewClient(serverAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor.Unary()),
+		grpc.WithStreamInterceptor(interceptor.Stream()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+	}
+
+	return &GRPCHealthCheckClient{
+		client: pb.NewQueryServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// HealthCheck asks the server to round-trip a synthetic record and returns the phase timings
+func (c *GRPCHealthCheckClient) HealthCheck(ctx context.Context) (*usecase.HealthCheckResult, error) {
+	resp, err := c.client.HealthCheck(ctx, &pb.HealthCheckRequest{})
+	if err != nil {
+		// Surface the server's reason rather than the full gRPC status text
+		if st, ok := status.FromError(err); ok {
+			return nil, fmt.Errorf("%s: %s", st.Code(), st.Message())
+		}
+		return nil, err
+	}
+
+	return &usecase.HealthCheckResult{
+		IngestDuration:  time.Duration(resp.IngestMicros) * time.Microsecond,
+		VerifyDuration:  time.Duration(resp.VerifyMicros) * time.Microsecond,
+		CleanupDuration: time.Duration(resp.CleanupMicros) * time.Microsecond,
+	}, nil
+}
+
+// Close closes the gRPC connection
+func (c *GRPCHealthCheckClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCIngestionClient pauses and resumes the server's ingestion via gRPC SetIngestionPaused
+type GRPCIngestionClient struct {
+	client pb.QueryServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewGRPCIngestionClient creates a new gRPC ingestion client instance
+func NewGRPCIngestionClient(serverAddress string, authToken string, authScheme entity.AuthScheme) (*GRPCIngestionClient, error) {
+	interceptor := NewClientInterceptor(authToken, authScheme)
+	conn, err := grpc.NewClient(serverAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor.Unary()),
+		grpc.WithStreamInterceptor(interceptor.Stream()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+	}
+
+	return &GRPCIngestionClient{
+		client: pb.NewQueryServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// SetIngestionPaused pauses or resumes ingestion and returns whether it was paused before
+func (c *GRPCIngestionClient) SetIngestionPaused(ctx context.Context, paused bool) (bool, error) {
+	resp, err := c.client.SetIngestionPaused(ctx, &pb.SetIngestionPausedRequest{Paused: paused})
+	if err != nil {
+		// Surface the server's reason rather than the full gRPC status text
+		if st, ok := status.FromError(err); ok {
+			return false, fmt.Errorf("%s: %s", st.Code(), st.Message())
+		}
+		return false, err
+	}
+	return resp.WasPaused, nil
+}
+
+// Close closes the gRPC connection
+func (c *GRPCIngestionClient) Close() error {
+	return c.conn.Close()
+}
@@ -3,16 +3,20 @@ package repository
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
 	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // GRPCStatsRepository implements usecase.StatsRepository using gRPC GetStats call
+// and usecase.StatsStreamRepository using gRPC StreamStats call
 // This is used on the client side to get pre-calculated stats from the server
 type GRPCStatsRepository struct {
 	client pb.QueryServiceClient
@@ -20,10 +24,13 @@ type GRPCStatsRepository struct {
 }
 
 // NewGRPCStatsRepository creates a new gRPC stats repository instance
-func NewGRPCStatsRepository(serverAddress string) (*GRPCStatsRepository, error) {
+func NewGRPCStatsRepository(serverAddress string, authToken string, authScheme entity.AuthScheme) (*GRPCStatsRepository, error) {
 	// Create connection
+	interceptor := NewClientInterceptor(authToken, authScheme)
 	conn, err := grpc.NewClient(serverAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor.Unary()),
+		grpc.WithStreamInterceptor(interceptor.Stream()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
@@ -37,9 +44,11 @@ func NewGRPCStatsRepository(serverAddress string) (*GRPCStatsRepository, error)
 	}, nil
 }
 
-// GetStatsByPeriod retrieves stats for a given period via gRPC GetStats
-func (r *GRPCStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+// GetStats retrieves stats for the query via gRPC GetStats, the server counts the negligible bucket
+// and applies the tier overrides. Servers that ignore the overrides fail with usecase.ErrModelTiersUnsupported.
+func (r *GRPCStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
 	// Convert entity.Period to protobuf timestamps
+	period := query.Period()
 	var startTime, endTime *timestamppb.Timestamp
 
 	if !period.IsAllTime() {
@@ -49,8 +58,10 @@ func (r *GRPCStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Sta
 
 	// Create gRPC request
 	req := &pb.GetStatsRequest{
-		StartTime: startTime,
-		EndTime:   endTime,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		NegligibleCost: query.NegligibleCost().Amount(),
+		ModelTiers:     convertClassifierToProto(query.Classifier()),
 	}
 
 	// Call gRPC service
@@ -61,11 +72,68 @@ func (r *GRPCStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Sta
 	if err != nil {
 		return entity.Stats{}, fmt.Errorf("failed to get stats via gRPC: %w", err)
 	}
+	if len(req.ModelTiers) > 0 && !resp.ModelTiersApplied {
+		return entity.Stats{}, fmt.Errorf("server ignored the model tier overrides: %w", usecase.ErrModelTiersUnsupported)
+	}
 
 	// Convert protobuf response to entity
 	return convertProtoToStats(resp.Stats, period), nil
 }
 
+// convertClassifierToProto converts the tier overrides sorted by model name
+func convertClassifierToProto(classifier entity.ModelClassifier) []*pb.ModelTierOverride {
+	overrides := classifier.Overrides()
+
+	models := make([]string, 0, len(overrides))
+	for model := range overrides {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	pbOverrides := make([]*pb.ModelTierOverride, 0, len(models))
+	for _, model := range models {
+		pbOverrides = append(pbOverrides, &pb.ModelTierOverride{Model: model, Tier: string(overrides[model])})
+	}
+	return pbOverrides
+}
+
+// WatchStats subscribes to the stats pushed via gRPC StreamStats, calling onUpdate for each update
+// until ctx is done or the stream fails
+func (r *GRPCStatsRepository) WatchStats(ctx context.Context, params usecase.WatchStatsParams, onUpdate func(entity.Stats)) error {
+	req := &pb.StreamStatsRequest{
+		WindowSeconds:  int64(params.Window / time.Second),
+		NegligibleCost: params.NegligibleCost.Amount(),
+	}
+	if !params.Start.IsZero() {
+		req.StartTime = timestamppb.New(params.Start)
+	}
+	if !params.End.IsZero() {
+		req.EndTime = timestamppb.New(params.End)
+	}
+
+	stream, err := r.client.StreamStats(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to stream stats via gRPC: %w", err)
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stream stats via gRPC: %w", err)
+		}
+
+		// The server reports the period it computed, which moves with its clock for open-ended periods
+		period := entity.NewAllTimePeriod(update.EndTime.AsTime())
+		if update.StartTime != nil {
+			period = entity.NewPeriod(update.StartTime.AsTime(), update.EndTime.AsTime())
+		}
+		onUpdate(convertProtoToStats(update.Stats, period))
+	}
+}
+
 // Close closes the gRPC connection
 func (r *GRPCStatsRepository) Close() error {
 	return r.conn.Close()
@@ -93,7 +161,7 @@ func convertProtoToStats(pbStats *pb.Stats, period entity.Period) entity.Stats {
 	premiumCost := entity.NewCost(pbStats.PremiumCost.Amount)
 
 	// Create stats entity
-	return entity.NewStats(
+	stats := entity.NewStats(
 		int(pbStats.BaseRequests),
 		int(pbStats.PremiumRequests),
 		baseTokens,
@@ -102,4 +170,18 @@ func convertProtoToStats(pbStats *pb.Stats, period entity.Period) entity.Stats {
 		premiumCost,
 		period,
 	)
+
+	// Servers before the negligible bucket, session counts or longest gap leave these unset, which reads as empty
+	negligibleTokens := entity.NewToken(
+		pbStats.GetNegligibleTokens().GetInput(),
+		pbStats.GetNegligibleTokens().GetOutput(),
+		pbStats.GetNegligibleTokens().GetCacheRead(),
+		pbStats.GetNegligibleTokens().GetCacheCreation(),
+	)
+	return stats.WithNegligibleTotals(
+		int(pbStats.NegligibleRequests),
+		negligibleTokens,
+		entity.NewCost(pbStats.GetNegligibleCost().GetAmount()),
+	).WithSessions(int(pbStats.Sessions), pbStats.UnknownSession).
+		WithLongestGap(time.Duration(pbStats.LongestGapMs)*time.Millisecond, pbStats.LongestGapAvailable)
 }
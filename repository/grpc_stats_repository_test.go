@@ -9,9 +9,11 @@ import (
 
 	"github.com/elct9620/ccmon/entity"
 	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // MockQueryServiceServer for testing GRPCStatsRepository
@@ -35,6 +37,28 @@ func (m *MockQueryServiceServer) GetAPIRequests(ctx context.Context, req *pb.Get
 	return &pb.GetAPIRequestsResponse{}, nil
 }
 
+func (m *MockQueryServiceServer) StreamStats(req *pb.StreamStatsRequest, stream pb.QueryService_StreamStatsServer) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	// Echo the requested period back twice, as if the stats were pushed on two intervals
+	end := req.EndTime
+	if end == nil {
+		end = timestamppb.New(time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC))
+	}
+	start := req.StartTime
+	if start == nil && req.WindowSeconds > 0 {
+		start = timestamppb.New(end.AsTime().Add(-time.Duration(req.WindowSeconds) * time.Second))
+	}
+	for i := 0; i < 2; i++ {
+		if err := stream.Send(&pb.StatsUpdate{Stats: m.stats, StartTime: start, EndTime: end}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // setupMockGRPCServer creates a mock gRPC server for testing
 func setupMockGRPCServer(mockStats *pb.Stats, mockErr error) (*grpc.Server, *bufconn.Listener) {
 	listener := bufconn.Listen(1024 * 1024)
@@ -72,7 +96,7 @@ func createGRPCStatsRepository(listener *bufconn.Listener) (*GRPCStatsRepository
 	}, nil
 }
 
-func TestGRPCStatsRepository_GetStatsByPeriod(t *testing.T) {
+func TestGRPCStatsRepository_GetStats(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -213,7 +237,7 @@ func TestGRPCStatsRepository_GetStatsByPeriod(t *testing.T) {
 			}()
 
 			// Execute
-			result, err := statsRepo.GetStatsByPeriod(tt.period)
+			result, err := statsRepo.GetStats(entity.NewStatsQuery(tt.period))
 
 			// Verify error expectation
 			if tt.expectError {
@@ -262,3 +286,90 @@ func TestGRPCStatsRepository_Close(t *testing.T) {
 		t.Errorf("Unexpected error closing repository: %v", err)
 	}
 }
+
+func TestGRPCStatsRepository_WatchStats(t *testing.T) {
+	t.Parallel()
+
+	mockStats := &pb.Stats{
+		BaseRequests:    1,
+		PremiumRequests: 2,
+		BaseTokens:      &pb.Token{Input: 100, Output: 50},
+		PremiumTokens:   &pb.Token{Input: 200, Output: 100},
+		BaseCost:        &pb.Cost{Amount: 0.1},
+		PremiumCost:     &pb.Cost{Amount: 0.5},
+	}
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	blockStart := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		params        usecase.WatchStatsParams
+		err           error
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectError   bool
+	}{
+		{
+			name:        "all time",
+			expectedEnd: now,
+		},
+		{
+			name:          "rolling window",
+			params:        usecase.WatchStatsParams{Window: 24 * time.Hour},
+			expectedStart: now.Add(-24 * time.Hour),
+			expectedEnd:   now,
+		},
+		{
+			name:          "fixed period",
+			params:        usecase.WatchStatsParams{Start: blockStart, End: blockStart.Add(5 * time.Hour)},
+			expectedStart: blockStart,
+			expectedEnd:   blockStart.Add(5 * time.Hour),
+		},
+		{
+			name:        "server error",
+			err:         fmt.Errorf("stream unavailable"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, listener := setupMockGRPCServer(mockStats, tt.err)
+			defer server.Stop()
+
+			repo, err := createGRPCStatsRepository(listener)
+			if err != nil {
+				t.Fatalf("Failed to create repository: %v", err)
+			}
+			defer func() { _ = repo.Close() }()
+
+			var updates []entity.Stats
+			err = repo.WatchStats(context.Background(), tt.params, func(stats entity.Stats) {
+				updates = append(updates, stats)
+			})
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(updates) != 2 {
+				t.Fatalf("Expected 2 updates, got %d", len(updates))
+			}
+			stats := updates[0]
+			if stats.TotalRequests() != 3 {
+				t.Errorf("Expected 3 requests, got %d", stats.TotalRequests())
+			}
+			if !stats.Period().StartAt().Equal(tt.expectedStart) || !stats.Period().EndAt().Equal(tt.expectedEnd) {
+				t.Errorf("Expected period %v - %v, got %v - %v", tt.expectedStart, tt.expectedEnd, stats.Period().StartAt(), stats.Period().EndAt())
+			}
+		})
+	}
+}
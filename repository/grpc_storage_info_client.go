@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStorageInfoClient reads the state of the server's database via gRPC GetStorageInfo
+type GRPCStorageInfoClient struct {
+	client pb.QueryServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewGRPCStorageInfoClient creates a new gRPC storage info client instance
+func NewGRPCStorageInfoClient(serverAddress string, authToken string, authScheme entity.AuthScheme) (*GRPCStorageInfoClient, error) {
+	interceptor := NewClientInterceptor(authToken, authScheme)
+	conn, err := grpc.NewClient(serverAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor.Unary()),
+		grpc.WithStreamInterceptor(interceptor.Stream()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+	}
+
+	return &GRPCStorageInfoClient{
+		client: pb.NewQueryServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// GetStorageInfo returns the database size, record count and stored time range of the server
+func (c *GRPCStorageInfoClient) GetStorageInfo(ctx context.Context) (entity.StorageInfo, error) {
+	resp, err := c.client.GetStorageInfo(ctx, &pb.GetStorageInfoRequest{})
+	if err != nil {
+		// Surface the server's reason rather than the full gRPC status text
+		if st, ok := status.FromError(err); ok {
+			return entity.StorageInfo{}, fmt.Errorf("%s: %s", st.Code(), st.Message())
+		}
+		return entity.StorageInfo{}, err
+	}
+
+	var oldestAt, newestAt time.Time
+	if resp.OldestTime != nil {
+		oldestAt = resp.OldestTime.AsTime()
+	}
+	if resp.NewestTime != nil {
+		newestAt = resp.NewestTime.AsTime()
+	}
+	return entity.NewStorageInfo(resp.SizeBytes, int(resp.RecordCount), oldestAt, newestAt), nil
+}
+
+// Close closes the gRPC connection
+func (c *GRPCStorageInfoClient) Close() error {
+	return c.conn.Close()
+}
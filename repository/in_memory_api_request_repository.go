@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// InMemoryAPIRequestRepository implements usecase.APIRequestRepository over requests held in memory,
+// e.g. a dataset loaded from an export file for offline review. Changes are never persisted.
+type InMemoryAPIRequestRepository struct {
+	mu       sync.RWMutex
+	requests []entity.APIRequest // Sorted by timestamp, oldest first
+}
+
+// NewInMemoryAPIRequestRepository creates a repository holding a copy of the given requests
+func NewInMemoryAPIRequestRepository(requests []entity.APIRequest) *InMemoryAPIRequestRepository {
+	sorted := append([]entity.APIRequest(nil), requests...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp().Before(sorted[j].Timestamp())
+	})
+
+	return &InMemoryAPIRequestRepository{requests: sorted}
+}
+
+// Save adds a request, keeping the requests in chronological order
+func (r *InMemoryAPIRequestRepository) Save(req entity.APIRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := sort.Search(len(r.requests), func(i int) bool {
+		return r.requests[i].Timestamp().After(req.Timestamp())
+	})
+	r.requests = append(r.requests, entity.APIRequest{})
+	copy(r.requests[index+1:], r.requests[index:])
+	r.requests[index] = req
+	return nil
+}
+
+// SaveBatch adds every request, keeping the requests in chronological order
+func (r *InMemoryAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	for _, req := range reqs {
+		if err := r.Save(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByPeriodWithLimit retrieves requests within the period in chronological order
+// Use limit = 0 for no limit (fetch all records)
+// Use offset = 0 when no offset is needed
+func (r *InMemoryAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]entity.APIRequest, 0)
+	for _, req := range r.requests {
+		if !period.IsAllTime() && !inPeriod(req.Timestamp(), period) {
+			continue
+		}
+		matched = append(matched, req)
+	}
+
+	if offset >= len(matched) {
+		return []entity.APIRequest{}, nil
+	}
+	matched = matched[offset:]
+
+	if limit > 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+
+	return matched, nil
+}
+
+// FindAll retrieves every request in chronological order
+func (r *InMemoryAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entity.APIRequest(nil), r.requests...), nil
+}
+
+// DeleteOlderThan removes requests older than the cutoff and returns how many were removed
+func (r *InMemoryAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
+	return r.deleteWhere(func(req entity.APIRequest) bool {
+		return req.Timestamp().Before(cutoffTime)
+	}), nil
+}
+
+// DeleteByPeriod removes requests within the period and returns how many were removed
+func (r *InMemoryAPIRequestRepository) DeleteByPeriod(period entity.Period) (int, error) {
+	return r.deleteWhere(func(req entity.APIRequest) bool {
+		return inPeriod(req.Timestamp(), period)
+	}), nil
+}
+
+// UpdateNote replaces the note of the request identified by session ID and timestamp
+// Returns false when no request matches
+func (r *InMemoryAPIRequestRepository) UpdateNote(sessionID string, timestamp time.Time, note string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, req := range r.requests {
+		if req.SessionID() == sessionID && req.Timestamp().Equal(timestamp) {
+			r.requests[i] = req.WithNote(note)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// deleteWhere removes the matching requests and returns how many were removed
+func (r *InMemoryAPIRequestRepository) deleteWhere(match func(req entity.APIRequest) bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.requests[:0]
+	for _, req := range r.requests {
+		if !match(req) {
+			kept = append(kept, req)
+		}
+	}
+
+	deleted := len(r.requests) - len(kept)
+	r.requests = kept
+	return deleted
+}
+
+// inPeriod returns true if t falls within the period, both boundaries included
+func inPeriod(t time.Time, period entity.Period) bool {
+	return !t.Before(period.StartAt()) && !t.After(period.EndAt())
+}
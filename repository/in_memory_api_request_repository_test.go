@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestInMemoryAPIRequestRepository_FindByPeriodWithLimit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	// Out of order on purpose, exports are not guaranteed to be sorted
+	repo := NewInMemoryAPIRequestRepository([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("s3", now, "claude-sonnet-4-20250514", 100, 50, 0.3),
+		testutil.CreateTestAPIRequest("s1", now.Add(-2*time.Hour), "claude-sonnet-4-20250514", 100, 50, 0.1),
+		testutil.CreateTestAPIRequest("s2", now.Add(-time.Hour), "claude-sonnet-4-20250514", 100, 50, 0.2),
+	})
+
+	tests := []struct {
+		name     string
+		period   entity.Period
+		limit    int
+		offset   int
+		expected []string
+	}{
+		{name: "all time in chronological order", period: entity.NewAllTimePeriod(now), expected: []string{"s1", "s2", "s3"}},
+		{name: "period boundaries are inclusive", period: entity.NewPeriod(now.Add(-time.Hour), now), expected: []string{"s2", "s3"}},
+		{name: "limit and offset", period: entity.NewAllTimePeriod(now), limit: 1, offset: 1, expected: []string{"s2"}},
+		{name: "offset past the end", period: entity.NewAllTimePeriod(now), offset: 5, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.FindByPeriodWithLimit(tt.period, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sessions := make([]string, len(got))
+			for i, req := range got {
+				sessions[i] = req.SessionID()
+			}
+			if strings.Join(sessions, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected sessions %v, got %v", tt.expected, sessions)
+			}
+		})
+	}
+}
+
+func TestInMemoryAPIRequestRepository_Changes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	repo := NewInMemoryAPIRequestRepository([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", now.Add(-2*time.Hour), "claude-sonnet-4-20250514", 100, 50, 0.1),
+		testutil.CreateTestAPIRequest("s3", now, "claude-sonnet-4-20250514", 100, 50, 0.3),
+	})
+
+	if err := repo.Save(testutil.CreateTestAPIRequest("s2", now.Add(-time.Hour), "claude-sonnet-4-20250514", 100, 50, 0.2)); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	all, _ := repo.FindAll()
+	if len(all) != 3 || all[1].SessionID() != "s2" {
+		t.Fatalf("expected the saved request in chronological order, got %v", all)
+	}
+
+	updated, err := repo.UpdateNote("s2", now.Add(-time.Hour), "refactor run")
+	if err != nil || !updated {
+		t.Fatalf("expected the note to be updated, got %v, %v", updated, err)
+	}
+	all, _ = repo.FindAll()
+	if all[1].Note() != "refactor run" {
+		t.Errorf("expected the note to be kept, got %q", all[1].Note())
+	}
+
+	deleted, err := repo.DeleteOlderThan(now.Add(-30 * time.Minute))
+	if err != nil || deleted != 2 {
+		t.Fatalf("expected 2 deleted requests, got %d, %v", deleted, err)
+	}
+	all, _ = repo.FindAll()
+	if len(all) != 1 || all[0].SessionID() != "s3" {
+		t.Errorf("expected only the newest request to remain, got %v", all)
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// RepricedAPIRequestRepository implements usecase.APIRequestRepository, recalculating the cost of
+// the requests it returns from their token counts. This projects what usage would cost at other
+// rates without touching the recorded costs; writes pass through unchanged.
+type RepricedAPIRequestRepository struct {
+	apiRequestRepository usecase.APIRequestRepository
+	rates                entity.RateTable
+}
+
+// NewRepricedAPIRequestRepository creates a new RepricedAPIRequestRepository
+func NewRepricedAPIRequestRepository(apiRequestRepository usecase.APIRequestRepository, rates entity.RateTable) *RepricedAPIRequestRepository {
+	return &RepricedAPIRequestRepository{
+		apiRequestRepository: apiRequestRepository,
+		rates:                rates,
+	}
+}
+
+// Save stores the request as given
+func (r *RepricedAPIRequestRepository) Save(req entity.APIRequest) error {
+	return r.apiRequestRepository.Save(req)
+}
+
+// SaveBatch stores the requests as given
+func (r *RepricedAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	return r.apiRequestRepository.SaveBatch(reqs)
+}
+
+// FindByPeriodWithLimit retrieves the requests in the period with their costs recalculated
+func (r *RepricedAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
+	requests, err := r.apiRequestRepository.FindByPeriodWithLimit(period, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return r.rates.Reprice(requests), nil
+}
+
+// FindAll retrieves all requests with their costs recalculated
+func (r *RepricedAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
+	requests, err := r.apiRequestRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return r.rates.Reprice(requests), nil
+}
+
+// DeleteOlderThan deletes requests older than the cutoff time
+func (r *RepricedAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
+	return r.apiRequestRepository.DeleteOlderThan(cutoffTime)
+}
+
+// DeleteByPeriod deletes requests within the period
+func (r *RepricedAPIRequestRepository) DeleteByPeriod(period entity.Period) (int, error) {
+	return r.apiRequestRepository.DeleteByPeriod(period)
+}
+
+// UpdateNote replaces the note of the matching request
+func (r *RepricedAPIRequestRepository) UpdateNote(sessionID string, timestamp time.Time, note string) (bool, error) {
+	return r.apiRequestRepository.UpdateNote(sessionID, timestamp, note)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestRepricedAPIRequestRepository_FindByPeriodWithLimit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	period := entity.NewPeriod(now.Add(-time.Hour), now)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", now.Add(-30*time.Minute), "claude-sonnet-4-20250514", 1_000_000, 100_000, 4.5),
+		testutil.CreateTestAPIRequest("s1", now.Add(-20*time.Minute), "local-llama", 1_000_000, 100_000, 0.2),
+	}
+
+	tests := []struct {
+		name     string
+		rates    entity.RateTable
+		expected []float64
+	}{
+		{
+			name:     "no rates keep the recorded costs",
+			rates:    entity.NewRateTable(),
+			expected: []float64{4.5, 0.2},
+		},
+		{
+			name:     "matching models are repriced from tokens",
+			rates:    entity.NewRateTable(entity.NewModelRate("claude-*sonnet*", 1.0, 0.1).WithOutputRates(5.0, 1.25)),
+			expected: []float64{1.5, 0.2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo := testutil.NewMockAPIRequestRepository()
+			apiRepo.SetMockData(requests)
+
+			repo := NewRepricedAPIRequestRepository(apiRepo, tt.rates)
+			got, err := repo.FindByPeriodWithLimit(period, 0, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expected), len(got))
+			}
+			for i, want := range tt.expected {
+				if diff := got[i].Cost().Amount() - want; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("request %d cost = %f, want %f", i, got[i].Cost().Amount(), want)
+				}
+			}
+
+			stats, err := NewBoltDBStatsRepository(repo).GetStats(entity.NewStatsQuery(period))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			total := 0.0
+			for _, want := range tt.expected {
+				total += want
+			}
+			if diff := stats.TotalCost().Amount() - total; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("stats total cost = %f, want %f", stats.TotalCost().Amount(), total)
+			}
+		})
+	}
+}
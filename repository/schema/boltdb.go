@@ -14,4 +14,6 @@ type APIRequest struct {
 	TotalTokens         int64
 	CostUSD             float64
 	DurationMS          int64
+	Labels              map[string]string `json:",omitempty"`
+	Note                string            `json:",omitempty"`
 }
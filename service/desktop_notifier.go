@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DesktopNotifier shows native desktop notifications through the notification command
+// shipped with the platform: osascript on macOS, notify-send on Linux and BSD, and
+// PowerShell on Windows. Platforms without one report notifications as unsupported.
+type DesktopNotifier struct {
+	goos     string
+	lookPath func(file string) (string, error)
+	run      func(name string, args ...string) error
+	start    func(name string, args ...string) error // Starts the command without waiting for it to exit
+}
+
+// NewDesktopNotifier creates a notifier for the current platform
+func NewDesktopNotifier() *DesktopNotifier {
+	return &DesktopNotifier{
+		goos:     runtime.GOOS,
+		lookPath: exec.LookPath,
+		run: func(name string, args ...string) error {
+			return exec.Command(name, args...).Run()
+		},
+		start: func(name string, args ...string) error {
+			cmd := exec.Command(name, args...)
+			if err := cmd.Start(); err != nil {
+				return err
+			}
+			// Reap the process once the notification is gone
+			go func() { _ = cmd.Wait() }()
+			return nil
+		},
+	}
+}
+
+// IsSupported returns true if the platform's notification command is installed
+func (n *DesktopNotifier) IsSupported() bool {
+	name, _, ok := NotificationCommand(n.goos, "", "")
+	if !ok {
+		return false
+	}
+	_, err := n.lookPath(name)
+	return err == nil
+}
+
+// Notify shows a notification with the given title and message, returning once it is shown
+func (n *DesktopNotifier) Notify(title, message string) error {
+	name, args, ok := NotificationCommand(n.goos, title, message)
+	if !ok {
+		return fmt.Errorf("desktop notifications are not supported on %s", n.goos)
+	}
+	// PowerShell stays alive for as long as the balloon tip shows, so it is not waited for
+	run := n.run
+	if n.goos == "windows" {
+		run = n.start
+	}
+	if err := run(name, args...); err != nil {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return nil
+}
+
+// NotificationCommand returns the command showing a notification on the given platform,
+// or false when the platform has no known notification command
+func NotificationCommand(goos, title, message string) (string, []string, bool) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return "osascript", []string{"-e", script}, true
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return "notify-send", []string{"--app-name=ccmon", title, message}, true
+	case "windows":
+		// Balloon tips work on every Windows version without extra modules
+		script := "Add-Type -AssemblyName System.Windows.Forms; " +
+			"$n = New-Object System.Windows.Forms.NotifyIcon; " +
+			"$n.Icon = [System.Drawing.SystemIcons]::Information; " +
+			"$n.Visible = $true; " +
+			fmt.Sprintf("$n.ShowBalloonTip(5000, %s, %s, 'Info'); ", powerShellString(title), powerShellString(message)) +
+			"Start-Sleep -Seconds 6; $n.Dispose()"
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, true
+	default:
+		return "", nil, false
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// powerShellString quotes s as a single-quoted PowerShell string literal
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNotificationCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		goos         string
+		expectedName string
+		expectedArgs []string
+		supported    bool
+	}{
+		{
+			name:         "macOS quotes the AppleScript strings",
+			goos:         "darwin",
+			expectedName: "osascript",
+			expectedArgs: []string{"-e", `display notification "Today's cost is \"$12.00\"" with title "ccmon"`},
+			supported:    true,
+		},
+		{
+			name:         "Linux uses notify-send",
+			goos:         "linux",
+			expectedName: "notify-send",
+			expectedArgs: []string{"--app-name=ccmon", "ccmon", `Today's cost is "$12.00"`},
+			supported:    true,
+		},
+		{
+			name:         "Windows quotes the PowerShell strings",
+			goos:         "windows",
+			expectedName: "powershell",
+			supported:    true,
+		},
+		{
+			name: "unknown platform is unsupported",
+			goos: "plan9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, ok := NotificationCommand(tt.goos, "ccmon", `Today's cost is "$12.00"`)
+			if ok != tt.supported {
+				t.Fatalf("expected supported = %v, got %v", tt.supported, ok)
+			}
+			if name != tt.expectedName {
+				t.Errorf("expected command %q, got %q", tt.expectedName, name)
+			}
+			if tt.expectedArgs != nil && strings.Join(args, "\x00") != strings.Join(tt.expectedArgs, "\x00") {
+				t.Errorf("expected args %q, got %q", tt.expectedArgs, args)
+			}
+			if tt.goos == "windows" && !strings.Contains(args[len(args)-1], `ShowBalloonTip(5000, 'ccmon', 'Today''s cost is "$12.00"', 'Info')`) {
+				t.Errorf("expected escaped balloon tip, got %q", args[len(args)-1])
+			}
+		})
+	}
+}
+
+func TestDesktopNotifier(t *testing.T) {
+	tests := []struct {
+		name              string
+		goos              string
+		installed         bool
+		runErr            error
+		expectedSupported bool
+		expectError       bool
+		expectedStarted   bool
+	}{
+		{
+			name:              "installed command shows the notification",
+			goos:              "linux",
+			installed:         true,
+			expectedSupported: true,
+		},
+		{
+			name:              "Windows does not wait for PowerShell to exit",
+			goos:              "windows",
+			installed:         true,
+			expectedSupported: true,
+			expectedStarted:   true,
+		},
+		{
+			name:        "missing command is unsupported",
+			goos:        "linux",
+			installed:   false,
+			runErr:      errors.New("executable file not found"),
+			expectError: true,
+		},
+		{
+			name:        "unknown platform is unsupported",
+			goos:        "plan9",
+			installed:   true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran, started []string
+			notifier := &DesktopNotifier{
+				goos: tt.goos,
+				lookPath: func(file string) (string, error) {
+					if !tt.installed {
+						return "", errors.New("not found")
+					}
+					return "/usr/bin/" + file, nil
+				},
+				run: func(name string, args ...string) error {
+					ran = append(ran, name)
+					return tt.runErr
+				},
+				start: func(name string, args ...string) error {
+					started = append(started, name)
+					return tt.runErr
+				},
+			}
+
+			if notifier.IsSupported() != tt.expectedSupported {
+				t.Errorf("expected IsSupported() = %v, got %v", tt.expectedSupported, notifier.IsSupported())
+			}
+
+			err := notifier.Notify("ccmon", "Today's cost is $12.00")
+			if tt.expectError && err == nil {
+				t.Error("expected an error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.expectedStarted {
+				ran, started = started, ran
+			}
+			if tt.expectedSupported && len(ran) != 1 {
+				t.Errorf("expected the notification command to run once, ran %q", ran)
+			}
+			if len(started) != 0 {
+				t.Errorf("expected the notification command to run the other way, got %q", started)
+			}
+		})
+	}
+}
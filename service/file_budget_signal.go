@@ -0,0 +1,82 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// FileBudgetSignal signals an exceeded monthly budget through a sentinel file holding the
+// usage snapshot as JSON, so shell wrappers can test for its existence before starting a session
+type FileBudgetSignal struct {
+	path string
+}
+
+// NewFileBudgetSignal creates a budget signal written to the given path
+func NewFileBudgetSignal(path string) *FileBudgetSignal {
+	return &FileBudgetSignal{path: path}
+}
+
+// budgetSnapshotJSON is the sentinel file content
+type budgetSnapshotJSON struct {
+	Plan             string    `json:"plan"`
+	MonthlyCost      float64   `json:"monthly_cost"`
+	MonthlyBudget    float64   `json:"monthly_budget"`
+	MonthlyPlanUsage int       `json:"monthly_plan_usage"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Raise writes the snapshot to the sentinel file, replacing it atomically so readers never see a partial file
+func (s *FileBudgetSignal) Raise(snapshot entity.BudgetSnapshot) error {
+	data, err := json.MarshalIndent(budgetSnapshotJSON{
+		Plan:             snapshot.PlanName(),
+		MonthlyCost:      snapshot.Cost().Amount(),
+		MonthlyBudget:    snapshot.Budget().Amount(),
+		MonthlyPlanUsage: snapshot.Usage(),
+		PeriodStart:      snapshot.Period().StartAt(),
+		PeriodEnd:        snapshot.Period().EndAt(),
+		UpdatedAt:        snapshot.TakenAt(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode budget snapshot: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create budget signal file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write budget signal file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write budget signal file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write budget signal file: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the sentinel file, doing nothing when it does not exist
+func (s *FileBudgetSignal) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove budget signal file: %w", err)
+	}
+	return nil
+}
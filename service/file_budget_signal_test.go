@@ -0,0 +1,63 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestFileBudgetSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "budget-exceeded")
+	signal := NewFileBudgetSignal(path)
+
+	// Clearing a signal that was never raised is not an error
+	if err := signal.Clear(); err != nil {
+		t.Fatalf("unexpected error clearing a missing file: %v", err)
+	}
+
+	month := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC))
+	takenAt := time.Date(2025, 1, 20, 9, 30, 0, 0, time.UTC)
+	snapshot := entity.NewBudgetSnapshot(entity.NewPlan("pro", entity.NewCost(20)), entity.NewCost(25), month, takenAt)
+
+	if err := signal.Raise(snapshot); err != nil {
+		t.Fatalf("unexpected error raising the signal: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the sentinel file to exist: %v", err)
+	}
+	var content budgetSnapshotJSON
+	if err := json.Unmarshal(data, &content); err != nil {
+		t.Fatalf("expected JSON content, got %q: %v", data, err)
+	}
+	if content.Plan != "pro" || content.MonthlyCost != 25 || content.MonthlyBudget != 20 || content.MonthlyPlanUsage != 125 {
+		t.Errorf("unexpected snapshot content: %+v", content)
+	}
+	if !content.UpdatedAt.Equal(takenAt) || !content.PeriodStart.Equal(month.StartAt()) {
+		t.Errorf("unexpected snapshot times: %+v", content)
+	}
+
+	// Raising again replaces the snapshot without leaving temporary files behind
+	if err := signal.Raise(snapshot); err != nil {
+		t.Fatalf("unexpected error raising the signal again: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the sentinel file, got %d entries", len(entries))
+	}
+
+	if err := signal.Clear(); err != nil {
+		t.Fatalf("unexpected error clearing the signal: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the sentinel file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// statsCacheFilePattern matches the entries and temporary files of the cache, the only files it removes
+var statsCacheFilePattern = regexp.MustCompile(`^(-?\d+_-?\d+(_n\d+)?(_t[0-9a-f]{8})?\.json|stats\.\d+\.tmp)$`)
+
+// FileStatsCache implements TTL-based caching of statistics in a directory, one JSON file per query,
+// so several ccmon processes on one host share results. Files are replaced atomically, so concurrent
+// readers and writers never see a partial entry. Caching is best effort: any file error counts as a miss.
+type FileStatsCache struct {
+	dir   string
+	ttl   time.Duration
+	mutex sync.Mutex
+	since time.Time // Entries stored before the last invalidation are ignored by this process
+}
+
+// NewFileStatsCache creates a cache storing entries under dir that expire after ttl
+func NewFileStatsCache(dir string, ttl time.Duration) *FileStatsCache {
+	return &FileStatsCache{
+		dir: dir,
+		ttl: ttl,
+	}
+}
+
+// statsCacheEntryJSON is the content of one cache file
+type statsCacheEntryJSON struct {
+	BaseRequests        int             `json:"base_requests"`
+	PremiumRequests     int             `json:"premium_requests"`
+	BaseTokens          statsTokensJSON `json:"base_tokens"`
+	PremiumTokens       statsTokensJSON `json:"premium_tokens"`
+	BaseCost            float64         `json:"base_cost"`
+	PremiumCost         float64         `json:"premium_cost"`
+	NegligibleRequests  int             `json:"negligible_requests"`
+	NegligibleTokens    statsTokensJSON `json:"negligible_tokens"`
+	NegligibleCost      float64         `json:"negligible_cost"`
+	Sessions            int             `json:"sessions"`
+	UnknownSession      bool            `json:"unknown_session"`
+	LongestGapMs        int64           `json:"longest_gap_ms"`
+	LongestGapAvailable bool            `json:"longest_gap_available"`
+	PeriodStart         time.Time       `json:"period_start"`
+	PeriodEnd           time.Time       `json:"period_end"`
+	StoredAt            time.Time       `json:"stored_at"`
+	ExpiresAt           time.Time       `json:"expires_at"`
+}
+
+// statsTokensJSON is a token count in a cache file
+type statsTokensJSON struct {
+	Input         int64 `json:"input"`
+	Output        int64 `json:"output"`
+	CacheRead     int64 `json:"cache_read"`
+	CacheCreation int64 `json:"cache_creation"`
+}
+
+// Get retrieves cached statistics for the given query.
+// Returns nil if the entry doesn't exist, has expired or cannot be read.
+func (c *FileStatsCache) Get(query entity.StatsQuery) *entity.Stats {
+	data, err := os.ReadFile(c.path(query))
+	if err != nil {
+		return nil
+	}
+
+	var entry statsCacheEntryJSON
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	if now.After(entry.ExpiresAt) || entry.StoredAt.Before(c.invalidatedAt()) {
+		return nil
+	}
+
+	stats := entity.NewStats(
+		entry.BaseRequests,
+		entry.PremiumRequests,
+		entry.BaseTokens.token(),
+		entry.PremiumTokens.token(),
+		entity.NewCost(entry.BaseCost),
+		entity.NewCost(entry.PremiumCost),
+		entity.NewPeriod(entry.PeriodStart, entry.PeriodEnd),
+	).WithNegligibleTotals(entry.NegligibleRequests, entry.NegligibleTokens.token(), entity.NewCost(entry.NegligibleCost)).
+		WithSessions(entry.Sessions, entry.UnknownSession).
+		WithLongestGap(time.Duration(entry.LongestGapMs)*time.Millisecond, entry.LongestGapAvailable)
+	return &stats
+}
+
+// Set stores statistics for the given query, replacing the file atomically
+func (c *FileStatsCache) Set(query entity.StatsQuery, stats *entity.Stats) {
+	if stats == nil {
+		return
+	}
+
+	now := time.Now()
+	longestGap, longestGapAvailable := stats.LongestGap()
+	data, err := json.Marshal(statsCacheEntryJSON{
+		BaseRequests:        stats.BaseRequests(),
+		PremiumRequests:     stats.PremiumRequests(),
+		BaseTokens:          newStatsTokensJSON(stats.BaseTokens()),
+		PremiumTokens:       newStatsTokensJSON(stats.PremiumTokens()),
+		BaseCost:            stats.BaseCost().Amount(),
+		PremiumCost:         stats.PremiumCost().Amount(),
+		NegligibleRequests:  stats.NegligibleRequests(),
+		NegligibleTokens:    newStatsTokensJSON(stats.NegligibleTokens()),
+		NegligibleCost:      stats.NegligibleCost().Amount(),
+		Sessions:            stats.Sessions(false),
+		UnknownSession:      stats.HasUnknownSession(),
+		LongestGapMs:        longestGap.Milliseconds(),
+		LongestGapAvailable: longestGapAvailable,
+		PeriodStart:         stats.Period().StartAt(),
+		PeriodEnd:           stats.Period().EndAt(),
+		StoredAt:            now,
+		ExpiresAt:           now.Add(c.ttl),
+	})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, "stats.*.tmp")
+	if err != nil {
+		return
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(query)); err != nil {
+		return
+	}
+
+	c.removeExpired(now)
+}
+
+// removeExpired deletes cache files written longer than the TTL ago, so periods ending at "now"
+// do not leave a file behind for every refresh
+func (c *FileStatsCache) removeExpired(now time.Time) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || !statsCacheFilePattern.MatchString(entry.Name()) {
+			continue
+		}
+		if now.Sub(info.ModTime()) > c.ttl {
+			_ = os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+}
+
+// Invalidate makes this process ignore every entry stored so far, while other processes keep
+// using them until they expire. The monitor calls it on every refresh tick, and the entries it
+// stores afterwards are shared again.
+func (c *FileStatsCache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.since = time.Now()
+}
+
+// invalidatedAt returns the time of the last invalidation, zero when there was none
+func (c *FileStatsCache) invalidatedAt() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.since
+}
+
+// path returns the cache file of the query, named from the period timestamps and the options
+func (c *FileStatsCache) path(query entity.StatsQuery) string {
+	return filepath.Join(c.dir, query.Key()+".json")
+}
+
+// newStatsTokensJSON converts a token count for the cache file
+func newStatsTokensJSON(token entity.Token) statsTokensJSON {
+	return statsTokensJSON{
+		Input:         token.Input(),
+		Output:        token.Output(),
+		CacheRead:     token.CacheRead(),
+		CacheCreation: token.CacheCreation(),
+	}
+}
+
+// token converts the cache file fields back to a token count
+func (t statsTokensJSON) token() entity.Token {
+	return entity.NewToken(t.Input, t.Output, t.CacheRead, t.CacheCreation)
+}
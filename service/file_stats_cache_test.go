@@ -0,0 +1,238 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func newTestFileCacheStats(period entity.Period) *entity.Stats {
+	stats := entity.NewStats(
+		2, 3,
+		entity.NewToken(10, 20, 30, 40),
+		entity.NewToken(100, 200, 300, 400),
+		entity.NewCost(0.25),
+		entity.NewCost(1.5),
+		period,
+	)
+	return &stats
+}
+
+func TestFileStatsCache_SharedBetweenInstances(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	period := entity.NewPeriod(time.Date(2025, 7, 24, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond))
+
+	writer := NewFileStatsCache(dir, time.Minute)
+	if result := writer.Get(entity.NewStatsQuery(period)); result != nil {
+		t.Fatal("Expected a miss before anything was stored")
+	}
+	writer.Set(entity.NewStatsQuery(period), newTestFileCacheStats(period))
+
+	// A second instance stands in for another ccmon process
+	reader := NewFileStatsCache(dir, time.Minute)
+	result := reader.Get(entity.NewStatsQuery(period))
+	if result == nil {
+		t.Fatal("Expected cached stats to be shared through the directory")
+	}
+
+	expected := newTestFileCacheStats(period)
+	if result.BaseRequests() != 2 || result.PremiumRequests() != 3 {
+		t.Errorf("Expected 2 base and 3 premium requests, got %d and %d", result.BaseRequests(), result.PremiumRequests())
+	}
+	if result.BaseTokens() != expected.BaseTokens() || result.PremiumTokens() != expected.PremiumTokens() {
+		t.Errorf("Expected tokens %v and %v, got %v and %v", expected.BaseTokens(), expected.PremiumTokens(), result.BaseTokens(), result.PremiumTokens())
+	}
+	if result.TotalCost().Amount() != 1.75 {
+		t.Errorf("Expected total cost 1.75, got %v", result.TotalCost().Amount())
+	}
+	if !result.Period().StartAt().Equal(period.StartAt()) || !result.Period().EndAt().Equal(period.EndAt()) {
+		t.Errorf("Expected period %v - %v, got %v - %v", period.StartAt(), period.EndAt(), result.Period().StartAt(), result.Period().EndAt())
+	}
+
+	other := entity.NewPeriod(period.StartAt(), period.EndAt().Add(time.Hour))
+	if result := reader.Get(entity.NewStatsQuery(other)); result != nil {
+		t.Error("Expected a miss for a different period")
+	}
+}
+
+func TestFileStatsCache_NegligibleBucket(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	period := entity.NewPeriod(time.Date(2025, 7, 24, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond))
+	query := entity.NewStatsQuery(period).WithNegligibleCost(entity.NewCost(0.01))
+
+	stats := newTestFileCacheStats(period).WithNegligibleTotals(1, entity.NewToken(1, 2, 3, 4), entity.NewCost(0.005))
+	NewFileStatsCache(dir, time.Minute).Set(query, &stats)
+
+	reader := NewFileStatsCache(dir, time.Minute)
+	result := reader.Get(query)
+	if result == nil {
+		t.Fatal("Expected cached stats for the same threshold")
+	}
+	if result.NegligibleRequests() != 1 || result.NegligibleTokens() != entity.NewToken(1, 2, 3, 4) || result.NegligibleCost().Amount() != 0.005 {
+		t.Errorf("Expected the negligible bucket to be restored, got %d requests, %v tokens and %v cost",
+			result.NegligibleRequests(), result.NegligibleTokens(), result.NegligibleCost().Amount())
+	}
+
+	if result := reader.Get(entity.NewStatsQuery(period)); result != nil {
+		t.Error("Expected a miss without a threshold")
+	}
+	if result := reader.Get(entity.NewStatsQuery(period).WithNegligibleCost(entity.NewCost(0.02))); result != nil {
+		t.Error("Expected a miss for a different threshold")
+	}
+}
+
+func TestFileStatsCache_LongestGap(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	period := entity.NewPeriod(time.Date(2025, 7, 24, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond))
+	query := entity.NewStatsQuery(period)
+
+	stats := newTestFileCacheStats(period).WithLongestGap(95*time.Minute, true)
+	NewFileStatsCache(dir, time.Minute).Set(query, &stats)
+
+	result := NewFileStatsCache(dir, time.Minute).Get(query)
+	if result == nil {
+		t.Fatal("Expected cached stats")
+	}
+	if gap, available := result.LongestGap(); gap != 95*time.Minute || !available {
+		t.Errorf("Expected the longest gap to be restored, got %v, %v", gap, available)
+	}
+}
+
+func TestFileStatsCache_Expiration(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	period := entity.NewPeriod(time.Now().Add(-time.Hour), time.Now())
+
+	cache := NewFileStatsCache(dir, 50*time.Millisecond)
+	cache.Set(entity.NewStatsQuery(period), newTestFileCacheStats(period))
+	if result := cache.Get(entity.NewStatsQuery(period)); result == nil {
+		t.Fatal("Expected cached stats to be returned")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if result := cache.Get(entity.NewStatsQuery(period)); result != nil {
+		t.Error("Expected expired entry to return nil")
+	}
+}
+
+func TestFileStatsCache_InvalidateIsLocal(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	period := entity.NewPeriod(time.Now().Add(-time.Hour), time.Now())
+
+	monitor := NewFileStatsCache(dir, time.Minute)
+	format := NewFileStatsCache(dir, time.Minute)
+	monitor.Set(entity.NewStatsQuery(period), newTestFileCacheStats(period))
+
+	monitor.Invalidate()
+	if result := monitor.Get(entity.NewStatsQuery(period)); result != nil {
+		t.Error("Expected the invalidating process to miss entries stored before")
+	}
+	if result := format.Get(entity.NewStatsQuery(period)); result == nil {
+		t.Error("Expected other processes to keep using the entry")
+	}
+
+	// Entries stored after the invalidation are used again
+	monitor.Set(entity.NewStatsQuery(period), newTestFileCacheStats(period))
+	if result := monitor.Get(entity.NewStatsQuery(period)); result == nil {
+		t.Error("Expected the entry stored after invalidation to be returned")
+	}
+}
+
+func TestFileStatsCache_UnreadableEntryIsMiss(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	period := entity.NewPeriod(time.Now().Add(-time.Hour), time.Now())
+
+	cache := NewFileStatsCache(dir, time.Minute)
+	if err := os.WriteFile(cache.path(entity.NewStatsQuery(period)), []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write corrupt entry: %v", err)
+	}
+
+	if result := cache.Get(entity.NewStatsQuery(period)); result != nil {
+		t.Error("Expected a corrupt entry to return nil")
+	}
+}
+
+func TestFileStatsCache_RemovesExpiredFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache := NewFileStatsCache(dir, time.Minute)
+
+	old := entity.NewPeriod(time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+	cache.Set(entity.NewStatsQuery(old), newTestFileCacheStats(old))
+	foreign := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(foreign, []byte("keep"), 0644); err != nil {
+		t.Fatalf("Failed to write foreign file: %v", err)
+	}
+
+	// Age both files past the TTL
+	past := time.Now().Add(-time.Hour)
+	for _, path := range []string{cache.path(entity.NewStatsQuery(old)), foreign} {
+		if err := os.Chtimes(path, past, past); err != nil {
+			t.Fatalf("Failed to age %s: %v", path, err)
+		}
+	}
+
+	current := entity.NewPeriod(time.Now().Add(-time.Hour), time.Now())
+	cache.Set(entity.NewStatsQuery(current), newTestFileCacheStats(current))
+
+	if _, err := os.Stat(cache.path(entity.NewStatsQuery(old))); !os.IsNotExist(err) {
+		t.Error("Expected the expired entry file to be removed")
+	}
+	if _, err := os.Stat(cache.path(entity.NewStatsQuery(current))); err != nil {
+		t.Errorf("Expected the new entry file to exist: %v", err)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Errorf("Expected files the cache did not write to be kept: %v", err)
+	}
+}
+
+func TestFileStatsCache_ConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	period := entity.NewPeriod(time.Now().Add(-time.Hour), time.Now())
+
+	// Separate instances share only the directory, like separate processes
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		cache := NewFileStatsCache(dir, time.Minute)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				cache.Set(entity.NewStatsQuery(period), newTestFileCacheStats(period))
+				if result := cache.Get(entity.NewStatsQuery(period)); result != nil && result.TotalRequests() != 5 {
+					t.Errorf("Expected a complete entry with 5 requests, got %d", result.TotalRequests())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if result := NewFileStatsCache(dir, time.Minute).Get(entity.NewStatsQuery(period)); result == nil {
+		t.Error("Expected the entry to be stored after concurrent writes")
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	if err != nil {
+		t.Fatalf("Failed to list temporary files: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Expected no temporary files left, got %v", matches)
+	}
+}
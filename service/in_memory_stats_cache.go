@@ -1,7 +1,7 @@
 package service
 
 import (
-	"fmt"
+	"container/list"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,11 +10,14 @@ import (
 )
 
 // InMemoryStatsCache implements TTL-based in-memory caching for statistics.
-// It provides thread-safe access and lazy cleanup of expired entries.
+// It provides thread-safe access, lazy cleanup of expired entries and optional
+// LRU eviction to bound the number of cached periods.
 type InMemoryStatsCache struct {
-	cache          map[string]*CachedStats
-	mutex          sync.RWMutex
+	cache          map[string]*list.Element
+	lru            *list.List // Front is most recently used
+	mutex          sync.Mutex
 	ttl            time.Duration
+	maxEntries     int   // 0 means unlimited
 	cleanupRunning int32 // atomic flag for cleanup goroutine
 }
 
@@ -24,55 +27,117 @@ type CachedStats struct {
 	ExpiresAt time.Time
 }
 
-// NewInMemoryStatsCache creates a new in-memory cache instance.
+// lruEntry is the value stored in the LRU list
+type lruEntry struct {
+	key    string
+	period entity.Period
+	cached *CachedStats
+}
+
+// NewInMemoryStatsCache creates a new in-memory cache instance without entry limit.
 func NewInMemoryStatsCache(ttl time.Duration) *InMemoryStatsCache {
+	return NewInMemoryStatsCacheWithLimit(ttl, 0)
+}
+
+// NewInMemoryStatsCacheWithLimit creates a new in-memory cache instance that evicts
+// the least recently used entry once maxEntries is reached (0 = unlimited).
+func NewInMemoryStatsCacheWithLimit(ttl time.Duration, maxEntries int) *InMemoryStatsCache {
 	return &InMemoryStatsCache{
-		cache: make(map[string]*CachedStats),
-		ttl:   ttl,
+		cache:      make(map[string]*list.Element),
+		lru:        list.New(),
+		ttl:        ttl,
+		maxEntries: maxEntries,
 	}
 }
 
-// Get retrieves cached statistics for the given period.
+// Get retrieves cached statistics for the given query.
 // Returns nil if entry doesn't exist or has expired.
-func (c *InMemoryStatsCache) Get(period entity.Period) *entity.Stats {
+func (c *InMemoryStatsCache) Get(query entity.StatsQuery) *entity.Stats {
 	c.tryCleanupExpired()
 
-	key := c.generateKey(period)
+	key := query.Key()
 
-	c.mutex.RLock()
-	cached, exists := c.cache[key]
-	c.mutex.RUnlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
+	element, exists := c.cache[key]
 	if !exists {
 		return nil
 	}
 
 	// Check if expired
+	cached := element.Value.(*lruEntry).cached
 	if time.Now().After(cached.ExpiresAt) {
 		return nil
 	}
 
+	c.lru.MoveToFront(element)
 	return cached.Stats
 }
 
-// Set stores statistics in the cache for the given period.
-func (c *InMemoryStatsCache) Set(period entity.Period, stats *entity.Stats) {
+// Set stores statistics in the cache for the given query.
+func (c *InMemoryStatsCache) Set(query entity.StatsQuery, stats *entity.Stats) {
 	c.tryCleanupExpired()
 
-	key := c.generateKey(period)
-	expiresAt := time.Now().Add(c.ttl)
+	key := query.Key()
+	cached := &CachedStats{
+		Stats:     stats,
+		ExpiresAt: time.Now().Add(c.ttl),
+	}
 
 	c.mutex.Lock()
-	c.cache[key] = &CachedStats{
-		Stats:     stats,
-		ExpiresAt: expiresAt,
+	defer c.mutex.Unlock()
+
+	if element, exists := c.cache[key]; exists {
+		element.Value.(*lruEntry).cached = cached
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.cache[key] = c.lru.PushFront(&lruEntry{key: key, period: query.Period(), cached: cached})
+
+	// Evict least recently used entries beyond the limit
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// Len returns the number of cached entries, including expired entries not yet cleaned up.
+func (c *InMemoryStatsCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
+
+// Invalidate drops every cached entry so the next query recalculates the stats.
+func (c *InMemoryStatsCache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cache = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// InvalidatePeriod drops the cached entries whose period overlaps the given one, keeping the rest.
+func (c *InMemoryStatsCache) InvalidatePeriod(period entity.Period) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*lruEntry).period.Overlaps(period) {
+			c.removeElement(element)
+		}
+		element = next
 	}
-	c.mutex.Unlock()
 }
 
-// generateKey creates a unique cache key from the period timestamps.
-func (c *InMemoryStatsCache) generateKey(period entity.Period) string {
-	return fmt.Sprintf("%d_%d", period.StartAt().Unix(), period.EndAt().Unix())
+// removeElement removes an entry from both the map and the LRU list.
+// Must be called with the mutex held.
+func (c *InMemoryStatsCache) removeElement(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.cache, element.Value.(*lruEntry).key)
 }
 
 // tryCleanupExpired attempts to start a cleanup goroutine if none is running.
@@ -89,26 +154,15 @@ func (c *InMemoryStatsCache) tryCleanupExpired() {
 // cleanupExpired removes all expired entries from the cache.
 func (c *InMemoryStatsCache) cleanupExpired() {
 	now := time.Now()
-	expiredKeys := make([]string, 0)
 
-	// First pass: identify expired keys
-	c.mutex.RLock()
-	for key, cached := range c.cache {
-		if now.After(cached.ExpiresAt) {
-			expiredKeys = append(expiredKeys, key)
-		}
-	}
-	c.mutex.RUnlock()
-
-	// Second pass: remove expired entries
-	if len(expiredKeys) > 0 {
-		c.mutex.Lock()
-		for _, key := range expiredKeys {
-			// Double-check expiration in case another goroutine updated it
-			if cached, exists := c.cache[key]; exists && now.After(cached.ExpiresAt) {
-				delete(c.cache, key)
-			}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		if now.After(element.Value.(*lruEntry).cached.ExpiresAt) {
+			c.removeElement(element)
 		}
-		c.mutex.Unlock()
+		element = next
 	}
 }
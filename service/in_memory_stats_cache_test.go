@@ -16,10 +16,10 @@ func TestInMemoryStatsCache_LazyCleanup(t *testing.T) {
 	stats := &entity.Stats{}
 
 	// Add entries that will expire quickly
-	cache.Set(period, stats)
+	cache.Set(entity.NewStatsQuery(period), stats)
 
 	// Verify entry exists initially
-	if result := cache.Get(period); result == nil {
+	if result := cache.Get(entity.NewStatsQuery(period)); result == nil {
 		t.Error("Expected cached stats to be returned")
 	}
 
@@ -27,7 +27,7 @@ func TestInMemoryStatsCache_LazyCleanup(t *testing.T) {
 	time.Sleep(60 * time.Millisecond)
 
 	// Access cache to trigger lazy cleanup
-	if result := cache.Get(period); result != nil {
+	if result := cache.Get(entity.NewStatsQuery(period)); result != nil {
 		t.Error("Expected expired entry to return nil")
 	}
 
@@ -35,9 +35,9 @@ func TestInMemoryStatsCache_LazyCleanup(t *testing.T) {
 	time.Sleep(20 * time.Millisecond)
 
 	// Verify cleanup removed expired entries
-	cache.mutex.RLock()
+	cache.mutex.Lock()
 	cacheSize := len(cache.cache)
-	cache.mutex.RUnlock()
+	cache.mutex.Unlock()
 
 	if cacheSize != 0 {
 		t.Errorf("Expected cache to be empty after cleanup, but found %d entries", cacheSize)
@@ -57,7 +57,7 @@ func TestInMemoryStatsCache_ConcurrentAccess(t *testing.T) {
 	// Concurrent Set operations
 	go func() {
 		for i := 0; i < 10; i++ {
-			cache.Set(period, stats)
+			cache.Set(entity.NewStatsQuery(period), stats)
 			time.Sleep(5 * time.Millisecond)
 		}
 		done <- true
@@ -66,7 +66,7 @@ func TestInMemoryStatsCache_ConcurrentAccess(t *testing.T) {
 	// Concurrent Get operations
 	go func() {
 		for i := 0; i < 10; i++ {
-			cache.Get(period)
+			cache.Get(entity.NewStatsQuery(period))
 			time.Sleep(5 * time.Millisecond)
 		}
 		done <- true
@@ -89,8 +89,8 @@ func TestInMemoryStatsCache_CleanupGoroutineLimit(t *testing.T) {
 
 	// Trigger multiple cleanup attempts rapidly
 	for i := 0; i < 5; i++ {
-		cache.Set(period, stats)
-		cache.Get(period)
+		cache.Set(entity.NewStatsQuery(period), stats)
+		cache.Get(entity.NewStatsQuery(period))
 	}
 
 	// Give time for any goroutines to complete
@@ -101,3 +101,100 @@ func TestInMemoryStatsCache_CleanupGoroutineLimit(t *testing.T) {
 		t.Error("Expected cleanup flag to be reset, indicating no orphaned goroutines")
 	}
 }
+
+func TestInMemoryStatsCache_LRUEviction(t *testing.T) {
+	t.Parallel()
+
+	cache := NewInMemoryStatsCacheWithLimit(time.Minute, 2)
+
+	now := time.Now()
+	periodA := entity.NewPeriod(now.Add(-3*time.Hour), now)
+	periodB := entity.NewPeriod(now.Add(-2*time.Hour), now)
+	periodC := entity.NewPeriod(now.Add(-1*time.Hour), now)
+	stats := &entity.Stats{}
+
+	cache.Set(entity.NewStatsQuery(periodA), stats)
+	cache.Set(entity.NewStatsQuery(periodB), stats)
+
+	// Touch A so B becomes the least recently used entry
+	if cache.Get(entity.NewStatsQuery(periodA)) == nil {
+		t.Fatal("Expected period A to be cached")
+	}
+
+	cache.Set(entity.NewStatsQuery(periodC), stats)
+
+	if cache.Len() != 2 {
+		t.Errorf("Expected cache to be bounded to 2 entries, got %d", cache.Len())
+	}
+	if cache.Get(entity.NewStatsQuery(periodB)) != nil {
+		t.Error("Expected least recently used period B to be evicted")
+	}
+	if cache.Get(entity.NewStatsQuery(periodA)) == nil {
+		t.Error("Expected recently used period A to stay cached")
+	}
+	if cache.Get(entity.NewStatsQuery(periodC)) == nil {
+		t.Error("Expected newest period C to be cached")
+	}
+}
+
+func TestInMemoryStatsCache_UnlimitedEntries(t *testing.T) {
+	t.Parallel()
+
+	cache := NewInMemoryStatsCache(time.Minute)
+
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		cache.Set(entity.NewStatsQuery(entity.NewPeriod(now.Add(-time.Duration(i+1)*time.Minute), now)), &entity.Stats{})
+	}
+
+	if cache.Len() != 100 {
+		t.Errorf("Expected 100 cached entries without limit, got %d", cache.Len())
+	}
+}
+
+func TestInMemoryStatsCache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	cache := NewInMemoryStatsCache(time.Minute)
+
+	now := time.Now()
+	period := entity.NewPeriod(now.Add(-time.Hour), now)
+	cache.Set(entity.NewStatsQuery(period), &entity.Stats{})
+
+	cache.Invalidate()
+
+	if cache.Get(entity.NewStatsQuery(period)) != nil {
+		t.Error("Expected no cached stats after invalidation")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected an empty cache after invalidation, got %d entries", cache.Len())
+	}
+
+	cache.Set(entity.NewStatsQuery(period), &entity.Stats{})
+	if cache.Get(entity.NewStatsQuery(period)) == nil {
+		t.Error("Expected stats stored after invalidation to be cached")
+	}
+}
+
+func TestInMemoryStatsCache_InvalidatePeriod(t *testing.T) {
+	t.Parallel()
+
+	cache := NewInMemoryStatsCache(time.Minute)
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	yesterday := entity.NewStatsQuery(entity.NewPeriod(now.Add(-48*time.Hour), now.Add(-24*time.Hour)))
+	today := entity.NewStatsQuery(entity.NewPeriod(now.Add(-24*time.Hour).Add(time.Nanosecond), now))
+	allTime := entity.NewStatsQuery(entity.NewAllTimePeriod(now))
+	for _, query := range []entity.StatsQuery{yesterday, today, allTime} {
+		cache.Set(query, &entity.Stats{})
+	}
+
+	cache.InvalidatePeriod(entity.NewPeriod(now.Add(-time.Hour), now.Add(-time.Minute)))
+
+	if cache.Get(yesterday) == nil {
+		t.Error("Expected the stats of a period without new requests to stay cached")
+	}
+	if cache.Get(today) != nil || cache.Get(allTime) != nil {
+		t.Error("Expected the stats of periods overlapping the new requests to be dropped")
+	}
+}
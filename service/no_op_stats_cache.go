@@ -7,11 +7,11 @@ import "github.com/elct9620/ccmon/entity"
 type NoOpStatsCache struct{}
 
 // Get always returns nil, indicating no cached data
-func (c *NoOpStatsCache) Get(period entity.Period) *entity.Stats {
+func (c *NoOpStatsCache) Get(query entity.StatsQuery) *entity.Stats {
 	return nil
 }
 
 // Set does nothing, as caching is disabled
-func (c *NoOpStatsCache) Set(period entity.Period, stats *entity.Stats) {
+func (c *NoOpStatsCache) Set(query entity.StatsQuery, stats *entity.Stats) {
 	// No-op: caching is disabled
 }
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsdata "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// OTLPTelemetryClient submits API requests to a remote ccmon server's OTLP receiver,
+// encoded the same way Claude Code sends them
+type OTLPTelemetryClient struct {
+	client     logsv1.LogsServiceClient
+	conn       *grpc.ClientConn
+	authToken  string
+	authScheme entity.AuthScheme
+}
+
+// NewOTLPTelemetryClient creates a client for the OTLP receiver at address, sending the auth token with the scheme when set
+func NewOTLPTelemetryClient(address string, authToken string, authScheme entity.AuthScheme) (*OTLPTelemetryClient, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to OTLP receiver at %s: %w", address, err)
+	}
+
+	return &OTLPTelemetryClient{
+		client:     logsv1.NewLogsServiceClient(conn),
+		conn:       conn,
+		authToken:  authToken,
+		authScheme: authScheme,
+	}, nil
+}
+
+// Ingest submits one API request, failing when the receiver rejects it
+func (c *OTLPTelemetryClient) Ingest(ctx context.Context, req entity.APIRequest) error {
+	if c.authToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", c.authScheme.Authorization(c.authToken))
+	}
+
+	resp, err := c.client.Export(ctx, NewAPIRequestLogs(req))
+	if err != nil {
+		return fmt.Errorf("failed to submit request: %w", err)
+	}
+	if partial := resp.GetPartialSuccess(); partial != nil && partial.GetRejectedLogRecords() > 0 {
+		return fmt.Errorf("request rejected by the receiver: %s", partial.GetErrorMessage())
+	}
+	return nil
+}
+
+// Close closes the gRPC connection
+func (c *OTLPTelemetryClient) Close() error {
+	return c.conn.Close()
+}
+
+// NewAPIRequestLogs encodes an API request as a claude_code.api_request log export
+func NewAPIRequestLogs(req entity.APIRequest) *logsv1.ExportLogsServiceRequest {
+	stringAttr := func(key, value string) *commonv1.KeyValue {
+		return &commonv1.KeyValue{
+			Key:   key,
+			Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}},
+		}
+	}
+
+	attributes := []*commonv1.KeyValue{
+		stringAttr("event.name", "api_request"),
+		stringAttr("session.id", req.SessionID()),
+		stringAttr("event.timestamp", req.Timestamp().UTC().Format(time.RFC3339Nano)),
+		stringAttr("model", req.Model().String()),
+		stringAttr("input_tokens", strconv.FormatInt(req.Tokens().Input(), 10)),
+		stringAttr("output_tokens", strconv.FormatInt(req.Tokens().Output(), 10)),
+		stringAttr("cache_read_tokens", strconv.FormatInt(req.Tokens().CacheRead(), 10)),
+		stringAttr("cache_creation_tokens", strconv.FormatInt(req.Tokens().CacheCreation(), 10)),
+		stringAttr("cost_usd", strconv.FormatFloat(req.Cost().Amount(), 'f', -1, 64)),
+		stringAttr("duration_ms", strconv.FormatInt(req.DurationMS(), 10)),
+	}
+	for key, value := range req.Labels() {
+		attributes = append(attributes, stringAttr(key, value))
+	}
+
+	return &logsv1.ExportLogsServiceRequest{
+		ResourceLogs: []*logsdata.ResourceLogs{{
+			ScopeLogs: []*logsdata.ScopeLogs{{
+				LogRecords: []*logsdata.LogRecord{{
+					Body:       &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: "claude_code.api_request"}},
+					Attributes: attributes,
+				}},
+			}},
+		}},
+	}
+}
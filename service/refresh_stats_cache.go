@@ -0,0 +1,55 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// RefreshStatsCache caches statistics until it is explicitly invalidated.
+// The monitor invalidates it on every refresh tick, so repeated queries for the
+// same period within one refresh cycle share a single server round-trip.
+type RefreshStatsCache struct {
+	cache map[string]*entity.Stats
+	mutex sync.Mutex
+}
+
+// NewRefreshStatsCache creates a new empty refresh cycle cache.
+func NewRefreshStatsCache() *RefreshStatsCache {
+	return &RefreshStatsCache{
+		cache: make(map[string]*entity.Stats),
+	}
+}
+
+// Get retrieves cached statistics for the given query.
+// Returns nil if the query was not cached since the last invalidation.
+func (c *RefreshStatsCache) Get(query entity.StatsQuery) *entity.Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.cache[query.Key()]
+}
+
+// Set stores statistics for the given query until the next invalidation.
+func (c *RefreshStatsCache) Set(query entity.StatsQuery, stats *entity.Stats) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cache[query.Key()] = stats
+}
+
+// Invalidate drops every cached entry so the next query reaches the server.
+func (c *RefreshStatsCache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cache = make(map[string]*entity.Stats)
+}
+
+// Size returns the number of cached periods.
+func (c *RefreshStatsCache) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.cache)
+}
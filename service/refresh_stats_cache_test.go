@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestRefreshStatsCache_GetSet(t *testing.T) {
+	cache := NewRefreshStatsCache()
+	now := time.Now()
+	period := entity.NewPeriod(now.Add(-1*time.Hour), now)
+	other := entity.NewPeriod(now.Add(-2*time.Hour), now)
+	stats := &entity.Stats{}
+
+	if result := cache.Get(entity.NewStatsQuery(period)); result != nil {
+		t.Error("Expected empty cache to return nil")
+	}
+
+	cache.Set(entity.NewStatsQuery(period), stats)
+
+	if result := cache.Get(entity.NewStatsQuery(period)); result != stats {
+		t.Error("Expected cached stats to be returned for the same period")
+	}
+	if result := cache.Get(entity.NewStatsQuery(other)); result != nil {
+		t.Error("Expected a different period to miss the cache")
+	}
+}
+
+func TestRefreshStatsCache_Invalidate(t *testing.T) {
+	cache := NewRefreshStatsCache()
+	now := time.Now()
+	period := entity.NewPeriod(now.Add(-1*time.Hour), now)
+
+	cache.Set(entity.NewStatsQuery(period), &entity.Stats{})
+	cache.Set(entity.NewStatsQuery(entity.NewPeriod(now.Add(-24*time.Hour), now)), &entity.Stats{})
+	if size := cache.Size(); size != 2 {
+		t.Fatalf("Expected 2 cached entries, got %d", size)
+	}
+
+	cache.Invalidate()
+
+	if size := cache.Size(); size != 0 {
+		t.Errorf("Expected cache to be empty after invalidation, got %d entries", size)
+	}
+	if result := cache.Get(entity.NewStatsQuery(period)); result != nil {
+		t.Error("Expected invalidated entry to return nil")
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// StatsDPublisher pushes usage gauges to a StatsD server over UDP. With tags configured the lines
+// use the DogStatsD "|#tag" extension, which plain StatsD servers do not understand.
+type StatsDPublisher struct {
+	conn   net.Conn
+	prefix string
+	names  map[entity.UsageGauge]string
+	tags   []string
+}
+
+// NewStatsDPublisher creates a publisher sending to the StatsD server at address (host:port)
+func NewStatsDPublisher(address string) (*StatsDPublisher, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to StatsD at %s: %w", address, err)
+	}
+
+	return &StatsDPublisher{
+		conn:  conn,
+		names: make(map[entity.UsageGauge]string),
+	}, nil
+}
+
+// SetPrefix sets the text prepended to every metric name, e.g. "ccmon."
+func (p *StatsDPublisher) SetPrefix(prefix string) {
+	p.prefix = prefix
+}
+
+// SetNames replaces the default metric names (the gauge keys) of the given gauges
+func (p *StatsDPublisher) SetNames(names map[entity.UsageGauge]string) {
+	p.names = names
+}
+
+// SetTags attaches DogStatsD tags such as "env:prod" to every metric
+func (p *StatsDPublisher) SetTags(tags []string) {
+	p.tags = tags
+}
+
+// Publish sends the gauges in a single datagram, one metric per line
+func (p *StatsDPublisher) Publish(values []entity.GaugeValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(values))
+	for _, value := range values {
+		lines = append(lines, p.formatGauge(value))
+	}
+
+	if _, err := p.conn.Write([]byte(strings.Join(lines, "\n"))); err != nil {
+		return fmt.Errorf("failed to send metrics to StatsD: %w", err)
+	}
+	return nil
+}
+
+// Close releases the UDP socket
+func (p *StatsDPublisher) Close() error {
+	return p.conn.Close()
+}
+
+// formatGauge renders a gauge as "name:value|g", followed by "|#tags" when tags are set
+func (p *StatsDPublisher) formatGauge(value entity.GaugeValue) string {
+	name, ok := p.names[value.Gauge()]
+	if !ok || name == "" {
+		name = value.Gauge().Key()
+	}
+
+	line := p.prefix + name + ":" + strconv.FormatFloat(value.Value(), 'f', -1, 64) + "|g"
+	if len(p.tags) > 0 {
+		line += "|#" + strings.Join(p.tags, ",")
+	}
+	return line
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestStatsDPublisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	values := []entity.GaugeValue{
+		entity.NewGaugeValue(entity.DailyCostGauge, 1.25),
+		entity.NewGaugeValue(entity.DailyRequestsGauge, 42),
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		names  map[entity.UsageGauge]string
+		tags   []string
+		want   string
+	}{
+		{
+			name: "default names",
+			want: "daily_cost:1.25|g\ndaily_requests:42|g",
+		},
+		{
+			name:   "prefix and renamed gauge",
+			prefix: "ccmon.",
+			names:  map[entity.UsageGauge]string{entity.DailyCostGauge: "cost.today"},
+			want:   "ccmon.cost.today:1.25|g\nccmon.daily_requests:42|g",
+		},
+		{
+			name: "DogStatsD tags",
+			tags: []string{"env:prod", "team:ai"},
+			want: "daily_cost:1.25|g|#env:prod,team:ai\ndaily_requests:42|g|#env:prod,team:ai",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			defer func() { _ = listener.Close() }()
+
+			publisher, err := NewStatsDPublisher(listener.LocalAddr().String())
+			if err != nil {
+				t.Fatalf("NewStatsDPublisher() error = %v", err)
+			}
+			defer func() { _ = publisher.Close() }()
+			publisher.SetPrefix(tt.prefix)
+			if tt.names != nil {
+				publisher.SetNames(tt.names)
+			}
+			publisher.SetTags(tt.tags)
+
+			if err := publisher.Publish(values); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			buffer := make([]byte, 1024)
+			_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+			n, _, err := listener.ReadFrom(buffer)
+			if err != nil {
+				t.Fatalf("failed to read datagram: %v", err)
+			}
+			if got := string(buffer[:n]); got != tt.want {
+				t.Errorf("datagram = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
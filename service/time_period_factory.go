@@ -8,7 +8,9 @@ import (
 
 // TimePeriodFactory implements PeriodFactory using timezone-aware calculations
 type TimePeriodFactory struct {
-	timezone *time.Location
+	timezone             *time.Location
+	clock                entity.Clock
+	monthlyIncludesToday bool
 }
 
 // NewTimePeriodFactory creates a new TimePeriodFactory with the given timezone
@@ -17,15 +19,34 @@ func NewTimePeriodFactory(timezone *time.Location) *TimePeriodFactory {
 		timezone = time.UTC
 	}
 	return &TimePeriodFactory{
-		timezone: timezone,
+		timezone:             timezone,
+		clock:                entity.SystemClock{},
+		monthlyIncludesToday: true,
 	}
 }
 
+// SetClock sets the source of the current time used for period boundaries
+func (f *TimePeriodFactory) SetClock(clock entity.Clock) {
+	f.clock = clock
+}
+
+// SetMonthlyIncludesToday controls whether the monthly period runs to the end of the month (the default)
+// or stops before today, so month-to-date figures only cover completed days
+func (f *TimePeriodFactory) SetMonthlyIncludesToday(include bool) {
+	f.monthlyIncludesToday = include
+}
+
 // CreateDaily creates a period for today using timezone-aware boundaries
 func (f *TimePeriodFactory) CreateDaily() entity.Period {
-	now := time.Now().In(f.timezone)
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, f.timezone)
-	dayEnd := dayStart.Add(24*time.Hour - time.Nanosecond)
+	return f.CreateDailyFor(f.clock.Now())
+}
+
+// CreateDailyFor creates a period for the calendar day containing date, in the factory's timezone
+func (f *TimePeriodFactory) CreateDailyFor(date time.Time) entity.Period {
+	day := date.In(f.timezone)
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, f.timezone)
+	// Step by calendar day so DST changes give the 23 or 25 hour local day
+	dayEnd := dayStart.AddDate(0, 0, 1).Add(-time.Nanosecond)
 
 	// Convert to UTC for database queries but maintain timezone-aware boundaries
 	return entity.NewPeriod(dayStart.UTC(), dayEnd.UTC())
@@ -33,12 +54,33 @@ func (f *TimePeriodFactory) CreateDaily() entity.Period {
 
 // CreateMonthly creates a period for current month using timezone-aware boundaries
 func (f *TimePeriodFactory) CreateMonthly() entity.Period {
-	now := time.Now().In(f.timezone)
+	now := f.clock.Now().In(f.timezone)
 	// First day of current month at 00:00:00 in user's timezone
 	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, f.timezone)
 	// First day of next month minus 1 nanosecond to get end of current month
 	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	if !f.monthlyIncludesToday {
+		// End just before today; on the first of the month this leaves no completed days and matches nothing
+		todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, f.timezone)
+		monthEnd = todayStart.Add(-time.Nanosecond)
+	}
 
 	// Convert to UTC for database queries but maintain timezone-aware boundaries
 	return entity.NewPeriod(monthStart.UTC(), monthEnd.UTC())
 }
+
+// CreateRolling creates a period ending now and starting exactly days×24h before, unlike the calendar
+// based periods it does not snap to midnight, so it stays the same length across DST changes
+func (f *TimePeriodFactory) CreateRolling(days int) entity.Period {
+	now := f.clock.Now().In(f.timezone)
+	start := now.Add(-time.Duration(days) * 24 * time.Hour)
+
+	// Convert to UTC for database queries
+	return entity.NewPeriod(start.UTC(), now.UTC())
+}
+
+// CreateScheduled creates a period for the window of the schedule containing now, or the latest one
+// before it when now is outside every window, with window times in the factory's timezone
+func (f *TimePeriodFactory) CreateScheduled(schedule entity.Schedule) entity.Period {
+	return schedule.Window(f.clock.Now().In(f.timezone))
+}
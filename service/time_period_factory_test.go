@@ -3,6 +3,8 @@ package service
 import (
 	"testing"
 	"time"
+
+	"github.com/elct9620/ccmon/entity"
 )
 
 func TestTimePeriodFactory(t *testing.T) {
@@ -67,3 +69,361 @@ func TestTimePeriodFactory(t *testing.T) {
 		}
 	})
 }
+
+func TestTimePeriodFactory_SetClock(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		timezone      *time.Location
+		expectedDay   time.Time
+		expectedMonth time.Time
+	}{
+		{
+			name:          "UTC midday",
+			now:           time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			expectedDay:   time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC),
+			expectedMonth: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "UTC new month is still the previous day in New York",
+			now:           time.Date(2025, 3, 1, 2, 0, 0, 0, time.UTC),
+			timezone:      newYork,
+			expectedDay:   time.Date(2025, 2, 28, 0, 0, 0, 0, newYork),
+			expectedMonth: time.Date(2025, 2, 1, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			factory := NewTimePeriodFactory(tt.timezone)
+			factory.SetClock(entity.NewFixedClock(tt.now))
+
+			if start := factory.CreateDaily().StartAt(); !start.Equal(tt.expectedDay) {
+				t.Errorf("daily period start: got %v, want %v", start, tt.expectedDay)
+			}
+			if start := factory.CreateMonthly().StartAt(); !start.Equal(tt.expectedMonth) {
+				t.Errorf("monthly period start: got %v, want %v", start, tt.expectedMonth)
+			}
+		})
+	}
+}
+
+func TestTimePeriodFactory_CreateDailyFor(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		date          time.Time
+		timezone      *time.Location
+		expectedStart time.Time
+	}{
+		{
+			name:          "midnight date in UTC",
+			date:          time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			expectedStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "date parsed in the factory timezone",
+			date:          time.Date(2025, 1, 13, 0, 0, 0, 0, newYork),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 1, 13, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:          "UTC instant falls on the previous day in New York",
+			date:          time.Date(2025, 1, 13, 3, 0, 0, 0, time.UTC),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 1, 12, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			period := NewTimePeriodFactory(tt.timezone).CreateDailyFor(tt.date)
+
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("period start: got %v, want %v", period.StartAt(), tt.expectedStart)
+			}
+			expectedEnd := tt.expectedStart.Add(24*time.Hour - time.Nanosecond)
+			if !period.EndAt().Equal(expectedEnd) {
+				t.Errorf("period end: got %v, want %v", period.EndAt(), expectedEnd)
+			}
+		})
+	}
+}
+
+func TestTimePeriodFactory_DaylightSavingTime(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		now                time.Time
+		expectedDayStart   time.Time // UTC
+		expectedDayEnd     time.Time // UTC, exclusive
+		expectedMonthStart time.Time // UTC
+		expectedMonthEnd   time.Time // UTC, exclusive
+	}{
+		{
+			name:               "spring forward day is 23 hours",
+			now:                time.Date(2025, 3, 9, 12, 0, 0, 0, newYork),
+			expectedDayStart:   time.Date(2025, 3, 9, 5, 0, 0, 0, time.UTC),
+			expectedDayEnd:     time.Date(2025, 3, 10, 4, 0, 0, 0, time.UTC),
+			expectedMonthStart: time.Date(2025, 3, 1, 5, 0, 0, 0, time.UTC),
+			expectedMonthEnd:   time.Date(2025, 4, 1, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:               "fall back day is 25 hours",
+			now:                time.Date(2025, 11, 2, 12, 0, 0, 0, newYork),
+			expectedDayStart:   time.Date(2025, 11, 2, 4, 0, 0, 0, time.UTC),
+			expectedDayEnd:     time.Date(2025, 11, 3, 5, 0, 0, 0, time.UTC),
+			expectedMonthStart: time.Date(2025, 11, 1, 4, 0, 0, 0, time.UTC),
+			expectedMonthEnd:   time.Date(2025, 12, 1, 5, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			factory := NewTimePeriodFactory(newYork)
+			factory.SetClock(entity.NewFixedClock(tt.now))
+
+			daily := factory.CreateDaily()
+			if !daily.StartAt().Equal(tt.expectedDayStart) {
+				t.Errorf("daily period start: got %v, want %v", daily.StartAt(), tt.expectedDayStart)
+			}
+			if end := daily.EndAt().Add(time.Nanosecond); !end.Equal(tt.expectedDayEnd) {
+				t.Errorf("daily period end: got %v, want %v", end, tt.expectedDayEnd)
+			}
+
+			monthly := factory.CreateMonthly()
+			if !monthly.StartAt().Equal(tt.expectedMonthStart) {
+				t.Errorf("monthly period start: got %v, want %v", monthly.StartAt(), tt.expectedMonthStart)
+			}
+			if end := monthly.EndAt().Add(time.Nanosecond); !end.Equal(tt.expectedMonthEnd) {
+				t.Errorf("monthly period end: got %v, want %v", end, tt.expectedMonthEnd)
+			}
+		})
+	}
+}
+
+func TestTimePeriodFactory_MonthlyIncludesToday(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		timezone      *time.Location
+		includeToday  bool
+		expectedStart time.Time
+		expectedEnd   time.Time // exclusive
+	}{
+		{
+			name:          "included mid month runs to the end of the month",
+			now:           time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			includeToday:  true,
+			expectedStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "excluded mid month stops before today",
+			now:           time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			includeToday:  false,
+			expectedStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "excluded on the first of the month leaves no days",
+			now:           time.Date(2025, 7, 1, 0, 30, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			includeToday:  false,
+			expectedStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "included on the first of the month covers the whole month",
+			now:           time.Date(2025, 7, 1, 0, 30, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			includeToday:  true,
+			expectedStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "excluded on the last day of the month covers all earlier days",
+			now:           time.Date(2025, 2, 28, 23, 59, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			includeToday:  false,
+			expectedStart: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "excluded today follows the factory timezone",
+			now:           time.Date(2025, 7, 31, 16, 0, 0, 0, time.UTC), // August 1st in Tokyo
+			timezone:      tokyo,
+			includeToday:  false,
+			expectedStart: time.Date(2025, 8, 1, 0, 0, 0, 0, tokyo),
+			expectedEnd:   time.Date(2025, 8, 1, 0, 0, 0, 0, tokyo),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			factory := NewTimePeriodFactory(tt.timezone)
+			factory.SetClock(entity.NewFixedClock(tt.now))
+			factory.SetMonthlyIncludesToday(tt.includeToday)
+
+			period := factory.CreateMonthly()
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("monthly period start: got %v, want %v", period.StartAt(), tt.expectedStart)
+			}
+			if end := period.EndAt().Add(time.Nanosecond); !end.Equal(tt.expectedEnd) {
+				t.Errorf("monthly period end: got %v, want %v", end, tt.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestTimePeriodFactory_CreateRolling(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		days          int
+		expectedStart time.Time // UTC
+	}{
+		{
+			name:          "last 7 days from mid-day, not calendar days",
+			now:           time.Date(2025, 7, 10, 15, 30, 0, 0, newYork),
+			days:          7,
+			expectedStart: time.Date(2025, 7, 3, 19, 30, 0, 0, time.UTC),
+		},
+		{
+			name:          "across spring forward stays 7×24h",
+			now:           time.Date(2025, 3, 12, 12, 0, 0, 0, newYork),
+			days:          7,
+			expectedStart: time.Date(2025, 3, 5, 16, 0, 0, 0, time.UTC), // 11:00 local, an hour off the wall clock
+		},
+		{
+			name:          "last 30 days",
+			now:           time.Date(2025, 7, 10, 15, 30, 0, 0, newYork),
+			days:          30,
+			expectedStart: time.Date(2025, 6, 10, 19, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			factory := NewTimePeriodFactory(newYork)
+			factory.SetClock(entity.NewFixedClock(tt.now))
+
+			rolling := factory.CreateRolling(tt.days)
+			if !rolling.EndAt().Equal(tt.now) {
+				t.Errorf("rolling period end: got %v, want %v", rolling.EndAt(), tt.now)
+			}
+			if !rolling.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("rolling period start: got %v, want %v", rolling.StartAt(), tt.expectedStart)
+			}
+			if length := rolling.EndAt().Sub(rolling.StartAt()); length != time.Duration(tt.days)*24*time.Hour {
+				t.Errorf("rolling period length: got %v, want exactly %d×24h", length, tt.days)
+			}
+			if rolling.StartAt().Location() != time.UTC || rolling.EndAt().Location() != time.UTC {
+				t.Errorf("rolling period boundaries should be in UTC for queries")
+			}
+		})
+	}
+}
+
+func TestTimePeriodFactory_CreateScheduled(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	schedule, err := entity.ParseSchedule("mon-fri 09:00-17:00")
+	if err != nil {
+		t.Fatalf("Failed to parse schedule: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		expectedStart time.Time // UTC
+		expectedEnd   time.Time // UTC, exclusive
+	}{
+		{
+			name:          "inside the window in the factory timezone",
+			now:           time.Date(2025, 7, 2, 18, 0, 0, 0, time.UTC), // 14:00 in New York
+			expectedStart: time.Date(2025, 7, 2, 13, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 7, 2, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "a UTC weekday that is still sunday in New York",
+			now:           time.Date(2025, 7, 7, 2, 0, 0, 0, time.UTC), // Sunday 22:00 in New York
+			expectedStart: time.Date(2025, 7, 4, 13, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 7, 4, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "window after the spring forward change",
+			now:           time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC), // Monday 10:00 EDT
+			expectedStart: time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 3, 10, 21, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			factory := NewTimePeriodFactory(newYork)
+			factory.SetClock(entity.NewFixedClock(tt.now))
+
+			period := factory.CreateScheduled(schedule)
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("Expected start %v, got %v", tt.expectedStart, period.StartAt())
+			}
+			if !period.EndAt().Equal(tt.expectedEnd.Add(-time.Nanosecond)) {
+				t.Errorf("Expected end %v, got %v", tt.expectedEnd, period.EndAt().Add(time.Nanosecond))
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package testutil
 
 import (
 	"fmt"
+	"io"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
@@ -56,6 +59,15 @@ func (m *MockAPIRequestRepository) Save(req entity.APIRequest) error {
 	return nil
 }
 
+// SaveBatch implements usecase.APIRequestRepository
+func (m *MockAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.requests = append(m.requests, reqs...)
+	return nil
+}
+
 // FindByPeriodWithLimit implements usecase.APIRequestRepository
 func (m *MockAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
 	if m.err != nil {
@@ -113,6 +125,105 @@ func (m *MockAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, e
 	return deletedCount, nil
 }
 
+// CountRequests implements usecase.RecordLimitRepository
+func (m *MockAPIRequestRepository) CountRequests() (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return len(m.requests), nil
+}
+
+// DeleteOldestBeyond implements usecase.RecordLimitRepository
+func (m *MockAPIRequestRepository) DeleteOldestBeyond(maxRecords int) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	excess := len(m.requests) - maxRecords
+	if excess <= 0 {
+		return 0, nil
+	}
+
+	sorted := make([]entity.APIRequest, len(m.requests))
+	copy(sorted, m.requests)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp().Before(sorted[j].Timestamp())
+	})
+
+	// Health check probes are never evicted
+	deleted := 0
+	remaining := make([]entity.APIRequest, 0, len(sorted))
+	for _, req := range sorted {
+		if deleted < excess && !req.IsHealthCheck() {
+			deleted++
+			continue
+		}
+		remaining = append(remaining, req)
+	}
+
+	m.requests = remaining
+	return deleted, nil
+}
+
+// DeleteByPeriod implements usecase.APIRequestRepository
+func (m *MockAPIRequestRepository) DeleteByPeriod(period entity.Period) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	var remaining []entity.APIRequest
+	deletedCount := 0
+
+	for _, req := range m.requests {
+		if req.Timestamp().Before(period.StartAt()) || req.Timestamp().After(period.EndAt()) {
+			remaining = append(remaining, req)
+		} else {
+			deletedCount++
+		}
+	}
+
+	m.requests = remaining
+	return deletedCount, nil
+}
+
+// UpdateNote implements usecase.APIRequestRepository
+func (m *MockAPIRequestRepository) UpdateNote(sessionID string, timestamp time.Time, note string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	for i, req := range m.requests {
+		if req.SessionID() == sessionID && req.Timestamp().Equal(timestamp) {
+			m.requests[i] = req.WithNote(note)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// FindModels implements usecase.ModelRepository
+func (m *MockAPIRequestRepository) FindModels() ([]entity.ModelUsage, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var models []entity.ModelUsage
+	index := make(map[string]int)
+	for _, req := range m.requests {
+		name := req.Model().String()
+		if i, ok := index[name]; ok {
+			models[i] = models[i].Add(req.Timestamp())
+			continue
+		}
+		index[name] = len(models)
+		models = append(models, entity.NewModelUsage(req.Model(), req.Timestamp(), req.Timestamp(), 1))
+	}
+
+	entity.SortModelUsages(models)
+	return models, nil
+}
+
 // MockStatsRepository wraps MockAPIRequestRepository to implement StatsRepository
 type MockStatsRepository struct {
 	apiRepo *MockAPIRequestRepository
@@ -123,13 +234,14 @@ func NewMockStatsRepository(apiRepo *MockAPIRequestRepository) *MockStatsReposit
 	return &MockStatsRepository{apiRepo: apiRepo}
 }
 
-// GetStatsByPeriod implements usecase.StatsRepository
-func (m *MockStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+// GetStats implements usecase.StatsRepository
+func (m *MockStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	period := query.Period()
 	requests, err := m.apiRepo.FindByPeriodWithLimit(period, 0, 0)
 	if err != nil {
 		return entity.Stats{}, err
 	}
-	return entity.NewStatsFromRequests(requests, period), nil
+	return entity.NewClassifiedStatsFromRequests(requests, period, query.Classifier()).WithNegligible(requests, query.NegligibleCost()), nil
 }
 
 // InstrumentedRepository wraps a repository to count method calls for performance testing
@@ -151,6 +263,11 @@ func (r *InstrumentedRepository) Save(req entity.APIRequest) error {
 	return r.repo.Save(req)
 }
 
+// SaveBatch implements usecase.APIRequestRepository
+func (r *InstrumentedRepository) SaveBatch(reqs []entity.APIRequest) error {
+	return r.repo.SaveBatch(reqs)
+}
+
 // FindByPeriodWithLimit implements usecase.APIRequestRepository with call counting
 func (r *InstrumentedRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
 	*r.callCount++
@@ -162,11 +279,21 @@ func (r *InstrumentedRepository) FindAll() ([]entity.APIRequest, error) {
 	return r.repo.FindAll()
 }
 
+// DeleteByPeriod implements usecase.APIRequestRepository
+func (r *InstrumentedRepository) DeleteByPeriod(period entity.Period) (int, error) {
+	return r.repo.DeleteByPeriod(period)
+}
+
 // DeleteOlderThan implements usecase.APIRequestRepository
 func (r *InstrumentedRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
 	return r.repo.DeleteOlderThan(cutoffTime)
 }
 
+// UpdateNote implements usecase.APIRequestRepository
+func (r *InstrumentedRepository) UpdateNote(sessionID string, timestamp time.Time, note string) (bool, error) {
+	return r.repo.UpdateNote(sessionID, timestamp, note)
+}
+
 // InstrumentedStatsRepository wraps InstrumentedRepository to implement StatsRepository
 type InstrumentedStatsRepository struct {
 	apiRepo *InstrumentedRepository
@@ -177,13 +304,14 @@ func NewInstrumentedStatsRepository(apiRepo *InstrumentedRepository) *Instrument
 	return &InstrumentedStatsRepository{apiRepo: apiRepo}
 }
 
-// GetStatsByPeriod implements usecase.StatsRepository with call counting
-func (m *InstrumentedStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+// GetStats implements usecase.StatsRepository with call counting
+func (m *InstrumentedStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	period := query.Period()
 	requests, err := m.apiRepo.FindByPeriodWithLimit(period, 0, 0)
 	if err != nil {
 		return entity.Stats{}, err
 	}
-	return entity.NewStatsFromRequests(requests, period), nil
+	return entity.NewClassifiedStatsFromRequests(requests, period, query.Classifier()).WithNegligible(requests, query.NegligibleCost()), nil
 }
 
 // Factory Methods for Convenience
@@ -249,8 +377,8 @@ func CreateTestStats() entity.Stats {
 
 // MockStatsCache implements usecase.StatsCache for testing
 type MockStatsCache struct {
-	getFunc   func(period entity.Period) *entity.Stats
-	setFunc   func(period entity.Period, stats *entity.Stats)
+	getFunc   func(query entity.StatsQuery) *entity.Stats
+	setFunc   func(query entity.StatsQuery, stats *entity.Stats)
 	getCalled int
 	setCalled int
 }
@@ -261,17 +389,17 @@ func NewMockStatsCache() *MockStatsCache {
 }
 
 // NewMockStatsCacheWithData creates a mock cache that returns specific stats for Get calls
-func NewMockStatsCacheWithData(getFunc func(period entity.Period) *entity.Stats) *MockStatsCache {
+func NewMockStatsCacheWithData(getFunc func(query entity.StatsQuery) *entity.Stats) *MockStatsCache {
 	return &MockStatsCache{getFunc: getFunc}
 }
 
 // SetGetFunc sets the function to be called for Get operations
-func (m *MockStatsCache) SetGetFunc(f func(period entity.Period) *entity.Stats) {
+func (m *MockStatsCache) SetGetFunc(f func(query entity.StatsQuery) *entity.Stats) {
 	m.getFunc = f
 }
 
 // SetSetFunc sets the function to be called for Set operations
-func (m *MockStatsCache) SetSetFunc(f func(period entity.Period, stats *entity.Stats)) {
+func (m *MockStatsCache) SetSetFunc(f func(query entity.StatsQuery, stats *entity.Stats)) {
 	m.setFunc = f
 }
 
@@ -286,27 +414,27 @@ func (m *MockStatsCache) SetCallCount() int {
 }
 
 // Get implements usecase.StatsCache
-func (m *MockStatsCache) Get(period entity.Period) *entity.Stats {
+func (m *MockStatsCache) Get(query entity.StatsQuery) *entity.Stats {
 	m.getCalled++
 	if m.getFunc != nil {
-		return m.getFunc(period)
+		return m.getFunc(query)
 	}
 	return nil
 }
 
 // Set implements usecase.StatsCache
-func (m *MockStatsCache) Set(period entity.Period, stats *entity.Stats) {
+func (m *MockStatsCache) Set(query entity.StatsQuery, stats *entity.Stats) {
 	m.setCalled++
 	if m.setFunc != nil {
-		m.setFunc(period, stats)
+		m.setFunc(query, stats)
 	}
 }
 
 // NoOpStatsCache creates a cache that does nothing (for testing when caching is disabled)
 func NewNoOpStatsCache() *MockStatsCache {
 	return &MockStatsCache{
-		getFunc: func(period entity.Period) *entity.Stats { return nil },
-		setFunc: func(period entity.Period, stats *entity.Stats) {},
+		getFunc: func(query entity.StatsQuery) *entity.Stats { return nil },
+		setFunc: func(query entity.StatsQuery, stats *entity.Stats) {},
 	}
 }
 
@@ -341,6 +469,52 @@ func (m *MockPlanRepository) GetConfiguredPlan() (entity.Plan, error) {
 	return m.plan, m.err
 }
 
+// MockModelTierRepository implements usecase.ModelTierRepository in memory for testing
+type MockModelTierRepository struct {
+	mu         sync.Mutex
+	classifier entity.ModelClassifier
+	err        error
+	saveCount  int
+}
+
+// NewMockModelTierRepository creates a new mock model tier repository
+func NewMockModelTierRepository(classifier entity.ModelClassifier) *MockModelTierRepository {
+	return &MockModelTierRepository{classifier: classifier}
+}
+
+// SetError sets an error to be returned when saving
+func (m *MockModelTierRepository) SetError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// SaveCount returns how many times overrides were saved
+func (m *MockModelTierRepository) SaveCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveCount
+}
+
+// GetClassifier implements usecase.ModelTierRepository
+func (m *MockModelTierRepository) GetClassifier() entity.ModelClassifier {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.classifier
+}
+
+// SaveClassifier implements usecase.ModelTierRepository
+func (m *MockModelTierRepository) SaveClassifier(classifier entity.ModelClassifier) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.classifier = classifier
+	m.saveCount++
+	return nil
+}
+
 // MockRepositoryWithDeleteFunc allows customization of DeleteOlderThan behavior for cleanup testing
 type MockRepositoryWithDeleteFunc struct {
 	*MockAPIRequestRepository
@@ -399,13 +573,14 @@ func (m *MockRepositoryWithCustomFunc) FindByPeriodWithLimit(period entity.Perio
 	return m.MockAPIRequestRepository.FindByPeriodWithLimit(period, limit, offset)
 }
 
-// GetStatsByPeriod implements StatsRepository interface by calculating stats from requests
-func (m *MockRepositoryWithCustomFunc) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+// GetStats implements StatsRepository interface by calculating stats from requests
+func (m *MockRepositoryWithCustomFunc) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	period := query.Period()
 	requests, err := m.FindByPeriodWithLimit(period, 0, 0)
 	if err != nil {
 		return entity.Stats{}, err
 	}
-	return entity.NewStatsFromRequests(requests, period), nil
+	return entity.NewClassifiedStatsFromRequests(requests, period, query.Classifier()).WithNegligible(requests, query.NegligibleCost()), nil
 }
 
 // MockPeriodBasedRepository allows different data for different periods (for usage variables testing)
@@ -439,13 +614,14 @@ func (m *MockPeriodBasedRepository) FindByPeriodWithLimit(period entity.Period,
 	return m.dailyRequests, nil
 }
 
-// GetStatsByPeriod implements StatsRepository interface by calculating stats from requests
-func (m *MockPeriodBasedRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+// GetStats implements StatsRepository interface by calculating stats from requests
+func (m *MockPeriodBasedRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	period := query.Period()
 	requests, err := m.FindByPeriodWithLimit(period, 0, 0)
 	if err != nil {
 		return entity.Stats{}, err
 	}
-	return entity.NewStatsFromRequests(requests, period), nil
+	return entity.NewClassifiedStatsFromRequests(requests, period, query.Classifier()).WithNegligible(requests, query.NegligibleCost()), nil
 }
 
 // Helper function to create API requests for testing - matches the pattern from CLI tests
@@ -511,3 +687,31 @@ func CreateTestAPIRequestsSet(dailyBaseRequests, dailyPremiumRequests, monthlyBa
 
 	return requests
 }
+
+// MockSnapshotRepository implements usecase.SnapshotRepository with fixed content for testing
+type MockSnapshotRepository struct {
+	data    []byte
+	records int
+	err     error
+}
+
+// NewMockSnapshotRepository creates a mock snapshot repository writing data and reporting records
+func NewMockSnapshotRepository(data []byte, records int) *MockSnapshotRepository {
+	return &MockSnapshotRepository{data: data, records: records}
+}
+
+// SetError sets an error to be returned when writing the snapshot
+func (m *MockSnapshotRepository) SetError(err error) {
+	m.err = err
+}
+
+// WriteSnapshot writes the fixed content to w
+func (m *MockSnapshotRepository) WriteSnapshot(w io.Writer) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	if _, err := w.Write(m.data); err != nil {
+		return 0, err
+	}
+	return m.records, nil
+}
@@ -175,7 +175,7 @@ func TestNewMockStatsRepository(t *testing.T) {
 	}
 }
 
-func TestMockStatsRepository_GetStatsByPeriod(t *testing.T) {
+func TestMockStatsRepository_GetStats(t *testing.T) {
 	apiRepo, statsRepo := NewMockRepositoryPair()
 
 	now := time.Now()
@@ -190,7 +190,7 @@ func TestMockStatsRepository_GetStatsByPeriod(t *testing.T) {
 	}
 
 	period := entity.NewAllTimePeriod(now)
-	stats, err := statsRepo.GetStatsByPeriod(period)
+	stats, err := statsRepo.GetStats(entity.NewStatsQuery(period))
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -252,7 +252,7 @@ func TestNewInstrumentedRepositoryPair(t *testing.T) {
 	}
 
 	period := entity.NewAllTimePeriod(now)
-	_, err := statsRepo.GetStatsByPeriod(period)
+	_, err := statsRepo.GetStats(entity.NewStatsQuery(period))
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -309,7 +309,7 @@ func TestNewMockRepositoryWithTestData(t *testing.T) {
 
 	// Verify we can get stats
 	period := entity.NewAllTimePeriod(time.Now())
-	stats, err := statsRepo.GetStatsByPeriod(period)
+	stats, err := statsRepo.GetStats(entity.NewStatsQuery(period))
 	if err != nil {
 		t.Errorf("Unexpected error getting stats: %v", err)
 	}
@@ -409,3 +409,40 @@ func TestCreateTestStats(t *testing.T) {
 		t.Error("Expected non-zero premium cost")
 	}
 }
+
+func TestMockAPIRequestRepository_DeleteOldestBeyond(t *testing.T) {
+	repo := NewMockAPIRequestRepository()
+	now := time.Now()
+
+	// Saved out of order, the oldest requests are deleted first
+	for _, req := range []entity.APIRequest{
+		CreateTestAPIRequest("middle", now.Add(-1*time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+		CreateTestAPIRequest("oldest", now.Add(-2*time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+		CreateTestAPIRequest("newest", now, "claude-3-sonnet-20240229", 200, 100, 0.02),
+	} {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	count, err := repo.CountRequests()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 requests, got %d", count)
+	}
+
+	deletedCount, err := repo.DeleteOldestBeyond(1)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if deletedCount != 2 {
+		t.Errorf("Expected 2 deleted records, got %d", deletedCount)
+	}
+
+	remaining, _ := repo.FindAll()
+	if len(remaining) != 1 || remaining[0].SessionID() != "newest" {
+		t.Errorf("Expected only 'newest' to remain, got %v", remaining)
+	}
+}
@@ -2,14 +2,27 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
 )
 
+// ErrDuplicateRequest is returned when a request repeats one already stored within the dedup window
+var ErrDuplicateRequest = errors.New("duplicate request")
+
+// ErrIngestionPaused is returned while storing requests is paused for maintenance
+var ErrIngestionPaused = errors.New("ingestion is paused")
+
 // AppendApiRequestCommand handles the command to append a new API request
 type AppendApiRequestCommand struct {
-	repository APIRequestRepository
+	repository  APIRequestRepository
+	dedupWindow time.Duration // 0 disables deduplication
+
+	writing sync.RWMutex // Held for reading while requests are stored
+	paused  bool
 }
 
 // NewAppendApiRequestCommand creates a new AppendApiRequestCommand with the given repository
@@ -19,6 +32,28 @@ func NewAppendApiRequestCommand(repository APIRequestRepository) *AppendApiReque
 	}
 }
 
+// SetDedupWindow treats a request as a duplicate when the same session already has a request of the
+// same model, tokens and cost at most window apart, e.g. when an exporter retries a batch. Requests
+// further apart or with other usage are distinct calls and are kept. A window of 0 disables deduplication.
+func (c *AppendApiRequestCommand) SetDedupWindow(window time.Duration) {
+	c.dedupWindow = window
+}
+
+// SetPaused stops or resumes storing requests, e.g. around a backup or compaction. Pausing waits
+// for the requests being stored to finish, so nothing is written by this command once it returns.
+func (c *AppendApiRequestCommand) SetPaused(paused bool) {
+	c.writing.Lock()
+	defer c.writing.Unlock()
+	c.paused = paused
+}
+
+// Paused reports whether storing requests is paused
+func (c *AppendApiRequestCommand) Paused() bool {
+	c.writing.RLock()
+	defer c.writing.RUnlock()
+	return c.paused
+}
+
 // AppendApiRequestParams contains the parameters for appending an API request
 type AppendApiRequestParams struct {
 	SessionID  string
@@ -27,10 +62,17 @@ type AppendApiRequestParams struct {
 	Tokens     entity.Token
 	Cost       entity.Cost
 	DurationMS int64
+	Labels     map[string]string // Optional extra attributes captured from the telemetry
 }
 
 // Execute executes the append API request command
 func (c *AppendApiRequestCommand) Execute(ctx context.Context, params AppendApiRequestParams) error {
+	c.writing.RLock()
+	defer c.writing.RUnlock()
+	if c.paused {
+		return ErrIngestionPaused
+	}
+
 	// Create the API request entity
 	apiRequest := entity.NewAPIRequest(
 		params.SessionID,
@@ -39,8 +81,129 @@ func (c *AppendApiRequestCommand) Execute(ctx context.Context, params AppendApiR
 		params.Tokens,
 		params.Cost,
 		params.DurationMS,
-	)
+	).WithLabels(params.Labels)
+
+	if c.dedupWindow > 0 {
+		duplicate, err := c.isDuplicate(apiRequest)
+		if err != nil {
+			return err
+		}
+		if duplicate {
+			return ErrDuplicateRequest
+		}
+	}
 
 	// Save the API request via repository
 	return c.repository.Save(apiRequest)
 }
+
+// isDuplicate reports whether a request of the same session, model and usage is stored within the dedup window
+func (c *AppendApiRequestCommand) isDuplicate(apiRequest entity.APIRequest) (bool, error) {
+	timestamp := apiRequest.Timestamp()
+	period := entity.NewPeriod(timestamp.Add(-c.dedupWindow), timestamp.Add(c.dedupWindow))
+	nearby, err := c.repository.FindByPeriodWithLimit(period, 0, 0)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up nearby requests: %w", err)
+	}
+
+	return c.duplicates(apiRequest, nearby), nil
+}
+
+// duplicates reports whether one of candidates has the session, model, tokens and cost of apiRequest
+// within the dedup window. Calls of one session made within the window differ in their usage.
+func (c *AppendApiRequestCommand) duplicates(apiRequest entity.APIRequest, candidates []entity.APIRequest) bool {
+	for _, req := range candidates {
+		if req.SessionID() != apiRequest.SessionID() || req.Model() != apiRequest.Model() {
+			continue
+		}
+		if req.Tokens() != apiRequest.Tokens() || req.Cost().Amount() != apiRequest.Cost().Amount() {
+			continue
+		}
+		if gap := req.Timestamp().Sub(apiRequest.Timestamp()).Abs(); gap <= c.dedupWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkAppendFailure identifies a request of a batch that was not stored
+type BulkAppendFailure struct {
+	Index  int    // Position of the request in the batch
+	Reason string // Why the request was rejected
+}
+
+// BulkAppendResult reports the outcome of appending a batch of requests
+type BulkAppendResult struct {
+	Saved    int                 // Requests stored
+	Skipped  int                 // Requests already stored or repeated within the batch
+	Failures []BulkAppendFailure // Requests rejected as invalid, in batch order
+}
+
+// ExecuteBatch appends a batch of requests in a single repository write. Requests already stored
+// under the same ID, duplicates within the dedup window and repeats within the batch are skipped,
+// so a client can resend the whole batch after a failed call. Invalid requests are reported as
+// failures without rejecting the rest; an error means nothing of the batch was stored.
+func (c *AppendApiRequestCommand) ExecuteBatch(ctx context.Context, params []AppendApiRequestParams) (BulkAppendResult, error) {
+	c.writing.RLock()
+	defer c.writing.RUnlock()
+	if c.paused {
+		return BulkAppendResult{}, ErrIngestionPaused
+	}
+
+	var result BulkAppendResult
+	requests := make([]entity.APIRequest, 0, len(params))
+	for i, p := range params {
+		switch {
+		case p.Timestamp.IsZero():
+			result.Failures = append(result.Failures, BulkAppendFailure{Index: i, Reason: "timestamp is required"})
+			continue
+		case p.Model == "":
+			result.Failures = append(result.Failures, BulkAppendFailure{Index: i, Reason: "model is required"})
+			continue
+		}
+		requests = append(requests, entity.NewAPIRequest(p.SessionID, p.Timestamp, p.Model, p.Tokens, p.Cost, p.DurationMS).WithLabels(p.Labels))
+	}
+	if len(requests) == 0 {
+		return result, nil
+	}
+
+	// A single lookup spanning the batch replaces one per request
+	start, end := requests[0].Timestamp(), requests[0].Timestamp()
+	for _, req := range requests[1:] {
+		if req.Timestamp().Before(start) {
+			start = req.Timestamp()
+		}
+		if req.Timestamp().After(end) {
+			end = req.Timestamp()
+		}
+	}
+	stored, err := c.repository.FindByPeriodWithLimit(entity.NewPeriod(start.Add(-c.dedupWindow), end.Add(c.dedupWindow)), 0, 0)
+	if err != nil {
+		return result, fmt.Errorf("failed to look up stored requests: %w", err)
+	}
+
+	// Compared by the key requests are stored under, like a replay
+	seen := make(map[string]bool, len(stored)+len(requests))
+	for _, req := range stored {
+		seen[replayID(req)] = true
+	}
+
+	batch := make([]entity.APIRequest, 0, len(requests))
+	for _, req := range requests {
+		if seen[replayID(req)] || (c.dedupWindow > 0 && (c.duplicates(req, stored) || c.duplicates(req, batch))) {
+			result.Skipped++
+			continue
+		}
+		seen[replayID(req)] = true
+		batch = append(batch, req)
+	}
+	if len(batch) == 0 {
+		return result, nil
+	}
+
+	if err := c.repository.SaveBatch(batch); err != nil {
+		return result, err
+	}
+	result.Saved = len(batch)
+	return result, nil
+}
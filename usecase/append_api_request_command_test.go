@@ -0,0 +1,274 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestAppendApiRequestCommand_DedupWindow(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	model := "claude-3-5-sonnet-20241022"
+
+	tests := []struct {
+		name          string
+		window        time.Duration
+		params        usecase.AppendApiRequestParams
+		wantDuplicate bool
+	}{
+		{
+			name:          "same session and model just inside the window",
+			window:        time.Second,
+			params:        usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: base.Add(999 * time.Millisecond), Model: model},
+			wantDuplicate: true,
+		},
+		{
+			name:          "same session and model exactly at the window",
+			window:        time.Second,
+			params:        usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: base.Add(-time.Second), Model: model},
+			wantDuplicate: true,
+		},
+		{
+			name:   "same session and model just outside the window",
+			window: time.Second,
+			params: usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: base.Add(time.Second + time.Millisecond), Model: model},
+		},
+		{
+			name:   "same session with another model",
+			window: time.Second,
+			params: usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: base.Add(100 * time.Millisecond), Model: "claude-3-5-haiku-20241022"},
+		},
+		{
+			name:   "another session with the same model",
+			window: time.Second,
+			params: usecase.AppendApiRequestParams{SessionID: "session-b", Timestamp: base.Add(100 * time.Millisecond), Model: model},
+		},
+		{
+			name:   "same session and model with other tokens",
+			window: time.Second,
+			params: usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: base.Add(100 * time.Millisecond), Model: model, Tokens: entity.NewToken(200, 80, 0, 0)},
+		},
+		{
+			name:   "same session and model with another cost",
+			window: time.Second,
+			params: usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: base.Add(100 * time.Millisecond), Model: model, Cost: entity.NewCost(0.02)},
+		},
+		{
+			name:   "zero window keeps every request",
+			window: 0,
+			params: usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: base.Add(100 * time.Millisecond), Model: model},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-a", base, model, 100, 50, 0.01),
+			})
+
+			command := usecase.NewAppendApiRequestCommand(repo)
+			command.SetDedupWindow(tt.window)
+			// Cases leave out the usage that matches the stored request
+			if tt.params.Tokens == (entity.Token{}) {
+				tt.params.Tokens = entity.NewToken(100, 50, 0, 0)
+			}
+			if tt.params.Cost.Amount() == 0 {
+				tt.params.Cost = entity.NewCost(0.01)
+			}
+
+			err := command.Execute(context.Background(), tt.params)
+			if tt.wantDuplicate {
+				if !errors.Is(err, usecase.ErrDuplicateRequest) {
+					t.Fatalf("Expected ErrDuplicateRequest, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			requests, err := repo.FindAll()
+			if err != nil {
+				t.Fatalf("FindAll failed: %v", err)
+			}
+			expected := 2
+			if tt.wantDuplicate {
+				expected = 1
+			}
+			if len(requests) != expected {
+				t.Errorf("Expected %d stored requests, got %d", expected, len(requests))
+			}
+		})
+	}
+}
+
+func TestAppendApiRequestCommand_ExecuteBatch(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	model := "claude-3-5-sonnet-20241022"
+
+	tests := []struct {
+		name         string
+		window       time.Duration
+		params       []usecase.AppendApiRequestParams
+		wantSaved    int
+		wantSkipped  int
+		wantFailures []int
+	}{
+		{
+			name:   "new requests are saved",
+			window: time.Second,
+			params: []usecase.AppendApiRequestParams{
+				{SessionID: "session-b", Timestamp: base, Model: model},
+				{SessionID: "session-b", Timestamp: base.Add(time.Minute), Model: model},
+			},
+			wantSaved: 2,
+		},
+		{
+			name:   "requests already stored are skipped even without a dedup window",
+			window: 0,
+			params: []usecase.AppendApiRequestParams{
+				{SessionID: "session-a", Timestamp: base, Model: model},
+				{SessionID: "session-b", Timestamp: base, Model: model},
+			},
+			wantSaved:   1,
+			wantSkipped: 1,
+		},
+		{
+			name:   "duplicates within the window and within the batch are skipped",
+			window: time.Second,
+			params: []usecase.AppendApiRequestParams{
+				{SessionID: "session-a", Timestamp: base.Add(500 * time.Millisecond), Model: model, Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+				{SessionID: "session-b", Timestamp: base, Model: model},
+				{SessionID: "session-b", Timestamp: base.Add(200 * time.Millisecond), Model: model},
+			},
+			wantSaved:   1,
+			wantSkipped: 2,
+		},
+		{
+			name:   "calls within the window with other usage are saved",
+			window: time.Second,
+			params: []usecase.AppendApiRequestParams{
+				{SessionID: "session-a", Timestamp: base.Add(500 * time.Millisecond), Model: model, Tokens: entity.NewToken(300, 20, 0, 0), Cost: entity.NewCost(0.01)},
+				{SessionID: "session-b", Timestamp: base, Model: model, Tokens: entity.NewToken(10, 5, 0, 0)},
+				{SessionID: "session-b", Timestamp: base.Add(200 * time.Millisecond), Model: model, Tokens: entity.NewToken(20, 5, 0, 0)},
+			},
+			wantSaved: 3,
+		},
+		{
+			name:   "invalid requests are reported by position",
+			window: time.Second,
+			params: []usecase.AppendApiRequestParams{
+				{SessionID: "session-b", Model: model},
+				{SessionID: "session-b", Timestamp: base, Model: model},
+				{SessionID: "session-c", Timestamp: base},
+			},
+			wantSaved:    1,
+			wantFailures: []int{0, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-a", base, model, 100, 50, 0.01),
+			})
+
+			command := usecase.NewAppendApiRequestCommand(repo)
+			command.SetDedupWindow(tt.window)
+
+			result, err := command.ExecuteBatch(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Saved != tt.wantSaved || result.Skipped != tt.wantSkipped {
+				t.Errorf("Expected %d saved and %d skipped, got %d and %d", tt.wantSaved, tt.wantSkipped, result.Saved, result.Skipped)
+			}
+			if len(result.Failures) != len(tt.wantFailures) {
+				t.Fatalf("Expected %d failures, got %v", len(tt.wantFailures), result.Failures)
+			}
+			for i, failure := range result.Failures {
+				if failure.Index != tt.wantFailures[i] || failure.Reason == "" {
+					t.Errorf("Failure %d: expected index %d with a reason, got %+v", i, tt.wantFailures[i], failure)
+				}
+			}
+
+			requests, err := repo.FindAll()
+			if err != nil {
+				t.Fatalf("FindAll failed: %v", err)
+			}
+			if len(requests) != 1+tt.wantSaved {
+				t.Errorf("Expected %d stored requests, got %d", 1+tt.wantSaved, len(requests))
+			}
+		})
+	}
+}
+
+func TestAppendApiRequestCommand_ExecuteBatchRetry(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	params := []usecase.AppendApiRequestParams{
+		{SessionID: "session-a", Timestamp: base, Model: "claude-3-5-sonnet-20241022"},
+		{SessionID: "session-a", Timestamp: base.Add(time.Minute), Model: "claude-3-5-sonnet-20241022"},
+	}
+
+	repo := testutil.NewMockAPIRequestRepository()
+	command := usecase.NewAppendApiRequestCommand(repo)
+
+	repo.SetError(errors.New("database is locked"))
+	if _, err := command.ExecuteBatch(context.Background(), params); err == nil {
+		t.Fatal("Expected an error while the repository fails")
+	}
+
+	// Resending the whole batch stores it once, then skips it
+	repo.SetError(nil)
+	for _, expected := range []usecase.BulkAppendResult{{Saved: 2}, {Skipped: 2}} {
+		result, err := command.ExecuteBatch(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Saved != expected.Saved || result.Skipped != expected.Skipped {
+			t.Errorf("Expected %d saved and %d skipped, got %d and %d", expected.Saved, expected.Skipped, result.Saved, result.Skipped)
+		}
+	}
+}
+
+func TestAppendApiRequestCommand_Paused(t *testing.T) {
+	t.Parallel()
+
+	repo := testutil.NewMockAPIRequestRepository()
+	command := usecase.NewAppendApiRequestCommand(repo)
+	params := usecase.AppendApiRequestParams{SessionID: "session-a", Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Model: "claude-3-5-sonnet-20241022"}
+
+	command.SetPaused(true)
+	if !command.Paused() {
+		t.Fatal("Expected the command to be paused")
+	}
+	if err := command.Execute(context.Background(), params); !errors.Is(err, usecase.ErrIngestionPaused) {
+		t.Errorf("Expected ErrIngestionPaused from Execute, got %v", err)
+	}
+	if _, err := command.ExecuteBatch(context.Background(), []usecase.AppendApiRequestParams{params}); !errors.Is(err, usecase.ErrIngestionPaused) {
+		t.Errorf("Expected ErrIngestionPaused from ExecuteBatch, got %v", err)
+	}
+
+	command.SetPaused(false)
+	if err := command.Execute(context.Background(), params); err != nil {
+		t.Fatalf("Unexpected error after resuming: %v", err)
+	}
+	if requests, _ := repo.FindAll(); len(requests) != 1 {
+		t.Errorf("Expected 1 stored request, got %d", len(requests))
+	}
+}
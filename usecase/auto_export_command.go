@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// AutoExportCommand exports the requests of each completed day or month to an archive, once per
+// period, so stored usage is kept after retention deletes it. Periods missed while the server was
+// down are backfilled, back to the last archived one or the oldest stored request.
+type AutoExportCommand struct {
+	getFilteredQuery *GetFilteredApiRequestsQuery
+	storageInfoQuery *GetStorageInfoQuery
+	archive          ExportArchive
+	interval         entity.ExportInterval
+	timezone         *time.Location
+	clock            entity.Clock
+}
+
+// NewAutoExportCommand creates a new AutoExportCommand exporting each interval, following the calendar of the timezone
+func NewAutoExportCommand(getFilteredQuery *GetFilteredApiRequestsQuery, storageInfoQuery *GetStorageInfoQuery, archive ExportArchive, interval entity.ExportInterval, timezone *time.Location) *AutoExportCommand {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+	return &AutoExportCommand{
+		getFilteredQuery: getFilteredQuery,
+		storageInfoQuery: storageInfoQuery,
+		archive:          archive,
+		interval:         interval,
+		timezone:         timezone,
+		clock:            entity.SystemClock{},
+	}
+}
+
+// SetClock changes the source of the current time used to find the last completed period
+func (c *AutoExportCommand) SetClock(clock entity.Clock) {
+	c.clock = clock
+}
+
+// AutoExportResult describes the export of a single period
+type AutoExportResult struct {
+	Name     string        // Export name, the date of the exported period
+	Period   entity.Period // Exported period
+	Exported int           // Number of exported requests
+	Skipped  bool          // True when the period was exported before
+}
+
+// Execute exports every completed period the archive does not have yet, oldest first. When the last
+// completed period is already archived, a single skipped result is returned. On failure, the periods
+// exported so far are returned with the error, and the rest are retried on the next run.
+func (c *AutoExportCommand) Execute(ctx context.Context) ([]AutoExportResult, error) {
+	pending, err := c.pendingPeriods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		period := c.interval.Previous(c.clock.Now(), c.timezone)
+		return []AutoExportResult{{Name: c.interval.Stamp(period.StartAt(), c.timezone), Period: period, Skipped: true}}, nil
+	}
+
+	results := make([]AutoExportResult, 0, len(pending))
+	for i := len(pending) - 1; i >= 0; i-- {
+		result, err := c.export(ctx, pending[i])
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// pendingPeriods walks back from the last completed period, newest first, until a period is archived
+// or ends before the oldest stored request. The last completed period is exported even when empty.
+func (c *AutoExportCommand) pendingPeriods(ctx context.Context) ([]entity.Period, error) {
+	info, err := c.storageInfoQuery.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []entity.Period
+	period := c.interval.Previous(c.clock.Now(), c.timezone)
+	for {
+		name := c.interval.Stamp(period.StartAt(), c.timezone)
+		exists, err := c.archive.Exists(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check export %s: %w", name, err)
+		}
+		if exists {
+			return pending, nil
+		}
+		if len(pending) > 0 && (info.IsEmpty() || period.EndAt().Before(info.OldestAt())) {
+			return pending, nil
+		}
+
+		pending = append(pending, period)
+		period = c.interval.Previous(period.StartAt(), c.timezone)
+	}
+}
+
+// export writes the requests of the period to the archive
+func (c *AutoExportCommand) export(ctx context.Context, period entity.Period) (AutoExportResult, error) {
+	result := AutoExportResult{
+		Name:   c.interval.Stamp(period.StartAt(), c.timezone),
+		Period: period,
+	}
+
+	requests, err := c.getFilteredQuery.Execute(ctx, GetFilteredApiRequestsParams{Period: period})
+	if err != nil {
+		return result, err
+	}
+
+	if err := c.archive.Write(result.Name, requests); err != nil {
+		return result, fmt.Errorf("failed to write export %s: %w", result.Name, err)
+	}
+
+	result.Exported = len(requests)
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// fakeExportArchive keeps the written exports in memory
+type fakeExportArchive struct {
+	exports map[string][]entity.APIRequest
+	err     error
+}
+
+func (a *fakeExportArchive) Exists(name string) (bool, error) {
+	_, ok := a.exports[name]
+	return ok, nil
+}
+
+func (a *fakeExportArchive) Write(name string, requests []entity.APIRequest) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.exports[name] = requests
+	return nil
+}
+
+func TestAutoExportCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC)
+	mockRepo, _ := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 1.0),
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 2.0),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 100, 50, 0.5),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 7, 2, 7, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 4.0),
+	})
+
+	storageInfoQuery := usecase.NewGetStorageInfoQuery(&fakeStorageInfoRepository{
+		info: entity.NewStorageInfo(4096, 4, time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC), time.Date(2025, 7, 2, 7, 0, 0, 0, time.UTC)),
+	})
+
+	tests := []struct {
+		name         string
+		interval     entity.ExportInterval
+		existing     []string
+		emptyStore   bool
+		writeErr     error
+		wantNames    []string
+		wantExported []int
+		wantSkipped  bool
+		wantErr      bool
+	}{
+		{name: "backfills back to the oldest request", interval: entity.ExportIntervalDay, wantNames: []string{"2025-06-30", "2025-07-01"}, wantExported: []int{1, 2}},
+		{name: "backfills back to the last archived day", interval: entity.ExportIntervalDay, existing: []string{"2025-06-30"}, wantNames: []string{"2025-07-01"}, wantExported: []int{2}},
+		{name: "empty store exports only the last day", interval: entity.ExportIntervalDay, emptyStore: true, wantNames: []string{"2025-07-01"}, wantExported: []int{2}},
+		{name: "exports last month", interval: entity.ExportIntervalMonth, wantNames: []string{"2025-06"}, wantExported: []int{1}},
+		{name: "skips an exported period", interval: entity.ExportIntervalDay, existing: []string{"2025-07-01"}, wantNames: []string{"2025-07-01"}, wantExported: []int{0}, wantSkipped: true},
+		{name: "write failure", interval: entity.ExportIntervalDay, writeErr: errors.New("disk full"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			archive := &fakeExportArchive{exports: map[string][]entity.APIRequest{}, err: tt.writeErr}
+			for _, name := range tt.existing {
+				archive.exports[name] = nil
+			}
+
+			infoQuery := storageInfoQuery
+			if tt.emptyStore {
+				infoQuery = usecase.NewGetStorageInfoQuery(&fakeStorageInfoRepository{})
+			}
+
+			command := usecase.NewAutoExportCommand(usecase.NewGetFilteredApiRequestsQuery(mockRepo), infoQuery, archive, tt.interval, time.UTC)
+			command.SetClock(entity.NewFixedClock(now))
+
+			results, err := command.Execute(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(results) != len(tt.wantNames) {
+				t.Fatalf("Expected %d results, got %+v", len(tt.wantNames), results)
+			}
+			for i, result := range results {
+				if result.Name != tt.wantNames[i] {
+					t.Errorf("Expected name %q, got %q", tt.wantNames[i], result.Name)
+				}
+				if result.Skipped != tt.wantSkipped {
+					t.Errorf("Expected skipped %v, got %v", tt.wantSkipped, result.Skipped)
+				}
+				if result.Exported != tt.wantExported[i] {
+					t.Errorf("Expected %d exported requests for %s, got %d", tt.wantExported[i], result.Name, result.Exported)
+				}
+				if !tt.wantSkipped && len(archive.exports[result.Name]) != tt.wantExported[i] {
+					t.Errorf("Expected %d archived requests for %s, got %d", tt.wantExported[i], result.Name, len(archive.exports[result.Name]))
+				}
+			}
+		})
+	}
+}
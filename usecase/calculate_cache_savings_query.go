@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// CalculateCacheSavingsQuery estimates how much cache reads saved compared to fresh input tokens
+type CalculateCacheSavingsQuery struct {
+	getFilteredQuery *GetFilteredApiRequestsQuery
+	rates            entity.RateTable
+}
+
+// NewCalculateCacheSavingsQuery creates a new CalculateCacheSavingsQuery with the configured rates
+func NewCalculateCacheSavingsQuery(getFilteredQuery *GetFilteredApiRequestsQuery, rates entity.RateTable) *CalculateCacheSavingsQuery {
+	return &CalculateCacheSavingsQuery{
+		getFilteredQuery: getFilteredQuery,
+		rates:            rates,
+	}
+}
+
+// CalculateCacheSavingsParams contains the parameters for the cache savings query
+type CalculateCacheSavingsParams struct {
+	Period entity.Period
+}
+
+// CacheSavings contains the estimated cache savings for a period
+type CacheSavings struct {
+	Amount    entity.Cost
+	Available bool // False when rates are missing, so the amount must not be shown
+}
+
+// IsEnabled returns true if any rate is configured
+func (q *CalculateCacheSavingsQuery) IsEnabled() bool {
+	return !q.rates.IsEmpty()
+}
+
+// Execute calculates the cache savings of the requests within the period
+func (q *CalculateCacheSavingsQuery) Execute(ctx context.Context, params CalculateCacheSavingsParams) (CacheSavings, error) {
+	// Skip the request scan entirely when there is nothing to price
+	if !q.IsEnabled() {
+		return CacheSavings{Amount: entity.NewCost(0)}, nil
+	}
+
+	requests, err := q.getFilteredQuery.Execute(ctx, GetFilteredApiRequestsParams{Period: params.Period})
+	if err != nil {
+		return CacheSavings{}, err
+	}
+
+	amount, available := q.rates.CacheSavings(requests)
+	return CacheSavings{Amount: amount, Available: available}, nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/elct9620/ccmon/entity"
 )
@@ -10,6 +11,9 @@ import (
 type CalculateStatsQuery struct {
 	statsRepository StatsRepository
 	cache           StatsCache
+	asOf            time.Time
+	negligibleCost  entity.Cost // Requests cheaper than the threshold are counted in their own bucket
+	tierRepository  ModelTierRepository
 }
 
 // NewCalculateStatsQuery creates a new CalculateStatsQuery with the given stats repository and cache
@@ -20,23 +24,80 @@ func NewCalculateStatsQuery(statsRepository StatsRepository, cache StatsCache) *
 	}
 }
 
+// SetAsOf leaves out requests after the given time from every query, to reproduce the stats as they
+// were then; the zero time keeps all requests
+func (q *CalculateStatsQuery) SetAsOf(asOf time.Time) {
+	q.asOf = asOf
+}
+
+// SetNegligibleCost counts the requests cheaper than the threshold in the negligible bucket of
+// the stats; a non-positive threshold disables it
+func (q *CalculateStatsQuery) SetNegligibleCost(threshold entity.Cost) {
+	q.negligibleCost = threshold
+}
+
+// SetModelTierRepository aggregates every query with the current model tier overrides of the
+// repository, so a reassigned model is reflected without refetching the requests
+func (q *CalculateStatsQuery) SetModelTierRepository(tierRepository ModelTierRepository) {
+	q.tierRepository = tierRepository
+}
+
 // CalculateStatsParams contains the parameters for calculating statistics
 type CalculateStatsParams struct {
-	Period entity.Period
+	Period         entity.Period
+	AsOf           time.Time              // Optional, clips the period end to this time instead of the query's as-of time
+	NegligibleCost entity.Cost            // Optional, replaces the query's negligible cost threshold when positive
+	Classifier     entity.ModelClassifier // Optional, replaces the tier repository's overrides when it has any
 }
 
 // Execute executes the calculate statistics query
 func (q *CalculateStatsQuery) Execute(ctx context.Context, params CalculateStatsParams) (entity.Stats, error) {
-	if cachedStats := q.cache.Get(params.Period); cachedStats != nil {
+	asOf := params.AsOf
+	if asOf.IsZero() {
+		asOf = q.asOf
+	}
+	// An as-of time after the period end, e.g. in the future, leaves the period unchanged
+	params.Period = params.Period.ClipEnd(asOf)
+
+	negligibleCost := params.NegligibleCost
+	if negligibleCost.Amount() <= 0 {
+		negligibleCost = q.negligibleCost
+	}
+	classifier := params.Classifier
+	if !classifier.HasOverrides() && q.tierRepository != nil {
+		classifier = q.tierRepository.GetClassifier()
+	}
+	query := entity.NewStatsQuery(params.Period).WithNegligibleCost(negligibleCost).WithClassifier(classifier)
+
+	if cachedStats := q.cache.Get(query); cachedStats != nil {
 		return *cachedStats, nil
 	}
 
-	stats, err := q.statsRepository.GetStatsByPeriod(params.Period)
+	stats, err := q.statsRepository.GetStats(query)
 	if err != nil {
-		return entity.Stats{}, err
+		return entity.Stats{}, repositoryError(err)
 	}
 
-	q.cache.Set(params.Period, &stats)
+	q.cache.Set(query, &stats)
 
 	return stats, nil
 }
+
+// InvalidateCache drops the cached stats when the cache supports it, so new data
+// shows up before the cached entries expire
+func (q *CalculateStatsQuery) InvalidateCache() {
+	if cache, ok := q.cache.(interface{ Invalidate() }); ok {
+		cache.Invalidate()
+	}
+}
+
+// InvalidateCachePeriod drops the cached stats of periods overlapping the given one, e.g. the span
+// of newly stored requests, so other periods keep their cached entries. Caches that cannot drop
+// single periods are invalidated entirely.
+func (q *CalculateStatsQuery) InvalidateCachePeriod(period entity.Period) {
+	if cache, ok := q.cache.(interface{ InvalidatePeriod(entity.Period) }); ok {
+		cache.InvalidatePeriod(period)
+		return
+	}
+	q.InvalidateCache()
+}
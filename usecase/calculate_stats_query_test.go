@@ -137,7 +137,7 @@ func TestCalculateStatsQuery_Execute(t *testing.T) {
 				return tt.repositoryData, nil
 			})
 
-			mockCache := testutil.NewMockStatsCacheWithData(func(p entity.Period) *entity.Stats {
+			mockCache := testutil.NewMockStatsCacheWithData(func(q entity.StatsQuery) *entity.Stats {
 				return tt.cacheGet
 			})
 
@@ -178,3 +178,125 @@ func TestCalculateStatsQuery_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateStatsQuery_AsOf(t *testing.T) {
+	monthStart := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	month := entity.NewPeriod(monthStart, monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond))
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 1.0),
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 2.0),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 4.0),
+	})
+
+	tests := []struct {
+		name         string
+		queryAsOf    time.Time
+		paramsAsOf   time.Time
+		wantRequests int
+		wantCost     float64
+	}{
+		{name: "unset counts every request", wantRequests: 3, wantCost: 7.0},
+		{name: "query as-of leaves out later requests", queryAsOf: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC), wantRequests: 2, wantCost: 3.0},
+		{name: "params override the query as-of", queryAsOf: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC), paramsAsOf: time.Date(2025, 7, 11, 0, 0, 0, 0, time.UTC), wantRequests: 1, wantCost: 1.0},
+		{name: "future as-of behaves as now", queryAsOf: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), wantRequests: 3, wantCost: 7.0},
+		{name: "as-of before the period matches nothing", paramsAsOf: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), wantRequests: 0, wantCost: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache())
+			query.SetAsOf(tt.queryAsOf)
+
+			stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: month, AsOf: tt.paramsAsOf})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stats.TotalRequests() != tt.wantRequests {
+				t.Errorf("Expected %d requests, got %d", tt.wantRequests, stats.TotalRequests())
+			}
+			if stats.TotalCost().Amount() != tt.wantCost {
+				t.Errorf("Expected cost %f, got %f", tt.wantCost, stats.TotalCost().Amount())
+			}
+		})
+	}
+}
+
+func TestCalculateStatsQuery_NegligibleCost(t *testing.T) {
+	monthStart := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	month := entity.NewPeriod(monthStart, monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond))
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC), "claude-3-haiku-20240307", 100, 50, 0.25),
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 0.5),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 4.0),
+	})
+
+	tests := []struct {
+		name         string
+		threshold    float64
+		wantRequests int
+		wantCost     float64
+	}{
+		{name: "zero threshold leaves the bucket empty", threshold: 0, wantRequests: 0, wantCost: 0},
+		{name: "cheap requests across tiers", threshold: 1.0, wantRequests: 2, wantCost: 0.75},
+		{name: "threshold at a request cost keeps it out", threshold: 0.5, wantRequests: 1, wantCost: 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache())
+			query.SetNegligibleCost(entity.NewCost(tt.threshold))
+
+			stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: month})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stats.NegligibleRequests() != tt.wantRequests {
+				t.Errorf("Expected %d negligible requests, got %d", tt.wantRequests, stats.NegligibleRequests())
+			}
+			if stats.NegligibleCost().Amount() != tt.wantCost {
+				t.Errorf("Expected negligible cost %f, got %f", tt.wantCost, stats.NegligibleCost().Amount())
+			}
+			if stats.TotalRequests() != 3 {
+				t.Errorf("Expected the totals to keep all 3 requests, got %d", stats.TotalRequests())
+			}
+		})
+	}
+}
+
+func TestCalculateStatsQuery_ModelTiers(t *testing.T) {
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	period := entity.NewPeriod(now.Add(-time.Hour), now)
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-30*time.Minute), "claude-3-haiku-20240307", 100, 50, 0.1),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-20*time.Minute), "local-llama", 100, 50, 0.2),
+	})
+	tierRepo := testutil.NewMockModelTierRepository(entity.NewModelClassifier(map[string]entity.ModelTier{"local-llama": entity.ModelTierBase}))
+
+	query := NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache())
+	query.SetModelTierRepository(tierRepo)
+
+	tests := []struct {
+		name     string
+		params   CalculateStatsParams
+		wantBase int
+	}{
+		{name: "repository overrides", params: CalculateStatsParams{Period: period}, wantBase: 2},
+		{
+			name:     "params overrides replace the repository's",
+			params:   CalculateStatsParams{Period: period, Classifier: entity.NewModelClassifier(map[string]entity.ModelTier{"claude-3-haiku-20240307": entity.ModelTierPremium})},
+			wantBase: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := query.Execute(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stats.BaseRequests() != tt.wantBase {
+				t.Errorf("Expected %d base requests, got %d", tt.wantBase, stats.BaseRequests())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// CountSessionsQuery handles the query to count distinct sessions within a period
+type CountSessionsQuery struct {
+	repository     APIRequestRepository
+	statsQuery     *CalculateStatsQuery
+	includeUnknown bool
+	asOf           time.Time
+}
+
+// NewCountSessionsQuery creates a new CountSessionsQuery with the given repository.
+// When includeUnknown is true, requests without a session ID are counted as a single "unknown" session.
+func NewCountSessionsQuery(repository APIRequestRepository, includeUnknown bool) *CountSessionsQuery {
+	return &CountSessionsQuery{
+		repository:     repository,
+		includeUnknown: includeUnknown,
+	}
+}
+
+// SetStatsQuery reads the session count from the period stats, which are cached and counted by the
+// stats repository, instead of fetching every request of the period on each call
+func (q *CountSessionsQuery) SetStatsQuery(statsQuery *CalculateStatsQuery) {
+	q.statsQuery = statsQuery
+}
+
+// SetAsOf leaves out requests after the given time, to count the sessions as they were then;
+// the zero time keeps all requests
+func (q *CountSessionsQuery) SetAsOf(asOf time.Time) {
+	q.asOf = asOf
+}
+
+// CountSessionsParams contains the parameters for counting sessions
+type CountSessionsParams struct {
+	Period entity.Period
+}
+
+// Execute executes the count sessions query
+func (q *CountSessionsQuery) Execute(ctx context.Context, params CountSessionsParams) (int, error) {
+	if q.statsQuery != nil {
+		stats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{Period: params.Period})
+		if err != nil {
+			return 0, err
+		}
+		// Every request belongs to a known or the unknown session, so stats with requests but
+		// neither come from a server that predates session counts
+		if stats.TotalRequests() == 0 || stats.Sessions(true) > 0 {
+			return stats.Sessions(q.includeUnknown), nil
+		}
+	}
+
+	requests, err := q.repository.FindByPeriodWithLimit(params.Period.ClipEnd(q.asOf), 0, 0)
+	if err != nil {
+		return 0, repositoryError(err)
+	}
+
+	return entity.CountDistinctSessions(requests, q.includeUnknown), nil
+}
@@ -0,0 +1,164 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestCountSessionsQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-a", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-a", now.Add(-30*time.Minute), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-b", now.Add(-20*time.Minute), "claude-3-haiku-20240307", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("", now.Add(-5*time.Minute), "claude-3-haiku-20240307", 100, 50, 0.01),
+	}
+
+	tests := []struct {
+		name           string
+		requests       []entity.APIRequest
+		repoErr        error
+		includeUnknown bool
+		expected       int
+		expectError    bool
+	}{
+		{
+			name:           "counts unknown bucket when included",
+			requests:       requests,
+			includeUnknown: true,
+			expected:       3,
+		},
+		{
+			name:           "excludes empty session IDs",
+			requests:       requests,
+			includeUnknown: false,
+			expected:       2,
+		},
+		{
+			name:           "no requests in period",
+			requests:       []entity.APIRequest{},
+			includeUnknown: true,
+			expected:       0,
+		},
+		{
+			name:           "repository error",
+			repoErr:        &testutil.MockError{Message: "database connection failed"},
+			includeUnknown: true,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(tt.requests)
+			if tt.repoErr != nil {
+				mockRepo.SetError(tt.repoErr)
+			}
+
+			query := usecase.NewCountSessionsQuery(mockRepo, tt.includeUnknown)
+
+			count, err := query.Execute(context.Background(), usecase.CountSessionsParams{Period: period})
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("expected %d sessions, got %d", tt.expected, count)
+			}
+		})
+	}
+}
+
+// fixedStatsRepository returns the same stats for every query
+type fixedStatsRepository struct {
+	stats entity.Stats
+}
+
+func (r *fixedStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	return r.stats, nil
+}
+
+func TestCountSessionsQuery_StatsQuery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	stats := entity.NewStats(3, 0, entity.Token{}, entity.Token{}, entity.Cost{}, entity.Cost{}, period)
+
+	tests := []struct {
+		name           string
+		stats          entity.Stats
+		includeUnknown bool
+		expected       int
+	}{
+		{name: "known sessions only", stats: stats.WithSessions(2, true), includeUnknown: false, expected: 2},
+		{name: "unknown bucket included", stats: stats.WithSessions(2, true), includeUnknown: true, expected: 3},
+		{name: "server without session counts falls back to the requests", stats: stats, includeUnknown: true, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// The requests are only read when the stats cannot answer
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-a", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+			})
+
+			query := usecase.NewCountSessionsQuery(mockRepo, tt.includeUnknown)
+			query.SetStatsQuery(usecase.NewCalculateStatsQuery(&fixedStatsRepository{stats: tt.stats}, testutil.NewNoOpStatsCache()))
+
+			count, err := query.Execute(context.Background(), usecase.CountSessionsParams{Period: period})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("expected %d sessions, got %d", tt.expected, count)
+			}
+		})
+	}
+}
+
+func TestCountSessionsQuery_AsOf(t *testing.T) {
+	t.Parallel()
+
+	asOf := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	day := entity.NewPeriod(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 1, 23, 59, 59, 0, time.UTC))
+
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-a", asOf.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-b", asOf.Add(time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+	})
+
+	query := usecase.NewCountSessionsQuery(mockRepo, false)
+	query.SetAsOf(asOf)
+
+	count, err := query.Execute(context.Background(), usecase.CountSessionsParams{Period: day})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 session before the as-of time, got %d", count)
+	}
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// CreateBackupQuery writes a consistent snapshot of the stored requests
+type CreateBackupQuery struct {
+	snapshotRepository SnapshotRepository
+}
+
+// NewCreateBackupQuery creates a new CreateBackupQuery with the given repository
+func NewCreateBackupQuery(snapshotRepository SnapshotRepository) *CreateBackupQuery {
+	return &CreateBackupQuery{
+		snapshotRepository: snapshotRepository,
+	}
+}
+
+// CreateBackupParams contains the destination of the snapshot
+type CreateBackupParams struct {
+	Writer io.Writer
+}
+
+// BackupResult describes a written snapshot
+type BackupResult struct {
+	Records int
+}
+
+// Execute writes the snapshot to the destination
+func (q *CreateBackupQuery) Execute(ctx context.Context, params CreateBackupParams) (*BackupResult, error) {
+	if params.Writer == nil {
+		return nil, fmt.Errorf("backup destination is required")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	records, err := q.snapshotRepository.WriteSnapshot(params.Writer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return &BackupResult{Records: records}, nil
+}
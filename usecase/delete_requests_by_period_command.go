@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+var (
+	// ErrUnboundedPeriod is returned when a deletion period has no start time
+	ErrUnboundedPeriod = errors.New("delete period must have a start and end time")
+	// ErrInvalidConfirmationToken is returned when the confirmation token does not match the preview
+	ErrInvalidConfirmationToken = errors.New("invalid confirmation token")
+)
+
+// DeleteRequestsByPeriodCommand handles the command to delete API requests within a period.
+// Deletion is two-phase: a call without a confirmation token only previews the matched
+// records and returns the token required to confirm the deletion.
+type DeleteRequestsByPeriodCommand struct {
+	repository          APIRequestRepository
+	calculateStatsQuery *CalculateStatsQuery
+}
+
+// NewDeleteRequestsByPeriodCommand creates a new DeleteRequestsByPeriodCommand with the given repository
+func NewDeleteRequestsByPeriodCommand(repository APIRequestRepository) *DeleteRequestsByPeriodCommand {
+	return &DeleteRequestsByPeriodCommand{
+		repository: repository,
+	}
+}
+
+// SetCalculateStatsQuery sets the stats query whose cache is dropped after a confirmed deletion,
+// so stats no longer count the deleted records
+func (c *DeleteRequestsByPeriodCommand) SetCalculateStatsQuery(calculateStatsQuery *CalculateStatsQuery) {
+	c.calculateStatsQuery = calculateStatsQuery
+}
+
+// DeleteRequestsByPeriodParams contains the parameters for deleting records within a period
+type DeleteRequestsByPeriodParams struct {
+	Period            entity.Period
+	ConfirmationToken string // Empty to preview the deletion
+}
+
+// DeleteRequestsByPeriodResult contains the result of the delete operation
+type DeleteRequestsByPeriodResult struct {
+	MatchedCount      int
+	DeletedCount      int
+	ConfirmationToken string
+}
+
+// Execute executes the delete requests by period command
+func (c *DeleteRequestsByPeriodCommand) Execute(ctx context.Context, params DeleteRequestsByPeriodParams) (*DeleteRequestsByPeriodResult, error) {
+	// Refuse unbounded periods to prevent accidental mass deletion
+	if params.Period.IsAllTime() {
+		return nil, ErrUnboundedPeriod
+	}
+
+	matched, err := c.repository.FindByPeriodWithLimit(params.Period, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find requests in period: %w", err)
+	}
+
+	token := confirmationToken(params.Period, len(matched))
+	result := &DeleteRequestsByPeriodResult{
+		MatchedCount:      len(matched),
+		ConfirmationToken: token,
+	}
+
+	// Preview only
+	if params.ConfirmationToken == "" {
+		return result, nil
+	}
+
+	if params.ConfirmationToken != token {
+		return nil, ErrInvalidConfirmationToken
+	}
+
+	deletedCount, err := c.repository.DeleteByPeriod(params.Period)
+	if err != nil {
+		return nil, err
+	}
+	result.DeletedCount = deletedCount
+	if c.calculateStatsQuery != nil {
+		c.calculateStatsQuery.InvalidateCachePeriod(params.Period)
+	}
+
+	return result, nil
+}
+
+// confirmationToken derives a token from the period boundaries and matched count,
+// so a token is only valid for the exact preview it was issued for
+func confirmationToken(period entity.Period, matchedCount int) string {
+	payload := fmt.Sprintf("%s|%s|%d",
+		period.StartAt().UTC().Format(time.RFC3339Nano),
+		period.EndAt().UTC().Format(time.RFC3339Nano),
+		matchedCount,
+	)
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestDeleteRequestsByPeriodCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	period := entity.NewPeriod(base.Add(time.Hour), base.Add(3*time.Hour))
+
+	newRequests := func() []entity.APIRequest {
+		return []entity.APIRequest{
+			testutil.CreateTestAPIRequest("session-a", base, "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+			testutil.CreateTestAPIRequest("session-a", base.Add(time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+			testutil.CreateTestAPIRequest("session-b", base.Add(2*time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+			testutil.CreateTestAPIRequest("session-b", base.Add(4*time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+		}
+	}
+
+	// Preview once to learn the token issued for the period
+	previewRepo := testutil.NewMockAPIRequestRepository()
+	previewRepo.SetMockData(newRequests())
+	preview, err := usecase.NewDeleteRequestsByPeriodCommand(previewRepo).Execute(context.Background(), usecase.DeleteRequestsByPeriodParams{Period: period})
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		period            entity.Period
+		confirmationToken string
+		expectedMatched   int
+		expectedDeleted   int
+		expectedRemaining int
+		expectedErr       error
+	}{
+		{
+			name:              "preview without token does not delete",
+			period:            period,
+			expectedMatched:   2,
+			expectedDeleted:   0,
+			expectedRemaining: 4,
+		},
+		{
+			name:              "confirmed deletion removes matched records",
+			period:            period,
+			confirmationToken: preview.ConfirmationToken,
+			expectedMatched:   2,
+			expectedDeleted:   2,
+			expectedRemaining: 2,
+		},
+		{
+			name:              "mismatched token is rejected",
+			period:            period,
+			confirmationToken: "invalid",
+			expectedRemaining: 4,
+			expectedErr:       usecase.ErrInvalidConfirmationToken,
+		},
+		{
+			name:              "token from another period is rejected",
+			period:            entity.NewPeriod(base, base.Add(3*time.Hour)),
+			confirmationToken: preview.ConfirmationToken,
+			expectedRemaining: 4,
+			expectedErr:       usecase.ErrInvalidConfirmationToken,
+		},
+		{
+			name:              "unbounded period is rejected",
+			period:            entity.NewAllTimePeriod(base.Add(5 * time.Hour)),
+			expectedRemaining: 4,
+			expectedErr:       usecase.ErrUnboundedPeriod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(newRequests())
+
+			command := usecase.NewDeleteRequestsByPeriodCommand(mockRepo)
+			result, err := command.Execute(context.Background(), usecase.DeleteRequestsByPeriodParams{
+				Period:            tt.period,
+				ConfirmationToken: tt.confirmationToken,
+			})
+
+			remaining, _ := mockRepo.FindAll()
+			if len(remaining) != tt.expectedRemaining {
+				t.Errorf("expected %d remaining records, got %d", tt.expectedRemaining, len(remaining))
+			}
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.MatchedCount != tt.expectedMatched {
+				t.Errorf("expected %d matched, got %d", tt.expectedMatched, result.MatchedCount)
+			}
+			if result.DeletedCount != tt.expectedDeleted {
+				t.Errorf("expected %d deleted, got %d", tt.expectedDeleted, result.DeletedCount)
+			}
+			if result.ConfirmationToken == "" {
+				t.Error("expected a confirmation token")
+			}
+		})
+	}
+}
+
+func TestDeleteRequestsByPeriodCommand_InvalidatesStatsCache(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-a", base.Add(time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-b", base.Add(4*time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+	})
+
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), service.NewInMemoryStatsCache(time.Hour))
+	command := usecase.NewDeleteRequestsByPeriodCommand(mockRepo)
+	command.SetCalculateStatsQuery(calculateStatsQuery)
+
+	statsPeriod := entity.NewPeriod(base, base.Add(5*time.Hour))
+	stats, err := calculateStatsQuery.Execute(context.Background(), usecase.CalculateStatsParams{Period: statsPeriod})
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if stats.TotalRequests() != 2 {
+		t.Fatalf("expected 2 requests before deletion, got %d", stats.TotalRequests())
+	}
+
+	deletePeriod := entity.NewPeriod(base, base.Add(2*time.Hour))
+	preview, err := command.Execute(context.Background(), usecase.DeleteRequestsByPeriodParams{Period: deletePeriod})
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	if _, err := command.Execute(context.Background(), usecase.DeleteRequestsByPeriodParams{
+		Period:            deletePeriod,
+		ConfirmationToken: preview.ConfirmationToken,
+	}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	stats, err = calculateStatsQuery.Execute(context.Background(), usecase.CalculateStatsParams{Period: statsPeriod})
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if stats.TotalRequests() != 1 {
+		t.Errorf("expected 1 request right after deletion, got %d", stats.TotalRequests())
+	}
+}
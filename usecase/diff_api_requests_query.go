@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// RequestsDiff compares the requests of two datasets, e.g. exports from two machines
+type RequestsDiff struct {
+	LeftTotal  int // Requests in the left dataset
+	RightTotal int // Requests in the right dataset
+	Common     int // Requests in both datasets
+
+	LeftCost  entity.Cost // Total cost of the left dataset
+	RightCost entity.Cost // Total cost of the right dataset
+
+	OnlyLeft  []entity.APIRequest // Requests missing from the right dataset, oldest first
+	OnlyRight []entity.APIRequest // Requests missing from the left dataset, oldest first
+}
+
+// OnlyLeftCost returns the total cost of the requests missing from the right dataset
+func (d RequestsDiff) OnlyLeftCost() entity.Cost {
+	return totalCost(d.OnlyLeft)
+}
+
+// OnlyRightCost returns the total cost of the requests missing from the left dataset
+func (d RequestsDiff) OnlyRightCost() entity.Cost {
+	return totalCost(d.OnlyRight)
+}
+
+// IsEmpty returns true if both datasets contain the same requests
+func (d RequestsDiff) IsEmpty() bool {
+	return len(d.OnlyLeft) == 0 && len(d.OnlyRight) == 0
+}
+
+// DiffApiRequestsQuery finds the requests only one of two datasets contains. Requests match by
+// session, timestamp and model, the same request sent to two servers, so a merge keeps one of them.
+type DiffApiRequestsQuery struct {
+	left  APIRequestRepository
+	right APIRequestRepository
+}
+
+// NewDiffApiRequestsQuery creates a new DiffApiRequestsQuery comparing the left and right datasets
+func NewDiffApiRequestsQuery(left APIRequestRepository, right APIRequestRepository) *DiffApiRequestsQuery {
+	return &DiffApiRequestsQuery{
+		left:  left,
+		right: right,
+	}
+}
+
+// Execute compares every request of both datasets. A request repeated within one dataset is
+// matched once per copy, so the extra copies are reported as missing from the other.
+func (q *DiffApiRequestsQuery) Execute(ctx context.Context) (RequestsDiff, error) {
+	left, err := findEveryRequest(q.left)
+	if err != nil {
+		return RequestsDiff{}, fmt.Errorf("failed to read left requests: %w", err)
+	}
+	right, err := findEveryRequest(q.right)
+	if err != nil {
+		return RequestsDiff{}, fmt.Errorf("failed to read right requests: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return RequestsDiff{}, err
+	}
+
+	diff := RequestsDiff{
+		LeftTotal:  len(left),
+		RightTotal: len(right),
+		LeftCost:   totalCost(left),
+		RightCost:  totalCost(right),
+		OnlyLeft:   []entity.APIRequest{},
+		OnlyRight:  []entity.APIRequest{},
+	}
+
+	unmatched := make(map[string]int, len(right))
+	for _, req := range right {
+		unmatched[diffKey(req)]++
+	}
+	for _, req := range left {
+		key := diffKey(req)
+		if unmatched[key] > 0 {
+			unmatched[key]--
+			diff.Common++
+			continue
+		}
+		diff.OnlyLeft = append(diff.OnlyLeft, req)
+	}
+
+	// The right requests left unmatched, walked in order so the oldest copies are reported
+	for _, req := range right {
+		key := diffKey(req)
+		if unmatched[key] > 0 {
+			unmatched[key]--
+			diff.OnlyRight = append(diff.OnlyRight, req)
+		}
+	}
+
+	return diff, nil
+}
+
+// findEveryRequest returns every request of the repository, oldest first
+func findEveryRequest(repository APIRequestRepository) ([]entity.APIRequest, error) {
+	// FindAll keeps only the latest requests of large stores, an explicit range returns every one
+	everything := entity.NewPeriod(time.Unix(0, 0).UTC(), time.Now().UTC().Add(24*time.Hour))
+	requests, err := repository.FindByPeriodWithLimit(everything, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	requests = slices.Clone(requests)
+	slices.SortStableFunc(requests, func(a, b entity.APIRequest) int {
+		return a.Timestamp().Compare(b.Timestamp())
+	})
+	return requests, nil
+}
+
+// diffKey identifies a request by session, UTC timestamp and model, so a request matches across timezones
+func diffKey(req entity.APIRequest) string {
+	return replayID(req) + "_" + req.Model().String()
+}
+
+// totalCost sums the cost of the requests
+func totalCost(requests []entity.APIRequest) entity.Cost {
+	total := entity.NewCost(0)
+	for _, req := range requests {
+		total = total.Add(req.Cost())
+	}
+	return total
+}
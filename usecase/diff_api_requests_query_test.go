@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestDiffApiRequestsQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	shared := testutil.CreateTestAPIRequest("session-1", at, "claude-sonnet-4-20250514", 100, 50, 0.5)
+	laptop := testutil.CreateTestAPIRequest("session-1", at.Add(time.Minute), "claude-sonnet-4-20250514", 100, 50, 0.25)
+	desktop := testutil.CreateTestAPIRequest("session-2", at.Add(2*time.Minute), "claude-3-5-haiku-20241022", 10, 5, 0.01)
+	// The same instant in another timezone is the same request
+	sharedElsewhere := testutil.CreateTestAPIRequest("session-1", at.In(time.FixedZone("UTC+8", 8*60*60)), "claude-sonnet-4-20250514", 100, 50, 0.5)
+	// Another model at the same instant is a different request
+	otherModel := testutil.CreateTestAPIRequest("session-1", at, "claude-3-5-haiku-20241022", 100, 50, 0.05)
+
+	tests := []struct {
+		name              string
+		left              []entity.APIRequest
+		right             []entity.APIRequest
+		expectedCommon    int
+		expectedOnlyLeft  []entity.APIRequest
+		expectedOnlyRight []entity.APIRequest
+		expectedLeftCost  float64
+		expectedRightCost float64
+	}{
+		{
+			name:              "identical datasets",
+			left:              []entity.APIRequest{shared, laptop},
+			right:             []entity.APIRequest{laptop, shared},
+			expectedCommon:    2,
+			expectedOnlyLeft:  []entity.APIRequest{},
+			expectedOnlyRight: []entity.APIRequest{},
+			expectedLeftCost:  0.75,
+			expectedRightCost: 0.75,
+		},
+		{
+			name:              "requests unique to each side",
+			left:              []entity.APIRequest{shared, laptop},
+			right:             []entity.APIRequest{shared, desktop},
+			expectedCommon:    1,
+			expectedOnlyLeft:  []entity.APIRequest{laptop},
+			expectedOnlyRight: []entity.APIRequest{desktop},
+			expectedLeftCost:  0.75,
+			expectedRightCost: 0.51,
+		},
+		{
+			name:              "timestamps match across timezones",
+			left:              []entity.APIRequest{shared},
+			right:             []entity.APIRequest{sharedElsewhere},
+			expectedCommon:    1,
+			expectedOnlyLeft:  []entity.APIRequest{},
+			expectedOnlyRight: []entity.APIRequest{},
+			expectedLeftCost:  0.5,
+			expectedRightCost: 0.5,
+		},
+		{
+			name:              "model is part of the key",
+			left:              []entity.APIRequest{shared},
+			right:             []entity.APIRequest{otherModel},
+			expectedOnlyLeft:  []entity.APIRequest{shared},
+			expectedOnlyRight: []entity.APIRequest{otherModel},
+			expectedLeftCost:  0.5,
+			expectedRightCost: 0.05,
+		},
+		{
+			name:              "extra copies are unique",
+			left:              []entity.APIRequest{shared, shared},
+			right:             []entity.APIRequest{shared},
+			expectedCommon:    1,
+			expectedOnlyLeft:  []entity.APIRequest{shared},
+			expectedOnlyRight: []entity.APIRequest{},
+			expectedLeftCost:  1.0,
+			expectedRightCost: 0.5,
+		},
+		{
+			name:              "empty side",
+			left:              []entity.APIRequest{shared},
+			expectedOnlyLeft:  []entity.APIRequest{shared},
+			expectedOnlyRight: []entity.APIRequest{},
+			expectedLeftCost:  0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			leftRepo, _ := testutil.NewMockRepositoryWithData(tt.left)
+			rightRepo, _ := testutil.NewMockRepositoryWithData(tt.right)
+
+			diff, err := usecase.NewDiffApiRequestsQuery(leftRepo, rightRepo).Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff.LeftTotal != len(tt.left) || diff.RightTotal != len(tt.right) {
+				t.Errorf("expected totals %d and %d, got %d and %d", len(tt.left), len(tt.right), diff.LeftTotal, diff.RightTotal)
+			}
+			if diff.Common != tt.expectedCommon {
+				t.Errorf("expected %d common requests, got %d", tt.expectedCommon, diff.Common)
+			}
+			assertRequestIDs(t, "only left", diff.OnlyLeft, tt.expectedOnlyLeft)
+			assertRequestIDs(t, "only right", diff.OnlyRight, tt.expectedOnlyRight)
+			if diff.IsEmpty() != (len(tt.expectedOnlyLeft) == 0 && len(tt.expectedOnlyRight) == 0) {
+				t.Errorf("unexpected IsEmpty %v", diff.IsEmpty())
+			}
+			if got := diff.LeftCost.Amount(); got < tt.expectedLeftCost-1e-9 || got > tt.expectedLeftCost+1e-9 {
+				t.Errorf("expected left cost %v, got %v", tt.expectedLeftCost, got)
+			}
+			if got := diff.RightCost.Amount(); got < tt.expectedRightCost-1e-9 || got > tt.expectedRightCost+1e-9 {
+				t.Errorf("expected right cost %v, got %v", tt.expectedRightCost, got)
+			}
+		})
+	}
+}
+
+func assertRequestIDs(t *testing.T, name string, got, expected []entity.APIRequest) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("%s: expected %d requests, got %d", name, len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i].ID() != expected[i].ID() || got[i].Model() != expected[i].Model() {
+			t.Errorf("%s[%d]: expected %s (%s), got %s (%s)", name, i, expected[i].ID(), expected[i].Model(), got[i].ID(), got[i].Model())
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import "errors"
+
+var (
+	// ErrRepositoryUnavailable marks queries that failed because their repository could not be read,
+	// e.g. a locked database or an unreachable server
+	ErrRepositoryUnavailable = errors.New("repository unavailable")
+	// ErrPlanUnavailable is returned by a PlanRepository that cannot resolve a plan.
+	// Queries fall back to the unset plan on it, so plan usage reads 0% instead of failing.
+	ErrPlanUnavailable = errors.New("plan unavailable")
+	// ErrModelTiersUnsupported is returned by a StatsRepository that cannot aggregate with the
+	// model tier overrides of a query, e.g. a server older than the overrides
+	ErrModelTiersUnsupported = errors.New("model tier overrides unsupported")
+	// ErrDurationFilterUnsupported is returned by a DurationFilterRepository that cannot filter
+	// by duration, e.g. a server older than the duration filter
+	ErrDurationFilterUnsupported = errors.New("duration filter unsupported")
+)
+
+// classifiedError keeps the message of the underlying error while matching kind with errors.Is,
+// so callers can tell failures apart without changing what users see
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// repositoryError classifies a failed repository call as ErrRepositoryUnavailable
+func repositoryError(err error) error {
+	if err == nil || errors.Is(err, ErrRepositoryUnavailable) {
+		return err
+	}
+	return &classifiedError{kind: ErrRepositoryUnavailable, err: err}
+}
@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestQueryErrors_RepositoryUnavailable(t *testing.T) {
+	t.Parallel()
+
+	period := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	cause := &testutil.MockError{Message: "database locked"}
+
+	tests := []struct {
+		name    string
+		execute func(repo *testutil.MockAPIRequestRepository, statsRepo *testutil.MockStatsRepository) error
+	}{
+		{
+			name: "calculate stats",
+			execute: func(_ *testutil.MockAPIRequestRepository, statsRepo *testutil.MockStatsRepository) error {
+				_, err := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()).Execute(context.Background(), usecase.CalculateStatsParams{Period: period})
+				return err
+			},
+		},
+		{
+			name: "filtered requests",
+			execute: func(repo *testutil.MockAPIRequestRepository, _ *testutil.MockStatsRepository) error {
+				_, err := usecase.NewGetFilteredApiRequestsQuery(repo).Execute(context.Background(), usecase.GetFilteredApiRequestsParams{Period: period})
+				return err
+			},
+		},
+		{
+			name: "count sessions",
+			execute: func(repo *testutil.MockAPIRequestRepository, _ *testutil.MockStatsRepository) error {
+				_, err := usecase.NewCountSessionsQuery(repo, true).Execute(context.Background(), usecase.CountSessionsParams{Period: period})
+				return err
+			},
+		},
+		{
+			name: "summary wraps the stats failure",
+			execute: func(_ *testutil.MockAPIRequestRepository, statsRepo *testutil.MockStatsRepository) error {
+				query := usecase.NewGetSummaryQuery(
+					usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+					testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20))),
+					&MockPeriodFactory{dailyPeriod: period},
+				)
+				_, err := query.Execute(context.Background(), usecase.GetSummaryParams{Now: period.EndAt()})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo, statsRepo := testutil.NewMockRepositoryPair()
+			repo.SetError(cause)
+
+			err := tt.execute(repo, statsRepo)
+			if !errors.Is(err, usecase.ErrRepositoryUnavailable) {
+				t.Fatalf("expected ErrRepositoryUnavailable, got %v", err)
+			}
+			if !errors.Is(err, cause) {
+				t.Errorf("expected the repository error to be kept, got %v", err)
+			}
+			if errors.Is(err, usecase.ErrPlanUnavailable) {
+				t.Errorf("expected a repository failure not to be a plan error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestQueryErrors_KeepMessage(t *testing.T) {
+	t.Parallel()
+
+	repo, statsRepo := testutil.NewMockRepositoryPair()
+	repo.SetError(&testutil.MockError{Message: "server unavailable"})
+
+	_, err := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()).Execute(context.Background(), usecase.CalculateStatsParams{})
+	if err == nil || err.Error() != "server unavailable" {
+		t.Errorf("expected the repository message unchanged, got %v", err)
+	}
+}
+
+func TestGetPlanQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		planErr    error
+		expectKind error
+	}{
+		{
+			name: "configured plan",
+		},
+		{
+			name:       "unavailable plan",
+			planErr:    fmt.Errorf("plan %q is not in plans.json: %w", "team", usecase.ErrPlanUnavailable),
+			expectKind: usecase.ErrPlanUnavailable,
+		},
+		{
+			name:       "repository failure",
+			planErr:    &testutil.MockError{Message: "config unreadable"},
+			expectKind: usecase.ErrRepositoryUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			planRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20)))
+			planRepo.SetError(tt.planErr)
+
+			plan, err := usecase.NewGetPlanQuery(planRepo).Execute(context.Background())
+			if tt.expectKind == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if plan.Name() != "pro" {
+					t.Errorf("expected plan pro, got %q", plan.Name())
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectKind) {
+				t.Errorf("expected %v, got %v", tt.expectKind, err)
+			}
+		})
+	}
+}
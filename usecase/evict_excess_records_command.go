@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+)
+
+// EvictExcessRecordsCommand keeps the store at a maximum number of API requests, evicting the
+// oldest first. It tracks an upper bound of the stored count, so the store is only trimmed once
+// a batch of saves may have crossed the maximum rather than on every save.
+type EvictExcessRecordsCommand struct {
+	repository RecordLimitRepository
+	maxRecords int
+
+	mutex sync.Mutex
+	count int // Upper bound of the stored requests, -1 until counted
+}
+
+// NewEvictExcessRecordsCommand creates a new EvictExcessRecordsCommand keeping at most maxRecords requests
+func NewEvictExcessRecordsCommand(repository RecordLimitRepository, maxRecords int) *EvictExcessRecordsCommand {
+	return &EvictExcessRecordsCommand{
+		repository: repository,
+		maxRecords: maxRecords,
+		count:      -1,
+	}
+}
+
+// EvictExcessRecordsParams contains the parameters for evicting excess records
+type EvictExcessRecordsParams struct {
+	Saved int // Requests saved since the last call
+}
+
+// EvictExcessRecordsResult contains the result of the eviction
+type EvictExcessRecordsResult struct {
+	DeletedCount int
+}
+
+// Execute evicts the oldest requests beyond the maximum after a batch of saves.
+// The first call counts the store, so it also trims a store over a lowered maximum.
+func (c *EvictExcessRecordsCommand) Execute(ctx context.Context, params EvictExcessRecordsParams) (*EvictExcessRecordsResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.count < 0 {
+		count, err := c.repository.CountRequests()
+		if err != nil {
+			return nil, err
+		}
+		c.count = count
+	} else {
+		c.count += params.Saved
+	}
+
+	if c.count <= c.maxRecords {
+		return &EvictExcessRecordsResult{}, nil
+	}
+
+	deletedCount, err := c.repository.DeleteOldestBeyond(c.maxRecords)
+	if err != nil {
+		c.count = -1 // Count again on the next call
+		return nil, err
+	}
+
+	c.count = c.maxRecords
+	if deletedCount == 0 {
+		// The bound drifted above the store, e.g. after retention cleanup or overwritten requests
+		c.count = -1
+	}
+
+	return &EvictExcessRecordsResult{
+		DeletedCount: deletedCount,
+	}, nil
+}
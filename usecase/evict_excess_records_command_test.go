@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// mockRecordLimitRepository keeps a stored request count and records repository calls
+type mockRecordLimitRepository struct {
+	stored      int
+	countCalls  int
+	deleteCalls int
+	countErr    error
+	deleteErr   error
+}
+
+func (m *mockRecordLimitRepository) CountRequests() (int, error) {
+	m.countCalls++
+	if m.countErr != nil {
+		return 0, m.countErr
+	}
+	return m.stored, nil
+}
+
+func (m *mockRecordLimitRepository) DeleteOldestBeyond(maxRecords int) (int, error) {
+	m.deleteCalls++
+	if m.deleteErr != nil {
+		return 0, m.deleteErr
+	}
+	if m.stored <= maxRecords {
+		return 0, nil
+	}
+	deleted := m.stored - maxRecords
+	m.stored = maxRecords
+	return deleted, nil
+}
+
+func TestEvictExcessRecordsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	type step struct {
+		saved           int    // Requests saved before the call
+		deleteErr       error  // Error returned by DeleteOldestBeyond during the call
+		expectedDeleted int    // Deleted requests reported by the call
+		expectError     bool   // Whether the call fails
+		expectedStored  int    // Stored requests after the call
+		expectedCalls   [2]int // Cumulative CountRequests and DeleteOldestBeyond calls
+	}
+
+	tests := []struct {
+		name       string
+		stored     int
+		maxRecords int
+		steps      []step
+	}{
+		{
+			name:       "first call counts and trims a store over the maximum",
+			stored:     15,
+			maxRecords: 10,
+			steps: []step{
+				{expectedDeleted: 5, expectedStored: 10, expectedCalls: [2]int{1, 1}},
+			},
+		},
+		{
+			name:       "skips the repository while under the maximum",
+			stored:     5,
+			maxRecords: 10,
+			steps: []step{
+				{expectedStored: 5, expectedCalls: [2]int{1, 0}},
+				{saved: 2, expectedStored: 7, expectedCalls: [2]int{1, 0}},
+				{saved: 3, expectedStored: 10, expectedCalls: [2]int{1, 0}},
+			},
+		},
+		{
+			name:       "trims once a batch crosses the maximum",
+			stored:     8,
+			maxRecords: 10,
+			steps: []step{
+				{expectedStored: 8, expectedCalls: [2]int{1, 0}},
+				{saved: 4, expectedDeleted: 2, expectedStored: 10, expectedCalls: [2]int{1, 1}},
+				{saved: 1, expectedDeleted: 1, expectedStored: 10, expectedCalls: [2]int{1, 2}},
+			},
+		},
+		{
+			name:       "repository error recounts on the next call",
+			stored:     12,
+			maxRecords: 10,
+			steps: []step{
+				{deleteErr: &testutil.MockError{Message: "database locked"}, expectError: true, expectedStored: 12, expectedCalls: [2]int{1, 1}},
+				{expectedDeleted: 2, expectedStored: 10, expectedCalls: [2]int{2, 2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRecordLimitRepository{stored: tt.stored}
+			command := NewEvictExcessRecordsCommand(repo, tt.maxRecords)
+
+			for i, s := range tt.steps {
+				repo.stored += s.saved
+				repo.deleteErr = s.deleteErr
+
+				result, err := command.Execute(context.Background(), EvictExcessRecordsParams{Saved: s.saved})
+				if s.expectError {
+					if err == nil {
+						t.Errorf("step %d: expected error but got none", i)
+					}
+				} else {
+					if err != nil {
+						t.Fatalf("step %d: unexpected error: %v", i, err)
+					}
+					if result.DeletedCount != s.expectedDeleted {
+						t.Errorf("step %d: expected %d deleted, got %d", i, s.expectedDeleted, result.DeletedCount)
+					}
+				}
+
+				if repo.stored != s.expectedStored {
+					t.Errorf("step %d: expected %d stored, got %d", i, s.expectedStored, repo.stored)
+				}
+				if calls := [2]int{repo.countCalls, repo.deleteCalls}; calls != s.expectedCalls {
+					t.Errorf("step %d: expected %v repository calls, got %v", i, s.expectedCalls, calls)
+				}
+			}
+		})
+	}
+}
+
+func TestEvictExcessRecordsCommand_RecountsAfterDrift(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockRecordLimitRepository{stored: 10}
+	command := NewEvictExcessRecordsCommand(repo, 10)
+
+	if _, err := command.Execute(context.Background(), EvictExcessRecordsParams{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Retention cleanup removed requests behind the command's back, so the saved batch
+	// crosses the tracked bound without crossing the actual maximum
+	repo.stored = 6
+	result, err := command.Execute(context.Background(), EvictExcessRecordsParams{Saved: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.DeletedCount != 0 {
+		t.Errorf("expected nothing deleted, got %d", result.DeletedCount)
+	}
+
+	// Nothing was deleted, so the next call counts the store again
+	if _, err := command.Execute(context.Background(), EvictExcessRecordsParams{Saved: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.countCalls != 2 {
+		t.Errorf("expected the store to be counted again, got %d count calls", repo.countCalls)
+	}
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetDailyBudgetQuery explains how the daily plan budget is derived
+type GetDailyBudgetQuery struct {
+	planRepository PlanRepository
+	periodFactory  PeriodFactory
+}
+
+// NewGetDailyBudgetQuery creates a new GetDailyBudgetQuery with the given dependencies
+func NewGetDailyBudgetQuery(planRepository PlanRepository, periodFactory PeriodFactory) *GetDailyBudgetQuery {
+	return &GetDailyBudgetQuery{
+		planRepository: planRepository,
+		periodFactory:  periodFactory,
+	}
+}
+
+// DailyBudget contains the inputs and result of the daily budget calculation
+type DailyBudget struct {
+	Plan        entity.Plan
+	DaysInMonth int
+	Budget      entity.Cost // Plan price / days in month, zero when no plan is set
+}
+
+// IsPlanSet returns true if a priced plan is configured
+func (b DailyBudget) IsPlanSet() bool {
+	return b.Budget.Amount() > 0
+}
+
+// Execute calculates today's budget the same way as @daily_plan_usage
+func (q *GetDailyBudgetQuery) Execute(ctx context.Context) (*DailyBudget, error) {
+	// Treat a missing plan as unset so the caller can explain why there is no budget
+	plan, err := configuredPlanOrUnset(q.planRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyPeriod := q.periodFactory.CreateDaily()
+
+	return &DailyBudget{
+		Plan:        plan,
+		DaysInMonth: entity.DaysInMonth(dailyPeriod.StartAt()),
+		Budget:      plan.CalculatePeriodBudget(dailyPeriod),
+	}, nil
+}
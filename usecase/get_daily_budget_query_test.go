@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetDailyBudgetQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		plan           entity.Plan
+		dailyStart     time.Time
+		expectedDays   int
+		expectedBudget float64
+		expectedSet    bool
+	}{
+		{
+			name:           "pro plan in a 31 day month",
+			plan:           entity.NewPlan("pro", entity.NewCost(31.0)),
+			dailyStart:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			expectedDays:   31,
+			expectedBudget: 1.0,
+			expectedSet:    true,
+		},
+		{
+			name:           "max plan in february",
+			plan:           entity.NewPlan("max", entity.NewCost(100.0)),
+			dailyStart:     time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC),
+			expectedDays:   28,
+			expectedBudget: 100.0 / 28,
+			expectedSet:    true,
+		},
+		{
+			name:         "unset plan has no budget",
+			plan:         entity.NewPlan("unset", entity.NewCost(0)),
+			dailyStart:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			expectedDays: 30,
+			expectedSet:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			periodFactory := &MockPeriodFactory{
+				dailyPeriod: entity.NewPeriod(tt.dailyStart, tt.dailyStart.Add(24*time.Hour-time.Nanosecond)),
+			}
+			query := usecase.NewGetDailyBudgetQuery(testutil.NewMockPlanRepository(tt.plan), periodFactory)
+
+			budget, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if budget.Plan.Name() != tt.plan.Name() {
+				t.Errorf("expected plan %q, got %q", tt.plan.Name(), budget.Plan.Name())
+			}
+			if budget.DaysInMonth != tt.expectedDays {
+				t.Errorf("expected %d days in month, got %d", tt.expectedDays, budget.DaysInMonth)
+			}
+			if budget.Budget.Amount() != tt.expectedBudget {
+				t.Errorf("expected budget %.4f, got %.4f", tt.expectedBudget, budget.Budget.Amount())
+			}
+			if budget.IsPlanSet() != tt.expectedSet {
+				t.Errorf("expected IsPlanSet %v, got %v", tt.expectedSet, budget.IsPlanSet())
+			}
+		})
+	}
+}
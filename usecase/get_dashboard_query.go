@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetDashboardQuery gathers the stats, block stats and plan needed to render the monitor
+type GetDashboardQuery struct {
+	statsQuery     *CalculateStatsQuery
+	planRepository PlanRepository
+}
+
+// NewGetDashboardQuery creates a new GetDashboardQuery composed from the stats query and plan repository
+func NewGetDashboardQuery(statsQuery *CalculateStatsQuery, planRepository PlanRepository) *GetDashboardQuery {
+	return &GetDashboardQuery{
+		statsQuery:     statsQuery,
+		planRepository: planRepository,
+	}
+}
+
+// GetDashboardParams contains the parameters for the dashboard query
+type GetDashboardParams struct {
+	Period         entity.Period
+	Block          *entity.Block // nil when block tracking is disabled
+	NegligibleCost entity.Cost   // Optional, replaces the stats query's negligible cost threshold when positive
+}
+
+// Dashboard contains the data rendered on each monitor refresh
+type Dashboard struct {
+	Stats      entity.Stats
+	Block      *entity.Block // nil when no block was requested
+	BlockStats entity.Stats
+	Plan       entity.Plan
+}
+
+// Execute executes the dashboard query
+func (q *GetDashboardQuery) Execute(ctx context.Context, params GetDashboardParams) (Dashboard, error) {
+	stats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{Period: params.Period, NegligibleCost: params.NegligibleCost})
+	if err != nil {
+		return Dashboard{}, err
+	}
+
+	dashboard := Dashboard{Stats: stats, Block: params.Block}
+	if params.Block != nil {
+		blockStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{Period: params.Block.Period(), NegligibleCost: params.NegligibleCost})
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.BlockStats = blockStats
+	}
+
+	// Treat a missing plan as unset, same as the daily budget
+	plan, err := configuredPlanOrUnset(q.planRepository)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	dashboard.Plan = plan
+
+	return dashboard, nil
+}
@@ -0,0 +1,81 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetDashboardQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	period := entity.NewPeriod(now.Add(-24*time.Hour), now)
+	block := entity.NewBlockWithLimit(now.Add(-2*time.Hour), 7000)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", now.Add(-6*time.Hour), "claude-sonnet-4-20250514", 100, 50, 0.10),
+		testutil.CreateTestAPIRequest("s1", now.Add(-time.Hour), "claude-sonnet-4-20250514", 200, 100, 0.20),
+	}
+
+	tests := []struct {
+		name                  string
+		block                 *entity.Block
+		plan                  entity.Plan
+		planErr               error
+		expectedBlockRequests int
+		expectedPlan          string
+	}{
+		{
+			name:                  "period and block stats with configured plan",
+			block:                 &block,
+			plan:                  entity.NewPlan("max", entity.NewCost(100)),
+			expectedBlockRequests: 1,
+			expectedPlan:          "max",
+		},
+		{
+			name:         "no block leaves block stats empty",
+			plan:         entity.NewPlan("pro", entity.NewCost(20)),
+			expectedPlan: "pro",
+		},
+		{
+			name:         "missing plan falls back to unset",
+			planErr:      usecase.ErrPlanUnavailable,
+			expectedPlan: "unset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			planRepo := testutil.NewMockPlanRepository(tt.plan)
+			if tt.planErr != nil {
+				planRepo.SetError(tt.planErr)
+			}
+			query := usecase.NewGetDashboardQuery(usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache()), planRepo)
+
+			dashboard, err := query.Execute(context.Background(), usecase.GetDashboardParams{Period: period, Block: tt.block})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if dashboard.Stats.TotalRequests() != 2 {
+				t.Errorf("expected 2 period requests, got %d", dashboard.Stats.TotalRequests())
+			}
+			if dashboard.Block != tt.block {
+				t.Errorf("expected block %v, got %v", tt.block, dashboard.Block)
+			}
+			if dashboard.BlockStats.TotalRequests() != tt.expectedBlockRequests {
+				t.Errorf("expected %d block requests, got %d", tt.expectedBlockRequests, dashboard.BlockStats.TotalRequests())
+			}
+			if dashboard.Plan.Name() != tt.expectedPlan {
+				t.Errorf("expected plan %q, got %q", tt.expectedPlan, dashboard.Plan.Name())
+			}
+		})
+	}
+}
@@ -2,13 +2,23 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
+	"time"
 
 	"github.com/elct9620/ccmon/entity"
 )
 
 // GetFilteredApiRequestsQuery handles the query to get filtered API requests
 type GetFilteredApiRequestsQuery struct {
-	repository APIRequestRepository
+	repository    APIRequestRepository
+	ignoredModels entity.ModelIgnoreList
+	asOf          time.Time
+
+	// Filters durations where the requests are stored; unsupported is set once it reports
+	// ErrDurationFilterUnsupported, so later queries filter locally right away
+	durationRepository  DurationFilterRepository
+	durationUnsupported atomic.Bool
 }
 
 // NewGetFilteredApiRequestsQuery creates a new GetFilteredApiRequestsQuery with the given repository
@@ -18,14 +28,70 @@ func NewGetFilteredApiRequestsQuery(repository APIRequestRepository) *GetFiltere
 	}
 }
 
+// SetIgnoredModels excludes requests for the matching models from every result
+func (q *GetFilteredApiRequestsQuery) SetIgnoredModels(ignoredModels entity.ModelIgnoreList) {
+	q.ignoredModels = ignoredModels
+}
+
+// SetAsOf leaves out requests after the given time from every result, to list the requests as they
+// were then; the zero time keeps all requests
+func (q *GetFilteredApiRequestsQuery) SetAsOf(asOf time.Time) {
+	q.asOf = asOf
+}
+
+// SetDurationFilterRepository filters by duration in the repository, e.g. on the server, instead of
+// fetching every request of the period when only a duration range is given
+func (q *GetFilteredApiRequestsQuery) SetDurationFilterRepository(repository DurationFilterRepository) {
+	q.durationRepository = repository
+}
+
 // GetFilteredApiRequestsParams contains the parameters for getting filtered API requests
 type GetFilteredApiRequestsParams struct {
-	Period entity.Period
-	Limit  int // Use 0 for no limit
-	Offset int // Use 0 for no offset
+	Period   entity.Period
+	Limit    int                  // Use 0 for no limit
+	Offset   int                  // Use 0 for no offset
+	Labels   []entity.LabelFilter // All filters must match; empty means no label filtering
+	Duration entity.DurationRange // Empty means no duration filtering
 }
 
 // Execute executes the get filtered API requests query
 func (q *GetFilteredApiRequestsQuery) Execute(ctx context.Context, params GetFilteredApiRequestsParams) ([]entity.APIRequest, error) {
-	return q.repository.FindByPeriodWithLimit(params.Period, params.Limit, params.Offset)
+	params.Period = params.Period.ClipEnd(q.asOf)
+
+	if len(params.Labels) == 0 && params.Duration.IsEmpty() && q.ignoredModels.IsEmpty() {
+		requests, err := q.repository.FindByPeriodWithLimit(params.Period, params.Limit, params.Offset)
+		return requests, repositoryError(err)
+	}
+
+	if len(params.Labels) == 0 && q.ignoredModels.IsEmpty() && q.durationRepository != nil && !q.durationUnsupported.Load() {
+		requests, err := q.durationRepository.FindByPeriodAndDuration(params.Period, params.Duration, params.Limit, params.Offset)
+		if !errors.Is(err, ErrDurationFilterUnsupported) {
+			return requests, repositoryError(err)
+		}
+		q.durationUnsupported.Store(true)
+	}
+
+	// Labels, durations and models are not indexed, so filter the whole period before applying limit and offset
+	requests, err := q.repository.FindByPeriodWithLimit(params.Period, 0, 0)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	filtered := make([]entity.APIRequest, 0, len(requests))
+	for _, req := range q.ignoredModels.Filter(requests) {
+		if entity.MatchesLabelFilters(req, params.Labels) && params.Duration.Matches(req) {
+			filtered = append(filtered, req)
+		}
+	}
+
+	if params.Offset >= len(filtered) {
+		return []entity.APIRequest{}, nil
+	}
+	filtered = filtered[params.Offset:]
+
+	if params.Limit > 0 && params.Limit < len(filtered) {
+		filtered = filtered[:params.Limit]
+	}
+
+	return filtered, nil
 }
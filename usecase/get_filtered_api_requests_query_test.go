@@ -0,0 +1,316 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetFilteredApiRequestsQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+
+	newRequest := func(sessionID string, offset time.Duration, labels map[string]string) entity.APIRequest {
+		return testutil.CreateTestAPIRequest(sessionID, now.Add(-offset), "claude-3-5-sonnet-20241022", 100, 50, 0.01).WithLabels(labels)
+	}
+
+	requests := []entity.APIRequest{
+		newRequest("session-1", 4*time.Hour, map[string]string{"environment": "prod", "user.id": "alice"}),
+		newRequest("session-2", 3*time.Hour, map[string]string{"environment": "dev", "user.id": "alice"}),
+		newRequest("session-3", 2*time.Hour, map[string]string{"environment": "prod", "user.id": "bob"}),
+		newRequest("session-4", time.Hour, nil),
+	}
+
+	tests := []struct {
+		name             string
+		params           usecase.GetFilteredApiRequestsParams
+		expectedSessions []string
+	}{
+		{
+			name:             "no label filters returns all requests",
+			params:           usecase.GetFilteredApiRequestsParams{Period: period},
+			expectedSessions: []string{"session-1", "session-2", "session-3", "session-4"},
+		},
+		{
+			name: "single label filter",
+			params: usecase.GetFilteredApiRequestsParams{
+				Period: period,
+				Labels: []entity.LabelFilter{entity.NewLabelFilter("environment", "prod")},
+			},
+			expectedSessions: []string{"session-1", "session-3"},
+		},
+		{
+			name: "multiple label filters must all match",
+			params: usecase.GetFilteredApiRequestsParams{
+				Period: period,
+				Labels: []entity.LabelFilter{
+					entity.NewLabelFilter("environment", "prod"),
+					entity.NewLabelFilter("user.id", "bob"),
+				},
+			},
+			expectedSessions: []string{"session-3"},
+		},
+		{
+			name: "absent label yields no match",
+			params: usecase.GetFilteredApiRequestsParams{
+				Period: period,
+				Labels: []entity.LabelFilter{entity.NewLabelFilter("organization.id", "acme")},
+			},
+			expectedSessions: []string{},
+		},
+		{
+			name: "limit and offset apply after label filtering",
+			params: usecase.GetFilteredApiRequestsParams{
+				Period: period,
+				Labels: []entity.LabelFilter{entity.NewLabelFilter("user.id", "alice")},
+				Limit:  1,
+				Offset: 1,
+			},
+			expectedSessions: []string{"session-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(requests)
+
+			query := usecase.NewGetFilteredApiRequestsQuery(mockRepo)
+			result, err := query.Execute(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result) != len(tt.expectedSessions) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expectedSessions), len(result))
+			}
+			for i, req := range result {
+				if req.SessionID() != tt.expectedSessions[i] {
+					t.Errorf("request[%d] session = %s, want %s", i, req.SessionID(), tt.expectedSessions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetFilteredApiRequestsQuery_IgnoredModels(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-3*time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-2*time.Hour), "ollama/llama3", 100, 50, 0),
+		testutil.CreateTestAPIRequest("session-3", now.Add(-time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+	})
+
+	query := usecase.NewGetFilteredApiRequestsQuery(repo)
+	query.SetIgnoredModels(entity.NewModelIgnoreList([]string{"ollama/*"}))
+
+	requests, err := query.Execute(context.Background(), usecase.GetFilteredApiRequestsParams{Period: period, Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The limit applies after ignored models are removed
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	for _, req := range requests {
+		if req.SessionID() == "session-2" {
+			t.Error("Expected ignored model to be excluded")
+		}
+	}
+
+	// Ignored requests are still stored
+	stored, _ := repo.FindAll()
+	if len(stored) != 3 {
+		t.Errorf("Expected all 3 requests to remain stored, got %d", len(stored))
+	}
+}
+
+func TestGetFilteredApiRequestsQuery_Duration(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	requestTaking := func(sessionID string, offset time.Duration, durationMS int64) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, now.Add(-offset), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), durationMS)
+	}
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		requestTaking("fast", 4*time.Hour, 2_000),
+		requestTaking("at-min", 3*time.Hour, 30_000),
+		requestTaking("slow", 2*time.Hour, 45_000),
+		requestTaking("at-max", time.Hour, 60_000),
+	})
+	query := usecase.NewGetFilteredApiRequestsQuery(repo)
+
+	tests := []struct {
+		name     string
+		min      time.Duration
+		max      time.Duration
+		limit    int
+		expected []string
+	}{
+		{name: "no bounds returns everything", expected: []string{"fast", "at-min", "slow", "at-max"}},
+		{name: "minimum is inclusive", min: 30 * time.Second, expected: []string{"at-min", "slow", "at-max"}},
+		{name: "maximum is exclusive", max: time.Minute, expected: []string{"fast", "at-min", "slow"}},
+		{name: "both bounds", min: 30 * time.Second, max: time.Minute, expected: []string{"at-min", "slow"}},
+		{name: "limit applies after filtering", min: 30 * time.Second, limit: 1, expected: []string{"at-min"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			durationRange, err := entity.NewDurationRange(tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("Unexpected range error: %v", err)
+			}
+
+			requests, err := query.Execute(context.Background(), usecase.GetFilteredApiRequestsParams{
+				Period:   period,
+				Limit:    tt.limit,
+				Duration: durationRange,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := make([]string, len(requests))
+			for i, req := range requests {
+				got[i] = req.SessionID()
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected sessions %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// fakeDurationFilterRepository filters durations like a server, or fails as one ignoring the range
+type fakeDurationFilterRepository struct {
+	requests    []entity.APIRequest
+	unsupported bool
+	calls       int
+}
+
+func (r *fakeDurationFilterRepository) FindByPeriodAndDuration(period entity.Period, durationRange entity.DurationRange, limit int, offset int) ([]entity.APIRequest, error) {
+	r.calls++
+	if r.unsupported {
+		return nil, fmt.Errorf("server ignored the duration range: %w", usecase.ErrDurationFilterUnsupported)
+	}
+	return r.requests, nil
+}
+
+func TestGetFilteredApiRequestsQuery_DurationFilterRepository(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	requestTaking := func(sessionID string, durationMS int64) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, now.Add(-time.Hour), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), durationMS).
+			WithLabels(map[string]string{"project": "web"})
+	}
+	durationRange, err := entity.NewDurationRange(30*time.Second, 0)
+	if err != nil {
+		t.Fatalf("Unexpected range error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		unsupported   bool
+		labels        []entity.LabelFilter
+		expected      string
+		expectedCalls int
+	}{
+		{name: "filtered by the repository", expected: "server", expectedCalls: 2},
+		{name: "unsupported falls back to local filtering once", unsupported: true, expected: "slow", expectedCalls: 1},
+		{name: "label filters need the whole period", labels: []entity.LabelFilter{entity.NewLabelFilter("project", "web")}, expected: "slow", expectedCalls: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData([]entity.APIRequest{requestTaking("fast", 2_000), requestTaking("slow", 45_000)})
+			durationRepo := &fakeDurationFilterRepository{requests: []entity.APIRequest{requestTaking("server", 45_000)}, unsupported: tt.unsupported}
+
+			query := usecase.NewGetFilteredApiRequestsQuery(repo)
+			query.SetDurationFilterRepository(durationRepo)
+
+			// The second query shows whether an unsupported repository is skipped afterwards
+			for range 2 {
+				requests, err := query.Execute(context.Background(), usecase.GetFilteredApiRequestsParams{
+					Period:   period,
+					Labels:   tt.labels,
+					Duration: durationRange,
+				})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(requests) != 1 || requests[0].SessionID() != tt.expected {
+					t.Fatalf("Expected only %s, got %d requests", tt.expected, len(requests))
+				}
+			}
+			if durationRepo.calls != tt.expectedCalls {
+				t.Errorf("Expected %d repository calls, got %d", tt.expectedCalls, durationRepo.calls)
+			}
+		})
+	}
+}
+
+func TestGetFilteredApiRequestsQuery_AsOf(t *testing.T) {
+	t.Parallel()
+
+	asOf := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	day := entity.NewPeriod(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 1, 23, 59, 59, 0, time.UTC))
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", asOf.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-2", asOf.Add(time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+	})
+
+	tests := []struct {
+		name     string
+		asOf     time.Time
+		expected int
+	}{
+		{name: "all requests without an as-of time", expected: 2},
+		{name: "requests after the as-of time left out", asOf: asOf, expected: 1},
+		{name: "as-of time before the period", asOf: day.StartAt().Add(-time.Hour), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query := usecase.NewGetFilteredApiRequestsQuery(repo)
+			query.SetAsOf(tt.asOf)
+
+			requests, err := query.Execute(context.Background(), usecase.GetFilteredApiRequestsParams{Period: day})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(requests) != tt.expected {
+				t.Errorf("Expected %d requests, got %d", tt.expected, len(requests))
+			}
+		})
+	}
+}
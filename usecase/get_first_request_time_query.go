@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetFirstRequestTimeQuery finds when the earliest request within a period was made
+type GetFirstRequestTimeQuery struct {
+	repository APIRequestRepository
+}
+
+// NewGetFirstRequestTimeQuery creates a new GetFirstRequestTimeQuery with the given repository
+func NewGetFirstRequestTimeQuery(repository APIRequestRepository) *GetFirstRequestTimeQuery {
+	return &GetFirstRequestTimeQuery{
+		repository: repository,
+	}
+}
+
+// GetFirstRequestTimeParams contains the parameters for finding the first request
+type GetFirstRequestTimeParams struct {
+	Period entity.Period
+}
+
+// Execute returns the timestamp of the earliest request in the period
+// Returns false when the period has no requests
+func (q *GetFirstRequestTimeQuery) Execute(ctx context.Context, params GetFirstRequestTimeParams) (time.Time, bool, error) {
+	requests, err := q.repository.FindByPeriodWithLimit(params.Period, 0, 0)
+	if err != nil {
+		return time.Time{}, false, repositoryError(err)
+	}
+
+	var first time.Time
+	for _, request := range requests {
+		if first.IsZero() || request.Timestamp().Before(first) {
+			first = request.Timestamp()
+		}
+	}
+
+	return first, !first.IsZero(), nil
+}
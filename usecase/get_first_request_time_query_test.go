@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetFirstRequestTimeQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+
+	tests := []struct {
+		name        string
+		requests    []entity.APIRequest
+		repoErr     error
+		expected    time.Time
+		expectFound bool
+		expectError bool
+	}{
+		{
+			name: "earliest request regardless of order",
+			requests: []entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-a", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+				testutil.CreateTestAPIRequest("session-a", now.Add(-3*time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+				testutil.CreateTestAPIRequest("session-b", now.Add(-2*time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+			},
+			expected:    now.Add(-3 * time.Hour),
+			expectFound: true,
+		},
+		{
+			name:        "no requests in period",
+			requests:    []entity.APIRequest{},
+			expectFound: false,
+		},
+		{
+			name:        "repository error",
+			repoErr:     &testutil.MockError{Message: "database connection failed"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(tt.requests)
+			if tt.repoErr != nil {
+				mockRepo.SetError(tt.repoErr)
+			}
+
+			query := usecase.NewGetFirstRequestTimeQuery(mockRepo)
+
+			first, found, err := query.Execute(context.Background(), usecase.GetFirstRequestTimeParams{Period: period})
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if found != tt.expectFound {
+				t.Fatalf("expected found = %v, got %v", tt.expectFound, found)
+			}
+			if !first.Equal(tt.expected) {
+				t.Errorf("expected first request at %v, got %v", tt.expected, first)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetLatestRequestTimeQuery finds when the most recent stored request was made, e.g. to tell
+// whether ingestion has stalled
+type GetLatestRequestTimeQuery struct {
+	getFilteredQuery *GetFilteredApiRequestsQuery
+}
+
+// NewGetLatestRequestTimeQuery creates a new GetLatestRequestTimeQuery reading requests through the filtered query
+func NewGetLatestRequestTimeQuery(getFilteredQuery *GetFilteredApiRequestsQuery) *GetLatestRequestTimeQuery {
+	return &GetLatestRequestTimeQuery{
+		getFilteredQuery: getFilteredQuery,
+	}
+}
+
+// GetLatestRequestTimeParams contains the parameters for finding the latest request
+type GetLatestRequestTimeParams struct {
+	Period entity.Period
+}
+
+// Execute returns the timestamp of the latest request in the period
+// Returns false when the period has no requests
+func (q *GetLatestRequestTimeQuery) Execute(ctx context.Context, params GetLatestRequestTimeParams) (time.Time, bool, error) {
+	// Stores return the most recent requests when limited without an offset
+	requests, err := q.getFilteredQuery.Execute(ctx, GetFilteredApiRequestsParams{Period: params.Period, Limit: 1})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	var latest time.Time
+	for _, request := range requests {
+		if request.Timestamp().After(latest) {
+			latest = request.Timestamp()
+		}
+	}
+
+	return latest, !latest.IsZero(), nil
+}
@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetLatestRequestTimeQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewAllTimePeriod(now)
+
+	tests := []struct {
+		name        string
+		requests    []entity.APIRequest
+		repoErr     error
+		expected    time.Time
+		expectFound bool
+		expectError bool
+	}{
+		{
+			// Limited queries return the most recent requests first, as the stores do
+			name: "latest request",
+			requests: []entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-a", now.Add(-time.Minute), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+				testutil.CreateTestAPIRequest("session-b", now.Add(-3*time.Hour), "claude-3-haiku-20240307", 100, 50, 0.01),
+			},
+			expected:    now.Add(-time.Minute),
+			expectFound: true,
+		},
+		{
+			name:        "no requests stored",
+			requests:    []entity.APIRequest{},
+			expectFound: false,
+		},
+		{
+			name:        "repository error",
+			repoErr:     &testutil.MockError{Message: "database connection failed"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(tt.requests)
+			if tt.repoErr != nil {
+				mockRepo.SetError(tt.repoErr)
+			}
+
+			query := usecase.NewGetLatestRequestTimeQuery(usecase.NewGetFilteredApiRequestsQuery(mockRepo))
+
+			latest, found, err := query.Execute(context.Background(), usecase.GetLatestRequestTimeParams{Period: period})
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if found != tt.expectFound {
+				t.Fatalf("expected found = %v, got %v", tt.expectFound, found)
+			}
+			if !latest.Equal(tt.expected) {
+				t.Errorf("expected latest request at %v, got %v", tt.expected, latest)
+			}
+		})
+	}
+}
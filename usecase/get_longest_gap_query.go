@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetLongestGapQuery finds the longest idle time between consecutive requests in a period
+type GetLongestGapQuery struct {
+	getFilteredQuery *GetFilteredApiRequestsQuery
+	statsQuery       *CalculateStatsQuery
+}
+
+// NewGetLongestGapQuery creates a new GetLongestGapQuery reading requests through the filtered query
+func NewGetLongestGapQuery(getFilteredQuery *GetFilteredApiRequestsQuery) *GetLongestGapQuery {
+	return &GetLongestGapQuery{
+		getFilteredQuery: getFilteredQuery,
+	}
+}
+
+// SetStatsQuery reads the longest gap from the period stats, which are cached and calculated by the
+// stats repository, instead of fetching every request of the period on each refresh
+func (q *GetLongestGapQuery) SetStatsQuery(statsQuery *CalculateStatsQuery) {
+	q.statsQuery = statsQuery
+}
+
+// GetLongestGapParams contains the parameters for the longest gap query
+type GetLongestGapParams struct {
+	Period entity.Period
+}
+
+// LongestGap contains the longest idle time between requests for a period
+type LongestGap struct {
+	Duration  time.Duration
+	Available bool // False with fewer than two requests, so there is no gap to show
+}
+
+// Execute calculates the longest gap between the requests within the period
+func (q *GetLongestGapQuery) Execute(ctx context.Context, params GetLongestGapParams) (LongestGap, error) {
+	if q.statsQuery != nil {
+		stats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{Period: params.Period})
+		if err != nil {
+			return LongestGap{}, err
+		}
+		// Two or more requests always have a gap, so stats without one come from a server or
+		// cache file that predates the longest gap
+		duration, available := stats.LongestGap()
+		if available || stats.TotalRequests() < 2 {
+			return LongestGap{Duration: duration, Available: available}, nil
+		}
+	}
+
+	requests, err := q.getFilteredQuery.Execute(ctx, GetFilteredApiRequestsParams{Period: params.Period})
+	if err != nil {
+		return LongestGap{}, err
+	}
+
+	duration, available := entity.LongestRequestGap(requests)
+	return LongestGap{Duration: duration, Available: available}, nil
+}
@@ -0,0 +1,65 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetLongestGapQuery_StatsQuery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	stats := entity.NewStats(2, 0, entity.Token{}, entity.Token{}, entity.Cost{}, entity.Cost{}, period)
+
+	tests := []struct {
+		name     string
+		stats    entity.Stats
+		expected usecase.LongestGap
+	}{
+		{
+			name:     "gap from the stats",
+			stats:    stats.WithLongestGap(45*time.Minute, true),
+			expected: usecase.LongestGap{Duration: 45 * time.Minute, Available: true},
+		},
+		{
+			name:     "no gap without enough requests",
+			stats:    entity.NewStats(1, 0, entity.Token{}, entity.Token{}, entity.Cost{}, entity.Cost{}, period),
+			expected: usecase.LongestGap{},
+		},
+		{
+			name:     "server without the longest gap falls back to the requests",
+			stats:    stats,
+			expected: usecase.LongestGap{Duration: 2 * time.Hour, Available: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// The requests are only read when the stats cannot answer
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-a", now.Add(-3*time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+				testutil.CreateTestAPIRequest("session-a", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.01),
+			})
+
+			query := usecase.NewGetLongestGapQuery(usecase.NewGetFilteredApiRequestsQuery(mockRepo))
+			query.SetStatsQuery(usecase.NewCalculateStatsQuery(&fixedStatsRepository{stats: tt.stats}, testutil.NewNoOpStatsCache()))
+
+			gap, err := query.Execute(context.Background(), usecase.GetLongestGapParams{Period: period})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gap != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, gap)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// DefaultModelsCacheTTL is how long GetModelsQuery reuses a result, as the set of models changes slowly
+const DefaultModelsCacheTTL = time.Minute
+
+// GetModelsQuery lists the distinct models in the store, caching the result briefly
+type GetModelsQuery struct {
+	repository    ModelRepository
+	ignoredModels entity.ModelIgnoreList
+	clock         entity.Clock
+	cacheTTL      time.Duration
+
+	mu        sync.Mutex
+	cached    []entity.ModelUsage
+	expiresAt time.Time
+}
+
+// NewGetModelsQuery creates a new GetModelsQuery with the given dependencies
+func NewGetModelsQuery(repository ModelRepository) *GetModelsQuery {
+	return &GetModelsQuery{
+		repository: repository,
+		clock:      entity.SystemClock{},
+		cacheTTL:   DefaultModelsCacheTTL,
+	}
+}
+
+// SetIgnoredModels leaves the matching models out of the result
+func (q *GetModelsQuery) SetIgnoredModels(ignoredModels entity.ModelIgnoreList) {
+	q.ignoredModels = ignoredModels
+}
+
+// SetClock sets the source of the current time used to expire the cached result
+func (q *GetModelsQuery) SetClock(clock entity.Clock) {
+	q.clock = clock
+}
+
+// SetCacheTTL sets how long a result is reused; zero disables caching
+func (q *GetModelsQuery) SetCacheTTL(ttl time.Duration) {
+	q.cacheTTL = ttl
+}
+
+// Execute returns the models, most used first
+func (q *GetModelsQuery) Execute(ctx context.Context) ([]entity.ModelUsage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.clock.Now()
+	if q.cached != nil && now.Before(q.expiresAt) {
+		return q.cached, nil
+	}
+
+	usages, err := q.repository.FindModels()
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	models := make([]entity.ModelUsage, 0, len(usages))
+	for _, usage := range usages {
+		if q.ignoredModels.Matches(usage.Model()) {
+			continue
+		}
+		models = append(models, usage)
+	}
+
+	if q.cacheTTL > 0 {
+		q.cached = models
+		q.expiresAt = now.Add(q.cacheTTL)
+	}
+
+	return models, nil
+}
@@ -0,0 +1,122 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// countingModelRepository returns fixed models and counts how often it is queried
+type countingModelRepository struct {
+	models []entity.ModelUsage
+	err    error
+	calls  int
+}
+
+func (r *countingModelRepository) FindModels() ([]entity.ModelUsage, error) {
+	r.calls++
+	return r.models, r.err
+}
+
+func TestGetModelsQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+	models := []entity.ModelUsage{
+		entity.NewModelUsage(entity.NewModel("claude-sonnet-4-20250514"), now.Add(-48*time.Hour), now, 10),
+		entity.NewModelUsage(entity.NewModel("local-llama"), now.Add(-time.Hour), now.Add(-time.Hour), 1),
+	}
+
+	tests := []struct {
+		name           string
+		ignoredModels  []string
+		cacheTTL       time.Duration
+		secondCallAt   time.Time
+		expectedModels []string
+		expectedCalls  int
+	}{
+		{
+			name:           "results are reused within the cache ttl",
+			cacheTTL:       time.Minute,
+			secondCallAt:   now.Add(30 * time.Second),
+			expectedModels: []string{"claude-sonnet-4-20250514", "local-llama"},
+			expectedCalls:  1,
+		},
+		{
+			name:           "expired results are fetched again",
+			cacheTTL:       time.Minute,
+			secondCallAt:   now.Add(time.Minute),
+			expectedModels: []string{"claude-sonnet-4-20250514", "local-llama"},
+			expectedCalls:  2,
+		},
+		{
+			name:           "zero ttl disables the cache",
+			secondCallAt:   now,
+			expectedModels: []string{"claude-sonnet-4-20250514", "local-llama"},
+			expectedCalls:  2,
+		},
+		{
+			name:           "ignored models are left out",
+			ignoredModels:  []string{"local-*"},
+			cacheTTL:       time.Minute,
+			secondCallAt:   now,
+			expectedModels: []string{"claude-sonnet-4-20250514"},
+			expectedCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &countingModelRepository{models: models}
+			query := usecase.NewGetModelsQuery(repo)
+			query.SetIgnoredModels(entity.NewModelIgnoreList(tt.ignoredModels))
+			query.SetCacheTTL(tt.cacheTTL)
+			query.SetClock(entity.NewFixedClock(now))
+
+			if _, err := query.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			query.SetClock(entity.NewFixedClock(tt.secondCallAt))
+			result, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if repo.calls != tt.expectedCalls {
+				t.Errorf("expected %d repository calls, got %d", tt.expectedCalls, repo.calls)
+			}
+			if len(result) != len(tt.expectedModels) {
+				t.Fatalf("expected %d models, got %d", len(tt.expectedModels), len(result))
+			}
+			for i, name := range tt.expectedModels {
+				if result[i].Model().String() != name {
+					t.Errorf("model %d = %s, want %s", i, result[i].Model().String(), name)
+				}
+			}
+		})
+	}
+}
+
+func TestGetModelsQuery_ExecuteError(t *testing.T) {
+	t.Parallel()
+
+	repo := &countingModelRepository{err: errors.New("database error")}
+	query := usecase.NewGetModelsQuery(repo)
+
+	if _, err := query.Execute(context.Background()); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	// Failures are not cached
+	if _, err := query.Execute(context.Background()); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if repo.calls != 2 {
+		t.Errorf("expected 2 repository calls, got %d", repo.calls)
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"github.com/elct9620/ccmon/entity"
 )
@@ -18,7 +19,24 @@ func NewGetPlanQuery(planRepository PlanRepository) *GetPlanQuery {
 	}
 }
 
-// Execute executes the get plan query
+// Execute executes the get plan query, returning ErrPlanUnavailable when no plan can be resolved
 func (q *GetPlanQuery) Execute(ctx context.Context) (entity.Plan, error) {
-	return q.planRepository.GetConfiguredPlan()
+	plan, err := q.planRepository.GetConfiguredPlan()
+	if err != nil && !errors.Is(err, ErrPlanUnavailable) {
+		return entity.Plan{}, repositoryError(err)
+	}
+	return plan, err
+}
+
+// configuredPlanOrUnset returns the configured plan, or the unset plan when the repository
+// reports ErrPlanUnavailable. Other failures are returned as ErrRepositoryUnavailable.
+func configuredPlanOrUnset(planRepository PlanRepository) (entity.Plan, error) {
+	plan, err := planRepository.GetConfiguredPlan()
+	if errors.Is(err, ErrPlanUnavailable) {
+		return entity.NewPlan("unset", entity.NewCost(0)), nil
+	}
+	if err != nil {
+		return entity.Plan{}, repositoryError(err)
+	}
+	return plan, nil
 }
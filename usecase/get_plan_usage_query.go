@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetPlanUsageQuery calculates today's and this month's plan usage the same way as
+// @daily_plan_usage and @monthly_plan_usage
+type GetPlanUsageQuery struct {
+	statsQuery     *CalculateStatsQuery
+	planRepository PlanRepository
+	periodFactory  PeriodFactory
+}
+
+// NewGetPlanUsageQuery creates a new GetPlanUsageQuery with the given dependencies
+func NewGetPlanUsageQuery(statsQuery *CalculateStatsQuery, planRepository PlanRepository, periodFactory PeriodFactory) *GetPlanUsageQuery {
+	return &GetPlanUsageQuery{
+		statsQuery:     statsQuery,
+		planRepository: planRepository,
+		periodFactory:  periodFactory,
+	}
+}
+
+// PlanUsage contains the daily and monthly plan usage percentages
+type PlanUsage struct {
+	Plan    entity.Plan
+	Daily   int // Percentage of the daily budget used
+	Monthly int // Percentage of the plan price used this month
+
+	// Unrounded percentages for display with decimals
+	DailyPercent   float64
+	MonthlyPercent float64
+}
+
+// IsPlanSet returns true if a priced plan is configured, otherwise both usages are 0
+func (u PlanUsage) IsPlanSet() bool {
+	return u.Plan.Price().Amount() > 0
+}
+
+// Execute calculates the plan usage, skipping the stats queries when no plan is priced
+func (q *GetPlanUsageQuery) Execute(ctx context.Context) (*PlanUsage, error) {
+	plan, err := configuredPlanOrUnset(q.planRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &PlanUsage{Plan: plan}
+	if !usage.IsPlanSet() {
+		return usage, nil
+	}
+
+	dailyPeriod := q.periodFactory.CreateDaily()
+	dailyStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
+		Period: dailyPeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate daily stats: %w", err)
+	}
+
+	monthlyStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
+		Period: q.periodFactory.CreateMonthly(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate monthly stats: %w", err)
+	}
+
+	usage.DailyPercent = plan.UsagePercentInPeriod(dailyStats.TotalCost(), dailyPeriod)
+	usage.MonthlyPercent = plan.UsagePercent(monthlyStats.TotalCost())
+	usage.Daily = int(usage.DailyPercent)
+	usage.Monthly = int(usage.MonthlyPercent)
+	return usage, nil
+}
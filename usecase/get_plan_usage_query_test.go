@@ -0,0 +1,68 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetPlanUsageQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+	dailyPeriod := entity.NewPeriod(time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), now)
+	monthlyPeriod := entity.NewPeriod(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), now)
+
+	tests := []struct {
+		name            string
+		plan            entity.Plan
+		expectedPlanSet bool
+		expectedDaily   int
+		expectedMonthly int
+	}{
+		{
+			name:            "priced plan",
+			plan:            entity.NewPlan("pro", entity.NewCost(20)),
+			expectedPlanSet: true,
+			expectedDaily:   300, // $2 of a $20 / 30 day budget
+			expectedMonthly: 90,  // $18 of $20
+		},
+		{
+			name: "unset plan",
+			plan: entity.NewPlan("unset", entity.NewCost(0)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, 2),
+				testutil.CreateTestAPIRequest("session-2", now.AddDate(0, 0, -7), "claude-3-5-sonnet-20241022", 1000, 500, 16),
+			})
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+			periodFactory := &MockPeriodFactory{dailyPeriod: dailyPeriod, monthlyPeriod: monthlyPeriod}
+
+			query := usecase.NewGetPlanUsageQuery(statsQuery, testutil.NewMockPlanRepository(tt.plan), periodFactory)
+
+			usage, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if usage.IsPlanSet() != tt.expectedPlanSet {
+				t.Errorf("expected plan set %v, got %v", tt.expectedPlanSet, usage.IsPlanSet())
+			}
+			if usage.Daily != tt.expectedDaily {
+				t.Errorf("expected daily usage %d%%, got %d%%", tt.expectedDaily, usage.Daily)
+			}
+			if usage.Monthly != tt.expectedMonthly {
+				t.Errorf("expected monthly usage %d%%, got %d%%", tt.expectedMonthly, usage.Monthly)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetStatsByBucketQuery aggregates statistics into consecutive calendar buckets,
+// e.g. one entry per day, for plotting usage over time
+type GetStatsByBucketQuery struct {
+	repository APIRequestRepository
+}
+
+// NewGetStatsByBucketQuery creates a new GetStatsByBucketQuery with the given dependencies
+func NewGetStatsByBucketQuery(repository APIRequestRepository) *GetStatsByBucketQuery {
+	return &GetStatsByBucketQuery{
+		repository: repository,
+	}
+}
+
+// GetStatsByBucketParams contains the parameters for aggregating statistics by bucket
+type GetStatsByBucketParams struct {
+	Period   entity.Period
+	Bucket   entity.TimeBucket
+	Timezone *time.Location // Timezone the bucket boundaries are taken in; nil uses UTC
+}
+
+// BucketStats pairs the start of a bucket with the statistics of every request in it
+type BucketStats struct {
+	StartAt time.Time
+	Stats   entity.Stats
+}
+
+// Execute returns one entry per bucket from the first to the last request, oldest first.
+// Buckets without requests in between are included so the series has no gaps.
+func (q *GetStatsByBucketQuery) Execute(ctx context.Context, params GetStatsByBucketParams) ([]BucketStats, error) {
+	timezone := params.Timezone
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	requests, err := q.repository.FindByPeriodWithLimit(params.Period, 0, 0) // No limit for stats calculation
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	grouped := make(map[time.Time][]entity.APIRequest)
+	first, last := requests[0].Timestamp(), requests[0].Timestamp()
+	for _, req := range requests {
+		timestamp := req.Timestamp()
+		start := params.Bucket.Start(timestamp, timezone)
+		grouped[start] = append(grouped[start], req)
+
+		if timestamp.Before(first) {
+			first = timestamp
+		}
+		if timestamp.After(last) {
+			last = timestamp
+		}
+	}
+
+	var results []BucketStats
+	lastStart := params.Bucket.Start(last, timezone)
+	for start := params.Bucket.Start(first, timezone); !start.After(lastStart); start = params.Bucket.Next(start) {
+		results = append(results, BucketStats{
+			StartAt: start,
+			Stats:   entity.NewStatsFromRequests(grouped[start], entity.NewPeriod(start, params.Bucket.Next(start))),
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,92 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetStatsByBucketQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", time.Date(2025, 1, 27, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 0.10),
+		testutil.CreateTestAPIRequest("s1", time.Date(2025, 1, 27, 20, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 200, 100, 0.20),
+		testutil.CreateTestAPIRequest("s2", time.Date(2025, 1, 29, 9, 30, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 300, 100, 0.05),
+	}
+
+	tests := []struct {
+		name           string
+		requests       []entity.APIRequest
+		bucket         entity.TimeBucket
+		timezone       *time.Location
+		expectedStarts []time.Time
+		expectedCosts  []float64
+	}{
+		{
+			name:           "daily buckets include empty days in between",
+			requests:       requests,
+			bucket:         entity.TimeBucketDay,
+			timezone:       time.UTC,
+			expectedStarts: []time.Time{time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)},
+			expectedCosts:  []float64{0.30, 0, 0.05},
+		},
+		{
+			name:           "buckets follow the configured timezone",
+			requests:       requests,
+			bucket:         entity.TimeBucketDay,
+			timezone:       tokyo,
+			expectedStarts: []time.Time{time.Date(2025, 1, 27, 0, 0, 0, 0, tokyo), time.Date(2025, 1, 28, 0, 0, 0, 0, tokyo), time.Date(2025, 1, 29, 0, 0, 0, 0, tokyo)},
+			expectedCosts:  []float64{0.10, 0.20, 0.05},
+		},
+		{
+			name:           "weekly buckets group the whole week",
+			requests:       requests,
+			bucket:         entity.TimeBucketWeek,
+			timezone:       time.UTC,
+			expectedStarts: []time.Time{time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC)},
+			expectedCosts:  []float64{0.35},
+		},
+		{
+			name:     "no requests return no buckets",
+			bucket:   entity.TimeBucketHour,
+			timezone: time.UTC,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo, _ := testutil.NewMockRepositoryWithData(tt.requests)
+			query := usecase.NewGetStatsByBucketQuery(apiRepo)
+
+			results, err := query.Execute(context.Background(), usecase.GetStatsByBucketParams{
+				Period:   entity.NewAllTimePeriod(now),
+				Bucket:   tt.bucket,
+				Timezone: tt.timezone,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(results) != len(tt.expectedStarts) {
+				t.Fatalf("expected %d buckets, got %d", len(tt.expectedStarts), len(results))
+			}
+			for i, result := range results {
+				if !result.StartAt.Equal(tt.expectedStarts[i]) {
+					t.Errorf("bucket %d starts at %v, want %v", i, result.StartAt, tt.expectedStarts[i])
+				}
+				if diff := result.Stats.TotalCost().Amount() - tt.expectedCosts[i]; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("bucket %d cost = %f, want %f", i, result.Stats.TotalCost().Amount(), tt.expectedCosts[i])
+				}
+			}
+		})
+	}
+}
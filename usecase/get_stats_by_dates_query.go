@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// DailyPeriodFactory creates the calendar day period containing an arbitrary date
+type DailyPeriodFactory interface {
+	CreateDailyFor(date time.Time) entity.Period
+}
+
+// GetStatsByDatesQuery calculates statistics for each day in an explicit list of dates
+type GetStatsByDatesQuery struct {
+	statsQuery    *CalculateStatsQuery
+	periodFactory DailyPeriodFactory
+}
+
+// NewGetStatsByDatesQuery creates a new GetStatsByDatesQuery with the given dependencies
+func NewGetStatsByDatesQuery(statsQuery *CalculateStatsQuery, periodFactory DailyPeriodFactory) *GetStatsByDatesQuery {
+	return &GetStatsByDatesQuery{
+		statsQuery:    statsQuery,
+		periodFactory: periodFactory,
+	}
+}
+
+// GetStatsByDatesParams contains the parameters for calculating per-date statistics
+type GetStatsByDatesParams struct {
+	Dates []time.Time
+}
+
+// DateStats pairs a requested date with the statistics of its day
+type DateStats struct {
+	Date  time.Time
+	Stats entity.Stats
+}
+
+// Execute calculates statistics for every date, in the order they were given
+func (q *GetStatsByDatesQuery) Execute(ctx context.Context, params GetStatsByDatesParams) ([]DateStats, error) {
+	if len(params.Dates) == 0 {
+		return nil, errors.New("at least one date is required")
+	}
+
+	results := make([]DateStats, 0, len(params.Dates))
+	for _, date := range params.Dates {
+		stats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
+			Period: q.periodFactory.CreateDailyFor(date),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, DateStats{Date: date, Stats: stats})
+	}
+
+	return results, nil
+}
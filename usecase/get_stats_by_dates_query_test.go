@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetStatsByDatesQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("s1", time.Date(2025, 1, 6, 23, 59, 0, 0, time.UTC), "claude-sonnet-4-20250514", 200, 100, 0.20),
+		testutil.CreateTestAPIRequest("s2", time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 300, 100, 0.30),
+		testutil.CreateTestAPIRequest("s3", time.Date(2025, 1, 13, 12, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 400, 200, 0.50),
+	}
+
+	tests := []struct {
+		name             string
+		dates            []time.Time
+		expectedRequests []int
+		expectedCosts    []float64
+		expectError      bool
+	}{
+		{
+			name: "each date covers its own calendar day",
+			dates: []time.Time{
+				time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+			},
+			expectedRequests: []int{2, 1},
+			expectedCosts:    []float64{0.21, 0.50},
+		},
+		{
+			name: "results follow the given order",
+			dates: []time.Time{
+				time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			},
+			expectedRequests: []int{1, 2},
+			expectedCosts:    []float64{0.30, 0.21},
+		},
+		{
+			name:             "day without requests is empty",
+			dates:            []time.Time{time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)},
+			expectedRequests: []int{0},
+			expectedCosts:    []float64{0},
+		},
+		{
+			name:        "no dates is an error",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+			query := usecase.NewGetStatsByDatesQuery(statsQuery, service.NewTimePeriodFactory(time.UTC))
+
+			results, err := query.Execute(context.Background(), usecase.GetStatsByDatesParams{Dates: tt.dates})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(results) != len(tt.dates) {
+				t.Fatalf("expected %d results, got %d", len(tt.dates), len(results))
+			}
+			for i, result := range results {
+				if !result.Date.Equal(tt.dates[i]) {
+					t.Errorf("result %d: expected date %v, got %v", i, tt.dates[i], result.Date)
+				}
+				if result.Stats.TotalRequests() != tt.expectedRequests[i] {
+					t.Errorf("result %d: expected %d requests, got %d", i, tt.expectedRequests[i], result.Stats.TotalRequests())
+				}
+				if diff := result.Stats.TotalCost().Amount() - tt.expectedCosts[i]; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("result %d: expected cost %.2f, got %.2f", i, tt.expectedCosts[i], result.Stats.TotalCost().Amount())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetStatsByGroupQuery aggregates this month's statistics per model, session, day or project
+type GetStatsByGroupQuery struct {
+	repository     APIRequestRepository
+	periodFactory  PeriodFactory
+	negligibleCost entity.Cost
+	modelNames     entity.ModelNameMap
+}
+
+// NewGetStatsByGroupQuery creates a new GetStatsByGroupQuery with the given dependencies
+func NewGetStatsByGroupQuery(repository APIRequestRepository, periodFactory PeriodFactory) *GetStatsByGroupQuery {
+	return &GetStatsByGroupQuery{
+		repository:    repository,
+		periodFactory: periodFactory,
+	}
+}
+
+// SetNegligibleCost moves requests cheaper than the threshold out of their groups into a single
+// entity.NegligibleGroup listed last, so they don't crowd the cost drivers; 0 groups every request
+func (q *GetStatsByGroupQuery) SetNegligibleCost(threshold entity.Cost) {
+	q.negligibleCost = threshold
+}
+
+// SetModelNames groups models by their display name, so models sharing a name are summed together
+func (q *GetStatsByGroupQuery) SetModelNames(modelNames entity.ModelNameMap) {
+	q.modelNames = modelNames
+}
+
+// GetStatsByGroupParams contains the parameters for aggregating statistics per group
+type GetStatsByGroupParams struct {
+	GroupBy      entity.GroupBy
+	Timezone     *time.Location // Timezone days are taken in; nil uses UTC
+	ProjectLabel string         // Label naming the project, required to group by project
+}
+
+// GroupStats pairs a group with the statistics of every request in it
+type GroupStats struct {
+	Group string
+	Stats entity.Stats
+}
+
+// Execute returns one entry per group with requests this month, the most expensive first
+// followed by the negligible requests when a threshold is set
+func (q *GetStatsByGroupQuery) Execute(ctx context.Context, params GetStatsByGroupParams) ([]GroupStats, error) {
+	if params.GroupBy == entity.GroupByProject && params.ProjectLabel == "" {
+		return nil, errors.New("grouping by project requires a project label")
+	}
+
+	timezone := params.Timezone
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	period := q.periodFactory.CreateMonthly()
+	requests, err := q.repository.FindByPeriodWithLimit(period, 0, 0) // No limit for stats calculation
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	requests, negligible := entity.SplitNegligible(requests, q.negligibleCost)
+
+	groups := make(map[string][]entity.APIRequest)
+	for _, req := range requests {
+		key := params.GroupBy.Key(req, timezone, params.ProjectLabel)
+		if params.GroupBy == entity.GroupByModel {
+			key = q.modelNames.DisplayName(req.Model())
+		}
+		groups[key] = append(groups[key], req)
+	}
+
+	results := make([]GroupStats, 0, len(groups))
+	for group, groupRequests := range groups {
+		results = append(results, GroupStats{
+			Group: group,
+			Stats: entity.NewStatsFromRequests(groupRequests, period),
+		})
+	}
+
+	// Most expensive first, ties in group order so the output is stable
+	sort.Slice(results, func(i, j int) bool {
+		costI, costJ := results[i].Stats.TotalCost().Amount(), results[j].Stats.TotalCost().Amount()
+		if costI != costJ {
+			return costI > costJ
+		}
+		return results[i].Group < results[j].Group
+	})
+
+	if len(negligible) > 0 {
+		results = append(results, GroupStats{
+			Group: entity.NegligibleGroup,
+			Stats: entity.NewStatsFromRequests(negligible, period),
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetStatsByGroupQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	monthlyPeriod := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC))
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 0.10).
+			WithLabels(map[string]string{"project": "ccmon"}),
+		testutil.CreateTestAPIRequest("s1", time.Date(2025, 1, 6, 11, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 200, 100, 0.05).
+			WithLabels(map[string]string{"project": "ccmon"}),
+		testutil.CreateTestAPIRequest("s2", time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 300, 100, 0.40),
+		testutil.CreateTestAPIRequest("s3", time.Date(2024, 12, 31, 9, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 400, 200, 9.00), // Last month
+	}
+
+	tests := []struct {
+		name           string
+		groupBy        entity.GroupBy
+		projectLabel   string
+		negligibleCost float64
+		modelNames     entity.ModelNameMap
+		expectedGroups []string
+		expectedCosts  []float64
+		expectError    bool
+	}{
+		{
+			name:           "by model",
+			groupBy:        entity.GroupByModel,
+			expectedGroups: []string{"claude-sonnet-4-20250514", "claude-3-5-haiku-20241022"},
+			expectedCosts:  []float64{0.50, 0.05},
+		},
+		{
+			name:           "by session",
+			groupBy:        entity.GroupBySession,
+			expectedGroups: []string{"s2", "s1"},
+			expectedCosts:  []float64{0.40, 0.15},
+		},
+		{
+			name:           "by day",
+			groupBy:        entity.GroupByDay,
+			expectedGroups: []string{"2025-01-20", "2025-01-06"},
+			expectedCosts:  []float64{0.40, 0.15},
+		},
+		{
+			name:           "by project",
+			groupBy:        entity.GroupByProject,
+			projectLabel:   "project",
+			expectedGroups: []string{entity.NoProjectGroup, "ccmon"},
+			expectedCosts:  []float64{0.40, 0.15},
+		},
+		{
+			name:           "by model display name",
+			groupBy:        entity.GroupByModel,
+			modelNames:     entity.NewModelNameMap(entity.NewModelDisplayName("claude-sonnet-*", "Sonnet 4")),
+			expectedGroups: []string{"Sonnet 4", "claude-3-5-haiku-20241022"},
+			expectedCosts:  []float64{0.50, 0.05},
+		},
+		{
+			name:           "models sharing a display name are summed",
+			groupBy:        entity.GroupByModel,
+			modelNames:     entity.NewModelNameMap(entity.NewModelDisplayName("claude-*", "Claude")),
+			expectedGroups: []string{"Claude"},
+			expectedCosts:  []float64{0.55},
+		},
+		{
+			name:           "display names only apply to models",
+			groupBy:        entity.GroupBySession,
+			modelNames:     entity.NewModelNameMap(entity.NewModelDisplayName("claude-*", "Claude")),
+			expectedGroups: []string{"s2", "s1"},
+			expectedCosts:  []float64{0.40, 0.15},
+		},
+		{
+			name:           "cheap requests grouped last",
+			groupBy:        entity.GroupByModel,
+			negligibleCost: 0.08,
+			expectedGroups: []string{"claude-sonnet-4-20250514", entity.NegligibleGroup},
+			expectedCosts:  []float64{0.50, 0.05},
+		},
+		{
+			name:           "negligible group stays last",
+			groupBy:        entity.GroupBySession,
+			negligibleCost: 0.2,
+			expectedGroups: []string{"s2", entity.NegligibleGroup},
+			expectedCosts:  []float64{0.40, 0.15},
+		},
+		{
+			name:        "by project without a label is an error",
+			groupBy:     entity.GroupByProject,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(requests)
+			query := usecase.NewGetStatsByGroupQuery(repo, &MockPeriodFactory{monthlyPeriod: monthlyPeriod})
+			query.SetNegligibleCost(entity.NewCost(tt.negligibleCost))
+			query.SetModelNames(tt.modelNames)
+
+			results, err := query.Execute(context.Background(), usecase.GetStatsByGroupParams{
+				GroupBy:      tt.groupBy,
+				Timezone:     time.UTC,
+				ProjectLabel: tt.projectLabel,
+			})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(results) != len(tt.expectedGroups) {
+				t.Fatalf("expected %d groups, got %d", len(tt.expectedGroups), len(results))
+			}
+			for i, result := range results {
+				if result.Group != tt.expectedGroups[i] {
+					t.Errorf("group %d: expected %q, got %q", i, tt.expectedGroups[i], result.Group)
+				}
+				if cost := result.Stats.TotalCost().Amount(); cost < tt.expectedCosts[i]-0.0001 || cost > tt.expectedCosts[i]+0.0001 {
+					t.Errorf("group %q: expected cost %.2f, got %.4f", result.Group, tt.expectedCosts[i], cost)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// HoursPerDay is the number of hour-of-day buckets returned by GetStatsByHourOfDayQuery
+const HoursPerDay = 24
+
+// GetStatsByHourOfDayQuery aggregates statistics by local hour of day across several days,
+// so requests at 14:xx on different dates share one bucket
+type GetStatsByHourOfDayQuery struct {
+	repository    APIRequestRepository
+	periodFactory PeriodFactory
+}
+
+// NewGetStatsByHourOfDayQuery creates a new GetStatsByHourOfDayQuery with the given dependencies
+func NewGetStatsByHourOfDayQuery(repository APIRequestRepository, periodFactory PeriodFactory) *GetStatsByHourOfDayQuery {
+	return &GetStatsByHourOfDayQuery{
+		repository:    repository,
+		periodFactory: periodFactory,
+	}
+}
+
+// GetStatsByHourOfDayParams contains the parameters for aggregating statistics by hour of day
+type GetStatsByHourOfDayParams struct {
+	Days     int            // Number of days ending today to aggregate
+	Timezone *time.Location // Timezone the hour is taken in; nil uses UTC
+}
+
+// HourOfDayStats pairs an hour of day (0-23) with the statistics of every request in that hour
+type HourOfDayStats struct {
+	Hour  int
+	Stats entity.Stats
+}
+
+// Execute returns one entry per hour of day, from 0 to 23
+func (q *GetStatsByHourOfDayQuery) Execute(ctx context.Context, params GetStatsByHourOfDayParams) ([]HourOfDayStats, error) {
+	if params.Days <= 0 {
+		return nil, errors.New("days must be positive")
+	}
+
+	timezone := params.Timezone
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	// Extend today's period back to cover the requested number of days
+	today := q.periodFactory.CreateDaily()
+	startAt := today.StartAt().In(timezone).AddDate(0, 0, -(params.Days - 1))
+	period := entity.NewPeriod(startAt, today.EndAt())
+
+	requests, err := q.repository.FindByPeriodWithLimit(period, 0, 0) // No limit for stats calculation
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	buckets := make([][]entity.APIRequest, HoursPerDay)
+	for _, req := range requests {
+		hour := req.Timestamp().In(timezone).Hour()
+		buckets[hour] = append(buckets[hour], req)
+	}
+
+	results := make([]HourOfDayStats, HoursPerDay)
+	for hour, bucket := range buckets {
+		results[hour] = HourOfDayStats{
+			Hour:  hour,
+			Stats: entity.NewStatsFromRequests(bucket, period),
+		}
+	}
+
+	return results, nil
+}
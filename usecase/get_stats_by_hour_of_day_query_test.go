@@ -0,0 +1,93 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetStatsByHourOfDayQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", time.Date(2025, 1, 31, 14, 10, 0, 0, time.UTC), "claude-sonnet-4-20250514", 100, 50, 0.10),
+		testutil.CreateTestAPIRequest("s2", time.Date(2025, 1, 20, 14, 50, 0, 0, time.UTC), "claude-sonnet-4-20250514", 200, 100, 0.20),
+		testutil.CreateTestAPIRequest("s3", time.Date(2025, 1, 25, 3, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 300, 100, 0.05),
+		testutil.CreateTestAPIRequest("s4", time.Date(2024, 12, 1, 14, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 400, 200, 1.00),
+	}
+
+	tests := []struct {
+		name          string
+		days          int
+		timezone      *time.Location
+		expectedHours map[int]float64 // hour -> cost; other hours are empty
+		expectError   bool
+	}{
+		{
+			name:          "requests on different dates share an hour",
+			days:          30,
+			timezone:      time.UTC,
+			expectedHours: map[int]float64{14: 0.30, 3: 0.05},
+		},
+		{
+			name:          "fewer days exclude older requests",
+			days:          7,
+			timezone:      time.UTC,
+			expectedHours: map[int]float64{14: 0.10, 3: 0.05},
+		},
+		{
+			name:          "hours are taken in the configured timezone",
+			days:          30,
+			timezone:      tokyo,
+			expectedHours: map[int]float64{23: 0.30, 12: 0.05},
+		},
+		{
+			name:        "non-positive days is an error",
+			days:        0,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo, _ := testutil.NewMockRepositoryWithData(requests)
+			periodFactory := service.NewTimePeriodFactory(tt.timezone)
+			periodFactory.SetClock(entity.NewFixedClock(now))
+			query := usecase.NewGetStatsByHourOfDayQuery(apiRepo, periodFactory)
+
+			results, err := query.Execute(context.Background(), usecase.GetStatsByHourOfDayParams{Days: tt.days, Timezone: tt.timezone})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(results) != usecase.HoursPerDay {
+				t.Fatalf("expected %d hours, got %d", usecase.HoursPerDay, len(results))
+			}
+			for hour, result := range results {
+				if result.Hour != hour {
+					t.Errorf("expected entry %d to be hour %d, got %d", hour, hour, result.Hour)
+				}
+
+				expected := tt.expectedHours[hour]
+				if cost := result.Stats.TotalCost().Amount(); cost < expected-0.0001 || cost > expected+0.0001 {
+					t.Errorf("hour %d: expected cost %.2f, got %.4f", hour, expected, cost)
+				}
+			}
+		})
+	}
+}
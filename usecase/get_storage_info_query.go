@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetStorageInfoQuery reports the size and contents of the store, e.g. to decide when to prune it
+type GetStorageInfoQuery struct {
+	repository StorageInfoRepository
+}
+
+// NewGetStorageInfoQuery creates a new GetStorageInfoQuery with the given repository
+func NewGetStorageInfoQuery(repository StorageInfoRepository) *GetStorageInfoQuery {
+	return &GetStorageInfoQuery{
+		repository: repository,
+	}
+}
+
+// Execute returns the current state of the store
+func (q *GetStorageInfoQuery) Execute(ctx context.Context) (entity.StorageInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.StorageInfo{}, err
+	}
+
+	info, err := q.repository.GetStorageInfo()
+	if err != nil {
+		return entity.StorageInfo{}, repositoryError(err)
+	}
+	return info, nil
+}
@@ -0,0 +1,69 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// fakeStorageInfoRepository returns a fixed storage description
+type fakeStorageInfoRepository struct {
+	info entity.StorageInfo
+	err  error
+}
+
+func (r *fakeStorageInfoRepository) GetStorageInfo() (entity.StorageInfo, error) {
+	return r.info, r.err
+}
+
+func TestGetStorageInfoQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	oldest := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	newest := time.Date(2025, 7, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		repository      *fakeStorageInfoRepository
+		wantRecords     int
+		wantUnavailable bool
+	}{
+		{
+			name:        "returns the repository's description",
+			repository:  &fakeStorageInfoRepository{info: entity.NewStorageInfo(32768, 120, oldest, newest)},
+			wantRecords: 120,
+		},
+		{
+			name:            "repository failures are unavailable",
+			repository:      &fakeStorageInfoRepository{err: errors.New("disk read failed")},
+			wantUnavailable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			info, err := usecase.NewGetStorageInfoQuery(tt.repository).Execute(context.Background())
+			if tt.wantUnavailable {
+				if !errors.Is(err, usecase.ErrRepositoryUnavailable) {
+					t.Fatalf("Execute() error = %v, want ErrRepositoryUnavailable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if info.Records() != tt.wantRecords {
+				t.Errorf("Records() = %d, want %d", info.Records(), tt.wantRecords)
+			}
+			if !info.OldestAt().Equal(oldest) || !info.NewestAt().Equal(newest) {
+				t.Errorf("range = %v - %v, want %v - %v", info.OldestAt(), info.NewestAt(), oldest, newest)
+			}
+		})
+	}
+}
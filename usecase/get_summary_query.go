@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetSummaryQuery retrieves a compact usage summary for the current day
+type GetSummaryQuery struct {
+	statsQuery     *CalculateStatsQuery
+	planRepository PlanRepository
+	periodFactory  PeriodFactory
+	// schedule adds the cost of its current or latest window, nil leaves it out
+	schedule        *entity.Schedule
+	scheduleFactory SchedulePeriodFactory
+}
+
+// NewGetSummaryQuery creates a new GetSummaryQuery with the given dependencies
+func NewGetSummaryQuery(
+	statsQuery *CalculateStatsQuery,
+	planRepository PlanRepository,
+	periodFactory PeriodFactory,
+) *GetSummaryQuery {
+	return &GetSummaryQuery{
+		statsQuery:     statsQuery,
+		planRepository: planRepository,
+		periodFactory:  periodFactory,
+	}
+}
+
+// SetSchedule adds the cost of the schedule's current or latest window to the summary
+func (q *GetSummaryQuery) SetSchedule(schedule entity.Schedule, periodFactory SchedulePeriodFactory) {
+	q.schedule = &schedule
+	q.scheduleFactory = periodFactory
+}
+
+// GetSummaryParams contains the parameters for the summary query
+type GetSummaryParams struct {
+	Block *entity.Block // Optional, include block time remaining when set
+	Now   time.Time
+}
+
+// Summary contains the daily usage summary
+type Summary struct {
+	DailyCost          entity.Cost
+	DailyBudget        entity.Cost // Zero when no plan is configured
+	DailyPlanUsage     int         // Percentage of the daily budget used
+	DailyPlanPercent   float64     // Unrounded DailyPlanUsage for display with decimals
+	DailyTokens        entity.Token
+	Block              *entity.Block // Block containing Now, nil when block tracking is disabled
+	BlockTimeRemaining time.Duration
+	ScheduleCost       *entity.Cost // Cost of the schedule's current or latest window, nil without a schedule
+}
+
+// Execute retrieves the summary with a single stats query, plus one for the schedule window when set
+func (q *GetSummaryQuery) Execute(ctx context.Context, params GetSummaryParams) (*Summary, error) {
+	// Don't fail the summary if plan is not configured
+	plan, err := configuredPlanOrUnset(q.planRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyPeriod := q.periodFactory.CreateDaily()
+	dailyStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
+		Period: dailyPeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate daily stats: %w", err)
+	}
+
+	dailyPlanPercent := plan.UsagePercentInPeriod(dailyStats.TotalCost(), dailyPeriod)
+	summary := &Summary{
+		DailyCost:        dailyStats.TotalCost(),
+		DailyBudget:      plan.CalculatePeriodBudget(dailyPeriod),
+		DailyPlanUsage:   int(dailyPlanPercent),
+		DailyPlanPercent: dailyPlanPercent,
+		DailyTokens:      dailyStats.TotalTokens(),
+	}
+
+	if q.schedule != nil {
+		scheduleStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
+			Period: q.scheduleFactory.CreateScheduled(*q.schedule),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate schedule stats: %w", err)
+		}
+		scheduleCost := scheduleStats.TotalCost()
+		summary.ScheduleCost = &scheduleCost
+	}
+
+	if params.Block != nil {
+		block := params.Block.NextBlock(params.Now)
+		summary.Block = &block
+		if remaining := block.EndAt().Sub(params.Now); remaining > 0 {
+			summary.BlockTimeRemaining = remaining
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,145 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestGetSummaryQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	dailyPeriod := entity.NewPeriod(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), now)
+	periodFactory := &MockPeriodFactory{dailyPeriod: dailyPeriod}
+
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-2*time.Hour), "claude-3-5-sonnet-20241022", 600_000, 400_000, 0.5),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 150_000, 50_000, 0.5),
+	}
+	block := entity.NewBlock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name              string
+		plan              entity.Plan
+		block             *entity.Block
+		expectedBudget    float64
+		expectedUsage     int
+		expectedRemaining time.Duration
+	}{
+		{
+			name:           "pro plan without block",
+			plan:           entity.NewPlan("pro", entity.NewCost(31.0)),
+			expectedBudget: 1.0,
+			expectedUsage:  100,
+		},
+		{
+			name:              "unset plan with block",
+			plan:              entity.NewPlan("unset", entity.NewCost(0)),
+			block:             &block,
+			expectedBudget:    0,
+			expectedUsage:     0,
+			expectedRemaining: 3 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo, statsRepo, callCount := testutil.NewInstrumentedRepositoryPair()
+			apiRepo.SetMockData(requests)
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+			query := usecase.NewGetSummaryQuery(statsQuery, testutil.NewMockPlanRepository(tt.plan), periodFactory)
+			summary, err := query.Execute(context.Background(), usecase.GetSummaryParams{Block: tt.block, Now: now})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if *callCount != 1 {
+				t.Errorf("expected a single stats call, got %d", *callCount)
+			}
+			if summary.DailyCost.Amount() != 1.0 {
+				t.Errorf("expected daily cost 1.0, got %.2f", summary.DailyCost.Amount())
+			}
+			if summary.DailyBudget.Amount() != tt.expectedBudget {
+				t.Errorf("expected daily budget %.2f, got %.2f", tt.expectedBudget, summary.DailyBudget.Amount())
+			}
+			if summary.DailyPlanUsage != tt.expectedUsage {
+				t.Errorf("expected daily plan usage %d%%, got %d%%", tt.expectedUsage, summary.DailyPlanUsage)
+			}
+			if summary.DailyTokens.Total() != 1_200_000 {
+				t.Errorf("expected 1200000 daily tokens, got %d", summary.DailyTokens.Total())
+			}
+			if (summary.Block != nil) != (tt.block != nil) {
+				t.Errorf("expected block presence %v, got %v", tt.block != nil, summary.Block != nil)
+			}
+			if summary.BlockTimeRemaining != tt.expectedRemaining {
+				t.Errorf("expected %v remaining, got %v", tt.expectedRemaining, summary.BlockTimeRemaining)
+			}
+		})
+	}
+}
+
+func TestGetSummaryQuery_Schedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC) // Wednesday noon
+	newRequest := func(at time.Time, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("test-session", at, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+	requests := []entity.APIRequest{
+		newRequest(time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC), 1.0),   // Today, before the window
+		newRequest(time.Date(2025, 7, 2, 10, 0, 0, 0, time.UTC), 0.25), // Inside the window
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		expected float64
+	}{
+		{name: "without a schedule"},
+		{name: "current window", schedule: "mon-fri 09:00-17:00", expected: 0.25},
+		{name: "latest window before now", schedule: "mon-fri 13:00-17:00", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache())
+			periodFactory := &MockPeriodFactory{dailyPeriod: entity.NewPeriod(time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC), now)}
+
+			query := usecase.NewGetSummaryQuery(statsQuery, testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))), periodFactory)
+			if tt.schedule != "" {
+				schedule, err := entity.ParseSchedule(tt.schedule)
+				if err != nil {
+					t.Fatalf("unexpected schedule error: %v", err)
+				}
+				query.SetSchedule(schedule, fixedSchedulePeriodFactory{now: now})
+			}
+
+			summary, err := query.Execute(context.Background(), usecase.GetSummaryParams{Now: now})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if summary.DailyCost.Amount() != 1.25 {
+				t.Errorf("expected daily cost 1.25, got %.2f", summary.DailyCost.Amount())
+			}
+			if (summary.ScheduleCost != nil) != (tt.schedule != "") {
+				t.Fatalf("expected schedule cost presence %v, got %v", tt.schedule != "", summary.ScheduleCost != nil)
+			}
+			if summary.ScheduleCost != nil && summary.ScheduleCost.Amount() != tt.expected {
+				t.Errorf("expected schedule cost %.2f, got %.2f", tt.expected, summary.ScheduleCost.Amount())
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ import (
 type GetUsageQuery struct {
 	repository    APIRequestRepository
 	periodFactory PeriodFactory
+	clock         entity.Clock
+	asOf          time.Time
 }
 
 // NewGetUsageQuery creates a new GetUsageQuery with the given dependencies
@@ -18,21 +20,37 @@ func NewGetUsageQuery(repository APIRequestRepository, periodFactory PeriodFacto
 	return &GetUsageQuery{
 		repository:    repository,
 		periodFactory: periodFactory,
+		clock:         entity.SystemClock{},
 	}
 }
 
-// ListByDay retrieves usage statistics grouped by daily periods
+// SetClock sets the source of the current time used to align interval buckets
+func (q *GetUsageQuery) SetClock(clock entity.Clock) {
+	q.clock = clock
+}
+
+// SetAsOf leaves out requests after the given time from the usage, to show it as it was then;
+// the zero time keeps all requests
+func (q *GetUsageQuery) SetAsOf(asOf time.Time) {
+	q.asOf = asOf
+}
+
+// ListByDay retrieves usage statistics grouped by daily periods.
+// The timezone should match the period factory; nil uses UTC.
 func (q *GetUsageQuery) ListByDay(ctx context.Context, days int, timezone *time.Location) (entity.Usage, error) {
 	var dailyStats []entity.Stats
+	if timezone == nil {
+		timezone = time.UTC
+	}
 
 	for i := 0; i < days; i++ {
 		// Create historical daily period (today minus i days)
-		period := q.createHistoricalDailyPeriod(i)
+		period := q.createHistoricalDailyPeriod(i, timezone)
 
 		// Get requests for this day using the API request repository
-		requests, err := q.repository.FindByPeriodWithLimit(period, 0, 0) // No limit for stats calculation
+		requests, err := q.repository.FindByPeriodWithLimit(period.ClipEnd(q.asOf), 0, 0) // No limit for stats calculation
 		if err != nil {
-			return entity.Usage{}, err
+			return entity.Usage{}, repositoryError(err)
 		}
 
 		// Calculate stats for this day
@@ -43,19 +61,56 @@ func (q *GetUsageQuery) ListByDay(ctx context.Context, days int, timezone *time.
 	return entity.NewUsage(dailyStats), nil
 }
 
+// ListByInterval retrieves usage statistics grouped into fixed-size buckets ending with
+// the bucket containing the current time, newest first.
+// Buckets are aligned to multiples of interval, so hourly buckets start on the hour.
+func (q *GetUsageQuery) ListByInterval(ctx context.Context, interval time.Duration, count int) (entity.Usage, error) {
+	if interval <= 0 || count <= 0 {
+		return entity.NewUsage(nil), nil
+	}
+
+	currentStart := q.clock.Now().UTC().Truncate(interval)
+	currentEnd := currentStart.Add(interval)
+	rangeStart := currentStart.Add(-time.Duration(count-1) * interval)
+
+	// Fetch the whole range once and distribute requests across buckets
+	requests, err := q.repository.FindByPeriodWithLimit(entity.NewPeriod(rangeStart, currentEnd).ClipEnd(q.asOf), 0, 0)
+	if err != nil {
+		return entity.Usage{}, repositoryError(err)
+	}
+
+	buckets := make([][]entity.APIRequest, count)
+	for _, req := range requests {
+		timestamp := req.Timestamp()
+		if timestamp.Before(rangeStart) || !timestamp.Before(currentEnd) {
+			continue
+		}
+
+		// Bucket i covers [currentStart - i*interval, currentEnd - i*interval)
+		index := int((currentEnd.Sub(timestamp) - 1) / interval)
+		buckets[index] = append(buckets[index], req)
+	}
+
+	stats := make([]entity.Stats, count)
+	for i, bucket := range buckets {
+		startAt := currentStart.Add(-time.Duration(i) * interval)
+		stats[i] = q.calculateStatsFromRequests(bucket, entity.NewPeriod(startAt, startAt.Add(interval)))
+	}
+
+	return entity.NewUsage(stats), nil
+}
+
 // createHistoricalDailyPeriod creates a daily period for i days ago using PeriodFactory
-func (q *GetUsageQuery) createHistoricalDailyPeriod(daysAgo int) entity.Period {
+func (q *GetUsageQuery) createHistoricalDailyPeriod(daysAgo int, timezone *time.Location) entity.Period {
 	// Get today's period from the factory
 	todayPeriod := q.periodFactory.CreateDaily()
 
-	// Calculate the offset for historical days
-	dayOffset := time.Duration(daysAgo) * 24 * time.Hour
-
-	// Create historical period by shifting both start and end times
-	startAt := todayPeriod.StartAt().Add(-dayOffset)
-	endAt := todayPeriod.EndAt().Add(-dayOffset)
+	// Step back by calendar days in the timezone, as a day across a DST change
+	// is 23 or 25 hours long
+	startAt := todayPeriod.StartAt().In(timezone).AddDate(0, 0, -daysAgo)
+	endAt := startAt.AddDate(0, 0, 1).Add(-time.Nanosecond)
 
-	return entity.NewPeriod(startAt, endAt)
+	return entity.NewPeriod(startAt.UTC(), endAt.UTC())
 }
 
 // calculateStatsFromRequests calculates statistics from a list of requests
@@ -171,3 +171,192 @@ func TestGetUsageQuery_ListByDay_NilTimezone(t *testing.T) {
 		t.Errorf("Expected 1 request, got %d", stat.TotalRequests())
 	}
 }
+
+func TestGetUsageQuery_ListByInterval(t *testing.T) {
+	currentHour := time.Now().UTC().Truncate(time.Hour)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		// Current bucket, including its exact start
+		entity.NewAPIRequest("session1", currentHour, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(1.0), 1000),
+		// Two hours ago, including the last instant before the next bucket
+		entity.NewAPIRequest("session2", currentHour.Add(-2*time.Hour), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(2.0), 1000),
+		entity.NewAPIRequest("session3", currentHour.Add(-time.Hour-time.Nanosecond), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.5), 1000),
+		// Outside the requested range
+		entity.NewAPIRequest("session4", currentHour.Add(-3*time.Hour-time.Nanosecond), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(4.0), 1000),
+	})
+	query := NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC))
+
+	usage, err := query.ListByInterval(context.Background(), time.Hour, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := usage.GetStats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 buckets, got %d", len(stats))
+	}
+
+	expectedCosts := []float64{1.0, 0, 2.5}
+	for i, expected := range expectedCosts {
+		if got := stats[i].TotalCost().Amount(); got != expected {
+			t.Errorf("Bucket %d: expected cost %v, got %v", i, expected, got)
+		}
+	}
+	if !stats[2].Period().StartAt().Equal(currentHour.Add(-2 * time.Hour)) {
+		t.Errorf("Expected oldest bucket to start at %v, got %v", currentHour.Add(-2*time.Hour), stats[2].Period().StartAt())
+	}
+}
+
+func TestGetUsageQuery_ListByInterval_Empty(t *testing.T) {
+	query := NewGetUsageQuery(testutil.NewMockAPIRequestRepository(), service.NewTimePeriodFactory(time.UTC))
+
+	usage, err := query.ListByInterval(context.Background(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(usage.GetStats()) != 0 {
+		t.Errorf("Expected no buckets, got %d", len(usage.GetStats()))
+	}
+}
+
+func TestGetUsageQuery_SetClock(t *testing.T) {
+	t.Parallel()
+
+	frozen := time.Date(2025, 7, 1, 10, 30, 0, 0, time.UTC)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("session1", frozen.Add(-10*time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(1.0), 1000),
+		entity.NewAPIRequest("session2", frozen.Add(-24*time.Hour), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(2.0), 1000),
+		// After the frozen time, so never in the current bucket or day
+		entity.NewAPIRequest("session3", frozen.Add(14*time.Hour), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(4.0), 1000),
+	})
+
+	clock := entity.NewFixedClock(frozen)
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	periodFactory.SetClock(clock)
+	query := NewGetUsageQuery(repo, periodFactory)
+	query.SetClock(clock)
+
+	hourly, err := query.ListByInterval(context.Background(), time.Hour, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if start := hourly.GetStats()[0].Period().StartAt(); !start.Equal(time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the current bucket to start at 10:00, got %v", start)
+	}
+	if got := hourly.GetStats()[0].TotalCost().Amount(); got != 1.0 {
+		t.Errorf("Expected current bucket cost 1.0, got %v", got)
+	}
+
+	daily, err := query.ListByDay(context.Background(), 2, time.UTC)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectedCosts := []float64{1.0, 2.0}
+	for i, expected := range expectedCosts {
+		if got := daily.GetStats()[i].TotalCost().Amount(); got != expected {
+			t.Errorf("Day %d: expected cost %v, got %v", i, expected, got)
+		}
+	}
+}
+
+func TestGetUsageQuery_SetAsOf(t *testing.T) {
+	t.Parallel()
+
+	frozen := time.Date(2025, 7, 1, 10, 30, 0, 0, time.UTC)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("session1", frozen.Add(-10*time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(1.0), 1000),
+		// In the current bucket and day, but after the as-of time
+		entity.NewAPIRequest("session2", frozen.Add(10*time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(2.0), 1000),
+	})
+
+	clock := entity.NewFixedClock(frozen)
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	periodFactory.SetClock(clock)
+	query := NewGetUsageQuery(repo, periodFactory)
+	query.SetClock(clock)
+	query.SetAsOf(frozen)
+
+	hourly, err := query.ListByInterval(context.Background(), time.Hour, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := hourly.GetStats()[0].TotalCost().Amount(); got != 1.0 {
+		t.Errorf("Expected current bucket cost 1.0, got %v", got)
+	}
+
+	daily, err := query.ListByDay(context.Background(), 1, time.UTC)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := daily.GetStats()[0].TotalCost().Amount(); got != 1.0 {
+		t.Errorf("Expected today's cost 1.0, got %v", got)
+	}
+}
+
+func TestGetUsageQuery_ListByDay_DaylightSavingTime(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load New York timezone: %v", err)
+	}
+
+	request := func(at time.Time, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("session1", at, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1500)
+	}
+
+	tests := []struct {
+		name         string
+		now          time.Time
+		requests     []entity.APIRequest
+		expectedCost float64 // Cost of yesterday, the DST change day
+	}{
+		{
+			name: "spring forward day excludes the previous evening",
+			now:  time.Date(2025, 3, 10, 12, 0, 0, 0, newYork),
+			requests: []entity.APIRequest{
+				request(time.Date(2025, 3, 8, 23, 30, 0, 0, newYork), 1.0),
+				request(time.Date(2025, 3, 9, 0, 30, 0, 0, newYork), 0.1),
+				request(time.Date(2025, 3, 9, 23, 30, 0, 0, newYork), 0.2),
+			},
+			expectedCost: 0.3,
+		},
+		{
+			name: "fall back day includes its first hour",
+			now:  time.Date(2025, 11, 3, 12, 0, 0, 0, newYork),
+			requests: []entity.APIRequest{
+				request(time.Date(2025, 11, 2, 0, 30, 0, 0, newYork), 0.1),
+				request(time.Date(2025, 11, 2, 23, 30, 0, 0, newYork), 0.2),
+				request(time.Date(2025, 11, 3, 0, 30, 0, 0, newYork), 1.0),
+			},
+			expectedCost: 0.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(tt.requests)
+			periodFactory := service.NewTimePeriodFactory(newYork)
+			periodFactory.SetClock(entity.NewFixedClock(tt.now))
+			query := NewGetUsageQuery(repo, periodFactory)
+
+			usage, err := query.ListByDay(context.Background(), 2, newYork)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			yesterday := usage.GetStats()[1]
+			if diff := yesterday.TotalCost().Amount() - tt.expectedCost; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected the DST day to cost %f, got %f", tt.expectedCost, yesterday.TotalCost().Amount())
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/elct9620/ccmon/entity"
 )
@@ -11,46 +13,189 @@ import (
 type PeriodFactory interface {
 	CreateDaily() entity.Period
 	CreateMonthly() entity.Period
+	CreateRolling(days int) entity.Period // Ends now and starts exactly days×24h before
+}
+
+// SchedulePeriodFactory creates the period of a schedule's window containing now, or the latest one before it
+type SchedulePeriodFactory interface {
+	CreateScheduled(schedule entity.Schedule) entity.Period
 }
 
 // GetUsageVariablesQuery retrieves usage variables for format string substitution
 type GetUsageVariablesQuery struct {
-	statsQuery     *CalculateStatsQuery
-	planRepository PlanRepository
-	periodFactory  PeriodFactory
+	statsQuery      *CalculateStatsQuery
+	sessionsQuery   *CountSessionsQuery
+	planRepository  PlanRepository
+	periodFactory   PeriodFactory
+	costStyle       entity.CostStyle
+	numberLocale    entity.NumberLocale
+	durationStyle   entity.DurationStyle
+	percentDecimals int
+	tokenDecimals   int
+	savingsQuery    *CalculateCacheSavingsQuery
+	gapQuery        *GetLongestGapQuery
+	timezone        *time.Location
+	clock           entity.Clock
+
+	// schedule is the recurring window of @schedule_cost, nil leaves it "-"
+	schedule        *entity.Schedule
+	scheduleFactory SchedulePeriodFactory
+
+	// block is the block of @block_used, @block_limit and the @block_usage family, nil leaves them empty
+	block *entity.Block
+
+	// usedVariables are the keys a format string uses; the rolling windows are only queried when
+	// their variables are among them. Nil queries every variable.
+	usedVariables []string
 }
 
 // NewGetUsageVariablesQuery creates a new GetUsageVariablesQuery with the given dependencies
 func NewGetUsageVariablesQuery(
 	statsQuery *CalculateStatsQuery,
+	sessionsQuery *CountSessionsQuery,
 	planRepository PlanRepository,
 	periodFactory PeriodFactory,
 ) *GetUsageVariablesQuery {
 	return &GetUsageVariablesQuery{
 		statsQuery:     statsQuery,
+		sessionsQuery:  sessionsQuery,
 		planRepository: planRepository,
 		periodFactory:  periodFactory,
+		numberLocale:   entity.DefaultNumberLocale,
+		tokenDecimals:  -1,
+		timezone:       time.UTC,
+		clock:          entity.SystemClock{},
 	}
 }
 
+// SetCostStyle changes the precision of the cost variables (default: one decimal place) and of the
+// block token variables, whole K and M with compact and exact counts with full
+func (q *GetUsageVariablesQuery) SetCostStyle(style entity.CostStyle) {
+	q.costStyle = style
+}
+
+// SetNumberLocale changes the decimal and grouping separators of the cost variables
+func (q *GetUsageVariablesQuery) SetNumberLocale(locale entity.NumberLocale) {
+	q.numberLocale = locale
+}
+
+// SetPercentDecimals sets the decimal places of the plan usage variables (default: whole percent)
+func (q *GetUsageVariablesQuery) SetPercentDecimals(decimals int) {
+	q.percentDecimals = decimals
+}
+
+// SetTokenDecimals sets the decimal places of the block token variables, negative keeps 1 for K and 2 for M
+func (q *GetUsageVariablesQuery) SetTokenDecimals(decimals int) {
+	q.tokenDecimals = decimals
+}
+
+// SetCacheSavingsQuery enables @daily_cache_savings; without configured rates it stays empty
+func (q *GetUsageVariablesQuery) SetCacheSavingsQuery(savingsQuery *CalculateCacheSavingsQuery) {
+	q.savingsQuery = savingsQuery
+}
+
+// SetLongestGapQuery enables @daily_max_gap; without it the variable shows "-"
+func (q *GetUsageVariablesQuery) SetLongestGapQuery(gapQuery *GetLongestGapQuery) {
+	q.gapQuery = gapQuery
+}
+
+// SetSchedule enables @schedule_cost, the cost of the schedule's current or latest window
+func (q *GetUsageVariablesQuery) SetSchedule(schedule entity.Schedule, periodFactory SchedulePeriodFactory) {
+	q.schedule = &schedule
+	q.scheduleFactory = periodFactory
+}
+
+// SetUsedVariables limits the optional queries to the variable keys in use, e.g. "@daily_cost".
+// Variables left out of the keys read as zero; nil queries every variable.
+func (q *GetUsageVariablesQuery) SetUsedVariables(keys []string) {
+	q.usedVariables = keys
+}
+
+// uses reports whether the variable is in use, so its inputs have to be queried
+func (q *GetUsageVariablesQuery) uses(variable entity.UsageVariable) bool {
+	return q.usedVariables == nil || slices.Contains(q.usedVariables, variable.Key())
+}
+
+// SetBlock enables @block_used and @block_limit, the premium tokens counted in the block and its token limit,
+// and @block_usage, @block_remaining and @block_time_remaining, the block's progress against them
+func (q *GetUsageVariablesQuery) SetBlock(block entity.Block) {
+	q.block = &block
+}
+
+// SetDurationStyle changes how @daily_max_gap and @block_time_remaining are rendered, matching monitor.duration_format
+func (q *GetUsageVariablesQuery) SetDurationStyle(style entity.DurationStyle) {
+	q.durationStyle = style
+}
+
+// SetClock sets the source of the current time @block_time_remaining counts down from
+func (q *GetUsageVariablesQuery) SetClock(clock entity.Clock) {
+	q.clock = clock
+}
+
+// SetTimezone sets the timezone @timezone names, the one the period factory computes days in (default: UTC)
+func (q *GetUsageVariablesQuery) SetTimezone(timezone *time.Location) {
+	q.timezone = timezone
+}
+
+// usageInputs are the plan, periods and query results the usage variables are computed from
+type usageInputs struct {
+	plan             entity.Plan
+	dailyPeriod      entity.Period
+	monthlyPeriod    entity.Period
+	dailyStats       entity.Stats
+	monthlyStats     entity.Stats
+	rolling7dPeriod  entity.Period
+	rolling7dStats   entity.Stats
+	rolling30dPeriod entity.Period
+	rolling30dStats  entity.Stats
+	dailySessions    int
+	monthlySessions  int
+	savingsEnabled   bool
+	savings          CacheSavings
+	gapEnabled       bool
+	gap              LongestGap
+	scheduleEnabled  bool
+	schedulePeriod   entity.Period
+	scheduleStats    entity.Stats
+	blockEnabled     bool
+	blockStats       entity.Stats
+}
+
 // Execute retrieves usage variables as a substitution map
 func (q *GetUsageVariablesQuery) Execute(ctx context.Context) (map[string]string, error) {
+	inputs, err := q.collectInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return q.generateVariables(inputs), nil
+}
+
+// ExecuteValues retrieves the usage variables as raw numbers keyed like Execute, for JSON output.
+// Costs are in USD, percentages unrounded, durations in seconds, and values shown as "-" or empty are nil.
+func (q *GetUsageVariablesQuery) ExecuteValues(ctx context.Context) (map[string]any, error) {
+	inputs, err := q.collectInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return q.generateValues(inputs), nil
+}
+
+// collectInputs queries everything the usage variables are computed from
+func (q *GetUsageVariablesQuery) collectInputs(ctx context.Context) (usageInputs, error) {
 	// Check if context is already cancelled
 	if err := ctx.Err(); err != nil {
-		return nil, fmt.Errorf("context cancelled before execution: %w", err)
+		return usageInputs{}, fmt.Errorf("context cancelled before execution: %w", err)
 	}
 
-	// Get configured plan for percentage calculations
-	plan, err := q.planRepository.GetConfiguredPlan()
+	// Get configured plan for percentage calculations, an unavailable plan falls back to unset
+	plan, err := configuredPlanOrUnset(q.planRepository)
 	if err != nil {
-		// Don't fail the entire query if plan is not configured
-		// Use an unset plan as fallback
-		plan = entity.NewPlan("unset", entity.NewCost(0))
+		return usageInputs{}, fmt.Errorf("failed to get plan: %w", err)
 	}
 
 	// Check if context was cancelled while getting plan
 	if err := ctx.Err(); err != nil {
-		return nil, fmt.Errorf("context cancelled while getting plan: %w", err)
+		return usageInputs{}, fmt.Errorf("context cancelled while getting plan: %w", err)
 	}
 
 	// Create periods for daily and monthly calculations
@@ -62,12 +207,12 @@ func (q *GetUsageVariablesQuery) Execute(ctx context.Context) (map[string]string
 		Period: dailyPeriod,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate daily stats: %w", err)
+		return usageInputs{}, fmt.Errorf("failed to calculate daily stats: %w", err)
 	}
 
 	// Check if context was cancelled between stats queries
 	if err := ctx.Err(); err != nil {
-		return nil, fmt.Errorf("context cancelled between stats queries: %w", err)
+		return usageInputs{}, fmt.Errorf("context cancelled between stats queries: %w", err)
 	}
 
 	// Get monthly stats
@@ -75,36 +220,523 @@ func (q *GetUsageVariablesQuery) Execute(ctx context.Context) (map[string]string
 		Period: monthlyPeriod,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate monthly stats: %w", err)
+		return usageInputs{}, fmt.Errorf("failed to calculate monthly stats: %w", err)
+	}
+
+	// Rolling windows end now, unlike the calendar day and month above, and are only queried when used
+	rolling7dPeriod := q.periodFactory.CreateRolling(7)
+	var rolling7dStats entity.Stats
+	if q.uses(entity.Rolling7dCostVariable) {
+		rolling7dStats, err = q.statsQuery.Execute(ctx, CalculateStatsParams{
+			Period: rolling7dPeriod,
+		})
+		if err != nil {
+			return usageInputs{}, fmt.Errorf("failed to calculate rolling 7 day stats: %w", err)
+		}
+	}
+
+	rolling30dPeriod := q.periodFactory.CreateRolling(30)
+	var rolling30dStats entity.Stats
+	if q.uses(entity.Rolling30dCostVariable) {
+		rolling30dStats, err = q.statsQuery.Execute(ctx, CalculateStatsParams{
+			Period: rolling30dPeriod,
+		})
+		if err != nil {
+			return usageInputs{}, fmt.Errorf("failed to calculate rolling 30 day stats: %w", err)
+		}
+	}
+
+	// Check if context was cancelled before session queries
+	if err := ctx.Err(); err != nil {
+		return usageInputs{}, fmt.Errorf("context cancelled before session queries: %w", err)
+	}
+
+	// Count distinct sessions for daily and monthly periods
+	dailySessions, err := q.sessionsQuery.Execute(ctx, CountSessionsParams{
+		Period: dailyPeriod,
+	})
+	if err != nil {
+		return usageInputs{}, fmt.Errorf("failed to count daily sessions: %w", err)
+	}
+
+	monthlySessions, err := q.sessionsQuery.Execute(ctx, CountSessionsParams{
+		Period: monthlyPeriod,
+	})
+	if err != nil {
+		return usageInputs{}, fmt.Errorf("failed to count monthly sessions: %w", err)
+	}
+
+	inputs := usageInputs{
+		plan:             plan,
+		dailyPeriod:      dailyPeriod,
+		monthlyPeriod:    monthlyPeriod,
+		dailyStats:       dailyStats,
+		monthlyStats:     monthlyStats,
+		rolling7dPeriod:  rolling7dPeriod,
+		rolling7dStats:   rolling7dStats,
+		rolling30dPeriod: rolling30dPeriod,
+		rolling30dStats:  rolling30dStats,
+		dailySessions:    dailySessions,
+		monthlySessions:  monthlySessions,
+	}
+
+	// Cache savings are hidden rather than guessed when rates are missing
+	if q.savingsQuery != nil && q.savingsQuery.IsEnabled() {
+		inputs.savingsEnabled = true
+		inputs.savings, err = q.savingsQuery.Execute(ctx, CalculateCacheSavingsParams{
+			Period: dailyPeriod,
+		})
+		if err != nil {
+			return usageInputs{}, fmt.Errorf("failed to calculate daily cache savings: %w", err)
+		}
+	}
+
+	// Longest idle time between today's requests
+	if q.gapQuery != nil {
+		inputs.gapEnabled = true
+		inputs.gap, err = q.gapQuery.Execute(ctx, GetLongestGapParams{
+			Period: dailyPeriod,
+		})
+		if err != nil {
+			return usageInputs{}, fmt.Errorf("failed to calculate daily max gap: %w", err)
+		}
+	}
+
+	// Opt-in recurring window, e.g. working hours
+	if q.schedule != nil {
+		inputs.scheduleEnabled = true
+		inputs.schedulePeriod = q.scheduleFactory.CreateScheduled(*q.schedule)
+		inputs.scheduleStats, err = q.statsQuery.Execute(ctx, CalculateStatsParams{
+			Period: inputs.schedulePeriod,
+		})
+		if err != nil {
+			return usageInputs{}, fmt.Errorf("failed to calculate schedule stats: %w", err)
+		}
 	}
 
-	// Generate the variable map
-	return q.generateVariableMap(plan, dailyStats, monthlyStats), nil
+	// Opt-in block, from --block or monitor.auto_block
+	if q.block != nil {
+		inputs.blockEnabled = true
+		inputs.blockStats, err = q.statsQuery.Execute(ctx, CalculateStatsParams{
+			Period: q.block.Period(),
+		})
+		if err != nil {
+			return usageInputs{}, fmt.Errorf("failed to calculate block stats: %w", err)
+		}
+	}
+
+	return inputs, nil
 }
 
-// generateVariableMap creates the substitution map from stats and plan data
-func (q *GetUsageVariablesQuery) generateVariableMap(
-	plan entity.Plan,
-	dailyStats entity.Stats,
-	monthlyStats entity.Stats,
-) map[string]string {
+// generateVariables creates the substitution map from stats and plan data
+func (q *GetUsageVariablesQuery) generateVariables(inputs usageInputs) map[string]string {
+	plan, dailyStats, monthlyStats := inputs.plan, inputs.dailyStats, inputs.monthlyStats
 	variables := make(map[string]string)
 
 	// Daily cost
 	dailyCost := dailyStats.TotalCost()
-	variables[entity.DailyCostVariable.Key()] = fmt.Sprintf("$%.1f", dailyCost.Amount())
+	variables[entity.DailyCostVariable.Key()] = dailyCost.FormatLocale(q.costStyle, q.numberLocale)
 
 	// Monthly cost
 	monthlyCost := monthlyStats.TotalCost()
-	variables[entity.MonthlyCostVariable.Key()] = fmt.Sprintf("$%.1f", monthlyCost.Amount())
+	variables[entity.MonthlyCostVariable.Key()] = monthlyCost.FormatLocale(q.costStyle, q.numberLocale)
+
+	// Rolling window costs, the last 7 and 30 days up to now
+	variables[entity.Rolling7dCostVariable.Key()] = inputs.rolling7dStats.TotalCost().FormatLocale(q.costStyle, q.numberLocale)
+	variables[entity.Rolling30dCostVariable.Key()] = inputs.rolling30dStats.TotalCost().FormatLocale(q.costStyle, q.numberLocale)
 
 	// Daily plan usage percentage - using entity business logic
-	dailyPercentage := plan.CalculateUsagePercentageInPeriod(dailyCost, dailyStats.Period())
-	variables[entity.DailyPlanUsageVariable.Key()] = fmt.Sprintf("%d%%", dailyPercentage)
+	dailyPercentage := plan.UsagePercentInPeriod(dailyCost, dailyStats.Period())
+	variables[entity.DailyPlanUsageVariable.Key()] = q.numberLocale.FormatPercent(dailyPercentage, q.percentDecimals)
 
 	// Monthly plan usage percentage
-	monthlyPercentage := plan.CalculateUsagePercentage(monthlyCost)
-	variables[entity.MonthlyPlanUsageVariable.Key()] = fmt.Sprintf("%d%%", monthlyPercentage)
+	monthlyPercentage := plan.UsagePercent(monthlyCost)
+	variables[entity.MonthlyPlanUsageVariable.Key()] = q.numberLocale.FormatPercent(monthlyPercentage, q.percentDecimals)
+
+	// Tokens per dollar, "-" when nothing was spent today
+	variables[entity.DailyTokensPerDollarVariable.Key()] = "-"
+	if tokensPerDollar, ok := dailyStats.TokensPerDollar(); ok {
+		variables[entity.DailyTokensPerDollarVariable.Key()] = q.numberLocale.FormatFloat(tokensPerDollar, 0)
+	}
+
+	// Distinct session counts
+	variables[entity.DailySessionsVariable.Key()] = fmt.Sprintf("%d", inputs.dailySessions)
+	variables[entity.MonthlySessionsVariable.Key()] = fmt.Sprintf("%d", inputs.monthlySessions)
+
+	// Average cost of today's sessions in cents, "$0.0" before the first session
+	variables[entity.DailyCostPerSessionVariable.Key()] = entity.NewCost(0).FormatLocale(entity.CostStyleDefault, q.numberLocale)
+	if costPerSession, ok := dailyStats.CostPerSession(inputs.dailySessions); ok {
+		variables[entity.DailyCostPerSessionVariable.Key()] = costPerSession.FormatLocale(entity.CostStyleFull, q.numberLocale)
+	}
+
+	// Cache savings stay empty without rates, the longest gap shows "-" until there are two requests
+	variables[entity.DailyCacheSavingsVariable.Key()] = ""
+	if inputs.savings.Available {
+		variables[entity.DailyCacheSavingsVariable.Key()] = inputs.savings.Amount.FormatLocale(q.costStyle, q.numberLocale)
+	}
+	variables[entity.DailyMaxGapVariable.Key()] = "-"
+	if inputs.gap.Available {
+		variables[entity.DailyMaxGapVariable.Key()] = q.durationStyle.Format(inputs.gap.Duration)
+	}
+
+	// Schedule window cost, "-" without a schedule
+	variables[entity.ScheduleCostVariable.Key()] = "-"
+	if inputs.scheduleEnabled {
+		variables[entity.ScheduleCostVariable.Key()] = inputs.scheduleStats.TotalCost().FormatLocale(q.costStyle, q.numberLocale)
+	}
+
+	// Block tokens against the limit, e.g. "1.2M" of "2M"; "-" without a block or a token limit
+	variables[entity.BlockUsedVariable.Key()] = "-"
+	variables[entity.BlockLimitVariable.Key()] = "-"
+	if inputs.blockEnabled {
+		variables[entity.BlockUsedVariable.Key()] = q.formatTokenCount(q.block.UsedTokens(inputs.blockStats.PremiumTokens()))
+		if q.block.HasLimit() {
+			variables[entity.BlockLimitVariable.Key()] = q.formatTokenCount(int64(q.block.TokenLimit()))
+		}
+	}
+
+	// Cost split by model tier, e.g. Haiku apart from Sonnet and Opus; the bare names cover today
+	variables[entity.DailyBaseCostVariable.Key()] = dailyStats.BaseCost().FormatLocale(q.costStyle, q.numberLocale)
+	variables[entity.DailyPremiumCostVariable.Key()] = dailyStats.PremiumCost().FormatLocale(q.costStyle, q.numberLocale)
+	variables[entity.MonthlyBaseCostVariable.Key()] = monthlyStats.BaseCost().FormatLocale(q.costStyle, q.numberLocale)
+	variables[entity.MonthlyPremiumCostVariable.Key()] = monthlyStats.PremiumCost().FormatLocale(q.costStyle, q.numberLocale)
+	variables[entity.BaseCostVariable.Key()] = variables[entity.DailyBaseCostVariable.Key()]
+	variables[entity.PremiumCostVariable.Key()] = variables[entity.DailyPremiumCostVariable.Key()]
+
+	// Block progress like the monitor's bar; "0%", "0" and empty without a block
+	variables[entity.BlockUsageVariable.Key()] = q.numberLocale.FormatPercent(0, q.percentDecimals)
+	variables[entity.BlockRemainingVariable.Key()] = "0"
+	variables[entity.BlockTimeRemainingVariable.Key()] = ""
+	if inputs.blockEnabled {
+		variables[entity.BlockUsageVariable.Key()] = q.numberLocale.FormatPercent(q.block.CalculateProgress(inputs.blockStats.PremiumTokens()), q.percentDecimals)
+		variables[entity.BlockRemainingVariable.Key()] = q.formatTokenCount(q.blockRemainingTokens(inputs.blockStats))
+		variables[entity.BlockTimeRemainingVariable.Key()] = q.durationStyle.Format(q.blockTimeRemaining())
+	}
+
+	// Timezone name, e.g. "America/New_York", to tell which daily and monthly boundaries apply
+	variables[entity.TimezoneVariable.Key()] = q.timezone.String()
 
 	return variables
 }
+
+// generateValues creates the raw value of every usage variable, the unformatted counterpart of generateVariables
+func (q *GetUsageVariablesQuery) generateValues(inputs usageInputs) map[string]any {
+	plan, dailyStats, monthlyStats := inputs.plan, inputs.dailyStats, inputs.monthlyStats
+	values := map[string]any{
+		entity.DailyCostVariable.Key():            dailyStats.TotalCost().Amount(),
+		entity.MonthlyCostVariable.Key():          monthlyStats.TotalCost().Amount(),
+		entity.Rolling7dCostVariable.Key():        inputs.rolling7dStats.TotalCost().Amount(),
+		entity.Rolling30dCostVariable.Key():       inputs.rolling30dStats.TotalCost().Amount(),
+		entity.DailyPlanUsageVariable.Key():       plan.UsagePercentInPeriod(dailyStats.TotalCost(), dailyStats.Period()),
+		entity.MonthlyPlanUsageVariable.Key():     plan.UsagePercent(monthlyStats.TotalCost()),
+		entity.DailySessionsVariable.Key():        inputs.dailySessions,
+		entity.MonthlySessionsVariable.Key():      inputs.monthlySessions,
+		entity.DailyCostPerSessionVariable.Key():  0.0,
+		entity.DailyTokensPerDollarVariable.Key(): nil,
+		entity.DailyCacheSavingsVariable.Key():    nil,
+		entity.DailyMaxGapVariable.Key():          nil,
+		entity.ScheduleCostVariable.Key():         nil,
+		entity.BlockUsedVariable.Key():            nil,
+		entity.BlockLimitVariable.Key():           nil,
+		entity.BlockUsageVariable.Key():           0.0,
+		entity.BlockRemainingVariable.Key():       int64(0),
+		entity.BlockTimeRemainingVariable.Key():   nil,
+		entity.DailyBaseCostVariable.Key():        dailyStats.BaseCost().Amount(),
+		entity.DailyPremiumCostVariable.Key():     dailyStats.PremiumCost().Amount(),
+		entity.MonthlyBaseCostVariable.Key():      monthlyStats.BaseCost().Amount(),
+		entity.MonthlyPremiumCostVariable.Key():   monthlyStats.PremiumCost().Amount(),
+		entity.BaseCostVariable.Key():             dailyStats.BaseCost().Amount(),
+		entity.PremiumCostVariable.Key():          dailyStats.PremiumCost().Amount(),
+		entity.TimezoneVariable.Key():             q.timezone.String(),
+	}
+
+	if costPerSession, ok := dailyStats.CostPerSession(inputs.dailySessions); ok {
+		values[entity.DailyCostPerSessionVariable.Key()] = costPerSession.Amount()
+	}
+	if tokensPerDollar, ok := dailyStats.TokensPerDollar(); ok {
+		values[entity.DailyTokensPerDollarVariable.Key()] = tokensPerDollar
+	}
+	if inputs.savings.Available {
+		values[entity.DailyCacheSavingsVariable.Key()] = inputs.savings.Amount.Amount()
+	}
+	if inputs.gap.Available {
+		values[entity.DailyMaxGapVariable.Key()] = inputs.gap.Duration.Seconds()
+	}
+	if inputs.scheduleEnabled {
+		values[entity.ScheduleCostVariable.Key()] = inputs.scheduleStats.TotalCost().Amount()
+	}
+	if inputs.blockEnabled {
+		values[entity.BlockUsedVariable.Key()] = q.block.UsedTokens(inputs.blockStats.PremiumTokens())
+		if q.block.HasLimit() {
+			values[entity.BlockLimitVariable.Key()] = int64(q.block.TokenLimit())
+		}
+		values[entity.BlockUsageVariable.Key()] = q.block.CalculateProgress(inputs.blockStats.PremiumTokens())
+		values[entity.BlockRemainingVariable.Key()] = q.blockRemainingTokens(inputs.blockStats)
+		values[entity.BlockTimeRemainingVariable.Key()] = q.blockTimeRemaining().Seconds()
+	}
+
+	return values
+}
+
+// blockRemainingTokens returns the tokens left before the block limit, 0 without a limit or once exceeded
+func (q *GetUsageVariablesQuery) blockRemainingTokens(blockStats entity.Stats) int64 {
+	if !q.block.HasLimit() {
+		return 0
+	}
+	return max(int64(q.block.TokenLimit())-q.block.UsedTokens(blockStats.PremiumTokens()), 0)
+}
+
+// blockTimeRemaining returns the time until the block ends, 0 once it has ended
+func (q *GetUsageVariablesQuery) blockTimeRemaining() time.Duration {
+	return max(q.block.EndAt().Sub(q.clock.Now()), 0)
+}
+
+// formatTokenCount abbreviates a token count like the monitor, e.g. 1.5K or 1.50M with the default precision.
+// The compact and full cost styles take precedence over the token decimals.
+func (q *GetUsageVariablesQuery) formatTokenCount(tokens int64) string {
+	switch q.costStyle {
+	case entity.CostStyleCompact:
+		return q.numberLocale.FormatTokenCount(tokens, 0, 0)
+	case entity.CostStyleFull:
+		return q.numberLocale.FormatFloat(float64(tokens), 0)
+	}
+	if q.tokenDecimals < 0 {
+		return q.numberLocale.FormatTokenCount(tokens, 1, 2)
+	}
+	return q.numberLocale.FormatTokenCount(tokens, q.tokenDecimals, q.tokenDecimals)
+}
+
+// UsageVariableExplanation shows how one usage variable was computed
+type UsageVariableExplanation struct {
+	Variable entity.UsageVariable
+	Value    string             // Value substituted into the format string
+	Inputs   []ExplanationInput // Intermediate values in calculation order
+}
+
+// ExplanationInput is one named intermediate value of a calculation
+type ExplanationInput struct {
+	Name  string
+	Value string
+}
+
+// Explain computes the usage variables like Execute and returns, for every variable, the periods,
+// query results and formula steps behind its value
+func (q *GetUsageVariablesQuery) Explain(ctx context.Context) ([]UsageVariableExplanation, error) {
+	inputs, err := q.collectInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	variables := q.generateVariables(inputs)
+
+	dailyCost := inputs.dailyStats.TotalCost()
+	monthlyCost := inputs.monthlyStats.TotalCost()
+	// The same period @daily_plan_usage divides by, the month of its start
+	daysInMonth := entity.DaysInMonth(inputs.dailyStats.Period().StartAt())
+	dailyBudget := inputs.plan.CalculatePeriodBudget(inputs.dailyStats.Period())
+	planPriced := inputs.plan.IsValid() && inputs.plan.Price().Amount() > 0
+
+	explanations := make([]UsageVariableExplanation, 0, len(entity.GetAllUsageVariables()))
+	for _, variable := range entity.GetAllUsageVariables() {
+		var details []ExplanationInput
+		switch variable {
+		case entity.DailyCostVariable:
+			details = explainCost("Daily cost", inputs.dailyPeriod, inputs.dailyStats)
+		case entity.MonthlyCostVariable:
+			details = explainCost("Monthly cost", inputs.monthlyPeriod, inputs.monthlyStats)
+		case entity.Rolling7dCostVariable:
+			details = explainCost("Rolling 7 day cost", inputs.rolling7dPeriod, inputs.rolling7dStats)
+		case entity.Rolling30dCostVariable:
+			details = explainCost("Rolling 30 day cost", inputs.rolling30dPeriod, inputs.rolling30dStats)
+		case entity.ScheduleCostVariable:
+			if !inputs.scheduleEnabled {
+				details = []ExplanationInput{{Name: "Schedule", Value: "- without monitor.schedule or --schedule"}}
+				break
+			}
+			details = append([]ExplanationInput{{Name: "Schedule", Value: q.schedule.String()}}, explainCost("Schedule cost", inputs.schedulePeriod, inputs.scheduleStats)...)
+		case entity.BlockUsedVariable:
+			if !inputs.blockEnabled {
+				details = []ExplanationInput{{Name: "Block", Value: "- without --block or monitor.auto_block"}}
+				break
+			}
+			details = []ExplanationInput{
+				{Name: "Period", Value: explainPeriod(q.block.Period())},
+				{Name: "Premium tokens", Value: fmt.Sprintf("%d %s", q.block.UsedTokens(inputs.blockStats.PremiumTokens()), q.block.TokenMetric())},
+			}
+		case entity.BlockLimitVariable:
+			switch {
+			case !inputs.blockEnabled:
+				details = []ExplanationInput{{Name: "Block", Value: "- without --block or monitor.auto_block"}}
+			case !q.block.HasLimit():
+				details = []ExplanationInput{{Name: "Token limit", Value: "- without claude.plan or claude.max_tokens"}}
+			default:
+				details = []ExplanationInput{{Name: "Token limit", Value: fmt.Sprintf("%d", q.block.TokenLimit())}}
+			}
+		case entity.BaseCostVariable, entity.DailyBaseCostVariable:
+			details = explainTierCost("Base cost", inputs.dailyPeriod, inputs.dailyStats.BaseRequests(), inputs.dailyStats.BaseCost())
+		case entity.PremiumCostVariable, entity.DailyPremiumCostVariable:
+			details = explainTierCost("Premium cost", inputs.dailyPeriod, inputs.dailyStats.PremiumRequests(), inputs.dailyStats.PremiumCost())
+		case entity.MonthlyBaseCostVariable:
+			details = explainTierCost("Base cost", inputs.monthlyPeriod, inputs.monthlyStats.BaseRequests(), inputs.monthlyStats.BaseCost())
+		case entity.MonthlyPremiumCostVariable:
+			details = explainTierCost("Premium cost", inputs.monthlyPeriod, inputs.monthlyStats.PremiumRequests(), inputs.monthlyStats.PremiumCost())
+		case entity.BlockUsageVariable, entity.BlockRemainingVariable:
+			switch {
+			case !inputs.blockEnabled:
+				details = []ExplanationInput{{Name: "Block", Value: "- without --block or monitor.auto_block"}}
+			case !q.block.HasLimit():
+				details = []ExplanationInput{{Name: "Token limit", Value: "- without claude.plan or claude.max_tokens"}}
+			default:
+				used := q.block.UsedTokens(inputs.blockStats.PremiumTokens())
+				details = []ExplanationInput{
+					{Name: "Period", Value: explainPeriod(q.block.Period())},
+					{Name: "Premium tokens", Value: fmt.Sprintf("%d %s", used, q.block.TokenMetric())},
+					{Name: "Token limit", Value: fmt.Sprintf("%d", q.block.TokenLimit())},
+				}
+				if variable == entity.BlockUsageVariable {
+					details = append(details, ExplanationInput{Name: "Usage", Value: fmt.Sprintf("%d / %d × 100 = %s (rounded down)", used, q.block.TokenLimit(), variables[variable.Key()])})
+				} else {
+					details = append(details, ExplanationInput{Name: "Remaining", Value: fmt.Sprintf("%d − %d = %d (at least 0)", q.block.TokenLimit(), used, q.blockRemainingTokens(inputs.blockStats))})
+				}
+			}
+		case entity.BlockTimeRemainingVariable:
+			if !inputs.blockEnabled {
+				details = []ExplanationInput{{Name: "Block", Value: "empty without --block or monitor.auto_block"}}
+				break
+			}
+			details = []ExplanationInput{
+				{Name: "Block ends", Value: q.block.EndAt().UTC().Format(time.RFC3339)},
+				{Name: "Now", Value: q.clock.Now().UTC().Format(time.RFC3339)},
+				{Name: "Remaining", Value: q.blockTimeRemaining().String()},
+			}
+		case entity.DailyPlanUsageVariable:
+			details = []ExplanationInput{
+				{Name: "Period", Value: explainPeriod(inputs.dailyPeriod)},
+				{Name: "Daily cost", Value: explainAmount(dailyCost)},
+				{Name: "Plan", Value: explainPlan(inputs.plan)},
+				{Name: "Days in month", Value: fmt.Sprintf("%d", daysInMonth)},
+			}
+			if planPriced {
+				details = append(details,
+					ExplanationInput{Name: "Daily budget", Value: fmt.Sprintf("%s / %d = %s", explainAmount(inputs.plan.Price()), daysInMonth, explainAmount(dailyBudget))},
+					ExplanationInput{Name: "Usage", Value: fmt.Sprintf("%s / %s × 100 = %s (rounded down)", explainAmount(dailyCost), explainAmount(dailyBudget), variables[variable.Key()])},
+				)
+			} else {
+				details = append(details, ExplanationInput{Name: "Usage", Value: "0% without a priced plan (set claude.plan)"})
+			}
+		case entity.MonthlyPlanUsageVariable:
+			details = []ExplanationInput{
+				{Name: "Period", Value: explainPeriod(inputs.monthlyPeriod)},
+				{Name: "Monthly cost", Value: explainAmount(monthlyCost)},
+				{Name: "Plan", Value: explainPlan(inputs.plan)},
+			}
+			if planPriced {
+				details = append(details, ExplanationInput{Name: "Usage", Value: fmt.Sprintf("%s / %s × 100 = %s (rounded down)", explainAmount(monthlyCost), explainAmount(inputs.plan.Price()), variables[variable.Key()])})
+			} else {
+				details = append(details, ExplanationInput{Name: "Usage", Value: "0% without a priced plan (set claude.plan)"})
+			}
+		case entity.DailySessionsVariable:
+			details = []ExplanationInput{
+				{Name: "Period", Value: explainPeriod(inputs.dailyPeriod)},
+				{Name: "Distinct sessions", Value: fmt.Sprintf("%d", inputs.dailySessions)},
+			}
+		case entity.MonthlySessionsVariable:
+			details = []ExplanationInput{
+				{Name: "Period", Value: explainPeriod(inputs.monthlyPeriod)},
+				{Name: "Distinct sessions", Value: fmt.Sprintf("%d", inputs.monthlySessions)},
+			}
+		case entity.DailyCacheSavingsVariable:
+			details = []ExplanationInput{{Name: "Period", Value: explainPeriod(inputs.dailyPeriod)}}
+			switch {
+			case !inputs.savingsEnabled:
+				details = append(details, ExplanationInput{Name: "Savings", Value: "empty without claude.rates"})
+			case !inputs.savings.Available:
+				details = append(details, ExplanationInput{Name: "Savings", Value: "empty, cache reads of a model without rates"})
+			default:
+				details = append(details, ExplanationInput{Name: "Savings", Value: explainAmount(inputs.savings.Amount)})
+			}
+		case entity.DailyTokensPerDollarVariable:
+			details = []ExplanationInput{
+				{Name: "Period", Value: explainPeriod(inputs.dailyPeriod)},
+				{Name: "Total tokens", Value: fmt.Sprintf("%d", inputs.dailyStats.TotalTokens().Total())},
+				{Name: "Daily cost", Value: explainAmount(dailyCost)},
+			}
+			if tokensPerDollar, ok := inputs.dailyStats.TokensPerDollar(); ok {
+				details = append(details, ExplanationInput{Name: "Tokens per dollar", Value: fmt.Sprintf("%d / %s = %.0f", inputs.dailyStats.TotalTokens().Total(), explainAmount(dailyCost), tokensPerDollar)})
+			} else {
+				details = append(details, ExplanationInput{Name: "Tokens per dollar", Value: "- while nothing was spent"})
+			}
+		case entity.DailyCostPerSessionVariable:
+			details = []ExplanationInput{
+				{Name: "Period", Value: explainPeriod(inputs.dailyPeriod)},
+				{Name: "Daily cost", Value: explainAmount(dailyCost)},
+				{Name: "Distinct sessions", Value: fmt.Sprintf("%d", inputs.dailySessions)},
+			}
+			if costPerSession, ok := inputs.dailyStats.CostPerSession(inputs.dailySessions); ok {
+				details = append(details, ExplanationInput{Name: "Cost per session", Value: fmt.Sprintf("%s / %d = %s", explainAmount(dailyCost), inputs.dailySessions, explainAmount(costPerSession))})
+			} else {
+				details = append(details, ExplanationInput{Name: "Cost per session", Value: "$0.0 without sessions"})
+			}
+		case entity.DailyMaxGapVariable:
+			details = []ExplanationInput{{Name: "Period", Value: explainPeriod(inputs.dailyPeriod)}}
+			switch {
+			case !inputs.gapEnabled:
+				details = append(details, ExplanationInput{Name: "Longest gap", Value: "- as gaps are not calculated here"})
+			case !inputs.gap.Available:
+				details = append(details, ExplanationInput{Name: "Longest gap", Value: "- with fewer than two requests"})
+			default:
+				details = append(details, ExplanationInput{Name: "Longest gap", Value: inputs.gap.Duration.String()})
+			}
+		case entity.TimezoneVariable:
+			details = []ExplanationInput{
+				{Name: "Source", Value: "monitor.timezone"},
+				{Name: "Day starts", Value: inputs.dailyPeriod.StartAt().In(q.timezone).Format(time.RFC3339)},
+			}
+		}
+
+		explanations = append(explanations, UsageVariableExplanation{
+			Variable: variable,
+			Value:    variables[variable.Key()],
+			Inputs:   details,
+		})
+	}
+
+	return explanations, nil
+}
+
+// explainCost lists the period, request counts and tier costs behind a cost variable
+func explainCost(name string, period entity.Period, stats entity.Stats) []ExplanationInput {
+	return []ExplanationInput{
+		{Name: "Period", Value: explainPeriod(period)},
+		{Name: "Requests", Value: fmt.Sprintf("%d base, %d premium", stats.BaseRequests(), stats.PremiumRequests())},
+		{Name: "Base cost", Value: explainAmount(stats.BaseCost())},
+		{Name: "Premium cost", Value: explainAmount(stats.PremiumCost())},
+		{Name: name, Value: explainAmount(stats.TotalCost())},
+	}
+}
+
+// explainTierCost lists the period and request count behind the cost of one model tier
+func explainTierCost(name string, period entity.Period, requests int, cost entity.Cost) []ExplanationInput {
+	return []ExplanationInput{
+		{Name: "Period", Value: explainPeriod(period)},
+		{Name: "Requests", Value: fmt.Sprintf("%d", requests)},
+		{Name: name, Value: explainAmount(cost)},
+	}
+}
+
+// explainPeriod shows the period boundaries in UTC, as they are queried
+func explainPeriod(period entity.Period) string {
+	return period.StartAt().UTC().Format(time.RFC3339) + " to " + period.EndAt().UTC().Format(time.RFC3339)
+}
+
+// explainAmount shows a cost with enough decimals to follow the formulas
+func explainAmount(cost entity.Cost) string {
+	return fmt.Sprintf("$%.4f", cost.Amount())
+}
+
+// explainPlan shows the plan name and monthly price
+func explainPlan(plan entity.Plan) string {
+	if !plan.IsValid() || plan.Price().Amount() == 0 {
+		return plan.Name() + " (no price)"
+	}
+	return fmt.Sprintf("%s (%s/month)", plan.Name(), explainAmount(plan.Price()))
+}
@@ -26,6 +26,11 @@ func (m *MockPeriodFactory) CreateMonthly() entity.Period {
 	return m.monthlyPeriod
 }
 
+// CreateRolling returns the monthly period, so rolling windows see the monthly requests
+func (m *MockPeriodFactory) CreateRolling(days int) entity.Period {
+	return m.monthlyPeriod
+}
+
 // Helper function to calculate expected daily usage percentage based on current month
 func calculateExpectedDailyUsage(dailyCost, planPrice float64) string {
 	now := time.Now()
@@ -88,6 +93,8 @@ func TestGetUsageVariablesQuery_Execute(t *testing.T) {
 		dailyRequests   []entity.APIRequest
 		monthlyRequests []entity.APIRequest
 		statsErr        error
+		costStyle       entity.CostStyle
+		locale          string
 		expectedVars    map[string]string
 		expectedErr     bool
 	}{
@@ -97,10 +104,31 @@ func TestGetUsageVariablesQuery_Execute(t *testing.T) {
 			dailyRequests:   createAPIRequests(5, 3, 0.5, 0.5),     // $1.0 total daily cost
 			monthlyRequests: createAPIRequests(50, 30, 50.0, 90.0), // $140.0 total monthly cost
 			expectedVars: map[string]string{
-				"@daily_cost":         "$1.0",
-				"@monthly_cost":       "$140.0",
-				"@daily_plan_usage":   calculateExpectedDailyUsage(1.0, 20.0), // Calculate based on current month
-				"@monthly_plan_usage": "700%",                                 // (140/20)*100 = 700%
+				"@daily_cost":              "$1.0",
+				"@monthly_cost":            "$140.0",
+				"@daily_plan_usage":        calculateExpectedDailyUsage(1.0, 20.0), // Calculate based on current month
+				"@monthly_plan_usage":      "700%",                                 // (140/20)*100 = 700%
+				"@daily_sessions":          "1",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "5298",
+				"@daily_cost_per_session":  "$1.00",
+				"@daily_max_gap":           "-", // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$140.0", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$140.0",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0.5",
+				"@premium_cost":            "$0.5",
+				"@daily_base_cost":         "$0.5",
+				"@daily_premium_cost":      "$0.5",
+				"@monthly_base_cost":       "$50.0",
+				"@monthly_premium_cost":    "$90.0",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
 			},
 		},
 		{
@@ -109,24 +137,239 @@ func TestGetUsageVariablesQuery_Execute(t *testing.T) {
 			dailyRequests:   createAPIRequests(5, 3, 0.5, 0.5),     // $1.0 total daily cost
 			monthlyRequests: createAPIRequests(50, 30, 50.0, 90.0), // $140.0 total monthly cost
 			expectedVars: map[string]string{
-				"@daily_cost":         "$1.0",
-				"@monthly_cost":       "$140.0",
-				"@daily_plan_usage":   "0%", // unset plan always returns 0%
-				"@monthly_plan_usage": "0%", // unset plan always returns 0%
+				"@daily_cost":              "$1.0",
+				"@monthly_cost":            "$140.0",
+				"@daily_plan_usage":        "0%", // unset plan always returns 0%
+				"@monthly_plan_usage":      "0%", // unset plan always returns 0%
+				"@daily_sessions":          "1",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "5298",
+				"@daily_cost_per_session":  "$1.00",
+				"@daily_max_gap":           "-", // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$140.0", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$140.0",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0.5",
+				"@premium_cost":            "$0.5",
+				"@daily_base_cost":         "$0.5",
+				"@daily_premium_cost":      "$0.5",
+				"@monthly_base_cost":       "$50.0",
+				"@monthly_premium_cost":    "$90.0",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
 			},
 		},
 		{
-			name:            "plan repository error - fallback to unset",
-			planErr:         errors.New("failed to get plan"),
+			name:            "plan unavailable - fallback to unset",
+			planErr:         fmt.Errorf("failed to get plan: %w", usecase.ErrPlanUnavailable),
 			dailyRequests:   createAPIRequests(5, 3, 0.5, 0.5),     // $1.0 total daily cost
 			monthlyRequests: createAPIRequests(50, 30, 50.0, 90.0), // $140.0 total monthly cost
 			expectedVars: map[string]string{
-				"@daily_cost":         "$1.0",
-				"@monthly_cost":       "$140.0",
-				"@daily_plan_usage":   "0%", // fallback to unset plan always returns 0%
-				"@monthly_plan_usage": "0%", // fallback to unset plan always returns 0%
+				"@daily_cost":              "$1.0",
+				"@monthly_cost":            "$140.0",
+				"@daily_plan_usage":        "0%", // fallback to unset plan always returns 0%
+				"@monthly_plan_usage":      "0%", // fallback to unset plan always returns 0%
+				"@daily_sessions":          "1",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "5298",
+				"@daily_cost_per_session":  "$1.00",
+				"@daily_max_gap":           "-", // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$140.0", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$140.0",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0.5",
+				"@premium_cost":            "$0.5",
+				"@daily_base_cost":         "$0.5",
+				"@daily_premium_cost":      "$0.5",
+				"@monthly_base_cost":       "$50.0",
+				"@monthly_premium_cost":    "$90.0",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
+			},
+		},
+		{
+			name:            "compact cost style drops decimals",
+			plan:            entity.NewPlan("unset", entity.NewCost(0)),
+			dailyRequests:   createAPIRequests(1, 1, 0.01, 0.99),  // $1.00 total daily cost
+			monthlyRequests: createAPIRequests(1, 1, 0.01, 15.02), // $15.03 total monthly cost
+			costStyle:       entity.CostStyleCompact,
+			expectedVars: map[string]string{
+				"@daily_cost":              "$1",
+				"@monthly_cost":            "$15",
+				"@daily_plan_usage":        "0%",
+				"@monthly_plan_usage":      "0%",
+				"@daily_sessions":          "1",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "1526",
+				"@daily_cost_per_session":  "$1.00",
+				"@daily_max_gap":           "-", // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$15", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$15",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0",
+				"@premium_cost":            "$1",
+				"@daily_base_cost":         "$0",
+				"@daily_premium_cost":      "$1",
+				"@monthly_base_cost":       "$0",
+				"@monthly_premium_cost":    "$15",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
+			},
+		},
+		{
+			name:            "full cost style keeps cents",
+			plan:            entity.NewPlan("unset", entity.NewCost(0)),
+			dailyRequests:   createAPIRequests(1, 1, 0.01, 0.99),  // $1.00 total daily cost
+			monthlyRequests: createAPIRequests(1, 1, 0.01, 15.02), // $15.03 total monthly cost
+			costStyle:       entity.CostStyleFull,
+			expectedVars: map[string]string{
+				"@daily_cost":              "$1.00",
+				"@monthly_cost":            "$15.03",
+				"@daily_plan_usage":        "0%",
+				"@monthly_plan_usage":      "0%",
+				"@daily_sessions":          "1",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "1526",
+				"@daily_cost_per_session":  "$1.00",
+				"@daily_max_gap":           "-", // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$15.03", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$15.03",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0.01",
+				"@premium_cost":            "$0.99",
+				"@daily_base_cost":         "$0.01",
+				"@daily_premium_cost":      "$0.99",
+				"@monthly_base_cost":       "$0.01",
+				"@monthly_premium_cost":    "$15.02",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
+			},
+		},
+		{
+			name:            "locale separators with grouping",
+			plan:            entity.NewPlan("unset", entity.NewCost(0)),
+			dailyRequests:   createAPIRequests(1, 1, 0.01, 0.99),    // $1.00 total daily cost
+			monthlyRequests: createAPIRequests(1, 1, 0.01, 1234.49), // $1234.50 total monthly cost
+			costStyle:       entity.CostStyleFull,
+			locale:          "de-DE",
+			expectedVars: map[string]string{
+				"@daily_cost":              "$1,00",
+				"@monthly_cost":            "$1.234,50",
+				"@daily_plan_usage":        "0%",
+				"@monthly_plan_usage":      "0%",
+				"@daily_sessions":          "1",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "1.526",
+				"@daily_cost_per_session":  "$1,00",
+				"@daily_max_gap":           "-", // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$1.234,50", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$1.234,50",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0,01",
+				"@premium_cost":            "$0,99",
+				"@daily_base_cost":         "$0,01",
+				"@daily_premium_cost":      "$0,99",
+				"@monthly_base_cost":       "$0,01",
+				"@monthly_premium_cost":    "$1.234,49",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
+			},
+		},
+		{
+			name:            "zero cost has no tokens per dollar",
+			plan:            entity.NewPlan("unset", entity.NewCost(0)),
+			dailyRequests:   createAPIRequests(1, 1, 0, 0),
+			monthlyRequests: createAPIRequests(1, 1, 0, 0),
+			expectedVars: map[string]string{
+				"@daily_cost":              "$0.0",
+				"@monthly_cost":            "$0.0",
+				"@daily_plan_usage":        "0%",
+				"@monthly_plan_usage":      "0%",
+				"@daily_sessions":          "1",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "-",
+				"@daily_cost_per_session":  "$0.00",
+				"@daily_max_gap":           "-", // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$0.0", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$0.0",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0.0",
+				"@premium_cost":            "$0.0",
+				"@daily_base_cost":         "$0.0",
+				"@daily_premium_cost":      "$0.0",
+				"@monthly_base_cost":       "$0.0",
+				"@monthly_premium_cost":    "$0.0",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
+			},
+		},
+		{
+			name:            "no sessions today",
+			plan:            entity.NewPlan("unset", entity.NewCost(0)),
+			monthlyRequests: createAPIRequests(1, 1, 0.01, 0.99),
+			expectedVars: map[string]string{
+				"@daily_cost":              "$0.0",
+				"@monthly_cost":            "$1.0",
+				"@daily_plan_usage":        "0%",
+				"@monthly_plan_usage":      "0%",
+				"@daily_sessions":          "0",
+				"@monthly_sessions":        "1",
+				"@daily_cache_savings":     "", // hidden without rates
+				"@daily_tokens_per_dollar": "-",
+				"@daily_cost_per_session":  "$0.0", // guarded against dividing by zero sessions
+				"@daily_max_gap":           "-",    // no gap query
+				"@timezone":                "UTC",
+				"@rolling_7d_cost":         "$1.0", // rolling windows see the monthly requests
+				"@rolling_30d_cost":        "$1.0",
+				"@schedule_cost":           "-", // no schedule
+				"@block_used":              "-", // no block
+				"@block_limit":             "-", // no block
+				"@base_cost":               "$0.0",
+				"@premium_cost":            "$0.0",
+				"@daily_base_cost":         "$0.0",
+				"@daily_premium_cost":      "$0.0",
+				"@monthly_base_cost":       "$0.0",
+				"@monthly_premium_cost":    "$1.0",
+				"@block_usage":             "0%",
+				"@block_remaining":         "0",
+				"@block_time_remaining":    "",
 			},
 		},
+		{
+			name:        "plan repository failure",
+			planErr:     errors.New("failed to read plans"),
+			expectedErr: true,
+		},
 		{
 			name:        "stats query error",
 			plan:        entity.NewPlan("pro", entity.NewCost(20.0)),
@@ -158,13 +401,23 @@ func TestGetUsageVariablesQuery_Execute(t *testing.T) {
 			noOpCache := testutil.NewNoOpStatsCache()
 			// Real CalculateStatsQuery with mock repository
 			statsQuery := usecase.NewCalculateStatsQuery(mockRepo, noOpCache)
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
 
 			// Create query
 			query := usecase.NewGetUsageVariablesQuery(
 				statsQuery,
+				sessionsQuery,
 				mockPlanRepo,
 				mockPeriodFactory,
 			)
+			query.SetCostStyle(tt.costStyle)
+			if tt.locale != "" {
+				locale, err := entity.ParseNumberLocale(tt.locale)
+				if err != nil {
+					t.Fatalf("unexpected locale error: %v", err)
+				}
+				query.SetNumberLocale(locale.WithGrouping(true))
+			}
 
 			// Execute
 			vars, err := query.Execute(context.Background())
@@ -317,9 +570,11 @@ func TestGetUsageVariablesQuery_CurrentMonthCalculation(t *testing.T) {
 
 			noOpCache := testutil.NewNoOpStatsCache()
 			statsQuery := usecase.NewCalculateStatsQuery(mockRepo, noOpCache)
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
 
 			query := usecase.NewGetUsageVariablesQuery(
 				statsQuery,
+				sessionsQuery,
 				mockPlanRepo,
 				mockPeriodFactory,
 			)
@@ -352,3 +607,722 @@ func TestGetUsageVariablesQuery_CurrentMonthCalculation(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUsageVariablesQuery_CacheSavings(t *testing.T) {
+	now := time.Now()
+	dailyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, time.UTC),
+	)
+	monthlyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+	)
+
+	dailyRequests := []entity.APIRequest{
+		entity.NewAPIRequest("test-session", now, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 2_000_000, 0), entity.NewCost(0.9), 1000),
+		entity.NewAPIRequest("test-session", now, "claude-3-haiku-20240307", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.1), 1000),
+	}
+
+	tests := []struct {
+		name      string
+		rates     entity.RateTable
+		costStyle entity.CostStyle
+		expected  string
+	}{
+		{
+			name:     "no rates hides the savings",
+			rates:    entity.NewRateTable(),
+			expected: "",
+		},
+		{
+			name:      "configured rates show the savings",
+			rates:     entity.NewRateTable(entity.NewModelRate("claude-*sonnet*", 3.0, 0.3)),
+			costStyle: entity.CostStyleFull,
+			expected:  "$5.40", // 2M cache reads * ($3.00 - $0.30) per million
+		},
+		{
+			name:     "unpriced cache reads hide the savings",
+			rates:    entity.NewRateTable(entity.NewModelRate("claude-*haiku*", 0.25, 0.03)),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockPeriodBasedRepository(dailyRequests, dailyRequests)
+			statsQuery := usecase.NewCalculateStatsQuery(mockRepo, testutil.NewNoOpStatsCache())
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
+			mockPeriodFactory := &MockPeriodFactory{
+				dailyPeriod:   dailyPeriod,
+				monthlyPeriod: monthlyPeriod,
+			}
+
+			query := usecase.NewGetUsageVariablesQuery(
+				statsQuery,
+				sessionsQuery,
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				mockPeriodFactory,
+			)
+			query.SetCostStyle(tt.costStyle)
+			query.SetCacheSavingsQuery(usecase.NewCalculateCacheSavingsQuery(usecase.NewGetFilteredApiRequestsQuery(mockRepo), tt.rates))
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@daily_cache_savings"]; got != tt.expected {
+				t.Errorf("@daily_cache_savings = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_MaxGap(t *testing.T) {
+	now := time.Now()
+	dailyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, time.UTC),
+	)
+	monthlyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+	)
+	newRequest := func(offset time.Duration) entity.APIRequest {
+		return entity.NewAPIRequest("test-session", now.Add(offset), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.1), 1000)
+	}
+
+	tests := []struct {
+		name     string
+		requests []entity.APIRequest
+		noQuery  bool
+		style    entity.DurationStyle
+		expected string
+	}{
+		{
+			name:     "hours and minutes",
+			requests: []entity.APIRequest{newRequest(0), newRequest(-10 * time.Minute), newRequest(-2*time.Hour - 25*time.Minute)},
+			expected: "2h 15m",
+		},
+		{
+			name:     "minutes only",
+			requests: []entity.APIRequest{newRequest(0), newRequest(-45 * time.Minute)},
+			expected: "45m 0s",
+		},
+		{
+			name:     "compact duration style",
+			requests: []entity.APIRequest{newRequest(0), newRequest(-10 * time.Minute), newRequest(-2*time.Hour - 25*time.Minute)},
+			style:    entity.DurationStyleCompact,
+			expected: "2h15m",
+		},
+		{
+			name:     "clock duration style",
+			requests: []entity.APIRequest{newRequest(0), newRequest(-45 * time.Minute)},
+			style:    entity.DurationStyleClock,
+			expected: "0:45:00",
+		},
+		{
+			name:     "seconds only",
+			requests: []entity.APIRequest{newRequest(0), newRequest(-30 * time.Second)},
+			expected: "30s",
+		},
+		{
+			name:     "single request has no gap",
+			requests: []entity.APIRequest{newRequest(0)},
+			expected: "-",
+		},
+		{
+			name:     "without the query",
+			requests: []entity.APIRequest{newRequest(0), newRequest(-45 * time.Minute)},
+			noQuery:  true,
+			expected: "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockPeriodBasedRepository(tt.requests, tt.requests)
+			statsQuery := usecase.NewCalculateStatsQuery(mockRepo, testutil.NewNoOpStatsCache())
+			sessionsQuery := usecase.NewCountSessionsQuery(mockRepo, true)
+			mockPeriodFactory := &MockPeriodFactory{
+				dailyPeriod:   dailyPeriod,
+				monthlyPeriod: monthlyPeriod,
+			}
+
+			query := usecase.NewGetUsageVariablesQuery(
+				statsQuery,
+				sessionsQuery,
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				mockPeriodFactory,
+			)
+			if !tt.noQuery {
+				query.SetLongestGapQuery(usecase.NewGetLongestGapQuery(usecase.NewGetFilteredApiRequestsQuery(mockRepo)))
+			}
+			query.SetDurationStyle(tt.style)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@daily_max_gap"]; got != tt.expected {
+				t.Errorf("@daily_max_gap = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// fixedSchedulePeriodFactory creates schedule windows around a fixed time
+type fixedSchedulePeriodFactory struct {
+	now time.Time
+}
+
+func (f fixedSchedulePeriodFactory) CreateScheduled(schedule entity.Schedule) entity.Period {
+	return schedule.Window(f.now)
+}
+
+func TestGetUsageVariablesQuery_Schedule(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC) // Wednesday noon
+	newRequest := func(at time.Time, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("test-session", at, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+	requests := []entity.APIRequest{
+		newRequest(time.Date(2025, 7, 2, 8, 59, 0, 0, time.UTC), 1.0),  // Before today's window
+		newRequest(time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC), 0.25),  // Window start
+		newRequest(time.Date(2025, 7, 2, 11, 30, 0, 0, time.UTC), 0.5), // Inside
+		newRequest(time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC), 2.0),  // Yesterday's window
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		expected string
+	}{
+		{name: "current window", schedule: "mon-fri 09:00-17:00", expected: "$0.8"},
+		{name: "latest window before now", schedule: "tue 09:00-17:00", expected: "$2.0"},
+		{name: "without a schedule", expected: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache())
+			query := usecase.NewGetUsageVariablesQuery(
+				statsQuery,
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				&MockPeriodFactory{dailyPeriod: entity.NewPeriodFromDuration(now, 24*time.Hour), monthlyPeriod: entity.NewPeriodFromDuration(now, 30*24*time.Hour)},
+			)
+			if tt.schedule != "" {
+				schedule, err := entity.ParseSchedule(tt.schedule)
+				if err != nil {
+					t.Fatalf("unexpected schedule error: %v", err)
+				}
+				query.SetSchedule(schedule, fixedSchedulePeriodFactory{now: now})
+			}
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := vars["@schedule_cost"]; got != tt.expected {
+				t.Errorf("@schedule_cost = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// countingStatsRepository records how many stats queries were made
+type countingStatsRepository struct {
+	queries int
+}
+
+func (r *countingStatsRepository) GetStats(query entity.StatsQuery) (entity.Stats, error) {
+	r.queries++
+	return entity.Stats{}, nil
+}
+
+func TestGetUsageVariablesQuery_UsedVariables(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	tests := []struct {
+		name            string
+		usedVariables   []string
+		expectedQueries int
+	}{
+		{name: "every variable without a format string", usedVariables: nil, expectedQueries: 4},
+		{name: "rolling windows skipped when unused", usedVariables: []string{"@daily_cost", "@monthly_cost"}, expectedQueries: 2},
+		{name: "only the used rolling window", usedVariables: []string{"@rolling_30d_cost"}, expectedQueries: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			statsRepo := &countingStatsRepository{}
+			query := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				usecase.NewCountSessionsQuery(testutil.NewMockAPIRequestRepository(), true),
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				&MockPeriodFactory{dailyPeriod: entity.NewPeriodFromDuration(now, 24*time.Hour), monthlyPeriod: entity.NewPeriodFromDuration(now, 30*24*time.Hour)},
+			)
+			query.SetUsedVariables(tt.usedVariables)
+
+			if _, err := query.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if statsRepo.queries != tt.expectedQueries {
+				t.Errorf("expected %d stats queries, got %d", tt.expectedQueries, statsRepo.queries)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_Block(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("test-session", time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", entity.NewToken(5000, 5000, 0, 0), entity.NewCost(0.1), 1000), // Base tier
+		entity.NewAPIRequest("test-session", time.Date(2025, 7, 2, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(800000, 400000, 50000, 0), entity.NewCost(2.0), 1000),
+		entity.NewAPIRequest("test-session", time.Date(2025, 7, 2, 7, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(900000, 0, 0, 0), entity.NewCost(2.0), 1000), // Before the block
+	}
+	blockStart := time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		block         *entity.Block
+		tokenDecimals int
+		costStyle     entity.CostStyle
+		durationStyle entity.DurationStyle
+		expectedUsed  string
+		expectedLimit string
+		expectedUsage string
+		expectedLeft  string
+		expectedTime  string
+	}{
+		{name: "without a block", tokenDecimals: -1, expectedUsed: "-", expectedLimit: "-", expectedUsage: "0%", expectedLeft: "0", expectedTime: ""},
+		{name: "block with a limit", block: blockPtr(entity.NewBlockWithLimit(blockStart, 2000000)), tokenDecimals: -1, expectedUsed: "1.20M", expectedLimit: "2.00M", expectedUsage: "60%", expectedLeft: "800.0K", expectedTime: "1h 0m"},
+		{name: "block without a limit", block: blockPtr(entity.NewBlockWithLimit(blockStart, 0)), tokenDecimals: -1, expectedUsed: "1.20M", expectedLimit: "-", expectedUsage: "0%", expectedLeft: "0", expectedTime: "1h 0m"},
+		{name: "total token metric", block: blockPtr(entity.NewBlockWithLimit(blockStart, 2000000).WithTokenMetric(entity.BlockTokenMetricTotal)), tokenDecimals: -1, expectedUsed: "1.25M", expectedLimit: "2.00M", expectedUsage: "62%", expectedLeft: "750.0K", expectedTime: "1h 0m"},
+		{name: "token decimals", block: blockPtr(entity.NewBlockWithLimit(blockStart, 2000000)), tokenDecimals: 0, expectedUsed: "1M", expectedLimit: "2M", expectedUsage: "60%", expectedLeft: "800K", expectedTime: "1h 0m"},
+		{name: "compact precision", block: blockPtr(entity.NewBlockWithLimit(blockStart, 2000000)), tokenDecimals: 2, costStyle: entity.CostStyleCompact, expectedUsed: "1M", expectedLimit: "2M", expectedUsage: "60%", expectedLeft: "800K", expectedTime: "1h 0m"},
+		{name: "full precision", block: blockPtr(entity.NewBlockWithLimit(blockStart, 2000000)), tokenDecimals: -1, costStyle: entity.CostStyleFull, expectedUsed: "1200000", expectedLimit: "2000000", expectedUsage: "60%", expectedLeft: "800000", expectedTime: "1h 0m"},
+		{name: "limit exceeded", block: blockPtr(entity.NewBlockWithLimit(blockStart, 1000000)), tokenDecimals: -1, expectedUsed: "1.20M", expectedLimit: "1.00M", expectedUsage: "120%", expectedLeft: "0", expectedTime: "1h 0m"},
+		{name: "ended block", block: blockPtr(entity.NewBlockWithLimit(blockStart.Add(-5*time.Hour), 2000000)), tokenDecimals: -1, expectedUsed: "900.0K", expectedLimit: "2.00M", expectedUsage: "45%", expectedLeft: "1.10M", expectedTime: "0s"},
+		{name: "clock duration style", block: blockPtr(entity.NewBlockWithLimit(blockStart, 2000000)), tokenDecimals: -1, durationStyle: entity.DurationStyleClock, expectedUsed: "1.20M", expectedLimit: "2.00M", expectedUsage: "60%", expectedLeft: "800.0K", expectedTime: "1:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache())
+			query := usecase.NewGetUsageVariablesQuery(
+				statsQuery,
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				&MockPeriodFactory{dailyPeriod: entity.NewPeriodFromDuration(now, 24*time.Hour), monthlyPeriod: entity.NewPeriodFromDuration(now, 30*24*time.Hour)},
+			)
+			query.SetTokenDecimals(tt.tokenDecimals)
+			query.SetCostStyle(tt.costStyle)
+			query.SetDurationStyle(tt.durationStyle)
+			query.SetClock(entity.NewFixedClock(now))
+			if tt.block != nil {
+				query.SetBlock(*tt.block)
+			}
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := vars["@block_used"]; got != tt.expectedUsed {
+				t.Errorf("@block_used = %q, want %q", got, tt.expectedUsed)
+			}
+			if got := vars["@block_limit"]; got != tt.expectedLimit {
+				t.Errorf("@block_limit = %q, want %q", got, tt.expectedLimit)
+			}
+			if got := vars["@block_usage"]; got != tt.expectedUsage {
+				t.Errorf("@block_usage = %q, want %q", got, tt.expectedUsage)
+			}
+			if got := vars["@block_remaining"]; got != tt.expectedLeft {
+				t.Errorf("@block_remaining = %q, want %q", got, tt.expectedLeft)
+			}
+			if got := vars["@block_time_remaining"]; got != tt.expectedTime {
+				t.Errorf("@block_time_remaining = %q, want %q", got, tt.expectedTime)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_ExecuteValues(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("test-session", time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", entity.NewToken(5000, 5000, 0, 0), entity.NewCost(0.25), 1000),
+		entity.NewAPIRequest("test-session", time.Date(2025, 7, 2, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(800000, 400000, 0, 0), entity.NewCost(2.0), 1000),
+	}
+	blockStart := time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		block    *entity.Block
+		expected map[string]any
+	}{
+		{
+			name: "without a block",
+			expected: map[string]any{
+				"@daily_cost":           2.25,
+				"@base_cost":            0.25,
+				"@premium_cost":         2.0,
+				"@daily_sessions":       1,
+				"@daily_max_gap":        nil, // no gap query
+				"@block_used":           nil,
+				"@block_limit":          nil,
+				"@block_usage":          0.0,
+				"@block_remaining":      int64(0),
+				"@block_time_remaining": nil,
+				"@timezone":             "UTC",
+			},
+		},
+		{
+			name:  "block with a limit",
+			block: blockPtr(entity.NewBlockWithLimit(blockStart, 2000000)),
+			expected: map[string]any{
+				"@block_used":           int64(1200000),
+				"@block_limit":          int64(2000000),
+				"@block_usage":          60.0,
+				"@block_remaining":      int64(800000),
+				"@block_time_remaining": 3600.0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			query := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				&MockPeriodFactory{dailyPeriod: entity.NewPeriodFromDuration(now, 24*time.Hour), monthlyPeriod: entity.NewPeriodFromDuration(now, 30*24*time.Hour)},
+			)
+			query.SetClock(entity.NewFixedClock(now))
+			if tt.block != nil {
+				query.SetBlock(*tt.block)
+			}
+
+			values, err := query.ExecuteValues(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(values) != len(entity.GetAllUsageVariables()) {
+				t.Errorf("got %d values, want %d", len(values), len(entity.GetAllUsageVariables()))
+			}
+			for key, expected := range tt.expected {
+				if got, ok := values[key]; !ok || got != expected {
+					t.Errorf("%s = %#v, want %#v", key, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_ValueKeys(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	schedule, err := entity.ParseSchedule("mon-fri 09:00-17:00")
+	if err != nil {
+		t.Fatalf("unexpected schedule error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		configure func(query *usecase.GetUsageVariablesQuery)
+	}{
+		{name: "without optional inputs", configure: func(query *usecase.GetUsageVariablesQuery) {}},
+		{
+			name: "with a block and a schedule",
+			configure: func(query *usecase.GetUsageVariablesQuery) {
+				query.SetBlock(entity.NewBlockWithLimit(time.Date(2025, 7, 2, 8, 0, 0, 0, time.UTC), 2000000))
+				query.SetSchedule(schedule, fixedSchedulePeriodFactory{now: now})
+			},
+		},
+	}
+
+	expected := make(map[string]bool)
+	for _, variable := range entity.GetAllUsageVariables() {
+		expected[variable.Key()] = true
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, statsRepo := testutil.NewMockRepositoryWithData(nil)
+			query := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				&MockPeriodFactory{dailyPeriod: entity.NewPeriodFromDuration(now, 24*time.Hour), monthlyPeriod: entity.NewPeriodFromDuration(now, 30*24*time.Hour)},
+			)
+			query.SetClock(entity.NewFixedClock(now))
+			tt.configure(query)
+
+			variables, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			values, err := query.ExecuteValues(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Every usage variable has both a formatted and a raw value, and nothing else does
+			for key := range expected {
+				if _, ok := variables[key]; !ok {
+					t.Errorf("Execute() is missing %s", key)
+				}
+				if _, ok := values[key]; !ok {
+					t.Errorf("ExecuteValues() is missing %s", key)
+				}
+			}
+			for key := range variables {
+				if !expected[key] {
+					t.Errorf("Execute() has unknown variable %s", key)
+				}
+			}
+			for key := range values {
+				if !expected[key] {
+					t.Errorf("ExecuteValues() has unknown variable %s", key)
+				}
+			}
+		})
+	}
+}
+
+func blockPtr(block entity.Block) *entity.Block {
+	return &block
+}
+
+func TestGetUsageVariablesQuery_PercentDecimals(t *testing.T) {
+	now := time.Now()
+	dailyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, time.UTC),
+	)
+	monthlyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+	)
+	// $31.1 of a $20 plan is 155.5%
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("test-session", now, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(31.1), 1000),
+	}
+
+	tests := []struct {
+		name     string
+		decimals int
+		expected string
+	}{
+		{
+			name:     "whole percent by default",
+			decimals: 0,
+			expected: "155%",
+		},
+		{
+			name:     "one decimal",
+			decimals: 1,
+			expected: "155.5%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockPeriodBasedRepository(requests, requests)
+			query := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(mockRepo, testutil.NewNoOpStatsCache()),
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20))),
+				&MockPeriodFactory{dailyPeriod: dailyPeriod, monthlyPeriod: monthlyPeriod},
+			)
+			query.SetPercentDecimals(tt.decimals)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@monthly_plan_usage"]; got != tt.expected {
+				t.Errorf("@monthly_plan_usage = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_Timezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	utc, err := time.LoadLocation("UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	dailyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, time.UTC),
+	)
+
+	tests := []struct {
+		name     string
+		timezone *time.Location
+		expected string
+	}{
+		{
+			name:     "named timezone",
+			timezone: newYork,
+			expected: "America/New_York",
+		},
+		{
+			name:     "configured UTC",
+			timezone: utc,
+			expected: "UTC",
+		},
+		{
+			name:     "default",
+			expected: "UTC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockPeriodBasedRepository(nil, nil)
+			query := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(mockRepo, testutil.NewNoOpStatsCache()),
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))),
+				&MockPeriodFactory{dailyPeriod: dailyPeriod, monthlyPeriod: dailyPeriod},
+			)
+			if tt.timezone != nil {
+				query.SetTimezone(tt.timezone)
+			}
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@timezone"]; got != tt.expected {
+				t.Errorf("@timezone = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_Explain(t *testing.T) {
+	now := time.Now()
+	dailyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, time.UTC),
+	)
+	monthlyPeriod := entity.NewPeriod(
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+	)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("test-session", now, "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.1), 1000),
+		entity.NewAPIRequest("test-session", now.Add(-45*time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.1), 1000),
+	}
+
+	tests := []struct {
+		name     string
+		plan     entity.Plan
+		variable entity.UsageVariable
+		expected usecase.ExplanationInput
+	}{
+		{
+			name:     "daily plan usage without a plan",
+			plan:     entity.NewPlan("unset", entity.NewCost(0)),
+			variable: entity.DailyPlanUsageVariable,
+			expected: usecase.ExplanationInput{Name: "Usage", Value: "0% without a priced plan (set claude.plan)"},
+		},
+		{
+			name:     "daily plan usage formula",
+			plan:     entity.NewPlan("pro", entity.NewCost(20)),
+			variable: entity.DailyPlanUsageVariable,
+			expected: usecase.ExplanationInput{Name: "Days in month", Value: fmt.Sprintf("%d", entity.DaysInMonth(dailyPeriod.StartAt()))},
+		},
+		{
+			name:     "monthly cost period",
+			plan:     entity.NewPlan("pro", entity.NewCost(20)),
+			variable: entity.MonthlyCostVariable,
+			expected: usecase.ExplanationInput{Name: "Period", Value: monthlyPeriod.StartAt().Format(time.RFC3339) + " to " + monthlyPeriod.EndAt().Format(time.RFC3339)},
+		},
+		{
+			name:     "longest gap",
+			plan:     entity.NewPlan("pro", entity.NewCost(20)),
+			variable: entity.DailyMaxGapVariable,
+			expected: usecase.ExplanationInput{Name: "Longest gap", Value: "45m0s"},
+		},
+		{
+			name:     "cache savings without rates",
+			plan:     entity.NewPlan("pro", entity.NewCost(20)),
+			variable: entity.DailyCacheSavingsVariable,
+			expected: usecase.ExplanationInput{Name: "Savings", Value: "empty without claude.rates"},
+		},
+		{
+			name:     "rolling 7 day cost period",
+			plan:     entity.NewPlan("pro", entity.NewCost(20)),
+			variable: entity.Rolling7dCostVariable,
+			expected: usecase.ExplanationInput{Name: "Period", Value: monthlyPeriod.StartAt().Format(time.RFC3339) + " to " + monthlyPeriod.EndAt().Format(time.RFC3339)},
+		},
+		{
+			name:     "timezone day start",
+			plan:     entity.NewPlan("pro", entity.NewCost(20)),
+			variable: entity.TimezoneVariable,
+			expected: usecase.ExplanationInput{Name: "Day starts", Value: dailyPeriod.StartAt().Format(time.RFC3339)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockPeriodBasedRepository(requests, requests)
+			query := usecase.NewGetUsageVariablesQuery(
+				usecase.NewCalculateStatsQuery(mockRepo, testutil.NewNoOpStatsCache()),
+				usecase.NewCountSessionsQuery(mockRepo, true),
+				testutil.NewMockPlanRepository(tt.plan),
+				&MockPeriodFactory{dailyPeriod: dailyPeriod, monthlyPeriod: monthlyPeriod},
+			)
+			query.SetLongestGapQuery(usecase.NewGetLongestGapQuery(usecase.NewGetFilteredApiRequestsQuery(mockRepo)))
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			explanations, err := query.Explain(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(explanations) != len(entity.GetAllUsageVariables()) {
+				t.Fatalf("expected an explanation per variable, got %d", len(explanations))
+			}
+
+			for _, explanation := range explanations {
+				// The explained value is the one substituted into format strings
+				if explanation.Value != vars[explanation.Variable.Key()] {
+					t.Errorf("%s: explained value %q, executed value %q", explanation.Variable.Key(), explanation.Value, vars[explanation.Variable.Key()])
+				}
+				if explanation.Variable != tt.variable {
+					continue
+				}
+				found := false
+				for _, input := range explanation.Inputs {
+					if input == tt.expected {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("expected input %+v in %+v", tt.expected, explanation.Inputs)
+				}
+			}
+		})
+	}
+}
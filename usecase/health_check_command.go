@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// healthCheckModel is the model name carried by synthetic health check records
+const healthCheckModel = "ccmon-healthcheck"
+
+// ErrHealthCheckRecordMissing is returned when the synthetic record does not reach the store
+var ErrHealthCheckRecordMissing = errors.New("synthetic record was not stored")
+
+// TelemetryIngester submits an API request through the same path as received telemetry
+type TelemetryIngester interface {
+	Ingest(ctx context.Context, req entity.APIRequest) error
+}
+
+// HealthCheckCommand verifies ingestion end-to-end by submitting a synthetic record,
+// reading it back and deleting it again.
+// The record is stamped within the first day of the Unix epoch, so it never falls
+// inside a real usage period and retention removes it if cleanup is interrupted.
+type HealthCheckCommand struct {
+	ingester              TelemetryIngester
+	getFilteredQuery      *GetFilteredApiRequestsQuery
+	deleteByPeriodCommand *DeleteRequestsByPeriodCommand
+}
+
+// NewHealthCheckCommand creates a new HealthCheckCommand
+func NewHealthCheckCommand(ingester TelemetryIngester, getFilteredQuery *GetFilteredApiRequestsQuery, deleteByPeriodCommand *DeleteRequestsByPeriodCommand) *HealthCheckCommand {
+	return &HealthCheckCommand{
+		ingester:              ingester,
+		getFilteredQuery:      getFilteredQuery,
+		deleteByPeriodCommand: deleteByPeriodCommand,
+	}
+}
+
+// HealthCheckResult contains the timing of each health check phase
+type HealthCheckResult struct {
+	IngestDuration  time.Duration
+	VerifyDuration  time.Duration
+	CleanupDuration time.Duration
+}
+
+// Total returns the combined duration of all phases
+func (r HealthCheckResult) Total() time.Duration {
+	return r.IngestDuration + r.VerifyDuration + r.CleanupDuration
+}
+
+// Execute executes the health check command
+func (c *HealthCheckCommand) Execute(ctx context.Context) (*HealthCheckResult, error) {
+	probe := c.newProbe()
+	// A single-instant period only ever matches synthetic records
+	period := entity.NewPeriod(probe.Timestamp(), probe.Timestamp())
+	result := &HealthCheckResult{}
+
+	start := time.Now()
+	if err := c.ingester.Ingest(ctx, probe); err != nil {
+		return result, fmt.Errorf("ingest failed: %w", err)
+	}
+	result.IngestDuration = time.Since(start)
+
+	start = time.Now()
+	verifyErr := c.verify(ctx, probe, period)
+	result.VerifyDuration = time.Since(start)
+
+	// Always clean up, even when verification failed, so a partial record does not linger
+	start = time.Now()
+	cleanupErr := c.cleanup(ctx, period)
+	result.CleanupDuration = time.Since(start)
+
+	if verifyErr != nil {
+		return result, fmt.Errorf("verify failed: %w", verifyErr)
+	}
+	if cleanupErr != nil {
+		return result, fmt.Errorf("cleanup failed: %w", cleanupErr)
+	}
+
+	return result, nil
+}
+
+// newProbe builds a synthetic request with distinct values for every mapped field
+func (c *HealthCheckCommand) newProbe() entity.APIRequest {
+	now := time.Now()
+	// Spread concurrent checks across the first day after the epoch
+	offset := time.Duration(1+now.UnixNano()%86399) * time.Second
+	timestamp := time.Unix(0, 0).UTC().Add(offset)
+
+	return entity.NewAPIRequest(
+		entity.HealthCheckSessionPrefix+strconv.FormatInt(now.UnixNano(), 36),
+		timestamp,
+		healthCheckModel,
+		entity.NewToken(1, 2, 3, 4),
+		entity.NewCost(0.000001),
+		5,
+	)
+}
+
+// verify reads the probe back and checks every field survived the ingestion mapping
+func (c *HealthCheckCommand) verify(ctx context.Context, probe entity.APIRequest, period entity.Period) error {
+	requests, err := c.getFilteredQuery.Execute(ctx, GetFilteredApiRequestsParams{Period: period})
+	if err != nil {
+		return err
+	}
+
+	for _, req := range requests {
+		if req.SessionID() != probe.SessionID() {
+			continue
+		}
+
+		switch {
+		case req.Model() != probe.Model():
+			return fmt.Errorf("model mismatch: stored %q, sent %q", req.Model(), probe.Model())
+		case req.Tokens() != probe.Tokens():
+			return fmt.Errorf("token mismatch: stored %+v, sent %+v", req.Tokens(), probe.Tokens())
+		case req.Cost() != probe.Cost():
+			return fmt.Errorf("cost mismatch: stored %g, sent %g", req.Cost().Amount(), probe.Cost().Amount())
+		case req.DurationMS() != probe.DurationMS():
+			return fmt.Errorf("duration mismatch: stored %d, sent %d", req.DurationMS(), probe.DurationMS())
+		case !req.Timestamp().Equal(probe.Timestamp()):
+			return fmt.Errorf("timestamp mismatch: stored %s, sent %s", req.Timestamp(), probe.Timestamp())
+		}
+		return nil
+	}
+
+	return ErrHealthCheckRecordMissing
+}
+
+// cleanup deletes the probe through the regular two-phase delete
+func (c *HealthCheckCommand) cleanup(ctx context.Context, period entity.Period) error {
+	preview, err := c.deleteByPeriodCommand.Execute(ctx, DeleteRequestsByPeriodParams{Period: period})
+	if err != nil {
+		return err
+	}
+	if preview.MatchedCount == 0 {
+		return nil
+	}
+
+	_, err = c.deleteByPeriodCommand.Execute(ctx, DeleteRequestsByPeriodParams{
+		Period:            period,
+		ConfirmationToken: preview.ConfirmationToken,
+	})
+	return err
+}
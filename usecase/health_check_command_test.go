@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// fakeIngester stores requests directly, optionally rewriting them to simulate mapping drift
+// and evicting excess records after each save like the receiver does
+type fakeIngester struct {
+	repo    *testutil.MockAPIRequestRepository
+	rewrite func(req entity.APIRequest) (entity.APIRequest, bool)
+	evict   *usecase.EvictExcessRecordsCommand
+	err     error
+}
+
+func (f *fakeIngester) Ingest(ctx context.Context, req entity.APIRequest) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.rewrite != nil {
+		var keep bool
+		if req, keep = f.rewrite(req); !keep {
+			return nil
+		}
+	}
+	if err := f.repo.Save(req); err != nil {
+		return err
+	}
+	if f.evict != nil {
+		_, err := f.evict.Execute(ctx, usecase.EvictExcessRecordsParams{Saved: 1})
+		return err
+	}
+	return nil
+}
+
+func TestHealthCheckCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	realRequest := testutil.CreateTestAPIRequest("real-session", time.Now().UTC(), "claude-3-5-sonnet-20241022", 100, 50, 0.5)
+
+	tests := []struct {
+		name        string
+		rewrite     func(req entity.APIRequest) (entity.APIRequest, bool)
+		ingestErr   error
+		expectedErr string
+	}{
+		{
+			name: "round trip succeeds",
+		},
+		{
+			name: "attribute mapping drift",
+			rewrite: func(req entity.APIRequest) (entity.APIRequest, bool) {
+				return entity.NewAPIRequest(req.SessionID(), req.Timestamp(), "unknown", req.Tokens(), req.Cost(), req.DurationMS()), true
+			},
+			expectedErr: "model mismatch",
+		},
+		{
+			name: "record dropped before storage",
+			rewrite: func(req entity.APIRequest) (entity.APIRequest, bool) {
+				return req, false
+			},
+			expectedErr: usecase.ErrHealthCheckRecordMissing.Error(),
+		},
+		{
+			name:        "ingest error",
+			ingestErr:   errors.New("receiver unavailable"),
+			expectedErr: "ingest failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData([]entity.APIRequest{realRequest})
+			ingester := &fakeIngester{repo: repo, rewrite: tt.rewrite, err: tt.ingestErr}
+
+			command := usecase.NewHealthCheckCommand(
+				ingester,
+				usecase.NewGetFilteredApiRequestsQuery(repo),
+				usecase.NewDeleteRequestsByPeriodCommand(repo),
+			)
+
+			result, err := command.Execute(context.Background())
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.expectedErr == "" && result.Total() <= 0 {
+				t.Errorf("expected positive total duration, got %v", result.Total())
+			}
+
+			// The synthetic record never lingers and real records are untouched
+			remaining, _ := repo.FindAll()
+			if len(remaining) != 1 || remaining[0].SessionID() != "real-session" {
+				t.Errorf("expected only the real request to remain, got %d requests", len(remaining))
+			}
+		})
+	}
+}
+
+func TestHealthCheckCommand_FullStore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("real-session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 100, 50, 0.5),
+		testutil.CreateTestAPIRequest("real-session-2", now, "claude-3-5-sonnet-20241022", 100, 50, 0.5),
+	})
+	// The store is at its maximum, so saving the probe triggers eviction of the oldest record
+	ingester := &fakeIngester{repo: repo, evict: usecase.NewEvictExcessRecordsCommand(repo, 2)}
+
+	command := usecase.NewHealthCheckCommand(
+		ingester,
+		usecase.NewGetFilteredApiRequestsQuery(repo),
+		usecase.NewDeleteRequestsByPeriodCommand(repo),
+	)
+
+	if _, err := command.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remaining, _ := repo.FindAll()
+	if len(remaining) != 1 || remaining[0].SessionID() != "real-session-2" {
+		t.Errorf("expected only the newest real request to remain, got %d requests", len(remaining))
+	}
+}
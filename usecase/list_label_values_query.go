@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// ListLabelValuesQuery lists the distinct values of one label among the requests in a period,
+// e.g. the projects seen when requests carry a project label
+type ListLabelValuesQuery struct {
+	getFilteredQuery *GetFilteredApiRequestsQuery
+}
+
+// NewListLabelValuesQuery creates a new ListLabelValuesQuery reading requests through the filtered query
+func NewListLabelValuesQuery(getFilteredQuery *GetFilteredApiRequestsQuery) *ListLabelValuesQuery {
+	return &ListLabelValuesQuery{
+		getFilteredQuery: getFilteredQuery,
+	}
+}
+
+// ListLabelValuesParams contains the parameters for the label values query
+type ListLabelValuesParams struct {
+	Period entity.Period
+	Key    string
+}
+
+// Execute returns the sorted non-empty values of the label, empty when no request carries it
+func (q *ListLabelValuesQuery) Execute(ctx context.Context, params ListLabelValuesParams) ([]string, error) {
+	requests, err := q.getFilteredQuery.Execute(ctx, GetFilteredApiRequestsParams{Period: params.Period})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0)
+	for _, req := range requests {
+		if value, ok := req.Label(params.Key); ok && value != "" && !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	slices.Sort(values)
+	return values, nil
+}
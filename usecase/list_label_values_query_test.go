@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestListLabelValuesQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+
+	newRequest := func(sessionID string, offset time.Duration, labels map[string]string) entity.APIRequest {
+		return testutil.CreateTestAPIRequest(sessionID, now.Add(-offset), "claude-3-5-sonnet-20241022", 100, 50, 0.01).WithLabels(labels)
+	}
+
+	requests := []entity.APIRequest{
+		newRequest("session-1", 4*time.Hour, map[string]string{"project": "web"}),
+		newRequest("session-2", 3*time.Hour, map[string]string{"project": "api"}),
+		newRequest("session-3", 2*time.Hour, map[string]string{"project": "web", "user.id": "bob"}),
+		newRequest("session-4", time.Hour, map[string]string{"project": ""}),
+		newRequest("session-5", 30*time.Minute, nil),
+		newRequest("session-6", 48*time.Hour, map[string]string{"project": "old"}),
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected []string
+	}{
+		{
+			name:     "distinct values sorted within the period",
+			key:      "project",
+			expected: []string{"api", "web"},
+		},
+		{
+			name:     "other label",
+			key:      "user.id",
+			expected: []string{"bob"},
+		},
+		{
+			name:     "absent label yields no values",
+			key:      "organization.id",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetMockData(requests)
+
+			query := usecase.NewListLabelValuesQuery(usecase.NewGetFilteredApiRequestsQuery(mockRepo))
+			values, err := query.Execute(context.Background(), usecase.ListLabelValuesParams{Period: period, Key: tt.key})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(values, tt.expected) {
+				t.Errorf("values = %v, want %v", values, tt.expected)
+			}
+		})
+	}
+}
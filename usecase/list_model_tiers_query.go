@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// ListModelTiersQuery lists the distinct models in a period with the tier they are counted in
+type ListModelTiersQuery struct {
+	getFilteredQuery *GetFilteredApiRequestsQuery
+	tierRepository   ModelTierRepository
+}
+
+// NewListModelTiersQuery creates a new ListModelTiersQuery with the given dependencies
+func NewListModelTiersQuery(getFilteredQuery *GetFilteredApiRequestsQuery, tierRepository ModelTierRepository) *ListModelTiersQuery {
+	return &ListModelTiersQuery{
+		getFilteredQuery: getFilteredQuery,
+		tierRepository:   tierRepository,
+	}
+}
+
+// ListModelTiersParams contains the parameters for listing model tiers
+type ListModelTiersParams struct {
+	Period entity.Period
+}
+
+// ModelTierUsage describes a model seen in the store and its current tier
+type ModelTierUsage struct {
+	Model      entity.Model
+	Requests   int
+	Tier       entity.ModelTier
+	Overridden bool // True when the tier comes from an override rather than the model name
+}
+
+// Execute lists the models, most used first
+func (q *ListModelTiersQuery) Execute(ctx context.Context, params ListModelTiersParams) ([]ModelTierUsage, error) {
+	requests, err := q.getFilteredQuery.Execute(ctx, GetFilteredApiRequestsParams{Period: params.Period})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entity.Model]int)
+	for _, req := range requests {
+		counts[req.Model()]++
+	}
+
+	classifier := q.tierRepository.GetClassifier()
+	models := make([]ModelTierUsage, 0, len(counts))
+	for model, count := range counts {
+		_, overridden := classifier.Override(model)
+		models = append(models, ModelTierUsage{
+			Model:      model,
+			Requests:   count,
+			Tier:       classifier.Classify(model),
+			Overridden: overridden,
+		})
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Requests != models[j].Requests {
+			return models[i].Requests > models[j].Requests
+		}
+		return models[i].Model < models[j].Model
+	})
+
+	return models, nil
+}
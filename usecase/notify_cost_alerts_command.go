@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// CostAlertTitle is the title of the notifications sent by NotifyCostAlertsCommand
+const CostAlertTitle = "ccmon: daily spend alert"
+
+// NotifyCostAlertsCommand notifies when today's cost or daily plan usage crosses a configured threshold.
+// Each threshold fires at most once per day; crossing several at once sends one notification for the highest.
+type NotifyCostAlertsCommand struct {
+	summaryQuery  *GetSummaryQuery
+	periodFactory PeriodFactory
+	notifier      Notifier
+
+	costThresholds      []float64
+	planUsageThresholds []int
+
+	// Alerts are held back during quiet hours, evaluated in the configured timezone
+	quietHours []entity.QuietHours
+	timezone   *time.Location
+	clock      entity.Clock
+
+	// Thresholds already notified today, reset when the day changes
+	mu       sync.Mutex
+	day      time.Time
+	notified map[string]bool
+}
+
+// NewNotifyCostAlertsCommand creates a new NotifyCostAlertsCommand with the given dependencies
+func NewNotifyCostAlertsCommand(summaryQuery *GetSummaryQuery, periodFactory PeriodFactory, notifier Notifier) *NotifyCostAlertsCommand {
+	return &NotifyCostAlertsCommand{
+		summaryQuery:  summaryQuery,
+		periodFactory: periodFactory,
+		notifier:      notifier,
+		timezone:      time.UTC,
+		clock:         entity.SystemClock{},
+		notified:      make(map[string]bool),
+	}
+}
+
+// SetQuietHours holds alerts back during the windows, read as wall clock times in the timezone.
+// A threshold crossed during quiet hours alerts once they end if it is still crossed that day.
+func (c *NotifyCostAlertsCommand) SetQuietHours(quietHours []entity.QuietHours, timezone *time.Location) {
+	c.quietHours = append([]entity.QuietHours(nil), quietHours...)
+	if timezone != nil {
+		c.timezone = timezone
+	}
+}
+
+// SetClock changes the source of "now" used to check quiet hours
+func (c *NotifyCostAlertsCommand) SetClock(clock entity.Clock) {
+	c.clock = clock
+}
+
+// SetThresholds sets the daily cost (USD) and daily plan usage (percent) thresholds to alert on
+func (c *NotifyCostAlertsCommand) SetThresholds(costThresholds []float64, planUsageThresholds []int) {
+	c.costThresholds = append([]float64(nil), costThresholds...)
+	sort.Float64s(c.costThresholds)
+	c.planUsageThresholds = append([]int(nil), planUsageThresholds...)
+	sort.Ints(c.planUsageThresholds)
+}
+
+// IsEnabled returns true if any threshold is configured and the platform can show notifications
+func (c *NotifyCostAlertsCommand) IsEnabled() bool {
+	if len(c.costThresholds) == 0 && len(c.planUsageThresholds) == 0 {
+		return false
+	}
+	return c.notifier.IsSupported()
+}
+
+// Execute checks today's usage and sends a notification for each newly crossed kind of threshold
+func (c *NotifyCostAlertsCommand) Execute(ctx context.Context) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	// Nothing is marked as notified, so crossed thresholds alert after quiet hours
+	if c.isQuietHours() {
+		return nil
+	}
+
+	summary, err := c.summaryQuery.Execute(ctx, GetSummaryParams{})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if day := c.periodFactory.CreateDaily().StartAt(); !day.Equal(c.day) {
+		c.day = day
+		c.notified = make(map[string]bool)
+	}
+
+	// Thresholds are marked even when sending fails, so a broken notifier is tried once a day
+	// instead of on every refresh
+	var errs []error
+	if threshold, keys := c.newlyCrossedCost(summary.DailyCost.Amount()); len(keys) > 0 {
+		message := fmt.Sprintf("Today's cost is %s, past your %s alert",
+			summary.DailyCost.Format(entity.CostStyleFull), entity.NewCost(threshold).Format(entity.CostStyleFull))
+		c.markNotified(keys)
+		if err := c.notifier.Notify(CostAlertTitle, message); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send cost alert: %w", err))
+		}
+	}
+
+	// Plan usage is meaningless without a plan budget
+	if summary.DailyBudget.Amount() > 0 {
+		if threshold, keys := c.newlyCrossedPlanUsage(summary.DailyPlanUsage); len(keys) > 0 {
+			message := fmt.Sprintf("Today's cost is %d%% of the daily plan budget (%s of %s), past your %d%% alert",
+				summary.DailyPlanUsage, summary.DailyCost.Format(entity.CostStyleFull), summary.DailyBudget.Format(entity.CostStyleFull), threshold)
+			c.markNotified(keys)
+			if err := c.notifier.Notify(CostAlertTitle, message); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send plan usage alert: %w", err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isQuietHours returns true if now falls inside any quiet hours window
+func (c *NotifyCostAlertsCommand) isQuietHours() bool {
+	now := c.clock.Now().In(c.timezone)
+	for _, quiet := range c.quietHours {
+		if quiet.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// markNotified records thresholds as notified so they do not fire again today
+func (c *NotifyCostAlertsCommand) markNotified(keys []string) {
+	for _, key := range keys {
+		c.notified[key] = true
+	}
+}
+
+// newlyCrossedCost returns the highest crossed cost threshold and the keys of every crossed one not yet notified
+func (c *NotifyCostAlertsCommand) newlyCrossedCost(cost float64) (float64, []string) {
+	var highest float64
+	var keys []string
+	for _, threshold := range c.costThresholds {
+		key := fmt.Sprintf("cost:%g", threshold)
+		if cost >= threshold && !c.notified[key] {
+			highest = threshold
+			keys = append(keys, key)
+		}
+	}
+	return highest, keys
+}
+
+// newlyCrossedPlanUsage returns the highest crossed plan usage threshold and the keys of every crossed one not yet notified
+func (c *NotifyCostAlertsCommand) newlyCrossedPlanUsage(usage int) (int, []string) {
+	var highest int
+	var keys []string
+	for _, threshold := range c.planUsageThresholds {
+		key := fmt.Sprintf("plan:%d", threshold)
+		if usage >= threshold && !c.notified[key] {
+			highest = threshold
+			keys = append(keys, key)
+		}
+	}
+	return highest, keys
+}
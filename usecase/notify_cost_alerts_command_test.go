@@ -0,0 +1,239 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// fakeNotifier records notifications instead of showing them
+type fakeNotifier struct {
+	supported bool
+	messages  []string
+	err       error
+}
+
+func (n *fakeNotifier) IsSupported() bool {
+	return n.supported
+}
+
+func (n *fakeNotifier) Notify(title, message string) error {
+	n.messages = append(n.messages, message)
+	return n.err
+}
+
+func TestNotifyCostAlertsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	dailyPeriod := entity.NewPeriod(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), now)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-2*time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, 0.5),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, 0.6),
+	}
+
+	tests := []struct {
+		name                string
+		plan                entity.Plan
+		supported           bool
+		costThresholds      []float64
+		planUsageThresholds []int
+		expectedEnabled     bool
+		expectedMessages    []string
+	}{
+		{
+			name:             "crossing several cost thresholds alerts once for the highest",
+			plan:             entity.NewPlan("unset", entity.NewCost(0)),
+			supported:        true,
+			costThresholds:   []float64{1.0, 0.5, 5.0},
+			expectedEnabled:  true,
+			expectedMessages: []string{"Today's cost is $1.10, past your $1.00 alert"},
+		},
+		{
+			name:                "plan usage alert uses the daily plan budget",
+			plan:                entity.NewPlan("pro", entity.NewCost(31.0)),
+			supported:           true,
+			planUsageThresholds: []int{80, 100},
+			expectedEnabled:     true,
+			expectedMessages:    []string{"Today's cost is 110% of the daily plan budget ($1.10 of $1.00), past your 100% alert"},
+		},
+		{
+			name:                "plan usage is skipped without a plan",
+			plan:                entity.NewPlan("unset", entity.NewCost(0)),
+			supported:           true,
+			planUsageThresholds: []int{80},
+			expectedEnabled:     true,
+		},
+		{
+			name:                "unsupported platform disables alerts",
+			plan:                entity.NewPlan("pro", entity.NewCost(31.0)),
+			supported:           false,
+			costThresholds:      []float64{1.0},
+			planUsageThresholds: []int{80},
+		},
+		{
+			name:      "no thresholds disables alerts",
+			plan:      entity.NewPlan("pro", entity.NewCost(31.0)),
+			supported: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+			periodFactory := &MockPeriodFactory{dailyPeriod: dailyPeriod}
+			summaryQuery := usecase.NewGetSummaryQuery(statsQuery, testutil.NewMockPlanRepository(tt.plan), periodFactory)
+			notifier := &fakeNotifier{supported: tt.supported}
+
+			command := usecase.NewNotifyCostAlertsCommand(summaryQuery, periodFactory, notifier)
+			command.SetThresholds(tt.costThresholds, tt.planUsageThresholds)
+
+			if command.IsEnabled() != tt.expectedEnabled {
+				t.Errorf("expected IsEnabled() = %v, got %v", tt.expectedEnabled, command.IsEnabled())
+			}
+			if err := command.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Join(notifier.messages, "\n") != strings.Join(tt.expectedMessages, "\n") {
+				t.Errorf("expected messages %q, got %q", tt.expectedMessages, notifier.messages)
+			}
+		})
+	}
+}
+
+func TestNotifyCostAlertsCommand_OncePerDay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, 1.5),
+	})
+	statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+	periodFactory := &MockPeriodFactory{dailyPeriod: entity.NewPeriod(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), now)}
+	summaryQuery := usecase.NewGetSummaryQuery(statsQuery, testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))), periodFactory)
+	notifier := &fakeNotifier{supported: true}
+
+	command := usecase.NewNotifyCostAlertsCommand(summaryQuery, periodFactory, notifier)
+	command.SetThresholds([]float64{1.0, 2.0}, nil)
+
+	execute := func(expectedCount int) {
+		t.Helper()
+		if err := command.Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(notifier.messages) != expectedCount {
+			t.Fatalf("expected %d notifications, got %q", expectedCount, notifier.messages)
+		}
+	}
+
+	// First crossing alerts, repeated checks stay quiet
+	execute(1)
+	execute(1)
+
+	// Crossing a higher threshold later the same day alerts again
+	if err := apiRepo.Save(testutil.CreateTestAPIRequest("session-1", now.Add(-30*time.Minute), "claude-3-5-sonnet-20241022", 1000, 500, 1.0)); err != nil {
+		t.Fatalf("failed to save request: %v", err)
+	}
+	execute(2)
+	execute(2)
+
+	// A new day starts over
+	nextDay := now.Add(24 * time.Hour)
+	if err := apiRepo.Save(testutil.CreateTestAPIRequest("session-2", nextDay.Add(-time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, 1.2)); err != nil {
+		t.Fatalf("failed to save request: %v", err)
+	}
+	periodFactory.dailyPeriod = entity.NewPeriod(time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), nextDay)
+	execute(3)
+	if !strings.Contains(notifier.messages[2], "$1.20, past your $1.00 alert") {
+		t.Errorf("expected the new day to alert on its own cost, got %q", notifier.messages[2])
+	}
+}
+
+func TestNotifyCostAlertsCommand_NotifyFailure(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, 1.5),
+	})
+	statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+	periodFactory := &MockPeriodFactory{dailyPeriod: entity.NewPeriod(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), now)}
+	summaryQuery := usecase.NewGetSummaryQuery(statsQuery, testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))), periodFactory)
+	notifier := &fakeNotifier{supported: true, err: errors.New("notification daemon not running")}
+
+	command := usecase.NewNotifyCostAlertsCommand(summaryQuery, periodFactory, notifier)
+	command.SetThresholds([]float64{1.0}, nil)
+
+	if err := command.Execute(context.Background()); err == nil {
+		t.Fatal("expected the failed notification to be reported")
+	}
+	// The failed alert is not sent again on the next refresh
+	if err := command.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.messages) != 1 {
+		t.Errorf("expected a single notification attempt, got %q", notifier.messages)
+	}
+}
+
+func TestNotifyCostAlertsCommand_QuietHours(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	overnight, err := entity.ParseQuietHours("22:00-07:00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 23:00 in Tokyo, inside quiet hours even though it is 14:00 UTC
+	now := time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC)
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, 1.5),
+	})
+	statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+	periodFactory := &MockPeriodFactory{dailyPeriod: entity.NewPeriod(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), now.Add(time.Hour))}
+	summaryQuery := usecase.NewGetSummaryQuery(statsQuery, testutil.NewMockPlanRepository(entity.NewPlan("unset", entity.NewCost(0))), periodFactory)
+	notifier := &fakeNotifier{supported: true}
+
+	command := usecase.NewNotifyCostAlertsCommand(summaryQuery, periodFactory, notifier)
+	command.SetThresholds([]float64{1.0}, nil)
+	command.SetQuietHours([]entity.QuietHours{overnight}, tokyo)
+	command.SetClock(entity.NewFixedClock(now))
+
+	if err := command.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.messages) != 0 {
+		t.Fatalf("expected no notifications during quiet hours, got %q", notifier.messages)
+	}
+
+	// The threshold crossed during quiet hours alerts once they end
+	command.SetClock(entity.NewFixedClock(time.Date(2025, 1, 15, 14, 59, 0, 0, time.UTC))) // 23:59 in Tokyo
+	if err := command.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.messages) != 0 {
+		t.Fatalf("expected no notifications during quiet hours, got %q", notifier.messages)
+	}
+
+	command.SetClock(entity.NewFixedClock(time.Date(2025, 1, 15, 22, 0, 0, 0, time.UTC))) // 07:00 in Tokyo
+	if err := command.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.messages) != 1 || !strings.Contains(notifier.messages[0], "past your $1.00 alert") {
+		t.Errorf("expected one cost alert after quiet hours, got %q", notifier.messages)
+	}
+}
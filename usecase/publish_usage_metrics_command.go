@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// PublishUsageMetricsCommand measures today's and this month's cost and requests, and the current
+// block usage when a token limit is set, and pushes them to a metrics pipeline as gauges
+type PublishUsageMetricsCommand struct {
+	statsQuery        *CalculateStatsQuery
+	firstRequestQuery *GetFirstRequestTimeQuery
+	periodFactory     PeriodFactory
+	publisher         MetricsPublisher
+	clock             entity.Clock
+	timezone          *time.Location
+	tokenLimit        int
+	tokenMetric       entity.BlockTokenMetric
+}
+
+// NewPublishUsageMetricsCommand creates a new PublishUsageMetricsCommand with the given dependencies
+func NewPublishUsageMetricsCommand(statsQuery *CalculateStatsQuery, firstRequestQuery *GetFirstRequestTimeQuery, periodFactory PeriodFactory, publisher MetricsPublisher) *PublishUsageMetricsCommand {
+	return &PublishUsageMetricsCommand{
+		statsQuery:        statsQuery,
+		firstRequestQuery: firstRequestQuery,
+		periodFactory:     periodFactory,
+		publisher:         publisher,
+		clock:             entity.SystemClock{},
+		timezone:          time.UTC,
+	}
+}
+
+// SetClock changes the source of the current time used to find the block
+func (c *PublishUsageMetricsCommand) SetClock(clock entity.Clock) {
+	c.clock = clock
+}
+
+// SetBlockLimit enables the block gauges. The block is anchored at the hour of today's first request
+// in the timezone, as with monitor.auto_block; a zero token limit disables them.
+func (c *PublishUsageMetricsCommand) SetBlockLimit(timezone *time.Location, tokenLimit int) {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+	c.timezone = timezone
+	c.tokenLimit = tokenLimit
+}
+
+// SetBlockTokenMetric selects which premium tokens the block gauges count against the limit
+func (c *PublishUsageMetricsCommand) SetBlockTokenMetric(metric entity.BlockTokenMetric) {
+	c.tokenMetric = metric
+}
+
+// Execute measures the gauges and publishes them, returning the published values
+func (c *PublishUsageMetricsCommand) Execute(ctx context.Context) ([]entity.GaugeValue, error) {
+	dailyPeriod := c.periodFactory.CreateDaily()
+	dailyStats, err := c.statsQuery.Execute(ctx, CalculateStatsParams{Period: dailyPeriod})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate daily stats: %w", err)
+	}
+
+	monthlyStats, err := c.statsQuery.Execute(ctx, CalculateStatsParams{Period: c.periodFactory.CreateMonthly()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate monthly stats: %w", err)
+	}
+
+	values := []entity.GaugeValue{
+		entity.NewGaugeValue(entity.DailyCostGauge, dailyStats.TotalCost().Amount()),
+		entity.NewGaugeValue(entity.MonthlyCostGauge, monthlyStats.TotalCost().Amount()),
+		entity.NewGaugeValue(entity.DailyRequestsGauge, float64(dailyStats.TotalRequests())),
+		entity.NewGaugeValue(entity.MonthlyRequestsGauge, float64(monthlyStats.TotalRequests())),
+	}
+
+	if c.tokenLimit > 0 {
+		firstRequestAt, _, err := c.firstRequestQuery.Execute(ctx, GetFirstRequestTimeParams{Period: dailyPeriod})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the first request of the day: %w", err)
+		}
+
+		block := entity.InferBlock(firstRequestAt, c.timezone, c.clock.Now(), c.tokenLimit).WithTokenMetric(c.tokenMetric)
+		blockStats, err := c.statsQuery.Execute(ctx, CalculateStatsParams{Period: block.Period()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate block stats: %w", err)
+		}
+
+		values = append(values,
+			entity.NewGaugeValue(entity.BlockTokensGauge, float64(block.UsedTokens(blockStats.PremiumTokens()))),
+			entity.NewGaugeValue(entity.BlockUsageGauge, block.CalculateProgress(blockStats.PremiumTokens())),
+		)
+	}
+
+	if err := c.publisher.Publish(values); err != nil {
+		return values, fmt.Errorf("failed to publish usage metrics: %w", err)
+	}
+	return values, nil
+}
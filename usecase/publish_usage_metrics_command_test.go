@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// fakeMetricsPublisher records the published gauges
+type fakeMetricsPublisher struct {
+	published []entity.GaugeValue
+	err       error
+}
+
+func (p *fakeMetricsPublisher) Publish(values []entity.GaugeValue) error {
+	p.published = values
+	return p.err
+}
+
+func TestPublishUsageMetricsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 15, 12, 30, 0, 0, time.UTC)
+	periodFactory := &MockPeriodFactory{
+		dailyPeriod:   entity.NewPeriod(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)),
+		monthlyPeriod: entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)),
+	}
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC), "claude-3-5-sonnet-20241022", 1000, 500, 2.0),
+		entity.NewAPIRequest("session-2", time.Date(2025, 1, 15, 9, 40, 0, 0, time.UTC), "claude-3-5-sonnet-20241022", entity.NewToken(2000, 1500, 2800, 700), entity.NewCost(1.5), 0),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 400, 100, 0.25),
+	}
+
+	tests := []struct {
+		name        string
+		tokenLimit  int
+		tokenMetric entity.BlockTokenMetric
+		expected    map[entity.UsageGauge]float64
+	}{
+		{
+			name: "without a token limit only cost and requests",
+			expected: map[entity.UsageGauge]float64{
+				entity.DailyCostGauge:       1.75,
+				entity.MonthlyCostGauge:     3.75,
+				entity.DailyRequestsGauge:   2,
+				entity.MonthlyRequestsGauge: 3,
+			},
+		},
+		{
+			name:       "with a token limit the block inferred from the first request",
+			tokenLimit: 7000,
+			expected: map[entity.UsageGauge]float64{
+				entity.DailyCostGauge:       1.75,
+				entity.MonthlyCostGauge:     3.75,
+				entity.DailyRequestsGauge:   2,
+				entity.MonthlyRequestsGauge: 3,
+				entity.BlockTokensGauge:     3500, // Block 9am-2pm, premium input + output only
+				entity.BlockUsageGauge:      50,
+			},
+		},
+		{
+			name:        "with the total token metric cache tokens count against the limit",
+			tokenLimit:  7000,
+			tokenMetric: entity.BlockTokenMetricTotal,
+			expected: map[entity.UsageGauge]float64{
+				entity.DailyCostGauge:       1.75,
+				entity.MonthlyCostGauge:     3.75,
+				entity.DailyRequestsGauge:   2,
+				entity.MonthlyRequestsGauge: 3,
+				entity.BlockTokensGauge:     7000, // Premium input, output and cache tokens
+				entity.BlockUsageGauge:      100,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiRepo, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			publisher := &fakeMetricsPublisher{}
+
+			command := usecase.NewPublishUsageMetricsCommand(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache()),
+				usecase.NewGetFirstRequestTimeQuery(apiRepo),
+				periodFactory,
+				publisher,
+			)
+			command.SetClock(entity.NewFixedClock(now))
+			command.SetBlockLimit(time.UTC, tt.tokenLimit)
+			command.SetBlockTokenMetric(tt.tokenMetric)
+
+			values, err := command.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(publisher.published) != len(tt.expected) {
+				t.Fatalf("expected %d published gauges, got %d", len(tt.expected), len(publisher.published))
+			}
+			if len(values) != len(publisher.published) {
+				t.Errorf("expected the returned values to match the published ones")
+			}
+			for _, value := range publisher.published {
+				want, ok := tt.expected[value.Gauge()]
+				if !ok {
+					t.Errorf("unexpected gauge %s", value.Gauge().Key())
+					continue
+				}
+				if diff := value.Value() - want; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("gauge %s: expected %v, got %v", value.Gauge().Key(), want, value.Value())
+				}
+			}
+		})
+	}
+}
+
+func TestPublishUsageMetricsCommand_PublishError(t *testing.T) {
+	t.Parallel()
+
+	_, statsRepo := testutil.NewMockRepositoryPair()
+	publisher := &fakeMetricsPublisher{err: errors.New("connection refused")}
+	command := usecase.NewPublishUsageMetricsCommand(
+		usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache()),
+		nil,
+		&MockPeriodFactory{},
+		publisher,
+	)
+
+	if _, err := command.Execute(context.Background()); err == nil {
+		t.Error("expected the publisher error to be returned")
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// ReplayProgress counts the requests handled so far by a replay
+type ReplayProgress struct {
+	Total   int       // Requests in the source store
+	Sent    int       // Requests submitted to the target
+	Skipped int       // Requests the target already stored
+	Day     time.Time // UTC day of the requests handled last
+}
+
+// Done returns how many requests were sent or skipped
+func (p ReplayProgress) Done() int {
+	return p.Sent + p.Skipped
+}
+
+// ReplayApiRequestsCommand submits the requests of a source store to a target server's receiver in
+// timestamp order. Requests the target already stores under the same ID are skipped, so replaying
+// again after an interruption resumes where it stopped instead of sending duplicates.
+type ReplayApiRequestsCommand struct {
+	source   APIRequestRepository
+	target   APIRequestRepository
+	ingester TelemetryIngester
+}
+
+// NewReplayApiRequestsCommand creates a new ReplayApiRequestsCommand reading from source, looking up
+// existing requests in target and submitting the missing ones through ingester
+func NewReplayApiRequestsCommand(source APIRequestRepository, target APIRequestRepository, ingester TelemetryIngester) *ReplayApiRequestsCommand {
+	return &ReplayApiRequestsCommand{
+		source:   source,
+		target:   target,
+		ingester: ingester,
+	}
+}
+
+// Execute replays every source request one UTC day at a time, calling onProgress after each day.
+// The returned progress covers the requests handled before any error.
+func (c *ReplayApiRequestsCommand) Execute(ctx context.Context, onProgress func(ReplayProgress)) (ReplayProgress, error) {
+	// FindAll keeps only the latest requests of large stores, an explicit range returns every one
+	everything := entity.NewPeriod(time.Unix(0, 0).UTC(), time.Now().UTC().Add(24*time.Hour))
+	requests, err := c.source.FindByPeriodWithLimit(everything, 0, 0)
+	if err != nil {
+		return ReplayProgress{}, fmt.Errorf("failed to read source requests: %w", err)
+	}
+	// Leftover health check probes are not usage worth consolidating
+	requests = slices.DeleteFunc(slices.Clone(requests), func(req entity.APIRequest) bool {
+		return req.IsHealthCheck()
+	})
+	slices.SortStableFunc(requests, func(a, b entity.APIRequest) int {
+		return a.Timestamp().Compare(b.Timestamp())
+	})
+
+	progress := ReplayProgress{Total: len(requests)}
+	for start := 0; start < len(requests); {
+		day := requests[start].Timestamp().UTC().Truncate(24 * time.Hour)
+		end := start
+		for end < len(requests) && requests[end].Timestamp().UTC().Truncate(24*time.Hour).Equal(day) {
+			end++
+		}
+
+		// One lookup per day keeps each query within the target's max_query_period
+		existing, err := c.target.FindByPeriodWithLimit(entity.NewPeriod(day, day.Add(24*time.Hour-time.Nanosecond)), 0, 0)
+		if err != nil {
+			return progress, fmt.Errorf("failed to read target requests of %s: %w", day.Format(time.DateOnly), err)
+		}
+		stored := make(map[string]bool, len(existing))
+		for _, req := range existing {
+			stored[replayID(req)] = true
+		}
+
+		progress.Day = day
+		for _, req := range requests[start:end] {
+			if stored[replayID(req)] {
+				progress.Skipped++
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return progress, err
+			}
+			if err := c.ingester.Ingest(ctx, req); err != nil {
+				return progress, fmt.Errorf("failed to replay request of session %s at %s: %w", req.SessionID(), req.Timestamp().UTC().Format(time.RFC3339), err)
+			}
+			progress.Sent++
+		}
+
+		if onProgress != nil {
+			onProgress(progress)
+		}
+		start = end
+	}
+
+	return progress, nil
+}
+
+// replayID identifies a request the way the store keys it (see entity.APIRequest.ID), in UTC so both stores agree
+func replayID(req entity.APIRequest) string {
+	return req.Timestamp().UTC().Format(time.RFC3339Nano) + "_" + req.SessionID()
+}
@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestReplayApiRequestsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	day1 := time.Date(2025, 7, 1, 23, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 7, 2, 1, 0, 0, 0, time.UTC)
+	first := testutil.CreateTestAPIRequest("session-1", day1, "claude-sonnet-4-20250514", 100, 50, 0.5)
+	second := testutil.CreateTestAPIRequest("session-1", day1.Add(30*time.Minute), "claude-sonnet-4-20250514", 100, 50, 0.5)
+	third := testutil.CreateTestAPIRequest("session-2", day2, "claude-3-5-haiku-20241022", 10, 5, 0.01)
+	// Another session at the same instant is a different request
+	sameInstant := testutil.CreateTestAPIRequest("session-3", day2, "claude-3-5-haiku-20241022", 10, 5, 0.01)
+
+	tests := []struct {
+		name         string
+		source       []entity.APIRequest
+		target       []entity.APIRequest
+		ingestErr    error
+		expected     usecase.ReplayProgress
+		expectedDays int
+		expectedErr  bool
+	}{
+		{
+			name:         "sends every request to an empty target",
+			source:       []entity.APIRequest{third, first, second},
+			expected:     usecase.ReplayProgress{Total: 3, Sent: 3, Day: time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)},
+			expectedDays: 2,
+		},
+		{
+			name:         "skips requests the target already stores",
+			source:       []entity.APIRequest{first, second, third, sameInstant},
+			target:       []entity.APIRequest{first, third},
+			expected:     usecase.ReplayProgress{Total: 4, Sent: 2, Skipped: 2, Day: time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)},
+			expectedDays: 2,
+		},
+		{
+			name:     "empty source",
+			expected: usecase.ReplayProgress{},
+		},
+		{
+			name:        "ingest failure stops the replay",
+			source:      []entity.APIRequest{first, second},
+			ingestErr:   errors.New("connection refused"),
+			expected:    usecase.ReplayProgress{Total: 2, Day: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := testutil.NewMockAPIRequestRepository()
+			source.SetMockData(tt.source)
+			target := testutil.NewMockAPIRequestRepository()
+			target.SetMockData(append([]entity.APIRequest(nil), tt.target...))
+			ingester := &fakeIngester{repo: target, err: tt.ingestErr}
+
+			days := 0
+			command := usecase.NewReplayApiRequestsCommand(source, target, ingester)
+			progress, err := command.Execute(context.Background(), func(usecase.ReplayProgress) { days++ })
+
+			if tt.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if progress != tt.expected {
+				t.Errorf("expected progress %+v, got %+v", tt.expected, progress)
+			}
+			if days != tt.expectedDays {
+				t.Errorf("expected %d progress reports, got %d", tt.expectedDays, days)
+			}
+
+			// Replaying again finds everything in place
+			if err == nil {
+				again, err := command.Execute(context.Background(), nil)
+				if err != nil {
+					t.Fatalf("unexpected error on second replay: %v", err)
+				}
+				if again.Sent != 0 || again.Skipped != len(tt.source) {
+					t.Errorf("expected the second replay to skip all %d requests, got %+v", len(tt.source), again)
+				}
+			}
+		})
+	}
+}
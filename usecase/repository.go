@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+	"io"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
@@ -11,6 +13,9 @@ type APIRequestRepository interface {
 	// Save stores an API request entity
 	Save(req entity.APIRequest) error
 
+	// SaveBatch stores API request entities all at once, storing none of them on error
+	SaveBatch(reqs []entity.APIRequest) error
+
 	// FindByPeriodWithLimit retrieves API requests filtered by time period with limit and offset
 	// Use limit = 0 for no limit (fetch all records)
 	// Use offset = 0 when no offset is needed
@@ -22,16 +27,113 @@ type APIRequestRepository interface {
 	// DeleteOlderThan deletes API requests older than the specified cutoff time
 	// Returns the number of deleted records and any error
 	DeleteOlderThan(cutoffTime time.Time) (int, error)
+
+	// DeleteByPeriod deletes API requests whose timestamp falls within the period
+	// Returns the number of deleted records and any error
+	DeleteByPeriod(period entity.Period) (int, error)
+
+	// UpdateNote replaces the note of the request identified by session ID and timestamp
+	// Returns false when no request matches
+	UpdateNote(sessionID string, timestamp time.Time, note string) (bool, error)
+}
+
+// DurationFilterRepository defines the repository interface for filtering requests by duration
+// where they are stored, so a limited list does not need every request of the period
+type DurationFilterRepository interface {
+	// FindByPeriodAndDuration retrieves API requests of the period whose duration falls in the range,
+	// applying limit and offset after filtering
+	// Returns ErrDurationFilterUnsupported when the store cannot filter by duration
+	FindByPeriodAndDuration(period entity.Period, durationRange entity.DurationRange, limit int, offset int) ([]entity.APIRequest, error)
+}
+
+// RecordLimitRepository defines the repository interface for bounding the number of stored requests
+type RecordLimitRepository interface {
+	// CountRequests returns the number of stored API requests
+	CountRequests() (int, error)
+
+	// DeleteOldestBeyond deletes the oldest API requests until at most maxRecords remain,
+	// never evicting health check probes
+	// Returns the number of deleted records and any error
+	DeleteOldestBeyond(maxRecords int) (int, error)
+}
+
+// ModelRepository defines the repository interface for summarizing the stored models
+type ModelRepository interface {
+	// FindModels returns every stored model with its request count and first and last use
+	FindModels() ([]entity.ModelUsage, error)
 }
 
 // PlanRepository defines the repository interface for plan configuration access
 type PlanRepository interface {
-	// GetConfiguredPlan retrieves the configured plan from the repository
+	// GetConfiguredPlan retrieves the configured plan from the repository,
+	// returning ErrPlanUnavailable when no plan can be resolved
 	GetConfiguredPlan() (entity.Plan, error)
 }
 
 // StatsRepository defines the repository interface for statistics access
 type StatsRepository interface {
-	// GetStatsByPeriod retrieves aggregated statistics for a given period
-	GetStatsByPeriod(period entity.Period) (entity.Stats, error)
+	// GetStats retrieves aggregated statistics for the query period, including the negligible
+	// bucket when the query sets a threshold
+	GetStats(query entity.StatsQuery) (entity.Stats, error)
+}
+
+// StatsStreamRepository defines the repository interface for stats pushed as they change
+type StatsStreamRepository interface {
+	// WatchStats calls onUpdate with the stats of the watched period on every update,
+	// blocking until ctx is done or the stream fails
+	WatchStats(ctx context.Context, params WatchStatsParams, onUpdate func(entity.Stats)) error
+}
+
+// ModelTierRepository defines the repository interface for model tier overrides
+type ModelTierRepository interface {
+	// GetClassifier returns a classifier with the current overrides
+	GetClassifier() entity.ModelClassifier
+
+	// SaveClassifier persists the overrides of the classifier, replacing the previous ones
+	SaveClassifier(classifier entity.ModelClassifier) error
+}
+
+// SnapshotRepository defines the repository interface for whole-store backups
+type SnapshotRepository interface {
+	// WriteSnapshot writes a consistent copy of the store to w and returns the number of requests it holds
+	WriteSnapshot(w io.Writer) (int, error)
+}
+
+// StorageInfoRepository defines the repository interface for describing the store itself
+type StorageInfoRepository interface {
+	// GetStorageInfo returns the size of the store on disk, its record count and the stored time range
+	GetStorageInfo() (entity.StorageInfo, error)
+}
+
+// Notifier defines the interface for delivering alerts to the user outside the terminal
+type Notifier interface {
+	// IsSupported returns false when the platform cannot show notifications
+	IsSupported() bool
+
+	// Notify shows a notification with the given title and message
+	Notify(title, message string) error
+}
+
+// BudgetSignal defines the interface for telling external tools that the monthly budget is used up
+type BudgetSignal interface {
+	// Raise signals the exceeded budget with the current usage, replacing any previous snapshot
+	Raise(snapshot entity.BudgetSnapshot) error
+
+	// Clear withdraws the signal, doing nothing when it is not raised
+	Clear() error
+}
+
+// MetricsPublisher defines the interface for pushing usage gauges to an external metrics pipeline
+type MetricsPublisher interface {
+	// Publish sends the measured gauges
+	Publish(values []entity.GaugeValue) error
+}
+
+// ExportArchive defines the interface for keeping exported requests under a name, e.g. as files in a directory
+type ExportArchive interface {
+	// Exists returns true when an export with the name is already kept
+	Exists(name string) (bool, error)
+
+	// Write keeps the requests as the named export
+	Write(name string, requests []entity.APIRequest) error
 }
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// SetModelTierCommand reassigns a model to a tier and persists the override
+type SetModelTierCommand struct {
+	tierRepository ModelTierRepository
+}
+
+// NewSetModelTierCommand creates a new SetModelTierCommand with the given repository
+func NewSetModelTierCommand(tierRepository ModelTierRepository) *SetModelTierCommand {
+	return &SetModelTierCommand{
+		tierRepository: tierRepository,
+	}
+}
+
+// SetModelTierParams identifies the model to reassign; an empty tier restores the default classification
+type SetModelTierParams struct {
+	Model string
+	Tier  entity.ModelTier
+}
+
+// Execute executes the set model tier command
+func (c *SetModelTierCommand) Execute(ctx context.Context, params SetModelTierParams) error {
+	if strings.TrimSpace(params.Model) == "" {
+		return fmt.Errorf("model is required")
+	}
+	if params.Tier != "" {
+		if _, err := entity.ParseModelTier(string(params.Tier)); err != nil {
+			return err
+		}
+	}
+
+	classifier := c.tierRepository.GetClassifier().WithOverride(entity.NewModel(params.Model), params.Tier)
+	if err := c.tierRepository.SaveClassifier(classifier); err != nil {
+		return fmt.Errorf("failed to save model tier: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestSetModelTierCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		initial      map[string]entity.ModelTier
+		params       usecase.SetModelTierParams
+		saveErr      error
+		expectedErr  bool
+		model        string
+		expectedTier entity.ModelTier
+	}{
+		{
+			name:         "reassign model to base",
+			params:       usecase.SetModelTierParams{Model: "local-llama", Tier: entity.ModelTierBase},
+			model:        "local-llama",
+			expectedTier: entity.ModelTierBase,
+		},
+		{
+			name:         "empty tier restores default classification",
+			initial:      map[string]entity.ModelTier{"claude-3-haiku-20240307": entity.ModelTierPremium},
+			params:       usecase.SetModelTierParams{Model: "claude-3-haiku-20240307"},
+			model:        "claude-3-haiku-20240307",
+			expectedTier: entity.ModelTierBase,
+		},
+		{
+			name:        "missing model is rejected",
+			params:      usecase.SetModelTierParams{Tier: entity.ModelTierBase},
+			expectedErr: true,
+		},
+		{
+			name:        "unknown tier is rejected",
+			params:      usecase.SetModelTierParams{Model: "local-llama", Tier: "sonnet"},
+			expectedErr: true,
+		},
+		{
+			name:        "save failure is reported",
+			params:      usecase.SetModelTierParams{Model: "local-llama", Tier: entity.ModelTierBase},
+			saveErr:     errors.New("read-only config"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockModelTierRepository(entity.NewModelClassifier(tt.initial))
+			repo.SetError(tt.saveErr)
+
+			err := usecase.NewSetModelTierCommand(repo).Execute(context.Background(), tt.params)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if repo.SaveCount() != 0 {
+					t.Error("expected nothing to be saved")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tier := repo.GetClassifier().Classify(entity.NewModel(tt.model)); tier != tt.expectedTier {
+				t.Errorf("expected tier %q, got %q", tt.expectedTier, tier)
+			}
+		})
+	}
+}
+
+func TestListModelTiersQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	apiRepo := testutil.NewMockAPIRequestRepository()
+	apiRepo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("s1", now.Add(-3*time.Minute), "claude-3-haiku-20240307", 10, 5, 0.01),
+		testutil.CreateTestAPIRequest("s1", now.Add(-2*time.Minute), "local-llama", 10, 5, 0.01),
+		testutil.CreateTestAPIRequest("s1", now.Add(-time.Minute), "local-llama", 10, 5, 0.01),
+	})
+	tierRepo := testutil.NewMockModelTierRepository(entity.NewModelClassifier(map[string]entity.ModelTier{"local-llama": entity.ModelTierBase}))
+
+	query := usecase.NewListModelTiersQuery(usecase.NewGetFilteredApiRequestsQuery(apiRepo), tierRepo)
+	models, err := query.Execute(context.Background(), usecase.ListModelTiersParams{Period: entity.NewAllTimePeriod(now)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []usecase.ModelTierUsage{
+		{Model: "local-llama", Requests: 2, Tier: entity.ModelTierBase, Overridden: true},
+		{Model: "claude-3-haiku-20240307", Requests: 1, Tier: entity.ModelTierBase, Overridden: false},
+	}
+	if len(models) != len(expected) {
+		t.Fatalf("expected %d models, got %d: %+v", len(expected), len(models), models)
+	}
+	for i := range expected {
+		if models[i] != expected[i] {
+			t.Errorf("model %d: expected %+v, got %+v", i, expected[i], models[i])
+		}
+	}
+}
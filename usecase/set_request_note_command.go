@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxRequestNoteLength is the maximum number of characters stored in a request note
+const MaxRequestNoteLength = 500
+
+var (
+	// ErrRequestNotFound is returned when no request matches the session ID and timestamp
+	ErrRequestNotFound = errors.New("request not found")
+	// ErrRequestNoteTooLong is returned when a note exceeds MaxRequestNoteLength
+	ErrRequestNoteTooLong = fmt.Errorf("note must be at most %d characters", MaxRequestNoteLength)
+)
+
+// SetRequestNoteCommand handles the command to annotate a stored request with a manual note.
+// The note is stored with the request, so it is removed only when the request itself is pruned.
+type SetRequestNoteCommand struct {
+	repository APIRequestRepository
+}
+
+// NewSetRequestNoteCommand creates a new SetRequestNoteCommand with the given repository
+func NewSetRequestNoteCommand(repository APIRequestRepository) *SetRequestNoteCommand {
+	return &SetRequestNoteCommand{
+		repository: repository,
+	}
+}
+
+// SetRequestNoteParams identifies the request to annotate; an empty note clears it
+type SetRequestNoteParams struct {
+	SessionID string
+	Timestamp time.Time
+	Note      string
+}
+
+// Execute executes the set request note command
+func (c *SetRequestNoteCommand) Execute(ctx context.Context, params SetRequestNoteParams) error {
+	if params.SessionID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	note := strings.TrimSpace(params.Note)
+	if utf8.RuneCountInString(note) > MaxRequestNoteLength {
+		return ErrRequestNoteTooLong
+	}
+
+	found, err := c.repository.UpdateNote(params.SessionID, params.Timestamp, note)
+	if err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	if !found {
+		return ErrRequestNotFound
+	}
+
+	return nil
+}
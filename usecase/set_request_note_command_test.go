@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestSetRequestNoteCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		params       usecase.SetRequestNoteParams
+		expectedNote string
+		expectedErr  error
+		wantErr      bool
+	}{
+		{
+			name:         "sets note on matching request",
+			params:       usecase.SetRequestNoteParams{SessionID: "session-a", Timestamp: base, Note: "  the big refactor run  "},
+			expectedNote: "the big refactor run",
+		},
+		{
+			name:         "empty note clears existing note",
+			params:       usecase.SetRequestNoteParams{SessionID: "session-a", Timestamp: base, Note: ""},
+			expectedNote: "",
+		},
+		{
+			name:        "unknown request is reported as not found",
+			params:      usecase.SetRequestNoteParams{SessionID: "session-a", Timestamp: base.Add(time.Second), Note: "missing"},
+			expectedErr: usecase.ErrRequestNotFound,
+		},
+		{
+			name:        "note over the length limit is rejected",
+			params:      usecase.SetRequestNoteParams{SessionID: "session-a", Timestamp: base, Note: strings.Repeat("x", usecase.MaxRequestNoteLength+1)},
+			expectedErr: usecase.ErrRequestNoteTooLong,
+		},
+		{
+			name:    "missing session ID is rejected",
+			params:  usecase.SetRequestNoteParams{Timestamp: base, Note: "note"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-a", base, "claude-3-5-sonnet-20241022", 100, 50, 0.01).WithNote("previous"),
+				testutil.CreateTestAPIRequest("session-b", base, "claude-3-haiku-20240307", 100, 50, 0.01),
+			})
+
+			err := usecase.NewSetRequestNoteCommand(repo).Execute(context.Background(), tt.params)
+
+			if tt.expectedErr != nil || tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			requests, _ := repo.FindAll()
+			if requests[0].Note() != tt.expectedNote {
+				t.Errorf("Expected note %q, got %q", tt.expectedNote, requests[0].Note())
+			}
+			if requests[1].Note() != "" {
+				t.Errorf("Expected other session to keep an empty note, got %q", requests[1].Note())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// SignalBudgetCommand raises the budget signal while the monthly plan usage is at or above 100%
+// and clears it otherwise, e.g. once a new month starts
+type SignalBudgetCommand struct {
+	statsQuery     *CalculateStatsQuery
+	planRepository PlanRepository
+	periodFactory  PeriodFactory
+	signal         BudgetSignal
+	clock          entity.Clock
+}
+
+// NewSignalBudgetCommand creates a new SignalBudgetCommand with the given dependencies
+func NewSignalBudgetCommand(statsQuery *CalculateStatsQuery, planRepository PlanRepository, periodFactory PeriodFactory, signal BudgetSignal) *SignalBudgetCommand {
+	return &SignalBudgetCommand{
+		statsQuery:     statsQuery,
+		planRepository: planRepository,
+		periodFactory:  periodFactory,
+		signal:         signal,
+		clock:          entity.SystemClock{},
+	}
+}
+
+// SetClock changes the source of the snapshot time
+func (c *SignalBudgetCommand) SetClock(clock entity.Clock) {
+	c.clock = clock
+}
+
+// Execute checks this month's plan usage and raises or clears the signal, returning the snapshot it was based on
+func (c *SignalBudgetCommand) Execute(ctx context.Context) (entity.BudgetSnapshot, error) {
+	// Without a configured plan there is no budget to exceed
+	plan, err := configuredPlanOrUnset(c.planRepository)
+	if err != nil {
+		return entity.BudgetSnapshot{}, err
+	}
+
+	monthlyPeriod := c.periodFactory.CreateMonthly()
+	monthlyStats, err := c.statsQuery.Execute(ctx, CalculateStatsParams{
+		Period: monthlyPeriod,
+	})
+	if err != nil {
+		return entity.BudgetSnapshot{}, fmt.Errorf("failed to calculate monthly stats: %w", err)
+	}
+
+	snapshot := entity.NewBudgetSnapshot(plan, monthlyStats.TotalCost(), monthlyPeriod, c.clock.Now())
+	if snapshot.IsExceeded() {
+		if err := c.signal.Raise(snapshot); err != nil {
+			return snapshot, fmt.Errorf("failed to raise budget signal: %w", err)
+		}
+		return snapshot, nil
+	}
+
+	if err := c.signal.Clear(); err != nil {
+		return snapshot, fmt.Errorf("failed to clear budget signal: %w", err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// fakeBudgetSignal records whether the signal is raised and the last snapshot
+type fakeBudgetSignal struct {
+	raised   bool
+	snapshot entity.BudgetSnapshot
+}
+
+func (s *fakeBudgetSignal) Raise(snapshot entity.BudgetSnapshot) error {
+	s.raised = true
+	s.snapshot = snapshot
+	return nil
+}
+
+func (s *fakeBudgetSignal) Clear() error {
+	s.raised = false
+	return nil
+}
+
+func TestSignalBudgetCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	monthlyPeriod := entity.NewPeriod(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), now)
+
+	tests := []struct {
+		name           string
+		plan           entity.Plan
+		monthlyCost    float64
+		alreadyRaised  bool
+		expectedRaised bool
+		expectedUsage  int
+	}{
+		{
+			name:           "usage past the budget raises the signal",
+			plan:           entity.NewPlan("pro", entity.NewCost(20)),
+			monthlyCost:    25,
+			expectedRaised: true,
+			expectedUsage:  125,
+		},
+		{
+			name:           "usage at the budget raises the signal",
+			plan:           entity.NewPlan("pro", entity.NewCost(20)),
+			monthlyCost:    20,
+			expectedRaised: true,
+			expectedUsage:  100,
+		},
+		{
+			name:          "usage below the budget clears a raised signal",
+			plan:          entity.NewPlan("pro", entity.NewCost(20)),
+			monthlyCost:   5,
+			alreadyRaised: true,
+			expectedUsage: 25,
+		},
+		{
+			name:          "unset plan never raises the signal",
+			plan:          entity.NewPlan("unset", entity.NewCost(0)),
+			monthlyCost:   500,
+			alreadyRaised: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-sonnet-20241022", 1000, 500, tt.monthlyCost),
+			})
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+			periodFactory := &MockPeriodFactory{monthlyPeriod: monthlyPeriod}
+			signal := &fakeBudgetSignal{raised: tt.alreadyRaised}
+
+			command := usecase.NewSignalBudgetCommand(statsQuery, testutil.NewMockPlanRepository(tt.plan), periodFactory, signal)
+			command.SetClock(entity.NewFixedClock(now))
+
+			snapshot, err := command.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if signal.raised != tt.expectedRaised {
+				t.Errorf("expected raised = %v, got %v", tt.expectedRaised, signal.raised)
+			}
+			if snapshot.Usage() != tt.expectedUsage {
+				t.Errorf("expected usage %d%%, got %d%%", tt.expectedUsage, snapshot.Usage())
+			}
+			if tt.expectedRaised && !signal.snapshot.TakenAt().Equal(now) {
+				t.Errorf("expected snapshot taken at %v, got %v", now, signal.snapshot.TakenAt())
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// WatchStatsQuery receives the stats of a period as the server pushes them, instead of polling
+type WatchStatsQuery struct {
+	repository     StatsStreamRepository
+	negligibleCost entity.Cost
+}
+
+// NewWatchStatsQuery creates a new WatchStatsQuery with the given stats stream repository
+func NewWatchStatsQuery(repository StatsStreamRepository) *WatchStatsQuery {
+	return &WatchStatsQuery{
+		repository: repository,
+	}
+}
+
+// SetNegligibleCost counts the requests cheaper than the threshold in the negligible bucket of
+// every update; a non-positive threshold disables it
+func (q *WatchStatsQuery) SetNegligibleCost(threshold entity.Cost) {
+	q.negligibleCost = threshold
+}
+
+// WatchStatsParams selects the watched period: fixed from Start, or a rolling Window ending now
+type WatchStatsParams struct {
+	Start  time.Time     // Fixed start, e.g. a block; zero for a rolling window
+	End    time.Time     // Fixed end; zero to include up to the current time
+	Window time.Duration // Rolling window when Start is zero; zero includes all time
+
+	NegligibleCost entity.Cost // Set from the query's threshold, requests cheaper than it are counted in their own bucket
+}
+
+// Execute calls onUpdate with every stats update until ctx is done or the stream fails.
+// Stopping through ctx is not a failure, so it returns nil then.
+func (q *WatchStatsQuery) Execute(ctx context.Context, params WatchStatsParams, onUpdate func(entity.Stats)) error {
+	params.NegligibleCost = q.negligibleCost
+	err := q.repository.WatchStats(ctx, params, onUpdate)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return repositoryError(err)
+}
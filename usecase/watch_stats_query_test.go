@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// mockStatsStreamRepository sends the given stats, then fails with err or waits for ctx
+type mockStatsStreamRepository struct {
+	updates []entity.Stats
+	err     error
+	params  WatchStatsParams
+}
+
+func (m *mockStatsStreamRepository) WatchStats(ctx context.Context, params WatchStatsParams, onUpdate func(entity.Stats)) error {
+	m.params = params
+	for _, stats := range m.updates {
+		onUpdate(stats)
+	}
+	if m.err != nil {
+		return m.err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWatchStatsQuery_Execute(t *testing.T) {
+	t.Parallel()
+
+	period := entity.NewPeriodFromDuration(time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC), time.Hour)
+	first := entity.NewStats(1, 0, entity.NewToken(100, 50, 0, 0), entity.Token{}, entity.NewCost(0.1), entity.Cost{}, period)
+	second := entity.NewStats(2, 0, entity.NewToken(200, 100, 0, 0), entity.Token{}, entity.NewCost(0.2), entity.Cost{}, period)
+
+	tests := []struct {
+		name        string
+		repository  *mockStatsStreamRepository
+		expectError bool
+	}{
+		{
+			name:       "updates until stopped",
+			repository: &mockStatsStreamRepository{updates: []entity.Stats{first, second}},
+		},
+		{
+			name:        "stream failure",
+			repository:  &mockStatsStreamRepository{updates: []entity.Stats{first}, err: errors.New("connection lost")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			params := WatchStatsParams{Window: time.Hour}
+			var received []entity.Stats
+			query := NewWatchStatsQuery(tt.repository)
+			err := query.Execute(ctx, params, func(stats entity.Stats) {
+				received = append(received, stats)
+				if len(received) == len(tt.repository.updates) && tt.repository.err == nil {
+					cancel()
+				}
+			})
+
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error when stopped through ctx, got %v", err)
+			}
+			if len(received) != len(tt.repository.updates) {
+				t.Errorf("Received %d updates, want %d", len(received), len(tt.repository.updates))
+			}
+			if tt.repository.params != params {
+				t.Errorf("Repository params = %+v, want %+v", tt.repository.params, params)
+			}
+		})
+	}
+}